@@ -190,7 +190,7 @@ func writeDescsToMetadata(
 			k := encodeDescSSTKey(i.ID)
 			var b []byte
 			if i.Desc != nil {
-				t, _, _, _ := descpb.FromDescriptor(i.Desc)
+				t, _, _, _, _, _ := descpb.FromDescriptor(i.Desc)
 				if t == nil || !t.Dropped() {
 					bytes, err := protoutil.Marshal(i.Desc)
 					if err != nil {
@@ -315,32 +315,37 @@ func writeNamesToMetadata(
 		}
 	}
 
-	names := make(namespace, len(revs))
+	names := make(namespace, 0, len(revs))
 
-	for i, rev := range revs {
-		names[i].id = rev.ID
-		names[i].ts = rev.Time
-		tb, db, typ, sc := descpb.FromDescriptor(rev.Desc)
+	for _, rev := range revs {
+		tb, db, typ, sc, fn := descpb.FromDescriptor(rev.Desc)
+		if fn != nil {
+			// Functions are not addressed by name in the namespace; they are
+			// found through the function mapping of their parent schema.
+			continue
+		}
+		n := name{id: rev.ID, ts: rev.Time}
 		if db != nil {
-			names[i].name = db.Name
+			n.name = db.Name
 		} else if sc != nil {
-			names[i].name = sc.Name
-			names[i].parent = sc.ParentID
+			n.name = sc.Name
+			n.parent = sc.ParentID
 		} else if tb != nil {
-			names[i].name = tb.Name
-			names[i].parent = tb.ParentID
-			names[i].parentSchema = keys.PublicSchemaID
+			n.name = tb.Name
+			n.parent = tb.ParentID
+			n.parentSchema = keys.PublicSchemaID
 			if s := tb.UnexposedParentSchemaID; s != descpb.InvalidID {
-				names[i].parentSchema = s
+				n.parentSchema = s
 			}
 			if tb.Dropped() {
-				names[i].id = 0
+				n.id = 0
 			}
 		} else if typ != nil {
-			names[i].name = typ.Name
-			names[i].parent = typ.ParentID
-			names[i].parentSchema = typ.ParentSchemaID
+			n.name = typ.Name
+			n.parent = typ.ParentID
+			n.parentSchema = typ.ParentSchemaID
 		}
+		names = append(names, n)
 	}
 	sort.Sort(names)
 
@@ -453,6 +458,8 @@ func descID(in descpb.Descriptor) descpb.ID {
 		return i.Type.ID
 	case *descpb.Descriptor_Schema:
 		return i.Schema.ID
+	case *descpb.Descriptor_Function:
+		return i.Function.ID
 	default:
 		panic(fmt.Sprintf("unknown desc %T", in))
 	}
@@ -1021,8 +1028,8 @@ func (di *DescIterator) Next(desc *descpb.Descriptor) bool {
 			return false
 		}
 
-		tbl, db, typ, sc := descpb.FromDescriptor(desc)
-		if tbl != nil || db != nil || typ != nil || sc != nil {
+		tbl, db, typ, sc, fn := descpb.FromDescriptor(desc)
+		if tbl != nil || db != nil || typ != nil || sc != nil || fn != nil {
 			return true
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedvalidators"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -448,6 +449,28 @@ func createChangefeedJobRecord(
 	if _, err := getEncoder(encodingOpts, AllTargets(details)); err != nil {
 		return nil, err
 	}
+	if !unspecifiedSink && encodingOpts.Format == changefeedbase.OptFormatPGOutput {
+		// The pgoutput format is used by the logical replication streams, which
+		// read the rows of sinkless changefeeds.
+		return nil, errors.Errorf(`%s=%s is only usable with sinkless changefeeds`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatPGOutput)
+	}
+	if encodingOpts.Format == changefeedbase.OptFormatParquet && !isCloudStorageSink(parsedSink) {
+		// Parquet files can only be written by the cloud storage sink, which
+		// encodes the rows itself.
+		return nil, errors.Errorf(`%s=%s is only usable with cloud storage sinks`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	if isPulsarSink(parsedSink) &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ChangefeedPulsarSink) {
+		return nil, errors.Newf(`%s sink is not supported until upgrade to version %s or higher is finalized`,
+			changefeedbase.SinkSchemePulsar, clusterversion.ChangefeedPulsarSink.String())
+	}
+	if parsedSink.Query().Get(changefeedbase.SinkParamKafkaTransactional) != `` &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ChangefeedKafkaTransactional) {
+		return nil, errors.Newf(`%s is not supported until upgrade to version %s or higher is finalized`,
+			changefeedbase.SinkParamKafkaTransactional, clusterversion.ChangefeedKafkaTransactional.String())
+	}
 
 	//	 The changefeed is opted in to `OptKeyInValue` for any cloud
 	//   storage sink or webhook sink. Kafka etc have a key and value field in
@@ -721,7 +744,7 @@ func validateSink(
 	}
 	var nilOracle timestampLowerBoundOracle
 	canarySink, err := getSink(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig, details,
-		nilOracle, p.User(), jobID, sli)
+		nilOracle, p.User(), jobID, `` /* sinkID */, sli)
 	if err != nil {
 		return changefeedbase.MaybeStripRetryableErrorMarker(err)
 	}
@@ -752,6 +775,7 @@ func changefeedJobDescription(
 		changefeedbase.SinkParamSASLPassword,
 		changefeedbase.SinkParamCACert,
 		changefeedbase.SinkParamClientCert,
+		changefeedbase.SinkParamAuthToken,
 	})
 
 	if err != nil {
@@ -63,6 +63,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
@@ -91,6 +92,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/dustin/go-humanize"
 	"github.com/lib/pq"
+	"github.com/lib/pq/oid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1901,7 +1903,7 @@ func fetchDescVersionModificationTime(
 			if err := value.GetProto(&desc); err != nil {
 				t.Fatal(err)
 			}
-			if tableDesc, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
+			if tableDesc, _, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
 				if int(tableDesc.Version) == version {
 					return tableDesc.ModificationTime
 				}
@@ -3561,6 +3563,53 @@ func TestChangefeedSchemaTTL(t *testing.T) {
 	cdcTestWithSystem(t, testFn, feedTestNoTenants)
 }
 
+func TestChangefeedPGOutput(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+	rows := sqlDB.Query(t, `EXPERIMENTAL CHANGEFEED FOR foo WITH format=pgoutput, diff`)
+	defer rows.Close()
+	sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'b')`)
+	sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+
+	// The initial scan produces an insert, followed by the update and the
+	// delete. Only the key of the deleted row is sent.
+	fooID := oid.Oid(desctestutils.TestingGetPublicTableDescriptor(
+		s.DB(), keys.SystemSQLCodec, "defaultdb", "foo").GetID())
+	expected := [][]byte{
+		pgrepl.EncodeInsert(fooID, tree.Datums{tree.NewDInt(1), tree.NewDString("a")}),
+		pgrepl.EncodeUpdate(fooID, tree.Datums{tree.NewDInt(1), tree.NewDString("b")}),
+		pgrepl.EncodeDelete(fooID, tree.Datums{tree.NewDInt(1), tree.DNull}),
+	}
+	for _, msg := range expected {
+		require.True(t, rows.Next())
+		var table gosql.NullString
+		var key, value []byte
+		require.NoError(t, rows.Scan(&table, &key, &value))
+		require.Equal(t, "foo", table.String)
+
+		change, err := pgrepl.DecodeChange(value)
+		require.NoError(t, err)
+		require.Equal(t, pgrepl.Relation{
+			ID:   fooID,
+			Name: "foo",
+			Columns: []pgrepl.RelationColumn{
+				{Name: "a", Key: true, TypeOID: oid.T_int8, TypeMod: -1},
+				{Name: "b", TypeOID: oid.T_text, TypeMod: -1},
+			},
+		}, change.Relation)
+		require.Equal(t, msg, change.Message)
+	}
+}
+
 func TestChangefeedErrors(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -3627,6 +3676,27 @@ func TestChangefeedErrors(t *testing.T) {
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH cursor=$1`, timeutil.Now().Add(time.Hour),
 	)
 
+	sqlDB.ExpectErr(
+		t, `format=pgoutput requires the diff option`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH format=pgoutput`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=pgoutput is only usable with sinkless changefeeds`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=pgoutput, diff`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=parquet is only usable with cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=parquet`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=parquet is only usable with cloud storage sinks`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH format=parquet`,
+	)
+	sqlDB.ExpectErr(
+		t, `topic_in_value is not supported with format=parquet`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=parquet, topic_in_value`, `nodelocal://0/parquet`,
+	)
+
 	sqlDB.ExpectErr(
 		t, `omit the SINK clause`,
 		`CREATE CHANGEFEED FOR foo INTO ''`,
@@ -3737,6 +3807,15 @@ func TestChangefeedErrors(t *testing.T) {
 		t, unknownParams(`kafka`, `kafka_topic_prefix`),
 		`CREATE CHANGEFEED FOR foo INTO $1`, `kafka://nope/?kafka_topic_prefix=foo`,
 	)
+	sqlDB.ExpectErr(
+		t, unknownParams(`pulsar`, `kafka_topic_prefix`),
+		`CREATE CHANGEFEED FOR foo INTO $1`, `pulsar://nope/?kafka_topic_prefix=foo`,
+	)
+	// kafka_sink_config is only honored for kafka sinks.
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option kafka_sink_config`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH kafka_sink_config='{}'`, `pulsar://nope`,
+	)
 
 	// topic_name is only honored for kafka sinks
 	sqlDB.ExpectErr(
@@ -3849,6 +3928,16 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH topic_in_value, format='experimental_avro'`,
 		`kafka://nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `WITH option confluent_schema_registry is required for format=protobuf`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=protobuf`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `envelope=row is not supported with format=protobuf`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH envelope=row, format=protobuf, confluent_schema_registry='http://nope'`,
+		`kafka://nope`,
+	)
 
 	// The topics option should not be exposed to users since it is used
 	// internally to display topics in the show changefeed jobs query
@@ -141,9 +141,12 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `avro`
-	OptFormatCSV  FormatType = `csv`
+	OptFormatJSON     FormatType = `json`
+	OptFormatAvro     FormatType = `avro`
+	OptFormatCSV      FormatType = `csv`
+	OptFormatPGOutput FormatType = `pgoutput`
+	OptFormatParquet  FormatType = `parquet`
+	OptFormatProtobuf FormatType = `protobuf`
 
 	OptOnErrorFail  OnErrorType = `fail`
 	OptOnErrorPause OnErrorType = `pause`
@@ -159,6 +162,9 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	// OptPulsarSinkConfig is a JSON configuration for pulsar sink
+	// (pulsarSinkConfig).
+	OptPulsarSinkConfig = `pulsar_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
 	// Note that this option is only allowed for alter changefeed statements.
@@ -185,6 +191,7 @@ const (
 	SinkSchemeHTTPS                 = `https`
 	SinkSchemeKafka                 = `kafka`
 	SinkSchemeNull                  = `null`
+	SinkSchemePulsar                = `pulsar`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkParamSASLEnabled            = `sasl_enabled`
@@ -192,6 +199,8 @@ const (
 	SinkParamSASLUser               = `sasl_user`
 	SinkParamSASLPassword           = `sasl_password`
 	SinkParamSASLMechanism          = `sasl_mechanism`
+	SinkParamAuthToken              = `auth_token`
+	SinkParamKafkaTransactional     = `kafka_transactional`
 
 	RegistryParamCACert = `ca_cert`
 
@@ -288,7 +297,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptCursor:                   timestampOption,
 	OptEndTime:                  timestampOption,
 	OptEnvelope:                 enum("row", "key_only", "wrapped", "deprecated_row"),
-	OptFormat:                   enum("json", "avro", "csv", "experimental_avro"),
+	OptFormat:                   enum("json", "avro", "csv", "experimental_avro", "pgoutput", "parquet", "protobuf"),
 	OptFullTableName:            flagOption,
 	OptKeyInValue:               flagOption,
 	OptTopicInValue:             flagOption,
@@ -307,6 +316,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptProtectDataFromGCOnPause: flagOption,
 	OptKafkaSinkConfig:          jsonOption,
 	OptWebhookSinkConfig:        jsonOption,
+	OptPulsarSinkConfig:         jsonOption,
 	OptWebhookAuthHeader:        stringOption,
 	OptWebhookClientTimeout:     durationOption,
 	OptOnError:                  enum("pause", "fail"),
@@ -340,6 +350,9 @@ var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTi
 // PubsubValidOptions is options exclusive to pubsub sink
 var PubsubValidOptions = makeStringSet()
 
+// PulsarValidOptions is options exclusive to pulsar sink
+var PulsarValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptPulsarSinkConfig)
+
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents,
 	OptSchemaChangePolicy, OptOnError, OptInitialScan)
@@ -393,9 +406,10 @@ var AlterChangefeedTargetOptions = map[string]OptionPermittedValues{
 // VersionGateOptions is a mapping between an option and its minimum supported
 // version.
 var VersionGateOptions = map[string]clusterversion.Key{
-	OptEndTime:         clusterversion.EnableNewChangefeedOptions,
-	OptInitialScanOnly: clusterversion.EnableNewChangefeedOptions,
-	OptInitialScan:     clusterversion.EnableNewChangefeedOptions,
+	OptEndTime:          clusterversion.EnableNewChangefeedOptions,
+	OptInitialScanOnly:  clusterversion.EnableNewChangefeedOptions,
+	OptInitialScan:      clusterversion.EnableNewChangefeedOptions,
+	OptPulsarSinkConfig: clusterversion.ChangefeedPulsarSink,
 }
 
 // MakeStatementOptions wraps and canonicalizes the options we get
@@ -446,6 +460,18 @@ func (s StatementOptions) CheckVersionGates(
 			}
 		}
 	}
+	if s.m[OptFormat] == string(OptFormatParquet) && !version.IsActive(ctx, clusterversion.ChangefeedParquet) {
+		return errors.Newf(
+			`%s=%s is not supported until upgrade to version %s or higher is finalized`,
+			OptFormat, OptFormatParquet, clusterversion.ChangefeedParquet.String(),
+		)
+	}
+	if s.m[OptFormat] == string(OptFormatProtobuf) && !version.IsActive(ctx, clusterversion.ChangefeedProtobuf) {
+		return errors.Newf(
+			`%s=%s is not supported until upgrade to version %s or higher is finalized`,
+			OptFormat, OptFormatProtobuf, clusterversion.ChangefeedProtobuf.String(),
+		)
+	}
 	return nil
 }
 
@@ -789,6 +815,12 @@ func (s StatementOptions) GetKafkaConfigJSON() SinkSpecificJSONConfig {
 	return s.getJSONValue(OptKafkaSinkConfig)
 }
 
+// GetPulsarConfigJSON returns arbitrary json to be interpreted
+// by the pulsar sink.
+func (s StatementOptions) GetPulsarConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptPulsarSinkConfig)
+}
+
 // GetResolvedTimestampInterval gets the best-effort interval at which resolved timestamps
 // should be emitted. Nil or 0 means emit as often as possible. False means do not emit at all.
 // Returns an error for negative or invalid duration value.
@@ -51,6 +51,12 @@ func getEncoder(
 		return newConfluentAvroEncoder(opts, targets)
 	case changefeedbase.OptFormatCSV:
 		return newCSVEncoder(opts), nil
+	case changefeedbase.OptFormatPGOutput:
+		return newPGOutputEncoder(opts)
+	case changefeedbase.OptFormatParquet:
+		return newParquetEncoder(opts, targets)
+	case changefeedbase.OptFormatProtobuf:
+		return newConfluentProtobufEncoder(opts, targets)
 	default:
 		return nil, errors.AssertionFailedf(`unknown format: %s`, opts.Format)
 	}
@@ -0,0 +1,222 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/importer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// Names of the columns that are added to every parquet file next to the
+// columns of the table.
+const (
+	parquetEventTypeColName     = `__crdb__event_type__`
+	parquetUpdatedColName       = `__crdb__updated__`
+	parquetMVCCTimestampColName = `__crdb__mvcc_timestamp__`
+)
+
+// Values of the event type column. An insert can only be told apart from an
+// update when the changefeed was created with the diff option; without it,
+// every row that isn't a deletion is reported as an insert.
+const (
+	parquetEventInsert = `c`
+	parquetEventUpdate = `u`
+	parquetEventDelete = `d`
+)
+
+// parquetEncoder is the Encoder for format=parquet. The rows of a parquet file
+// share a schema and are written into row groups, so they cannot be encoded
+// one message at a time; instead, the cloud storage sink encodes the rows
+// itself (see SinkWithEncoder). Resolved timestamps are still written to
+// their own files, which are encoded as JSON just like with format=json.
+type parquetEncoder struct {
+	resolvedEncoder *jsonEncoder
+}
+
+var _ Encoder = &parquetEncoder{}
+
+func newParquetEncoder(
+	opts changefeedbase.EncodingOptions, targets changefeedbase.Targets,
+) (*parquetEncoder, error) {
+	if opts.Envelope != changefeedbase.OptEnvelopeWrapped {
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts.Envelope,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	if opts.TopicInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	resolvedEncoder, err := makeJSONEncoder(opts, targets)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetEncoder{resolvedEncoder: resolvedEncoder}, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *parquetEncoder) EncodeKey(context.Context, cdcevent.Row) ([]byte, error) {
+	return nil, errors.AssertionFailedf(`%s=%s rows are encoded by the sink`,
+		changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *parquetEncoder) EncodeValue(
+	context.Context, eventContext, cdcevent.Row, cdcevent.Row,
+) ([]byte, error) {
+	return nil, errors.AssertionFailedf(`%s=%s rows are encoded by the sink`,
+		changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *parquetEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return e.resolvedEncoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+}
+
+// parquetRowWriter writes the rows of a single table version into a parquet
+// file. The type of every column is mapped to parquet in the same way as EXPORT
+// PARQUET does. All the rows are buffered into a single row group, which is
+// written out along with the file footer by close, so the row groups line up
+// with the files that the cloud storage sink rotates on size and on resolved
+// timestamps.
+type parquetRowWriter struct {
+	writer *goparquet.FileWriter
+	// cols holds the columns of the table, followed by the event type column
+	// and the optional timestamp columns.
+	cols   []importer.ParquetColumn
+	record map[string]interface{}
+	// keyCols holds the names of the primary key columns, which are the only
+	// columns that are set in deleted rows.
+	keyCols map[string]struct{}
+
+	updatedTimestamps bool
+	mvccTimestamps    bool
+}
+
+// newParquetRowWriter creates a parquetRowWriter whose schema is derived from
+// the columns of the given row.
+func newParquetRowWriter(
+	w io.Writer,
+	row cdcevent.Row,
+	opts changefeedbase.EncodingOptions,
+	compression parquet.CompressionCodec,
+) (*parquetRowWriter, error) {
+	pw := &parquetRowWriter{
+		keyCols:           make(map[string]struct{}),
+		updatedTimestamps: opts.UpdatedTimestamps,
+		mvccTimestamps:    opts.MVCCTimestamps,
+	}
+	if err := row.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		pw.keyCols[col.Name] = struct{}{}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	// Table columns are always nullable: the non-key columns of deleted rows
+	// are NULL.
+	if err := row.ForEachColumn().Col(func(col cdcevent.ResultColumn) error {
+		return pw.addColumn(col.Typ, col.Name, true /* nullable */)
+	}); err != nil {
+		return nil, err
+	}
+	if err := pw.addColumn(types.String, parquetEventTypeColName, false /* nullable */); err != nil {
+		return nil, err
+	}
+	if pw.updatedTimestamps {
+		if err := pw.addColumn(types.Decimal, parquetUpdatedColName, false /* nullable */); err != nil {
+			return nil, err
+		}
+	}
+	if pw.mvccTimestamps {
+		if err := pw.addColumn(types.Decimal, parquetMVCCTimestampColName, false /* nullable */); err != nil {
+			return nil, err
+		}
+	}
+
+	pw.record = make(map[string]interface{}, len(pw.cols))
+	pw.writer = goparquet.NewFileWriter(w,
+		goparquet.WithCompressionCodec(compression),
+		goparquet.WithSchemaDefinition(importer.NewParquetSchema(pw.cols)),
+	)
+	return pw, nil
+}
+
+func (pw *parquetRowWriter) addColumn(typ *types.T, name string, nullable bool) error {
+	col, err := importer.NewParquetColumn(typ, name, nullable)
+	if err != nil {
+		return errors.Wrapf(err, "column %s", name)
+	}
+	pw.cols = append(pw.cols, col)
+	return nil
+}
+
+// addRow buffers the row in the current row group.
+func (pw *parquetRowWriter) addRow(
+	updatedRow cdcevent.Row, prevRow cdcevent.Row, updated, mvcc hlc.Timestamp,
+) error {
+	i := 0
+	if err := updatedRow.ForEachColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if i >= len(pw.cols) || pw.cols[i].Name() != col.Name {
+			return errors.AssertionFailedf("column %s does not match the parquet schema", col.Name)
+		}
+		parquetCol := &pw.cols[i]
+		i++
+		if _, isKey := pw.keyCols[col.Name]; updatedRow.IsDeleted() && !isKey {
+			pw.record[col.Name] = nil
+			return nil
+		}
+		v, err := parquetCol.EncodeDatum(d)
+		if err != nil {
+			return err
+		}
+		pw.record[col.Name] = v
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	eventType := parquetEventInsert
+	if updatedRow.IsDeleted() {
+		eventType = parquetEventDelete
+	} else if prevRow.IsInitialized() && !prevRow.IsDeleted() {
+		eventType = parquetEventUpdate
+	}
+	pw.record[parquetEventTypeColName] = []byte(eventType)
+	if pw.updatedTimestamps {
+		pw.record[parquetUpdatedColName] = []byte(eval.TimestampToDecimalDatum(updated).Decimal.String())
+	}
+	if pw.mvccTimestamps {
+		pw.record[parquetMVCCTimestampColName] = []byte(eval.TimestampToDecimalDatum(mvcc).Decimal.String())
+	}
+	return pw.writer.AddData(pw.record)
+}
+
+// estimatedSize returns an estimate of the size of the file if it were closed
+// now.
+func (pw *parquetRowWriter) estimatedSize() int64 {
+	return pw.writer.CurrentFileSize() + pw.writer.CurrentRowGroupSize()
+}
+
+// close writes out the buffered row group and the file footer.
+func (pw *parquetRowWriter) close() error {
+	return pw.writer.Close()
+}
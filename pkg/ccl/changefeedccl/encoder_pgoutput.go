@@ -0,0 +1,112 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// pgoutputEncoder encodes the changes for the logical replication streams
+// served over pgwire. Each value is a pgrepl.Change, which carries the
+// description of the table along with the Insert, Update or Delete message of
+// the change; the replication stream buffers the changes until they are
+// resolved, and then sends them in LSN order.
+type pgoutputEncoder struct {
+	keyCols map[string]struct{}
+	datums  tree.Datums
+}
+
+var _ Encoder = &pgoutputEncoder{}
+
+func newPGOutputEncoder(opts changefeedbase.EncodingOptions) (*pgoutputEncoder, error) {
+	// The previous version of the row is needed to tell inserts and updates
+	// apart.
+	if !opts.Diff {
+		return nil, errors.Errorf(`%s=%s requires the %s option`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatPGOutput, changefeedbase.OptDiff)
+	}
+	if opts.Envelope != changefeedbase.OptEnvelopeWrapped {
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts.Envelope,
+			changefeedbase.OptFormat, changefeedbase.OptFormatPGOutput)
+	}
+	return &pgoutputEncoder{keyCols: make(map[string]struct{})}, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *pgoutputEncoder) EncodeKey(_ context.Context, row cdcevent.Row) ([]byte, error) {
+	return nil, nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *pgoutputEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	for k := range e.keyCols {
+		delete(e.keyCols, k)
+	}
+	if err := updatedRow.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		e.keyCols[col.Name] = struct{}{}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	change := pgrepl.Change{
+		Timestamp: evCtx.updated,
+		Relation: pgrepl.Relation{
+			ID:   oid.Oid(updatedRow.TableID),
+			Name: updatedRow.TableName,
+		},
+	}
+	e.datums = e.datums[:0]
+	if err := updatedRow.ForEachColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		_, isKey := e.keyCols[col.Name]
+		change.Relation.Columns = append(change.Relation.Columns, pgrepl.RelationColumn{
+			Name:    col.Name,
+			Key:     isKey,
+			TypeOID: col.Typ.Oid(),
+			TypeMod: col.Typ.TypeModifier(),
+		})
+		// Only the key columns of deleted rows are sent.
+		if updatedRow.IsDeleted() && !isKey {
+			d = tree.DNull
+		}
+		e.datums = append(e.datums, d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	relID := change.Relation.ID
+	switch {
+	case updatedRow.IsDeleted():
+		change.Message = pgrepl.EncodeDelete(relID, e.datums)
+	case prevRow.HasValues() && !prevRow.IsDeleted():
+		change.Message = pgrepl.EncodeUpdate(relID, e.datums)
+	default:
+		change.Message = pgrepl.EncodeInsert(relID, e.datums)
+	}
+	return change.Encode(), nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *pgoutputEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return pgrepl.EncodeResolved(resolved), nil
+}
@@ -0,0 +1,581 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the envelope message. They are the same for every
+// envelope, regardless of which of the fields it has.
+const (
+	protobufAfterFieldNumber    protowire.Number = 1
+	protobufBeforeFieldNumber   protowire.Number = 2
+	protobufUpdatedFieldNumber  protowire.Number = 3
+	protobufResolvedFieldNumber protowire.Number = 4
+)
+
+// confluentProtobufEncoder encodes changefeed entries as protobuf messages in
+// the Confluent wire format. The message descriptors are generated from the
+// columns of the rows, and registered with the schema registry as proto3
+// schemas. Keys are the primary key columns in a record. Values are all columns
+// in a record, wrapped in an envelope message.
+//
+// A new version of the descriptors is registered whenever the table descriptor
+// version of the rows changes, which happens on every schema change.
+type confluentProtobufEncoder struct {
+	schemaRegistry                     schemaRegistry
+	schemaPrefix                       string
+	updatedField, beforeField, keyOnly bool
+	targets                            changefeedbase.Targets
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]protobufRegisteredKeySchema
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]protobufRegisteredEnvelopeSchema
+
+	// resolvedCache doesn't need to be bounded like the other caches because the number of topics
+	// is fixed per changefeed.
+	resolvedCache map[string]protobufRegisteredEnvelopeSchema
+}
+
+type protobufRegisteredKeySchema struct {
+	schema     *protobufMessage
+	registryID int32
+}
+
+type protobufRegisteredEnvelopeSchema struct {
+	schema     *protobufEnvelope
+	registryID int32
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(
+	opts changefeedbase.EncodingOptions, targets changefeedbase.Targets,
+) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{
+		schemaPrefix: opts.AvroSchemaPrefix,
+		targets:      targets,
+	}
+
+	switch opts.Envelope {
+	case changefeedbase.OptEnvelopeKeyOnly:
+		e.keyOnly = true
+	case changefeedbase.OptEnvelopeWrapped:
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts.Envelope, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	e.updatedField = opts.UpdatedTimestamps
+	if e.updatedField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptUpdatedTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+	e.beforeField = opts.Diff
+	if e.beforeField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+
+	if opts.KeyInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if opts.TopicInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if len(opts.SchemaRegistryURI) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	reg, err := newConfluentSchemaRegistry(opts.SchemaRegistryURI)
+	if err != nil {
+		return nil, err
+	}
+
+	e.schemaRegistry = reg
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]protobufRegisteredEnvelopeSchema)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(
+	ctx context.Context, row cdcevent.Row,
+) ([]byte, error) {
+	// No familyID in the cache key for keys because it's the same schema for all families
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+
+	var registered protobufRegisteredKeySchema
+	if v, ok := e.keyCache.Get(cacheKey); ok {
+		registered = v.(protobufRegisteredKeySchema)
+	} else {
+		tableName, err := confluentRawTableName(e.targets, e.schemaPrefix, row.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		registered.schema, err = newProtobufMessage(SQLNameToAvroName(tableName)+`_key`, row.ForEachKeyColumn())
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		registered.registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject, confluentSchemaTypeProtobuf, registered.schema.fileDefinition())
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registered)
+	}
+
+	return registered.schema.appendRow(protobufWireHeader(registered.registryID), row.ForEachKeyColumn())
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.keyOnly {
+		return nil, nil
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField && prevRow.IsInitialized() {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: prevRow.TableID, version: prevRow.Version, familyID: prevRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+
+	var registered protobufRegisteredEnvelopeSchema
+	if v, ok := e.valueCache.Get(cacheKey); ok {
+		registered = v.(protobufRegisteredEnvelopeSchema)
+	} else {
+		name, err := confluentRawTableName(e.targets, e.schemaPrefix, updatedRow.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		env := &protobufEnvelope{
+			name:         SQLNameToAvroName(name) + `_envelope`,
+			beforeField:  e.beforeField,
+			updatedField: e.updatedField,
+		}
+		env.after, err = newProtobufMessage(protobufMessageName(updatedRow, ``), updatedRow.ForEachColumn())
+		if err != nil {
+			return nil, err
+		}
+		// Without a previous row, the before field is always unset, so it just
+		// reuses the message of the after field.
+		env.before = env.after
+		if e.beforeField && prevRow.IsInitialized() {
+			env.before, err = newProtobufMessage(protobufMessageName(prevRow, `before`), prevRow.ForEachColumn())
+			if err != nil {
+				return nil, err
+			}
+		}
+		registered.schema = env
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(name) + confluentSubjectSuffixValue
+		registered.registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject, confluentSchemaTypeProtobuf, env.fileDefinition())
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+
+	return registered.schema.appendRow(
+		protobufWireHeader(registered.registryID), evCtx.updated, prevRow, updatedRow)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registered, ok := e.resolvedCache[topic]
+	if !ok {
+		registered.schema = &protobufEnvelope{
+			name:          SQLNameToAvroName(topic) + `_envelope`,
+			resolvedField: true,
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		var err error
+		registered.registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject, confluentSchemaTypeProtobuf, registered.schema.fileDefinition())
+		if err != nil {
+			return nil, err
+		}
+		e.resolvedCache[topic] = registered
+	}
+	return registered.schema.appendResolved(protobufWireHeader(registered.registryID), resolved), nil
+}
+
+// protobufWireHeader returns the header of a message encoded with the schema
+// with the given ID. Next to the magic byte and the schema ID, the header of
+// protobuf messages holds the indexes of the message type in the schema, as a
+// varint length followed by that many varints. The message types are always
+// the first ones of their schemas, whose indexes are encoded as a single 0.
+//
+// https://docs.confluent.io/platform/current/schema-registry/serdes-develop/index.html#wire-format
+func protobufWireHeader(registryID int32) []byte {
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+		0, // Message indexes.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
+
+// protobufMessageName returns the name of the message generated for the columns
+// of the given row. Like the avro records, the messages of tables with only one
+// family don't get family-specific names.
+func protobufMessageName(row cdcevent.Row, nameSuffix string) string {
+	sqlName := row.TableName
+	if row.HasOtherFamilies {
+		sqlName += "." + row.FamilyName
+	}
+	name := SQLNameToAvroName(sqlName)
+	if nameSuffix != `` {
+		name += `_` + nameSuffix
+	}
+	return name
+}
+
+// protobufType is the protobuf type of a field that holds a column.
+type protobufType int
+
+const (
+	protobufBool protobufType = iota
+	protobufInt32
+	protobufInt64
+	protobufFloat
+	protobufDouble
+	protobufBytes
+	protobufString
+	protobufTimestamp
+)
+
+var protobufTypeNames = [...]string{
+	protobufBool:      `bool`,
+	protobufInt32:     `int32`,
+	protobufInt64:     `int64`,
+	protobufFloat:     `float`,
+	protobufDouble:    `double`,
+	protobufBytes:     `bytes`,
+	protobufString:    `string`,
+	protobufTimestamp: `google.protobuf.Timestamp`,
+}
+
+// protobufTypeForColumn returns the protobuf type of the field of a column of
+// the given type. Types that have no protobuf counterpart are held as strings
+// in their text format.
+func protobufTypeForColumn(typ *types.T) protobufType {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return protobufBool
+	case types.IntFamily:
+		if typ.Width() == 64 {
+			return protobufInt64
+		}
+		return protobufInt32
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			return protobufFloat
+		}
+		return protobufDouble
+	case types.BytesFamily:
+		return protobufBytes
+	case types.TimestampFamily, types.TimestampTZFamily:
+		return protobufTimestamp
+	default:
+		return protobufString
+	}
+}
+
+type protobufField struct {
+	name   string
+	number protowire.Number
+	typ    protobufType
+}
+
+// protobufMessage is a generated protobuf message that holds the columns of a
+// row, in the order they are iterated.
+type protobufMessage struct {
+	name   string
+	fields []protobufField
+}
+
+// newProtobufMessage generates the message for the columns of the given
+// iterator. The fields are numbered after the IDs of their columns, which
+// don't change when other columns are added or dropped, so the messages of
+// consecutive versions of a table stay wire compatible. Columns that don't
+// come from the table, like those of projections, have no ID, in which case
+// the fields are numbered after their positions instead.
+func newProtobufMessage(name string, it cdcevent.Iterator) (*protobufMessage, error) {
+	m := &protobufMessage{name: name}
+	useColumnIDs := true
+	seen := make(map[protowire.Number]struct{})
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		number := protowire.Number(col.PGAttributeNum)
+		if _, ok := seen[number]; ok || !number.IsValid() {
+			useColumnIDs = false
+		}
+		seen[number] = struct{}{}
+		m.fields = append(m.fields, protobufField{
+			name:   SQLNameToAvroName(col.Name),
+			number: number,
+			typ:    protobufTypeForColumn(col.Typ),
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if !useColumnIDs {
+		for i := range m.fields {
+			m.fields[i].number = protowire.Number(i + 1)
+		}
+	}
+	return m, nil
+}
+
+// usesTimestamps returns whether the message has google.protobuf.Timestamp
+// fields, whose definition has to be imported.
+func (m *protobufMessage) usesTimestamps() bool {
+	for _, f := range m.fields {
+		if f.typ == protobufTimestamp {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDefinition writes the definition of the message in the proto3 language.
+// Scalar fields are declared optional, so that NULLs can be told apart from
+// zero values.
+func (m *protobufMessage) writeDefinition(buf *strings.Builder) {
+	fmt.Fprintf(buf, "\nmessage %s {\n", m.name)
+	for _, f := range m.fields {
+		if f.typ == protobufTimestamp {
+			fmt.Fprintf(buf, "  %s %s = %d;\n", protobufTypeNames[f.typ], f.name, f.number)
+		} else {
+			fmt.Fprintf(buf, "  optional %s %s = %d;\n", protobufTypeNames[f.typ], f.name, f.number)
+		}
+	}
+	buf.WriteString("}\n")
+}
+
+// fileDefinition returns the definition of a proto3 file holding only this
+// message.
+func (m *protobufMessage) fileDefinition() string {
+	var buf strings.Builder
+	writeProtobufFileHeader(&buf, m.usesTimestamps())
+	m.writeDefinition(&buf)
+	return buf.String()
+}
+
+func writeProtobufFileHeader(buf *strings.Builder, importTimestamp bool) {
+	buf.WriteString("syntax = \"proto3\";\n")
+	if importTimestamp {
+		buf.WriteString("\nimport \"google/protobuf/timestamp.proto\";\n")
+	}
+}
+
+// appendRow appends the encoding of the row data to buf. NULL columns are left
+// unset.
+func (m *protobufMessage) appendRow(buf []byte, it cdcevent.Iterator) ([]byte, error) {
+	i := 0
+	if err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if i >= len(m.fields) {
+			return errors.AssertionFailedf(`unexpected column %s`, col.Name)
+		}
+		f := m.fields[i]
+		i++
+		if d == tree.DNull {
+			return nil
+		}
+		var err error
+		buf, err = appendProtobufDatum(buf, f, d)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func appendProtobufDatum(buf []byte, f protobufField, d tree.Datum) ([]byte, error) {
+	switch f.typ {
+	case protobufBool:
+		if b, ok := d.(*tree.DBool); ok {
+			buf = protowire.AppendTag(buf, f.number, protowire.VarintType)
+			return protowire.AppendVarint(buf, protowire.EncodeBool(bool(*b))), nil
+		}
+	case protobufInt32, protobufInt64:
+		if i, ok := d.(*tree.DInt); ok {
+			buf = protowire.AppendTag(buf, f.number, protowire.VarintType)
+			return protowire.AppendVarint(buf, uint64(*i)), nil
+		}
+	case protobufFloat:
+		if fl, ok := d.(*tree.DFloat); ok {
+			buf = protowire.AppendTag(buf, f.number, protowire.Fixed32Type)
+			return protowire.AppendFixed32(buf, math.Float32bits(float32(*fl))), nil
+		}
+	case protobufDouble:
+		if fl, ok := d.(*tree.DFloat); ok {
+			buf = protowire.AppendTag(buf, f.number, protowire.Fixed64Type)
+			return protowire.AppendFixed64(buf, math.Float64bits(float64(*fl))), nil
+		}
+	case protobufBytes:
+		if b, ok := d.(*tree.DBytes); ok {
+			buf = protowire.AppendTag(buf, f.number, protowire.BytesType)
+			return protowire.AppendString(buf, string(*b)), nil
+		}
+	case protobufString:
+		buf = protowire.AppendTag(buf, f.number, protowire.BytesType)
+		return protowire.AppendString(buf, tree.AsStringWithFlags(d, tree.FmtExport)), nil
+	case protobufTimestamp:
+		var ts []byte
+		switch t := d.(type) {
+		case *tree.DTimestamp:
+			ts = appendProtobufTimestamp(ts, t.Unix(), t.Nanosecond())
+		case *tree.DTimestampTZ:
+			ts = appendProtobufTimestamp(ts, t.Unix(), t.Nanosecond())
+		default:
+			return nil, errors.AssertionFailedf(`unexpected datum %T for field %s`, d, f.name)
+		}
+		buf = protowire.AppendTag(buf, f.number, protowire.BytesType)
+		return protowire.AppendBytes(buf, ts), nil
+	}
+	return nil, errors.AssertionFailedf(`unexpected datum %T for field %s`, d, f.name)
+}
+
+// appendProtobufTimestamp appends the encoding of a google.protobuf.Timestamp.
+func appendProtobufTimestamp(buf []byte, seconds int64, nanos int) []byte {
+	if seconds != 0 {
+		buf = protowire.AppendTag(buf, 1, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(seconds))
+	}
+	if nanos != 0 {
+		buf = protowire.AppendTag(buf, 2, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(nanos))
+	}
+	return buf
+}
+
+// protobufEnvelope is the generated message that wraps the messages of the
+// before and after versions of a row change, along with metadata about that
+// change. Resolved timestamps are sent in envelopes too.
+type protobufEnvelope struct {
+	name                                     string
+	after, before                            *protobufMessage
+	beforeField, updatedField, resolvedField bool
+}
+
+// fileDefinition returns the definition of a proto3 file holding the envelope
+// message, followed by the messages of its fields.
+func (e *protobufEnvelope) fileDefinition() string {
+	var buf strings.Builder
+	var importTimestamp bool
+	if e.after != nil {
+		importTimestamp = e.after.usesTimestamps() || (e.beforeField && e.before.usesTimestamps())
+	}
+	writeProtobufFileHeader(&buf, importTimestamp)
+
+	fmt.Fprintf(&buf, "\nmessage %s {\n", e.name)
+	if e.after != nil {
+		fmt.Fprintf(&buf, "  %s after = %d;\n", e.after.name, protobufAfterFieldNumber)
+	}
+	if e.beforeField {
+		fmt.Fprintf(&buf, "  %s before = %d;\n", e.before.name, protobufBeforeFieldNumber)
+	}
+	if e.updatedField {
+		fmt.Fprintf(&buf, "  optional string updated = %d;\n", protobufUpdatedFieldNumber)
+	}
+	if e.resolvedField {
+		fmt.Fprintf(&buf, "  optional string resolved = %d;\n", protobufResolvedFieldNumber)
+	}
+	buf.WriteString("}\n")
+
+	if e.after != nil {
+		e.after.writeDefinition(&buf)
+	}
+	if e.beforeField && e.before != e.after {
+		e.before.writeDefinition(&buf)
+	}
+	return buf.String()
+}
+
+// appendRow appends the encoding of the envelope of the given row change to
+// buf.
+func (e *protobufEnvelope) appendRow(
+	buf []byte, updated hlc.Timestamp, beforeRow, afterRow cdcevent.Row,
+) ([]byte, error) {
+	var err error
+	if afterRow.HasValues() && !afterRow.IsDeleted() {
+		if buf, err = appendProtobufMessageField(
+			buf, protobufAfterFieldNumber, e.after, afterRow.ForEachColumn(),
+		); err != nil {
+			return nil, err
+		}
+	}
+	if e.beforeField && beforeRow.HasValues() && !beforeRow.IsDeleted() {
+		if buf, err = appendProtobufMessageField(
+			buf, protobufBeforeFieldNumber, e.before, beforeRow.ForEachColumn(),
+		); err != nil {
+			return nil, err
+		}
+	}
+	if e.updatedField {
+		buf = protowire.AppendTag(buf, protobufUpdatedFieldNumber, protowire.BytesType)
+		buf = protowire.AppendString(buf, updated.AsOfSystemTime())
+	}
+	return buf, nil
+}
+
+// appendResolved appends the encoding of the envelope of the given resolved
+// timestamp to buf.
+func (e *protobufEnvelope) appendResolved(buf []byte, resolved hlc.Timestamp) []byte {
+	buf = protowire.AppendTag(buf, protobufResolvedFieldNumber, protowire.BytesType)
+	return protowire.AppendString(buf, resolved.AsOfSystemTime())
+}
+
+func appendProtobufMessageField(
+	buf []byte, number protowire.Number, m *protobufMessage, it cdcevent.Iterator,
+) ([]byte, error) {
+	msg, err := m.appendRow(nil, it)
+	if err != nil {
+		return nil, err
+	}
+	buf = protowire.AppendTag(buf, number, protowire.BytesType)
+	return protowire.AppendBytes(buf, msg), nil
+}
@@ -12,14 +12,17 @@ import (
 	"context"
 	gosql "database/sql"
 	"fmt"
+	"math"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -30,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/workload/ledger"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 func TestEncoders(t *testing.T) {
@@ -841,3 +845,195 @@ func TestAvroLedger(t *testing.T) {
 
 	cdcTest(t, testFn, feedTestForceSink("kafka"))
 }
+
+// decodeProtobufForTest decodes the fields of a protobuf message into a map
+// from their numbers to their values, which are either uint64s or strings.
+func decodeProtobufForTest(t *testing.T, b []byte) map[protowire.Number]interface{} {
+	fields := make(map[protowire.Number]interface{})
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(b)
+			fields[num] = v
+		case protowire.Fixed64Type:
+			var v uint64
+			v, n = protowire.ConsumeFixed64(b)
+			fields[num] = v
+		case protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(b)
+			fields[num] = string(v)
+		default:
+			t.Fatalf(`unexpected wire type %d`, typ)
+		}
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+	}
+	return fields
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	reg := cdctest.StartTestSchemaRegistry()
+	defer reg.Close()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c BOOL, d FLOAT)`)
+	require.NoError(t, err)
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+	})
+	opts := changefeedbase.EncodingOptions{
+		Format:            changefeedbase.OptFormatProtobuf,
+		Envelope:          changefeedbase.OptEnvelopeWrapped,
+		UpdatedTimestamps: true,
+		Diff:              true,
+		SchemaRegistryURI: reg.URL(),
+	}
+	e, err := getEncoder(opts, targets)
+	require.NoError(t, err)
+
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	evCtx := eventContext{updated: ts}
+
+	// decode checks the header of an encoded message and returns its fields.
+	decode := func(b []byte, subject string) map[protowire.Number]interface{} {
+		require.Greater(t, len(b), 6)
+		require.Equal(t, changefeedbase.ConfluentAvroWireFormatMagic, b[0])
+		require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(subject))
+		// The message is the first one of the schema.
+		require.Equal(t, byte(0), b[5])
+		return decodeProtobufForTest(t, b[6:])
+	}
+
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+		rowenc.EncDatum{Datum: tree.DBoolTrue},
+		rowenc.EncDatum{Datum: tree.DNull},
+	}
+	rowInsert := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+	prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false)
+
+	key, err := e.EncodeKey(ctx, rowInsert)
+	require.NoError(t, err)
+	require.Equal(t, map[protowire.Number]interface{}{1: uint64(1)}, decode(key, `foo-key`))
+	require.Equal(t, `syntax = "proto3";
+
+message foo_key {
+  optional int64 a = 1;
+}
+`, reg.SchemaForSubject(`foo-key`))
+
+	value, err := e.EncodeValue(ctx, evCtx, rowInsert, prevRow)
+	require.NoError(t, err)
+	fields := decode(value, `foo-value`)
+	require.Equal(t, `1.0000000002`, fields[protobufUpdatedFieldNumber])
+	require.NotContains(t, fields, protobufBeforeFieldNumber)
+	require.Equal(t, map[protowire.Number]interface{}{1: uint64(1), 2: `bar`, 3: uint64(1)},
+		decodeProtobufForTest(t, []byte(fields[protobufAfterFieldNumber].(string))))
+	require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  foo after = 1;
+  foo_before before = 2;
+  optional string updated = 3;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bool c = 3;
+  optional double d = 4;
+}
+
+message foo_before {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bool c = 3;
+  optional double d = 4;
+}
+`, reg.SchemaForSubject(`foo-value`))
+
+	// A schema change registers a new version of the schema, in which the
+	// fields of the existing columns keep their numbers.
+	alteredDesc, err := parseTableDesc(
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c BOOL, d FLOAT, e TIMESTAMPTZ)`)
+	require.NoError(t, err)
+	alteredDesc.(*tabledesc.Mutable).Version = tableDesc.GetVersion() + 1
+	eTime := time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC)
+	alteredRow := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`baz`)},
+		rowenc.EncDatum{Datum: tree.DBoolFalse},
+		rowenc.EncDatum{Datum: tree.NewDFloat(1.5)},
+		rowenc.EncDatum{Datum: tree.MustMakeDTimestampTZ(eTime, time.Microsecond)},
+	}
+	rowUpdate := cdcevent.TestingMakeEventRow(alteredDesc, 0, alteredRow, false)
+	prevRow = cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+
+	value, err = e.EncodeValue(ctx, evCtx, rowUpdate, prevRow)
+	require.NoError(t, err)
+	fields = decode(value, `foo-value`)
+	require.Equal(t, map[protowire.Number]interface{}{1: uint64(1), 2: `bar`, 3: uint64(1)},
+		decodeProtobufForTest(t, []byte(fields[protobufBeforeFieldNumber].(string))))
+	after := decodeProtobufForTest(t, []byte(fields[protobufAfterFieldNumber].(string)))
+	require.Equal(t, uint64(0), after[3])
+	require.Equal(t, math.Float64bits(1.5), after[4])
+	require.Equal(t, map[protowire.Number]interface{}{1: uint64(eTime.Unix()), 2: uint64(6000)},
+		decodeProtobufForTest(t, []byte(after[5].(string))))
+	require.Equal(t, `syntax = "proto3";
+
+import "google/protobuf/timestamp.proto";
+
+message foo_envelope {
+  foo after = 1;
+  foo_before before = 2;
+  optional string updated = 3;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bool c = 3;
+  optional double d = 4;
+  google.protobuf.Timestamp e = 5;
+}
+
+message foo_before {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bool c = 3;
+  optional double d = 4;
+}
+`, reg.SchemaForSubject(`foo-value`))
+
+	// Deletes leave the after field unset.
+	rowDelete := cdcevent.TestingMakeEventRow(alteredDesc, 0, alteredRow, true)
+	prevRow = cdcevent.TestingMakeEventRow(alteredDesc, 0, alteredRow, false)
+	value, err = e.EncodeValue(ctx, evCtx, rowDelete, prevRow)
+	require.NoError(t, err)
+	fields = decode(value, `foo-value`)
+	require.NotContains(t, fields, protobufAfterFieldNumber)
+	require.Contains(t, fields, protobufBeforeFieldNumber)
+
+	resolved, err := e.EncodeResolvedTimestamp(ctx, `foo`, ts)
+	require.NoError(t, err)
+	require.Equal(t, map[protowire.Number]interface{}{protobufResolvedFieldNumber: `1.0000000002`},
+		decode(resolved, `foo-value`))
+	require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  optional string resolved = 4;
+}
+`, reg.SchemaForSubject(`foo-value`))
+}
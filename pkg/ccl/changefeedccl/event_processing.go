@@ -13,6 +13,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
@@ -45,6 +46,10 @@ type kvEventToRowConsumer struct {
 	evaluator *cdceval.Evaluator
 	safeExpr  string
 
+	// encodingOpts is used to determine whether rows are encoded by the
+	// encoder or by the sink itself.
+	encodingOpts changefeedbase.EncodingOptions
+
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
 	topicNamer           *TopicNamer
 }
@@ -69,6 +74,11 @@ func newKVEventToRowConsumer(
 		return nil, err
 	}
 
+	encodingOpts, err := details.Opts.GetEncodingOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	var evaluator *cdceval.Evaluator
 	var safeExpr string
 	if expr.Expr != "" {
@@ -95,6 +105,7 @@ func newKVEventToRowConsumer(
 		topicNamer:           topicNamer,
 		evaluator:            evaluator,
 		safeExpr:             safeExpr,
+		encodingOpts:         encodingOpts,
 	}, nil
 }
 
@@ -195,6 +206,12 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return nil
 	}
 
+	if c.encodingOpts.Format == changefeedbase.OptFormatParquet {
+		return c.encodeAndEmitRowInSink(
+			ctx, updatedRow, prevRow, topic, schemaTimestamp, mvccTimestamp, ev.DetachAlloc(),
+		)
+	}
+
 	evCtx := eventContext{
 		updated: schemaTimestamp,
 		mvcc:    mvccTimestamp,
@@ -238,3 +255,33 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	}
 	return nil
 }
+
+// encodeAndEmitRowInSink hands the row over to a sink which encodes it itself.
+func (c *kvEventToRowConsumer) encodeAndEmitRowInSink(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	sinkWithEncoder, ok := c.sink.(SinkWithEncoder)
+	if !ok {
+		return errors.AssertionFailedf("%s=%s requires a sink that encodes rows, found %T",
+			changefeedbase.OptFormat, c.encodingOpts.Format, c.sink)
+	}
+	if c.knobs.BeforeEmitRow != nil {
+		if err := c.knobs.BeforeEmitRow(ctx); err != nil {
+			return err
+		}
+	}
+	if err := sinkWithEncoder.EncodeAndEmitRow(
+		ctx, updatedRow, prevRow, topic, updated, mvcc, alloc,
+	); err != nil {
+		return err
+	}
+	if log.V(3) {
+		log.Infof(ctx, `r %s: emitted by sink`, updatedRow.TableName)
+	}
+	return nil
+}
@@ -28,21 +28,36 @@ import (
 
 const confluentSchemaContentType = `application/vnd.schemaregistry.v1+json`
 
+// confluentSchemaType is the type of the schemas registered with the schema
+// registry.
+type confluentSchemaType string
+
+const (
+	// confluentSchemaTypeAvro is left empty, since AVRO is the default schema
+	// type and schema registries that predate the other schema types don't
+	// know about the schemaType field.
+	confluentSchemaTypeAvro     confluentSchemaType = ``
+	confluentSchemaTypeProtobuf confluentSchemaType = `PROTOBUF`
+)
+
 type schemaRegistry interface {
 	// Ping tests the connectivity to the schema registry. A nil
 	// error is returned if the schema registry appears to be
 	// available.
 	Ping(ctx context.Context) error
 
-	// RegisterSchemaForSubject registers the given schema for the
-	// given subject. The returned int32 is a schema ID that can
-	// be used in Avro wire messages or in other calls to the
-	// schema registry.
-	RegisterSchemaForSubject(ctx context.Context, subject string, schema string) (int32, error)
+	// RegisterSchemaForSubject registers the given schema of the
+	// given type for the given subject. The returned int32 is a
+	// schema ID that can be used in Avro or Protobuf wire messages
+	// or in other calls to the schema registry.
+	RegisterSchemaForSubject(
+		ctx context.Context, subject string, schemaType confluentSchemaType, schema string,
+	) (int32, error)
 }
 
 type confluentSchemaVersionRequest struct {
-	Schema string `json:"schema"`
+	Schema     string              `json:"schema"`
+	SchemaType confluentSchemaType `json:"schemaType,omitempty"`
 }
 
 type confluentSchemaVersionResponse struct {
@@ -139,19 +154,23 @@ func (r *confluentSchemaRegistry) Ping(ctx context.Context) error {
 }
 
 // RegisterSchemaForSubject registers the given schema for the given
-// subject. The schema type is assumed to be AVRO.
+// subject. An empty schema type means AVRO.
 //
 //   https://docs.confluent.io/platform/current/schema-registry/develop/api.html#post--subjects-(string-%20subject)-versions
 //
 func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
-	ctx context.Context, subject string, schema string,
+	ctx context.Context, subject string, schemaType confluentSchemaType, schema string,
 ) (int32, error) {
 	u := r.urlForPath(fmt.Sprintf("subjects/%s/versions", subject))
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", u, schema)
+		if schemaType == confluentSchemaTypeAvro {
+			log.Infof(ctx, "registering avro schema %s %s", u, schema)
+		} else {
+			log.Infof(ctx, "registering %s schema %s %s", schemaType, u, schema)
+		}
 	}
 
-	req := confluentSchemaVersionRequest{Schema: schema}
+	req := confluentSchemaVersionRequest{Schema: schema, SchemaType: schemaType}
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
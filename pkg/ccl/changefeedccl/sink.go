@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -61,6 +62,24 @@ type SinkWithTopics interface {
 	Topics() []string
 }
 
+// SinkWithEncoder is a Sink that encodes rows itself rather than receiving
+// them already encoded. It is used by formats, like parquet, whose files are
+// not a concatenation of independently encoded messages.
+type SinkWithEncoder interface {
+	Sink
+	// EncodeAndEmitRow encodes the row and enqueues it for asynchronous
+	// delivery on the sink. An error may be returned if a previously enqueued
+	// message has failed.
+	EncodeAndEmitRow(
+		ctx context.Context,
+		updatedRow cdcevent.Row,
+		prevRow cdcevent.Row,
+		topic TopicDescriptor,
+		updated, mvcc hlc.Timestamp,
+		alloc kvevent.Alloc,
+	) error
+}
+
 func getSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -68,6 +87,7 @@ func getSink(
 	timestampOracle timestampLowerBoundOracle,
 	user username.SQLUsername,
 	jobID jobspb.JobID,
+	sinkID string,
 	m metricsRecorder,
 ) (Sink, error) {
 	u, err := url.Parse(feedCfg.SinkURI)
@@ -115,7 +135,8 @@ func getSink(
 			return makeNullSink(sinkURL{URL: u}, metricsBuilder(nullIsAccounted))
 		case u.Scheme == changefeedbase.SinkSchemeKafka:
 			return validateOptionsAndMakeSink(changefeedbase.KafkaValidOptions, func() (Sink, error) {
-				return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(), metricsBuilder)
+				return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(),
+					kafkaTransactionalID(jobID, sinkID), metricsBuilder)
 			})
 		case isWebhookSink(u):
 			webhookOpts, err := opts.GetWebhookSinkOptions()
@@ -126,6 +147,10 @@ func getSink(
 				return makeWebhookSink(ctx, sinkURL{URL: u}, encodingOpts, webhookOpts,
 					defaultWorkerCount(), timeutil.DefaultTimeSource{}, metricsBuilder)
 			})
+		case isPulsarSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.PulsarValidOptions, func() (Sink, error) {
+				return makePulsarSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetPulsarConfigJSON(), metricsBuilder)
+			})
 		case isPubsubSink(u):
 			// TODO: add metrics to pubsubsink
 			return MakePubsubSink(ctx, u, encodingOpts, AllTargets(feedCfg))
@@ -250,6 +275,8 @@ type errorWrapperSink struct {
 	wrapped Sink
 }
 
+var _ SinkWithEncoder = errorWrapperSink{}
+
 // EmitRow implements Sink interface.
 func (s errorWrapperSink) EmitRow(
 	ctx context.Context,
@@ -264,6 +291,25 @@ func (s errorWrapperSink) EmitRow(
 	return nil
 }
 
+// EncodeAndEmitRow implements SinkWithEncoder interface.
+func (s errorWrapperSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	sinkWithEncoder, ok := s.wrapped.(SinkWithEncoder)
+	if !ok {
+		return errors.AssertionFailedf("sink %T does not support encoding rows", s.wrapped)
+	}
+	if err := sinkWithEncoder.EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, alloc); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements Sink interface.
 func (s errorWrapperSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
@@ -35,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/fraugster/parquet-go/parquet"
 	"github.com/google/btree"
 )
 
@@ -68,6 +70,8 @@ type cloudStorageSinkFile struct {
 	buf         bytes.Buffer
 	alloc       kvevent.Alloc
 	oldestMVCC  hlc.Timestamp
+	// parquet, if set, encodes the rows of a format=parquet file into buf.
+	parquet *parquetRowWriter
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -287,8 +291,12 @@ type cloudStorageSink struct {
 
 	ext          string
 	rowDelimiter []byte
+	encodingOpts changefeedbase.EncodingOptions
 
 	compression string
+	// parquetCompression is the codec used for the column chunks of
+	// format=parquet files, which are not compressed as a whole.
+	parquetCompression parquet.CompressionCodec
 
 	es cloud.ExternalStorage
 
@@ -368,6 +376,7 @@ func makeCloudStorageSink(
 		// TODO(dan,ajwerner): Use the jobs framework's session ID once that's available.
 		jobSessionID: sessID,
 		topicNamer:   tn,
+		encodingOpts: encodingOpts,
 	}
 
 	if partitionFormat := u.consumeParam(changefeedbase.SinkParamPartitionFormat); partitionFormat != "" {
@@ -395,6 +404,9 @@ func makeCloudStorageSink(
 		// would require a bit of refactoring.
 		s.ext = `.csv`
 		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatParquet:
+		// Rows are encoded by the sink itself, see EncodeAndEmitRow.
+		s.ext = `.parquet`
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, encodingOpts.Format)
@@ -413,8 +425,12 @@ func makeCloudStorageSink(
 
 	if codec := encodingOpts.Compression; codec != "" {
 		if strings.EqualFold(codec, "gzip") {
-			s.compression = sinkCompressionGzip
-			s.ext = s.ext + ".gz"
+			if encodingOpts.Format == changefeedbase.OptFormatParquet {
+				s.parquetCompression = parquet.CompressionCodec_GZIP
+			} else {
+				s.compression = sinkCompressionGzip
+				s.ext = s.ext + ".gz"
+			}
 		} else {
 			return nil, errors.Errorf(`unsupported compression codec %q`, codec)
 		}
@@ -469,6 +485,10 @@ func (s *cloudStorageSink) EmitRow(
 	if s.files == nil {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
+	if s.encodingOpts.Format == changefeedbase.OptFormatParquet {
+		return errors.AssertionFailedf(`%s=%s rows must be emitted with EncodeAndEmitRow`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
 
 	s.metrics.recordMessageSize(int64(len(key) + len(value)))
 	file := s.getOrCreateFile(topic, mvcc)
@@ -489,6 +509,56 @@ func (s *cloudStorageSink) EmitRow(
 	return nil
 }
 
+var _ SinkWithEncoder = (*cloudStorageSink)(nil)
+
+// EncodeAndEmitRow implements the SinkWithEncoder interface. It is used by
+// format=parquet, for which every file holds a single row group: the row group
+// is written out when the file is flushed, either because it outgrew the
+// target file size or because of a resolved timestamp.
+func (s *cloudStorageSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	if s.encodingOpts.Format != changefeedbase.OptFormatParquet {
+		return errors.AssertionFailedf(`%s=%s rows must be emitted with EmitRow`,
+			changefeedbase.OptFormat, s.encodingOpts.Format)
+	}
+
+	file := s.getOrCreateFile(topic, mvcc)
+	file.alloc.Merge(&alloc)
+
+	if file.parquet == nil {
+		// All the rows of a file have the same table version, and therefore the
+		// same columns.
+		var err error
+		file.parquet, err = newParquetRowWriter(&file.buf, updatedRow, s.encodingOpts, s.parquetCompression)
+		if err != nil {
+			return err
+		}
+	}
+	if err := file.parquet.addRow(updatedRow, prevRow, updated, mvcc); err != nil {
+		return err
+	}
+	size := file.parquet.estimatedSize()
+	s.metrics.recordMessageSize(size - int64(file.rawSize))
+	file.rawSize = int(size)
+	file.numMessages++
+
+	if size > s.targetMaxFileSize {
+		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements the Sink interface.
 func (s *cloudStorageSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -586,6 +656,11 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 		return nil
 	}
 
+	if file.parquet != nil {
+		if err := file.parquet.close(); err != nil {
+			return err
+		}
+	}
 	if file.codec != nil {
 		if err := file.codec.Close(); err != nil {
 			return err
@@ -13,6 +13,7 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"net/url"
@@ -42,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
+	goparquet "github.com/fraugster/parquet-go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -721,4 +723,141 @@ func TestCloudStorageSink(t *testing.T) {
 			"w1\n",
 		}, slurpDir(t, dir))
 	})
+
+	t.Run(`parquet`, func(t *testing.T) {
+		tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		require.NoError(t, err)
+		rows, err := parseValues(tableDesc, `VALUES (1, 'one'), (1, 'uno'), (2, 'two')`)
+		require.NoError(t, err)
+		topic := &tableDescriptorTopic{
+			Metadata: makeMetadata(tableDesc),
+			spec: changefeedbase.Target{
+				Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+				TableID:           tableDesc.GetID(),
+				StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+			},
+		}
+		noPrev := cdcevent.TestingMakeEventRow(tableDesc, 0, nil, true)
+		insert := cdcevent.TestingMakeEventRow(tableDesc, 0, rows[0], false)
+		update := cdcevent.TestingMakeEventRow(tableDesc, 0, rows[1], false)
+		insertTwo := cdcevent.TestingMakeEventRow(tableDesc, 0, rows[2], false)
+		deleteTwo := cdcevent.TestingMakeEventRow(tableDesc, 0, rows[2], true)
+
+		parquetOpts := changefeedbase.EncodingOptions{
+			Format:            changefeedbase.OptFormatParquet,
+			Envelope:          changefeedbase.OptEnvelopeWrapped,
+			KeyInValue:        true,
+			Diff:              true,
+			UpdatedTimestamps: true,
+		}
+
+		// readParquetDir returns the rows of every parquet file under root, one
+		// slice per file, sorted by the name of the file. Resolved timestamp
+		// files are skipped.
+		readParquetDir := func(t *testing.T, root string) [][]string {
+			var files [][]string
+			walkFn := func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if info.IsDir() || strings.HasSuffix(path, `.RESOLVED`) {
+					return nil
+				}
+				require.True(t, strings.HasSuffix(path, `.parquet`), path)
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				fr, err := goparquet.NewFileReader(f)
+				if err != nil {
+					return err
+				}
+				// Every file holds a single row group.
+				require.Equal(t, 1, fr.RowGroupCount())
+				cols := fr.GetSchemaDefinition().RootColumn.Children
+				var fileRows []string
+				for {
+					row, err := fr.NextRow()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						return err
+					}
+					var vals []string
+					for _, col := range cols {
+						switch v := row[col.SchemaElement.Name].(type) {
+						case nil:
+							vals = append(vals, `NULL`)
+						case []byte:
+							vals = append(vals, string(v))
+						default:
+							vals = append(vals, fmt.Sprint(v))
+						}
+					}
+					fileRows = append(fileRows, strings.Join(vals, `,`))
+				}
+				files = append(files, fileRows)
+				return nil
+			}
+			absRoot := filepath.Join(dir, root)
+			require.NoError(t, os.MkdirAll(absRoot, 0755))
+			require.NoError(t, filepath.Walk(absRoot, walkFn))
+			return files
+		}
+
+		for _, compression := range []string{"", "gzip"} {
+			parquetOpts.Compression = compression
+			t.Run("compress="+compression, func(t *testing.T) {
+				testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+				sf, err := span.MakeFrontier(testSpan)
+				require.NoError(t, err)
+				timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+				e, err := getEncoder(parquetOpts, changefeedbase.Targets{})
+				require.NoError(t, err)
+
+				sinkDir := `parquet` + compression
+				s, err := makeCloudStorageSink(
+					ctx, sinkURI(sinkDir, unlimitedFileSize), 1, settings,
+					parquetOpts, timestampOracle, externalStorageFromURI, user, nil,
+				)
+				require.NoError(t, err)
+				defer func() { require.NoError(t, s.Close()) }()
+				sw := s.(SinkWithEncoder)
+
+				// Rows have to be encoded by the sink.
+				require.Error(t, s.EmitRow(ctx, topic, noKey, []byte(`v1`), ts(1), ts(1), zeroAlloc))
+
+				require.NoError(t, sw.EncodeAndEmitRow(ctx, insert, noPrev, topic, ts(1), ts(1), zeroAlloc))
+				require.NoError(t, sw.EncodeAndEmitRow(ctx, update, insert, topic, ts(2), ts(2), zeroAlloc))
+				require.NoError(t, sw.EncodeAndEmitRow(ctx, insertTwo, noPrev, topic, ts(2), ts(2), zeroAlloc))
+				require.Equal(t, [][]string(nil), readParquetDir(t, sinkDir))
+				require.NoError(t, s.Flush(ctx))
+				require.NoError(t, s.EmitResolvedTimestamp(ctx, e, ts(2)))
+
+				require.True(t, forwardFrontier(sf, testSpan, 3))
+				require.NoError(t, s.Flush(ctx))
+				require.NoError(t, sw.EncodeAndEmitRow(ctx, deleteTwo, insertTwo, topic, ts(3), ts(3), zeroAlloc))
+				require.NoError(t, s.Flush(ctx))
+
+				require.Equal(t, [][]string{
+					{
+						`1,one,c,1.0000000000`,
+						`1,uno,u,2.0000000000`,
+						`2,two,c,2.0000000000`,
+					},
+					{
+						`2,NULL,d,3.0000000000`,
+					},
+				}, readParquetDir(t, sinkDir))
+
+				// Resolved timestamps are still written as JSON.
+				resolvedFile, err := ioutil.ReadFile(filepath.Join(
+					dir, sinkDir, `1970-01-01`, `197001010000000000000020000000000.RESOLVED`))
+				require.NoError(t, err)
+				require.Equal(t, `{"resolved":"2.0000000000"}`, string(resolvedFile))
+			})
+		}
+	})
 }
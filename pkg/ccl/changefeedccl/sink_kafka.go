@@ -86,6 +86,23 @@ type kafkaSink struct {
 	producer       sarama.AsyncProducer
 	topics         *TopicNamer
 
+	// transactionalID is set if the sink writes to kafka in transactions, see
+	// kafkaTxnProducer. The messages emitted since the last flush are then
+	// buffered in txnMsgs, and written and committed by txn when the sink is
+	// flushed, or once they exceed txnMaxBufferedBytes, instead of going
+	// through producer.
+	//
+	// Every transaction is atomic for consumers using read_committed, which
+	// never see the rows of a transaction that was aborted. Delivery is still
+	// at-least-once, as with the other sinks: the sink is flushed before the
+	// changefeed checkpoints its progress, so the rows committed after the
+	// last checkpoint are emitted again when the changefeed restarts.
+	transactionalID     string
+	txn                 *kafkaTxnProducer
+	txnMsgs             []*sarama.ProducerMessage
+	txnBufferedBytes    int64
+	txnMaxBufferedBytes int64
+
 	lastMetadataRefresh time.Time
 
 	stopWorkerCh chan struct{}
@@ -180,6 +197,11 @@ func (s *kafkaSink) Dial() error {
 		return pgerror.Wrapf(err, pgcode.CannotConnectNow,
 			`connecting to kafka: %s`, s.bootstrapAddrs)
 	}
+	if s.transactionalID != `` {
+		s.client = client
+		s.txn = newKafkaTxnProducer(client, s.transactionalID)
+		return nil
+	}
 	s.producer, err = sarama.NewAsyncProducerFromClient(client)
 	if err != nil {
 		return pgerror.Wrapf(err, pgcode.CannotConnectNow,
@@ -192,6 +214,13 @@ func (s *kafkaSink) Dial() error {
 
 // Close implements the Sink interface.
 func (s *kafkaSink) Close() error {
+	if s.txn != nil {
+		// Messages that weren't committed by a flush are dropped; an open
+		// transaction is aborted by the coordinator.
+		s.releaseTxnMsgs()
+		_ = s.txn.Close()
+		return s.client.Close()
+	}
 	close(s.stopWorkerCh)
 	s.worker.Wait()
 	// If we're shutting down, we don't care what happens to the outstanding
@@ -230,6 +259,18 @@ func (s *kafkaSink) EmitRow(
 		Value:    sarama.ByteEncoder(value),
 		Metadata: messageMetadata{alloc: alloc, mvcc: mvcc, updateMetrics: s.metrics.recordOneMessage()},
 	}
+	if s.txn != nil {
+		s.txnMsgs = append(s.txnMsgs, msg)
+		s.txnBufferedBytes += int64(msg.Key.Length() + msg.Value.Length())
+		if s.txnBufferedBytes > s.txnMaxBufferedBytes {
+			// Rather than buffering an unbounded number of messages until the
+			// next flush, commit the ones buffered so far. This doesn't weaken
+			// the guarantees of the sink, since the rows up to a checkpoint can
+			// be spread over several transactions anyway.
+			return s.commitTxn(ctx)
+		}
+		return nil
+	}
 	s.stats.startMessage(int64(msg.Key.Length() + msg.Value.Length()))
 	return s.emitMessage(ctx, msg)
 }
@@ -255,7 +296,7 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 		s.lastMetadataRefresh = timeutil.Now()
 	}
 
-	return s.topics.Each(func(topic string) error {
+	if err := s.topics.Each(func(topic string) error {
 		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
 		if err != nil {
 			return err
@@ -277,18 +318,36 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 				Key:       nil,
 				Value:     sarama.ByteEncoder(payload),
 			}
+			if s.txn != nil {
+				s.txnMsgs = append(s.txnMsgs, msg)
+				continue
+			}
 			if err := s.emitMessage(ctx, msg); err != nil {
 				return err
 			}
 		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	// Resolved timestamps are emitted by a sink that is never flushed, so
+	// their transaction is committed right away. The rows up to the resolved
+	// timestamp were committed by the flushes that preceded it.
+	if s.txn != nil {
+		return s.commitTxn(ctx)
+	}
+	return nil
 }
 
 // Flush implements the Sink interface.
 func (s *kafkaSink) Flush(ctx context.Context) error {
 	defer s.metrics.recordFlushRequestCallback()()
 
+	if s.txn != nil {
+		return s.commitTxn(ctx)
+	}
+
 	flushCh := make(chan struct{}, 1)
 
 	s.mu.Lock()
@@ -320,6 +379,32 @@ func (s *kafkaSink) Flush(ctx context.Context) error {
 	}
 }
 
+// commitTxn writes the messages emitted since the last commit to kafka, and
+// commits them in a single transaction.
+func (s *kafkaSink) commitTxn(ctx context.Context) error {
+	defer s.releaseTxnMsgs()
+	if err := s.txn.commit(ctx, s.txnMsgs); err != nil {
+		return err
+	}
+	for _, msg := range s.txnMsgs {
+		if m, ok := msg.Metadata.(messageMetadata); ok {
+			m.updateMetrics(m.mvcc, msg.Key.Length()+msg.Value.Length(), sinkDoesNotCompress)
+		}
+	}
+	return nil
+}
+
+// releaseTxnMsgs releases the resources of the buffered messages.
+func (s *kafkaSink) releaseTxnMsgs() {
+	for _, msg := range s.txnMsgs {
+		if m, ok := msg.Metadata.(messageMetadata); ok {
+			m.alloc.Release(s.ctx)
+		}
+	}
+	s.txnMsgs = s.txnMsgs[:0]
+	s.txnBufferedBytes = 0
+}
+
 func (s *kafkaSink) startInflightMessage(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -646,6 +731,7 @@ func makeKafkaSink(
 	u sinkURL,
 	targets changefeedbase.Targets,
 	jsonStr changefeedbase.SinkSpecificJSONConfig,
+	transactionalID string,
 	mb metricsRecorderBuilder,
 ) (Sink, error) {
 	kafkaTopicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
@@ -654,10 +740,19 @@ func makeKafkaSink(
 		return nil, errors.Errorf(`%s is not yet supported`, changefeedbase.SinkParamSchemaTopic)
 	}
 
+	var transactional bool
+	if _, err := u.consumeBool(changefeedbase.SinkParamKafkaTransactional, &transactional); err != nil {
+		return nil, err
+	}
+
 	config, err := buildKafkaConfig(u, jsonStr)
 	if err != nil {
 		return nil, err
 	}
+	if transactional && !config.Version.IsAtLeast(sarama.V0_11_0_0) {
+		return nil, errors.Errorf(`%s requires kafka version %s or later`,
+			changefeedbase.SinkParamKafkaTransactional, sarama.V0_11_0_0)
+	}
 
 	topics, err := MakeTopicNamer(
 		targets,
@@ -674,6 +769,10 @@ func makeKafkaSink(
 		metrics:        mb(requiresResourceAccounting),
 		topics:         topics,
 	}
+	if transactional {
+		sink.transactionalID = transactionalID
+		sink.txnMaxBufferedBytes = kafkaTxnMaxBufferedBytes
+	}
 
 	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
 		return nil, errors.Errorf(
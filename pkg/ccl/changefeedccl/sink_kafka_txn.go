@@ -0,0 +1,365 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// kafkaTxnTimeout is the transaction.timeout.ms of the transactional producer.
+// A transaction left open by a producer that went away is aborted by the
+// transaction coordinator once it times out, or when a producer with the same
+// transactional.id is initialized, whichever comes first.
+const kafkaTxnTimeout = time.Minute
+
+// kafkaTxnMaxBufferedBytes is the default limit on the size of the messages
+// that a transactional kafka sink buffers between flushes. Once it is
+// exceeded, the buffered messages are committed without waiting for the flush.
+const kafkaTxnMaxBufferedBytes = 16 << 20 // 16 MiB
+
+// frontierSinkID is the sink ID of the sink of the change frontier, which only
+// emits resolved timestamps.
+const frontierSinkID = `frontier`
+
+// spansSinkID returns the sink ID of the sink of a change aggregator which
+// watches the given spans. It only depends on the spans, so that a change
+// aggregator that is restarted with the same spans after the changefeed is
+// replanned gets the same ID, whichever processor it is planned as.
+func spansSinkID(spans []roachpb.Span) string {
+	sorted := append(roachpb.Spans(nil), spans...)
+	sort.Sort(sorted)
+	h := fnv.New64a()
+	var buf [binary.MaxVarintLen64]byte
+	for _, sp := range sorted {
+		for _, key := range []roachpb.Key{sp.Key, sp.EndKey} {
+			_, _ = h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)))])
+			_, _ = h.Write(key)
+		}
+	}
+	return fmt.Sprintf(`%016x`, h.Sum64())
+}
+
+// kafkaTransactionalID returns the transactional.id used by the kafka sink
+// with the given sink ID in the given changefeed. The ID only depends on the
+// job and on the spans the sink emits (see spansSinkID), so that the producer
+// of a sink that is restarted for the same spans fences off the producer of its
+// previous incarnation, and aborts the transaction it left open.
+//
+// If the changefeed is replanned with different spans per aggregator, the
+// producers of the previous plan are not fenced off, and their open
+// transactions are aborted once they time out. As the changefeed restarts from
+// its last checkpoint, which the rows of these transactions are not covered
+// by, no rows are lost.
+func kafkaTransactionalID(jobID jobspb.JobID, sinkID string) string {
+	return fmt.Sprintf(`crdb-changefeed-%d-%s`, jobID, sinkID)
+}
+
+// kafkaTxnProducer writes messages to kafka in transactions. sarama's
+// producers don't support transactions, so it drives the transaction protocol
+// (KIP-98) itself using the requests that sarama does implement. Every call to
+// commit writes the given messages and commits them in a single transaction.
+//
+// It is not concurrency-safe.
+type kafkaTxnProducer struct {
+	client sarama.Client
+	txnID  string
+
+	// coordinator is the transaction coordinator for txnID. It is nil until
+	// the producer is initialized, and is reset whenever a transaction fails,
+	// so that the next transaction re-initializes the producer.
+	coordinator   *sarama.Broker
+	producerID    int64
+	producerEpoch int16
+	// sequences holds the sequence number of the next record written to each
+	// partition by this producer ID and epoch.
+	sequences map[string]map[int32]int32
+}
+
+func newKafkaTxnProducer(client sarama.Client, txnID string) *kafkaTxnProducer {
+	return &kafkaTxnProducer{client: client, txnID: txnID}
+}
+
+// init finds the transaction coordinator and gets a producer ID and epoch for
+// the transactional ID. Bumping the epoch aborts any transaction that was left
+// open by a previous producer with the same transactional ID, and fences off
+// that producer if it is still running.
+func (p *kafkaTxnProducer) init() error {
+	controller, err := p.client.Controller()
+	if err != nil {
+		return err
+	}
+	findResp, err := controller.FindCoordinator(&sarama.FindCoordinatorRequest{
+		Version:         1,
+		CoordinatorKey:  p.txnID,
+		CoordinatorType: sarama.CoordinatorTransaction,
+	})
+	if err != nil {
+		return errors.Wrapf(err, `finding transaction coordinator for %s`, p.txnID)
+	}
+	if findResp.Err != sarama.ErrNoError {
+		return errors.Wrapf(findResp.Err, `finding transaction coordinator for %s`, p.txnID)
+	}
+	coordinator := findResp.Coordinator
+	if err := coordinator.Open(p.client.Config()); err != nil && !errors.Is(err, sarama.ErrAlreadyConnected) {
+		return err
+	}
+
+	txnID := p.txnID
+	initResp, err := coordinator.InitProducerID(&sarama.InitProducerIDRequest{
+		TransactionalID:    &txnID,
+		TransactionTimeout: kafkaTxnTimeout,
+	})
+	if err == nil && initResp.Err != sarama.ErrNoError {
+		err = initResp.Err
+	}
+	if err != nil {
+		_ = coordinator.Close()
+		return errors.Wrapf(err, `initializing transactional producer %s`, p.txnID)
+	}
+
+	p.coordinator = coordinator
+	p.producerID = initResp.ProducerID
+	p.producerEpoch = initResp.ProducerEpoch
+	p.sequences = make(map[string]map[int32]int32)
+	return nil
+}
+
+// commit writes the messages to kafka and commits them in a single
+// transaction. Keyed messages are partitioned by the hash of their key, and
+// the others are written to the partition they name. If commit returns an
+// error, the transaction was aborted, or will be by the coordinator once it
+// times out.
+func (p *kafkaTxnProducer) commit(ctx context.Context, msgs []*sarama.ProducerMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	if p.coordinator == nil {
+		if err := p.init(); err != nil {
+			return err
+		}
+	}
+
+	batches, err := p.partition(msgs)
+	if err != nil {
+		return err
+	}
+	if err := p.addPartitionsToTxn(batches); err != nil {
+		return p.abort(ctx, err)
+	}
+	if err := p.produce(batches); err != nil {
+		return p.abort(ctx, err)
+	}
+	if err := p.endTxn(true /* commit */); err != nil {
+		p.reset()
+		return errors.Wrap(err, `committing kafka transaction`)
+	}
+	return nil
+}
+
+// partition groups the messages by the topic and partition they are written
+// to, preserving their order.
+func (p *kafkaTxnProducer) partition(
+	msgs []*sarama.ProducerMessage,
+) (map[string]map[int32][]*sarama.ProducerMessage, error) {
+	batches := make(map[string]map[int32][]*sarama.ProducerMessage)
+	partitioners := make(map[string]sarama.Partitioner)
+	numPartitions := make(map[string]int32)
+	for _, msg := range msgs {
+		partitioner, ok := partitioners[msg.Topic]
+		if !ok {
+			partitions, err := p.client.Partitions(msg.Topic)
+			if err != nil {
+				return nil, err
+			}
+			partitioner = newChangefeedPartitioner(msg.Topic)
+			partitioners[msg.Topic] = partitioner
+			numPartitions[msg.Topic] = int32(len(partitions))
+			batches[msg.Topic] = make(map[int32][]*sarama.ProducerMessage)
+		}
+		partition, err := partitioner.Partition(msg, numPartitions[msg.Topic])
+		if err != nil {
+			return nil, err
+		}
+		msg.Partition = partition
+		batches[msg.Topic][partition] = append(batches[msg.Topic][partition], msg)
+	}
+	return batches, nil
+}
+
+// addPartitionsToTxn registers the partitions written by the transaction with
+// the coordinator, which writes the commit or abort markers to them when the
+// transaction ends.
+func (p *kafkaTxnProducer) addPartitionsToTxn(
+	batches map[string]map[int32][]*sarama.ProducerMessage,
+) error {
+	req := &sarama.AddPartitionsToTxnRequest{
+		TransactionalID: p.txnID,
+		ProducerID:      p.producerID,
+		ProducerEpoch:   p.producerEpoch,
+		TopicPartitions: make(map[string][]int32),
+	}
+	for topic, partitions := range batches {
+		for partition := range partitions {
+			req.TopicPartitions[topic] = append(req.TopicPartitions[topic], partition)
+		}
+	}
+	resp, err := p.coordinator.AddPartitionsToTxn(req)
+	if err != nil {
+		return err
+	}
+	for topic, partitionErrs := range resp.Errors {
+		for _, partitionErr := range partitionErrs {
+			if partitionErr.Err != sarama.ErrNoError {
+				return errors.Wrapf(partitionErr.Err,
+					`adding partition %d of topic %s to kafka transaction`, partitionErr.Partition, topic)
+			}
+		}
+	}
+	return nil
+}
+
+// produce writes the messages to the leaders of their partitions, as a single
+// record batch per partition.
+func (p *kafkaTxnProducer) produce(batches map[string]map[int32][]*sarama.ProducerMessage) error {
+	type topicPartition struct {
+		topic     string
+		partition int32
+	}
+	type leaderRequest struct {
+		req        *sarama.ProduceRequest
+		partitions []topicPartition
+	}
+
+	config := p.client.Config()
+	requests := make(map[*sarama.Broker]*leaderRequest)
+	now := timeutil.Now()
+	for topic, partitions := range batches {
+		if p.sequences[topic] == nil {
+			p.sequences[topic] = make(map[int32]int32)
+		}
+		for partition, msgs := range partitions {
+			leader, err := p.client.Leader(topic, partition)
+			if err != nil {
+				return err
+			}
+			lr, ok := requests[leader]
+			if !ok {
+				txnID := p.txnID
+				lr = &leaderRequest{req: &sarama.ProduceRequest{
+					TransactionalID: &txnID,
+					RequiredAcks:    sarama.WaitForAll,
+					Timeout:         int32(config.Producer.Timeout / time.Millisecond),
+					Version:         3,
+				}}
+				requests[leader] = lr
+			}
+
+			batch := &sarama.RecordBatch{
+				Version:          2,
+				Codec:            config.Producer.Compression,
+				CompressionLevel: config.Producer.CompressionLevel,
+				FirstTimestamp:   now,
+				MaxTimestamp:     now,
+				ProducerID:       p.producerID,
+				ProducerEpoch:    p.producerEpoch,
+				FirstSequence:    p.sequences[topic][partition],
+				IsTransactional:  true,
+				LastOffsetDelta:  int32(len(msgs) - 1),
+				Records:          make([]*sarama.Record, len(msgs)),
+			}
+			for i, msg := range msgs {
+				record := &sarama.Record{OffsetDelta: int64(i)}
+				if msg.Key != nil {
+					if record.Key, err = msg.Key.Encode(); err != nil {
+						return err
+					}
+				}
+				if record.Value, err = msg.Value.Encode(); err != nil {
+					return err
+				}
+				batch.Records[i] = record
+			}
+			lr.req.AddBatch(topic, partition, batch)
+			lr.partitions = append(lr.partitions, topicPartition{topic: topic, partition: partition})
+			p.sequences[topic][partition] += int32(len(msgs))
+		}
+	}
+
+	for leader, lr := range requests {
+		resp, err := leader.Produce(lr.req)
+		if err != nil {
+			return err
+		}
+		for _, tp := range lr.partitions {
+			block := resp.GetBlock(tp.topic, tp.partition)
+			if block == nil {
+				return errors.Errorf(`no response for partition %d of topic %s`, tp.partition, tp.topic)
+			}
+			if block.Err != sarama.ErrNoError {
+				return errors.Wrapf(block.Err, `writing to partition %d of topic %s`, tp.partition, tp.topic)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *kafkaTxnProducer) endTxn(commit bool) error {
+	resp, err := p.coordinator.EndTxn(&sarama.EndTxnRequest{
+		TransactionalID:   p.txnID,
+		ProducerID:        p.producerID,
+		ProducerEpoch:     p.producerEpoch,
+		TransactionResult: commit,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Err != sarama.ErrNoError {
+		return resp.Err
+	}
+	return nil
+}
+
+// abort aborts the open transaction after it failed with the given error,
+// which it returns. The producer is re-initialized by the next transaction,
+// since the sequence numbers of the partitions that were written to are
+// unknown.
+func (p *kafkaTxnProducer) abort(ctx context.Context, err error) error {
+	if abortErr := p.endTxn(false /* commit */); abortErr != nil {
+		log.Warningf(ctx, "failed to abort kafka transaction %s: %v", p.txnID, abortErr)
+	}
+	p.reset()
+	return errors.Wrap(err, `kafka transaction aborted`)
+}
+
+func (p *kafkaTxnProducer) reset() {
+	if p.coordinator != nil {
+		_ = p.coordinator.Close()
+	}
+	p.coordinator = nil
+}
+
+// Close closes the connection to the transaction coordinator. An open
+// transaction is aborted once it times out, or when the transactional ID is
+// initialized again.
+func (p *kafkaTxnProducer) Close() error {
+	p.reset()
+	return nil
+}
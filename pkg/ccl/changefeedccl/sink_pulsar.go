@@ -0,0 +1,620 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// pulsarDefaultTenant and pulsarDefaultNamespace are used when the sink
+	// URI doesn't name a namespace.
+	pulsarDefaultTenant    = `public`
+	pulsarDefaultNamespace = `default`
+	// pulsarDialTimeout bounds the time spent connecting a producer or
+	// looking up the partitions of a topic.
+	pulsarDialTimeout = 30 * time.Second
+)
+
+func isPulsarSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemePulsar
+}
+
+// pulsarSink emits to Apache Pulsar through the WebSocket API served by its
+// brokers (or by a standalone WebSocket proxy). Every topic is written by its
+// own producer connection. It is not concurrency-safe; all calls to Emit and
+// Flush should be from the same goroutine.
+type pulsarSink struct {
+	ctx context.Context
+	// producerURL and adminURL are the base URLs of the WebSocket producer
+	// endpoint and of the admin REST endpoint of the namespace the sink writes
+	// to. The name of a topic is appended to them.
+	producerURL    url.URL
+	adminURL       url.URL
+	producerParams url.Values
+	header         http.Header
+	dialer         *websocket.Dialer
+	client         *httputil.Client
+
+	// producers holds a producer per topic, as well as a producer per
+	// partition of every topic, which are used to send resolved timestamps
+	// to each partition. They are created lazily.
+	producers map[string]*pulsarProducer
+	topics    *TopicNamer
+	metrics   metricsRecorder
+
+	// Synchronized between the sink and the acknowledgement callbacks, which
+	// are run on the goroutines reading from the producer connections.
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+var _ Sink = (*pulsarSink)(nil)
+var _ SinkWithTopics = (*pulsarSink)(nil)
+
+// pulsarSinkConfig is the JSON configuration of the pulsar sink, which is
+// provided via the pulsar_sink_config option.
+type pulsarSinkConfig struct {
+	// Batching configures the batching done by the producers of the broker.
+	Batching struct {
+		Disabled  bool         `json:",omitempty"`
+		Messages  uint         `json:",omitempty"`
+		Frequency jsonDuration `json:",omitempty"`
+	}
+}
+
+func getPulsarSinkConfig(
+	jsonStr changefeedbase.SinkSpecificJSONConfig,
+) (config *pulsarSinkConfig, err error) {
+	config = &pulsarSinkConfig{}
+	if jsonStr != `` {
+		err = json.Unmarshal([]byte(jsonStr), config)
+	}
+	if err == nil && config.Batching.Frequency < 0 {
+		err = errors.Errorf(`Batching.Frequency must be non-negative`)
+	}
+	return
+}
+
+// Apply sets the query parameters of the producer endpoint corresponding to
+// this config. Zero values leave the defaults of the broker in place.
+func (c *pulsarSinkConfig) Apply(params url.Values) {
+	params.Set(`batchingEnabled`, strconv.FormatBool(!c.Batching.Disabled))
+	if c.Batching.Messages > 0 {
+		params.Set(`batchingMaxMessages`, strconv.FormatUint(uint64(c.Batching.Messages), 10))
+	}
+	if c.Batching.Frequency > 0 {
+		// The broker takes the delay in milliseconds.
+		delay := time.Duration(c.Batching.Frequency).Milliseconds()
+		if delay == 0 {
+			delay = 1
+		}
+		params.Set(`batchingMaxPublishDelay`, strconv.FormatInt(delay, 10))
+	}
+}
+
+func makePulsarSink(
+	ctx context.Context,
+	u sinkURL,
+	targets changefeedbase.Targets,
+	jsonStr changefeedbase.SinkSpecificJSONConfig,
+	mb metricsRecorderBuilder,
+) (Sink, error) {
+	if u.Host == `` {
+		return nil, errors.Errorf(`%s sink requires a broker address`, changefeedbase.SinkSchemePulsar)
+	}
+	// The path of the URI names the namespace as /<tenant>/<namespace>.
+	tenant, namespace := pulsarDefaultTenant, pulsarDefaultNamespace
+	if path := strings.Trim(u.Path, `/`); path != `` {
+		parts := strings.Split(path, `/`)
+		if len(parts) != 2 || parts[0] == `` || parts[1] == `` {
+			return nil, errors.Errorf(
+				`%s sink path must be of the form /<tenant>/<namespace>, got %s`,
+				changefeedbase.SinkSchemePulsar, u.Path)
+		}
+		tenant, namespace = parts[0], parts[1]
+	}
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+
+	dialConfig := struct {
+		tlsEnabled    bool
+		tlsSkipVerify bool
+		caCert        []byte
+		clientCert    []byte
+		clientKey     []byte
+		authToken     string
+	}{}
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &dialConfig.tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &dialConfig.tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &dialConfig.caCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &dialConfig.clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &dialConfig.clientKey); err != nil {
+		return nil, err
+	}
+	dialConfig.authToken = u.consumeParam(changefeedbase.SinkParamAuthToken)
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown pulsar sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	wsScheme, httpScheme := `ws`, `http`
+	var tlsConfig *tls.Config
+	if dialConfig.tlsEnabled {
+		wsScheme, httpScheme = `wss`, `https`
+		tlsConfig = &tls.Config{InsecureSkipVerify: dialConfig.tlsSkipVerify}
+
+		if dialConfig.caCert != nil {
+			caCertPool, err := x509.SystemCertPool()
+			if err != nil {
+				return nil, errors.Wrap(err, "could not load system root CA pool")
+			}
+			if caCertPool == nil {
+				caCertPool = x509.NewCertPool()
+			}
+			if !caCertPool.AppendCertsFromPEM(dialConfig.caCert) {
+				return nil, errors.Errorf("failed to parse certificate data:%s", string(dialConfig.caCert))
+			}
+			tlsConfig.RootCAs = caCertPool
+		}
+
+		if dialConfig.clientCert != nil && dialConfig.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		} else if dialConfig.clientKey != nil && dialConfig.clientCert == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+		}
+
+		if dialConfig.clientCert != nil && dialConfig.clientKey != nil {
+			cert, err := tls.X509KeyPair(dialConfig.clientCert, dialConfig.clientKey)
+			if err != nil {
+				return nil, errors.Wrap(err, `invalid client certificate data provided`)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	} else {
+		if dialConfig.caCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if dialConfig.clientCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+		}
+	}
+	header := make(http.Header)
+	if dialConfig.authToken != `` {
+		header.Set(`Authorization`, `Bearer `+dialConfig.authToken)
+	}
+
+	// Apply statement level overrides.
+	config, err := getPulsarSinkConfig(jsonStr)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to parse pulsar config; check %s option", changefeedbase.OptPulsarSinkConfig)
+	}
+	producerParams := make(url.Values)
+	config.Apply(producerParams)
+
+	topics, err := MakeTopicNamer(
+		targets,
+		WithPrefix(topicPrefix), WithSingleName(topicName), WithSanitizeFn(SQLNameToKafkaName))
+	if err != nil {
+		return nil, err
+	}
+
+	namespacePath := `/` + url.PathEscape(tenant) + `/` + url.PathEscape(namespace)
+	return &pulsarSink{
+		ctx: ctx,
+		producerURL: url.URL{
+			Scheme: wsScheme, Host: u.Host, Path: `/ws/v2/producer/persistent` + namespacePath,
+		},
+		adminURL: url.URL{
+			Scheme: httpScheme, Host: u.Host, Path: `/admin/v2/persistent` + namespacePath,
+		},
+		producerParams: producerParams,
+		header:         header,
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: pulsarDialTimeout,
+			TLSClientConfig:  tlsConfig,
+		},
+		client: &httputil.Client{Client: &http.Client{
+			Timeout: pulsarDialTimeout,
+			Transport: &http.Transport{
+				DialContext:     (&net.Dialer{Timeout: pulsarDialTimeout}).DialContext,
+				TLSClientConfig: tlsConfig,
+			},
+		}},
+		producers: make(map[string]*pulsarProducer),
+		topics:    topics,
+		metrics:   mb(requiresResourceAccounting),
+	}, nil
+}
+
+// Dial implements the Sink interface.
+func (s *pulsarSink) Dial() error {
+	// Creating the producers up front surfaces connection and authorization
+	// problems when the changefeed starts rather than on its first message.
+	return s.topics.Each(func(topic string) error {
+		if _, err := s.getProducer(topic); err != nil {
+			return pgerror.Wrapf(err, pgcode.CannotConnectNow,
+				`connecting to pulsar: %s`, s.producerURL.Host)
+		}
+		return nil
+	})
+}
+
+// Close implements the Sink interface.
+func (s *pulsarSink) Close() error {
+	// If we're shutting down, we don't care what happens to the outstanding
+	// messages.
+	var err error
+	for _, p := range s.producers {
+		err = errors.CombineErrors(err, p.close())
+	}
+	s.producers = nil
+	s.client.CloseIdleConnections()
+	return err
+}
+
+// getProducer returns the producer for the given topic, connecting it if
+// necessary.
+func (s *pulsarSink) getProducer(topic string) (*pulsarProducer, error) {
+	if p, ok := s.producers[topic]; ok {
+		return p, nil
+	}
+	u := s.producerURL
+	u.Path += `/` + url.PathEscape(topic)
+	u.RawQuery = s.producerParams.Encode()
+	conn, resp, err := s.dialer.DialContext(s.ctx, u.String(), s.header)
+	if err != nil {
+		if resp != nil {
+			return nil, errors.Wrapf(err, `creating producer for topic %s: %s`, topic, resp.Status)
+		}
+		return nil, errors.Wrapf(err, `creating producer for topic %s`, topic)
+	}
+	p := &pulsarProducer{
+		topic: topic,
+		conn:  conn,
+		done:  make(chan struct{}),
+	}
+	p.mu.pending = make(map[string]func(error))
+	go p.readAcks()
+	s.producers[topic] = p
+	return p, nil
+}
+
+// topicPartitions returns the names of the partitions of the given topic. A
+// non-partitioned topic is its own single partition.
+func (s *pulsarSink) topicPartitions(ctx context.Context, topic string) ([]string, error) {
+	u := s.adminURL
+	u.Path += `/` + url.PathEscape(topic) + `/partitions`
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil /* body */)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = s.header.Clone()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, `looking up the partitions of topic %s`, topic)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf(`looking up the partitions of topic %s: %s: %s`,
+			topic, resp.Status, body)
+	}
+	var metadata struct {
+		Partitions int `json:"partitions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, errors.Wrapf(err, `looking up the partitions of topic %s`, topic)
+	}
+	if metadata.Partitions == 0 {
+		return []string{topic}, nil
+	}
+	partitions := make([]string, metadata.Partitions)
+	for i := range partitions {
+		partitions[i] = fmt.Sprintf(`%s-partition-%d`, topic, i)
+	}
+	return partitions, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *pulsarSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	topic, err := s.topics.Name(topicDescr)
+	if err != nil {
+		return err
+	}
+	producer, err := s.getProducer(topic)
+	if err != nil {
+		return err
+	}
+
+	// Messages with the same key are routed to the same partition by the
+	// broker, and a producer's messages are persisted in the order they are
+	// sent, so the updates to a row arrive in order.
+	updateMetrics := s.metrics.recordOneMessage()
+	return s.emitMessage(ctx, producer, key, value, func(err error) {
+		if err == nil {
+			updateMetrics(mvcc, len(key)+len(value), sinkDoesNotCompress)
+		}
+		alloc.Release(s.ctx)
+	})
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *pulsarSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	return s.topics.Each(func(topic string) error {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		// Keyless messages are spread across partitions by the broker, so
+		// the resolved timestamp is sent through a producer on each partition
+		// of the topic instead. The partitions are looked up every time
+		// because they can be added to a topic while the changefeed runs.
+		partitions, err := s.topicPartitions(ctx, topic)
+		if err != nil {
+			return err
+		}
+		for _, partition := range partitions {
+			producer, err := s.getProducer(partition)
+			if err != nil {
+				return err
+			}
+			if err := s.emitMessage(ctx, producer, nil /* key */, payload, nil /* onAck */); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Flush implements the Sink interface.
+func (s *pulsarSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	flushCh := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	flushErr := s.mu.flushErr
+	s.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		s.mu.Lock()
+		flushErr := s.mu.flushErr
+		s.mu.flushErr = nil
+		s.mu.Unlock()
+		return flushErr
+	}
+}
+
+// emitMessage sends the message without waiting for it to be acknowledged.
+// onAck, if set, is run once the message is acknowledged by the broker or
+// fails to be persisted; such failures are returned by the next call to
+// Flush.
+func (s *pulsarSink) emitMessage(
+	ctx context.Context, producer *pulsarProducer, key, payload []byte, onAck func(error),
+) error {
+	s.mu.Lock()
+	s.mu.inflight++
+	if log.V(2) {
+		log.Infof(ctx, "emitting %d inflight records to pulsar", s.mu.inflight)
+	}
+	s.mu.Unlock()
+
+	keyStr, size := string(key), len(key)+len(payload)
+	err := producer.send(keyStr, payload, func(err error) {
+		if err != nil {
+			err = errors.Wrapf(err, "while sending message with key=%s, size=%d to topic %s",
+				keyStr, size, producer.topic)
+		}
+		if onAck != nil {
+			onAck(err)
+		}
+		s.messageDone(err)
+	})
+	if err != nil {
+		s.messageDone(nil /* err */)
+		return errors.Wrapf(err, `sending message to topic %s`, producer.topic)
+	}
+	return nil
+}
+
+// messageDone records that a message is no longer inflight.
+func (s *pulsarSink) messageDone(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.inflight--
+	if s.mu.flushErr == nil && err != nil {
+		s.mu.flushErr = err
+	}
+	if s.mu.inflight == 0 && s.mu.flushCh != nil {
+		s.mu.flushCh <- struct{}{}
+		s.mu.flushCh = nil
+	}
+}
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *pulsarSink) Topics() []string {
+	return s.topics.DisplayNamesSlice()
+}
+
+// pulsarMessage is a message sent to the WebSocket producer endpoint. The
+// payload is base64 encoded by encoding/json.
+type pulsarMessage struct {
+	Payload []byte `json:"payload"`
+	Key     string `json:"key,omitempty"`
+	Context string `json:"context"`
+}
+
+// pulsarAck is the response of the broker to a pulsarMessage, which carries
+// the context of the message it acknowledges. Result is "ok" if the message
+// was persisted.
+type pulsarAck struct {
+	Result   string `json:"result"`
+	ErrorMsg string `json:"errorMsg,omitempty"`
+	Context  string `json:"context"`
+}
+
+// pulsarProducer is a connection to the WebSocket producer endpoint of a
+// topic. Messages are written by the sink's goroutine and acknowledgements
+// are read by a goroutine owned by the producer.
+type pulsarProducer struct {
+	topic  string
+	conn   *websocket.Conn
+	nextID uint64
+	// done is closed when the goroutine reading acknowledgements exits.
+	done chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		// pending maps the context of the messages waiting for an
+		// acknowledgement to their callback.
+		pending map[string]func(error)
+		// err is set once the connection fails; no more messages can be sent.
+		err error
+	}
+}
+
+// send writes a message to the connection. onAck is run once the message is
+// acknowledged or the connection fails. If an error is returned, onAck is
+// never run.
+func (p *pulsarProducer) send(key string, payload []byte, onAck func(error)) error {
+	p.nextID++
+	id := strconv.FormatUint(p.nextID, 10)
+
+	p.mu.Lock()
+	if err := p.mu.err; err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	p.mu.pending[id] = onAck
+	p.mu.Unlock()
+
+	if err := p.conn.WriteJSON(pulsarMessage{Payload: payload, Key: key, Context: id}); err != nil {
+		p.mu.Lock()
+		_, ok := p.mu.pending[id]
+		delete(p.mu.pending, id)
+		p.mu.Unlock()
+		if !ok {
+			// The reader failed the message concurrently and ran onAck, so the
+			// message must be reported through it.
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// readAcks runs the callbacks of the messages acknowledged by the broker
+// until the connection fails or is closed.
+func (p *pulsarProducer) readAcks() {
+	defer close(p.done)
+	for {
+		var ack pulsarAck
+		if err := p.conn.ReadJSON(&ack); err != nil {
+			p.fail(errors.Wrap(err, `reading acknowledgements`))
+			return
+		}
+		p.mu.Lock()
+		onAck, ok := p.mu.pending[ack.Context]
+		delete(p.mu.pending, ack.Context)
+		p.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if ack.Result != `ok` {
+			onAck(errors.Errorf(`%s: %s`, ack.Result, ack.ErrorMsg))
+		} else {
+			onAck(nil /* err */)
+		}
+	}
+}
+
+// fail fails all the messages waiting for an acknowledgement, as well as all
+// the messages sent later.
+func (p *pulsarProducer) fail(err error) {
+	p.mu.Lock()
+	p.mu.err = err
+	pending := p.mu.pending
+	p.mu.pending = nil
+	p.mu.Unlock()
+	for _, onAck := range pending {
+		onAck(err)
+	}
+}
+
+// close closes the connection and waits for the reader to exit.
+func (p *pulsarProducer) close() error {
+	err := p.conn.Close()
+	<-p.done
+	return err
+}
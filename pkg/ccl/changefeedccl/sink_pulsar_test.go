@@ -0,0 +1,332 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// pulsarBrokerMock is an in-process stand-in for the WebSocket and admin
+// APIs of a pulsar broker. Messages sent through its producers are
+// acknowledged immediately and recorded per partition.
+type pulsarBrokerMock struct {
+	*httptest.Server
+	numPartitions int
+	// failKey, if set, makes the sending of messages with that key fail.
+	failKey string
+	// token, if set, is the token clients must authenticate with.
+	token string
+
+	mu struct {
+		syncutil.Mutex
+		// messages maps a partition topic name to the payloads it received,
+		// in order, as `key:payload`.
+		messages map[string][]string
+		conns    []*websocket.Conn
+	}
+}
+
+const pulsarMockNamespace = `/public/default/`
+
+func newPulsarBrokerMock(numPartitions int) *pulsarBrokerMock {
+	b := &pulsarBrokerMock{numPartitions: numPartitions}
+	b.mu.messages = make(map[string][]string)
+	upgrader := websocket.Upgrader{}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if b.token != `` && r.Header.Get(`Authorization`) != `Bearer `+b.token {
+			http.Error(w, `unauthorized`, http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, `/admin/v2/persistent`+pulsarMockNamespace) {
+			_ = json.NewEncoder(w).Encode(map[string]int{`partitions`: b.numPartitions})
+			return
+		}
+		topic := strings.TrimPrefix(r.URL.Path, `/ws/v2/producer/persistent`+pulsarMockNamespace)
+		if topic == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil /* responseHeader */)
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.mu.conns = append(b.mu.conns, conn)
+		b.mu.Unlock()
+		defer conn.Close()
+		for {
+			var msg pulsarMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			ack := pulsarAck{Result: `ok`, Context: msg.Context}
+			if msg.Key != `` && msg.Key == b.failKey {
+				ack = pulsarAck{Result: `send-error:3`, ErrorMsg: `failed to send ` + msg.Key, Context: msg.Context}
+			} else {
+				b.record(topic, msg)
+			}
+			if err := conn.WriteJSON(ack); err != nil {
+				return
+			}
+		}
+	}))
+	return b
+}
+
+func (b *pulsarBrokerMock) partitionTopic(topic string, partition int) string {
+	return fmt.Sprintf(`%s-partition-%d`, topic, partition)
+}
+
+// record routes keyed messages sent to a partitioned topic by the hash of
+// their key, like the broker does.
+func (b *pulsarBrokerMock) record(topic string, msg pulsarMessage) {
+	if msg.Key != `` && !strings.Contains(topic, `-partition-`) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(msg.Key))
+		topic = b.partitionTopic(topic, int(h.Sum32()%uint32(b.numPartitions)))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.messages[topic] = append(b.mu.messages[topic], msg.Key+`:`+string(msg.Payload))
+}
+
+func (b *pulsarBrokerMock) messages(partitionTopic string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.mu.messages[partitionTopic]...)
+}
+
+// closeProducers closes the connections of all the producers.
+func (b *pulsarBrokerMock) closeProducers() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, conn := range b.mu.conns {
+		_ = conn.Close()
+	}
+}
+
+func (b *pulsarBrokerMock) sinkURI(params string) string {
+	return `pulsar://` + strings.TrimPrefix(b.URL, `http://`) + `?` + params
+}
+
+func makeTestPulsarSink(
+	t testing.TB, uri string, jsonConfig string, targetNames ...string,
+) (*pulsarSink, error) {
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	s, err := makePulsarSink(context.Background(), sinkURL{URL: u}, makeChangefeedTargets(targetNames...),
+		changefeedbase.SinkSpecificJSONConfig(jsonConfig), nilMetricsRecorderBuilder)
+	if err != nil {
+		return nil, err
+	}
+	return s.(*pulsarSink), nil
+}
+
+func TestPulsarSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	broker := newPulsarBrokerMock(3)
+	defer broker.Close()
+	broker.token = `secret`
+
+	sink, err := makeTestPulsarSink(t, broker.sinkURI(`auth_token=secret`), ``, `t`, `u`)
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	// No inflight.
+	require.NoError(t, sink.Flush(ctx))
+
+	var pool testAllocPool
+	keys := []string{`a`, `b`, `c`, `d`}
+	for i := 0; i < 3; i++ {
+		for _, key := range keys {
+			require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(key),
+				[]byte(fmt.Sprintf(`v%d`, i)), zeroTS, zeroTS, pool.alloc()))
+		}
+	}
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+
+	// Every key landed on a single partition, in the order it was emitted.
+	perKey := make(map[string][]string)
+	for i := 0; i < broker.numPartitions; i++ {
+		for _, m := range broker.messages(broker.partitionTopic(`t`, i)) {
+			key := m[:1]
+			perKey[key] = append(perKey[key], fmt.Sprintf(`%d:%s`, i, m))
+		}
+	}
+	for _, key := range keys {
+		require.Len(t, perKey[key], 3)
+		partition := perKey[key][0][:1]
+		for i, m := range perKey[key] {
+			require.Equal(t, fmt.Sprintf(`%s:%s:v%d`, partition, key, i), m)
+		}
+	}
+
+	// Resolved timestamps are sent to every partition of every topic.
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, zeroTS))
+	require.NoError(t, sink.Flush(ctx))
+	for _, topic := range []string{`t`, `u`} {
+		for i := 0; i < broker.numPartitions; i++ {
+			messages := broker.messages(broker.partitionTopic(topic, i))
+			require.NotEmpty(t, messages)
+			require.Equal(t, `:`+zeroTS.String(), messages[len(messages)-1])
+		}
+	}
+	require.ElementsMatch(t, []string{`t`, `u`}, sink.Topics())
+}
+
+func TestPulsarSinkNonPartitioned(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	broker := newPulsarBrokerMock(0)
+	defer broker.Close()
+
+	sink, err := makeTestPulsarSink(t, broker.sinkURI(`topic_prefix=foo_`), ``, `t`)
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, zeroTS))
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, []string{`:` + zeroTS.String()}, broker.messages(`foo_t`))
+}
+
+func TestPulsarSinkErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	broker := newPulsarBrokerMock(1)
+	defer broker.Close()
+	broker.failKey = `bad`
+	broker.token = `secret`
+
+	t.Run("unauthorized", func(t *testing.T) {
+		sink, err := makeTestPulsarSink(t, broker.sinkURI(`auth_token=wrong`), ``, `t`)
+		require.NoError(t, err)
+		require.Regexp(t, `connecting to pulsar.*401 Unauthorized`, sink.Dial())
+		require.NoError(t, sink.Close())
+	})
+
+	t.Run("send", func(t *testing.T) {
+		sink, err := makeTestPulsarSink(t, broker.sinkURI(`auth_token=secret`), ``, `t`)
+		require.NoError(t, err)
+		require.NoError(t, sink.Dial())
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		var pool testAllocPool
+		for _, key := range []string{`good`, `bad`, `good`} {
+			require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(key), []byte(`v`),
+				zeroTS, zeroTS, pool.alloc()))
+		}
+		require.Regexp(t, `key=bad.*failed to send bad`, sink.Flush(ctx))
+		// The error is only reported once.
+		require.NoError(t, sink.Flush(ctx))
+		require.EqualValues(t, 0, pool.used())
+	})
+
+	t.Run("connection closed", func(t *testing.T) {
+		sink, err := makeTestPulsarSink(t, broker.sinkURI(`auth_token=secret`), ``, `t`)
+		require.NoError(t, err)
+		require.NoError(t, sink.Dial())
+		defer func() { _ = sink.Close() }()
+
+		broker.closeProducers()
+		var pool testAllocPool
+		// Messages sent once the connection is known to be broken fail
+		// right away; those sent before fail at the next Flush.
+		testutils.SucceedsSoon(t, func() error {
+			if err := sink.EmitRow(ctx, topic(`t`), []byte(`k`), []byte(`v`),
+				zeroTS, zeroTS, pool.alloc()); err == nil {
+				return errors.New(`expected the connection to fail`)
+			}
+			return nil
+		})
+		require.Error(t, sink.Flush(ctx))
+	})
+}
+
+func TestPulsarSinkOptionParsing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t.Run("url", func(t *testing.T) {
+		s, err := makeTestPulsarSink(t, `pulsar://localhost:8080?topic_prefix=foo_&auth_token=secret`, ``, `t`)
+		require.NoError(t, err)
+		require.Equal(t, `ws://localhost:8080/ws/v2/producer/persistent/public/default`, s.producerURL.String())
+		require.Equal(t, `http://localhost:8080/admin/v2/persistent/public/default`, s.adminURL.String())
+		require.Equal(t, []string{`foo_t`}, s.Topics())
+		require.Equal(t, `Bearer secret`, s.header.Get(`Authorization`))
+
+		s, err = makeTestPulsarSink(t,
+			`pulsar://localhost:8443/acme/feeds?tls_enabled=true&insecure_tls_skip_verify=true`, ``, `t`)
+		require.NoError(t, err)
+		require.Equal(t, `wss://localhost:8443/ws/v2/producer/persistent/acme/feeds`, s.producerURL.String())
+		require.Equal(t, `https://localhost:8443/admin/v2/persistent/acme/feeds`, s.adminURL.String())
+		require.True(t, s.dialer.TLSClientConfig.InsecureSkipVerify)
+	})
+
+	t.Run("url errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			uri string
+			err string
+		}{
+			{`pulsar://`, `requires a broker address`},
+			{`pulsar://localhost:8080/public`, `path must be of the form /<tenant>/<namespace>`},
+			{`pulsar://localhost:8080?foo=bar`, `unknown pulsar sink query parameters: foo`},
+			{`pulsar://localhost:8080?tls_enabled=maybe`, `param tls_enabled must be a bool`},
+			{`pulsar://localhost:8080?ca_cert=Zm9v`, `ca_cert requires tls_enabled=true`},
+			{`pulsar://localhost:8080?tls_enabled=true&client_cert=Zm9v`, `client_cert requires client_key to be set`},
+			{`pulsar://localhost:8080?tls_enabled=true&client_cert=Zm9v&client_key=Zm9v`, `invalid client certificate`},
+		} {
+			_, err := makeTestPulsarSink(t, tc.uri, ``, `t`)
+			require.Regexp(t, tc.err, err, tc.uri)
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		s, err := makeTestPulsarSink(t, `pulsar://localhost:8080`,
+			`{"Batching": {"Messages": 10, "Frequency": "5ms"}}`, `t`)
+		require.NoError(t, err)
+		require.Equal(t, `batchingEnabled=true&batchingMaxMessages=10&batchingMaxPublishDelay=5`,
+			s.producerParams.Encode())
+
+		s, err = makeTestPulsarSink(t, `pulsar://localhost:8080`, `{"Batching": {"Disabled": true}}`, `t`)
+		require.NoError(t, err)
+		require.Equal(t, `batchingEnabled=false`, s.producerParams.Encode())
+
+		_, err = makeTestPulsarSink(t, `pulsar://localhost:8080`, `{"Batching": {"Frequency": "soon"}}`, `t`)
+		require.Regexp(t, `failed to parse pulsar config`, err)
+		_, err = makeTestPulsarSink(t, `pulsar://localhost:8080`, `{"Batching": {"Frequency": "-1s"}}`, `t`)
+		require.Regexp(t, `Batching.Frequency must be non-negative`, err)
+	})
+}
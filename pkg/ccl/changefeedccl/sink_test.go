@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"sync"
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -675,3 +677,151 @@ func TestKafkaSinkTracksMemory(t *testing.T) {
 	require.EqualValues(t, 0, p.outstanding())
 	require.EqualValues(t, 0, pool.used())
 }
+
+func TestKafkaSinkTransactional(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	produceResponse := sarama.NewMockProduceResponse(t).SetVersion(3)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()).
+			SetLeader(`t`, 0, broker.BrokerID()).
+			SetLeader(`t`, 1, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockWrapper(&sarama.FindCoordinatorResponse{
+			Version:     1,
+			Coordinator: sarama.NewBroker(broker.Addr()),
+		}),
+		"InitProducerIDRequest": sarama.NewMockWrapper(&sarama.InitProducerIDResponse{
+			ProducerID:    7,
+			ProducerEpoch: 1,
+		}),
+		"AddPartitionsToTxnRequest": sarama.NewMockWrapper(&sarama.AddPartitionsToTxnResponse{}),
+		"ProduceRequest":            produceResponse,
+		"EndTxnRequest":             sarama.NewMockWrapper(&sarama.EndTxnResponse{}),
+	})
+
+	u, err := url.Parse(fmt.Sprintf(`kafka://%s?kafka_transactional=true`, broker.Addr()))
+	require.NoError(t, err)
+	s, err := makeKafkaSink(ctx, sinkURL{URL: u}, makeChangefeedTargets(`t`), ``,
+		kafkaTransactionalID(1, `2`), nilMetricsRecorderBuilder)
+	require.NoError(t, err)
+	require.NoError(t, s.Dial())
+	defer func() { require.NoError(t, s.Close()) }()
+
+	// requests returns the transactional requests received by the broker since
+	// the last call.
+	var seen int
+	requests := func() (res []string) {
+		history := broker.History()
+		for _, rr := range history[seen:] {
+			switch req := rr.Request.(type) {
+			case *sarama.InitProducerIDRequest:
+				require.Equal(t, `crdb-changefeed-1-2`, *req.TransactionalID)
+				res = append(res, `init`)
+			case *sarama.AddPartitionsToTxnRequest:
+				require.EqualValues(t, 7, req.ProducerID)
+				res = append(res, `add partitions`)
+			case *sarama.ProduceRequest:
+				require.Equal(t, `crdb-changefeed-1-2`, *req.TransactionalID)
+				res = append(res, `produce`)
+			case *sarama.EndTxnRequest:
+				res = append(res, fmt.Sprintf(`end commit=%t`, req.TransactionResult))
+			}
+		}
+		seen = len(history)
+		return res
+	}
+
+	// Nothing is written until the sink is flushed, and flushing without any
+	// messages doesn't start a transaction.
+	require.NoError(t, s.Flush(ctx))
+	var pool testAllocPool
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.EmitRow(ctx, topic(`t`), []byte(strconv.Itoa(i)), []byte(`v`),
+			zeroTS, zeroTS, pool.alloc()))
+	}
+	require.Empty(t, requests())
+	require.EqualValues(t, 5, pool.used())
+
+	require.NoError(t, s.Flush(ctx))
+	require.Equal(t, []string{`init`, `add partitions`, `produce`, `end commit=true`}, requests())
+	require.EqualValues(t, 0, pool.used())
+
+	// Resolved timestamps are committed right away.
+	require.NoError(t, s.EmitResolvedTimestamp(ctx, testEncoder{}, zeroTS))
+	require.Equal(t, []string{`add partitions`, `produce`, `end commit=true`}, requests())
+
+	// A failed write aborts the transaction, and the producer is initialized
+	// again by the next one.
+	produceResponse.SetError(`t`, 0, sarama.ErrNotEnoughReplicas)
+	produceResponse.SetError(`t`, 1, sarama.ErrNotEnoughReplicas)
+	require.NoError(t, s.EmitRow(ctx, topic(`t`), []byte(`k`), []byte(`v`), zeroTS, zeroTS, pool.alloc()))
+	require.Regexp(t, `kafka transaction aborted`, s.Flush(ctx))
+	require.Equal(t, []string{`add partitions`, `produce`, `end commit=false`}, requests())
+	require.EqualValues(t, 0, pool.used())
+
+	produceResponse.SetError(`t`, 0, sarama.ErrNoError)
+	produceResponse.SetError(`t`, 1, sarama.ErrNoError)
+	require.NoError(t, s.EmitRow(ctx, topic(`t`), []byte(`k`), []byte(`v`), zeroTS, zeroTS, pool.alloc()))
+	require.NoError(t, s.Flush(ctx))
+	require.Equal(t, []string{`init`, `add partitions`, `produce`, `end commit=true`}, requests())
+
+	// The buffered messages are committed without waiting for a flush once
+	// they exceed the limit.
+	s.(*kafkaSink).txnMaxBufferedBytes = 3
+	require.NoError(t, s.EmitRow(ctx, topic(`t`), []byte(`k`), []byte(`v`), zeroTS, zeroTS, pool.alloc()))
+	require.Empty(t, requests())
+	require.NoError(t, s.EmitRow(ctx, topic(`t`), []byte(`k`), []byte(`v`), zeroTS, zeroTS, pool.alloc()))
+	require.Equal(t, []string{`add partitions`, `produce`, `end commit=true`}, requests())
+	require.EqualValues(t, 0, pool.used())
+}
+
+func TestSpansSinkID(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	a := roachpb.Span{Key: roachpb.Key(`a`), EndKey: roachpb.Key(`b`)}
+	b := roachpb.Span{Key: roachpb.Key(`b`), EndKey: roachpb.Key(`c`)}
+	ab := roachpb.Span{Key: roachpb.Key(`a`), EndKey: roachpb.Key(`bb`)}
+
+	// The ID doesn't depend on the order of the spans, but on their keys.
+	require.Equal(t, spansSinkID([]roachpb.Span{a, b}), spansSinkID([]roachpb.Span{b, a}))
+	require.NotEqual(t, spansSinkID([]roachpb.Span{a, b}), spansSinkID([]roachpb.Span{a}))
+	require.NotEqual(t, spansSinkID([]roachpb.Span{a}), spansSinkID([]roachpb.Span{ab}))
+	require.Len(t, spansSinkID([]roachpb.Span{a, b}), 16)
+}
+
+func TestKafkaSinkTransactionalOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		uri     string
+		jsonStr changefeedbase.SinkSpecificJSONConfig
+		err     string
+	}{
+		{uri: `kafka://nope?kafka_transactional=true`},
+		{uri: `kafka://nope?kafka_transactional=maybe`, err: `param kafka_transactional must be a bool`},
+		{
+			uri:     `kafka://nope?kafka_transactional=true`,
+			jsonStr: `{"Version": "0.10.2.0"}`,
+			err:     `kafka_transactional requires kafka version 0.11.0.0 or later`,
+		},
+	} {
+		u, err := url.Parse(tc.uri)
+		require.NoError(t, err)
+		s, err := makeKafkaSink(ctx, sinkURL{URL: u}, makeChangefeedTargets(`t`), tc.jsonStr,
+			`txn`, nilMetricsRecorderBuilder)
+		if tc.err != `` {
+			require.Regexp(t, tc.err, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, `txn`, s.(*kafkaSink).transactionalID)
+	}
+}
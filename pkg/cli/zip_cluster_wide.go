@@ -75,6 +75,7 @@ func makeClusterWideZipRequests(
 // Tables containing cluster-wide info that are collected using SQL
 // into a debug zip.
 var debugZipTablesPerCluster = []string{
+	"crdb_internal.cluster_advisory_locks",
 	"crdb_internal.cluster_contention_events",
 	"crdb_internal.cluster_distsql_flows",
 	"crdb_internal.cluster_database_privileges",
@@ -78,6 +78,7 @@ table_name NOT IN (
 	'forward_dependencies',
 	'index_columns',
 	'lost_descriptors_with_data',
+	'materialized_views',
 	'table_columns',
 	'table_row_statistics',
 	'ranges',
@@ -386,6 +386,70 @@ const (
 	// AlterSystemSQLInstancesAddLocality adds a locality column to the
 	// system.sql_instances table.
 	AlterSystemSQLInstancesAddLocality
+	// RowLevelTriggers enables the creation of row-level triggers, which are
+	// stored in table descriptors.
+	RowLevelTriggers
+	// DeferrableConstraints enables foreign key and unique constraints whose checks
+	// can be deferred to the end of the transaction.
+	DeferrableConstraints
+	// Publications enables CREATE PUBLICATION and streaming changes to publication
+	// subscribers with the logical replication protocol.
+	Publications
+	// ChangefeedParquet enables the parquet format for changefeeds emitting to
+	// cloud storage sinks.
+	ChangefeedParquet
+	// ChangefeedKafkaTransactional enables the kafka_transactional parameter of
+	// kafka sinks, which makes changefeeds write to kafka in transactions.
+	ChangefeedKafkaTransactional
+	// ChangefeedProtobuf enables the protobuf format of changefeeds.
+	ChangefeedProtobuf
+	// ExclusionConstraints enables EXCLUDE constraints, which are stored in
+	// table descriptors.
+	ExclusionConstraints
+	// TSearchTypes enables the tsvector and tsquery column types.
+	TSearchTypes
+	// RangeTypes enables the range column types.
+	RangeTypes
+	// JsonpathType enables the jsonpath column type.
+	JsonpathType
+	// PGVectorType enables the vector column type and ivfflat indexes.
+	PGVectorType
+	// NotificationsTable adds system.notifications, which carries the
+	// notifications sent with NOTIFY to the sessions that LISTEN on them.
+	NotificationsTable
+	// AdvisoryLocksTable adds system.advisory_locks, which stores the advisory
+	// locks acquired with the pg_advisory_* builtins.
+	AdvisoryLocksTable
+	// RowLevelSecurity enables row-level security policies, which are stored in
+	// table descriptors.
+	RowLevelSecurity
+	// ColumnPrivileges enables privileges granted on individual columns of tables,
+	// which are stored in table descriptors.
+	ColumnPrivileges
+	// ReadCommittedIsolation enables the READ COMMITTED isolation level, which
+	// requires all nodes to understand the isolation level of transactions.
+	ReadCommittedIsolation
+	// UserDefinedAggregates enables CREATE AGGREGATE and the storage of
+	// user-defined aggregates on database descriptors.
+	UserDefinedAggregates
+	// PLpgSQLFunctions adds user-defined functions written in PL/pgSQL, which
+	// are stored in the database descriptor.
+	PLpgSQLFunctions
+	// Procedures adds stored procedures, which are invoked with CALL and can
+	// commit or roll back their transaction.
+	Procedures
+	// ForeignTables adds foreign tables, whose rows are read from files in
+	// external storage.
+	ForeignTables
+	// IncrementalMaterializedViews adds materialized views which are maintained
+	// incrementally by the transactions which modify the tables they depend on.
+	IncrementalMaterializedViews
+	// ChangefeedPulsarSink enables changefeeds emitting to Apache Pulsar.
+	ChangefeedPulsarSink
+	// ReplicationSlotsTable adds system.replication_slots, which stores the
+	// logical replication slots and the positions confirmed by their
+	// subscribers.
+	ReplicationSlotsTable
 
 	// *************************************************
 	// Step (1): Add new versions here.
@@ -687,6 +751,98 @@ var versionsSingleton = keyedVersions{
 		Key:     AlterSystemSQLInstancesAddLocality,
 		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 26},
 	},
+	{
+		Key:     RowLevelTriggers,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 28},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 30},
+	},
+	{
+		Key:     Publications,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 32},
+	},
+	{
+		Key:     ChangefeedParquet,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 34},
+	},
+	{
+		Key:     ChangefeedKafkaTransactional,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 36},
+	},
+	{
+		Key:     ChangefeedProtobuf,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 38},
+	},
+	{
+		Key:     ExclusionConstraints,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 40},
+	},
+	{
+		Key:     TSearchTypes,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 42},
+	},
+	{
+		Key:     RangeTypes,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 44},
+	},
+	{
+		Key:     JsonpathType,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 46},
+	},
+	{
+		Key:     PGVectorType,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 48},
+	},
+	{
+		Key:     NotificationsTable,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 50},
+	},
+	{
+		Key:     AdvisoryLocksTable,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 52},
+	},
+	{
+		Key:     RowLevelSecurity,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 54},
+	},
+	{
+		Key:     ColumnPrivileges,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 56},
+	},
+	{
+		Key:     ReadCommittedIsolation,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 58},
+	},
+	{
+		Key:     UserDefinedAggregates,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 60},
+	},
+	{
+		Key:     PLpgSQLFunctions,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 62},
+	},
+	{
+		Key:     Procedures,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 64},
+	},
+	{
+		Key:     ForeignTables,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 66},
+	},
+	{
+		Key:     IncrementalMaterializedViews,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 68},
+	},
+	{
+		Key:     ChangefeedPulsarSink,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 70},
+	},
+	{
+		Key:     ReplicationSlotsTable,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 72},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
@@ -19,6 +19,7 @@ PROTOBUF_SRCS = [
   "//pkg/jobs/jobspb:jobspb_go_proto",
   "//pkg/kv/kvnemesis:kvnemesis_go_proto",
   "//pkg/kv/kvserver/closedts/ctpb:ctpb_go_proto",
+  "//pkg/kv/kvserver/concurrency/isolation:isolation_go_proto",
   "//pkg/kv/kvserver/concurrency/lock:lock_go_proto",
   "//pkg/kv/kvserver/concurrency/poison:poison_go_proto",
   "//pkg/kv/kvserver/kvserverpb:kvserverpb_go_proto",
@@ -16,6 +16,7 @@ import (
 	"runtime/debug"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -815,6 +816,13 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	errTxnID := pErr.GetTxn().ID
 	newTxn := roachpb.PrepareTransactionForRetry(ctx, pErr, tc.mu.userPriority, tc.clock)
 
+	// Transactions which establish a new read snapshot for each statement don't
+	// need to restart in their entirety. Instead, only the statement which hit
+	// the error is retried at a new read timestamp, so we don't bump the epoch.
+	if errTxnID == newTxn.ID && tc.mu.txn.IsoLevel.PerStatementReadSnapshot() {
+		return tc.handleRetryableStatementErrLocked(ctx, pErr, &newTxn)
+	}
+
 	// We'll pass a TransactionRetryWithProtoRefreshError up to the next layer.
 	retErr := roachpb.NewTransactionRetryWithProtoRefreshError(
 		pErr.String(),
@@ -852,6 +860,36 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	return retErr
 }
 
+// handleRetryableStatementErrLocked is like handleRetryableErrLocked, but is
+// used for transactions which establish a new read snapshot for each statement.
+// Instead of preparing the transaction for a new epoch, the transaction's read
+// and write timestamps are forwarded to the timestamp at which the statement
+// that hit the error should be retried. The transaction retains its epoch, and
+// with it, all the writes performed by previous statements. The caller is
+// expected to roll back to a savepoint established before the statement
+// started, which discards the writes performed by the statement itself (see
+// kv.Txn.PrepareForStatementRetry).
+func (tc *TxnCoordSender) handleRetryableStatementErrLocked(
+	ctx context.Context, pErr *roachpb.Error, newTxn *roachpb.Transaction,
+) *roachpb.TransactionRetryWithProtoRefreshError {
+	log.VEventf(ctx, 2, "retrying statement at timestamp %s on retryable error: %s",
+		newTxn.WriteTimestamp, pErr)
+	tc.mu.txn.WriteTimestamp.Forward(newTxn.WriteTimestamp)
+	tc.mu.txn.ReadTimestamp.Forward(tc.mu.txn.WriteTimestamp)
+	tc.mu.txn.UpgradePriority(newTxn.Priority)
+	tc.mu.txn.WriteTooOld = false
+
+	retErr := roachpb.NewTransactionRetryWithProtoRefreshError(
+		pErr.String(), tc.mu.txn.ID, tc.mu.txn)
+	retErr.StatementRetry = true
+
+	// Move to a retryable error state, where all Send() calls fail until the
+	// state is cleared.
+	tc.mu.txnState = txnRetryableError
+	tc.mu.storedRetryableErr = retErr
+	return retErr
+}
+
 // updateStateLocked updates the transaction state in both the success and error
 // cases. It also updates retryable errors with the updated transaction for use
 // by client restarts.
@@ -1001,6 +1039,24 @@ func (tc *TxnCoordSender) SetUserPriority(pri roachpb.UserPriority) error {
 	return nil
 }
 
+// SetIsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) SetIsoLevel(isoLevel isolation.Level) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.active && isoLevel != tc.mu.txn.IsoLevel {
+		return errors.New("cannot change the isolation level of a running transaction")
+	}
+	tc.mu.txn.IsoLevel = isoLevel
+	return nil
+}
+
+// IsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) IsoLevel() isolation.Level {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.txn.IsoLevel
+}
+
 // SetDebugName is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) SetDebugName(name string) {
 	tc.mu.Lock()
@@ -1115,6 +1171,11 @@ func (tc *TxnCoordSender) IsSerializablePushAndRefreshNotPossible() bool {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
 
+	// Transactions which tolerate write skew can commit at a pushed timestamp
+	// without refreshing their reads.
+	if tc.mu.txn.IsoLevel.ToleratesWriteSkew() {
+		return false
+	}
 	isTxnPushed := tc.mu.txn.WriteTimestamp != tc.mu.txn.ReadTimestamp
 	refreshAttemptNotPossible := tc.interceptorAlloc.txnSpanRefresher.refreshInvalid ||
 		tc.mu.txn.CommitTimestampFixed
@@ -1288,6 +1349,34 @@ func (tc *TxnCoordSender) Step(ctx context.Context) error {
 	return tc.interceptorAlloc.txnSeqNumAllocator.stepLocked(ctx)
 }
 
+// StepReadTimestamp is part of the TxnSender interface.
+func (tc *TxnCoordSender) StepReadTimestamp(ctx context.Context) error {
+	if tc.typ != kv.RootTxn {
+		return errors.AssertionFailedf("cannot step the read timestamp of a non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if err := tc.assertNotFinalized(); err != nil {
+		return err
+	}
+	if !tc.mu.txn.IsoLevel.PerStatementReadSnapshot() || tc.mu.txn.CommitTimestampFixed {
+		return nil
+	}
+
+	// Establish a new read snapshot, which observes all the writes that were
+	// committed before the statement started. The uncertainty interval of the
+	// transaction is reset along with it, so that the statement does not
+	// observe values in the uncertainty interval of previous statements.
+	now := tc.clock.Now()
+	tc.mu.txn.WriteTimestamp.Forward(now)
+	tc.mu.txn.ReadTimestamp.Forward(tc.mu.txn.WriteTimestamp)
+	tc.mu.txn.GlobalUncertaintyLimit = now.Add(tc.clock.MaxOffset().Nanoseconds(), 0)
+	tc.mu.txn.ResetObservedTimestamps()
+	return nil
+}
+
 // SetReadSeqNum is part of the TxnSender interface.
 func (tc *TxnCoordSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
 	tc.mu.Lock()
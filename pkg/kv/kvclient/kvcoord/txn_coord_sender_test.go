@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
@@ -2958,3 +2959,136 @@ func TestTxnCoordSenderSetFixedTimestamp(t *testing.T) {
 		})
 	}
 }
+
+// makeTestDBWithSenderFn returns a DB whose transactions send their batches to
+// the provided function, along with the manual time source of its clock.
+func makeTestDBWithSenderFn(
+	stopper *stop.Stopper, senderFn kv.SenderFunc,
+) (*kv.DB, *timeutil.ManualTime, *hlc.Clock) {
+	manual := timeutil.NewManualTime(timeutil.Unix(0, 123))
+	clock := hlc.NewClock(manual, 20*time.Nanosecond /* maxOffset */)
+	ambient := log.MakeTestingAmbientCtxWithNewTracer()
+	tsf := kvcoord.NewTxnCoordSenderFactory(
+		kvcoord.TxnCoordSenderFactoryConfig{
+			AmbientCtx: ambient,
+			Clock:      clock,
+			Stopper:    stopper,
+		},
+		senderFn,
+	)
+	return kv.NewDB(ambient, tsf, clock, stopper), manual, clock
+}
+
+// TestTxnCoordSenderStepReadTimestamp verifies that StepReadTimestamp
+// establishes a new read snapshot at the current time for transactions which
+// use a read snapshot per statement, and that it is a no-op otherwise.
+func TestTxnCoordSenderStepReadTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	testutils.RunTrueAndFalse(t, "readCommitted", func(t *testing.T, readCommitted bool) {
+		stopper := stop.NewStopper()
+		defer stopper.Stop(ctx)
+
+		var senderFn kv.SenderFunc = func(
+			_ context.Context, ba roachpb.BatchRequest,
+		) (*roachpb.BatchResponse, *roachpb.Error) {
+			br := ba.CreateReply()
+			br.Txn = ba.Txn.Clone()
+			return br, nil
+		}
+		db, manual, clock := makeTestDBWithSenderFn(stopper, senderFn)
+
+		txn := kv.NewTxn(ctx, db, 0 /* gatewayNodeID */)
+		if readCommitted {
+			require.NoError(t, txn.SetIsoLevel(isolation.ReadCommitted))
+		}
+		require.NoError(t, txn.Put(ctx, "a", "value"))
+		before := txn.TestingCloneTxn()
+
+		manual.Advance(100 * time.Nanosecond)
+		require.NoError(t, txn.StepReadTimestamp(ctx))
+		after := txn.TestingCloneTxn()
+
+		require.Equal(t, before.Epoch, after.Epoch)
+		if !readCommitted {
+			require.Equal(t, before.ReadTimestamp, after.ReadTimestamp)
+			require.Equal(t, before.WriteTimestamp, after.WriteTimestamp)
+			require.Equal(t, before.GlobalUncertaintyLimit, after.GlobalUncertaintyLimit)
+			return
+		}
+		require.True(t, before.ReadTimestamp.Less(after.ReadTimestamp))
+		require.Equal(t, manual.Now().UnixNano(), after.ReadTimestamp.WallTime)
+		require.Equal(t, after.ReadTimestamp, after.WriteTimestamp)
+		require.Equal(t,
+			after.ReadTimestamp.Add(clock.MaxOffset().Nanoseconds(), 0), after.GlobalUncertaintyLimit)
+	})
+}
+
+// TestTxnCoordSenderStatementRetry verifies that a retryable error hit by a
+// transaction which uses a read snapshot per statement only requires the
+// statement to be retried: the transaction keeps its epoch and is moved to
+// the timestamp of the error, and it can continue after
+// PrepareForStatementRetry. Serializable transactions are restarted instead.
+func TestTxnCoordSenderStatementRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	testutils.RunTrueAndFalse(t, "readCommitted", func(t *testing.T, readCommitted bool) {
+		stopper := stop.NewStopper()
+		defer stopper.Stop(ctx)
+
+		var retryTS hlc.Timestamp
+		var injected bool
+		var senderFn kv.SenderFunc = func(
+			_ context.Context, ba roachpb.BatchRequest,
+		) (*roachpb.BatchResponse, *roachpb.Error) {
+			if put, ok := ba.GetArg(roachpb.Put); ok && !injected &&
+				put.Header().Key.Equal(roachpb.Key("b")) {
+				injected = true
+				txn := ba.Txn.Clone()
+				txn.WriteTimestamp = retryTS
+				return nil, roachpb.NewErrorWithTxn(&roachpb.TransactionRetryError{}, txn)
+			}
+			br := ba.CreateReply()
+			br.Txn = ba.Txn.Clone()
+			return br, nil
+		}
+		db, _, clock := makeTestDBWithSenderFn(stopper, senderFn)
+
+		txn := kv.NewTxn(ctx, db, 0 /* gatewayNodeID */)
+		if readCommitted {
+			require.NoError(t, txn.SetIsoLevel(isolation.ReadCommitted))
+		}
+		require.NoError(t, txn.Put(ctx, "a", "value"))
+		orig := txn.TestingCloneTxn()
+		retryTS = clock.Now().Add(10, 0)
+
+		err := txn.Put(ctx, "b", "value")
+		var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+		require.True(t, errors.As(err, &retryErr), "unexpected error: %v", err)
+		require.Equal(t, readCommitted, retryErr.StatementRetry)
+		require.Equal(t, orig.ID, retryErr.TxnID)
+
+		if !readCommitted {
+			require.Equal(t, orig.Epoch+1, retryErr.Transaction.Epoch)
+			return
+		}
+		require.Equal(t, orig.Epoch, retryErr.Transaction.Epoch)
+		require.Equal(t, retryTS, retryErr.Transaction.WriteTimestamp)
+		require.Equal(t, retryTS, retryErr.Transaction.ReadTimestamp)
+
+		// The transaction is unusable until the error is cleared.
+		require.True(t, errors.As(txn.Put(ctx, "b", "value"), &retryErr))
+		require.NoError(t, txn.PrepareForStatementRetry(ctx))
+
+		// The statement can be retried in the same epoch, at the new timestamp.
+		require.NoError(t, txn.Put(ctx, "b", "value"))
+		proto := txn.TestingCloneTxn()
+		require.Equal(t, orig.ID, proto.ID)
+		require.Equal(t, orig.Epoch, proto.Epoch)
+		require.Equal(t, retryTS, proto.ReadTimestamp)
+	})
+}
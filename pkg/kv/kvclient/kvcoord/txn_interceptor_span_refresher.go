@@ -166,7 +166,9 @@ func (sr *txnSpanRefresher) SendLocked(
 	if err := sr.assertRefreshSpansAtInvalidTimestamp(br.Txn.ReadTimestamp); err != nil {
 		return nil, roachpb.NewError(err)
 	}
-	if !sr.refreshInvalid {
+	// Transactions which tolerate write skew never refresh their reads, so
+	// there is no need to track them.
+	if !sr.refreshInvalid && !br.Txn.IsoLevel.ToleratesWriteSkew() {
 		if err := sr.appendRefreshSpans(ctx, ba, br); err != nil {
 			return nil, roachpb.NewError(err)
 		}
@@ -426,6 +428,12 @@ func (sr *txnSpanRefresher) maybeRefreshPreemptivelyLocked(
 		return ba, nil
 	}
 
+	// If the transaction tolerates write skew, it can commit at its pushed
+	// timestamp without a refresh.
+	if ba.Txn.IsoLevel.ToleratesWriteSkew() {
+		return ba, nil
+	}
+
 	// If true, tryRefreshTxnSpans will trivially succeed.
 	refreshFree := ba.CanForwardReadTimestamp
 
@@ -575,8 +583,13 @@ func (sr *txnSpanRefresher) appendRefreshSpans(
 // to this point. This requires that the transaction's timestamp has not leaked.
 // It also requires that the txnSpanRefresher has been configured to allow
 // auto-retries.
+//
+// Transactions which tolerate write skew never forward their read timestamp in
+// the middle of a SQL statement, as the statement must read from a single
+// snapshot. Instead, the statement is retried on a new snapshot (see
+// TxnCoordSender.handleRetryableErrLocked).
 func (sr *txnSpanRefresher) canForwardReadTimestamp(txn *roachpb.Transaction) bool {
-	return sr.canAutoRetry && !txn.CommitTimestampFixed
+	return sr.canAutoRetry && !txn.CommitTimestampFixed && !txn.IsoLevel.ToleratesWriteSkew()
 }
 
 // canForwardReadTimestampWithoutRefresh returns whether the transaction can
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/abortspan"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -54,6 +55,8 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 	headerTxn := txn.Clone()
 	pushedHeaderTxn := txn.Clone()
 	pushedHeaderTxn.WriteTimestamp.Forward(ts2)
+	pushedReadCommittedHeaderTxn := pushedHeaderTxn.Clone()
+	pushedReadCommittedHeaderTxn.IsoLevel = isolation.ReadCommitted
 	refreshedHeaderTxn := txn.Clone()
 	refreshedHeaderTxn.WriteTimestamp.Forward(ts2)
 	refreshedHeaderTxn.ReadTimestamp.Forward(ts2)
@@ -258,6 +261,25 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 			// Expected result.
 			expError: "TransactionRetryError: retry txn (RETRY_SERIALIZABLE)",
 		},
+		{
+			// The transaction's commit timestamp was increased during its
+			// lifetime, but it hasn't refreshed up to its new commit timestamp.
+			// The transaction tolerates write skew, so the commit will succeed.
+			name: "record missing, can create, try commit at pushed timestamp with read committed isolation",
+			// Replica state.
+			existingTxn:  nil,
+			canCreateTxn: func() (bool, hlc.Timestamp) { return true, hlc.Timestamp{} },
+			// Request state.
+			headerTxn: pushedReadCommittedHeaderTxn,
+			commit:    true,
+			// Expected result.
+			expTxn: func() *roachpb.TransactionRecord {
+				record := *committedRecord
+				record.IsoLevel = isolation.ReadCommitted
+				record.WriteTimestamp.Forward(ts2)
+				return &record
+			}(),
+		},
 		{
 			// The transaction's commit timestamp was increased during its
 			// lifetime and it has refreshed up to this timestamp. The stage
@@ -106,7 +106,15 @@ func QueryIntent(
 				// in the batch header.
 				cmpTS.Forward(h.Txn.WriteTimestamp)
 			}
-			if cmpTS.Less(intent.Txn.WriteTimestamp) {
+			if cmpTS.Less(intent.Txn.WriteTimestamp) && ownTxn && h.Txn.IsoLevel.ToleratesWriteSkew() {
+				// The intent matched but was pushed to a later timestamp. The
+				// transaction does not need to refresh its reads to commit at a later
+				// timestamp, so the intent is still valid. Update the response
+				// transaction with the timestamp of the intent.
+				log.VEventf(ctx, 2, "found pushed intent")
+				reply.Txn = h.Txn.Clone()
+				reply.Txn.WriteTimestamp.Forward(intent.Txn.WriteTimestamp)
+			} else if cmpTS.Less(intent.Txn.WriteTimestamp) {
 				// The intent matched but was pushed to a later timestamp. Consider a
 				// pushed intent a missing intent.
 				curIntentPushed = true
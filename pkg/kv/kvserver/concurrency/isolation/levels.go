@@ -0,0 +1,39 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package isolation provides type definitions for isolation level concepts used
+// by concurrency control in the key-value layer.
+package isolation
+
+// WeakerThan returns true if the receiver's strength is weaker than the
+// parameter's strength. It returns false if the two isolation levels are
+// equivalent or if the parameter's strength is weaker than the receiver's.
+func (l Level) WeakerThan(l2 Level) bool {
+	// Level is defined such that a larger value represents a weaker isolation
+	// level.
+	return l > l2
+}
+
+// ToleratesWriteSkew returns whether the isolation level permits write skew. A
+// transaction running at such an isolation level is allowed to commit at a
+// timestamp later than its read timestamp without refreshing its reads.
+func (l Level) ToleratesWriteSkew() bool {
+	return l.WeakerThan(Serializable)
+}
+
+// PerStatementReadSnapshot returns whether the isolation level establishes a
+// new read snapshot for each SQL statement, instead of reading from a single
+// snapshot for the whole transaction.
+func (l Level) PerStatementReadSnapshot() bool {
+	return l == ReadCommitted
+}
+
+// SafeValue implements redact.SafeValue.
+func (Level) SafeValue() {}
@@ -0,0 +1,39 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package isolation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelWeakerThan(t *testing.T) {
+	exp := map[[2]Level]bool{
+		{Serializable, Serializable}:   false,
+		{Serializable, ReadCommitted}:  false,
+		{ReadCommitted, Serializable}:  true,
+		{ReadCommitted, ReadCommitted}: false,
+	}
+	for levels, weaker := range exp {
+		require.Equal(t, weaker, levels[0].WeakerThan(levels[1]), "%s < %s", levels[0], levels[1])
+	}
+}
+
+func TestLevelToleratesWriteSkew(t *testing.T) {
+	require.False(t, Serializable.ToleratesWriteSkew())
+	require.True(t, ReadCommitted.ToleratesWriteSkew())
+}
+
+func TestLevelPerStatementReadSnapshot(t *testing.T) {
+	require.False(t, Serializable.PerStatementReadSnapshot())
+	require.True(t, ReadCommitted.PerStatementReadSnapshot())
+}
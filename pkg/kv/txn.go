@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
@@ -298,6 +299,26 @@ func (txn *Txn) SetUserPriority(userPriority roachpb.UserPriority) error {
 	return txn.mu.sender.SetUserPriority(userPriority)
 }
 
+// SetIsoLevel sets the transaction's isolation level. Transactions default to
+// Serializable isolation. The isolation level must be set before any
+// operations are performed on the transaction.
+func (txn *Txn) SetIsoLevel(isoLevel isolation.Level) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("SetIsoLevel() called on leaf txn")
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetIsoLevel(isoLevel)
+}
+
+// IsoLevel returns the transaction's isolation level.
+func (txn *Txn) IsoLevel() isolation.Level {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.IsoLevel()
+}
+
 // TestingSetPriority sets the transaction priority. It is intended for
 // internal (testing) use only.
 func (txn *Txn) TestingSetPriority(priority enginepb.TxnPriority) {
@@ -1108,9 +1129,43 @@ func (txn *Txn) handleRetryableErrLocked(
 	ctx context.Context, retryErr *roachpb.TransactionRetryWithProtoRefreshError,
 ) {
 	txn.resetDeadlineLocked()
+	if retryErr.StatementRetry {
+		// The error only required the statement which hit it to be retried, so
+		// the transaction was not restarted. Since we're retrying the whole
+		// transaction, restart it at a new epoch to discard all of its writes.
+		txn.mu.sender.ManualRestart(ctx, txn.mu.userPriority, retryErr.Transaction.WriteTimestamp)
+	}
 	txn.replaceRootSenderIfTxnAbortedLocked(ctx, retryErr, retryErr.TxnID)
 }
 
+// PrepareForStatementRetry needs to be called before retrying a SQL statement
+// which hit a retryable error that only requires the statement to be retried
+// (see TransactionRetryWithProtoRefreshError.StatementRetry). It clears the
+// error without restarting the transaction. The caller is expected to roll back
+// the writes of the statement, by rolling back to a savepoint established
+// before the statement started.
+func (txn *Txn) PrepareForStatementRetry(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("PrepareForStatementRetry() called on leaf txn")
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+
+	retryErr := txn.mu.sender.GetTxnRetryableErr(ctx)
+	if retryErr == nil {
+		return nil
+	}
+	if !retryErr.StatementRetry {
+		return errors.NewAssertionErrorWithWrappedErrf(
+			retryErr, "PrepareForStatementRetry() called on transaction retry error")
+	}
+	log.VEventf(ctx, 2, "retrying statement in transaction: %s because of a retryable error: %s",
+		txn.debugNameLocked(), retryErr)
+	txn.mu.sender.ClearTxnRetryableErr(ctx)
+	return nil
+}
+
 // NegotiateAndSend is a specialized version of Send that is capable of
 // orchestrating a bounded-staleness read through the transaction, given a
 // read-only BatchRequest with a min_timestamp_bound set in its Header.
@@ -1556,6 +1611,19 @@ func (txn *Txn) Step(ctx context.Context) error {
 	return txn.mu.sender.Step(ctx)
 }
 
+// StepReadTimestamp establishes a new read snapshot for the transaction, if
+// its isolation level calls for a new read snapshot for each SQL statement.
+// See TxnSender.StepReadTimestamp.
+func (txn *Txn) StepReadTimestamp(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("StepReadTimestamp() called on leaf txn")
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.StepReadTimestamp(ctx)
+}
+
 // SetReadSeqNum sets the read sequence number for this transaction.
 func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
 	txn.mu.Lock()
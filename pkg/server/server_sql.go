@@ -72,6 +72,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/gcjob/gcjobnotifier"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
@@ -972,6 +973,14 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		cfg.Settings,
 	)
 	execCfg.StmtDiagnosticsRecorder = stmtDiagnosticsRegistry
+	execCfg.NotifyRegistry = notify.NewRegistry(
+		codec,
+		cfg.clock,
+		cfg.Settings,
+		cfg.stopper,
+		cfg.rangeFeedFactory,
+		cfg.circularInternalExecutor,
+	)
 
 	{
 		// We only need to attach a version upgrade hook if we're the system
@@ -1228,6 +1237,7 @@ func (s *SQLServer) preStart(
 		return err
 	}
 	s.stmtDiagnosticsRegistry.Start(ctx, stopper)
+	s.execCfg.NotifyRegistry.Start(ctx, s.execCfg.SystemTableIDResolver)
 
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
@@ -522,9 +522,9 @@ func (s *SQLTranslator) findDescendantLeafIDsForDescriptor(
 	}
 
 	switch desc.DescriptorType() {
-	case catalog.Type, catalog.Schema:
-		// There is nothing to do for {Type, Schema} descriptors as they are not
-		// part of the zone configuration hierarchy.
+	case catalog.Type, catalog.Schema, catalog.Function:
+		// There is nothing to do for {Type, Schema, Function} descriptors as
+		// they are not part of the zone configuration hierarchy.
 		return nil, nil
 	case catalog.Table:
 		// Tables are leaf objects in the zone configuration hierarchy, so simply
@@ -0,0 +1,294 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
+)
+
+// advisoryLockRetryOptions are the options of the loop which waits for an
+// advisory lock held by another session.
+var advisoryLockRetryOptions = retry.Options{
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
+// releaseAdvisoryLocksTimeout is how long a closing session tries to release
+// its advisory locks.
+const releaseAdvisoryLocksTimeout = 10 * time.Second
+
+// advisoryLocks tracks whether a session may hold advisory locks. The locks
+// themselves are stored in system.advisory_locks, keyed by the ID of the
+// session.
+//
+// Each lock is also associated with the sqlliveness session of the SQL
+// instance which serves the session, so that the locks of the sessions of a
+// SQL instance which died are taken over by the sessions which wait for them.
+// Deadlocks between sessions waiting for each other's advisory locks are not
+// detected; they are resolved by statement_timeout or lock_timeout.
+type advisoryLocks struct {
+	// session is set once the session attempted to acquire a session-level
+	// lock, and reset when it released all of them.
+	session bool
+	// xact is set once the current transaction attempted to acquire a
+	// transaction-level lock, and reset when the locks are released at the end
+	// of the transaction.
+	xact bool
+	// changes counts the attempts to acquire or release locks. The locks are
+	// acquired and released in their own transactions, so a statement which
+	// changed them cannot be retried on a new read snapshot.
+	changes int
+}
+
+// AcquireAdvisoryLock is part of the eval.Planner interface.
+func (p *planner) AcquireAdvisoryLock(
+	ctx context.Context, lock eval.AdvisoryLock, wait bool,
+) (bool, error) {
+	if err := p.checkCanUseAdvisoryLocks(ctx); err != nil {
+		return false, err
+	}
+	// The session is marked as holding locks before the lock is acquired, so
+	// that the lock is released even if the outcome of the acquisition is
+	// ambiguous.
+	p.extendedEvalCtx.AdvisoryLocks.changes++
+	if lock.Xact {
+		p.extendedEvalCtx.AdvisoryLocks.xact = true
+	} else {
+		p.extendedEvalCtx.AdvisoryLocks.session = true
+	}
+	var deadline time.Time
+	if timeout := p.SessionData().LockTimeout; timeout > 0 {
+		deadline = timeutil.Now().Add(timeout)
+	}
+	for r := retry.StartWithCtx(ctx, advisoryLockRetryOptions); r.Next(); {
+		acquired, err := p.tryAcquireAdvisoryLock(ctx, lock)
+		if err != nil || acquired || !wait {
+			return acquired, err
+		}
+		if !deadline.IsZero() && timeutil.Now().After(deadline) {
+			return false, pgerror.New(pgcode.LockNotAvailable,
+				"canceling statement due to lock timeout on advisory lock")
+		}
+	}
+	return false, ctx.Err()
+}
+
+// tryAcquireAdvisoryLock acquires an advisory lock if it is not held by
+// another session in a conflicting mode. The locks held by the sessions of a
+// SQL instance whose sqlliveness session expired are released.
+func (p *planner) tryAcquireAdvisoryLock(
+	ctx context.Context, lock eval.AdvisoryLock,
+) (acquired bool, _ error) {
+	execCfg := p.ExecCfg()
+	livenessSession, err := execCfg.SQLLiveness.Session(ctx)
+	if err != nil {
+		return false, err
+	}
+	ie := execCfg.InternalExecutor
+	sessionID := p.ExtendedEvalContext().SessionID.GetBytes()
+	err = execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		acquired = false
+		// Shared locks only conflict with exclusive locks.
+		holders, err := ie.QueryBufferedEx(
+			ctx, "advisory-lock-holders", txn,
+			sessiondata.NodeUserSessionDataOverride,
+			`SELECT DISTINCT liveness_session_id FROM system.advisory_locks
+WHERE lock_key = $1 AND key_pair = $2 AND session_id != $3 AND NOT (shared AND $4)`,
+			lock.Key, lock.KeyPair, sessionID, lock.Shared,
+		)
+		if err != nil {
+			return err
+		}
+		for _, holder := range holders {
+			id := sqlliveness.SessionID(tree.MustBeDBytes(holder[0]))
+			alive, err := execCfg.SQLLiveness.IsAlive(ctx, id)
+			if err != nil {
+				return err
+			}
+			if alive {
+				return nil
+			}
+			// The SQL instance of the holder died, so the locks of all its
+			// sessions are released.
+			if _, err := ie.ExecEx(
+				ctx, "advisory-lock-release-dead", txn,
+				sessiondata.NodeUserSessionDataOverride,
+				`DELETE FROM system.advisory_locks WHERE liveness_session_id = $1`,
+				id.UnsafeBytes(),
+			); err != nil {
+				return err
+			}
+		}
+		if _, err := ie.ExecEx(
+			ctx, "advisory-lock-acquire", txn,
+			sessiondata.NodeUserSessionDataOverride,
+			`INSERT INTO system.advisory_locks
+  (lock_key, key_pair, session_id, xact, shared, count, liveness_session_id)
+VALUES ($1, $2, $3, $4, $5, 1, $6)
+ON CONFLICT (lock_key, key_pair, session_id, xact, shared) DO UPDATE
+SET count = system.advisory_locks.count + 1, liveness_session_id = excluded.liveness_session_id`,
+			lock.Key, lock.KeyPair, sessionID, lock.Xact, lock.Shared,
+			livenessSession.ID().UnsafeBytes(),
+		); err != nil {
+			return err
+		}
+		acquired = true
+		return nil
+	})
+	return acquired, err
+}
+
+// ReleaseAdvisoryLock is part of the eval.Planner interface.
+func (p *planner) ReleaseAdvisoryLock(
+	ctx context.Context, lock eval.AdvisoryLock,
+) (released bool, _ error) {
+	if err := p.checkCanUseAdvisoryLocks(ctx); err != nil {
+		return false, err
+	}
+	if !p.extendedEvalCtx.AdvisoryLocks.session {
+		return false, nil
+	}
+	p.extendedEvalCtx.AdvisoryLocks.changes++
+	execCfg := p.ExecCfg()
+	ie := execCfg.InternalExecutor
+	sessionID := p.ExtendedEvalContext().SessionID.GetBytes()
+	err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		row, err := ie.QueryRowEx(
+			ctx, "advisory-unlock", txn,
+			sessiondata.NodeUserSessionDataOverride,
+			`UPDATE system.advisory_locks SET count = count - 1
+WHERE lock_key = $1 AND key_pair = $2 AND session_id = $3 AND NOT xact AND shared = $4
+RETURNING count`,
+			lock.Key, lock.KeyPair, sessionID, lock.Shared,
+		)
+		if err != nil {
+			return err
+		}
+		released = row != nil
+		if !released || tree.MustBeDInt(row[0]) > 0 {
+			return nil
+		}
+		_, err = ie.ExecEx(
+			ctx, "advisory-unlock-delete", txn,
+			sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.advisory_locks
+WHERE lock_key = $1 AND key_pair = $2 AND session_id = $3 AND NOT xact AND shared = $4`,
+			lock.Key, lock.KeyPair, sessionID, lock.Shared,
+		)
+		return err
+	})
+	return released, err
+}
+
+// ReleaseAllAdvisoryLocks is part of the eval.Planner interface.
+func (p *planner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	if err := p.checkCanUseAdvisoryLocks(ctx); err != nil {
+		return err
+	}
+	if !p.extendedEvalCtx.AdvisoryLocks.session {
+		return nil
+	}
+	p.extendedEvalCtx.AdvisoryLocks.changes++
+	if err := releaseAdvisoryLocks(
+		ctx, p.ExecCfg().InternalExecutor, p.ExtendedEvalContext().SessionID, false, /* xact */
+	); err != nil {
+		return err
+	}
+	p.extendedEvalCtx.AdvisoryLocks.session = false
+	return nil
+}
+
+func (p *planner) checkCanUseAdvisoryLocks(ctx context.Context) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.AdvisoryLocksTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use advisory locks",
+			clusterversion.ByKey(clusterversion.AdvisoryLocksTable))
+	}
+	if p.extendedEvalCtx.AdvisoryLocks == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"advisory locks cannot be used in this context")
+	}
+	return nil
+}
+
+// releaseAdvisoryLocks releases either the session-level or the
+// transaction-level advisory locks held by a session.
+func releaseAdvisoryLocks(
+	ctx context.Context, ie *InternalExecutor, sessionID clusterunique.ID, xact bool,
+) error {
+	_, err := ie.ExecEx(
+		ctx, "release-advisory-locks", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.advisory_locks WHERE session_id = $1 AND xact = $2`,
+		sessionID.GetBytes(), xact,
+	)
+	return err
+}
+
+// releaseXactAdvisoryLocks releases the transaction-level advisory locks
+// acquired by a transaction which ended.
+func (ex *connExecutor) releaseXactAdvisoryLocks(ctx context.Context) {
+	if !ex.advisoryLocks.xact {
+		return
+	}
+	if err := releaseAdvisoryLocks(
+		ctx, ex.server.cfg.InternalExecutor, ex.sessionID, true, /* xact */
+	); err != nil {
+		// The transaction already ended, so the error cannot be returned to the
+		// client. The release is attempted again at the end of the next
+		// transaction, and when the session closes.
+		log.Warningf(ctx, "failed to release transaction-level advisory locks: %v", err)
+		return
+	}
+	ex.advisoryLocks.xact = false
+}
+
+// releaseAllAdvisoryLocks releases the advisory locks held by a session which
+// is closing.
+func (ex *connExecutor) releaseAllAdvisoryLocks(ctx context.Context) {
+	if !ex.advisoryLocks.session && !ex.advisoryLocks.xact {
+		return
+	}
+	// The context of the session may already be canceled if the client
+	// disconnected, but the locks must still be released so that other
+	// sessions can acquire them.
+	ctx, cancel := context.WithTimeout(
+		logtags.WithTags(context.Background(), logtags.FromContext(ctx)), releaseAdvisoryLocksTimeout,
+	)
+	defer cancel()
+	if _, err := ex.server.cfg.InternalExecutor.ExecEx(
+		ctx, "release-all-advisory-locks", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.advisory_locks WHERE session_id = $1`,
+		ex.sessionID.GetBytes(),
+	); err != nil {
+		// The locks are taken over by other sessions once the SQL instance
+		// dies.
+		log.Warningf(ctx, "failed to release advisory locks: %v", err)
+	}
+	ex.advisoryLocks = advisoryLocks{}
+}
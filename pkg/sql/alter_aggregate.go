@@ -0,0 +1,120 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+type alterAggregateRenameNode struct {
+	n    *tree.AlterAggregateRename
+	desc *funcdesc.Mutable
+}
+
+// AlterAggregateRename renames a user-defined aggregate. The aggregate stays
+// in the same schema.
+// Privileges: ownership of the aggregate.
+//   notes: postgres also requires CREATE on the schema of the aggregate.
+func (p *planner) AlterAggregateRename(
+	ctx context.Context, n *tree.AlterAggregateRename,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+
+	if _, ok := tree.FunDefs[string(n.NewName)]; ok {
+		return nil, pgerror.Newf(pgcode.DuplicateFunction,
+			"aggregate %s conflicts with a builtin function", n.NewName)
+	}
+	name := &n.Aggregate.Name
+	argTypes, err := p.resolveAggregateTypes(ctx, &n.Aggregate)
+	if err != nil {
+		return nil, err
+	}
+	db, err := p.getFunctionDatabase(ctx, "aggregate", name)
+	if err != nil {
+		return nil, err
+	}
+	agg, err := p.findAggregate(ctx, db, name, argTypes)
+	if err != nil {
+		return nil, err
+	}
+	if agg == nil {
+		return nil, errAggregateDoesNotExist(name.Object(), argTypes)
+	}
+	if err := p.checkFunctionOwnership(ctx, agg); err != nil {
+		return nil, err
+	}
+	desc, err := p.Descriptors().GetMutableFunctionByID(
+		ctx, p.txn, agg.GetID(), tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &alterAggregateRenameNode{n: n, desc: desc}, nil
+}
+
+func (n *alterAggregateRenameNode) startExec(params runParams) error {
+	p := params.p
+	newName := string(n.n.NewName)
+	if newName == n.desc.Name {
+		return nil
+	}
+	mutSchema, err := p.Descriptors().GetMutableDescriptorByID(
+		params.ctx, p.txn, n.desc.GetParentSchemaID(),
+	)
+	if err != nil {
+		return err
+	}
+	scDesc, ok := mutSchema.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("schema %d of aggregate %q has no descriptor",
+			n.desc.GetParentSchemaID(), n.desc.Name)
+	}
+	if hasFunction, err := p.hasFunctionNamed(params.ctx, scDesc, newName); err != nil {
+		return err
+	} else if hasFunction {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"aggregate %s conflicts with a user-defined function", newName)
+	}
+	argTypes := n.desc.ArgTypes()
+	if existing, err := p.findFunctionInSchema(params.ctx, scDesc, newName, argTypes); err != nil {
+		return err
+	} else if existing != nil {
+		return pgerror.Newf(pgcode.DuplicateFunction, "%s %s already exists",
+			functionDescKind(existing), aggregateSignatureString(newName, argTypes))
+	}
+
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	scDesc.RemoveFunction(n.desc.Name, n.desc.ID)
+	scDesc.AddFunction(newName, n.desc.ID, argTypes)
+	if err := p.writeSchemaDescChange(params.ctx, scDesc, jobDesc); err != nil {
+		return err
+	}
+	n.desc.Name = newName
+	return p.writeFunctionDescChange(params.ctx, n.desc, jobDesc)
+}
+
+func (n *alterAggregateRenameNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterAggregateRenameNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterAggregateRenameNode) Close(context.Context)        {}
@@ -64,6 +64,9 @@ func (p *planner) AlterPrimaryKey(
 	); err != nil {
 		return err
 	}
+	if err := CheckNoIncrementalMaterializedViews(tableDesc, "alter the primary key of"); err != nil {
+		return err
+	}
 
 	if alterPrimaryKeyLocalitySwap != nil {
 		if err := p.checkNoRegionChangeUnderway(
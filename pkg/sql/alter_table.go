@@ -99,6 +99,11 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 			tree.Name(tableDesc.GetName()), tree.Name(tableDesc.GetName()))
 	}
 
+	if tableDesc.IsForeign() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"cannot alter foreign table %q", tableDesc.GetName())
+	}
+
 	n.HoistAddColumnConstraints(func() {
 		telemetry.Inc(sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "add_column.references"))
 	})
@@ -223,6 +228,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					continue
 				}
+				if d.Deferrability != tree.ConstraintNotDeferrable {
+					return errDeferrableUniqueIndex
+				}
 
 				if d.PrimaryKey {
 					// Translate this operation into an ALTER PRIMARY KEY command.
@@ -447,6 +455,21 @@ func (n *alterTableNode) startExec(params runParams) error {
 				// 	return err
 				// }
 
+			case *tree.ExclusionConstraintTableDef:
+				if err := addExclusionConstraintTableDef(
+					params.ctx,
+					params.EvalContext(),
+					d,
+					n.tableDesc,
+					*tn,
+					NonEmptyTable,
+					t.ValidationBehavior,
+					params.p.SemaCtx(),
+				); err != nil {
+					return err
+				}
+				descriptorChanged = true
+
 			default:
 				return errors.AssertionFailedf(
 					"unsupported constraint: %T", t.ConstraintDef)
@@ -590,6 +613,22 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 				foundFk.Validity = descpb.ConstraintValidity_Validated
 
+			case descpb.ConstraintTypeExclusion:
+				// If the constraint is still being validated, don't allow VALIDATE
+				// CONSTRAINT to run.
+				if constraint.ExclusionConstraint.Validity == descpb.ConstraintValidity_Validating {
+					return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+						"constraint %q in the middle of being added, try again later", t.Constraint)
+				}
+				if err := validateExclusionConstraintInTxn(
+					params.ctx, params.ExecCfg().InternalExecutorFactory(
+						params.ctx, params.SessionData(),
+					), n.tableDesc, params.p.Txn(), params.p.User(), name,
+				); err != nil {
+					return err
+				}
+				constraint.ExclusionConstraint.Validity = descpb.ConstraintValidity_Validated
+
 			case descpb.ConstraintTypeUnique:
 				if constraint.Index == nil {
 					var foundUnique *descpb.UniqueWithoutIndexConstraint
@@ -623,8 +662,8 @@ func (n *alterTableNode) startExec(params runParams) error {
 
 			default:
 				return pgerror.Newf(pgcode.WrongObjectType,
-					"constraint %q of relation %q is not a foreign key, check, or unique without index"+
-						" constraint", tree.ErrString(&t.Constraint), tree.ErrString(n.n.Table))
+					"constraint %q of relation %q is not a foreign key, check, exclusion, or unique"+
+						" without index constraint", tree.ErrString(&t.Constraint), tree.ErrString(n.n.Table))
 			}
 			descriptorChanged = true
 
@@ -715,6 +754,27 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableRowLevelSecurity:
+			if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.RowLevelSecurity) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use row-level security",
+					clusterversion.ByKey(clusterversion.RowLevelSecurity))
+			}
+			if err := params.p.checkCanManagePolicies(params.ctx, n.tableDesc); err != nil {
+				return err
+			}
+			switch t.Action {
+			case tree.RowLevelSecurityEnable:
+				n.tableDesc.RowLevelSecurity = true
+			case tree.RowLevelSecurityDisable:
+				n.tableDesc.RowLevelSecurity = false
+			case tree.RowLevelSecurityForce:
+				n.tableDesc.ForceRowLevelSecurity = true
+			case tree.RowLevelSecurityNoForce:
+				n.tableDesc.ForceRowLevelSecurity = false
+			}
+			descriptorChanged = true
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -1419,6 +1479,9 @@ func validateConstraintNameIsNotUsed(
 	case *tree.ForeignKeyConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
+	case *tree.ExclusionConstraintTableDef:
+		name = d.Name
+		hasIfNotExists = d.IfNotExists
 	case *tree.UniqueConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
@@ -1657,6 +1720,15 @@ func dropColumnImpl(
 		return nil, err
 	}
 
+	// You can't drop a column referenced by a policy unless CASCADE was
+	// specified, in which case the policy is dropped.
+	if err := dropPoliciesReferencingColumn(tableDesc, colToDrop, t.DropBehavior); err != nil {
+		return nil, err
+	}
+
+	// The privileges granted on the column are dropped with it.
+	tableDesc.RemoveColumnPrivileges(colToDrop.GetID())
+
 	if tableDesc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(colToDrop.GetID()) {
 		return nil, pgerror.Newf(pgcode.InvalidColumnReference,
 			"column %q is referenced by the primary key", colToDrop.GetName())
@@ -1840,6 +1912,43 @@ func dropColumnImpl(
 	return droppedViews, validateDescriptor(params.ctx, params.p, tableDesc)
 }
 
+// dropPoliciesReferencingColumn removes the policies of the table whose
+// expressions reference the given column if the drop behavior is CASCADE, and
+// returns an error if there are any such policies otherwise.
+func dropPoliciesReferencingColumn(
+	tableDesc *tabledesc.Mutable, col catalog.Column, behavior tree.DropBehavior,
+) error {
+	policies := make([]descpb.TableDescriptor_Policy, 0, len(tableDesc.Policies))
+	for _, policy := range tableDesc.Policies {
+		referencesCol := false
+		for _, exprStr := range []string{policy.UsingExpr, policy.WithCheckExpr} {
+			if exprStr == "" {
+				continue
+			}
+			expr, err := parser.ParseExpr(exprStr)
+			if err != nil {
+				return err
+			}
+			colIDs, err := schemaexpr.ExtractColumnIDs(tableDesc, expr)
+			if err != nil {
+				return err
+			}
+			referencesCol = referencesCol || colIDs.Contains(col.GetID())
+		}
+		if !referencesCol {
+			policies = append(policies, policy)
+			continue
+		}
+		if behavior != tree.DropCascade {
+			return pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"cannot drop column %q because policy %q on table %q depends on it",
+				col.GetName(), policy.Name, tableDesc.GetName())
+		}
+	}
+	tableDesc.Policies = policies
+	return nil
+}
+
 func handleTTLStorageParamChange(
 	params runParams,
 	tn *tree.TableName,
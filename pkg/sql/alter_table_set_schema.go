@@ -80,6 +80,11 @@ func (p *planner) AlterTableSetSchema(
 	// Check if any objects depend on this table/view/sequence via its name.
 	// If so, then we disallow renaming, otherwise we allow it.
 	for _, dependent := range tableDesc.DependedOnBy {
+		if dependent.Trigger {
+			return nil, p.dependentTriggerError(
+				ctx, string(tableDesc.DescriptorType()), tableDesc.Name, dependent.ID, "set schema on",
+			)
+		}
 		if !dependent.ByID {
 			return nil, p.dependentViewError(
 				ctx, string(tableDesc.DescriptorType()), tableDesc.Name,
@@ -0,0 +1,305 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/plpgsql"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/errors"
+)
+
+type callNode struct {
+	fn   *plpgsql.Function
+	args tree.Datums
+}
+
+// Call invokes a procedure in the transaction of the statement. The
+// procedure can only end the transaction with COMMIT and ROLLBACK if the
+// statement is executed in an implicit transaction which it commits, see
+// procedureCall.
+// Privileges: EXECUTE on the procedure.
+func (p *planner) Call(ctx context.Context, n *tree.Call) (planNode, error) {
+	fn, args, err := p.prepareCall(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return &callNode{fn: fn, args: args}, nil
+}
+
+// prepareCall resolves the procedure invoked by a CALL statement and
+// evaluates its arguments.
+func (p *planner) prepareCall(
+	ctx context.Context, n *tree.Call,
+) (*plpgsql.Function, tree.Datums, error) {
+	name := &n.Procedure
+	db, err := p.getFunctionDatabase(ctx, "procedure", name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The procedures are looked up in the first schema of the search path
+	// which contains procedures with the given name.
+	var overloads []tree.Overload
+	procs := make(map[descpb.ID]catalog.FunctionDescriptor)
+	isFunction := false
+	collect := func(scName string) error {
+		sc, err := p.Descriptors().GetImmutableSchemaByName(
+			ctx, p.txn, db, scName, tree.SchemaLookupFlags{},
+		)
+		if err != nil || sc == nil {
+			return err
+		}
+		fns, err := p.getFunctionsNamed(ctx, sc, name.Object())
+		if err != nil {
+			return err
+		}
+		for _, fn := range fns {
+			if !fn.GetIsProcedure() {
+				isFunction = true
+				continue
+			}
+			procs[fn.GetID()] = fn
+			overloads = append(overloads, makeUserDefinedFunctionOverloads(fn, nil /* privErr */)...)
+		}
+		return nil
+	}
+	if name.ExplicitSchema {
+		if err := collect(string(name.SchemaName)); err != nil {
+			return nil, nil, err
+		}
+	} else if err := p.SessionData().SearchPath.IterateSearchPath(func(scName string) error {
+		if err := collect(scName); err != nil {
+			return err
+		}
+		if len(overloads) > 0 {
+			return iterutil.StopIteration()
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	if len(overloads) == 0 {
+		if isFunction {
+			return nil, nil, errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%s is not a procedure", tree.ErrString(name)),
+				"To call a function, use SELECT.")
+		}
+		return nil, nil, pgerror.Newf(pgcode.UndefinedFunction,
+			"procedure %s does not exist", tree.ErrString(name))
+	}
+
+	// The procedure is chosen among the overloads like a function.
+	def := tree.NewFunctionDefinition(name.Object(), &tree.FunctionProperties{
+		Category: "User-defined",
+	}, overloads)
+	expr := &tree.FuncExpr{
+		Func:  tree.ResolvableFunctionReference{FunctionReference: def},
+		Exprs: n.Args,
+	}
+	defer p.semaCtx.Properties.Restore(p.semaCtx.Properties)
+	p.semaCtx.Properties.Require("CALL", tree.RejectSpecial|tree.RejectSubqueries)
+	typedExpr, err := tree.TypeCheck(ctx, expr, &p.semaCtx, types.Any)
+	if err != nil {
+		return nil, nil, err
+	}
+	funcExpr, ok := typedExpr.(*tree.FuncExpr)
+	if !ok {
+		return nil, nil, errors.AssertionFailedf("unexpected expression %T", typedExpr)
+	}
+	proc := funcExpr.ResolvedOverload().UserDefinedFunction.(*descpb.FunctionDescriptor)
+	if err := p.CheckPrivilege(ctx, procs[proc.ID], privilege.EXECUTE); err != nil {
+		return nil, nil, err
+	}
+	fn, err := makePLpgSQLFunction(proc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.EvalContext().PrepareOnly {
+		// The arguments are evaluated when the statement is executed, once the
+		// values of the placeholders are known.
+		return fn, nil, nil
+	}
+	args := make(tree.Datums, len(funcExpr.Exprs))
+	for i, arg := range funcExpr.Exprs {
+		d, err := eval.Expr(p.EvalContext(), arg.(tree.TypedExpr))
+		if err != nil {
+			return nil, nil, err
+		}
+		if typ := proc.Args[i].Type; d != tree.DNull && !d.ResolvedType().Identical(typ) {
+			if d, err = eval.PerformAssignmentCast(p.EvalContext(), d, typ); err != nil {
+				return nil, nil, err
+			}
+		}
+		args[i] = d
+	}
+	if args, err = addDefaultArgs(p.EvalContext(), proc, args); err != nil {
+		return nil, nil, err
+	}
+	return fn, args, nil
+}
+
+func (n *callNode) startExec(params runParams) error {
+	state := params.extendedEvalCtx.procedureTxn
+	if state == nil || !params.p.autoCommit {
+		return n.fn.CallProcedure(params.EvalContext(), n.args, nil /* txnCtl */)
+	}
+	// The notices of the procedure are buffered by the procedureCall, since
+	// the results of the CALL statement are discarded when the procedure ends
+	// its transaction, and are delivered once it returns.
+	res := params.p.noticeSender
+	defer func() { params.p.noticeSender = res }()
+	var done bool
+	var err error
+	if call := state.call; call == nil {
+		call = newProcedureCall()
+		state.call = call
+		params.p.noticeSender = call
+		call.start(n.fn, params.EvalContext(), n.args)
+		done, err = call.wait()
+	} else {
+		params.p.noticeSender = call
+		done, err = call.continueIn(params.EvalContext())
+	}
+	if done {
+		if res != nil {
+			for _, notice := range state.call.notices {
+				res.BufferNotice(notice)
+			}
+		}
+		state.call = nil
+	}
+	return err
+}
+
+func (n *callNode) Next(runParams) (bool, error) { return false, nil }
+func (n *callNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *callNode) Close(context.Context)        {}
+
+// procedureTxnState holds the procedure of a session which is suspended after
+// ending its transaction.
+type procedureTxnState struct {
+	call *procedureCall
+}
+
+// procedureCall is a procedure which can end its transactions. The procedure
+// runs in its own goroutine, and is suspended when it executes COMMIT or
+// ROLLBACK: its CALL statement then returns, and the connExecutor ends the
+// transaction through its state machine, like it does for a COMMIT or ROLLBACK
+// statement. The CALL statement is then executed again, in a new implicit
+// transaction, which resumes the procedure.
+//
+// The goroutine of the procedure and the connExecutor never run at the same
+// time: each of them waits for the other while it is not running.
+type procedureCall struct {
+	// events receives an event when the procedure returns or is suspended.
+	events chan procedureEvent
+	// resume is used to resume the suspended procedure in the transaction of
+	// an evaluation context, or to abort it if the context is nil.
+	resume chan *eval.Context
+	// commit is set if the suspended procedure commits its transaction, rather
+	// than rolling it back.
+	commit bool
+	// resumed is set once the procedure ended a transaction, after which the
+	// CALL statement can no longer be retried.
+	resumed bool
+	// portalName is the name of the portal which executes the CALL statement,
+	// if the extended protocol is used. The portal is preserved across the
+	// transactions of the procedure.
+	portalName string
+	// notices are the notices sent by the procedure.
+	notices []pgnotice.Notice
+}
+
+// procedureEvent is sent by a procedureCall when the procedure returns, in
+// which case done is set, or when it is suspended.
+type procedureEvent struct {
+	done   bool
+	err    error
+	commit bool
+}
+
+var _ plpgsql.TxnController = &procedureCall{}
+var _ noticeSender = &procedureCall{}
+
+func newProcedureCall() *procedureCall {
+	return &procedureCall{
+		events: make(chan procedureEvent),
+		resume: make(chan *eval.Context),
+	}
+}
+
+// start starts executing a procedure in the transaction of evalCtx. It must be
+// followed by a call to wait.
+func (c *procedureCall) start(fn *plpgsql.Function, evalCtx *eval.Context, args tree.Datums) {
+	go func() {
+		err := fn.CallProcedure(evalCtx, args, c)
+		c.events <- procedureEvent{done: true, err: err}
+	}()
+}
+
+// BufferNotice implements the noticeSender interface.
+func (c *procedureCall) BufferNotice(notice pgnotice.Notice) {
+	c.notices = append(c.notices, notice)
+}
+
+// EndTxn implements the plpgsql.TxnController interface. It suspends the
+// procedure until it is resumed in a new transaction.
+func (c *procedureCall) EndTxn(_ context.Context, commit bool) (*eval.Context, error) {
+	c.events <- procedureEvent{commit: commit}
+	evalCtx := <-c.resume
+	if evalCtx == nil {
+		return nil, errors.New("procedure aborted")
+	}
+	return evalCtx, nil
+}
+
+// wait waits until the procedure returns, in which case done is set, or until
+// it is suspended.
+func (c *procedureCall) wait() (done bool, _ error) {
+	ev := <-c.events
+	c.commit = ev.commit
+	return ev.done, c.maybeNonRetriable(ev.err)
+}
+
+// continueIn resumes the suspended procedure in the transaction of evalCtx,
+// and waits until it returns or is suspended again.
+func (c *procedureCall) continueIn(evalCtx *eval.Context) (done bool, _ error) {
+	c.resumed = true
+	c.resume <- evalCtx
+	return c.wait()
+}
+
+// abort aborts the suspended procedure, and waits until it returns.
+func (c *procedureCall) abort() {
+	c.resume <- nil
+	<-c.events
+}
+
+// maybeNonRetriable prevents the error of a procedure which already ended a
+// transaction from causing the CALL statement to be retried, since the
+// procedure cannot be executed again from the start.
+func (c *procedureCall) maybeNonRetriable(err error) error {
+	if err == nil || !c.resumed || !errIsRetriable(err) {
+		return err
+	}
+	return pgerror.WithCandidateCode(errors.Handled(err), pgcode.SerializationFailure)
+}
@@ -324,6 +324,9 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 
 	// Tables introduced in 22.2.
 	target.AddDescriptor(systemschema.SystemPrivilegeTable)
+	target.AddDescriptor(systemschema.NotificationsTable)
+	target.AddDescriptor(systemschema.AdvisoryLocksTable)
+	target.AddDescriptor(systemschema.ReplicationSlotsTable)
 
 	// Adding a new system table? It should be added here to the metadata schema,
 	// and also created as a migration for older clusters.
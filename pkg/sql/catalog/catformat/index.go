@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -113,7 +114,9 @@ func indexForDisplay(
 
 	if f.HasFlags(tree.FmtPGCatalog) {
 		f.WriteString(" USING")
-		if index.Type == descpb.IndexDescriptor_INVERTED {
+		if !index.VectorConfig.IsEmpty() {
+			f.WriteString(" ivfflat")
+		} else if index.Type == descpb.IndexDescriptor_INVERTED {
 			f.WriteString(" gin")
 		} else {
 			f.WriteString(" btree")
@@ -215,6 +218,13 @@ func FormatIndexElements(
 		} else {
 			f.FormatNameP(&index.KeyColumnNames[i])
 		}
+		// The operator class of a vector index determines its distance metric,
+		// so it is shown unless it is the default.
+		if i == n-1 && !index.VectorConfig.IsEmpty() &&
+			index.VectorConfig.Metric != vecindex.DistanceMetric_L2 {
+			f.WriteByte(' ')
+			f.WriteString(index.VectorConfig.Metric.OpClass())
+		}
 		f.WriteByte(' ')
 		f.WriteString(index.KeyColumnDirections[i].String())
 	}
@@ -298,6 +308,17 @@ func formatStorageConfigs(
 		}
 	}
 
+	if !index.VectorConfig.IsEmpty() && index.VectorConfig.Lists != vecindex.DefaultLists {
+		if numCustomSettings > 0 {
+			f.WriteString(", ")
+		} else {
+			f.WriteString(" WITH (")
+		}
+		f.WriteString(`lists=`)
+		f.WriteString(strconv.FormatInt(int64(index.VectorConfig.Lists), 10))
+		numCustomSettings++
+	}
+
 	if index.IsSharded() {
 		if numCustomSettings > 0 {
 			f.WriteString(", ")
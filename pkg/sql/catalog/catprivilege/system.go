@@ -65,6 +65,9 @@ var (
 		catconstants.TenantSettingsTableName,
 		catconstants.SpanCountTableName,
 		catconstants.SystemPrivilegeTableName,
+		catconstants.NotificationsTableName,
+		catconstants.AdvisoryLocksTableName,
+		catconstants.ReplicationSlotsTableName,
 	}
 
 	systemSuperuserPrivileges = func() map[descpb.NameInfo]privilege.List {
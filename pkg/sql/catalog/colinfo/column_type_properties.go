@@ -55,6 +55,8 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 		return true
 	case types.ArrayFamily:
 		return CanHaveCompositeKeyEncoding(typ.ArrayContents())
+	case types.RangeFamily:
+		return CanHaveCompositeKeyEncoding(typ.RangeContents())
 	case types.TupleFamily:
 		for _, t := range typ.TupleContents() {
 			if CanHaveCompositeKeyEncoding(t) {
@@ -80,7 +82,11 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 		types.GeometryFamily,
 		types.GeographyFamily,
 		types.EnumFamily,
-		types.Box2DFamily:
+		types.Box2DFamily,
+		types.TSQueryFamily,
+		types.TSVectorFamily,
+		types.JsonpathFamily,
+		types.PGVectorFamily:
 		return false
 	case types.UnknownFamily,
 		types.AnyFamily:
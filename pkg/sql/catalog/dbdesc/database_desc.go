@@ -272,6 +272,38 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	if desc.IsMultiRegion() {
 		desc.validateMultiRegion(vea)
 	}
+
+	desc.validatePublications(vea)
+}
+
+// validatePublications validates that publications are well formed. Checks
+// include validating that publication names are unique and that publications
+// which include all tables do not list tables.
+func (desc *immutable) validatePublications(vea catalog.ValidationErrorAccumulator) {
+	names := make(map[string]struct{}, len(desc.Publications))
+	for i := range desc.Publications {
+		pub := &desc.Publications[i]
+		if pub.Name == "" {
+			vea.Report(errors.AssertionFailedf("empty publication name"))
+			continue
+		}
+		if _, ok := names[pub.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate publication name: %q", pub.Name))
+		}
+		names[pub.Name] = struct{}{}
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q includes all tables but also lists specific tables", pub.Name))
+		}
+		var ids catalog.DescriptorIDSet
+		for _, id := range pub.TableIDs {
+			if ids.Contains(id) {
+				vea.Report(errors.AssertionFailedf(
+					"publication %q lists table %d more than once", pub.Name, id))
+			}
+			ids.Add(id)
+		}
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
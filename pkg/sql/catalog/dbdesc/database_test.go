@@ -113,6 +113,40 @@ func TestValidateDatabaseDesc(t *testing.T) {
 				Privileges:   catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
 			},
 		},
+		{
+			`duplicate publication name: "pub"`,
+			descpb.DatabaseDescriptor{
+				Name: "db",
+				ID:   51,
+				Publications: []descpb.DatabaseDescriptor_Publication{
+					{Name: "pub", TableIDs: []descpb.ID{52}},
+					{Name: "pub", AllTables: true},
+				},
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+			},
+		},
+		{
+			`publication "pub" includes all tables but also lists specific tables`,
+			descpb.DatabaseDescriptor{
+				Name: "db",
+				ID:   51,
+				Publications: []descpb.DatabaseDescriptor_Publication{
+					{Name: "pub", AllTables: true, TableIDs: []descpb.ID{52}},
+				},
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+			},
+		},
+		{
+			`publication "pub" lists table 52 more than once`,
+			descpb.DatabaseDescriptor{
+				Name: "db",
+				ID:   51,
+				Publications: []descpb.DatabaseDescriptor_Publication{
+					{Name: "pub", TableIDs: []descpb.ID{52, 53, 52}},
+				},
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+			},
+		},
 	}
 	for i, d := range testData {
 		t.Run(d.err, func(t *testing.T) {
@@ -68,6 +68,23 @@ var ForeignKeyReferenceActionValue = [...]catpb.ForeignKeyAction{
 	tree.Cascade:    catpb.ForeignKeyAction_CASCADE,
 }
 
+// ConstraintDeferrabilityValue allows the conversion from a
+// tree.ConstraintDeferrability to a ConstraintDeferrability.
+var ConstraintDeferrabilityValue = [...]ConstraintDeferrability{
+	tree.ConstraintNotDeferrable:      ConstraintDeferrability_NotDeferrable,
+	tree.ConstraintInitiallyImmediate: ConstraintDeferrability_InitiallyImmediate,
+	tree.ConstraintInitiallyDeferred:  ConstraintDeferrability_InitiallyDeferred,
+}
+
+// ConstraintDeferrabilityType allows the conversion from a
+// ConstraintDeferrability to a tree.ConstraintDeferrability. This should match
+// ConstraintDeferrabilityValue.
+var ConstraintDeferrabilityType = [...]tree.ConstraintDeferrability{
+	ConstraintDeferrability_NotDeferrable:      tree.ConstraintNotDeferrable,
+	ConstraintDeferrability_InitiallyImmediate: tree.ConstraintInitiallyImmediate,
+	ConstraintDeferrability_InitiallyDeferred:  tree.ConstraintInitiallyDeferred,
+}
+
 // ConstraintType is used to identify the type of a constraint.
 type ConstraintType string
 
@@ -80,6 +97,8 @@ const (
 	ConstraintTypeUnique ConstraintType = "UNIQUE"
 	// ConstraintTypeCheck identifies a CHECK constraint.
 	ConstraintTypeCheck ConstraintType = "CHECK"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // ConstraintDetail describes a constraint.
@@ -105,6 +124,9 @@ type ConstraintDetail struct {
 
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
+
+	// Only populated for Exclusion Constraints.
+	ExclusionConstraint *TableDescriptor_ExclusionConstraint
 }
 
 // GetConstraintName retrieves correct constraint name base on the constraint
@@ -122,6 +144,22 @@ func (c *ConstraintDetail) GetConstraintName() string {
 		return c.FK.Name
 	case ConstraintTypeCheck:
 		return c.CheckConstraint.Name
+	case ConstraintTypeExclusion:
+		return c.ExclusionConstraint.Name
 	}
 	return ""
 }
+
+// Deferrability returns the deferrability of the constraint. Only foreign key
+// constraints and unique constraints without an index can be deferrable.
+func (c *ConstraintDetail) Deferrability() ConstraintDeferrability {
+	switch c.Kind {
+	case ConstraintTypeFK:
+		return c.FK.Deferrability
+	case ConstraintTypeUnique:
+		if c.UniqueWithoutIndexConstraint != nil {
+			return c.UniqueWithoutIndexConstraint.Deferrability
+		}
+	}
+	return ConstraintDeferrability_NotDeferrable
+}
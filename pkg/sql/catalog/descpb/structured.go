@@ -251,6 +251,11 @@ func (desc *TableDescriptor) IsSequence() bool {
 	return desc.SequenceOpts != nil
 }
 
+// IsForeign implements the TableDescriptor interface.
+func (desc *TableDescriptor) IsForeign() bool {
+	return desc.Foreign != nil
+}
+
 // IsVirtualTable implements the TableDescriptor interface.
 func (desc *TableDescriptor) IsVirtualTable() bool {
 	return IsVirtualTable(desc.ID)
@@ -312,6 +317,17 @@ func (opts *TableDescriptor_SequenceOpts) EffectiveCacheSize() int64 {
 	return opts.CacheSize
 }
 
+// UserDefinedAggregate implements the tree.UserDefinedAggregateOverload
+// interface.
+func (*FunctionDescriptor) UserDefinedAggregate() {}
+
+// UserDefinedFunction implements the tree.UserDefinedFunctionOverload
+// interface.
+func (*FunctionDescriptor) UserDefinedFunction() {}
+
+var _ tree.UserDefinedAggregateOverload = &FunctionDescriptor{}
+var _ tree.UserDefinedFunctionOverload = &FunctionDescriptor{}
+
 // SafeValue implements the redact.SafeValue interface.
 func (ConstraintValidity) SafeValue() {}
 
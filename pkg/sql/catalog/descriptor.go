@@ -45,6 +45,9 @@ const (
 
 	// Schema is for schema descriptors.
 	Schema = "schema"
+
+	// Function is for function descriptors.
+	Function = "function"
 )
 
 // MutationPublicationFilter is used by MakeFirstMutationPublic to filter the
@@ -263,6 +266,8 @@ type DatabaseDescriptor interface {
 	// HasPublicSchemaWithDescriptor returns true iff the database has a public
 	// schema which itself has a descriptor.
 	HasPublicSchemaWithDescriptor() bool
+	// GetPublications returns the publications defined in this database.
+	GetPublications() []descpb.DatabaseDescriptor_Publication
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -303,10 +308,16 @@ type TableDescriptor interface {
 	// IsAs returns true if the TableDescriptor describes a Table that was created
 	// with a CREATE TABLE AS command.
 	IsAs() bool
+	// IsForeign returns true if the TableDescriptor describes a foreign table,
+	// whose rows are read from files in external storage.
+	IsForeign() bool
 
 	// GetSequenceOpts returns the sequence options for this table. Only valid if
 	// IsSequence is true.
 	GetSequenceOpts() *descpb.TableDescriptor_SequenceOpts
+	// GetForeign returns the files from which the rows of a foreign table are
+	// read. Only valid if IsForeign is true.
+	GetForeign() *descpb.TableDescriptor_ForeignTable
 
 	// GetCreateQuery returns the full CREATE TABLE AS query that was used for
 	// table's creation. Only valid if IsAs is true.
@@ -319,6 +330,13 @@ type TableDescriptor interface {
 	// GetViewQuery returns this view's CREATE VIEW declaration. Only valid if
 	// IsView is true.
 	GetViewQuery() string
+	// GetIncrementalRefresh returns how a materialized view is maintained
+	// incrementally. It is nil unless the view is incrementally maintained.
+	GetIncrementalRefresh() *descpb.TableDescriptor_IncrementalRefresh
+	// GetLastRefreshTime returns the timestamp as of which the data of a
+	// materialized view was last computed, or the zero timestamp if it has not
+	// been refreshed since its creation.
+	GetLastRefreshTime() hlc.Timestamp
 
 	// GetLease returns this table's schema change lease.
 	GetLease() *descpb.TableDescriptor_SchemaChangeLease
@@ -667,6 +685,27 @@ type TableDescriptor interface {
 	// referenced by the returned checks are writable, but not necessarily public.
 	ActiveChecks() []descpb.TableDescriptor_CheckConstraint
 
+	// GetTriggers returns information about this table's row-level triggers, if
+	// there are any. Only valid if IsTable returns true.
+	GetTriggers() []descpb.TableDescriptor_Trigger
+
+	// GetExclusionConstraints returns information about this table's EXCLUDE
+	// constraints, if there are any. Only valid if IsTable returns true.
+	GetExclusionConstraints() []descpb.TableDescriptor_ExclusionConstraint
+
+	// GetPolicies returns information about this table's row-level security
+	// policies, if there are any. Only valid if IsTable returns true.
+	GetPolicies() []descpb.TableDescriptor_Policy
+	// GetRowLevelSecurity returns true if row-level security is enabled on this
+	// table.
+	GetRowLevelSecurity() bool
+	// GetForceRowLevelSecurity returns true if the row-level security policies
+	// of this table also apply to its owner.
+	GetForceRowLevelSecurity() bool
+	// GetColumnPrivileges returns the privileges which were granted on
+	// individual columns of this table, if there are any.
+	GetColumnPrivileges() []descpb.TableDescriptor_ColumnPrivileges
+
 	// GetLocalityConfig returns the locality config for this table, which
 	// describes the table's multi-region locality policy if one is set (e.g.
 	// GLOBAL or REGIONAL BY ROW).
@@ -813,6 +852,37 @@ type TypeDescriptor interface {
 	GetReferencingDescriptorID(refOrdinal int) descpb.ID
 }
 
+// FunctionDescriptor is an interface around the descriptor of a user-defined
+// function or procedure.
+type FunctionDescriptor interface {
+	Descriptor
+	// FuncDesc returns the backing protobuf for this function.
+	FuncDesc() *descpb.FunctionDescriptor
+	// GetArgs returns the arguments of the function.
+	GetArgs() []descpb.FunctionDescriptor_Argument
+	// ArgTypes returns the types of the arguments of the function.
+	ArgTypes() []*types.T
+	// GetReturnType returns the return type of the function, which is VOID for
+	// procedures and trigger functions.
+	GetReturnType() *types.T
+	// GetLanguage returns the language in which the body is written.
+	GetLanguage() descpb.FunctionDescriptor_Language
+	// GetBody returns the source of the function.
+	GetBody() string
+	// GetVolatility returns the declared volatility of the function.
+	GetVolatility() descpb.FunctionDescriptor_Volatility
+	// GetStrict returns whether the function returns NULL without being
+	// called when one of its arguments is NULL.
+	GetStrict() bool
+	// GetIsProcedure returns whether this is a procedure.
+	GetIsProcedure() bool
+	// GetReturnsTrigger returns whether this is a trigger function.
+	GetReturnsTrigger() bool
+	// GetAggregate returns the definition of the aggregate if this is a
+	// user-defined aggregate, or nil otherwise.
+	GetAggregate() *descpb.FunctionDescriptor_Aggregate
+}
+
 // TypeDescriptorResolver is an interface used during hydration of type
 // metadata in types.T's. It is similar to tree.TypeReferenceResolver, except
 // that it has the power to return TypeDescriptor, rather than only a
@@ -0,0 +1,140 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// FunctionDescriptorBuilder is an extension of catalog.DescriptorBuilder
+// for function descriptors.
+type FunctionDescriptorBuilder interface {
+	catalog.DescriptorBuilder
+	BuildImmutableFunction() catalog.FunctionDescriptor
+	BuildExistingMutableFunction() *Mutable
+	BuildCreatedMutableFunction() *Mutable
+}
+
+type functionDescriptorBuilder struct {
+	original             *descpb.FunctionDescriptor
+	maybeModified        *descpb.FunctionDescriptor
+	isUncommittedVersion bool
+	changes              catalog.PostDeserializationChanges
+}
+
+var _ FunctionDescriptorBuilder = &functionDescriptorBuilder{}
+
+// NewBuilder creates a new catalog.DescriptorBuilder object for building
+// function descriptors.
+func NewBuilder(desc *descpb.FunctionDescriptor) FunctionDescriptorBuilder {
+	return newBuilder(desc, false, /* isUncommittedVersion */
+		catalog.PostDeserializationChanges{})
+}
+
+func newBuilder(
+	desc *descpb.FunctionDescriptor,
+	isUncommittedVersion bool,
+	changes catalog.PostDeserializationChanges,
+) FunctionDescriptorBuilder {
+	return &functionDescriptorBuilder{
+		original:             protoutil.Clone(desc).(*descpb.FunctionDescriptor),
+		isUncommittedVersion: isUncommittedVersion,
+		changes:              changes,
+	}
+}
+
+// DescriptorType implements the catalog.DescriptorBuilder interface.
+func (fdb *functionDescriptorBuilder) DescriptorType() catalog.DescriptorType {
+	return catalog.Function
+}
+
+// RunPostDeserializationChanges implements the catalog.DescriptorBuilder
+// interface.
+func (fdb *functionDescriptorBuilder) RunPostDeserializationChanges() error {
+	fdb.maybeModified = protoutil.Clone(fdb.original).(*descpb.FunctionDescriptor)
+	privsChanged := catprivilege.MaybeFixPrivileges(
+		&fdb.maybeModified.Privileges,
+		fdb.maybeModified.GetParentID(),
+		fdb.maybeModified.GetParentSchemaID(),
+		privilege.Function,
+		fdb.maybeModified.GetName(),
+	)
+	if privsChanged {
+		fdb.changes.Add(catalog.UpgradedPrivileges)
+	}
+	return nil
+}
+
+// RunRestoreChanges implements the catalog.DescriptorBuilder interface.
+func (fdb *functionDescriptorBuilder) RunRestoreChanges(
+	_ func(id descpb.ID) catalog.Descriptor,
+) error {
+	return nil
+}
+
+// BuildImmutable implements the catalog.DescriptorBuilder interface.
+func (fdb *functionDescriptorBuilder) BuildImmutable() catalog.Descriptor {
+	return fdb.BuildImmutableFunction()
+}
+
+// BuildImmutableFunction returns an immutable function descriptor.
+func (fdb *functionDescriptorBuilder) BuildImmutableFunction() catalog.FunctionDescriptor {
+	desc := fdb.maybeModified
+	if desc == nil {
+		desc = fdb.original
+	}
+	return &immutable{
+		FunctionDescriptor:   *desc,
+		changes:              fdb.changes,
+		isUncommittedVersion: fdb.isUncommittedVersion,
+	}
+}
+
+// BuildExistingMutable implements the catalog.DescriptorBuilder interface.
+func (fdb *functionDescriptorBuilder) BuildExistingMutable() catalog.MutableDescriptor {
+	return fdb.BuildExistingMutableFunction()
+}
+
+// BuildExistingMutableFunction returns a mutable descriptor for a function
+// which already exists.
+func (fdb *functionDescriptorBuilder) BuildExistingMutableFunction() *Mutable {
+	if fdb.maybeModified == nil {
+		fdb.maybeModified = protoutil.Clone(fdb.original).(*descpb.FunctionDescriptor)
+	}
+	return &Mutable{
+		immutable: immutable{
+			FunctionDescriptor:   *fdb.maybeModified,
+			changes:              fdb.changes,
+			isUncommittedVersion: fdb.isUncommittedVersion,
+		},
+		ClusterVersion: &immutable{FunctionDescriptor: *fdb.original},
+	}
+}
+
+// BuildCreatedMutable implements the catalog.DescriptorBuilder interface.
+func (fdb *functionDescriptorBuilder) BuildCreatedMutable() catalog.MutableDescriptor {
+	return fdb.BuildCreatedMutableFunction()
+}
+
+// BuildCreatedMutableFunction returns a mutable descriptor for a function
+// which is in the process of being created.
+func (fdb *functionDescriptorBuilder) BuildCreatedMutableFunction() *Mutable {
+	return &Mutable{
+		immutable: immutable{
+			FunctionDescriptor: *fdb.original,
+			changes:            fdb.changes,
+		},
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/internal/validate"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestValidateFunctionDesc(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	strPtr := func(s string) *string { return &s }
+	testData := []struct {
+		err  string
+		desc descpb.FunctionDescriptor
+	}{
+		{
+			`missing return type`,
+			descpb.FunctionDescriptor{
+				Body: "BEGIN RETURN 1; END",
+			},
+		},
+		{
+			`missing body`,
+			descpb.FunctionDescriptor{
+				ReturnType: types.Int,
+			},
+		},
+		{
+			`return type INT8 is not VOID`,
+			descpb.FunctionDescriptor{
+				ReturnType:  types.Int,
+				Body:        "BEGIN RETURN 1; END",
+				IsProcedure: true,
+			},
+		},
+		{
+			`trigger function has arguments`,
+			descpb.FunctionDescriptor{
+				Args:           []descpb.FunctionDescriptor_Argument{{Type: types.Int}},
+				ReturnType:     types.Void,
+				Body:           "BEGIN RETURN NULL; END",
+				ReturnsTrigger: true,
+			},
+		},
+		{
+			`argument 2 has no default but follows an argument with a default`,
+			descpb.FunctionDescriptor{
+				Args: []descpb.FunctionDescriptor_Argument{
+					{Name: "a", Type: types.Int, DefaultExpr: strPtr("1")},
+					{Name: "b", Type: types.Int},
+				},
+				ReturnType: types.Int,
+				Language:   descpb.FunctionDescriptor_SQL,
+				Body:       "SELECT a + b",
+			},
+		},
+		{
+			`missing aggregate state type`,
+			descpb.FunctionDescriptor{
+				Args:       []descpb.FunctionDescriptor_Argument{{Type: types.Int}},
+				ReturnType: types.Int,
+				Aggregate: &descpb.FunctionDescriptor_Aggregate{
+					TransitionExpr: "@1 + @2",
+				},
+			},
+		},
+		{
+			`missing aggregate transition expression`,
+			descpb.FunctionDescriptor{
+				Args:       []descpb.FunctionDescriptor_Argument{{Type: types.Int}},
+				ReturnType: types.Int,
+				Aggregate: &descpb.FunctionDescriptor_Aggregate{
+					StateType: types.Int,
+				},
+			},
+		},
+	}
+	for i, d := range testData {
+		t.Run(d.err, func(t *testing.T) {
+			d.desc.Name = "f"
+			d.desc.ID = 53
+			d.desc.ParentID = 51
+			d.desc.ParentSchemaID = 52
+			d.desc.Privileges = catpb.NewBasePrivilegeDescriptor(username.RootUserName())
+			desc := funcdesc.NewBuilder(&d.desc).BuildImmutable()
+			expectedErr := fmt.Sprintf("%s %q (%d): %s", desc.DescriptorType(), desc.GetName(), desc.GetID(), d.err)
+			if err := validate.Self(clusterversion.TestingClusterVersion, desc); err == nil {
+				t.Errorf("%d: expected \"%s\", but found success: %+v", i, expectedErr, d.desc)
+			} else if expectedErr != err.Error() {
+				t.Errorf("%d: expected \"%s\", but found \"%+v\"", i, expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCrossFunctionReferences(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	overloads := func(ids ...descpb.ID) map[string]descpb.SchemaDescriptor_Function {
+		fn := descpb.SchemaDescriptor_Function{Name: "f"}
+		for _, id := range ids {
+			fn.Overloads = append(fn.Overloads, descpb.SchemaDescriptor_Function_Overload{ID: id})
+		}
+		return map[string]descpb.SchemaDescriptor_Function{"f": fn}
+	}
+	tests := []struct {
+		err     string
+		dropped bool
+		scDesc  descpb.SchemaDescriptor
+	}{
+		{ // 0
+			scDesc: descpb.SchemaDescriptor{
+				ID:        52,
+				ParentID:  51,
+				Name:      "sc",
+				Functions: overloads(54, 53),
+			},
+		},
+		{ // 1
+			err: `not present in parent schema [52] functions mapping`,
+			scDesc: descpb.SchemaDescriptor{
+				ID:        52,
+				ParentID:  51,
+				Name:      "sc",
+				Functions: overloads(54),
+			},
+		},
+		{ // 2
+			dropped: true,
+			scDesc: descpb.SchemaDescriptor{
+				ID:       52,
+				ParentID: 51,
+				Name:     "sc",
+			},
+		},
+		{ // 3
+			err: `parent schema "sc" (52) is in database 500`,
+			scDesc: descpb.SchemaDescriptor{
+				ID:        52,
+				ParentID:  500,
+				Name:      "sc",
+				Functions: overloads(53),
+			},
+		},
+	}
+
+	for i, test := range tests {
+		privilege := catpb.NewBasePrivilegeDescriptor(username.AdminRoleName())
+		var cb nstree.MutableCatalog
+		fnDesc := descpb.FunctionDescriptor{
+			Name:           "f",
+			ID:             53,
+			ParentID:       51,
+			ParentSchemaID: 52,
+			ReturnType:     types.Int,
+			Body:           "BEGIN RETURN 1; END",
+			Privileges:     privilege,
+		}
+		if test.dropped {
+			fnDesc.State = descpb.DescriptorState_DROP
+		}
+		desc := funcdesc.NewBuilder(&fnDesc).BuildImmutable()
+		cb.UpsertDescriptorEntry(desc)
+		test.scDesc.Privileges = privilege
+		cb.UpsertDescriptorEntry(schemadesc.NewBuilder(&test.scDesc).BuildImmutable())
+		cb.UpsertDescriptorEntry(dbdesc.NewBuilder(&descpb.DatabaseDescriptor{
+			ID:         51,
+			Name:       "db",
+			Privileges: privilege,
+			Schemas: map[string]descpb.DatabaseDescriptor_SchemaInfo{
+				"sc": {ID: 52},
+			},
+		}).BuildImmutable())
+		expectedErr := fmt.Sprintf("%s %q (%d): %s", desc.DescriptorType(), desc.GetName(), desc.GetID(), test.err)
+		const validateCrossReferencesOnly = catalog.ValidationLevelCrossReferences &^ (catalog.ValidationLevelCrossReferences >> 1)
+		results := cb.Validate(ctx, clusterversion.TestingClusterVersion, catalog.NoValidationTelemetry, validateCrossReferencesOnly, desc)
+		if err := results.CombinedError(); err == nil {
+			if test.err != "" {
+				t.Errorf("%d: expected \"%s\", but found success: %+v", i, expectedErr, fnDesc)
+			}
+		} else if expectedErr != err.Error() {
+			t.Errorf("%d: expected \"%s\", but found \"%s\"", i, expectedErr, err.Error())
+		}
+	}
+}
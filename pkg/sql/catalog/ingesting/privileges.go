@@ -85,6 +85,13 @@ func GetIngestingDescriptorPrivileges(
 		if descCoverage == tree.RequestedDescriptors {
 			updatedPrivileges = catpb.NewBaseDatabasePrivilegeDescriptor(user)
 		}
+	case catalog.FunctionDescriptor:
+		// If the ingestion is not a cluster restore we cannot know that the users
+		// on the ingesting cluster match the ones that were on the cluster that was
+		// backed up. So we wipe the privileges on the function.
+		if descCoverage == tree.RequestedDescriptors {
+			updatedPrivileges = catpb.NewBasePrivilegeDescriptor(user)
+		}
 	}
 	return updatedPrivileges, nil
 }
@@ -218,6 +218,8 @@ func (vea *validationErrorAccumulator) decorate(err error) error {
 			err = errors.Wrapf(err, catalog.Schema+" %q (%d)", name, id)
 		case catalog.Type:
 			err = errors.Wrapf(err, catalog.Type+" %q (%d)", name, id)
+		case catalog.Function:
+			err = errors.Wrapf(err, catalog.Function+" %q (%d)", name, id)
 		default:
 			return err
 		}
@@ -306,7 +308,7 @@ func (vdg *validationDescGetterImpl) addNamespaceEntries(
 ) error {
 	reqs := make([]descpb.NameInfo, 0, len(descriptors))
 	for _, desc := range descriptors {
-		if desc == nil {
+		if desc == nil || desc.DescriptorType() == catalog.Function {
 			continue
 		}
 		reqs = append(reqs, descpb.NameInfo{
@@ -425,6 +427,12 @@ func validateNamespace(
 	if desc.GetID() == keys.NamespaceTableID || desc.GetID() == keys.DeprecatedNamespaceTableID {
 		return
 	}
+	// Functions have no namespace entries: they are found through the functions
+	// mapping of their schema, which is checked when validating their cross
+	// references.
+	if desc.DescriptorType() == catalog.Function {
+		return
+	}
 
 	key := descpb.NameInfo{
 		ParentID:       desc.GetParentID(),
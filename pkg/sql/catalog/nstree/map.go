@@ -33,13 +33,16 @@ type Map struct {
 type EntryIterator func(entry catalog.NameEntry) error
 
 // Upsert adds the descriptor to the tree. If any descriptor exists in the
-// tree with the same name or id, it will be removed.
+// tree with the same name or id, it will be removed. Function descriptors are
+// only indexed by id, see isNamed.
 func (dt *Map) Upsert(d catalog.NameEntry) {
 	dt.maybeInitialize()
-	if replaced := dt.byName.upsert(d); replaced != nil {
-		dt.byID.delete(replaced.GetID())
+	if isNamed(d) {
+		if replaced := dt.byName.upsert(d); replaced != nil {
+			dt.byID.delete(replaced.GetID())
+		}
 	}
-	if replaced := dt.byID.upsert(d); replaced != nil {
+	if replaced := dt.byID.upsert(d); replaced != nil && isNamed(replaced) {
 		dt.byName.delete(replaced)
 	}
 }
@@ -49,12 +52,23 @@ func (dt *Map) Upsert(d catalog.NameEntry) {
 func (dt *Map) Remove(id descpb.ID) catalog.NameEntry {
 	dt.maybeInitialize()
 	if d := dt.byID.delete(id); d != nil {
-		dt.byName.delete(d)
+		if isNamed(d) {
+			dt.byName.delete(d)
+		}
 		return d
 	}
 	return nil
 }
 
+// isNamed returns false for the function descriptors, which have no
+// namespace entry since the overloads of a function share the same name.
+// They are not indexed by name, so that they neither collide with each other
+// nor with the other objects of their schema.
+func isNamed(e catalog.NameEntry) bool {
+	d, ok := e.(catalog.Descriptor)
+	return !ok || d.DescriptorType() != catalog.Function
+}
+
 // GetByID gets a descriptor from the tree by id.
 func (dt *Map) GetByID(id descpb.ID) catalog.NameEntry {
 	if !dt.initialized() {
@@ -142,6 +142,20 @@ func TableDescs(
 					table.Name, dest)
 			}
 		}
+		if ir := table.IncrementalRefresh; ir != nil {
+			for i := range ir.Sources {
+				src := &ir.Sources[i]
+				if depRewrite, ok := descriptorRewrites[src.TableID]; ok {
+					src.TableID = depRewrite.ID
+				} else {
+					// The sources are a subset of the dependencies, which were checked
+					// above.
+					return errors.AssertionFailedf(
+						"cannot restore %q because referenced table %d was not found",
+						table.Name, src.TableID)
+				}
+			}
+		}
 		for i, dest := range table.DependsOnTypes {
 			if depRewrite, ok := descriptorRewrites[dest]; ok {
 				table.DependsOnTypes[i] = depRewrite.ID
@@ -153,6 +167,18 @@ func TableDescs(
 					table.Name, dest)
 			}
 		}
+		for i := range table.Triggers {
+			trig := &table.Triggers[i]
+			origDeps := trig.DependsOn
+			trig.DependsOn = nil
+			for _, id := range origDeps {
+				// Like back-references, the dependencies of triggers are dropped if
+				// the relation is not being restored.
+				if depRewrite, ok := descriptorRewrites[id]; ok {
+					trig.DependsOn = append(trig.DependsOn, depRewrite.ID)
+				}
+			}
+		}
 		origRefs := table.DependedOnBy
 		table.DependedOnBy = nil
 		for _, ref := range origRefs {
@@ -26,6 +26,14 @@ type SchemaDescriptor interface {
 	// GetDefaultPrivilegeDescriptor returns the default privileges for this
 	// database.
 	GetDefaultPrivilegeDescriptor() DefaultPrivilegeDescriptor
+
+	// GetFunction returns the overloads of the user-defined functions of the
+	// schema with the given name.
+	GetFunction(name string) (descpb.SchemaDescriptor_Function, bool)
+
+	// ForEachFunctionOverload iterates over the overloads of the user-defined
+	// functions of the schema, in the order of their names.
+	ForEachFunctionOverload(f func(name string, overload descpb.SchemaDescriptor_Function_Overload) error) error
 }
 
 // ResolvedSchemaKind is an enum that represents what kind of schema
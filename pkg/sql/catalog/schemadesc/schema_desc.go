@@ -13,6 +13,7 @@ package schemadesc
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -26,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -191,6 +194,21 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		// Validate the default privilege descriptor.
 		vea.Report(catprivilege.ValidateDefaultPrivileges(*desc.GetDefaultPrivileges()))
 	}
+
+	// Validate the functions mapping.
+	for name, fn := range desc.Functions {
+		if fn.Name != name {
+			vea.Report(errors.AssertionFailedf("function %q is mapped under name %q", fn.Name, name))
+		}
+		if len(fn.Overloads) == 0 {
+			vea.Report(errors.AssertionFailedf("function %q has no overloads", name))
+		}
+		for _, o := range fn.Overloads {
+			if o.ID == descpb.InvalidID {
+				vea.Report(errors.AssertionFailedf("invalid function ID %d for function %q", o.ID, name))
+			}
+		}
+	}
 }
 
 // GetReferencedDescIDs returns the IDs of all descriptors referenced by
@@ -261,6 +279,31 @@ func (desc *immutable) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeD
 	return catprivilege.MakeDefaultPrivileges(defaultPrivilegeDescriptor)
 }
 
+// GetFunction implements the SchemaDescriptor interface.
+func (desc *immutable) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	fn, ok := desc.Functions[name]
+	return fn, ok
+}
+
+// ForEachFunctionOverload implements the SchemaDescriptor interface.
+func (desc *immutable) ForEachFunctionOverload(
+	f func(name string, overload descpb.SchemaDescriptor_Function_Overload) error,
+) error {
+	names := make([]string, 0, len(desc.Functions))
+	for name := range desc.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, o := range desc.Functions[name].Overloads {
+			if err := f(name, o); err != nil {
+				return iterutil.Map(err)
+			}
+		}
+	}
+	return nil
+}
+
 // GetPostDeserializationChanges implements the Descriptor interface.
 func (desc *immutable) GetPostDeserializationChanges() catalog.PostDeserializationChanges {
 	return desc.changes
@@ -339,6 +382,42 @@ func (desc *Mutable) SetName(name string) {
 	desc.Name = name
 }
 
+// AddFunction adds the overload of a function with the given name and
+// argument types to the functions of the schema.
+func (desc *Mutable) AddFunction(name string, id descpb.ID, argTypes []*types.T) {
+	if desc.Functions == nil {
+		desc.Functions = make(map[string]descpb.SchemaDescriptor_Function)
+	}
+	fn := desc.Functions[name]
+	fn.Name = name
+	fn.Overloads = append(fn.Overloads, descpb.SchemaDescriptor_Function_Overload{
+		ID:       id,
+		ArgTypes: argTypes,
+	})
+	desc.Functions[name] = fn
+}
+
+// RemoveFunction removes the overload of a function with the given name and
+// ID from the functions of the schema.
+func (desc *Mutable) RemoveFunction(name string, id descpb.ID) {
+	fn, ok := desc.Functions[name]
+	if !ok {
+		return
+	}
+	overloads := fn.Overloads[:0]
+	for _, o := range fn.Overloads {
+		if o.ID != id {
+			overloads = append(overloads, o)
+		}
+	}
+	if len(overloads) == 0 {
+		delete(desc.Functions, name)
+		return
+	}
+	fn.Overloads = overloads
+	desc.Functions[name] = fn
+}
+
 // IsUncommittedVersion implements the Descriptor interface.
 func (desc *Mutable) IsUncommittedVersion() bool {
 	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
@@ -140,6 +140,19 @@ func (p synthetic) GetPrivilegeDescriptor(
 	return p.GetPrivileges(), nil
 }
 
+// GetFunction implements the SchemaDescriptor interface. Synthetic schemas
+// have no user-defined functions.
+func (p synthetic) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	return descpb.SchemaDescriptor_Function{}, false
+}
+
+// ForEachFunctionOverload implements the SchemaDescriptor interface.
+func (p synthetic) ForEachFunctionOverload(
+	f func(name string, overload descpb.SchemaDescriptor_Function_Overload) error,
+) error {
+	return nil
+}
+
 // GetDefaultPrivilegeDescriptor returns a DefaultPrivilegeDescriptor.
 func (p synthetic) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeDescriptor {
 	return catprivilege.MakeDefaultPrivileges(catprivilege.MakeDefaultPrivilegeDescriptor(catpb.DefaultPrivilegeDescriptor_SCHEMA))
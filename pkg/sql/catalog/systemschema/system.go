@@ -664,6 +664,59 @@ CREATE TABLE system.privileges (
 	CONSTRAINT "primary" PRIMARY KEY (username, path),
 	FAMILY "primary" (username, path, privileges, grant_options)
 );`
+
+	// notifications stores the notifications sent with NOTIFY. The rows are
+	// delivered to the sessions that listen on the channel through a rangefeed
+	// over the table, and are deleted shortly after they are written. Each
+	// notification is identified by the ID of the transaction which sent it
+	// and by its sequence number in the transaction, which allows the
+	// listeners to ignore the rows emitted more than once by the rangefeed.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+	id      INT8      NOT NULL DEFAULT unique_rowid(),
+	channel STRING    NOT NULL,
+	payload STRING    NOT NULL,
+	pid     INT8      NOT NULL,
+	created TIMESTAMP NOT NULL DEFAULT now(),
+	txn_id  UUID      NOT NULL,
+	seq     INT8      NOT NULL,
+	CONSTRAINT "primary" PRIMARY KEY (id),
+	FAMILY "primary" (id, channel, payload, pid, created, txn_id, seq)
+);`
+
+	// advisory_locks stores the advisory locks held by the sessions of the
+	// cluster. Each row is held by a session, identified by its cluster-wide
+	// ID, and by the sqlliveness session of the SQL instance serving it, which
+	// allows other sessions to take over the locks of a SQL instance which
+	// died.
+	AdvisoryLocksTableSchema = `
+CREATE TABLE system.advisory_locks (
+	lock_key            INT8      NOT NULL,
+	key_pair            BOOL      NOT NULL,
+	session_id          BYTES     NOT NULL,
+	xact                BOOL      NOT NULL,
+	shared              BOOL      NOT NULL,
+	count               INT8      NOT NULL,
+	liveness_session_id BYTES     NOT NULL,
+	acquired            TIMESTAMP NOT NULL DEFAULT now(),
+	CONSTRAINT "primary" PRIMARY KEY (lock_key, key_pair, session_id, xact, shared),
+	INDEX (session_id),
+	FAMILY "primary" (lock_key, key_pair, session_id, xact, shared, count, liveness_session_id, acquired)
+);`
+
+	// replication_slots stores the logical replication slots, and the LSN up to
+	// which their subscriber confirmed that it flushed the changes, from which
+	// the changes are streamed again when replication is restarted.
+	ReplicationSlotsTableSchema = `
+CREATE TABLE system.replication_slots (
+	slot_name           STRING    NOT NULL,
+	plugin              STRING    NOT NULL,
+	database_id         INT8      NOT NULL,
+	confirmed_flush_lsn INT8      NOT NULL,
+	created             TIMESTAMP NOT NULL DEFAULT now(),
+	CONSTRAINT "primary" PRIMARY KEY (slot_name),
+	FAMILY "primary" (slot_name, plugin, database_id, confirmed_flush_lsn, created)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2424,6 +2477,111 @@ var (
 			},
 		),
 	)
+
+	// NotificationsTable is the descriptor for the notifications table.
+	NotificationsTable = registerSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "channel", ID: 2, Type: types.String},
+				{Name: "payload", ID: 3, Type: types.String},
+				{Name: "pid", ID: 4, Type: types.Int},
+				{Name: "created", ID: 5, Type: types.Timestamp, DefaultExpr: &nowString},
+				{Name: "txn_id", ID: 6, Type: types.Uuid},
+				{Name: "seq", ID: 7, Type: types.Int},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"id", "channel", "payload", "pid", "created", "txn_id", "seq"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+			pk("id"),
+		),
+	)
+
+	// AdvisoryLocksTable is the descriptor for the advisory_locks table.
+	AdvisoryLocksTable = registerSystemTable(
+		AdvisoryLocksTableSchema,
+		systemTable(
+			catconstants.AdvisoryLocksTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "lock_key", ID: 1, Type: types.Int},
+				{Name: "key_pair", ID: 2, Type: types.Bool},
+				{Name: "session_id", ID: 3, Type: types.Bytes},
+				{Name: "xact", ID: 4, Type: types.Bool},
+				{Name: "shared", ID: 5, Type: types.Bool},
+				{Name: "count", ID: 6, Type: types.Int},
+				{Name: "liveness_session_id", ID: 7, Type: types.Bytes},
+				{Name: "acquired", ID: 8, Type: types.Timestamp, DefaultExpr: &nowString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name: "primary",
+					ID:   0,
+					ColumnNames: []string{
+						"lock_key", "key_pair", "session_id", "xact", "shared",
+						"count", "liveness_session_id", "acquired",
+					},
+					ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7, 8},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:           "primary",
+				ID:             1,
+				Unique:         true,
+				KeyColumnNames: []string{"lock_key", "key_pair", "session_id", "xact", "shared"},
+				KeyColumnDirections: []catpb.IndexColumn_Direction{
+					catpb.IndexColumn_ASC, catpb.IndexColumn_ASC, catpb.IndexColumn_ASC,
+					catpb.IndexColumn_ASC, catpb.IndexColumn_ASC,
+				},
+				KeyColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5},
+			},
+			descpb.IndexDescriptor{
+				Name:                "advisory_locks_session_id_idx",
+				ID:                  2,
+				Unique:              false,
+				KeyColumnNames:      []string{"session_id"},
+				KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{3},
+				KeySuffixColumnIDs:  []descpb.ColumnID{1, 2, 4, 5},
+				Version:             descpb.StrictIndexColumnIDGuaranteesVersion,
+			},
+		),
+	)
+
+	// ReplicationSlotsTable is the descriptor for the replication_slots table.
+	ReplicationSlotsTable = registerSystemTable(
+		ReplicationSlotsTableSchema,
+		systemTable(
+			catconstants.ReplicationSlotsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "slot_name", ID: 1, Type: types.String},
+				{Name: "plugin", ID: 2, Type: types.String},
+				{Name: "database_id", ID: 3, Type: types.Int},
+				{Name: "confirmed_flush_lsn", ID: 4, Type: types.Int},
+				{Name: "created", ID: 5, Type: types.Timestamp, DefaultExpr: &nowString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name: "primary",
+					ID:   0,
+					ColumnNames: []string{
+						"slot_name", "plugin", "database_id", "confirmed_flush_lsn", "created",
+					},
+					ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5},
+				},
+			},
+			pk("slot_name"),
+		),
+	)
 )
 
 type descRefByName struct {
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 )
 
 // TableElementMaybeMutation is an interface used as a subtype for the various
@@ -143,6 +144,7 @@ type Index interface {
 	GetPredicate() string
 	GetType() descpb.IndexDescriptor_Type
 	GetGeoConfig() geoindex.Config
+	GetVectorConfig() vecindex.Config
 	GetVersion() descpb.IndexDescriptorVersion
 	GetEncodingType() descpb.IndexDescriptorEncodingType
 
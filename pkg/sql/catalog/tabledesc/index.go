@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -235,6 +236,11 @@ func (w index) GetGeoConfig() geoindex.Config {
 	return w.desc.GeoConfig
 }
 
+// GetVectorConfig returns the vector config in the index descriptor.
+func (w index) GetVectorConfig() vecindex.Config {
+	return w.desc.VectorConfig
+}
+
 // GetSharded returns the ShardedDescriptor in the index descriptor
 func (w index) GetSharded() catpb.ShardedDescriptor {
 	return w.desc.Sharded
@@ -1243,6 +1243,49 @@ func (desc *Mutable) removeColumnFromFamily(colID descpb.ColumnID) {
 	}
 }
 
+// ChangeColumnPrivileges calls fn with the column-level privileges of the
+// column with the given ID, and stores the privileges once fn returns. It
+// returns true if fn changed the privileges.
+func (desc *Mutable) ChangeColumnPrivileges(
+	colID descpb.ColumnID, fn func(privs *catpb.PrivilegeDescriptor),
+) (changed bool) {
+	idx := sort.Search(len(desc.ColumnPrivileges), func(i int) bool {
+		return desc.ColumnPrivileges[i].ColumnID >= colID
+	})
+	found := idx < len(desc.ColumnPrivileges) && desc.ColumnPrivileges[idx].ColumnID == colID
+	var before, privs catpb.PrivilegeDescriptor
+	if found {
+		before.Users = desc.ColumnPrivileges[idx].Users
+		privs.Users = append([]catpb.UserPrivileges(nil), before.Users...)
+	}
+	fn(&privs)
+	if privs.Equal(&before) {
+		return false
+	}
+	switch {
+	case len(privs.Users) == 0:
+		desc.ColumnPrivileges = append(desc.ColumnPrivileges[:idx], desc.ColumnPrivileges[idx+1:]...)
+	case found:
+		desc.ColumnPrivileges[idx].Users = privs.Users
+	default:
+		desc.ColumnPrivileges = append(desc.ColumnPrivileges, descpb.TableDescriptor_ColumnPrivileges{})
+		copy(desc.ColumnPrivileges[idx+1:], desc.ColumnPrivileges[idx:])
+		desc.ColumnPrivileges[idx] = descpb.TableDescriptor_ColumnPrivileges{
+			ColumnID: colID,
+			Users:    privs.Users,
+		}
+	}
+	return true
+}
+
+// RemoveColumnPrivileges removes the column-level privileges of the column
+// with the given ID.
+func (desc *Mutable) RemoveColumnPrivileges(colID descpb.ColumnID) {
+	desc.ChangeColumnPrivileges(colID, func(privs *catpb.PrivilegeDescriptor) {
+		privs.Users = nil
+	})
+}
+
 // RenameColumnDescriptor updates all references to a column name in
 // a table descriptor including indexes and families.
 func (desc *Mutable) RenameColumnDescriptor(column catalog.Column, newColName string) {
@@ -1432,6 +1475,19 @@ func (desc *Mutable) DropConstraint(
 			}
 		}
 
+	case descpb.ConstraintTypeExclusion:
+		// EXCLUDE constraints are never queued as mutations, and nodes that
+		// still enforce the constraint after it was dropped don't affect
+		// correctness, so the constraint is dropped immediately.
+		for i := range desc.ExclusionConstraints {
+			if desc.ExclusionConstraints[i].Name == name {
+				desc.ExclusionConstraints = append(
+					desc.ExclusionConstraints[:i], desc.ExclusionConstraints[i+1:]...,
+				)
+				return nil
+			}
+		}
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("drop-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(name))
@@ -1535,6 +1591,10 @@ func (desc *Mutable) RenameConstraint(
 		detail.CheckConstraint.Name = newName
 		return nil
 
+	case descpb.ConstraintTypeExclusion:
+		detail.ExclusionConstraint.Name = newName
+		return nil
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(oldName))
@@ -1783,6 +1843,9 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				desc.LastRefreshTime = t.MaterializedViewRefresh.AsOf
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
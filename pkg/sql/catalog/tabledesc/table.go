@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
@@ -155,6 +156,34 @@ func MakeColumnDefDescs(
 	if err = colinfo.ValidateColumnDefType(resType); err != nil {
 		return nil, err
 	}
+	if isTSearchType(resType) {
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.TSearchTypes) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use type %s",
+				clusterversion.ByKey(clusterversion.TSearchTypes), resType.SQLString())
+		}
+	}
+	if isRangeType(resType) {
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.RangeTypes) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use type %s",
+				clusterversion.ByKey(clusterversion.RangeTypes), resType.SQLString())
+		}
+	}
+	if isJsonpathType(resType) {
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.JsonpathType) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use type %s",
+				clusterversion.ByKey(clusterversion.JsonpathType), resType.SQLString())
+		}
+	}
+	if isPGVectorType(resType) {
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.PGVectorType) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use type %s",
+				clusterversion.ByKey(clusterversion.PGVectorType), resType.SQLString())
+		}
+	}
 	col.Type = resType
 
 	if d.HasDefaultExpr() {
@@ -235,6 +264,46 @@ func MakeColumnDefDescs(
 	return ret, nil
 }
 
+// isTSearchType returns whether t is a full text search type, or an array of
+// one. Columns of these types can only be created once the cluster version
+// gating them is active.
+func isTSearchType(t *types.T) bool {
+	if t.Family() == types.ArrayFamily {
+		t = t.ArrayContents()
+	}
+	return t.Family() == types.TSQueryFamily || t.Family() == types.TSVectorFamily
+}
+
+// isRangeType returns whether t is a range type, or an array of one. Columns
+// of these types can only be created once the cluster version gating them is
+// active.
+func isRangeType(t *types.T) bool {
+	if t.Family() == types.ArrayFamily {
+		t = t.ArrayContents()
+	}
+	return t.Family() == types.RangeFamily
+}
+
+// isJsonpathType returns whether t is the jsonpath type, or an array of it.
+// Columns of these types can only be created once the cluster version gating
+// them is active.
+func isJsonpathType(t *types.T) bool {
+	if t.Family() == types.ArrayFamily {
+		t = t.ArrayContents()
+	}
+	return t.Family() == types.JsonpathFamily
+}
+
+// isPGVectorType returns whether t is the vector type, or an array of it.
+// Columns of these types can only be created once the cluster version gating
+// them is active.
+func isPGVectorType(t *types.T) bool {
+	if t.Family() == types.ArrayFamily {
+		t = t.ArrayContents()
+	}
+	return t.Family() == types.PGVectorFamily
+}
+
 // EvalShardBucketCount evaluates and checks the integer argument to a `USING HASH WITH
 // BUCKET_COUNT` index creation query.
 func EvalShardBucketCount(
@@ -479,6 +548,28 @@ func (desc *wrapper) collectConstraintInfo(
 		}
 		info[c.Name] = detail
 	}
+
+	for i := range desc.ExclusionConstraints {
+		ec := &desc.ExclusionConstraints[i]
+		if _, ok := info[ec.Name]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate constraint name: %q", ec.Name)
+		}
+		detail := descpb.ConstraintDetail{
+			Kind:         descpb.ConstraintTypeExclusion,
+			ConstraintID: ec.ConstraintID,
+		}
+		// Constraints in the Validating state are considered Unvalidated for this
+		// purpose.
+		detail.Unvalidated = ec.Validity != descpb.ConstraintValidity_Validated
+		var err error
+		detail.Columns, err = desc.NamesForColumnIDs(ec.ColumnIDs)
+		if err != nil {
+			return nil, err
+		}
+		detail.ExclusionConstraint = ec
+		info[ec.Name] = detail
+	}
 	return info, nil
 }
 
@@ -692,6 +783,19 @@ func RenameColumnInTable(
 		}
 	}
 
+	// Rename the column in row-level security policies.
+	for i := range tableDesc.Policies {
+		p := &tableDesc.Policies[i]
+		for _, expr := range []*string{&p.UsingExpr, &p.WithCheckExpr} {
+			if *expr == "" {
+				continue
+			}
+			if err := renameInExpr(expr); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Do all of the above renames inside check constraints, computed expressions,
 	// and idx predicates that are in mutations.
 	for i := range tableDesc.Mutations {
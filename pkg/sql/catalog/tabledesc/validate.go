@@ -120,6 +120,11 @@ func (desc *wrapper) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, ref := range desc.GetDependedOnBy() {
 		ids.Add(ref.ID)
 	}
+	for i := range desc.Triggers {
+		for _, id := range desc.Triggers[i].DependsOn {
+			ids.Add(id)
+		}
+	}
 	// Add sequence dependencies
 	return ids, nil
 }
@@ -188,6 +193,14 @@ func (desc *wrapper) ValidateCrossReferences(
 		vea.Report(desc.validateInboundTableRef(by, vdg))
 	}
 
+	// Check the relations which the triggers depend on.
+	for i := range desc.Triggers {
+		for _, id := range desc.Triggers[i].DependsOn {
+			vea.Report(errors.Wrapf(desc.validateOutboundTableRef(id, vdg),
+				"trigger %q", desc.Triggers[i].Name))
+		}
+	}
+
 	// For row-level TTL, only ascending PKs are permitted.
 	if desc.HasRowLevelTTL() {
 		rowLevelTTL := desc.RowLevelTTL
@@ -344,6 +357,27 @@ func (desc *wrapper) validateInboundTableRef(
 		}
 	}
 
+	if by.IncrementalRefresh && backReferencedTable.GetIncrementalRefresh() == nil {
+		return errors.AssertionFailedf(
+			"depended-on-by relation %q (%d) is not an incrementally maintained materialized view",
+			backReferencedTable.GetName(), by.ID)
+	}
+
+	// Trigger back-references need a corresponding forward reference in one of
+	// the triggers.
+	if by.Trigger {
+		for _, trig := range backReferencedTable.GetTriggers() {
+			for _, id := range trig.DependsOn {
+				if id == desc.GetID() {
+					return nil
+				}
+			}
+		}
+		return errors.AssertionFailedf(
+			"depended-on-by relation %q (%d) has no trigger which depends on this relation",
+			backReferencedTable.GetName(), by.ID)
+	}
+
 	// View back-references need corresponding forward reference.
 	if !backReferencedTable.IsView() {
 		return nil
@@ -545,6 +579,22 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 
 	desc.validateAutoStatsSettings(vea)
 
+	if foreign := desc.GetForeign(); foreign != nil {
+		if !desc.IsTable() {
+			vea.Report(errors.AssertionFailedf("foreign table cannot be a view or a sequence"))
+		}
+		if len(foreign.Files) == 0 {
+			vea.Report(errors.AssertionFailedf("foreign table has no files"))
+		}
+		if len(desc.Indexes) > 0 {
+			vea.Report(errors.AssertionFailedf("foreign table cannot have secondary indexes"))
+		}
+	}
+
+	if ir := desc.GetIncrementalRefresh(); ir != nil {
+		desc.validateIncrementalRefresh(ir, vea)
+	}
+
 	if desc.IsSequence() {
 		return
 	}
@@ -629,6 +679,10 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateColumnFamilies(columnIDs),
 			desc.validateCheckConstraints(columnIDs),
 			desc.validateUniqueWithoutIndexConstraints(columnIDs),
+			desc.validateExclusionConstraints(columnIDs),
+			desc.validateTriggers(),
+			desc.validatePolicies(),
+			desc.validateColumnPrivileges(columnIDs),
 			desc.validateTableIndexes(columnNames, vea),
 			desc.validatePartitioning(),
 		}
@@ -1005,6 +1059,122 @@ func (desc *wrapper) validateCheckConstraints(
 	return nil
 }
 
+// validateTriggers validates that triggers are well formed. Checks include
+// validating that trigger names are unique, that each trigger executes either a
+// function or statements, and that the statements in the body of each trigger
+// can be parsed.
+func (desc *wrapper) validateTriggers() error {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trig := &desc.Triggers[i]
+		if trig.Name == "" {
+			return pgerror.Newf(pgcode.Syntax, "empty trigger name")
+		}
+		if _, ok := names[trig.Name]; ok {
+			return errors.Newf("duplicate trigger name: %q", trig.Name)
+		}
+		names[trig.Name] = struct{}{}
+		if len(trig.Events) == 0 {
+			return errors.Newf("trigger %q has no events", trig.Name)
+		}
+		if trig.FunctionID != descpb.InvalidID {
+			if len(trig.Body) > 0 || len(trig.DependsOn) > 0 {
+				return errors.Newf("trigger %q has both a function and a body", trig.Name)
+			}
+		} else if len(trig.Body) == 0 {
+			return errors.Newf("trigger %q has an empty body", trig.Name)
+		}
+		for _, sql := range trig.Body {
+			if _, err := parser.ParseOne(sql); err != nil {
+				return errors.Wrapf(err, "trigger %q", trig.Name)
+			}
+		}
+		for _, id := range trig.DependsOn {
+			if id == desc.ID {
+				return errors.AssertionFailedf("trigger %q depends on its own table", trig.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePolicies validates that row-level security policies are well formed.
+// Checks include validating that policy names are unique, that each policy
+// applies to some roles and that the expressions of each policy only reference
+// columns of the table.
+func (desc *wrapper) validatePolicies() error {
+	names := make(map[string]struct{}, len(desc.Policies))
+	for i := range desc.Policies {
+		p := &desc.Policies[i]
+		if err := catalog.ValidateName(p.Name, "policy"); err != nil {
+			return err
+		}
+		if _, ok := names[p.Name]; ok {
+			return errors.Newf("duplicate policy name: %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if len(p.RoleNames) == 0 {
+			return errors.Newf("policy %q does not apply to any role", p.Name)
+		}
+		for _, exprStr := range []string{p.UsingExpr, p.WithCheckExpr} {
+			if exprStr == "" {
+				continue
+			}
+			expr, err := parser.ParseExpr(exprStr)
+			if err != nil {
+				return errors.Wrapf(err, "policy %q", p.Name)
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return errors.Newf(
+					"policy %q refers to unknown columns in expression: %s", p.Name, exprStr,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// validateColumnPrivileges validates that column-level privileges are well
+// formed. Checks include validating the column IDs, that the columns and the
+// users of each column are sorted, and that only privileges which can be
+// granted on columns are present.
+func (desc *wrapper) validateColumnPrivileges(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	validPrivs := privilege.ColumnPrivileges.ToBitField()
+	for i := range desc.ColumnPrivileges {
+		cp := &desc.ColumnPrivileges[i]
+		if _, ok := columnIDs[cp.ColumnID]; !ok {
+			return errors.Newf("column privileges refer to unknown column ID %d", cp.ColumnID)
+		}
+		if i > 0 && desc.ColumnPrivileges[i-1].ColumnID >= cp.ColumnID {
+			return errors.Newf("column privileges are not sorted by column ID")
+		}
+		if len(cp.Users) == 0 {
+			return errors.Newf("column ID %d has empty column privileges", cp.ColumnID)
+		}
+		for j := range cp.Users {
+			u := &cp.Users[j]
+			if j > 0 && !cp.Users[j-1].User().LessThan(u.User()) {
+				return errors.Newf("users of column ID %d are not sorted", cp.ColumnID)
+			}
+			if u.Privileges == 0 || u.Privileges&^validPrivs != 0 {
+				return errors.Newf("user %s has invalid privileges on column ID %d: %s",
+					u.User(), cp.ColumnID, privilege.ListFromBitField(u.Privileges, privilege.Table))
+			}
+			if u.WithGrantOption&^u.Privileges != 0 {
+				return errors.Newf("user %s has grant options without privileges on column ID %d",
+					u.User(), cp.ColumnID)
+			}
+		}
+	}
+	return nil
+}
+
 // validateUniqueWithoutIndexConstraints validates that unique without index
 // constraints are well formed. Checks include validating the column IDs and
 // column names.
@@ -1063,6 +1233,59 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateExclusionConstraints validates that EXCLUDE constraints are well
+// formed. Checks include validating the column IDs, the operators and the
+// predicate of each constraint.
+func (desc *wrapper) validateExclusionConstraints(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	for i := range desc.ExclusionConstraints {
+		c := &desc.ExclusionConstraints[i]
+		if err := catalog.ValidateName(c.Name, "exclusion constraint"); err != nil {
+			return err
+		}
+		if len(c.ColumnIDs) == 0 {
+			return errors.Newf("exclusion constraint %q has no columns", c.Name)
+		}
+		if len(c.Operators) != len(c.ColumnIDs) {
+			return errors.Newf(
+				"exclusion constraint %q has %d columns but %d operators",
+				c.Name, len(c.ColumnIDs), len(c.Operators),
+			)
+		}
+		for j, colID := range c.ColumnIDs {
+			if _, ok := columnIDs[colID]; !ok {
+				return errors.Newf(
+					"exclusion constraint %q contains unknown column \"%d\"", c.Name, colID,
+				)
+			}
+			if op := c.Operators[j]; op != "=" && op != "&&" {
+				return errors.Newf(
+					"exclusion constraint %q contains unsupported operator %q", c.Name, op,
+				)
+			}
+		}
+		if c.Predicate != "" {
+			expr, err := parser.ParseExpr(c.Predicate)
+			if err != nil {
+				return err
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return errors.Newf(
+					"exclusion constraint %q refers to unknown columns in predicate: %s",
+					c.Name,
+					c.Predicate,
+				)
+			}
+		}
+	}
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -1544,6 +1767,43 @@ func (desc *wrapper) validatePartitioning() error {
 	})
 }
 
+// validateIncrementalRefresh validates the description of how an incrementally
+// maintained materialized view is maintained.
+func (desc *wrapper) validateIncrementalRefresh(
+	ir *descpb.TableDescriptor_IncrementalRefresh, vea catalog.ValidationErrorAccumulator,
+) {
+	if !desc.MaterializedView() {
+		vea.Report(errors.AssertionFailedf("only materialized views can be maintained incrementally"))
+		return
+	}
+	var dependsOn catalog.DescriptorIDSet
+	for _, id := range desc.DependsOn {
+		dependsOn.Add(id)
+	}
+	checkColumn := func(id descpb.ColumnID) {
+		if col, _ := desc.FindColumnWithID(id); col == nil || !col.IsHidden() {
+			vea.Report(errors.AssertionFailedf(
+				"incremental refresh column ID %d is not a hidden column of the view", id))
+		}
+	}
+	for _, src := range ir.Sources {
+		if !dependsOn.Contains(src.TableID) {
+			vea.Report(errors.AssertionFailedf(
+				"source table %d of incrementally maintained materialized view is not in depends-on references",
+				src.TableID))
+		}
+		for _, id := range src.KeyColumnIDs {
+			checkColumn(id)
+		}
+	}
+	if ir.CountColumnID != 0 {
+		checkColumn(ir.CountColumnID)
+	}
+	for _, id := range ir.NonNullCountColumnIDs {
+		checkColumn(id)
+	}
+}
+
 // validateAutoStatsSettings validates that any new settings in
 // catpb.AutoStatsSettings hold a valid value.
 func (desc *wrapper) validateAutoStatsSettings(vea catalog.ValidationErrorAccumulator) {
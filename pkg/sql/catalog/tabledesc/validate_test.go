@@ -132,6 +132,15 @@ var validationMap = []struct {
 			"DeclarativeSchemaChangerState": {status: iSolemnlySwearThisFieldIsValidated},
 			"AutoStatsSettings":             {status: iSolemnlySwearThisFieldIsValidated},
 			"ForecastStats":                 {status: thisFieldReferencesNoObjects},
+			"Triggers":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"ExclusionConstraints":          {status: iSolemnlySwearThisFieldIsValidated},
+			"Policies":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelSecurity":              {status: thisFieldReferencesNoObjects},
+			"ForceRowLevelSecurity":         {status: thisFieldReferencesNoObjects},
+			"ColumnPrivileges":              {status: iSolemnlySwearThisFieldIsValidated},
+			"Foreign":                       {status: iSolemnlySwearThisFieldIsValidated},
+			"IncrementalRefresh":            {status: iSolemnlySwearThisFieldIsValidated},
+			"LastRefreshTime":               {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -1184,6 +1193,248 @@ func TestValidateTableDesc(t *testing.T) {
 					},
 				},
 			}},
+		{`duplicate trigger name: "trig"`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				Triggers: []descpb.TableDescriptor_Trigger{
+					{
+						Name:   "trig",
+						Events: []descpb.TableDescriptor_Trigger_Event{descpb.TableDescriptor_Trigger_INSERT},
+						Body:   []string{"INSERT INTO t VALUES (new.bar)"},
+					},
+					{
+						Name:   "trig",
+						Events: []descpb.TableDescriptor_Trigger_Event{descpb.TableDescriptor_Trigger_DELETE},
+						Body:   []string{"DELETE FROM t WHERE k = old.bar"},
+					},
+				},
+			}},
+		{`column privileges refer to unknown column ID 2`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				ColumnPrivileges: []descpb.TableDescriptor_ColumnPrivileges{
+					{
+						ColumnID: 2,
+						Users: []catpb.UserPrivileges{
+							{UserProto: username.TestUserName().EncodeProto(), Privileges: privilege.SELECT.Mask()},
+						},
+					},
+				},
+			}},
+		{`user testuser has invalid privileges on column ID 1: DELETE`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				ColumnPrivileges: []descpb.TableDescriptor_ColumnPrivileges{
+					{
+						ColumnID: 1,
+						Users: []catpb.UserPrivileges{
+							{UserProto: username.TestUserName().EncodeProto(), Privileges: privilege.DELETE.Mask()},
+						},
+					},
+				},
+			}},
+		{`duplicate policy name: "pol"`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				Policies: []descpb.TableDescriptor_Policy{
+					{
+						Name:      "pol",
+						Command:   descpb.TableDescriptor_Policy_SELECT,
+						RoleNames: []string{"public"},
+						UsingExpr: "bar > 0",
+					},
+					{
+						Name:          "pol",
+						Command:       descpb.TableDescriptor_Policy_INSERT,
+						RoleNames:     []string{"public"},
+						WithCheckExpr: "bar > 0",
+					},
+				},
+			}},
+		{`policy "pol" refers to unknown columns in expression: baz = 1`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				Policies: []descpb.TableDescriptor_Policy{
+					{
+						Name:      "pol",
+						RoleNames: []string{"public"},
+						UsingExpr: "baz = 1",
+					},
+				},
+			}},
+		{`trigger "trig": at or near "EOF": syntax error`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				Triggers: []descpb.TableDescriptor_Trigger{
+					{
+						Name:   "trig",
+						Events: []descpb.TableDescriptor_Trigger_Event{descpb.TableDescriptor_Trigger_INSERT},
+						Body:   []string{"INSERT INTO"},
+					},
+				},
+			}},
+		{`exclusion constraint "excl" contains unknown column "2"`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				NextConstraintID: 3,
+				ExclusionConstraints: []descpb.TableDescriptor_ExclusionConstraint{
+					{
+						Name:         "excl",
+						ColumnIDs:    []descpb.ColumnID{2},
+						Operators:    []string{"="},
+						ConstraintID: 2,
+					},
+				},
+			}},
+		{`exclusion constraint "excl" contains unsupported operator "<"`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, ConstraintID: 1, Name: "primary", KeyColumnIDs: []descpb.ColumnID{1}, KeyColumnNames: []string{"bar"},
+					KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC}},
+				NextConstraintID: 3,
+				ExclusionConstraints: []descpb.TableDescriptor_ExclusionConstraint{
+					{
+						Name:         "excl",
+						ColumnIDs:    []descpb.ColumnID{1},
+						Operators:    []string{"<"},
+						ConstraintID: 2,
+					},
+				},
+			}},
 		{`index "sec" cannot store virtual column "c3"`,
 			descpb.TableDescriptor{
 				ID:            2,
@@ -1897,6 +2148,30 @@ func TestValidateTableDesc(t *testing.T) {
 				NextColumnID:      2,
 				AutoStatsSettings: &catpb.AutoStatsSettings{Enabled: &boolTrue},
 			}},
+		{`foreign table has no files`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				NextColumnID: 2,
+				Foreign:      &descpb.TableDescriptor_ForeignTable{},
+			}},
+		{`only materialized views can be maintained incrementally`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				NextColumnID:       2,
+				IncrementalRefresh: &descpb.TableDescriptor_IncrementalRefresh{},
+			}},
 		{`Setting sql_stats_automatic_collection_enabled may not be set on a view or sequence`,
 			descpb.TableDescriptor{
 				Name:                    "bar",
@@ -192,6 +192,9 @@ func supportedNatively(core *execinfrapb.ProcessorCoreUnion) error {
 			if agg.FilterColIdx != nil {
 				return errors.Newf("filtering aggregation not supported")
 			}
+			if agg.UserDefined != nil {
+				return errors.Newf("user-defined aggregation not supported")
+			}
 		}
 		return nil
 
@@ -303,6 +306,7 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, core *execinfrapb.ProcessorCo
 	case core.Filterer != nil:
 	case core.StreamIngestionData != nil:
 	case core.StreamIngestionFrontier != nil:
+	case core.ForeignScan != nil:
 	default:
 		return errors.AssertionFailedf("unexpected processor core %q", core)
 	}
@@ -0,0 +1,250 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+// changeColumnPrivilegesNode grants or revokes privileges on columns of
+// tables.
+type changeColumnPrivilegesNode struct {
+	changePrivilegesNode
+	privileges tree.ColumnPrivileges
+}
+
+// changeColumnPrivileges plans a GRANT or REVOKE statement on columns of
+// tables.
+// Privileges: the privileges WITH GRANT OPTION on the tables.
+//   Notes: postgres also accepts the privileges WITH GRANT OPTION on the
+//          columns.
+func (p *planner) changeColumnPrivileges(
+	ctx context.Context,
+	privileges tree.ColumnPrivileges,
+	targets tree.TargetList,
+	granteeSpecs tree.RoleSpecList,
+	isGrant bool,
+	withGrantOption bool,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ColumnPrivileges) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use column privileges",
+			clusterversion.ByKey(clusterversion.ColumnPrivileges))
+	}
+	if targets.Databases != nil || targets.Schemas != nil || targets.Types != nil ||
+		targets.TenantID.Specified || targets.System || targets.Tables.SequenceOnly {
+		return nil, pgerror.New(pgcode.InvalidGrantOperation,
+			"column privileges are only valid for tables")
+	}
+	for _, cp := range privileges {
+		if !privilege.ColumnPrivileges.Contains(cp.Privilege) {
+			return nil, pgerror.Newf(pgcode.InvalidGrantOperation,
+				"invalid privilege type %s for column", cp.Privilege)
+		}
+	}
+
+	grantees, err := decodeusername.FromRoleSpecList(
+		p.SessionData(), username.PurposeValidation, granteeSpecs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &changeColumnPrivilegesNode{
+		changePrivilegesNode: changePrivilegesNode{
+			isGrant:         isGrant,
+			withGrantOption: withGrantOption,
+			grantees:        grantees,
+			targets:         targets,
+			grantOn:         privilege.Table,
+		},
+		privileges: privileges,
+	}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+func (n *changeColumnPrivilegesNode) ReadingOwnWrites() {}
+
+func (n *changeColumnPrivilegesNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	if err := n.preChangePrivilegesValidation(params); err != nil {
+		return err
+	}
+
+	var err error
+	var descriptorsWithTypes []DescriptorWithObjectType
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		descriptorsWithTypes, err = p.getDescriptorsFromTargetListForPrivilegeChange(ctx, n.targets)
+	})
+	if err != nil {
+		return err
+	}
+
+	var events []eventLogEntry
+	b := p.txn.NewBatch()
+	for _, descriptorWithTypes := range descriptorsWithTypes {
+		tableDesc, ok := descriptorWithTypes.descriptor.(*tabledesc.Mutable)
+		if !ok || !tableDesc.IsTable() {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"column privileges are only valid for tables, but %q is not a table",
+				descriptorWithTypes.descriptor.GetName())
+		}
+		if catalog.IsSystemDescriptor(tableDesc) {
+			op := "REVOKE"
+			if n.isGrant {
+				op = "GRANT"
+			}
+			return pgerror.Newf(pgcode.InsufficientPrivilege, "cannot %s on system object", op)
+		}
+
+		changed := false
+		var changedPrivs []string
+		for i := range n.privileges {
+			cp := &n.privileges[i]
+			privList := privilege.List{cp.Privilege}
+			if err := p.CheckGrantOptionsForUser(
+				ctx, tableDesc.GetPrivileges(), tableDesc, privList, p.User(), n.isGrant,
+			); err != nil {
+				return err
+			}
+			for _, name := range cp.Columns {
+				col, err := tableDesc.FindColumnWithName(name)
+				if err != nil {
+					return err
+				}
+				if !col.Public() {
+					return colinfo.NewUndefinedColumnError(string(name))
+				}
+				for _, grantee := range n.grantees {
+					if tableDesc.ChangeColumnPrivileges(col.GetID(), func(privs *catpb.PrivilegeDescriptor) {
+						if n.isGrant {
+							privs.Grant(grantee, privList, n.withGrantOption)
+						} else {
+							privs.Revoke(grantee, privList, privilege.Table, n.withGrantOption)
+						}
+					}) {
+						changed = true
+					}
+				}
+			}
+			changedPrivs = append(changedPrivs, tree.AsString(&tree.ColumnPrivileges{*cp}))
+		}
+		if !changed {
+			continue
+		}
+
+		if err := p.createOrUpdateSchemaChangeJob(
+			ctx, tableDesc,
+			"updating column privileges for table "+tableDesc.Name,
+			descpb.InvalidMutationID,
+		); err != nil {
+			return err
+		}
+		if err := p.writeSchemaChangeToBatch(ctx, tableDesc, b); err != nil {
+			return err
+		}
+		for _, grantee := range n.grantees {
+			privs := eventpb.CommonSQLPrivilegeEventDetails{Grantee: grantee.Normalized()}
+			if n.isGrant {
+				privs.GrantedPrivileges = changedPrivs
+			} else {
+				privs.RevokedPrivileges = changedPrivs
+			}
+			events = append(events, eventLogEntry{
+				targetID: int32(tableDesc.ID),
+				event: &eventpb.ChangeTablePrivilege{
+					CommonSQLPrivilegeEventDetails: privs,
+					TableName:                      tableDesc.Name,
+				}})
+		}
+	}
+
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+	if events != nil {
+		return params.p.logEvents(params.ctx, events...)
+	}
+	return nil
+}
+
+func (*changeColumnPrivilegesNode) Next(runParams) (bool, error) { return false, nil }
+func (*changeColumnPrivilegesNode) Values() tree.Datums          { return tree.Datums{} }
+func (*changeColumnPrivilegesNode) Close(context.Context)        {}
+
+// revokeColumnPrivileges revokes the given privileges from the given users on
+// all the columns of the table, as is done when the privileges are revoked on
+// the table itself. It returns true if any privileges were changed.
+func revokeColumnPrivileges(
+	tableDesc *tabledesc.Mutable,
+	privList privilege.List,
+	grantees []username.SQLUsername,
+	grantOptionFor bool,
+) (changed bool) {
+	colIDs := make([]descpb.ColumnID, len(tableDesc.ColumnPrivileges))
+	for i := range tableDesc.ColumnPrivileges {
+		colIDs[i] = tableDesc.ColumnPrivileges[i].ColumnID
+	}
+	for _, colID := range colIDs {
+		for _, grantee := range grantees {
+			if tableDesc.ChangeColumnPrivileges(colID, func(privs *catpb.PrivilegeDescriptor) {
+				privs.Revoke(grantee, privList, privilege.Table, grantOptionFor)
+			}) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// hasColumnPrivilege returns true if the current user has the given privilege
+// on the column of the table with the given ID through the privileges granted
+// on the column. If colID is zero, it returns true if the user has the
+// privilege on any column of the table.
+func (p *planner) hasColumnPrivilege(
+	ctx context.Context,
+	tableDesc catalog.TableDescriptor,
+	colID descpb.ColumnID,
+	kind privilege.Kind,
+) (bool, error) {
+	for _, cp := range tableDesc.GetColumnPrivileges() {
+		if colID != 0 && cp.ColumnID != colID {
+			continue
+		}
+		privs := catpb.PrivilegeDescriptor{Users: cp.Users}
+		if privs.CheckPrivilege(username.PublicRoleName(), kind) {
+			return true, nil
+		}
+		hasPriv, err := p.checkRolePredicate(ctx, p.User(), func(role username.SQLUsername) bool {
+			return privs.CheckPrivilege(role, kind)
+		})
+		if err != nil || hasPriv {
+			return hasPriv, err
+		}
+	}
+	return false, nil
+}
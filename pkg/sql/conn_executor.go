@@ -21,9 +21,11 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
@@ -37,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/contention/txnidcache"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
@@ -1120,6 +1123,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.state.finishExternalTxn()
 	}
 
+	if ex.procedureTxn.call != nil {
+		ex.abortProcedure(ctx)
+	}
 	ex.resetExtraTxnState(ctx, txnEvent{eventType: txnEvType})
 	if ex.hasCreatedTemporarySchema && !ex.server.cfg.TestingKnobs.DisableTempObjectsCleanupOnSessionExit {
 		ieMon := MakeInternalExecutorMemMonitor(MemoryMetrics{}, ex.server.cfg.Settings)
@@ -1168,6 +1174,11 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.eventLog = nil
 	}
 
+	if ex.notifyListener != nil {
+		ex.notifyListener.Close()
+	}
+	ex.releaseAllAdvisoryLocks(ctx)
+
 	// Stop idle timer if the connExecutor is closed to ensure cancel session
 	// is not called.
 	ex.mu.IdleInSessionTimeout.Stop()
@@ -1411,6 +1422,18 @@ type connExecutor struct {
 		// createdSequences keeps track of sequences created in the current transaction.
 		// The map key is the sequence descpb.ID.
 		createdSequences map[descpb.ID]struct{}
+
+		// deferredChecks keeps track of the constraint checks which are deferred
+		// to the end of the current transaction.
+		deferredChecks deferredChecksState
+
+		// listenActions are the LISTEN and UNLISTEN statements executed in the
+		// current transaction, which are applied when it commits.
+		listenActions listenActions
+
+		// sentNotifications are the notifications sent in the current
+		// transaction.
+		sentNotifications sentNotifications
 	}
 
 	// sessionDataStack contains the user-configurable connection variables.
@@ -1513,6 +1536,24 @@ type connExecutor struct {
 	// information collected.
 	stmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// notifyListener receives the notifications sent on the channels the
+	// session listens on. It is created when the session first listens on a
+	// channel.
+	notifyListener *notify.Listener
+
+	// advisoryLocks tracks whether the session may hold advisory locks, which
+	// are released when the session closes.
+	advisoryLocks advisoryLocks
+
+	// temporaryReplicationSlots are the temporary replication slots created by
+	// the session, which are dropped when it closes.
+	temporaryReplicationSlots temporaryReplicationSlots
+
+	// procedureTxn holds the procedure which is suspended after ending its
+	// transaction, until the CALL statement which executes it is executed again
+	// in a new transaction.
+	procedureTxn procedureTxnState
+
 	// indexUsageStats is used to track index usage stats.
 	indexUsageStats *idxusage.LocalIndexUsageStats
 
@@ -1680,8 +1721,13 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) {
 
 	ex.extraTxnState.descCollection.ReleaseAll(ctx)
 
-	// Close all portals.
+	// Close all portals, except the one executing a CALL statement whose
+	// procedure ended the transaction, since it is executed again in the next
+	// transaction.
 	for name, p := range ex.extraTxnState.prepStmtsNamespace.portals {
+		if call := ex.procedureTxn.call; call != nil && call.portalName == name {
+			continue
+		}
 		p.close(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc, name)
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
@@ -1690,6 +1736,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) {
 	ex.extraTxnState.sqlCursors.closeAll()
 
 	ex.extraTxnState.createdSequences = make(map[descpb.ID]struct{})
+	ex.extraTxnState.deferredChecks.reset()
 
 	switch ev.eventType {
 	case txnCommit, txnRollback:
@@ -1698,6 +1745,12 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) {
 			delete(ex.extraTxnState.prepStmtsNamespaceAtTxnRewindPos.portals, name)
 		}
 		ex.extraTxnState.savepoints.clear()
+		if ev.eventType == txnCommit {
+			ex.applyListenActions(ctx)
+		}
+		ex.extraTxnState.listenActions = nil
+		ex.extraTxnState.sentNotifications.reset()
+		ex.releaseXactAdvisoryLocks(ctx)
 		ex.onTxnFinish(ctx, ev)
 	case txnRestart:
 		ex.onTxnRestart(ctx)
@@ -1705,9 +1758,10 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) {
 		defer ex.state.mu.Unlock()
 		ex.state.mu.stmtCount = 0
 	}
-	// NOTE: on txnRestart we don't need to muck with the savepoints stack. It's either a
-	// a ROLLBACK TO SAVEPOINT that generated the event, and that statement deals with the
-	// savepoints, or it's a rewind which also deals with them.
+	// NOTE: on txnRestart we don't need to muck with the savepoints stack or the
+	// listen actions. It's either a ROLLBACK TO SAVEPOINT that generated the
+	// event, and that statement deals with them, or it's a rewind which also
+	// deals with them.
 }
 
 // Ctx returns the transaction's ctx, if we're inside a transaction, or the
@@ -1872,6 +1926,9 @@ func (ex *connExecutor) execCmd() error {
 	var ev fsm.Event
 	var payload fsm.EventPayload
 	var res ResultBase
+	// notifyRes is set if the pending notifications of a session listening on
+	// channels can be delivered through the result of the command.
+	var notifyRes NotificationResult
 	switch tcmd := cmd.(type) {
 	case ExecStmt:
 		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionQueryReceived, tcmd.TimeReceived)
@@ -2039,7 +2096,8 @@ func (ex *connExecutor) execCmd() error {
 			ev, payload = ex.handleAutoCommit(ctx, &tree.CommitTransaction{})
 		}
 		// Note that the Sync result will flush results to the network connection.
-		res = ex.clientComm.CreateSyncResult(pos)
+		syncRes := ex.clientComm.CreateSyncResult(pos)
+		res, notifyRes = syncRes, syncRes
 		if ex.draining {
 			// If we're draining, check whether this is a good time to finish the
 			// connection. If we're not inside a transaction, we stop processing
@@ -2059,6 +2117,9 @@ func (ex *connExecutor) execCmd() error {
 		if err != nil {
 			return err
 		}
+	case StartReplication:
+		res = ex.clientComm.CreateCopyInResult(pos)
+		ev, payload = ex.execStartReplication(ctx, tcmd)
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -2072,6 +2133,17 @@ func (ex *connExecutor) execCmd() error {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// Notifications are only delivered outside of transactions. Inside a
+		// transaction, nothing is flushed, so as to not prevent the automatic
+		// retries; the notifications are delivered by the Sync which follows
+		// the end of the transaction.
+		if ex.idleConn() {
+			flushRes := ex.clientComm.CreateFlushResult(pos)
+			res, notifyRes = flushRes, flushRes
+		} else {
+			res = ex.clientComm.CreateDrainResult(pos)
+		}
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -2131,6 +2203,9 @@ func (ex *connExecutor) execCmd() error {
 				res.SetError(pe.errorCause())
 			}
 		}
+		if notifyRes != nil && ex.idleConn() {
+			ex.bufferNotifications(notifyRes)
+		}
 		res.Close(ctx, stateToTxnStatusIndicator(ex.machine.CurState()))
 	} else {
 		res.Discard()
@@ -2159,6 +2234,10 @@ func (ex *connExecutor) execCmd() error {
 			return err
 		}
 		ex.extraTxnState.savepoints = ex.extraTxnState.rewindPosSnapshot.savepoints
+		// The rewind position is never past a LISTEN or UNLISTEN statement, so
+		// all of them will be executed again. The same goes for NOTIFY.
+		ex.extraTxnState.listenActions = nil
+		ex.extraTxnState.sentNotifications.reset()
 		// Note we use the Replace function instead of reassigning, as there are
 		// copies of the ex.sessionDataStack in the iterators and extendedEvalContext.
 		ex.sessionDataStack.Replace(ex.extraTxnState.rewindPosSnapshot.sessionDataStack)
@@ -2187,6 +2266,17 @@ func (ex *connExecutor) execCmd() error {
 	return nil
 }
 
+// bufferNotifications appends the pending notifications of the session, if it
+// listens on channels, to the result of a command.
+func (ex *connExecutor) bufferNotifications(res NotificationResult) {
+	if ex.notifyListener == nil {
+		return
+	}
+	for _, n := range ex.notifyListener.TakePending() {
+		res.BufferNotification(n)
+	}
+}
+
 func (ex *connExecutor) idleConn() bool {
 	switch ex.machine.CurState().(type) {
 	case stateNoTxn:
@@ -2284,10 +2374,14 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case CopyIn:
 				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -2600,7 +2694,13 @@ func (ex *connExecutor) setTransactionModes(
 			return err
 		}
 	}
-	if modes.Isolation != tree.UnspecifiedIsolation && modes.Isolation != tree.SerializableIsolation {
+	switch modes.Isolation {
+	case tree.UnspecifiedIsolation:
+	case tree.SerializableIsolation, tree.ReadCommittedIsolation:
+		if err := ex.state.setIsolationLevel(ex.txnIsolationLevelToKV(ctx, modes.Isolation)); err != nil {
+			return err
+		}
+	default:
 		return errors.AssertionFailedf(
 			"unknown isolation level: %s", errors.Safe(modes.Isolation))
 	}
@@ -2646,6 +2746,27 @@ func (ex *connExecutor) txnPriorityWithSessionDefault(mode tree.UserPriority) ro
 	return txnPriorityToProto(mode)
 }
 
+// txnIsolationLevelToKV returns the KV isolation level that a transaction
+// which requested the given isolation level should run at. The session's
+// default isolation level is used if the level is not specified. READ
+// COMMITTED is upgraded to SERIALIZABLE unless it is allowed by the
+// sql.txn.read_committed_isolation.enabled cluster setting and the cluster
+// version.
+func (ex *connExecutor) txnIsolationLevelToKV(
+	ctx context.Context, level tree.IsolationLevel,
+) isolation.Level {
+	if level == tree.UnspecifiedIsolation {
+		level = tree.IsolationLevel(ex.sessionData().DefaultTxnIsolationLevel)
+	}
+	ret := isolation.Serializable
+	if level == tree.ReadCommittedIsolation &&
+		allowReadCommittedIsolation.Get(&ex.server.cfg.Settings.SV) &&
+		ex.server.cfg.Settings.Version.IsActive(ctx, clusterversion.ReadCommittedIsolation) {
+		ret = isolation.ReadCommitted
+	}
+	return ret
+}
+
 // QualityOfService returns the QoSLevel session setting if the session
 // settings are populated, otherwise the default QoSLevel.
 func (ex *connExecutor) QualityOfService() sessiondatapb.QoSLevel {
@@ -2712,15 +2833,20 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			CatalogBuiltins:                &p.evalCatalogBuiltins,
 			QueryCancelKey:                 ex.queryCancelKey,
 		},
-		Tracing:                &ex.sessionTracing,
-		MemMetrics:             &ex.memMetrics,
-		Descs:                  &ex.extraTxnState.descCollection,
-		TxnModesSetter:         ex,
-		Jobs:                   &ex.extraTxnState.jobs,
-		SchemaChangeJobRecords: ex.extraTxnState.schemaChangeJobRecords,
-		statsProvider:          ex.server.sqlStats,
-		indexUsageStats:        ex.indexUsageStats,
-		statementPreparer:      ex,
+		Tracing:                   &ex.sessionTracing,
+		MemMetrics:                &ex.memMetrics,
+		Descs:                     &ex.extraTxnState.descCollection,
+		TxnModesSetter:            ex,
+		Jobs:                      &ex.extraTxnState.jobs,
+		ListenActions:             &ex.extraTxnState.listenActions,
+		SentNotifications:         &ex.extraTxnState.sentNotifications,
+		AdvisoryLocks:             &ex.advisoryLocks,
+		TemporaryReplicationSlots: &ex.temporaryReplicationSlots,
+		procedureTxn:              &ex.procedureTxn,
+		SchemaChangeJobRecords:    ex.extraTxnState.schemaChangeJobRecords,
+		statsProvider:             ex.server.sqlStats,
+		indexUsageStats:           ex.indexUsageStats,
+		statementPreparer:         ex,
 	}
 	evalCtx.copyFromExecCfg(ex.server.cfg)
 }
@@ -2736,6 +2862,10 @@ func (ex *connExecutor) resetEvalCtx(evalCtx *extendedEvalContext, txn *kv.Txn,
 	newTxn := txn == nil || evalCtx.Txn != txn
 	evalCtx.TxnState = ex.getTransactionState()
 	evalCtx.TxnReadOnly = ex.state.readOnly
+	evalCtx.TxnIsoLevel = isolation.Serializable
+	if txn != nil {
+		evalCtx.TxnIsoLevel = txn.IsoLevel()
+	}
 	evalCtx.TxnImplicit = ex.implicitTxn()
 	evalCtx.TxnIsSingleStmt = false
 	if newTxn || !ex.implicitTxn() {
@@ -2804,6 +2934,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.preparedStatements = ex.getPrepStmtsAccessor()
 	p.sqlCursors = ex.getCursorAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.deferredChecks = ex.getDeferredChecksAccessor()
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -2822,7 +2953,7 @@ func (ex *connExecutor) resetPlanner(
 	p.cancelChecker.Reset(ctx)
 
 	p.semaCtx = tree.MakeSemaContext()
-	p.semaCtx.SearchPath = &ex.sessionData().SearchPath
+	p.semaCtx.SearchPath = aggregateResolver{SearchPath: &ex.sessionData().SearchPath, p: p}
 	p.semaCtx.Annotations = nil
 	p.semaCtx.TypeResolver = p
 	p.semaCtx.TableNameResolver = p
@@ -2997,9 +3128,39 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 		return advanceInfo{}, errors.AssertionFailedf(
 			"unexpected event: %v", errors.Safe(advInfo.txnEvent))
 	}
+
+	// If a procedure ended its transaction, the CALL statement which executes
+	// it is executed again in a new implicit transaction, which resumes the
+	// procedure. If the transaction failed instead, the procedure is aborted.
+	if ex.procedureTxn.call != nil && advInfo.txnEvent.eventType != txnStart {
+		switch advInfo.txnEvent.eventType {
+		case txnCommit, txnRollback:
+			if res.Err() == nil && !payloadHasError(payload) {
+				advInfo.code = stayInPlace
+				return advInfo, nil
+			}
+		}
+		ex.abortProcedure(ex.Ctx())
+	}
 	return advInfo, nil
 }
 
+// abortProcedure aborts the procedure which is suspended after ending its
+// transaction, and closes the portal which executed it.
+func (ex *connExecutor) abortProcedure(ctx context.Context) {
+	call := ex.procedureTxn.call
+	ex.procedureTxn.call = nil
+	call.abort()
+	// Inside a transaction, the portal is closed when the transaction ends.
+	if _, noTxn := ex.machine.CurState().(stateNoTxn); !noTxn || call.portalName == "" {
+		return
+	}
+	if p, ok := ex.extraTxnState.prepStmtsNamespace.portals[call.portalName]; ok {
+		p.close(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc, call.portalName)
+		delete(ex.extraTxnState.prepStmtsNamespace.portals, call.portalName)
+	}
+}
+
 func (ex *connExecutor) handleWaitingForConcurrentSchemaChanges(
 	ctx context.Context, descID descpb.ID,
 ) error {
@@ -3211,6 +3372,12 @@ func (ex *connExecutor) getCreatedSequencesAccessor() createdSequences {
 	}
 }
 
+func (ex *connExecutor) getDeferredChecksAccessor() deferredChecks {
+	return connExDeferredChecksAccessor{
+		ex: ex,
+	}
+}
+
 // sessionEventf logs a message to the session event log (if any).
 func (ex *connExecutor) sessionEventf(ctx context.Context, format string, args ...interface{}) {
 	if log.ExpensiveLogEnabled(ctx, 2) {
@@ -3228,7 +3395,7 @@ func (ex *connExecutor) notifyStatsRefresherOfNewTables(ctx context.Context) {
 	for _, desc := range ex.extraTxnState.descCollection.GetUncommittedTables() {
 		// The CREATE STATISTICS run for an async CTAS query is initiated by the
 		// SchemaChanger, so we don't do it here.
-		if desc.IsTable() && !desc.IsAs() && desc.GetVersion() == 1 {
+		if desc.IsTable() && !desc.IsAs() && !desc.IsForeign() && desc.GetVersion() == 1 {
 			// Initiate a run of CREATE STATISTICS. We use a large number
 			// for rowsAffected because we want to make sure that stats always get
 			// created/refreshed here.
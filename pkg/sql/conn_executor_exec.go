@@ -231,6 +231,13 @@ func (ex *connExecutor) execPortal(
 			return nil, nil, nil
 		}
 		ev, payload, err = ex.execStmt(ctx, portal.Stmt.Statement, portal.Stmt, pinfo, stmtRes, canAutoCommit)
+		// If the portal executes a CALL statement whose procedure ended the
+		// transaction, the portal is executed again in the next transaction to
+		// resume the procedure, so it is not exhausted.
+		if call := ex.procedureTxn.call; call != nil {
+			call.portalName = portalName
+			return ev, payload, err
+		}
 		// Portal suspension is supported via a "side" state machine
 		// (see pgwire.limitedCommandResult for details), so when
 		// execStmt returns, we know for sure that the portal has been
@@ -516,6 +523,16 @@ func (ex *connExecutor) execStmtInOpenState(
 			filter(ctx, ex.sessionData(), ast.String(), execErr)
 		}
 
+		// If the statement is a CALL whose procedure executed COMMIT or
+		// ROLLBACK, the procedure is suspended until its transaction ends, like
+		// the transaction of a COMMIT or ROLLBACK statement.
+		if ex.procedureTxn.call != nil {
+			if retEv == nil && retErr == nil {
+				retEv, retPayload = ex.endProcedureTxn(ctx, ast)
+			}
+			return
+		}
+
 		// Do the auto-commit, if necessary. In the extended protocol, the
 		// auto-commit happens when the Sync message is handled.
 		if retEv != nil || retErr != nil {
@@ -688,9 +705,15 @@ func (ex *connExecutor) execStmtInOpenState(
 		stmtCtx = ctx
 	}
 
-	if err := ex.dispatchToExecutionEngine(stmtCtx, p, res); err != nil {
+	var dispatchErr error
+	if ex.state.mu.txn.IsoLevel().PerStatementReadSnapshot() {
+		dispatchErr = ex.dispatchPerStatementSnapshotToExecutionEngine(stmtCtx, p, res)
+	} else {
+		dispatchErr = ex.dispatchToExecutionEngine(stmtCtx, p, res)
+	}
+	if dispatchErr != nil {
 		stmtThresholdSpan.Finish()
-		return nil, nil, err
+		return nil, nil, dispatchErr
 	}
 
 	if stmtThresholdSpan != nil {
@@ -739,6 +762,73 @@ func (ex *connExecutor) execStmtInOpenState(
 	return nil, nil, nil
 }
 
+// maxStmtRetries is the maximum number of times that a statement of a
+// transaction which establishes a new read snapshot for each statement is
+// retried after hitting a retryable error, before the whole transaction is
+// restarted instead.
+const maxStmtRetries = 5
+
+// dispatchPerStatementSnapshotToExecutionEngine is like
+// dispatchToExecutionEngine, but is used for transactions which establish a new
+// read snapshot for each statement (i.e. READ COMMITTED transactions).
+//
+// The statement reads from a snapshot established when it starts. If it hits a
+// retryable error which only requires the statement to be retried, such as a
+// write-write conflict with a concurrent transaction, the writes it performed
+// are rolled back and the statement is executed again on a new snapshot,
+// instead of restarting the whole transaction. The transaction is restarted
+// anyway (by leaving the error on the result) if the statement performed DDL,
+// if it acquired or released advisory locks, which is done outside of the
+// transaction, if some of its results have already been delivered to the
+// client, or if it hit too many retryable errors. The LISTEN, UNLISTEN and
+// NOTIFY actions and the notices of the failed execution are discarded.
+func (ex *connExecutor) dispatchPerStatementSnapshotToExecutionEngine(
+	ctx context.Context, p *planner, res RestrictedCommandResult,
+) error {
+	txn := ex.state.mu.txn
+	numDDL := ex.extraTxnState.numDDL
+	numAdvisoryLockChanges := ex.advisoryLocks.changes
+	numListenActions := len(ex.extraTxnState.listenActions)
+	numNotifications := len(ex.extraTxnState.sentNotifications.list)
+	for retries := 0; ; retries++ {
+		if err := txn.StepReadTimestamp(ctx); err != nil {
+			return err
+		}
+		// KV savepoints don't hold on to any resources, so the savepoint does not
+		// need to be released if the statement succeeds.
+		sp, err := txn.CreateSavepoint(ctx)
+		if err != nil {
+			return err
+		}
+		if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+			return err
+		}
+
+		var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+		if !errors.As(res.Err(), &retryErr) || !retryErr.StatementRetry {
+			return nil
+		}
+		if retries >= maxStmtRetries || ex.extraTxnState.numDDL > numDDL ||
+			ex.advisoryLocks.changes > numAdvisoryLockChanges ||
+			!res.TruncateBufferedResults(ctx) {
+			return nil
+		}
+		log.VEventf(ctx, 2, "retrying statement on new read snapshot after error: %v", res.Err())
+		if err := txn.PrepareForStatementRetry(ctx); err != nil {
+			return err
+		}
+		if err := txn.RollbackToSavepoint(ctx, sp); err != nil {
+			return err
+		}
+		ex.extraTxnState.listenActions = ex.extraTxnState.listenActions[:numListenActions]
+		ex.extraTxnState.sentNotifications.truncate(numNotifications)
+		res.SetError(nil)
+		if err := txn.Step(ctx); err != nil {
+			return err
+		}
+	}
+}
+
 // handleAOST gets the AsOfSystemTime clause from the statement, and sets
 // the timestamps of the transaction accordingly.
 func (ex *connExecutor) handleAOST(ctx context.Context, stmt tree.Statement) error {
@@ -944,6 +1034,13 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) error
 	ctx, sp := tracing.EnsureChildSpan(ctx, ex.server.cfg.AmbientCtx.Tracer, "commit sql txn")
 	defer sp.Finish()
 
+	// Validate the constraint checks which were deferred until commit.
+	if pending := ex.extraTxnState.deferredChecks.takePending(); len(pending) > 0 {
+		if err := ex.planner.validatePendingChecks(ctx, pending); err != nil {
+			return err
+		}
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -1474,8 +1571,10 @@ func (ex *connExecutor) execWithDistSQLEngine(
 		// return from this method (after the main query is executed).
 		subqueryResultMemAcc := planner.EvalContext().Mon.MakeBoundAccount()
 		defer subqueryResultMemAcc.Close(ctx)
-		if !ex.server.cfg.DistSQLPlanner.PlanAndRunSubqueries(
-			ctx, planner, evalCtxFactory, planner.curPlan.subqueryPlans, recv, &subqueryResultMemAcc,
+		// Row-level triggers which fire before the mutation can only run once
+		// the mutation input has been buffered by a subquery.
+		if !ex.server.cfg.DistSQLPlanner.PlanAndRunSubqueriesAndBeforeTriggers(
+			ctx, planner, evalCtxFactory, &planner.curPlan.planComponents, recv, &subqueryResultMemAcc,
 		) {
 			return *recv.stats, recv.commErr
 		}
@@ -1581,6 +1680,7 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		return eventStartExplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(s.Modes.UserPriority),
+				ex.txnIsolationLevelToKV(ctx, s.Modes.Isolation),
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1602,6 +1702,7 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		return eventStartImplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+				ex.txnIsolationLevelToKV(ctx, tree.UnspecifiedIsolation),
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1632,6 +1733,7 @@ func (ex *connExecutor) beginImplicitTxn(
 	return eventStartImplicitTxn,
 		makeEventTxnStartPayload(
 			ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+			ex.txnIsolationLevelToKV(ctx, tree.UnspecifiedIsolation),
 			mode,
 			sqlTs,
 			historicalTs,
@@ -2092,6 +2194,25 @@ func (ex *connExecutor) handleAutoCommit(
 	return ev, payload
 }
 
+// endProcedureTxn commits or rolls back the transaction of a procedure which
+// executed COMMIT or ROLLBACK. The procedure is resumed when the CALL
+// statement is executed again, in the next transaction.
+func (ex *connExecutor) endProcedureTxn(
+	ctx context.Context, stmt tree.Statement,
+) (fsm.Event, fsm.EventPayload) {
+	call := ex.procedureTxn.call
+	if !call.commit {
+		return ex.rollbackSQLTransaction(ctx, stmt)
+	}
+	err := ex.extraTxnState.descCollection.MaybeUpdateDeadline(ctx, ex.state.mu.txn)
+	if err != nil {
+		return ex.makeErrEvent(call.maybeNonRetriable(err), stmt)
+	}
+	return ex.commitSQLTransaction(ctx, stmt, func(ctx context.Context) error {
+		return call.maybeNonRetriable(ex.commitSQLTransactionInternal(ctx))
+	})
+}
+
 // incrementStartedStmtCounter increments the appropriate started
 // statement counter for stmt's type.
 func (ex *connExecutor) incrementStartedStmtCounter(ast tree.Statement) {
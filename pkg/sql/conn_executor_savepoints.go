@@ -101,10 +101,12 @@ func (ex *connExecutor) execSavepointInOpenState(
 	}
 
 	sp := savepoint{
-		name:            s.Name,
-		commitOnRelease: commitOnRelease,
-		kvToken:         token,
-		numDDL:          ex.extraTxnState.numDDL,
+		name:             s.Name,
+		commitOnRelease:  commitOnRelease,
+		kvToken:          token,
+		numDDL:           ex.extraTxnState.numDDL,
+		numListenActions: len(ex.extraTxnState.listenActions),
+		numNotifications: len(ex.extraTxnState.sentNotifications.list),
 	}
 	savepoints.push(sp)
 	ex.sessionDataStack.PushTopClone()
@@ -203,6 +205,8 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 		ev, payload := ex.makeErrEvent(err, s)
 		return ev, payload
 	}
+	ex.extraTxnState.listenActions = ex.extraTxnState.listenActions[:entry.numListenActions]
+	ex.extraTxnState.sentNotifications.truncate(entry.numNotifications)
 
 	if err := ex.popSavepointsToIdx(s, idx); err != nil {
 		return ex.makeErrEvent(err, s)
@@ -287,6 +291,8 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, entry.kvToken); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
+	ex.extraTxnState.listenActions = ex.extraTxnState.listenActions[:entry.numListenActions]
+	ex.extraTxnState.sentNotifications.truncate(entry.numNotifications)
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -351,6 +357,14 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// The number of LISTEN and UNLISTEN statements that had been executed in
+	// the transaction, which are discarded when rolling back the savepoint.
+	numListenActions int
+
+	// The number of notifications that had been sent in the transaction, whose
+	// rows are discarded when rolling back the savepoint.
+	numNotifications int
 }
 
 type savepointStack []savepoint
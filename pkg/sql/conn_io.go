@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
@@ -343,6 +344,35 @@ func (c CopyIn) String() string {
 
 var _ Command = CopyIn{}
 
+// StartReplication is the command for execution of the START_REPLICATION
+// command of the replication protocol, which runs the Copy-both pgwire
+// subprotocol.
+type StartReplication struct {
+	Stmt *tree.StartReplication
+	// Conn is the network connection. Execution of the command takes control of
+	// the write side of the connection.
+	Conn pgwirebase.ReplicationConn
+	// ClientMsgs receives the copy messages sent by the client while the
+	// changes are streamed.
+	ClientMsgs <-chan pgwirebase.CopyMessage
+	// StreamDone is closed once execution finishes, signaling to the network
+	// routine that copy messages should no longer be forwarded.
+	StreamDone chan<- struct{}
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+func (c StartReplication) String() string {
+	s := "(empty)"
+	if c.Stmt != nil {
+		s = c.Stmt.String()
+	}
+	return fmt.Sprintf("StartReplication: %s", s)
+}
+
+var _ Command = StartReplication{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -358,6 +388,21 @@ func (DrainRequest) String() string {
 
 var _ Command = DrainRequest{}
 
+// DeliverNotifications is a command that is pushed when notifications become
+// pending for a session listening on channels with LISTEN. The notifications
+// are delivered to the client if the session is not in a transaction;
+// otherwise, they are delivered by the next Sync outside of a transaction.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // SendError is a command that, upon execution, send a specific error to the
 // client. This is used by pgwire to schedule errors to be sent at an
 // appropriate time.
@@ -758,6 +803,12 @@ type RestrictedCommandResult interface {
 	// to this CommandResult, will be flushed immediately to the client.
 	// This is currently used for sinkless changefeeds.
 	DisableBuffering()
+
+	// TruncateBufferedResults discards the results and the notices accumulated
+	// so far, so that the statement producing them can be executed again. It
+	// returns false if some of the results have already been delivered to the
+	// client, in which case nothing is discarded.
+	TruncateBufferedResults(ctx context.Context) bool
 }
 
 // DescribeResult represents the result of a Describe command (for either
@@ -803,12 +854,22 @@ type DeleteResult interface {
 // flushed.
 type SyncResult interface {
 	ResultBase
+	NotificationResult
 }
 
 // FlushResult represents the result of a Flush command. When this result is
 // closed, all previously accumulated results are flushed to the client.
 type FlushResult interface {
 	ResultBase
+	NotificationResult
+}
+
+// NotificationResult is implemented by the results which can deliver the
+// notifications received by a session listening on channels.
+type NotificationResult interface {
+	// BufferNotification appends a notification to the result.
+	// This gets flushed only when the result is closed.
+	BufferNotification(notify.Notification)
 }
 
 // DrainResult represents the result of a Drain command. Closing this result
@@ -926,6 +987,11 @@ func (r *streamingCommandResult) BufferNotice(notice pgnotice.Notice) {
 	panic("unimplemented")
 }
 
+// BufferNotification is part of the NotificationResult interface.
+func (r *streamingCommandResult) BufferNotification(notify.Notification) {
+	panic("unimplemented")
+}
+
 // ResetStmtType is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) ResetStmtType(stmt tree.Statement) {
 	panic("unimplemented")
@@ -957,6 +1023,12 @@ func (r *streamingCommandResult) DisableBuffering() {
 	panic("cannot disable buffering here")
 }
 
+// TruncateBufferedResults is part of the RestrictedCommandResult interface.
+func (r *streamingCommandResult) TruncateBufferedResults(context.Context) bool {
+	// The rows are streamed to the consumer as soon as they are added.
+	return false
+}
+
 // SetError is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) SetError(err error) {
 	r.err = err
@@ -702,6 +702,9 @@ func (p *planner) preparePlannerForCopy(
 	}
 	txnOpt.resetPlanner(ctx, p, txn, txnTs, stmtTs)
 	p.autoCommit = autoCommit
+	// Constraint checks are never deferred by COPY, since the copyMachine may
+	// commit its own transactions.
+	p.deferredChecks = emptyDeferredChecks{}
 
 	return func(ctx context.Context, prevErr error) (err error) {
 		// Ensure that we clean up any accumulated extraTxnState state if we've
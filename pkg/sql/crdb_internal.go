@@ -89,10 +89,10 @@ import (
 const CrdbInternalName = catconstants.CRDBInternalSchemaName
 
 // Naming convention:
-// - if the response is served from memory, prefix with node_
-// - if the response is served via a kv request, prefix with kv_
-// - if the response is not from kv requests but is cluster-wide (i.e. the
-//    answer isn't specific to the sql connection being used, prefix with cluster_.
+//   - if the response is served from memory, prefix with node_
+//   - if the response is served via a kv request, prefix with kv_
+//   - if the response is not from kv requests but is cluster-wide (i.e. the
+//     answer isn't specific to the sql connection being used, prefix with cluster_.
 //
 // Adding something new here will require an update to `pkg/cli` for inclusion in
 // a `debug zip`; the unit tests will guide you.
@@ -105,6 +105,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalBackwardDependenciesTableID:        crdbInternalBackwardDependenciesTable,
 		catconstants.CrdbInternalBuildInfoTableID:                   crdbInternalBuildInfoTable,
 		catconstants.CrdbInternalBuiltinFunctionsTableID:            crdbInternalBuiltinFunctionsTable,
+		catconstants.CrdbInternalClusterAdvisoryLocksTableID:        crdbInternalClusterAdvisoryLocksTable,
 		catconstants.CrdbInternalClusterContendedIndexesViewID:      crdbInternalClusterContendedIndexesView,
 		catconstants.CrdbInternalClusterContendedKeysViewID:         crdbInternalClusterContendedKeysView,
 		catconstants.CrdbInternalClusterContendedTablesViewID:       crdbInternalClusterContendedTablesView,
@@ -142,6 +143,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalLocalTransactionsTableID:           crdbInternalLocalTxnsTable,
 		catconstants.CrdbInternalLocalSessionsTableID:               crdbInternalLocalSessionsTable,
 		catconstants.CrdbInternalLocalMetricsTableID:                crdbInternalLocalMetricsTable,
+		catconstants.CrdbInternalMaterializedViewsTableID:           crdbInternalMaterializedViewsTable,
 		catconstants.CrdbInternalNodeExecutionOutliersTableID:       crdbInternalNodeExecutionOutliersTable,
 		catconstants.CrdbInternalNodeStmtStatsTableID:               crdbInternalNodeStmtStatsTable,
 		catconstants.CrdbInternalNodeTxnStatsTableID:                crdbInternalNodeTxnStatsTable,
@@ -653,6 +655,62 @@ CREATE TABLE crdb_internal.table_row_statistics (
 	},
 }
 
+var crdbInternalMaterializedViewsTable = virtualSchemaTable{
+	comment: `materialized views accessible by current user in current database, with the staleness of their data (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.materialized_views (
+  descriptor_id    INT         NOT NULL,
+  database_name    STRING      NOT NULL,
+  schema_name      STRING      NOT NULL,
+  descriptor_name  STRING      NOT NULL,
+  incremental      BOOL        NOT NULL,
+  populated        BOOL        NOT NULL,
+  last_refresh     TIMESTAMP,
+  staleness        INTERVAL
+)`,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		now := p.txn.ReadTimestamp()
+		return forEachTableDescAll(ctx, p, dbContext, hideVirtual,
+			func(db catalog.DatabaseDescriptor, scName string, table catalog.TableDescriptor) error {
+				if !table.MaterializedView() || table.Dropped() {
+					return nil
+				}
+				incremental := table.GetIncrementalRefresh() != nil
+				populated := !table.IsRefreshViewRequired()
+				lastRefresh := table.GetLastRefreshTime()
+				if lastRefresh.IsEmpty() {
+					lastRefresh = table.GetCreateAsOfTime()
+				}
+				lastRefreshDatum, staleness := tree.DNull, tree.DNull
+				if populated && !lastRefresh.IsEmpty() {
+					lastRefreshDatum = eval.TimestampToInexactDTimestamp(lastRefresh)
+					// The data of an incrementally maintained view is always up to
+					// date once it is populated, since the view is modified in the
+					// same transactions as the tables it depends on.
+					var d time.Duration
+					if !incremental && lastRefresh.Less(now) {
+						d = now.GoTime().Sub(lastRefresh.GoTime())
+					}
+					staleness = tree.NewDInterval(
+						duration.MakeDuration(d.Nanoseconds(), 0 /* days */, 0 /* months */),
+						types.DefaultIntervalTypeMetadata,
+					)
+				}
+				return addRow(
+					tree.NewDInt(tree.DInt(table.GetID())),
+					tree.NewDString(db.GetName()),
+					tree.NewDString(scName),
+					tree.NewDString(table.GetName()),
+					tree.MakeDBool(tree.DBool(incremental)),
+					tree.MakeDBool(tree.DBool(populated)),
+					lastRefreshDatum,
+					staleness,
+				)
+			},
+		)
+	},
+}
+
 // TODO(tbg): prefix with kv_.
 var crdbInternalSchemaChangesTable = virtualSchemaTable{
 	comment: `ongoing schema changes, across all descriptors accessible by current user (KV scan; expensive!)`,
@@ -3116,6 +3174,7 @@ CREATE TABLE crdb_internal.forward_dependencies (
 		fkDep := tree.NewDString("fk")
 		viewDep := tree.NewDString("view")
 		sequenceDep := tree.NewDString("sequence")
+		triggerDep := tree.NewDString("trigger")
 		return forEachTableDescAll(ctx, p, dbContext, hideVirtual, /* virtual tables have no backward/forward dependencies*/
 			func(db catalog.DatabaseDescriptor, _ string, table catalog.TableDescriptor) error {
 				tableID := tree.NewDInt(tree.DInt(table.GetID()))
@@ -3151,10 +3210,16 @@ CREATE TABLE crdb_internal.forward_dependencies (
 
 				if table.IsTable() || table.IsView() {
 					return table.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
+						if dep.Trigger {
+							return reportDependedOnBy(dep, triggerDep)
+						}
 						return reportDependedOnBy(dep, viewDep)
 					})
 				} else if table.IsSequence() {
 					return table.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
+						if dep.Trigger {
+							return reportDependedOnBy(dep, triggerDep)
+						}
 						return reportDependedOnBy(dep, sequenceDep)
 					})
 				}
@@ -4848,7 +4913,8 @@ CREATE TABLE crdb_internal.invalid_objects (
 			return err
 		}
 
-		// Validate database & schema descriptors, and namespace entries.
+		// Validate database, schema & function descriptors, and namespace
+		// entries.
 		{
 			lCtx := newInternalLookupCtx(c.OrderedDescriptors(), dbContext)
 
@@ -4858,7 +4924,7 @@ CREATE TABLE crdb_internal.invalid_objects (
 					if dbContext != nil && d.GetID() != dbContext.GetID() {
 						return nil
 					}
-				case catalog.SchemaDescriptor:
+				case catalog.SchemaDescriptor, catalog.FunctionDescriptor:
 					if dbContext != nil && d.GetParentID() != dbContext.GetID() {
 						return nil
 					}
@@ -5853,6 +5919,89 @@ CREATE TABLE crdb_internal.transaction_contention_events (
 	},
 }
 
+// crdbInternalClusterAdvisoryLocksTable exposes the advisory locks held by the
+// sessions of the cluster, which are stored in system.advisory_locks. The
+// locks of the SQL instances which died are not shown, even though they are
+// only deleted once another session tries to acquire them.
+var crdbInternalClusterAdvisoryLocksTable = virtualSchemaTable{
+	comment: `advisory locks held by the sessions of the cluster`,
+	schema: `
+CREATE TABLE crdb_internal.cluster_advisory_locks (
+	lock_key    INT,
+	lock_key1   INT,
+	lock_key2   INT,
+	session_id  STRING NOT NULL,
+	scope       STRING NOT NULL,
+	mode        STRING NOT NULL,
+	count       INT NOT NULL,
+	acquired    TIMESTAMP NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.AdvisoryLocksTable) {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"table crdb_internal.cluster_advisory_locks is not supported on this version")
+		}
+		hasViewActivityOrViewActivityRedacted, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		}
+		if !hasViewActivityOrViewActivityRedacted {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"user %s does not have %s or %s privilege", p.User(), roleoption.VIEWACTIVITY, roleoption.VIEWACTIVITYREDACTED)
+		}
+		// The locks are read outside of the transaction, since they are
+		// acquired and released in their own transactions.
+		rows, err := p.ExecCfg().InternalExecutor.QueryBufferedEx(
+			ctx, "crdb-internal-advisory-locks", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`SELECT lock_key, key_pair, session_id, xact, shared, count, liveness_session_id, acquired
+FROM system.advisory_locks`,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			livenessSessionID := sqlliveness.SessionID(tree.MustBeDBytes(row[6]))
+			alive, err := p.ExecCfg().SQLLiveness.IsAlive(ctx, livenessSessionID)
+			if err != nil {
+				return err
+			}
+			if !alive {
+				continue
+			}
+			lockKey, lockKey1, lockKey2 := row[0], tree.DNull, tree.DNull
+			if tree.MustBeDBool(row[1]) {
+				key := int64(tree.MustBeDInt(lockKey))
+				lockKey = tree.DNull
+				lockKey1 = tree.NewDInt(tree.DInt(key >> 32))
+				lockKey2 = tree.NewDInt(tree.DInt(int32(key)))
+			}
+			sessionID := clusterunique.IDFromBytes([]byte(tree.MustBeDBytes(row[2])))
+			scope := "session"
+			if tree.MustBeDBool(row[3]) {
+				scope = "transaction"
+			}
+			mode := "ExclusiveLock"
+			if tree.MustBeDBool(row[4]) {
+				mode = "ShareLock"
+			}
+			if err := addRow(
+				lockKey,                             // lock_key
+				lockKey1,                            // lock_key1
+				lockKey2,                            // lock_key2
+				tree.NewDString(sessionID.String()), // session_id
+				tree.NewDString(scope),              // scope
+				tree.NewDString(mode),               // mode
+				row[5],                              // count
+				row[7],                              // acquired
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalClusterLocksTable exposes the state of locks, as well as lock waiters,
 // in range lock tables across the cluster.
 var crdbInternalClusterLocksTable = virtualSchemaTable{
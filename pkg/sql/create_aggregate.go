@@ -0,0 +1,333 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createAggregateNode struct {
+	n      *tree.CreateAggregate
+	dbDesc catalog.DatabaseDescriptor
+	schema catalog.SchemaDescriptor
+	// agg holds the definition of the aggregate, without the fields shared by
+	// all descriptors.
+	agg      *descpb.FunctionDescriptor
+	argTypes []*types.T
+}
+
+// CreateAggregate creates a user-defined aggregate. The aggregate is stored in
+// its own function descriptor, and referenced by name by its schema. The
+// support functions of the aggregate are type checked and stored as scalar
+// expressions in the descriptor.
+// Privileges: CREATE on the schema of the aggregate. The owner of the
+// aggregate is the user who creates it, and the public role is granted
+// EXECUTE.
+//   notes: postgres requires EXECUTE on the support functions.
+func (p *planner) CreateAggregate(ctx context.Context, n *tree.CreateAggregate) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.UserDefinedAggregates) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create aggregates",
+			clusterversion.ByKey(clusterversion.UserDefinedAggregates))
+	}
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+
+	name := &n.Aggregate.Name
+	db, schema, _, err := p.ResolveTargetObject(ctx, name.ToUnresolvedObjectName())
+	if err != nil {
+		return nil, err
+	}
+	if db.GetID() == keys.SystemDatabaseID {
+		return nil, errors.New("cannot create an aggregate in the system database")
+	}
+	switch schema.SchemaKind() {
+	case catalog.SchemaUserDefined:
+	case catalog.SchemaTemporary:
+		return nil, unimplemented.NewWithIssue(74775, "cannot create aggregates in a temporary schema")
+	default:
+		// The public schemas of the databases created before public schemas
+		// had descriptors cannot reference functions.
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot create aggregates in schema %q", schema.GetName())
+	}
+	if err := p.canCreateOnSchema(
+		ctx, schema.GetID(), db.GetID(), p.User(), checkPublicSchema,
+	); err != nil {
+		return nil, err
+	}
+
+	// Builtin functions shadow the aggregates of every schema of the search
+	// path, so an aggregate with the name of a builtin could only be used
+	// with a qualified name.
+	if _, ok := tree.FunDefs[name.Object()]; ok {
+		return nil, pgerror.Newf(pgcode.DuplicateFunction,
+			"aggregate %s conflicts with a builtin function", name.Object())
+	}
+	// An aggregate cannot be overloaded by a function, since the name of a
+	// user-defined routine resolves to either aggregates or functions.
+	if hasFunction, err := p.hasFunctionNamed(ctx, schema, name.Object()); err != nil {
+		return nil, err
+	} else if hasFunction {
+		return nil, pgerror.Newf(pgcode.DuplicateFunction,
+			"aggregate %s conflicts with a user-defined function", name.Object())
+	}
+	argTypes, err := p.resolveAggregateTypes(ctx, &n.Aggregate)
+	if err != nil {
+		return nil, err
+	}
+	agg, err := p.makeAggregate(ctx, n, argTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createAggregateNode{
+		n: n, dbDesc: db, schema: schema, agg: agg, argTypes: argTypes,
+	}, nil
+}
+
+// makeAggregate builds the definition of a user-defined aggregate from the
+// attributes of a CREATE AGGREGATE statement.
+func (p *planner) makeAggregate(
+	ctx context.Context, n *tree.CreateAggregate, argTypes []*types.T,
+) (*descpb.FunctionDescriptor, error) {
+	fn := &descpb.FunctionDescriptor{
+		Name:       n.Aggregate.Name.Object(),
+		Args:       make([]descpb.FunctionDescriptor_Argument, len(argTypes)),
+		Volatility: descpb.FunctionDescriptor_VOLATILE,
+		Aggregate:  &descpb.FunctionDescriptor_Aggregate{},
+	}
+	for i, typ := range argTypes {
+		fn.Args[i].Type = typ
+	}
+	agg := fn.Aggregate
+	var sfunc, finalFunc, combineFunc *tree.AggregateOption
+	seen := make(map[tree.Name]struct{}, len(n.Options))
+	for i := range n.Options {
+		opt := &n.Options[i]
+		if _, ok := seen[opt.Name]; ok {
+			return nil, pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		seen[opt.Name] = struct{}{}
+		switch opt.Name {
+		case "sfunc":
+			sfunc = opt
+		case "finalfunc":
+			finalFunc = opt
+		case "combinefunc":
+			combineFunc = opt
+		case "stype":
+			if opt.Type == nil {
+				return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate stype must be a type")
+			}
+			typ, err := tree.ResolveType(ctx, opt.Type, p.semaCtx.GetTypeResolver())
+			if err != nil {
+				return nil, err
+			}
+			if typ.UserDefined() {
+				return nil, unimplemented.NewWithIssuef(74775,
+					"user-defined types cannot be used by aggregates: %s", typ.SQLString())
+			}
+			agg.StateType = typ
+		case "initcond":
+			if opt.Type != nil {
+				return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+					"aggregate initcond must be a string constant")
+			}
+			initCond := opt.Str
+			agg.InitialCondition = &initCond
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"aggregate attribute %q not recognized", opt.Name)
+		}
+	}
+	if agg.StateType == nil {
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate stype must be specified")
+	}
+	if sfunc == nil {
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate sfunc must be specified")
+	}
+
+	// The transition expression refers to the state as $1 and to the arguments
+	// of the aggregate as $2, $3, ...
+	stepTypes := append([]*types.T{agg.StateType}, argTypes...)
+	step, err := p.typeCheckAggregateOption(ctx, sfunc, stepTypes, agg.StateType)
+	if err != nil {
+		return nil, err
+	}
+	agg.TransitionExpr = tree.Serialize(step)
+
+	fn.ReturnType = agg.StateType
+	if finalFunc != nil {
+		final, err := p.typeCheckAggregateOption(
+			ctx, finalFunc, []*types.T{agg.StateType}, nil, /* required */
+		)
+		if err != nil {
+			return nil, err
+		}
+		agg.FinalExpr = tree.Serialize(final)
+		fn.ReturnType = final.ResolvedType()
+		if fn.ReturnType.Family() == types.UnknownFamily {
+			return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"could not determine the result type of the aggregate finalfunc")
+		}
+	}
+
+	if combineFunc != nil {
+		combine, err := p.typeCheckAggregateOption(
+			ctx, combineFunc, []*types.T{agg.StateType, agg.StateType}, agg.StateType,
+		)
+		if err != nil {
+			return nil, err
+		}
+		agg.CombineExpr = tree.Serialize(combine)
+	}
+
+	if agg.InitialCondition != nil {
+		if _, _, err := tree.ParseAndRequireString(
+			agg.StateType, *agg.InitialCondition, p.EvalContext(),
+		); err != nil {
+			return nil, errors.Wrap(err, "invalid aggregate initcond")
+		}
+	}
+	return fn, nil
+}
+
+// typeCheckAggregateOption type checks a support function of a user-defined
+// aggregate, whose parameters have the given types. The function is either
+// the name of a function, which is applied to the parameters, or a string
+// containing a scalar expression in which the parameters are referenced as
+// $1, $2, ... The parameters are replaced with ordinal references in the
+// returned expression. If required is set, the expression must return a value
+// of that type.
+func (p *planner) typeCheckAggregateOption(
+	ctx context.Context, opt *tree.AggregateOption, typs []*types.T, required *types.T,
+) (tree.TypedExpr, error) {
+	h := tree.MakeTypesOnlyIndexedVarHelper(typs)
+	var expr tree.Expr
+	if opt.Type != nil {
+		fn, ok := opt.Type.(*tree.UnresolvedObjectName)
+		if !ok {
+			return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"aggregate %s must be a function name or a string", opt.Name)
+		}
+		args := make(tree.Exprs, len(typs))
+		for i := range args {
+			args[i] = h.IndexedVar(i)
+		}
+		expr = &tree.FuncExpr{
+			Func:  tree.ResolvableFunctionReference{FunctionReference: fn.ToUnresolvedName()},
+			Exprs: args,
+		}
+	} else {
+		parsed, err := parser.ParseExpr(opt.Str)
+		if err != nil {
+			return nil, errors.Wrapf(err, "aggregate %s", opt.Name)
+		}
+		if tree.ContainsVars(parsed) {
+			return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"aggregate %s cannot reference columns", opt.Name)
+		}
+		expr, err = tree.SimpleVisit(parsed, func(e tree.Expr) (bool, tree.Expr, error) {
+			placeholder, ok := e.(*tree.Placeholder)
+			if !ok {
+				return true, e, nil
+			}
+			if int(placeholder.Idx) >= len(typs) {
+				return false, nil, pgerror.Newf(pgcode.UndefinedParameter,
+					"there is no parameter %s in aggregate %s", placeholder, opt.Name)
+			}
+			return false, h.IndexedVar(int(placeholder.Idx)), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// We need to save and restore the previous value of the fields in semaCtx
+	// since the planner's semaCtx is shared by the whole statement.
+	defer p.semaCtx.Properties.Restore(p.semaCtx.Properties)
+	defer func(ivarContainer tree.IndexedVarContainer) {
+		p.semaCtx.IVarContainer = ivarContainer
+	}(p.semaCtx.IVarContainer)
+	exprContext := "aggregate " + strings.ToUpper(string(opt.Name))
+	p.semaCtx.Properties.Require(exprContext, tree.RejectSpecial|tree.RejectSubqueries)
+	p.semaCtx.IVarContainer = h.Container()
+
+	desired := types.Any
+	if required != nil {
+		desired = required
+	}
+	typedExpr, err := tree.TypeCheck(ctx, expr, &p.semaCtx, desired)
+	if err != nil {
+		return nil, err
+	}
+	if typ := typedExpr.ResolvedType(); required != nil &&
+		typ.Family() != types.UnknownFamily && !typ.Equivalent(required) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"aggregate %s must return type %s, not type %s", opt.Name, required, typ)
+	}
+	return typedExpr, nil
+}
+
+func (n *createAggregateNode) startExec(params runParams) error {
+	p := params.p
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	existing, err := p.findFunctionInSchema(params.ctx, n.schema, n.agg.Name, n.argTypes)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return p.createFunctionDesc(params.ctx, n.dbDesc, n.schema, n.agg, n.argTypes, jobDesc)
+	}
+	if !n.n.Replace {
+		return pgerror.Newf(pgcode.DuplicateFunction, "%s %s already exists",
+			functionDescKind(existing), aggregateSignatureString(n.agg.Name, n.argTypes))
+	}
+	if existing.GetAggregate() == nil {
+		return errors.WithDetailf(
+			pgerror.New(pgcode.WrongObjectType, "cannot change routine kind"),
+			"%q is a %s.", n.agg.Name, functionDescKind(existing))
+	}
+	if err := p.checkFunctionOwnership(params.ctx, existing); err != nil {
+		return err
+	}
+	desc, err := p.Descriptors().GetMutableFunctionByID(
+		params.ctx, p.txn, existing.GetID(), tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	setFunctionDefinition(desc, n.agg)
+	return p.writeFunctionDescChange(params.ctx, desc, jobDesc)
+}
+
+func (n *createAggregateNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createAggregateNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createAggregateNode) Close(context.Context)        {}
@@ -0,0 +1,121 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// PrepareForeignTable is the hook point for the importer code which validates
+// the format options and the data files of a CREATE FOREIGN TABLE statement.
+var PrepareForeignTable = func(
+	ctx context.Context, p PlanHookState, n *tree.CreateForeignTable,
+) (*descpb.TableDescriptor_ForeignTable, error) {
+	return nil, pgerror.New(pgcode.FeatureNotSupported, "foreign tables are not supported")
+}
+
+// ExpandForeignTableFiles is the hook point for the importer code which expands
+// the glob patterns of the data files of a foreign table when it is scanned.
+var ExpandForeignTableFiles = func(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	user username.SQLUsername,
+	foreign *descpb.TableDescriptor_ForeignTable,
+) ([]string, error) {
+	return nil, pgerror.New(pgcode.FeatureNotSupported, "foreign tables are not supported")
+}
+
+// CreateForeignTable creates a read-only table whose rows are read from files
+// in external storage. The columns of a foreign table can only be declared
+// NULL or NOT NULL; the table gets a hidden rowid primary key like any table
+// without a primary key, whose values are synthesized by the scans from the
+// position of the rows in the files.
+// Privileges: CREATE on the schema of the table.
+func (p *planner) CreateForeignTable(
+	ctx context.Context, n *tree.CreateForeignTable,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ForeignTables) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create foreign tables",
+			clusterversion.ByKey(clusterversion.ForeignTables))
+	}
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		n.StatementTag(),
+	); err != nil {
+		return nil, err
+	}
+
+	un := n.Table.ToUnresolvedObjectName()
+	dbDesc, _, prefix, err := p.ResolveTargetObject(ctx, un)
+	if err != nil {
+		return nil, err
+	}
+	n.Table.ObjectNamePrefix = prefix
+
+	for _, def := range n.Defs {
+		d, ok := def.(*tree.ColumnTableDef)
+		if !ok {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"foreign tables cannot have indexes, families or table constraints")
+		}
+		if d.IsSerial || d.GeneratedIdentity.IsGeneratedAsIdentity || d.Hidden ||
+			d.PrimaryKey.IsPrimaryKey || d.Unique.IsUnique || d.DefaultExpr.Expr != nil ||
+			d.OnUpdateExpr.Expr != nil || len(d.CheckExprs) > 0 || d.References.Table != nil ||
+			d.Computed.Computed || d.Family.Name != "" || d.Family.Create {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"column %q of a foreign table can only be declared NULL or NOT NULL", d.Name)
+		}
+		typ, err := tree.ResolveType(ctx, d.Type, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, err
+		}
+		if typ.UserDefined() {
+			return nil, unimplemented.Newf("foreign table user-defined types",
+				"column %q of a foreign table cannot have a user-defined type", d.Name)
+		}
+	}
+
+	return &createTableNode{
+		n: &tree.CreateTable{
+			IfNotExists: n.IfNotExists,
+			Table:       n.Table,
+			Defs:        n.Defs,
+		},
+		dbDesc:  dbDesc,
+		foreign: n,
+	}, nil
+}
+
+// checkTableMatchesForeign returns an error if the table is a foreign table
+// and wantForeign is false, or if it is not and wantForeign is true.
+func checkTableMatchesForeign(desc catalog.TableDescriptor, wantForeign bool) error {
+	isForeign := desc.IsForeign()
+	if isForeign && !wantForeign {
+		err := pgerror.Newf(pgcode.WrongObjectType, "%q is a foreign table", desc.GetName())
+		return errors.WithHint(err, "use the corresponding FOREIGN TABLE command")
+	}
+	if !isForeign && wantForeign {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a foreign table", desc.GetName())
+	}
+	return nil
+}
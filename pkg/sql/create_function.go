@@ -0,0 +1,409 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/plpgsql"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc catalog.DatabaseDescriptor
+	schema catalog.SchemaDescriptor
+	// fn holds the definition of the function, without the fields shared by
+	// all descriptors.
+	fn *descpb.FunctionDescriptor
+}
+
+// CreateFunction creates a user-defined function or procedure, written in
+// PL/pgSQL or in SQL. The function is stored in its own descriptor, and
+// referenced by name by its schema. The body of the function is parsed to
+// report syntax errors, and stored as is.
+// Privileges: CREATE on the schema of the function. The owner of the function
+// is the user who creates it, and the public role is granted EXECUTE.
+//   notes: postgres requires USAGE on the language of the function.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.PLpgSQLFunctions) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create functions",
+			clusterversion.ByKey(clusterversion.PLpgSQLFunctions))
+	}
+	if n.IsProcedure && !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.Procedures) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create procedures",
+			clusterversion.ByKey(clusterversion.Procedures))
+	}
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		n.StatementTag(),
+	); err != nil {
+		return nil, err
+	}
+	kind := functionKind(n.IsProcedure)
+
+	name := &n.FuncName
+	db, schema, _, err := p.ResolveTargetObject(ctx, name.ToUnresolvedObjectName())
+	if err != nil {
+		return nil, err
+	}
+	if db.GetID() == keys.SystemDatabaseID {
+		return nil, errors.Newf("cannot create a %s in the system database", kind)
+	}
+	switch schema.SchemaKind() {
+	case catalog.SchemaUserDefined:
+	case catalog.SchemaTemporary:
+		return nil, unimplemented.NewWithIssuef(83228, "cannot create %ss in a temporary schema", kind)
+	default:
+		// The public schemas of the databases created before public schemas
+		// had descriptors cannot reference functions.
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot create %ss in schema %q", kind, schema.GetName())
+	}
+	if err := p.canCreateOnSchema(
+		ctx, schema.GetID(), db.GetID(), p.User(), checkPublicSchema,
+	); err != nil {
+		return nil, err
+	}
+
+	// Builtin functions shadow the functions of every schema of the search
+	// path, so a function with the name of a builtin could only be used with
+	// a qualified name. Procedures are not resolved like functions, so their
+	// names cannot conflict with builtins or aggregates.
+	if !n.IsProcedure {
+		if _, ok := tree.FunDefs[name.Object()]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateFunction,
+				"function %s conflicts with a builtin function", name.Object())
+		}
+		if hasAggregate, err := p.hasAggregateNamed(ctx, schema, name.Object()); err != nil {
+			return nil, err
+		} else if hasAggregate {
+			return nil, pgerror.Newf(pgcode.DuplicateFunction,
+				"function %s conflicts with a user-defined aggregate", name.Object())
+		}
+	}
+	fn, err := p.makeFunction(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createFunctionNode{n: n, dbDesc: db, schema: schema, fn: fn}, nil
+}
+
+// makeFunction builds the definition of a user-defined function from a CREATE
+// FUNCTION or CREATE PROCEDURE statement.
+func (p *planner) makeFunction(
+	ctx context.Context, n *tree.CreateFunction,
+) (*descpb.FunctionDescriptor, error) {
+	fn := &descpb.FunctionDescriptor{
+		Name:        n.FuncName.Object(),
+		IsProcedure: n.IsProcedure,
+	}
+	var lang tree.FunctionLanguage
+	var hasBody bool
+	seen := make(map[string]struct{}, len(n.Options))
+	for _, opt := range n.Options {
+		var kind string
+		switch t := opt.(type) {
+		case tree.FunctionLanguage:
+			kind = "language"
+			lang = t
+		case tree.FunctionBodyStr:
+			kind = "body"
+			fn.Body = string(t)
+			hasBody = true
+		case tree.FunctionVolatility:
+			kind = "volatility"
+			switch t {
+			case tree.FunctionImmutable:
+				fn.Volatility = descpb.FunctionDescriptor_IMMUTABLE
+			case tree.FunctionStable:
+				fn.Volatility = descpb.FunctionDescriptor_STABLE
+			default:
+				fn.Volatility = descpb.FunctionDescriptor_VOLATILE
+			}
+		case tree.FunctionNullInputBehavior:
+			kind = "null input behavior"
+			fn.Strict = t != tree.FunctionCalledOnNullInput
+		case tree.FunctionLeakProof:
+			kind = "leakproof"
+			if t {
+				return nil, unimplemented.NewWithIssue(83228, "LEAKPROOF functions are not supported")
+			}
+		default:
+			return nil, errors.AssertionFailedf("unexpected function option %T", opt)
+		}
+		if n.IsProcedure && kind != "language" && kind != "body" {
+			return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"invalid attribute in procedure definition")
+		}
+		if _, ok := seen[kind]; ok {
+			return nil, pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		seen[kind] = struct{}{}
+	}
+
+	switch lang {
+	case tree.FunctionLangPLpgSQL:
+		fn.Language = descpb.FunctionDescriptor_PLPGSQL
+	case tree.FunctionLangSQL:
+		fn.Language = descpb.FunctionDescriptor_SQL
+	default:
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified")
+	}
+	if n.RoutineBody != nil {
+		return nil, unimplemented.NewWithIssuef(83228,
+			"%s bodies given with BEGIN ATOMIC are not yet supported", functionKind(n.IsProcedure))
+	}
+	if !hasBody {
+		return nil, pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	if n.ReturnType.IsSet {
+		return nil, unimplemented.NewWithIssue(83228, "functions returning SETOF are not yet supported")
+	}
+
+	names := make(map[tree.Name]struct{}, len(n.Args))
+	for i := range n.Args {
+		arg := &n.Args[i]
+		if arg.Class != tree.FunctionArgIn {
+			return nil, unimplemented.NewWithIssue(83228,
+				"OUT, INOUT and VARIADIC arguments are not yet supported")
+		}
+		if arg.Name != "" {
+			if _, ok := names[arg.Name]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", string(arg.Name))
+			}
+			names[arg.Name] = struct{}{}
+		}
+		typ, err := p.resolveFunctionType(ctx, arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		fnArg := descpb.FunctionDescriptor_Argument{Name: string(arg.Name), Type: typ}
+		if arg.DefaultVal != nil {
+			// The default is type checked like the default of a column, and
+			// evaluated every time the function is called without the argument.
+			typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+				ctx, arg.DefaultVal, typ, "DEFAULT", &p.semaCtx, volatility.Volatile,
+				true, /* allowAssignmentCast */
+			)
+			if err != nil {
+				return nil, err
+			}
+			s := tree.Serialize(typedExpr)
+			fnArg.DefaultExpr = &s
+		} else if i > 0 && fn.Args[i-1].DefaultExpr != nil {
+			return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"input parameters after one with a default value must also have defaults")
+		}
+		fn.Args = append(fn.Args, fnArg)
+	}
+	if n.IsProcedure {
+		fn.ReturnType = types.Void
+	} else if isTriggerReturnType(n.ReturnType.Type) {
+		if len(fn.Args) > 0 {
+			return nil, errors.WithHint(
+				pgerror.New(pgcode.InvalidFunctionDefinition,
+					"trigger functions cannot have declared arguments"),
+				"The row modified by the trigger can be accessed through NEW and OLD instead.")
+		}
+		if fn.Language == descpb.FunctionDescriptor_SQL {
+			return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"SQL functions cannot return type trigger")
+		}
+		fn.ReturnType = types.Void
+		fn.ReturnsTrigger = true
+	} else {
+		var err error
+		if fn.ReturnType, err = p.resolveFunctionType(ctx, n.ReturnType.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	// The body is parsed again when the function is resolved; it is only
+	// parsed here to report syntax errors early.
+	f, err := makePLpgSQLFunction(fn)
+	if err != nil {
+		return nil, err
+	}
+	if f.SQL && fn.ReturnType.Family() != types.VoidFamily {
+		var last *plpgsql.ExecSQL
+		if stmts := f.Body.Body; len(stmts) > 0 {
+			last = stmts[len(stmts)-1].(*plpgsql.ExecSQL)
+		}
+		if last == nil || !last.Result {
+			return nil, errors.WithDetail(
+				pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"return type mismatch in function declared to return %s",
+					fn.ReturnType.SQLString()),
+				"Function's final statement must be SELECT or INSERT/UPDATE/DELETE RETURNING.")
+		}
+	}
+	return fn, nil
+}
+
+// isTriggerReturnType returns whether the return type of a function is the
+// trigger pseudo-type, which cannot be resolved like other types.
+func isTriggerReturnType(ref tree.ResolvableTypeReference) bool {
+	name, ok := ref.(*tree.UnresolvedObjectName)
+	return ok && name.NumParts == 1 && name.Object() == "trigger"
+}
+
+// resolveFunctionType resolves the type of an argument or of the result of a
+// user-defined function.
+func (p *planner) resolveFunctionType(
+	ctx context.Context, ref tree.ResolvableTypeReference,
+) (*types.T, error) {
+	typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	if typ.UserDefined() {
+		return nil, unimplemented.NewWithIssuef(83228,
+			"user-defined types cannot be used by functions: %s", typ.SQLString())
+	}
+	return typ, nil
+}
+
+// setFunctionDefinition replaces the definition of a function, keeping the
+// fields shared by all descriptors.
+func setFunctionDefinition(desc *funcdesc.Mutable, def *descpb.FunctionDescriptor) {
+	desc.Args = def.Args
+	desc.ReturnType = def.ReturnType
+	desc.Language = def.Language
+	desc.Body = def.Body
+	desc.Volatility = def.Volatility
+	desc.Strict = def.Strict
+	desc.IsProcedure = def.IsProcedure
+	desc.ReturnsTrigger = def.ReturnsTrigger
+	desc.Aggregate = def.Aggregate
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	p := params.p
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	argTypes := make([]*types.T, len(n.fn.Args))
+	for i := range n.fn.Args {
+		argTypes[i] = n.fn.Args[i].Type
+	}
+	existing, err := p.findFunctionInSchema(params.ctx, n.schema, n.fn.Name, argTypes)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if !n.n.Replace {
+			return pgerror.Newf(pgcode.DuplicateFunction, "%s %s already exists",
+				functionDescKind(existing), aggregateSignatureString(n.fn.Name, argTypes))
+		}
+		if existing.GetIsProcedure() != n.fn.IsProcedure || existing.GetAggregate() != nil {
+			return errors.WithDetailf(
+				pgerror.New(pgcode.WrongObjectType, "cannot change routine kind"),
+				"%q is a %s.", n.fn.Name, functionDescKind(existing))
+		}
+		if !existing.GetReturnType().Identical(n.fn.ReturnType) ||
+			existing.GetReturnsTrigger() != n.fn.ReturnsTrigger {
+			return pgerror.New(pgcode.InvalidFunctionDefinition,
+				"cannot change return type of existing function")
+		}
+		if err := p.checkFunctionOwnership(params.ctx, existing); err != nil {
+			return err
+		}
+		desc, err := p.Descriptors().GetMutableFunctionByID(
+			params.ctx, p.txn, existing.GetID(), tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		setFunctionDefinition(desc, n.fn)
+		return p.writeFunctionDescChange(params.ctx, desc, jobDesc)
+	}
+	return p.createFunctionDesc(params.ctx, n.dbDesc, n.schema, n.fn, argTypes, jobDesc)
+}
+
+// createFunctionDesc creates the descriptor of a new user-defined function,
+// procedure or aggregate with the given definition, and adds it to the
+// functions of its schema. The function is owned by the current user, and the
+// public role is granted EXECUTE.
+func (p *planner) createFunctionDesc(
+	ctx context.Context,
+	db catalog.DatabaseDescriptor,
+	sc catalog.SchemaDescriptor,
+	def *descpb.FunctionDescriptor,
+	argTypes []*types.T,
+	jobDesc string,
+) error {
+	id, err := descidgen.GenerateUniqueDescID(ctx, p.ExecCfg().DB, p.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+	privs := catpb.NewBasePrivilegeDescriptor(p.User())
+	privs.Grant(username.PublicRoleName(), privilege.List{privilege.EXECUTE}, false /* withGrantOption */)
+	desc := funcdesc.NewInitialFunctionDescriptor(
+		id, db.GetID(), sc.GetID(), def.Name, privs,
+	)
+	setFunctionDefinition(desc, def)
+	mutSchema, err := p.Descriptors().GetMutableDescriptorByID(ctx, p.txn, sc.GetID())
+	if err != nil {
+		return err
+	}
+	scDesc, ok := mutSchema.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("schema %q has no descriptor", sc.GetName())
+	}
+	scDesc.AddFunction(desc.Name, desc.ID, argTypes)
+	if err := p.writeSchemaDescChange(ctx, scDesc, jobDesc); err != nil {
+		return err
+	}
+	return p.writeFunctionDesc(ctx, desc)
+}
+
+// checkFunctionOwnership returns an error unless the user is an admin or owns
+// the given function, which is required to replace or drop it.
+func (p *planner) checkFunctionOwnership(ctx context.Context, fn catalog.FunctionDescriptor) error {
+	isAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	hasOwnership, err := p.HasOwnership(ctx, fn)
+	if err != nil {
+		return err
+	}
+	if !(isAdmin || hasOwnership) {
+		return pgerror.Newf(pgcode.InsufficientPrivilege, "must be owner of %s %s",
+			functionDescKind(fn), aggregateSignatureString(fn.GetName(), fn.ArgTypes()))
+	}
+	return nil
+}
+
+func (n *createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createFunctionNode) Close(context.Context)        {}
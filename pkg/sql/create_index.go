@@ -12,11 +12,14 @@ package sql
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -31,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
@@ -38,6 +42,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -69,6 +75,11 @@ func (p *planner) CreateIndex(ctx context.Context, n *tree.CreateIndex) (planNod
 		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a table or materialized view", tableDesc.Name)
 	}
 
+	if tableDesc.IsForeign() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"cannot create index on foreign table %q", tableDesc.Name)
+	}
+
 	if tableDesc.MaterializedView() {
 		if n.Sharded != nil {
 			return nil, pgerror.New(pgcode.InvalidObjectDefinition,
@@ -300,6 +311,9 @@ func makeIndexDescriptor(
 		if len(indexDesc.InvertedColumnKinds) > 0 && indexDesc.InvertedColumnKinds[0] == catpb.InvertedIndexColumnKind_TRIGRAM {
 			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 		}
+		if !indexDesc.VectorConfig.IsEmpty() {
+			telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+		}
 		if indexDesc.IsPartial() {
 			telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 		}
@@ -412,12 +426,112 @@ func populateInvertedIndexDescriptor(
 			return newUndefinedOpclassError(invCol.OpClass)
 		}
 		indexDesc.InvertedColumnKinds[0] = catpb.InvertedIndexColumnKind_TRIGRAM
+	case types.TSVectorFamily:
+		switch invCol.OpClass {
+		case "tsvector_ops", "":
+		default:
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
+	case types.PGVectorFamily:
+		metric, ok := vecindex.OpClassMetrics[string(invCol.OpClass)]
+		if !ok && invCol.OpClass != "" {
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
+		dims := column.GetType().Width()
+		if dims == 0 {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"column %q does not have dimensions", column.GetName())
+		}
+		// The centroids of the lists are computed once the number of lists is
+		// known, after the storage parameters of the index have been set.
+		indexDesc.VectorConfig = vecindex.Config{
+			Dims:   dims,
+			Metric: metric,
+			Lists:  vecindex.DefaultLists,
+		}
 	default:
 		return tabledesc.NewInvalidInvertedColumnError(column.GetName(), column.GetType().Name())
 	}
 	return nil
 }
 
+// trainVectorIndex computes the centroids of the lists of the given vector
+// index from a random sample of the existing rows of the table. If the table
+// has fewer rows than the index has lists, the remaining centroids are random,
+// which makes the index less accurate.
+func (p *planner) trainVectorIndex(
+	ctx context.Context, tableDesc *tabledesc.Mutable, indexDesc *descpb.IndexDescriptor,
+) error {
+	cfg := &indexDesc.VectorConfig
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	// Use a fixed seed so that the centroids only depend on the sampled rows.
+	rng := rand.New(rand.NewSource(0))
+	numSamples := vecindex.SamplesPerList * int(cfg.Lists)
+	var samples []vector.T
+	// The rows of a table created in the current transaction cannot be read
+	// by the internal executor, so the index is trained without samples.
+	if !tableDesc.IsNew() {
+		col, err := tableDesc.FindColumnWithName(tree.Name(indexDesc.InvertedColumnName()))
+		if err != nil {
+			return err
+		}
+		// The column of an expression index is inaccessible, so the expression
+		// is evaluated instead.
+		var colNameOrExpr string
+		if col.IsExpressionIndexColumn() {
+			colNameOrExpr = col.GetComputeExpr()
+		} else {
+			colNameOrExpr = fmt.Sprintf("%q", col.ColName())
+		}
+		stmt := fmt.Sprintf(
+			`SELECT %[1]s FROM [%[2]d AS t] WHERE %[1]s IS NOT NULL`, colNameOrExpr, tableDesc.GetID(),
+		)
+		if indexDesc.IsPartial() {
+			stmt = fmt.Sprintf(`%s AND (%s)`, stmt, indexDesc.Predicate)
+		}
+		it, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.QueryIteratorEx(
+			ctx, "sample-vector-index", p.txn,
+			sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+			stmt,
+		)
+		if err != nil {
+			return err
+		}
+		// Reservoir sampling keeps a uniform random sample of the rows.
+		var ok bool
+		var seen int
+		for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+			v := tree.MustBeDPGVector(it.Cur()[0]).T
+			if len(samples) < numSamples {
+				samples = append(samples, v)
+			} else if i := rng.Intn(seen + 1); i < numSamples {
+				samples[i] = v
+			}
+			seen++
+		}
+		if err != nil {
+			_ = it.Close()
+			return err
+		}
+		if err := it.Close(); err != nil {
+			return err
+		}
+	}
+	if len(samples) < int(cfg.Lists) {
+		p.BufferClientNotice(ctx, errors.WithHint(
+			errors.WithDetail(
+				pgnotice.Newf("ivfflat index created with little data"),
+				"This will cause low recall.",
+			),
+			"Drop the index until the table has more data.",
+		))
+	}
+	cfg.Train(rng, samples)
+	return nil
+}
+
 func newUndefinedOpclassError(opclass tree.Name) error {
 	return pgerror.Newf(pgcode.UndefinedObject, "operator class %q does not exist", opclass)
 }
@@ -756,6 +870,12 @@ func (n *createIndexNode) startExec(params runParams) error {
 		return err
 	}
 
+	if !indexDesc.VectorConfig.IsEmpty() {
+		if err := params.p.trainVectorIndex(params.ctx, n.tableDesc, indexDesc); err != nil {
+			return err
+		}
+	}
+
 	// Increment the counter if this index could be storing data across multiple column families.
 	if len(indexDesc.StoreColumnNames) > 1 && len(n.tableDesc.Families) > 1 {
 		telemetry.Inc(sqltelemetry.SecondaryIndexColumnFamiliesCounter)
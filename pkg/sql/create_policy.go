@@ -0,0 +1,179 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type createPolicyNode struct {
+	n         *tree.CreatePolicy
+	tableDesc *tabledesc.Mutable
+	policy    descpb.TableDescriptor_Policy
+}
+
+// CreatePolicy creates a row-level security policy on a table.
+// Privileges: ownership of the table.
+//   notes: postgres requires ownership of the table.
+func (p *planner) CreatePolicy(ctx context.Context, n *tree.CreatePolicy) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelSecurity) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create policies",
+			clusterversion.ByKey(clusterversion.RowLevelSecurity))
+	}
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE POLICY",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkCanManagePolicies(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+
+	policy := descpb.TableDescriptor_Policy{
+		Name:        string(n.Name),
+		Restrictive: n.Restrictive,
+	}
+	switch n.Command {
+	case tree.PolicyCommandAll:
+		policy.Command = descpb.TableDescriptor_Policy_ALL
+	case tree.PolicyCommandSelect:
+		policy.Command = descpb.TableDescriptor_Policy_SELECT
+	case tree.PolicyCommandInsert:
+		policy.Command = descpb.TableDescriptor_Policy_INSERT
+	case tree.PolicyCommandUpdate:
+		policy.Command = descpb.TableDescriptor_Policy_UPDATE
+	case tree.PolicyCommandDelete:
+		policy.Command = descpb.TableDescriptor_Policy_DELETE
+	}
+	if n.Using != nil && n.Command == tree.PolicyCommandInsert {
+		return nil, pgerror.New(pgcode.Syntax,
+			"only WITH CHECK expression allowed for INSERT")
+	}
+	if n.WithCheck != nil &&
+		(n.Command == tree.PolicyCommandSelect || n.Command == tree.PolicyCommandDelete) {
+		return nil, pgerror.New(pgcode.Syntax,
+			"WITH CHECK cannot be applied to SELECT or DELETE")
+	}
+
+	roles, err := decodeusername.FromRoleSpecList(
+		p.SessionData(), username.PurposeValidation, n.Roles,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateRoles(ctx, roles, true /* isPublicValid */); err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		policy.RoleNames = append(policy.RoleNames, role.Normalized())
+	}
+
+	policy.UsingExpr, err = p.validatePolicyExpr(ctx, tableDesc, n.Table, n.Using, "POLICY USING")
+	if err != nil {
+		return nil, err
+	}
+	policy.WithCheckExpr, err = p.validatePolicyExpr(
+		ctx, tableDesc, n.Table, n.WithCheck, "POLICY WITH CHECK",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createPolicyNode{n: n, tableDesc: tableDesc, policy: policy}, nil
+}
+
+// checkCanManagePolicies returns an error if the current user cannot create,
+// drop or enable the row-level security policies of the given table.
+func (p *planner) checkCanManagePolicies(ctx context.Context, tableDesc catalog.TableDescriptor) error {
+	hasAdminRole, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdminRole {
+		return nil
+	}
+	hasOwnership, err := p.HasOwnership(ctx, tableDesc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of table %s", tree.Name(tableDesc.GetName()))
+	}
+	return nil
+}
+
+// validatePolicyExpr validates an expression of a policy and returns its
+// serialized form, or the empty string if the expression is nil. The
+// expression may be volatile, since it is evaluated for every row on which the
+// policy is enforced.
+func (p *planner) validatePolicyExpr(
+	ctx context.Context, tableDesc catalog.TableDescriptor, tn tree.TableName, expr tree.Expr, op string,
+) (string, error) {
+	if expr == nil {
+		return "", nil
+	}
+	serialized, _, _, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx,
+		tableDesc,
+		expr,
+		types.Bool,
+		op,
+		p.SemaCtx(),
+		volatility.Volatile,
+		&tn,
+	)
+	return serialized, err
+}
+
+func (n *createPolicyNode) startExec(params runParams) error {
+	tableDesc := n.tableDesc
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].Name == n.policy.Name {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"policy %q for table %q already exists", n.policy.Name, tableDesc.Name)
+		}
+	}
+	tableDesc.Policies = append(tableDesc.Policies, n.policy)
+	if err := validateDescriptor(params.ctx, params.p, tableDesc); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *createPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPolicyNode) Close(context.Context)        {}
@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type createPublicationNode struct {
+	n      *tree.CreatePublication
+	dbDesc *dbdesc.Mutable
+	pub    descpb.DatabaseDescriptor_Publication
+}
+
+// CreatePublication creates a publication in the current database. The
+// changes to the tables of a publication can be streamed to clients of the
+// logical replication protocol.
+// Privileges: CREATE on database, and SELECT on the published tables.
+//   notes: postgres requires ownership of the published tables, and
+//          superuser privileges for FOR ALL TABLES.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.Publications) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create publications",
+			clusterversion.ByKey(clusterversion.Publications))
+	}
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+
+	// Publications are stored in the database descriptor, so the session must
+	// be connected to a database.
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot create publication without being connected to a database")
+	}
+	dbDesc, err := p.Descriptors().GetMutableDatabaseByName(ctx, p.txn, p.CurrentDatabase(),
+		tree.DatabaseLookupFlags{Required: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if n.AllTables {
+		if err := p.RequireAdminRole(ctx, "CREATE PUBLICATION FOR ALL TABLES"); err != nil {
+			return nil, err
+		}
+	}
+
+	pub := descpb.DatabaseDescriptor_Publication{
+		Name:      string(n.Name),
+		AllTables: n.AllTables,
+	}
+	for i := range n.Tables {
+		tableDesc, err := p.ResolveExistingObjectEx(
+			ctx, n.Tables[i].ToUnresolvedObjectName(), true /* required */, tree.ResolveRequireTableDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if tableDesc.IsVirtualTable() || tableDesc.IsTemporary() {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add relation %q to publication: only persistent tables can be published",
+				tableDesc.GetName())
+		}
+		if tableDesc.GetParentID() != dbDesc.GetID() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot add relation %q to publication: it is not in database %q",
+				tableDesc.GetName(), dbDesc.GetName())
+		}
+		if err := p.CheckPrivilege(ctx, tableDesc, privilege.SELECT); err != nil {
+			return nil, err
+		}
+		duplicate := false
+		for _, id := range pub.TableIDs {
+			duplicate = duplicate || id == tableDesc.GetID()
+		}
+		if duplicate {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"relation %q is already member of publication %q", tableDesc.GetName(), pub.Name)
+		}
+		pub.TableIDs = append(pub.TableIDs, tableDesc.GetID())
+	}
+
+	return &createPublicationNode{n: n, dbDesc: dbDesc, pub: pub}, nil
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	for i := range n.dbDesc.Publications {
+		if n.dbDesc.Publications[i].Name == n.pub.Name {
+			return pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", n.pub.Name)
+		}
+	}
+	n.dbDesc.Publications = append(n.dbDesc.Publications, n.pub)
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPublicationNode) Close(context.Context)        {}
@@ -231,6 +231,12 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		)
 	}
 
+	if tableDesc.IsForeign() {
+		return nil, pgerror.New(
+			pgcode.WrongObjectType, "cannot create statistics on foreign tables",
+		)
+	}
+
 	if tableDesc.GetID() == keys.TableStatisticsTableID {
 		return nil, pgerror.New(
 			pgcode.WrongObjectType, "cannot create statistics on system.table_statistics",
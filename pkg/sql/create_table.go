@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"go/constant"
+	"math/rand"
 	"strconv"
 	"strings"
 	"time"
@@ -71,6 +72,17 @@ type createTableNode struct {
 	n          *tree.CreateTable
 	dbDesc     catalog.DatabaseDescriptor
 	sourcePlan planNode
+	// foreign is set when the table is created by CREATE FOREIGN TABLE, in
+	// which case n holds the column definitions of the statement.
+	foreign *tree.CreateForeignTable
+}
+
+// stmt returns the statement which creates the table.
+func (n *createTableNode) stmt() tree.Statement {
+	if n.foreign != nil {
+		return n.foreign
+	}
+	return n.n
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -251,7 +263,11 @@ func hasPrimaryKeySerialType(params runParams, colDef *tree.ColumnTableDef) (boo
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
+	if n.foreign != nil {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("foreign_table"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
+	}
 
 	colsWithPrimaryKeyConstraint := make(map[tree.Name]bool)
 
@@ -400,6 +416,11 @@ func (n *createTableNode) startExec(params runParams) error {
 		if err != nil {
 			return err
 		}
+		if n.foreign != nil {
+			if desc.Foreign, err = PrepareForeignTable(params.ctx, params.p, n.foreign); err != nil {
+				return err
+			}
+		}
 
 		if desc.Adding() {
 			// if this table and all its references are created in the same
@@ -429,7 +450,7 @@ func (n *createTableNode) startExec(params runParams) error {
 		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, n.dbDesc.GetID(), schema.GetID(), n.n.Table.Table()),
 		id,
 		desc,
-		tree.AsStringWithFQNames(n.n, params.Ann()),
+		tree.AsStringWithFQNames(n.stmt(), params.Ann()),
 	); err != nil {
 		return err
 	}
@@ -671,6 +692,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.ConstraintNotDeferrable,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -726,8 +748,13 @@ func addUniqueWithoutIndexTableDef(
 	for i := range colNames {
 		colNames[i] = string(d.Columns[i].Column)
 	}
+	if d.Deferrability != tree.ConstraintNotDeferrable {
+		if err := checkDeferrableConstraintsSupported(ctx, evalCtx); err != nil {
+			return err
+		}
+	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrability, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -747,6 +774,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -800,12 +828,13 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:         constraintName,
-		TableID:      tbl.ID,
-		ColumnIDs:    columnIDs,
-		Predicate:    predicate,
-		Validity:     validity,
-		ConstraintID: tbl.NextConstraintID,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		ConstraintID:  tbl.NextConstraintID,
+		Deferrability: descpb.ConstraintDeferrabilityValue[deferrability],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -817,6 +846,23 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// errDeferrableUniqueIndex is returned when a unique constraint which is
+// enforced by an index is marked DEFERRABLE. Index keys are checked for
+// uniqueness as soon as they are written, so these checks cannot be deferred.
+var errDeferrableUniqueIndex = unimplemented.NewWithIssue(31632,
+	"only UNIQUE WITHOUT INDEX constraints can be DEFERRABLE")
+
+// checkDeferrableConstraintsSupported returns an error if deferrable
+// constraints cannot be created yet because the cluster version is too old.
+func checkDeferrableConstraintsSupported(ctx context.Context, evalCtx *eval.Context) error {
+	if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create deferrable constraints",
+			clusterversion.ByKey(clusterversion.DeferrableConstraints))
+	}
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -1041,6 +1087,19 @@ func ResolveFK(
 		return err
 	}
 
+	if d.Deferrability != tree.ConstraintNotDeferrable {
+		if err := checkDeferrableConstraintsSupported(ctx, evalCtx); err != nil {
+			return err
+		}
+		// A deferred check only verifies that the recorded keys have a match in
+		// the referenced table, so it cannot detect keys which mix null and
+		// non-null values.
+		if d.Match == tree.MatchFull {
+			return unimplemented.NewWithIssue(31632,
+				"deferrable foreign keys with MATCH FULL are not supported")
+		}
+	}
+
 	var validity descpb.ConstraintValidity
 	if ts != NewTable {
 		if validationBehavior == tree.ValidationSkip {
@@ -1061,6 +1120,7 @@ func ResolveFK(
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
 		ConstraintID:        tbl.NextConstraintID,
+		Deferrability:       descpb.ConstraintDeferrabilityValue[d.Deferrability],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1870,6 +1930,14 @@ func NewTableDesc(
 			); err != nil {
 				return nil, err
 			}
+			if !idx.VectorConfig.IsEmpty() {
+				if err := idx.VectorConfig.Validate(); err != nil {
+					return nil, err
+				}
+				// A new table has no rows to sample, so the centroids of the lists
+				// are random.
+				idx.VectorConfig.Train(rand.New(rand.NewSource(0)), nil /* samples */)
+			}
 
 			if err := desc.AddSecondaryIndex(idx); err != nil {
 				return nil, err
@@ -1879,6 +1947,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrability != tree.ConstraintNotDeferrable {
+				return nil, errDeferrableUniqueIndex
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
 			// AllocateIDs is called.
@@ -1987,7 +2058,8 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -2187,6 +2259,13 @@ func NewTableDesc(
 				return nil, err
 			}
 
+		case *tree.ExclusionConstraintTableDef:
+			if err := addExclusionConstraintTableDef(
+				ctx, evalCtx, d, &desc, n.Table, NewTable, tree.ValidationDefault, semaCtx,
+			); err != nil {
+				return nil, err
+			}
+
 		default:
 			return nil, errors.Errorf("unsupported table def: %T", def)
 		}
@@ -2242,6 +2321,9 @@ func NewTableDesc(
 			if idx.InvertedColumnKind() == catpb.InvertedIndexColumnKind_TRIGRAM {
 				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 			}
+			if !idx.GetVectorConfig().IsEmpty() {
+				telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+			}
 			if idx.IsPartial() {
 				telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 			}
@@ -0,0 +1,409 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *tabledesc.Mutable
+	trigger   descpb.TableDescriptor_Trigger
+}
+
+// CreateTrigger creates a row-level trigger on a table.
+// Privileges: CREATE on table, and EXECUTE on the trigger function.
+//   notes: postgres requires TRIGGER on the table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelTriggers) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create triggers",
+			clusterversion.ByKey(clusterversion.RowLevelTriggers))
+	}
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	trigger := descpb.TableDescriptor_Trigger{
+		Name: string(n.Name),
+	}
+	switch n.ActionTime {
+	case tree.TriggerActionTimeBefore:
+		trigger.ActionTime = descpb.TableDescriptor_Trigger_BEFORE
+	case tree.TriggerActionTimeAfter:
+		trigger.ActionTime = descpb.TableDescriptor_Trigger_AFTER
+	}
+	for _, event := range n.Events {
+		var e descpb.TableDescriptor_Trigger_Event
+		switch event {
+		case tree.TriggerEventInsert:
+			e = descpb.TableDescriptor_Trigger_INSERT
+		case tree.TriggerEventUpdate:
+			e = descpb.TableDescriptor_Trigger_UPDATE
+		case tree.TriggerEventDelete:
+			e = descpb.TableDescriptor_Trigger_DELETE
+		}
+		for _, other := range trigger.Events {
+			if other == e {
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"duplicate trigger events specified")
+			}
+		}
+		trigger.Events = append(trigger.Events, e)
+	}
+
+	if n.FuncName != nil {
+		if n.ActionTime == tree.TriggerActionTimeBefore {
+			// The row returned by the trigger function would have to replace the
+			// row of the mutation, which is not supported yet.
+			return nil, unimplemented.NewWithIssue(83228,
+				"BEFORE triggers executing trigger functions are not supported")
+		}
+		fn, err := p.resolveTriggerFunction(ctx, tableDesc, n.FuncName)
+		if err != nil {
+			return nil, err
+		}
+		trigger.FunctionID = fn.GetID()
+		trigger.FunctionName = fn.GetName()
+		return &createTriggerNode{n: n, tableDesc: tableDesc, trigger: trigger}, nil
+	}
+
+	if len(n.Body.Stmts) == 0 {
+		return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"trigger %q must contain at least one statement", n.Name)
+	}
+	var deps catalog.DescriptorIDSet
+	for i, stmt := range n.Body.Stmts {
+		isLast := i == len(n.Body.Stmts)-1
+		if err := checkTriggerBodyStmt(
+			stmt, isLast && n.ActionTime == tree.TriggerActionTimeBefore,
+		); err != nil {
+			return nil, err
+		}
+		// Resolve the relations referenced by the statement, so that they can't
+		// be dropped or renamed while the trigger exists. Their names are
+		// qualified in place, so that the trigger is not affected by changes to
+		// the search path.
+		for _, tn := range triggerBodyTableNames(stmt) {
+			_, desc, err := p.ResolveMutableTableDescriptor(
+				ctx, tn, true /* required */, tree.ResolveAnyTableKind,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if desc.GetID() != tableDesc.GetID() {
+				deps.Add(desc.GetID())
+			}
+		}
+		// Serialize user defined types so that renaming a type referenced by the
+		// trigger does not corrupt it.
+		sql, err := serializeUserDefinedTypes(ctx, p.SemaCtx(), tree.AsStringWithFlags(stmt, tree.FmtParsable))
+		if err != nil {
+			return nil, err
+		}
+		trigger.Body = append(trigger.Body, sql)
+	}
+	trigger.DependsOn = deps.Ordered()
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, trigger: trigger}, nil
+}
+
+// resolveTriggerFunction returns the trigger function executed by a trigger on
+// the given table. The function must belong to the database of the table, and
+// the user must have the EXECUTE privilege on it.
+func (p *planner) resolveTriggerFunction(
+	ctx context.Context, tableDesc catalog.TableDescriptor, name *tree.FunctionName,
+) (catalog.FunctionDescriptor, error) {
+	_, db, err := p.Descriptors().GetImmutableDatabaseByID(
+		ctx, p.txn, tableDesc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if name.ExplicitCatalog && string(name.CatalogName) != db.GetName() {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cross-database function references not allowed: %s", tree.ErrString(name))
+	}
+	fn, err := p.findFunction(ctx, db, name, nil /* argTypes */)
+	if err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return nil, errFunctionDoesNotExist(false /* isProcedure */, name.Object(), nil /* argTypes */)
+	}
+	if !fn.GetReturnsTrigger() {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", name.Object())
+	}
+	if err := p.CheckPrivilege(ctx, fn, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+// checkTriggerBodyStmt returns an error if the given statement cannot be used
+// in the body of a trigger. The statements of a trigger are executed in the
+// same way as foreign key cascades, so only mutations which do not return any
+// rows are allowed. The exception is the last statement of a BEFORE trigger,
+// which can be a query returning the new row (or no row, to skip the row).
+func checkTriggerBodyStmt(stmt tree.Statement, allowSelect bool) error {
+	var returning tree.ReturningClause
+	switch t := stmt.(type) {
+	case *tree.Select:
+		if !allowSelect {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"SELECT is only supported as the last statement of a BEFORE trigger")
+		}
+		return nil
+	case *tree.Insert:
+		returning = t.Returning
+	case *tree.Update:
+		returning = t.Returning
+	case *tree.Delete:
+		returning = t.Returning
+	default:
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s statements are not supported in trigger bodies", stmt.StatementTag())
+	}
+	if tree.HasReturningClause(returning) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"RETURNING is not supported in trigger bodies")
+	}
+	return nil
+}
+
+// triggerBodyTableNames returns the names of the relations referenced by a
+// statement in the body of a trigger. Names which refer to common table
+// expressions are not included.
+func triggerBodyTableNames(stmt tree.Statement) []*tree.TableName {
+	var c triggerTableCollector
+	c.walkStmt(stmt)
+	names := c.names[:0]
+	for _, tn := range c.names {
+		if !tn.ExplicitSchema {
+			if _, ok := c.ctes[tn.ObjectName]; ok {
+				continue
+			}
+		}
+		names = append(names, tn)
+	}
+	return names
+}
+
+type triggerTableCollector struct {
+	names []*tree.TableName
+	ctes  map[tree.Name]struct{}
+}
+
+func (c *triggerTableCollector) walkWith(with *tree.With) {
+	if with == nil {
+		return
+	}
+	for _, cte := range with.CTEList {
+		if c.ctes == nil {
+			c.ctes = make(map[tree.Name]struct{})
+		}
+		c.ctes[cte.Name.Alias] = struct{}{}
+		c.walkStmt(cte.Stmt)
+	}
+}
+
+func (c *triggerTableCollector) walkStmt(stmt tree.Statement) {
+	switch t := stmt.(type) {
+	case *tree.Insert:
+		c.walkWith(t.With)
+		c.walkTableExpr(t.Table)
+		if t.Rows != nil {
+			c.walkStmt(t.Rows)
+		}
+	case *tree.Update:
+		c.walkWith(t.With)
+		c.walkTableExpr(t.Table)
+		for _, expr := range t.From {
+			c.walkTableExpr(expr)
+		}
+	case *tree.Delete:
+		c.walkWith(t.With)
+		c.walkTableExpr(t.Table)
+		for _, expr := range t.Using {
+			c.walkTableExpr(expr)
+		}
+	case *tree.Select:
+		c.walkWith(t.With)
+		c.walkStmt(t.Select)
+	case *tree.ParenSelect:
+		c.walkStmt(t.Select)
+	case *tree.UnionClause:
+		c.walkStmt(t.Left)
+		c.walkStmt(t.Right)
+	case *tree.SelectClause:
+		for _, expr := range t.From.Tables {
+			c.walkTableExpr(expr)
+		}
+	}
+	// Find the subqueries in the expressions of the statement.
+	_, _ = tree.SimpleStmtVisit(stmt, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if sub, ok := expr.(*tree.Subquery); ok {
+			c.walkStmt(sub.Select)
+			return false, expr, nil
+		}
+		return true, expr, nil
+	})
+}
+
+func (c *triggerTableCollector) walkTableExpr(expr tree.TableExpr) {
+	switch t := expr.(type) {
+	case *tree.TableName:
+		c.names = append(c.names, t)
+	case *tree.AliasedTableExpr:
+		c.walkTableExpr(t.Expr)
+	case *tree.ParenTableExpr:
+		c.walkTableExpr(t.Expr)
+	case *tree.JoinTableExpr:
+		c.walkTableExpr(t.Left)
+		c.walkTableExpr(t.Right)
+	case *tree.Subquery:
+		c.walkStmt(t.Select)
+	case *tree.StatementSource:
+		c.walkStmt(t.Statement)
+	}
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	tableDesc := n.tableDesc
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name != n.trigger.Name {
+			continue
+		}
+		if !n.n.Replace {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", n.trigger.Name, tableDesc.Name)
+		}
+		oldDeps := tableDesc.Triggers[i].DependsOn
+		tableDesc.Triggers[i] = n.trigger
+		return n.finish(params, oldDeps)
+	}
+	tableDesc.Triggers = append(tableDesc.Triggers, n.trigger)
+	return n.finish(params, nil /* oldDeps */)
+}
+
+func (n *createTriggerNode) finish(params runParams, oldDeps []descpb.ID) error {
+	deps := catalog.MakeDescriptorIDSet(oldDeps...)
+	for _, id := range n.trigger.DependsOn {
+		deps.Add(id)
+	}
+	if err := params.p.updateTriggerBackReferences(params.ctx, n.tableDesc, deps); err != nil {
+		return err
+	}
+	if err := validateDescriptor(params.ctx, params.p, n.tableDesc); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// updateTriggerBackReferences updates the back-references from the given
+// relations to tableDesc, so that a relation has a back-reference if and only
+// if one of the triggers of tableDesc depends on it.
+func (p *planner) updateTriggerBackReferences(
+	ctx context.Context, tableDesc *tabledesc.Mutable, ids catalog.DescriptorIDSet,
+) error {
+	for _, id := range ids.Ordered() {
+		var used bool
+		for i := range tableDesc.Triggers {
+			used = used || triggerDependsOn(&tableDesc.Triggers[i], id)
+		}
+		if err := p.setTriggerBackReference(ctx, tableDesc, id, used); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTriggerBackReference adds or removes the back-reference from the relation
+// with the given ID to the triggers of tableDesc.
+func (p *planner) setTriggerBackReference(
+	ctx context.Context, tableDesc *tabledesc.Mutable, id descpb.ID, used bool,
+) error {
+	depDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+	if err != nil {
+		return err
+	}
+	if depDesc.Dropped() {
+		// The relation is being dropped, so we don't have to update it.
+		return nil
+	}
+	refIdx := -1
+	for i := range depDesc.DependedOnBy {
+		if ref := &depDesc.DependedOnBy[i]; ref.ID == tableDesc.ID && ref.Trigger {
+			refIdx = i
+			break
+		}
+	}
+	switch {
+	case used && refIdx == -1:
+		depDesc.DependedOnBy = append(depDesc.DependedOnBy, descpb.TableDescriptor_Reference{
+			ID:      tableDesc.ID,
+			Trigger: true,
+		})
+	case !used && refIdx != -1:
+		depDesc.DependedOnBy = append(depDesc.DependedOnBy[:refIdx], depDesc.DependedOnBy[refIdx+1:]...)
+	default:
+		return nil
+	}
+	return p.writeSchemaChange(
+		ctx, depDesc, descpb.InvalidMutationID,
+		fmt.Sprintf("updating trigger references for table %s(%d) in %s(%d)",
+			tableDesc.Name, tableDesc.ID, depDesc.Name, depDesc.ID),
+	)
+}
+
+// triggerDependsOn returns true if the statements of the trigger reference the
+// relation with the given ID.
+func triggerDependsOn(trig *descpb.TableDescriptor_Trigger, id descpb.ID) bool {
+	for _, depID := range trig.DependsOn {
+		if depID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createTriggerNode) Close(context.Context)        {}
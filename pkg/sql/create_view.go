@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -71,6 +72,9 @@ type createViewNode struct {
 	// withData indicates if a materialized view should be populated
 	// with data by executing the underlying query.
 	withData bool
+	// incrementalRefresh indicates if a materialized view should be
+	// maintained incrementally as the tables it depends on are modified.
+	incrementalRefresh bool
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -88,6 +92,14 @@ func (n *createViewNode) startExec(params runParams) error {
 		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter(tableType))
 	}
 
+	if n.incrementalRefresh && !params.ExecCfg().Settings.Version.IsActive(
+		params.ctx, clusterversion.IncrementalMaterializedViews,
+	) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create incrementally refreshed materialized views",
+			clusterversion.ByKey(clusterversion.IncrementalMaterializedViews))
+	}
+
 	viewName := n.viewName.Object()
 	log.VEventf(params.ctx, 2, "dependencies for view %s:\n%s", viewName, n.planDeps.String())
 
@@ -249,6 +261,11 @@ func (n *createViewNode) startExec(params runParams) error {
 					// on it.
 					desc.RefreshViewRequired = !n.withData
 					desc.State = descpb.DescriptorState_ADD
+					if n.incrementalRefresh {
+						if err := n.makeIncrementalRefresh(params, &desc); err != nil {
+							return err
+						}
+					}
 					version := params.ExecCfg().Settings.Version.ActiveVersion(params.ctx)
 					if err := desc.AllocateIDs(params.ctx, version); err != nil {
 						return err
@@ -0,0 +1,408 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// deferredChecksBatchSize is the maximum number of keys that are re-checked by
+// a single query when validating deferred constraint checks.
+const deferredChecksBatchSize = 1000
+
+// deferredChecks tracks the DEFERRABLE constraints whose checks have been
+// deferred to the end of the current transaction.
+type deferredChecks interface {
+	// isDeferred returns whether the given check should be deferred to the end
+	// of the transaction rather than performed immediately.
+	isDeferred(check *exec.DeferrableCheck) bool
+	// queue records that the key with the given values must be re-checked
+	// before the transaction commits. err is the error that is returned if the
+	// key still violates the constraint at that point.
+	queue(check *exec.DeferrableCheck, keyVals tree.Datums, err error)
+	// setMode changes whether the constraints with the given names are deferred
+	// for the rest of the transaction. If names is empty, all constraints are
+	// changed. It returns the queued checks which are no longer deferred and
+	// must be validated immediately.
+	setMode(names tree.NameList, deferred bool) []*pendingCheck
+	// takePending removes and returns all of the queued checks.
+	takePending() []*pendingCheck
+}
+
+// deferredChecksState is the transaction state behind deferredChecks.
+type deferredChecksState struct {
+	// allMode is set if SET CONSTRAINTS ALL has been used in the transaction;
+	// the value indicates whether the constraints were deferred.
+	allMode *bool
+	// modes contains the constraints set with SET CONSTRAINTS since the last
+	// SET CONSTRAINTS ALL, by name.
+	modes map[tree.Name]bool
+	// pending contains the checks that must be performed before the
+	// transaction commits.
+	pending map[pendingCheckKey]*pendingCheck
+}
+
+// pendingCheckKey identifies a constraint with queued checks.
+type pendingCheckKey struct {
+	tableID    descpb.ID
+	name       string
+	foreignKey bool
+}
+
+// pendingCheck contains the keys of a constraint that must be re-checked
+// before the transaction commits.
+type pendingCheck struct {
+	pendingCheckKey
+	// keys maps the string representation of each queued key to its values.
+	keys map[string]tree.Datums
+	// errs maps the string representation of each queued key to the error to
+	// return if the key violates the constraint.
+	errs map[string]error
+}
+
+func (s *deferredChecksState) reset() {
+	*s = deferredChecksState{}
+}
+
+func (s *deferredChecksState) isDeferred(check *exec.DeferrableCheck) bool {
+	if deferred, ok := s.modes[tree.Name(check.ConstraintName)]; ok {
+		return deferred
+	}
+	if s.allMode != nil {
+		return *s.allMode
+	}
+	return check.InitiallyDeferred
+}
+
+func (s *deferredChecksState) queue(
+	check *exec.DeferrableCheck, keyVals tree.Datums, err error,
+) {
+	key := pendingCheckKey{
+		tableID:    descpb.ID(check.TableID),
+		name:       check.ConstraintName,
+		foreignKey: check.ForeignKey,
+	}
+	if s.pending == nil {
+		s.pending = make(map[pendingCheckKey]*pendingCheck)
+	}
+	c, ok := s.pending[key]
+	if !ok {
+		c = &pendingCheck{
+			pendingCheckKey: key,
+			keys:            make(map[string]tree.Datums),
+			errs:            make(map[string]error),
+		}
+		s.pending[key] = c
+	}
+	str := tree.AsString(&keyVals)
+	if _, ok := c.keys[str]; !ok {
+		c.keys[str] = keyVals
+		c.errs[str] = err
+	}
+}
+
+func (s *deferredChecksState) setMode(names tree.NameList, deferred bool) []*pendingCheck {
+	if len(names) == 0 {
+		s.allMode = &deferred
+		s.modes = nil
+	} else {
+		if s.modes == nil {
+			s.modes = make(map[tree.Name]bool)
+		}
+		for _, name := range names {
+			s.modes[name] = deferred
+		}
+	}
+	if deferred {
+		return nil
+	}
+	var res []*pendingCheck
+	for key, c := range s.pending {
+		if len(names) == 0 || containsName(names, tree.Name(key.name)) {
+			res = append(res, c)
+			delete(s.pending, key)
+		}
+	}
+	return res
+}
+
+func containsName(names tree.NameList, name tree.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *deferredChecksState) takePending() []*pendingCheck {
+	res := make([]*pendingCheck, 0, len(s.pending))
+	for _, c := range s.pending {
+		res = append(res, c)
+	}
+	s.pending = nil
+	return res
+}
+
+type connExDeferredChecksAccessor struct {
+	ex *connExecutor
+}
+
+func (c connExDeferredChecksAccessor) isDeferred(check *exec.DeferrableCheck) bool {
+	// Internal executors may run in a transaction which is committed by their
+	// caller, so the deferred checks would never be validated.
+	if c.ex.executorType == executorTypeInternal {
+		return false
+	}
+	return c.ex.extraTxnState.deferredChecks.isDeferred(check)
+}
+
+func (c connExDeferredChecksAccessor) queue(
+	check *exec.DeferrableCheck, keyVals tree.Datums, err error,
+) {
+	c.ex.extraTxnState.deferredChecks.queue(check, keyVals, err)
+}
+
+func (c connExDeferredChecksAccessor) setMode(
+	names tree.NameList, deferred bool,
+) []*pendingCheck {
+	return c.ex.extraTxnState.deferredChecks.setMode(names, deferred)
+}
+
+func (c connExDeferredChecksAccessor) takePending() []*pendingCheck {
+	return c.ex.extraTxnState.deferredChecks.takePending()
+}
+
+// emptyDeferredChecks is the default impl used by the planner when the
+// connExecutor is not available. Checks are never deferred.
+type emptyDeferredChecks struct{}
+
+func (emptyDeferredChecks) isDeferred(check *exec.DeferrableCheck) bool {
+	return false
+}
+
+func (emptyDeferredChecks) queue(check *exec.DeferrableCheck, keyVals tree.Datums, err error) {}
+
+func (emptyDeferredChecks) setMode(names tree.NameList, deferred bool) []*pendingCheck {
+	return nil
+}
+
+func (emptyDeferredChecks) takePending() []*pendingCheck {
+	return nil
+}
+
+// validatePendingChecks verifies that none of the keys queued by the given
+// checks violate their constraints.
+func (p *planner) validatePendingChecks(ctx context.Context, checks []*pendingCheck) error {
+	// Validate the checks in a deterministic order, so that the same error is
+	// returned if multiple constraints are violated.
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].tableID != checks[j].tableID {
+			return checks[i].tableID < checks[j].tableID
+		}
+		return checks[i].name < checks[j].name
+	})
+	for _, c := range checks {
+		tableDesc, err := p.Descriptors().GetImmutableTableByID(
+			ctx, p.Txn(), c.tableID, tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) ||
+				pgerror.GetPGCode(err) == pgcode.UndefinedTable {
+				// The table was dropped in the transaction.
+				continue
+			}
+			return err
+		}
+		keys := make([]string, 0, len(c.keys))
+		for k := range c.keys {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for len(keys) > 0 {
+			batch := keys
+			if len(batch) > deferredChecksBatchSize {
+				batch = batch[:deferredChecksBatchSize]
+			}
+			keys = keys[len(batch):]
+			if err := p.validatePendingCheckBatch(ctx, tableDesc, c, batch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validatePendingCheckBatch verifies that none of the given keys of the check
+// violate its constraint.
+func (p *planner) validatePendingCheckBatch(
+	ctx context.Context, tableDesc catalog.TableDescriptor, c *pendingCheck, keys []string,
+) error {
+	tuples := make([]string, len(keys))
+	for i, k := range keys {
+		vals := c.keys[k]
+		strs := make([]string, len(vals))
+		for j := range vals {
+			strs[j] = tree.AsStringWithFlags(vals[j], tree.FmtParsable)
+		}
+		tuples[i] = fmt.Sprintf("(%s)", strings.Join(strs, ", "))
+	}
+
+	var query string
+	var colNames []string
+	if c.foreignKey {
+		if err := tableDesc.ForeachOutboundFK(func(fk *descpb.ForeignKeyConstraint) error {
+			if fk.Name != c.name {
+				return nil
+			}
+			targetDesc, err := p.Descriptors().GetImmutableTableByID(
+				ctx, p.Txn(), fk.ReferencedTableID, tree.ObjectLookupFlagsWithRequired(),
+			)
+			if err != nil {
+				return err
+			}
+			query, colNames, err = deferredForeignKeyQuery(tableDesc, fk, targetDesc, tuples)
+			return err
+		}); err != nil {
+			return err
+		}
+	} else {
+		ucs := tableDesc.GetUniqueWithoutIndexConstraints()
+		for i := range ucs {
+			if ucs[i].Name != c.name {
+				continue
+			}
+			var err error
+			query, colNames, err = deferredUniqueQuery(tableDesc, &ucs[i], tuples)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if query == "" {
+		// The constraint was dropped in the transaction.
+		return nil
+	}
+
+	log.VEventf(ctx, 2, "validating deferred constraint %q on %q with query %q",
+		c.name, tableDesc.GetName(), query)
+
+	values, err := p.QueryRowEx(
+		ctx, "validate deferred constraint", sessiondata.NodeUserSessionDataOverride, query,
+	)
+	if err != nil {
+		return err
+	}
+	if values.Len() == 0 {
+		return nil
+	}
+	if err, ok := c.errs[tree.AsString(&values)]; ok {
+		return err
+	}
+	code := pgcode.UniqueViolation
+	if c.foreignKey {
+		code = pgcode.ForeignKeyViolation
+	}
+	return pgerror.WithConstraintName(pgerror.Newf(code,
+		"deferred constraint %q on %q is violated by row %s",
+		c.name, tableDesc.GetName(), formatValues(colNames, values),
+	), c.name)
+}
+
+// deferredForeignKeyQuery generates and returns a query for the rows of the
+// origin table with any of the given keys that have no matching row in the
+// referenced table. The query returns the origin columns of the FK, in FK
+// order.
+func deferredForeignKeyQuery(
+	srcTbl catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	targetTbl catalog.TableDescriptor,
+	tuples []string,
+) (sql string, originColNames []string, _ error) {
+	originColNames, err := srcTbl.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	referencedColNames, err := targetTbl.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	nCols := len(fk.OriginColumnIDs)
+	srcCols := make([]string, nCols)
+	qualifiedSrcCols := make([]string, nCols)
+	targetCols := make([]string, nCols)
+	on := make([]string, nCols)
+	for i := 0; i < nCols; i++ {
+		// s and t are table aliases used in the query.
+		srcCols[i] = tree.NameString(originColNames[i])
+		qualifiedSrcCols[i] = fmt.Sprintf("s.%s", srcCols[i])
+		targetCols[i] = fmt.Sprintf("t.%s", tree.NameString(referencedColNames[i]))
+		on[i] = fmt.Sprintf("%s = %s", qualifiedSrcCols[i], targetCols[i])
+	}
+
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM
+		  (SELECT %[2]s FROM [%[3]d AS src]@{IGNORE_FOREIGN_KEYS} WHERE (%[2]s) IN (%[4]s)) AS s
+			LEFT OUTER JOIN
+			[%[5]d AS target] AS t
+			ON %[6]s
+		 WHERE %[7]s IS NULL LIMIT 1`,
+		strings.Join(qualifiedSrcCols, ", "), // 1
+		strings.Join(srcCols, ", "),          // 2
+		srcTbl.GetID(),                       // 3
+		strings.Join(tuples, ", "),           // 4
+		targetTbl.GetID(),                    // 5
+		strings.Join(on, " AND "),            // 6
+		// Sufficient to check the first column to see whether there was no matching row
+		targetCols[0], // 7
+	), originColNames, nil
+}
+
+// deferredUniqueQuery generates and returns a query for any of the given keys
+// that are duplicated in the table, in the same form as duplicateRowQuery.
+func deferredUniqueQuery(
+	srcTbl catalog.TableDescriptor, uc *descpb.UniqueWithoutIndexConstraint, tuples []string,
+) (sql string, colNames []string, _ error) {
+	colNames, err := srcTbl.NamesForColumnIDs(uc.ColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	srcCols := make([]string, len(colNames))
+	for i, n := range colNames {
+		srcCols[i] = tree.NameString(n)
+	}
+
+	pred := ""
+	if uc.Predicate != "" {
+		pred = fmt.Sprintf(" AND (%s)", uc.Predicate)
+	}
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS tbl] WHERE (%[1]s) IN (%[3]s)%[4]s GROUP BY %[1]s HAVING count(*) > 1 LIMIT 1`,
+		strings.Join(srcCols, ", "), // 1
+		srcTbl.GetID(),              // 2
+		strings.Join(tuples, ", "),  // 3
+		pred,                        // 4
+	), colNames, nil
+}
@@ -53,6 +53,24 @@ SELECT table_catalog AS database_name,
        privilege_type,
        is_grantable::boolean
 FROM "".information_schema.table_privileges`
+	// The privileges granted on columns are shown along with the privileges
+	// granted on tables, unless the same privileges are granted on the whole
+	// table.
+	const columnPrivQuery = `
+SELECT table_catalog AS database_name,
+       table_schema AS schema_name,
+       table_name,
+       grantee,
+       privilege_type || ' (' || quote_ident(column_name) || ')' AS privilege_type,
+       is_grantable::boolean
+FROM "".information_schema.column_privileges AS c
+WHERE NOT EXISTS (
+  SELECT 1
+    FROM "".information_schema.table_privileges AS t
+   WHERE (t.table_catalog, t.table_schema, t.table_name, t.grantee) =
+         (c.table_catalog, c.table_schema, c.table_name, c.grantee)
+     AND t.privilege_type IN (c.privilege_type, 'ALL')
+)`
 	const typePrivQuery = `
 SELECT type_catalog AS database_name,
        type_schema AS schema_name,
@@ -183,6 +201,8 @@ FROM "".information_schema.type_privileges`
 
 		if n.Targets != nil {
 			fmt.Fprint(&source, tablePrivQuery)
+			source.WriteString(` UNION ALL `)
+			source.WriteString(columnPrivQuery)
 			// Get grants of table from information_schema.table_privileges
 			// if the type of target is table.
 			var allTables tree.TableNames
@@ -224,6 +244,8 @@ FROM "".information_schema.type_privileges`
 				`SELECT database_name, schema_name, table_name AS relation_name, grantee, privilege_type, is_grantable FROM (`,
 			)
 			source.WriteString(tablePrivQuery)
+			source.WriteString(` UNION ALL `)
+			source.WriteString(columnPrivQuery)
 			source.WriteByte(')')
 			source.WriteString(` UNION ALL ` +
 				`SELECT database_name, schema_name, NULL::STRING AS relation_name, grantee, privilege_type, is_grantable FROM (`)
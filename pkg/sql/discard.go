@@ -40,6 +40,11 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// UNLISTEN *
+		if actions := p.extendedEvalCtx.ListenActions; actions != nil {
+			*actions = append(*actions, listenAction{unlisten: true})
+		}
 	default:
 		return nil, errors.AssertionFailedf("unknown mode for DISCARD: %d", s.Mode)
 	}
@@ -378,6 +378,7 @@ func (dsp *DistSQLPlanner) mustWrapNode(planCtx *PlanningCtx, node planNode) boo
 	case *distinctNode:
 	case *exportNode:
 	case *filterNode:
+	case *foreignScanNode:
 	case *groupNode:
 	case *indexJoinNode:
 	case *invertedFilterNode:
@@ -450,6 +451,14 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		}
 		return checkSupportForPlanNode(n.source.plan)
 
+	case *foreignScanNode:
+		// Scans of foreign tables with several files should be distributed so
+		// that the files are read in parallel.
+		if n.hardLimit == 0 && foreignTableMayHaveSeveralFiles(n.desc.GetForeign()) {
+			return shouldDistribute, nil
+		}
+		return canDistribute, nil
+
 	case *groupNode:
 		rec, err := checkSupportForPlanNode(n.plan)
 		if err != nil {
@@ -1686,11 +1695,14 @@ func (dsp *DistSQLPlanner) addAggregators(
 	aggregations := make([]execinfrapb.AggregatorSpec_Aggregation, len(n.funcs))
 	argumentsColumnTypes := make([][]*types.T, len(n.funcs))
 	for i, fholder := range n.funcs {
-		funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
-		if err != nil {
-			return err
+		aggregations[i].UserDefined = makeUserDefinedAggregation(fholder.userDefined)
+		if aggregations[i].UserDefined == nil {
+			funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
+			if err != nil {
+				return err
+			}
+			aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		}
-		aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		aggregations[i].Distinct = fholder.isDistinct
 		for _, renderIdx := range fholder.argRenderIdxs {
 			aggregations[i].ColIdx = append(aggregations[i].ColIdx, uint32(p.PlanToStreamColMap[renderIdx]))
@@ -1722,6 +1734,21 @@ func (dsp *DistSQLPlanner) addAggregators(
 	})
 }
 
+// makeUserDefinedAggregation returns the specification of the computation of
+// the given user-defined aggregate in a single stage, or nil if the aggregate
+// is a builtin.
+func makeUserDefinedAggregation(
+	agg tree.UserDefinedAggregateOverload,
+) *execinfrapb.AggregatorSpec_UserDefinedAggregation {
+	if agg == nil {
+		return nil
+	}
+	return &execinfrapb.AggregatorSpec_UserDefinedAggregation{
+		Aggregate: *agg.(*descpb.FunctionDescriptor),
+		Stage:     execinfrapb.AggregatorSpec_UserDefinedAggregation_FULL,
+	}
+}
+
 // planAggregators plans the aggregator processors. An evaluator stage is added
 // if necessary.
 // Invariants assumed:
@@ -1832,6 +1859,15 @@ func (dsp *DistSQLPlanner) planAggregators(
 				multiStage = false
 				break
 			}
+			if e.UserDefined != nil {
+				// A user-defined aggregate supports a local stage if its partial
+				// states can be combined.
+				if e.UserDefined.Aggregate.Aggregate.CombineExpr == "" {
+					multiStage = false
+					break
+				}
+				continue
+			}
 			// Check that the function supports a local stage.
 			if _, ok := physicalplan.DistAggregationTable[e.Func]; !ok {
 				multiStage = false
@@ -1868,6 +1904,12 @@ func (dsp *DistSQLPlanner) planAggregators(
 		nFinalAgg := 0
 		needRender := false
 		for _, e := range info.aggregations {
+			if e.UserDefined != nil {
+				// User-defined aggregates have a single local and final stage.
+				nLocalAgg++
+				nFinalAgg++
+				continue
+			}
 			info := physicalplan.DistAggregationTable[e.Func]
 			nLocalAgg += len(info.LocalStage)
 			nFinalAgg += len(info.FinalStage)
@@ -1903,6 +1945,36 @@ func (dsp *DistSQLPlanner) planAggregators(
 		// to all final aggregations.
 		finalIdx := 0
 		for _, e := range info.aggregations {
+			if e.UserDefined != nil {
+				// The local stage computes the partial state of a user-defined
+				// aggregate, and the final stage combines the partial states.
+				// These are never equivalent to other aggregations.
+				def := &e.UserDefined.Aggregate
+				localIdx := uint32(len(localAggs))
+				localAggs = append(localAggs, execinfrapb.AggregatorSpec_Aggregation{
+					ColIdx:       e.ColIdx,
+					FilterColIdx: e.FilterColIdx,
+					Arguments:    e.Arguments,
+					UserDefined: &execinfrapb.AggregatorSpec_UserDefinedAggregation{
+						Aggregate: *def,
+						Stage:     execinfrapb.AggregatorSpec_UserDefinedAggregation_PARTIAL,
+					},
+				})
+				intermediateTypes = append(intermediateTypes, def.Aggregate.StateType)
+				finalIdxMap[finalIdx] = uint32(len(finalAggs))
+				finalAggs = append(finalAggs, execinfrapb.AggregatorSpec_Aggregation{
+					ColIdx: []uint32{localIdx},
+					UserDefined: &execinfrapb.AggregatorSpec_UserDefinedAggregation{
+						Aggregate: *def,
+						Stage:     execinfrapb.AggregatorSpec_UserDefinedAggregation_FINAL,
+					},
+				})
+				if needRender {
+					finalPreRenderTypes = append(finalPreRenderTypes, def.ReturnType)
+				}
+				finalIdx++
+				continue
+			}
 			info := physicalplan.DistAggregationTable[e.Func]
 
 			// relToAbsLocalIdx maps each local stage for the given
@@ -2103,6 +2175,18 @@ func (dsp *DistSQLPlanner) planAggregators(
 			finalIdx := 0
 			for i, e := range info.aggregations {
 				info := physicalplan.DistAggregationTable[e.Func]
+				if e.UserDefined != nil {
+					// User-defined aggregates have a single final stage and
+					// don't need a final rendering.
+					var err error
+					renderExprs[i], err = physicalplan.MakeExpression(
+						h.IndexedVar(int(finalIdxMap[finalIdx])), planCtx, nil /* indexVarMap */)
+					if err != nil {
+						return err
+					}
+					finalIdx++
+					continue
+				}
 				if info.FinalRendering == nil {
 					// mappedIdx corresponds to the index
 					// location of the result for this
@@ -2153,6 +2237,10 @@ func (dsp *DistSQLPlanner) planAggregators(
 
 	finalOutTypes := make([]*types.T, len(info.aggregations))
 	for i, agg := range info.aggregations {
+		if agg.UserDefined != nil {
+			finalOutTypes[i] = agg.UserDefined.Aggregate.ReturnType
+			continue
+		}
 		argTypes := make([]*types.T, len(agg.ColIdx)+len(agg.Arguments))
 		for j, c := range agg.ColIdx {
 			argTypes[j] = inputTypes[c]
@@ -2947,6 +3035,9 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 			return nil, err
 		}
 
+	case *foreignScanNode:
+		plan, err = dsp.createPlanForForeignScan(ctx, planCtx, n)
+
 	case *groupNode:
 		plan, err = dsp.createPhysPlanForPlanNode(ctx, planCtx, n.plan)
 		if err != nil {
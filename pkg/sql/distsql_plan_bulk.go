@@ -38,14 +38,25 @@ func (dsp *DistSQLPlanner) setupAllNodesPlanningSystem(
 ) (*PlanningCtx, []base.SQLInstanceID, error) {
 	planCtx := dsp.NewPlanningCtx(ctx, evalCtx, nil /* planner */, nil, /* txn */
 		DistributionTypeAlways)
+	nodes, err := dsp.allHealthyNodes(ctx, planCtx, execCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return planCtx, nodes, nil
+}
 
+// allHealthyNodes returns all nodes of a system tenant that can be used for
+// planning with the given planCtx.
+func (dsp *DistSQLPlanner) allHealthyNodes(
+	ctx context.Context, planCtx *PlanningCtx, execCfg *ExecutorConfig,
+) ([]base.SQLInstanceID, error) {
 	ss, err := execCfg.NodesStatusServer.OptionalNodesStatusServer(47900)
 	if err != nil {
-		return planCtx, []base.SQLInstanceID{dsp.gatewaySQLInstanceID}, nil //nolint:returnerrcheck
+		return []base.SQLInstanceID{dsp.gatewaySQLInstanceID}, nil //nolint:returnerrcheck
 	}
 	resp, err := ss.ListNodesInternal(ctx, &serverpb.NodesRequest{})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// Because we're not going through the normal pathways, we have to set up the
 	// planCtx.NodeStatuses map ourselves. CheckInstanceHealthAndVersion() will
@@ -59,7 +70,7 @@ func (dsp *DistSQLPlanner) setupAllNodesPlanningSystem(
 			nodes = append(nodes, nodeID)
 		}
 	}
-	return planCtx, nodes, nil
+	return nodes, nil
 }
 
 // setupAllNodesPlanningTenant creates a planCtx and returns all nodes available
@@ -67,20 +78,29 @@ func (dsp *DistSQLPlanner) setupAllNodesPlanningSystem(
 func (dsp *DistSQLPlanner) setupAllNodesPlanningTenant(
 	ctx context.Context, evalCtx *extendedEvalContext, execCfg *ExecutorConfig,
 ) (*PlanningCtx, []base.SQLInstanceID, error) {
-	if dsp.sqlInstanceProvider == nil {
-		return nil, nil, errors.New("sql instance provider not available in multi-tenant environment")
-	}
 	planCtx := dsp.NewPlanningCtx(ctx, evalCtx, nil /* planner */, nil, /* txn */
 		DistributionTypeAlways)
-	pods, err := dsp.sqlInstanceProvider.GetAllInstances(ctx)
+	sqlInstanceIDs, err := dsp.allInstances(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
+	return planCtx, sqlInstanceIDs, nil
+}
+
+// allInstances returns all SQL instances of a non-system tenant.
+func (dsp *DistSQLPlanner) allInstances(ctx context.Context) ([]base.SQLInstanceID, error) {
+	if dsp.sqlInstanceProvider == nil {
+		return nil, errors.New("sql instance provider not available in multi-tenant environment")
+	}
+	pods, err := dsp.sqlInstanceProvider.GetAllInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
 	sqlInstanceIDs := make([]base.SQLInstanceID, len(pods))
 	for i, pod := range pods {
 		sqlInstanceIDs[i] = pod.InstanceID
 	}
-	return planCtx, sqlInstanceIDs, nil
+	return sqlInstanceIDs, nil
 }
 
 // PhysicalPlanMaker describes a function that makes a physical plan.
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execopnode"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
@@ -1487,8 +1488,28 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	prevSteppingMode := planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
 	defer func() { _ = planner.Txn().ConfigureStepping(ctx, prevSteppingMode) }()
 
+	// Create a separate memory account for the results of any subqueries of the
+	// cascades (see runCascadePlan).
+	subqueryResultMemAcc := planner.EvalContext().Mon.MakeBoundAccount()
+	defer subqueryResultMemAcc.Close(ctx)
+
 	// We treat plan.cascades as a queue.
 	for i := 0; i < len(plan.cascades); i++ {
+		if plan.cascades[i].ForEachRow {
+			// Row-level triggers which fire before the mutation have already been
+			// executed (see PlanAndRunSubqueriesAndBeforeTriggers).
+			end := triggerEnd(plan.cascades, i)
+			if !plan.cascades[i].Before {
+				if !dsp.planAndRunRowTrigger(
+					ctx, planner, evalCtxFactory, plan.cascades[i:end], recv, &subqueryResultMemAcc,
+				) {
+					return false
+				}
+			}
+			i = end - 1
+			continue
+		}
+
 		// The original bufferNode is stored in c.Buffer; we can refer to it
 		// directly.
 		// TODO(radu): this requires keeping all previous plans "alive" until the
@@ -1536,39 +1557,11 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 		}
 		cp := cascadePlan.(*planComponents)
 		plan.cascades[i].plan = cp.main
-		if len(cp.subqueryPlans) > 0 {
-			recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
-			return false
-		}
-
-		// Queue any new cascades.
-		if len(cp.cascades) > 0 {
-			plan.cascades = append(plan.cascades, cp.cascades...)
-		}
-
-		// Collect any new checks.
-		if len(cp.checkPlans) > 0 {
-			plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
-		}
-
-		// In cyclical reference situations, the number of cascading operations can
-		// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
-		// safeguard in case we have a bug that results in an infinite cascade loop.
-		if limit := int(evalCtx.SessionData().OptimizerFKCascadesLimit); len(plan.cascades) > limit {
-			telemetry.Inc(sqltelemetry.CascadesLimitReached)
-			err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
-			recv.SetError(err)
-			return false
-		}
-
-		if err := dsp.planAndRunPostquery(
-			ctx,
-			cp.main,
-			planner,
-			evalCtx,
-			recv,
-		); err != nil {
-			recv.SetError(err)
+		plan.cascades[i].subqueryPlans = cp.subqueryPlans
+		if !dsp.runCascadePlan(
+			ctx, planner, evalCtxFactory, evalCtx, plan, cp, recv, &errOnlyResultWriter{},
+			&subqueryResultMemAcc,
+		) {
 			return false
 		}
 	}
@@ -1596,6 +1589,7 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			planner,
 			evalCtxFactory(),
 			recv,
+			&errOnlyResultWriter{},
 		); err != nil {
 			recv.SetError(err)
 			return false
@@ -1605,13 +1599,543 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	return true
 }
 
-// planAndRunPostquery runs a cascade or check query.
+// runCascadePlan runs the plan for a cascade or for a row of a row-level
+// trigger, queueing any new cascades and checks in the given planComponents.
+// Any rows produced by the main query are passed to the given resultWriter.
+//
+// The plan can have subqueries only if the mutation in it has row-level
+// triggers which fire before the mutation; in that case the subqueries buffer
+// the mutation input, and the triggers are executed before the main query.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) runCascadePlan(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	evalCtx *extendedEvalContext,
+	plan *planComponents,
+	cp *planComponents,
+	recv *DistSQLReceiver,
+	resultWriter rowResultWriter,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	if len(cp.subqueryPlans) > 0 {
+		if !cp.cascades.hasBeforeTriggers() {
+			recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
+			return false
+		}
+		if !dsp.planAndRunSubqueriesAndBeforeTriggers(
+			ctx, planner, evalCtxFactory, plan, cp.subqueryPlans, cp.cascades, recv, subqueryResultMemAcc,
+		) {
+			return false
+		}
+	}
+
+	// Queue any new cascades. Triggers which fire before the mutation have
+	// already been executed.
+	for i := range cp.cascades {
+		if !cp.cascades[i].Before {
+			plan.cascades = append(plan.cascades, cp.cascades[i])
+		}
+	}
+	cp.cascades = nil
+
+	// Collect any new checks.
+	if len(cp.checkPlans) > 0 {
+		plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
+		cp.checkPlans = nil
+	}
+
+	// In cyclical reference situations, the number of cascading operations can
+	// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
+	// safeguard in case we have a bug that results in an infinite cascade loop.
+	if limit := int(evalCtx.SessionData().OptimizerFKCascadesLimit); len(plan.cascades) > limit {
+		telemetry.Inc(sqltelemetry.CascadesLimitReached)
+		err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
+		recv.SetError(err)
+		return false
+	}
+
+	if err := dsp.planAndRunPostquery(
+		ctx,
+		cp.main,
+		planner,
+		evalCtx,
+		recv,
+		resultWriter,
+	); err != nil {
+		recv.SetError(err)
+		return false
+	}
+	return true
+}
+
+// PlanAndRunSubqueriesAndBeforeTriggers runs the subqueries of the main query,
+// along with its row-level triggers which fire before the mutation modifies
+// any rows. These triggers can only run once the mutation input has been
+// buffered by a subquery.
+//
+// Any cascades and checks generated by the triggers are queued in the plan,
+// to be executed by PlanAndRunCascadesAndChecks.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) PlanAndRunSubqueriesAndBeforeTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	recv *DistSQLReceiver,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	if !plan.cascades.hasBeforeTriggers() {
+		return dsp.PlanAndRunSubqueries(
+			ctx, planner, evalCtxFactory, plan.subqueryPlans, recv, subqueryResultMemAcc,
+		)
+	}
+
+	prevSteppingMode := planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+	defer func() { _ = planner.Txn().ConfigureStepping(ctx, prevSteppingMode) }()
+
+	// Only the cascades of the main query are considered here; any cascades
+	// queued while running the triggers are executed after the main query.
+	return dsp.planAndRunSubqueriesAndBeforeTriggers(
+		ctx, planner, evalCtxFactory, plan, plan.subqueryPlans, plan.cascades, recv,
+		subqueryResultMemAcc,
+	)
+}
+
+// planAndRunSubqueriesAndBeforeTriggers runs the given subqueries, and the
+// row-level triggers among the given cascades which fire before the mutation
+// modifies any rows. Any new cascades and checks are queued in the given
+// planComponents.
+//
+// The triggers which modify the rows of a mutation are run as soon as the
+// subquery which buffers their input has been executed, since the subqueries
+// which follow it (e.g. the buffered input of the mutation itself) read the
+// modified rows. The other triggers are run once all the subqueries have been
+// executed.
+func (dsp *DistSQLPlanner) planAndRunSubqueriesAndBeforeTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	subqueryPlans []subquery,
+	cascades cascadeList,
+	recv *DistSQLReceiver,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	start := 0
+	for i := 0; i < len(cascades); i++ {
+		if !cascades[i].ModifiesRows {
+			continue
+		}
+		// All the triggers which modify the rows of the same mutation are run
+		// together, since the row modified by a trigger is passed to the next one.
+		end := i + 1
+		for end < len(cascades) && cascades[end].ModifiesRows &&
+			cascades[end].Buffer == cascades[i].Buffer {
+			end++
+		}
+		bufferIdx := -1
+		for j := start; j < len(subqueryPlans); j++ {
+			if subqueryPlans[j].plan.planNode == cascades[i].Buffer {
+				bufferIdx = j
+				break
+			}
+		}
+		if bufferIdx == -1 {
+			recv.SetError(errors.AssertionFailedf(
+				"cannot find the input of trigger %q among the subqueries", cascades[i].FKName,
+			))
+			return false
+		}
+		if !dsp.PlanAndRunSubqueries(
+			ctx, planner, evalCtxFactory, subqueryPlans[start:bufferIdx+1], recv, subqueryResultMemAcc,
+		) {
+			return false
+		}
+		start = bufferIdx + 1
+		if !dsp.planAndRunRowModifyingTriggers(
+			ctx, planner, evalCtxFactory, cascades[i:end], recv, subqueryResultMemAcc,
+		) {
+			return false
+		}
+		i = end - 1
+	}
+	if !dsp.PlanAndRunSubqueries(
+		ctx, planner, evalCtxFactory, subqueryPlans[start:], recv, subqueryResultMemAcc,
+	) {
+		return false
+	}
+
+	for i := 0; i < len(cascades); i++ {
+		if !cascades[i].ForEachRow {
+			continue
+		}
+		end := triggerEnd(cascades, i)
+		if cascades[i].Before && !cascades[i].ModifiesRows {
+			if !dsp.planAndRunRowTrigger(
+				ctx, planner, evalCtxFactory, cascades[i:end], recv, subqueryResultMemAcc,
+			) {
+				return false
+			}
+		}
+		i = end - 1
+	}
+	return true
+}
+
+// triggerEnd returns the index following the last cascade which belongs to the
+// same row-level trigger as cascades[start]. Each statement in the body of a
+// trigger is planned as a separate cascade; these cascades are adjacent.
+func triggerEnd(cascades cascadeList, start int) int {
+	end := start + 1
+	for end < len(cascades) && cascades[end].ForEachRow &&
+		cascades[end].FKName == cascades[start].FKName &&
+		cascades[end].Buffer == cascades[start].Buffer {
+		end++
+	}
+	return end
+}
+
+// maxTriggerDepth is the maximum nesting depth of row-level triggers, which
+// can fire each other recursively.
+const maxTriggerDepth = 64
+
+type triggerDepthKey struct{}
+
+// planAndRunRowTrigger runs a row-level trigger. The given cascades correspond
+// to the statements of the trigger body; all the statements are planned and
+// executed for a row of the buffered mutation input before moving on to the
+// next row.
+//
+// Each statement is built once, and the plan for each row is closed as soon as
+// it has run. For this reason, any cascades and checks of the statement are
+// also executed right away, instead of being queued. A trigger which executes a
+// trigger function is a single cascade; see runTriggerFunction.
+func (dsp *DistSQLPlanner) planAndRunRowTrigger(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	stmts cascadeList,
+	recv *DistSQLReceiver,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	buf := stmts[0].Buffer.(*bufferNode)
+	if buf.rows.Len() == 0 {
+		// No rows were actually modified.
+		return true
+	}
+	ctx, planRowFns, ok := dsp.prepareRowTrigger(ctx, planner, evalCtxFactory, stmts, recv)
+	if !ok {
+		return false
+	}
+
+	if stmts[0].TriggerFunction != nil {
+		if err := runTriggerFunction(ctx, planner, evalCtxFactory, &stmts[0].Cascade, buf); err != nil {
+			recv.SetError(err)
+			return false
+		}
+		return true
+	}
+
+	it := newRowContainerIterator(ctx, buf.rows, buf.typs)
+	defer it.Close()
+	for {
+		row, err := it.Next()
+		if err != nil {
+			recv.SetError(err)
+			return false
+		}
+		if row == nil {
+			return true
+		}
+		// The iterator reuses the row.
+		row = append(tree.Datums(nil), row...)
+
+		for i := range planRowFns {
+			if !dsp.runTriggerRow(
+				ctx, planner, evalCtxFactory, planRowFns[i], row, recv, &errOnlyResultWriter{},
+				subqueryResultMemAcc,
+			) {
+				return false
+			}
+		}
+	}
+}
+
+// prepareRowTrigger prepares the statements of row-level triggers for
+// execution, returning the function which plans each statement for a row and
+// a context which tracks the nesting depth of the triggers.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) prepareRowTrigger(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	stmts cascadeList,
+	recv *DistSQLReceiver,
+) (context.Context, []exec.PlanRowFn, bool) {
+	log.VEventf(ctx, 2, "executing trigger %s", stmts[0].FKName)
+
+	depth, _ := ctx.Value(triggerDepthKey{}).(int)
+	if depth >= maxTriggerDepth {
+		recv.SetError(pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"stack depth limit exceeded while executing trigger %q", stmts[0].FKName))
+		return ctx, nil, false
+	}
+	ctx = context.WithValue(ctx, triggerDepthKey{}, depth+1)
+
+	if stmts[0].TriggerFunction != nil {
+		// The trigger function is executed without being planned.
+		return ctx, nil, true
+	}
+	planRowFns := make([]exec.PlanRowFn, len(stmts))
+	for i := range stmts {
+		evalCtx := evalCtxFactory()
+		planRowFn, err := stmts[i].PrepareRowFn(ctx, &planner.semaCtx, &evalCtx.Context)
+		if err != nil {
+			recv.SetError(err)
+			return ctx, nil, false
+		}
+		planRowFns[i] = planRowFn
+	}
+	return ctx, planRowFns, true
+}
+
+// planAndRunRowModifyingTriggers runs the BEFORE row-level triggers of a
+// mutation which can modify or skip the rows of the mutation. The given
+// cascades correspond to the statements of the triggers, in the order in which
+// the triggers fire; all the triggers fire for a row of the buffered mutation
+// input before moving on to the next row.
+//
+// The last statement of a trigger can return the new row, in which case the
+// row is modified accordingly before it is passed to the next trigger, or no
+// row, in which case the row is skipped by the mutation and by the remaining
+// triggers. Once all the rows have been processed, the buffered input is
+// replaced with the rows which were not skipped.
+func (dsp *DistSQLPlanner) planAndRunRowModifyingTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	stmts cascadeList,
+	recv *DistSQLReceiver,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	buf := stmts[0].Buffer.(*bufferNode)
+	if buf.rows.Len() == 0 {
+		return true
+	}
+	ctx, planRowFns, ok := dsp.prepareRowTrigger(ctx, planner, evalCtxFactory, stmts, recv)
+	if !ok {
+		return false
+	}
+
+	var rows rowContainerHelper
+	rows.Init(buf.typs, planner.ExtendedEvalContext(), "before triggers")
+	defer func() { rows.Close(ctx) }()
+
+	if !dsp.runRowModifyingTriggers(
+		ctx, planner, evalCtxFactory, stmts, planRowFns, buf, &rows, recv, subqueryResultMemAcc,
+	) {
+		return false
+	}
+	// The mutation reads the rows which were not skipped; the original rows are
+	// closed by the deferred function.
+	buf.rows, rows = rows, buf.rows
+	return true
+}
+
+// runRowModifyingTriggers runs the given trigger statements for each row of
+// the buffer, adding the rows which are not skipped to the given container.
+func (dsp *DistSQLPlanner) runRowModifyingTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	stmts cascadeList,
+	planRowFns []exec.PlanRowFn,
+	buf *bufferNode,
+	rows *rowContainerHelper,
+	recv *DistSQLReceiver,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	it := newRowContainerIterator(ctx, buf.rows, buf.typs)
+	defer it.Close()
+	for {
+		row, err := it.Next()
+		if err != nil {
+			recv.SetError(err)
+			return false
+		}
+		if row == nil {
+			return true
+		}
+		// The iterator reuses the row.
+		row = append(tree.Datums(nil), row...)
+
+		skip := false
+		for i := 0; i < len(stmts) && !skip; i++ {
+			if !stmts[i].ReturnsRow {
+				if !dsp.runTriggerRow(
+					ctx, planner, evalCtxFactory, planRowFns[i], row, recv, &errOnlyResultWriter{},
+					subqueryResultMemAcc,
+				) {
+					return false
+				}
+				continue
+			}
+			// Errors are not returned from the callback, since they would be
+			// treated as communication errors.
+			var newRow tree.Datums
+			var numRows int
+			resultWriter := NewCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+				numRows++
+				if numRows == 1 {
+					newRow = append(tree.Datums(nil), row...)
+				}
+				return nil
+			})
+			if !dsp.runTriggerRow(
+				ctx, planner, evalCtxFactory, planRowFns[i], row, recv, resultWriter,
+				subqueryResultMemAcc,
+			) {
+				return false
+			}
+			switch {
+			case numRows == 0:
+				// The trigger skipped the row, so the remaining triggers don't fire
+				// for it.
+				skip = true
+			case numRows > 1:
+				recv.SetError(pgerror.Newf(pgcode.CardinalityViolation,
+					"trigger %q returned more than one row", stmts[i].FKName))
+				return false
+			default:
+				for j, ord := range stmts[i].ReturnOrdinals {
+					row[ord] = newRow[j]
+				}
+			}
+		}
+		if skip {
+			continue
+		}
+		if err := rows.AddRow(ctx, row); err != nil {
+			recv.SetError(err)
+			return false
+		}
+	}
+}
+
+// runTriggerFunction runs a row-level trigger which executes a trigger
+// function, by calling the function for each row of the buffered mutation
+// input.
+func runTriggerFunction(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	trigger *exec.Cascade,
+	buf *bufferNode,
+) error {
+	f, data, err := planner.makeTriggerCall(ctx, trigger)
+	if err != nil {
+		return err
+	}
+	project := func(row tree.Datums, ords []int) tree.Datums {
+		if len(ords) == 0 {
+			return nil
+		}
+		res := make(tree.Datums, len(ords))
+		for i, ord := range ords {
+			res[i] = row[ord]
+		}
+		return res
+	}
+
+	it := newRowContainerIterator(ctx, buf.rows, buf.typs)
+	defer it.Close()
+	for {
+		row, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			return nil
+		}
+		data.Old = project(row, trigger.OldOrdinals)
+		data.New = project(row, trigger.NewOrdinals)
+
+		// The function must observe the writes of the mutation and of the
+		// previous calls.
+		_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+		if err := planner.Txn().Step(ctx); err != nil {
+			return err
+		}
+		evalCtx := evalCtxFactory()
+		// The statements of the function can fire triggers in turn, which must
+		// observe the nesting depth.
+		evalCtx.Context.Context = ctx
+		if err := f.CallTrigger(&evalCtx.Context, data); err != nil {
+			return err
+		}
+	}
+}
+
+// runTriggerRow plans and runs a statement of a row-level trigger for a single
+// row, along with any cascades and checks it generates. Any rows produced by
+// the statement are passed to the given resultWriter.
+func (dsp *DistSQLPlanner) runTriggerRow(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	planRowFn exec.PlanRowFn,
+	row tree.Datums,
+	recv *DistSQLReceiver,
+	resultWriter rowResultWriter,
+	subqueryResultMemAcc *mon.BoundAccount,
+) bool {
+	// We place a sequence point before every statement, so that it can observe
+	// the writes of the previous statements.
+	_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+	if err := planner.Txn().Step(ctx); err != nil {
+		recv.SetError(err)
+		return false
+	}
+
+	evalCtx := evalCtxFactory()
+	rowPlan, err := planRowFn(ctx, &evalCtx.Context, newExecFactory(planner), row)
+	if err != nil {
+		recv.SetError(err)
+		return false
+	}
+	cp := rowPlan.(*planComponents)
+	defer cp.close(ctx)
+
+	var nested planComponents
+	defer nested.close(ctx)
+	if !dsp.runCascadePlan(
+		ctx, planner, evalCtxFactory, evalCtx, &nested, cp, recv, resultWriter,
+		subqueryResultMemAcc,
+	) {
+		return false
+	}
+	if len(nested.cascades) == 0 && len(nested.checkPlans) == 0 {
+		return true
+	}
+	return dsp.PlanAndRunCascadesAndChecks(ctx, planner, evalCtxFactory, &nested, recv)
+}
+
+// planAndRunPostquery runs a cascade or check query. Any rows produced by the
+// query are passed to the given resultWriter.
 func (dsp *DistSQLPlanner) planAndRunPostquery(
 	ctx context.Context,
 	postqueryPlan planMaybePhysical,
 	planner *planner,
 	evalCtx *extendedEvalContext,
 	recv *DistSQLReceiver,
+	resultWriter rowResultWriter,
 ) error {
 	postqueryMonitor := mon.NewMonitor(
 		"postquery",
@@ -1654,11 +2178,10 @@ func (dsp *DistSQLPlanner) planAndRunPostquery(
 
 	postqueryRecv := recv.clone()
 	defer postqueryRecv.Release()
-	// TODO(yuzefovich): at the moment, errOnlyResultWriter is sufficient here,
-	// but it may not be the case when we support cascades through the optimizer.
-	postqueryResultWriter := &errOnlyResultWriter{}
-	postqueryRecv.resultWriter = postqueryResultWriter
-	postqueryRecv.batchWriter = postqueryResultWriter
+	postqueryRecv.resultWriter = resultWriter
+	if batchWriter, ok := resultWriter.(batchResultWriter); ok {
+		postqueryRecv.batchWriter = batchWriter
+	}
 	dsp.Run(ctx, postqueryPlanCtx, planner.txn, postqueryPhysPlan, postqueryRecv, evalCtx, nil /* finishedSetupFn */)()
 	return postqueryRecv.resultWriter.Err()
 }
@@ -177,6 +177,9 @@ func (e *distSQLSpecExecFactory) ConstructScan(
 			},
 		)
 	}
+	if table.IsForeignTable() {
+		return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: foreign scan")
+	}
 
 	// Although we don't yet recommend distributing plans where soft limits
 	// propagate to scan nodes because we don't have infrastructure to only
@@ -449,6 +452,7 @@ func (e *distSQLSpecExecFactory) ConstructMergeJoin(
 func populateAggFuncSpec(
 	spec *execinfrapb.AggregatorSpec_Aggregation,
 	funcName string,
+	userDefined tree.UserDefinedAggregateOverload,
 	distinct bool,
 	argCols []exec.NodeColumnOrdinal,
 	constArgs []tree.Datum,
@@ -456,11 +460,14 @@ func populateAggFuncSpec(
 	planCtx *PlanningCtx,
 	physPlan *PhysicalPlan,
 ) (argumentsColumnTypes []*types.T, err error) {
-	funcIdx, err := execinfrapb.GetAggregateFuncIdx(funcName)
-	if err != nil {
-		return nil, err
+	spec.UserDefined = makeUserDefinedAggregation(userDefined)
+	if spec.UserDefined == nil {
+		funcIdx, err := execinfrapb.GetAggregateFuncIdx(funcName)
+		if err != nil {
+			return nil, err
+		}
+		spec.Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 	}
-	spec.Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 	spec.Distinct = distinct
 	spec.ColIdx = make([]uint32, len(argCols))
 	for i, col := range argCols {
@@ -506,7 +513,7 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 			spec := &aggregationSpecs[i]
 			argColsScratch[0] = col
 			_, err = populateAggFuncSpec(
-				spec, builtins.AnyNotNull, false /* distinct*/, argColsScratch,
+				spec, builtins.AnyNotNull, nil /* userDefined */, false /* distinct*/, argColsScratch,
 				nil /* constArgs */, noFilter, planCtx, physPlan,
 			)
 			if err != nil {
@@ -518,8 +525,13 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 		i := len(groupCols) + j
 		spec := &aggregationSpecs[i]
 		agg := &aggregations[j]
+		if err := e.planner.checkAggregatePrivilege(
+			e.planner.EvalContext().Context, agg.UserDefined,
+		); err != nil {
+			return nil, err
+		}
 		argumentsColumnTypes[i], err = populateAggFuncSpec(
-			spec, agg.FuncName, agg.Distinct, agg.ArgCols,
+			spec, agg.FuncName, agg.UserDefined, agg.Distinct, agg.ArgCols,
 			agg.ConstArgs, agg.Filter, planCtx, physPlan,
 		)
 		if err != nil {
@@ -993,6 +1005,7 @@ func (e *distSQLSpecExecFactory) ConstructDelete(
 	table cat.Table,
 	fetchCols exec.TableColumnOrdinalSet,
 	returnCols exec.TableColumnOrdinalSet,
+	passthrough colinfo.ResultColumns,
 	autoCommit bool,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: delete")
@@ -1031,6 +1044,7 @@ func (e *distSQLSpecExecFactory) ConstructCreateView(
 	deps opt.ViewDeps,
 	typeDeps opt.ViewTypeDeps,
 	withData bool,
+	incrementalRefresh bool,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: create view")
 }
@@ -1046,7 +1060,7 @@ func (e *distSQLSpecExecFactory) ConstructSaveTable(
 }
 
 func (e *distSQLSpecExecFactory) ConstructErrorIfRows(
-	input exec.Node, mkErr exec.MkErrFn,
+	input exec.Node, mkErr exec.MkErrFn, deferrable *exec.DeferrableCheck,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: error if rows")
 }
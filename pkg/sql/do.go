@@ -0,0 +1,53 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/plpgsql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// inlineCodeBlockName is the name of the function executing the code of a DO
+// statement in the context of errors, as in postgres.
+const inlineCodeBlockName = "inline_code_block"
+
+type doBlockNode struct {
+	fn plpgsql.Function
+}
+
+// DoBlock executes an anonymous PL/pgSQL code block. The code is parsed when
+// the statement is planned and executed in the transaction of the statement.
+// Privileges: None.
+//   notes: postgres requires USAGE on the language of the code.
+func (p *planner) DoBlock(ctx context.Context, n *tree.DoBlock) (planNode, error) {
+	if n.Language == tree.FunctionLangSQL {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			`language "sql" does not support inline code execution`)
+	}
+	body, err := plpgsql.Parse(inlineCodeBlockName, n.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &doBlockNode{fn: plpgsql.Function{Name: inlineCodeBlockName, Body: body}}, nil
+}
+
+func (n *doBlockNode) startExec(params runParams) error {
+	_, err := n.fn.Call(params.EvalContext(), nil /* args */)
+	return err
+}
+
+func (n *doBlockNode) Next(runParams) (bool, error) { return false, nil }
+func (n *doBlockNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *doBlockNode) Close(context.Context)        {}
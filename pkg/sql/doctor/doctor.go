@@ -356,6 +356,9 @@ func descriptorModifiedForInsert(r DescriptorTableRow) ([]byte, error) {
 		d.TableDesc().ModificationTime = hlc.Timestamp{}
 		d.TableDesc().CreateAsOfTime = hlc.Timestamp{}
 		d.TableDesc().Version = 1
+	case catalog.FunctionDescriptor:
+		d.FuncDesc().ModificationTime = hlc.Timestamp{}
+		d.FuncDesc().Version = 1
 	default:
 		return nil, nil
 	}
@@ -0,0 +1,89 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type dropAggregateNode struct {
+	n    *tree.DropAggregate
+	aggs []*funcdesc.Mutable
+}
+
+// DropAggregate drops user-defined aggregates. Nothing can depend on an
+// aggregate, since aggregates cannot be used in views, so CASCADE and
+// RESTRICT behave in the same way.
+// Privileges: ownership of the aggregate.
+func (p *planner) DropAggregate(ctx context.Context, n *tree.DropAggregate) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropAggregateNode{n: n}
+	for i := range n.Aggregates {
+		sig := &n.Aggregates[i]
+		argTypes, err := p.resolveAggregateTypes(ctx, sig)
+		if err != nil {
+			return nil, err
+		}
+		db, err := p.getFunctionDatabase(ctx, "aggregate", &sig.Name)
+		if err != nil {
+			return nil, err
+		}
+		agg, err := p.findAggregate(ctx, db, &sig.Name, argTypes)
+		if err != nil {
+			return nil, err
+		}
+		if agg == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, errAggregateDoesNotExist(sig.Name.Object(), argTypes)
+		}
+		if err := p.checkFunctionOwnership(ctx, agg); err != nil {
+			return nil, err
+		}
+		mutDesc, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, agg.GetID(), tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		node.aggs = append(node.aggs, mutDesc)
+	}
+	return node, nil
+}
+
+func (n *dropAggregateNode) startExec(params runParams) error {
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	for _, agg := range n.aggs {
+		if agg.Dropped() {
+			// The aggregate was listed more than once.
+			continue
+		}
+		if err := params.p.dropFunctionDesc(params.ctx, agg, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dropAggregateNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropAggregateNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropAggregateNode) Close(context.Context)        {}
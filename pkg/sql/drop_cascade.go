@@ -12,11 +12,13 @@ package sql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -36,6 +38,7 @@ type dropCascadeState struct {
 	toDeleteByID            map[descpb.ID]*toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []*funcdesc.Mutable
 
 	droppedNames []string
 }
@@ -66,10 +69,32 @@ func (d *dropCascadeState) collectObjectsInSchema(
 	for i := range names {
 		d.objectNamesToDelete = append(d.objectNamesToDelete, &names[i])
 	}
+	// Functions have no namespace entries, so they are collected through the
+	// functions mapping of the schema.
+	if err := schema.ForEachFunctionOverload(func(
+		_ string, overload descpb.SchemaDescriptor_Function_Overload,
+	) error {
+		fn, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, overload.ID, tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		d.functionsToDelete = append(d.functionsToDelete, fn)
+		return nil
+	}); err != nil {
+		return err
+	}
 	d.schemasToDelete = append(d.schemasToDelete, schemaWithDbDesc{schema: schema, dbDesc: db})
 	return nil
 }
 
+// objectCount returns the number of objects collected in the schemas to
+// delete, which must be zero unless the drop behavior is CASCADE.
+func (d *dropCascadeState) objectCount() int {
+	return len(d.objectNamesToDelete) + len(d.functionsToDelete)
+}
+
 // This resolves objects for DROP SCHEMA and DROP DATABASE ops.
 // db is used to generate a useful error message in the case
 // of DROP DATABASE; otherwise, db is nil.
@@ -226,6 +251,21 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		}
 	}
 
+	// Finally delete all of the functions, and the triggers of the remaining
+	// tables which execute them.
+	for _, fn := range d.functionsToDelete {
+		if err := p.dropTriggersUsingFunction(ctx, fn, tree.DropCascade); err != nil {
+			return err
+		}
+		fn.SetDropped()
+		if err := p.writeFunctionDescChange(
+			ctx, fn, fmt.Sprintf("dropping function %s(%d)", fn.GetName(), fn.GetID()),
+		); err != nil {
+			return err
+		}
+		d.droppedNames = append(d.droppedNames, fn.GetName())
+	}
+
 	return nil
 }
 
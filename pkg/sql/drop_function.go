@@ -0,0 +1,204 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n   *tree.DropFunction
+	fns []*funcdesc.Mutable
+}
+
+// DropFunction drops user-defined functions or procedures.
+// Functions cannot be used in views, so the only dependencies on functions are
+// the triggers which execute trigger functions. These triggers are dropped with
+// CASCADE, and prevent the function from being dropped otherwise.
+// Privileges: ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		n.StatementTag(),
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropFunctionNode{n: n}
+	for i := range n.Functions {
+		sig := &n.Functions[i]
+		argTypes := make([]*types.T, len(sig.ArgTypes))
+		for j, ref := range sig.ArgTypes {
+			var err error
+			if argTypes[j], err = p.resolveFunctionType(ctx, ref); err != nil {
+				return nil, err
+			}
+		}
+		db, err := p.getFunctionDatabase(ctx, functionKind(n.IsProcedure), &sig.Name)
+		if err != nil {
+			return nil, err
+		}
+		fn, err := p.findFunction(ctx, db, &sig.Name, argTypes)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, errFunctionDoesNotExist(n.IsProcedure, sig.Name.Object(), argTypes)
+		}
+		if fn.GetIsProcedure() != n.IsProcedure {
+			return nil, errWrongFunctionKind(n.IsProcedure, sig.Name.Object(), argTypes)
+		}
+		if fn.GetAggregate() != nil {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%s is an aggregate function",
+					aggregateSignatureString(sig.Name.Object(), argTypes)),
+				"Use DROP AGGREGATE to drop aggregate functions.")
+		}
+		if err := p.checkFunctionOwnership(ctx, fn); err != nil {
+			return nil, err
+		}
+		mutDesc, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, fn.GetID(), tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		node.fns = append(node.fns, mutDesc)
+	}
+	return node, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	for _, fn := range n.fns {
+		if fn.Dropped() {
+			// The function was listed more than once.
+			continue
+		}
+		if err := params.p.dropTriggersUsingFunction(params.ctx, fn, n.n.DropBehavior); err != nil {
+			return err
+		}
+		if err := params.p.dropFunctionDesc(params.ctx, fn, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropFunctionDesc removes the given user-defined function, procedure or
+// aggregate from the functions of its schema, and marks its descriptor as
+// dropped.
+func (p *planner) dropFunctionDesc(ctx context.Context, fn *funcdesc.Mutable, jobDesc string) error {
+	mutSchema, err := p.Descriptors().GetMutableDescriptorByID(ctx, p.txn, fn.GetParentSchemaID())
+	if err != nil {
+		return err
+	}
+	scDesc, ok := mutSchema.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("schema %d of function %q has no descriptor",
+			fn.GetParentSchemaID(), fn.GetName())
+	}
+	scDesc.RemoveFunction(fn.GetName(), fn.GetID())
+	if err := p.writeSchemaDescChange(ctx, scDesc, jobDesc); err != nil {
+		return err
+	}
+	fn.SetDropped()
+	return p.writeFunctionDescChange(ctx, fn, jobDesc)
+}
+
+// dropTriggersUsingFunction drops the triggers which execute the given trigger
+// function if the behavior is CASCADE, and returns an error if there are any
+// such triggers otherwise. Triggers can only execute the functions of the
+// database of their table.
+func (p *planner) dropTriggersUsingFunction(
+	ctx context.Context, fn *funcdesc.Mutable, behavior tree.DropBehavior,
+) error {
+	if !fn.ReturnsTrigger {
+		return nil
+	}
+	tables, err := p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.txn, fn.GetParentID())
+	if err != nil {
+		return err
+	}
+	usesFunction := func(trig *descpb.TableDescriptor_Trigger) bool {
+		return trig.FunctionID == fn.GetID()
+	}
+	for _, table := range tables {
+		if table.Dropped() {
+			continue
+		}
+		triggers := table.GetTriggers()
+		found := false
+		for i := range triggers {
+			if !usesFunction(&triggers[i]) {
+				continue
+			}
+			if behavior != tree.DropCascade {
+				tableName, err := p.getQualifiedTableName(ctx, table)
+				if err != nil {
+					return err
+				}
+				return errors.WithHintf(
+					sqlerrors.NewDependentObjectErrorf(
+						"cannot drop function %s because trigger %q on table %q depends on it",
+						fn.GetName(), triggers[i].Name, tableName.FQString()),
+					"you can drop the trigger instead.")
+			}
+			found = true
+		}
+		if !found {
+			continue
+		}
+
+		tableDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, table.GetID(), p.txn)
+		if err != nil {
+			return err
+		}
+		if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+			return err
+		}
+		kept := tableDesc.Triggers[:0]
+		for i := range tableDesc.Triggers {
+			if !usesFunction(&tableDesc.Triggers[i]) {
+				kept = append(kept, tableDesc.Triggers[i])
+			}
+		}
+		tableDesc.Triggers = kept
+		if err := p.writeSchemaChange(
+			ctx, tableDesc, descpb.InvalidMutationID,
+			fmt.Sprintf("dropping triggers of table %s(%d) which execute function %s",
+				tableDesc.Name, tableDesc.ID, fn.GetName()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropFunctionNode) Close(context.Context)        {}
@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type dropPolicyNode struct {
+	n         *tree.DropPolicy
+	tableDesc *tabledesc.Mutable
+}
+
+// DropPolicy drops a row-level security policy from a table. Nothing can
+// depend on a policy, so CASCADE and RESTRICT behave in the same way.
+// Privileges: ownership of the table.
+//   notes: postgres requires ownership of the table.
+func (p *planner) DropPolicy(ctx context.Context, n *tree.DropPolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP POLICY",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and table did not exist -- noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	if err := p.checkCanManagePolicies(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+
+	return &dropPolicyNode{n: n, tableDesc: tableDesc}, nil
+}
+
+func (n *dropPolicyNode) startExec(params runParams) error {
+	tableDesc := n.tableDesc
+	name := string(n.n.Name)
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].Name != name {
+			continue
+		}
+		tableDesc.Policies = append(tableDesc.Policies[:i], tableDesc.Policies[i+1:]...)
+		if err := validateDescriptor(params.ctx, params.p, tableDesc); err != nil {
+			return err
+		}
+		return params.p.writeSchemaChange(
+			params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+		)
+	}
+	if n.n.IfExists {
+		return nil
+	}
+	return pgerror.Newf(pgcode.UndefinedObject,
+		"policy %q for table %q does not exist", name, tableDesc.Name)
+}
+
+func (n *dropPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropPolicyNode) Close(context.Context)        {}
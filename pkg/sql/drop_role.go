@@ -80,6 +80,7 @@ const (
 	table            objectType = "table"
 	schema           objectType = "schema"
 	typeObject       objectType = "type"
+	policy           objectType = "policy"
 	defaultPrivilege objectType = "default_privilege"
 )
 
@@ -184,18 +185,48 @@ func (n *DropRoleNode) startExec(params runParams) error {
 					ObjectName: tn.String(),
 				})
 		}
+		hasPrivileges := false
 		for _, u := range tableDescriptor.GetPrivileges().Users {
 			if _, ok := userNames[u.User()]; ok {
-				if privilegeObjectFormatter.Len() > 0 {
-					privilegeObjectFormatter.WriteString(", ")
-				}
-				parentName := lCtx.getDatabaseName(tableDescriptor)
-				schemaName := lCtx.getSchemaName(tableDescriptor)
-				tn := tree.MakeTableNameWithSchema(tree.Name(parentName), tree.Name(schemaName), tree.Name(tableDescriptor.GetName()))
-				privilegeObjectFormatter.FormatNode(&tn)
+				hasPrivileges = true
 				break
 			}
 		}
+		// The privileges granted on the columns of the table are reported as
+		// privileges on the table.
+		for _, cp := range tableDescriptor.GetColumnPrivileges() {
+			for _, u := range cp.Users {
+				if _, ok := userNames[u.User()]; ok {
+					hasPrivileges = true
+					break
+				}
+			}
+		}
+		if hasPrivileges {
+			if privilegeObjectFormatter.Len() > 0 {
+				privilegeObjectFormatter.WriteString(", ")
+			}
+			parentName := lCtx.getDatabaseName(tableDescriptor)
+			schemaName := lCtx.getSchemaName(tableDescriptor)
+			tn := tree.MakeTableNameWithSchema(tree.Name(parentName), tree.Name(schemaName), tree.Name(tableDescriptor.GetName()))
+			privilegeObjectFormatter.FormatNode(&tn)
+		}
+		for _, pol := range tableDescriptor.GetPolicies() {
+			for _, roleName := range pol.RoleNames {
+				role := username.MakeSQLUsernameFromPreNormalizedString(roleName)
+				if _, ok := userNames[role]; !ok {
+					continue
+				}
+				tn, err := getTableNameFromTableDescriptor(lCtx, tableDescriptor, "")
+				if err != nil {
+					return err
+				}
+				userNames[role] = append(userNames[role], objectAndType{
+					ObjectType: policy,
+					ObjectName: fmt.Sprintf("%s on table %s", tree.Name(pol.Name), tn.String()),
+				})
+			}
+		}
 	}
 	for _, schemaDesc := range lCtx.schemaDescs {
 		if !descriptorIsVisible(schemaDesc, true /* allowAdding */) {
@@ -281,6 +312,8 @@ func (n *DropRoleNode) startExec(params runParams) error {
 				switch obj.ObjectType {
 				case database, table, schema, typeObject:
 					objectsMsg.WriteString(fmt.Sprintf("\nowner of %s %s", obj.ObjectType, obj.ObjectName))
+				case policy:
+					objectsMsg.WriteString(fmt.Sprintf("\ntarget of %s %s", obj.ObjectType, obj.ObjectName))
 				case defaultPrivilege:
 					hasDependentDefaultPrivilege = true
 					objectsMsg.WriteString(fmt.Sprintf("\n%s", obj.ErrorMessage))
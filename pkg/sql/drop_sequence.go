@@ -224,7 +224,7 @@ func (p *planner) canRemoveOwnedSequencesImpl(
 
 // dropDependentOnSequence drops the default values of any columns that depend on the
 // given sequence descriptor being dropped, and if the dependent object
-// is a view, it drops the views.
+// is a view or a trigger, it drops the views and triggers.
 // This is called when the DropBehavior is DropCascade.
 func dropDependentOnSequence(ctx context.Context, p *planner, seqDesc *tabledesc.Mutable) error {
 	for _, dependent := range seqDesc.DependedOnBy {
@@ -245,6 +245,14 @@ func dropDependentOnSequence(ctx context.Context, p *planner, seqDesc *tabledesc
 			continue
 		}
 
+		// If the dependent object is a trigger, drop the trigger.
+		if dependent.Trigger {
+			if err := p.dropDependentTriggers(ctx, dependent.ID, seqDesc.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// If the dependent object is a view, drop the view.
 		if tblDesc.IsView() {
 			_, err = p.dropViewImpl(ctx, tblDesc, false /* queueJob */, "", tree.DropCascade)
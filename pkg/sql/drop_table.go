@@ -57,7 +57,7 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP TABLE",
+		n.StatementTag(),
 	); err != nil {
 		return nil, err
 	}
@@ -72,6 +72,9 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 		if droppedDesc == nil {
 			continue
 		}
+		if err := checkTableMatchesForeign(droppedDesc, n.IsForeign); err != nil {
+			return nil, err
+		}
 
 		td[droppedDesc.ID] = toDelete{tn, droppedDesc}
 	}
@@ -272,11 +275,23 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
-	// Drop all views that depend on this table, assuming that we wouldn't have
-	// made it to this point if `cascade` wasn't enabled.
+	// Remove the back-references from the relations which the triggers of this
+	// table depend on.
+	if err := p.removeTriggerBackReferences(ctx, tableDesc); err != nil {
+		return droppedViews, err
+	}
+
+	// Drop all views and triggers that depend on this table, assuming that we
+	// wouldn't have made it to this point if `cascade` wasn't enabled.
 	// Copy out the set of dependencies as it may be overwritten in the loop.
 	dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), tableDesc.DependedOnBy...)
 	for _, ref := range dependedOnBy {
+		if ref.Trigger {
+			if err := p.dropDependentTriggers(ctx, ref.ID, tableDesc.ID); err != nil {
+				return droppedViews, err
+			}
+			continue
+		}
 		viewDesc, err := p.getViewDescForCascade(
 			ctx, string(tableDesc.DescriptorType()), tableDesc.Name, tableDesc.ParentID, ref.ID, tree.DropCascade,
 		)
@@ -0,0 +1,192 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/errors"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *tabledesc.Mutable
+}
+
+// DropTrigger drops a trigger from a table. Nothing can depend on a trigger,
+// so CASCADE and RESTRICT behave in the same way.
+// Privileges: CREATE on table.
+//   notes: postgres requires ownership of the table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and table did not exist -- noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &dropTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	tableDesc := n.tableDesc
+	name := string(n.n.Name)
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name != name {
+			continue
+		}
+		deps := catalog.MakeDescriptorIDSet(tableDesc.Triggers[i].DependsOn...)
+		tableDesc.Triggers = append(tableDesc.Triggers[:i], tableDesc.Triggers[i+1:]...)
+		if err := params.p.updateTriggerBackReferences(params.ctx, tableDesc, deps); err != nil {
+			return err
+		}
+		if err := validateDescriptor(params.ctx, params.p, tableDesc); err != nil {
+			return err
+		}
+		return params.p.writeSchemaChange(
+			params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+		)
+	}
+	if n.n.IfExists {
+		return nil
+	}
+	return pgerror.Newf(pgcode.UndefinedObject,
+		"trigger %q for table %q does not exist", name, tableDesc.Name)
+}
+
+// canRemoveDependentTrigger returns an error if the triggers of the given table
+// which depend on an object being dropped cannot be dropped along with it.
+func (p *planner) canRemoveDependentTrigger(
+	ctx context.Context,
+	typeName string,
+	objName string,
+	ref descpb.TableDescriptor_Reference,
+	behavior tree.DropBehavior,
+) error {
+	tableDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, ref.ID, p.txn)
+	if err != nil {
+		return errors.Wrapf(err, "error resolving dependent table ID %d", ref.ID)
+	}
+	if behavior != tree.DropCascade {
+		tableName, err := p.getQualifiedTableName(ctx, tableDesc)
+		if err != nil {
+			return err
+		}
+		return errors.WithHintf(
+			sqlerrors.NewDependentObjectErrorf(
+				"cannot drop %s %q because a trigger on table %q depends on it",
+				typeName, objName, tableName.FQString()),
+			"you can drop the trigger instead.")
+	}
+	return p.CheckPrivilege(ctx, tableDesc, privilege.CREATE)
+}
+
+// dependentTriggerError returns an error for an operation on an object which
+// is not allowed because a trigger on the given table depends on it.
+func (p *planner) dependentTriggerError(
+	ctx context.Context, typeName, objName string, tableID descpb.ID, op string,
+) error {
+	tableDesc, err := p.Descriptors().Direct().MustGetTableDescByID(ctx, p.txn, tableID)
+	if err != nil {
+		return err
+	}
+	tableName, err := p.getQualifiedTableName(ctx, tableDesc)
+	if err != nil {
+		return err
+	}
+	return errors.WithHintf(
+		sqlerrors.NewDependentObjectErrorf("cannot %s %s %q because a trigger on table %q depends on it",
+			op, typeName, objName, tableName.FQString()),
+		"you can drop the trigger instead.")
+}
+
+// dropDependentTriggers drops the triggers of the table with the given ID which
+// depend on the relation with ID droppedID, which is being dropped.
+func (p *planner) dropDependentTriggers(ctx context.Context, tableID, droppedID descpb.ID) error {
+	tableDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, tableID, p.txn)
+	if err != nil {
+		return errors.Wrapf(err, "error resolving dependent table ID %d", tableID)
+	}
+	if tableDesc.Dropped() {
+		return nil
+	}
+	var deps catalog.DescriptorIDSet
+	triggers := tableDesc.Triggers[:0]
+	for i := range tableDesc.Triggers {
+		trig := &tableDesc.Triggers[i]
+		if !triggerDependsOn(trig, droppedID) {
+			triggers = append(triggers, *trig)
+			continue
+		}
+		for _, id := range trig.DependsOn {
+			if id != droppedID {
+				deps.Add(id)
+			}
+		}
+	}
+	tableDesc.Triggers = triggers
+	if err := p.updateTriggerBackReferences(ctx, tableDesc, deps); err != nil {
+		return err
+	}
+	return p.writeSchemaChange(
+		ctx, tableDesc, descpb.InvalidMutationID,
+		fmt.Sprintf("dropping triggers of table %s(%d) which depend on relation %d",
+			tableDesc.Name, tableDesc.ID, droppedID),
+	)
+}
+
+// removeTriggerBackReferences removes the back-references to the triggers of
+// the given table, which is being dropped.
+func (p *planner) removeTriggerBackReferences(
+	ctx context.Context, tableDesc *tabledesc.Mutable,
+) error {
+	var deps catalog.DescriptorIDSet
+	for i := range tableDesc.Triggers {
+		for _, id := range tableDesc.Triggers[i].DependsOn {
+			deps.Add(id)
+		}
+	}
+	for _, id := range deps.Ordered() {
+		if err := p.setTriggerBackReference(ctx, tableDesc, id, false /* used */); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropTriggerNode) Close(context.Context)        {}
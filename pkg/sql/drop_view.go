@@ -152,6 +152,9 @@ func (p *planner) canRemoveDependentViewGeneric(
 	ref descpb.TableDescriptor_Reference,
 	behavior tree.DropBehavior,
 ) error {
+	if ref.Trigger {
+		return p.canRemoveDependentTrigger(ctx, typeName, objName, ref, behavior)
+	}
 	viewDesc, err := p.getViewDescForCascade(ctx, typeName, objName, parentID, ref.ID, behavior)
 	if err != nil {
 		return err
@@ -228,6 +231,12 @@ func (p *planner) dropViewImpl(
 	if behavior == tree.DropCascade {
 		dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), viewDesc.DependedOnBy...)
 		for _, ref := range dependedOnBy {
+			if ref.Trigger {
+				if err := p.dropDependentTriggers(ctx, ref.ID, viewDesc.ID); err != nil {
+					return cascadeDroppedViews, err
+				}
+				continue
+			}
 			dependentDesc, err := p.getViewDescForCascade(
 				ctx, string(viewDesc.DescriptorType()), viewDesc.Name, viewDesc.ParentID, ref.ID, behavior,
 			)
@@ -18,7 +18,9 @@ import (
 )
 
 // errorIfRowsNode wraps another planNode and returns an error if the wrapped
-// node produces any rows.
+// node produces any rows. If the node checks a DEFERRABLE constraint which is
+// deferred in the current transaction, the rows are instead queued to be
+// checked again when the transaction commits.
 type errorIfRowsNode struct {
 	plan planNode
 
@@ -26,6 +28,9 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// deferrable is set if the node checks a DEFERRABLE constraint.
+	deferrable *exec.DeferrableCheck
+
 	nexted bool
 }
 
@@ -39,6 +44,10 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
+	if n.deferrable != nil && params.p.deferredChecks.isDeferred(n.deferrable) {
+		return false, n.queueDeferredChecks(params)
+	}
+
 	ok, err := n.plan.Next(params)
 	if err != nil {
 		return false, err
@@ -49,6 +58,23 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	return false, nil
 }
 
+// queueDeferredChecks queues the keys of all rows produced by the wrapped node
+// to be checked at the end of the transaction.
+func (n *errorIfRowsNode) queueDeferredChecks(params runParams) error {
+	for {
+		ok, err := n.plan.Next(params)
+		if err != nil || !ok {
+			return err
+		}
+		row := n.plan.Values()
+		keyVals := make(tree.Datums, len(n.deferrable.KeyCols))
+		for i, ord := range n.deferrable.KeyCols {
+			keyVals[i] = row[ord]
+		}
+		params.p.deferredChecks.queue(n.deferrable, keyVals, n.mkErr(row))
+	}
+}
+
 func (n *errorIfRowsNode) Values() tree.Datums {
 	return nil
 }
@@ -0,0 +1,441 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// addExclusionConstraintTableDef runs various checks on the given
+// ExclusionConstraintTableDef before adding it as an EXCLUDE constraint to the
+// given table descriptor.
+//
+// EXCLUDE constraints are not backed by an index of their own. Like UNIQUE
+// WITHOUT INDEX constraints, they are enforced by the optimizer, which adds a
+// check to every mutation of the table that looks for conflicting rows using
+// the existing indexes of the table. Inverted indexes are used to find rows
+// with overlapping values. If no index applies, every mutation scans the whole
+// table, so its cost is linear in the size of the table. For this reason, the
+// USING clause, which names the access method of the backing index in
+// Postgres, is rejected rather than ignored.
+//
+// The constraint is validated immediately if the table is new. Otherwise it
+// is added in the Validating state, unless validation is skipped, and the
+// existing rows are validated by the schema changer once all nodes enforce the
+// constraint. See SchemaChanger.maybeValidateExclusionConstraints.
+func addExclusionConstraintTableDef(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	d *tree.ExclusionConstraintTableDef,
+	desc *tabledesc.Mutable,
+	tn tree.TableName,
+	ts TableState,
+	validationBehavior tree.ValidationBehavior,
+	semaCtx *tree.SemaContext,
+) error {
+	if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.ExclusionConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create exclusion constraints",
+			clusterversion.ByKey(clusterversion.ExclusionConstraints))
+	}
+
+	if d.IndexMethod != "" {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"access method %q is not supported in exclusion constraints", d.IndexMethod),
+			"Exclusion constraints are not backed by an index. Omit the USING clause; "+
+				"conflicting rows are then found with the existing indexes of the table, "+
+				"or by scanning the table if none of them applies.",
+		)
+	}
+
+	c := descpb.TableDescriptor_ExclusionConstraint{
+		ColumnIDs: make([]descpb.ColumnID, len(d.Elems)),
+		Operators: make([]string, len(d.Elems)),
+	}
+	colNames := make([]string, len(d.Elems))
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		if elem.Expr != nil {
+			return unimplemented.NewWithIssue(46657, "expressions in exclusion constraints are not supported")
+		}
+		if elem.OpClass != "" || elem.Direction != tree.DefaultDirection ||
+			elem.NullsOrder != tree.DefaultNullsOrder {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"operator classes and orderings are not supported in exclusion constraints")
+		}
+		col, err := desc.FindActiveOrNewColumnByName(elem.Column)
+		if err != nil {
+			return err
+		}
+		if col.IsInaccessible() {
+			return pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q is inaccessible and cannot be referenced by an exclusion constraint",
+				col.GetName())
+		}
+		if err := checkExclusionOperator(elem.Operator.Symbol, col.GetType()); err != nil {
+			return err
+		}
+		c.ColumnIDs[i] = col.GetID()
+		c.Operators[i] = elem.Operator.Symbol.String()
+		colNames[i] = col.GetName()
+	}
+
+	if d.Predicate != nil {
+		var err error
+		c.Predicate, _, _, err = schemaexpr.DequalifyAndValidateExpr(
+			ctx,
+			desc,
+			d.Predicate,
+			types.Bool,
+			"exclusion constraint predicate",
+			semaCtx,
+			volatility.Immutable,
+			&tn,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	constraintInfo, err := desc.GetConstraintInfo()
+	if err != nil {
+		return err
+	}
+	c.Name = string(d.Name)
+	if c.Name == "" {
+		c.Name = tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", desc.Name, strings.Join(colNames, "_")),
+			func(p string) bool {
+				_, ok := constraintInfo[p]
+				return ok
+			},
+		)
+	} else if _, ok := constraintInfo[c.Name]; ok {
+		return pgerror.Newf(pgcode.DuplicateObject, "duplicate constraint name: %q", c.Name)
+	}
+
+	c.Validity = descpb.ConstraintValidity_Validated
+	if ts != NewTable {
+		if validationBehavior == tree.ValidationSkip {
+			c.Validity = descpb.ConstraintValidity_Unvalidated
+		} else {
+			c.Validity = descpb.ConstraintValidity_Validating
+		}
+	}
+	c.ConstraintID = desc.NextConstraintID
+	desc.NextConstraintID++
+	desc.ExclusionConstraints = append(desc.ExclusionConstraints, c)
+	return nil
+}
+
+// checkExclusionOperator returns an error if the given operator cannot be
+// used to compare values of the given type in an EXCLUDE constraint.
+func checkExclusionOperator(op treecmp.ComparisonOperatorSymbol, typ *types.T) error {
+	switch op {
+	case treecmp.EQ:
+		if !tree.EqualComparisonFunctionExists(typ, typ) {
+			return pgerror.Newf(pgcode.UndefinedFunction,
+				"unsupported comparison operator: <%s> %s <%s>", typ, op, typ)
+		}
+	case treecmp.Overlaps:
+		if _, ok := tree.CmpOps[treecmp.Overlaps].LookupImpl(typ, typ); !ok {
+			return pgerror.Newf(pgcode.UndefinedFunction,
+				"unsupported comparison operator: <%s> %s <%s>", typ, op, typ)
+		}
+	default:
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"operator %s is not supported in exclusion constraints", op)
+	}
+	return nil
+}
+
+// formatExclusionConstraint formats the definition of the given EXCLUDE
+// constraint, without its name, for SHOW CREATE and pg_constraint.
+func formatExclusionConstraint(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	c *descpb.TableDescriptor_ExclusionConstraint,
+	semaCtx *tree.SemaContext,
+	sessionData *sessiondata.SessionData,
+	predFmtFlags tree.FmtFlags,
+	f *tree.FmtCtx,
+) error {
+	colNames, err := desc.NamesForColumnIDs(c.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	f.WriteString("EXCLUDE (")
+	for i := range colNames {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		formatQuoteNames(&f.Buffer, colNames[i])
+		f.WriteString(" WITH ")
+		f.WriteString(c.Operators[i])
+	}
+	f.WriteByte(')')
+	if c.Predicate != "" {
+		pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, predFmtFlags)
+		if err != nil {
+			return err
+		}
+		f.WriteString(" WHERE (")
+		f.WriteString(pred)
+		f.WriteByte(')')
+	}
+	return nil
+}
+
+// exclusionViolationQuery generates and returns a query that returns a pair of
+// distinct rows of the table which conflict according to the given EXCLUDE
+// constraint. The query has the form:
+//
+// SELECT a.c1, a.c2, b.c1, b.c2
+// FROM (SELECT c1, c2, pk1 FROM [<ID of srcTbl> AS tbl] WHERE <pred>) AS a,
+//      (SELECT c1, c2, pk1 FROM [<ID of srcTbl> AS tbl] WHERE <pred>) AS b
+// WHERE a.c1 = b.c1 AND a.c2 && b.c2 AND (a.pk1) != (b.pk1)
+// LIMIT 1
+//
+// Rows are identified by their primary key. The WHERE clause of the subqueries
+// is omitted if the constraint is not partial.
+func exclusionViolationQuery(
+	srcTbl catalog.TableDescriptor, c *descpb.TableDescriptor_ExclusionConstraint,
+) (sql string, colNames []string, _ error) {
+	colNames, err := srcTbl.NamesForColumnIDs(c.ColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	pkColNames := srcTbl.GetPrimaryIndex().IndexDesc().KeyColumnNames
+
+	// The columns projected by the subqueries are the columns of the constraint
+	// followed by the primary key columns, without duplicates.
+	var srcCols []string
+	seen := make(map[string]struct{})
+	for _, names := range [][]string{colNames, pkColNames} {
+		for _, n := range names {
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				srcCols = append(srcCols, tree.NameString(n))
+			}
+		}
+	}
+
+	resultCols := make([]string, 0, 2*len(colNames))
+	for _, alias := range []string{"a", "b"} {
+		for _, n := range colNames {
+			resultCols = append(resultCols, fmt.Sprintf("%s.%s", alias, tree.NameString(n)))
+		}
+	}
+
+	where := make([]string, 0, len(colNames)+1)
+	for i, n := range colNames {
+		where = append(where, fmt.Sprintf(
+			"a.%[1]s %[2]s b.%[1]s", tree.NameString(n), c.Operators[i],
+		))
+	}
+	aPK := make([]string, len(pkColNames))
+	bPK := make([]string, len(pkColNames))
+	for i, n := range pkColNames {
+		aPK[i] = "a." + tree.NameString(n)
+		bPK[i] = "b." + tree.NameString(n)
+	}
+	where = append(where, fmt.Sprintf(
+		"(%s) != (%s)", strings.Join(aPK, ", "), strings.Join(bPK, ", "),
+	))
+
+	pred := ""
+	if c.Predicate != "" {
+		pred = fmt.Sprintf(" WHERE (%s)", c.Predicate)
+	}
+	src := fmt.Sprintf(
+		"(SELECT %s FROM [%d AS tbl]%s)", strings.Join(srcCols, ", "), srcTbl.GetID(), pred,
+	)
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM %[2]s AS a, %[2]s AS b WHERE %[3]s LIMIT 1`,
+		strings.Join(resultCols, ", "), // 1
+		src,                            // 2
+		strings.Join(where, " AND "),   // 3
+	), colNames, nil
+}
+
+// validateExclusionConstraint verifies that no two rows of the table conflict
+// according to the given EXCLUDE constraint.
+func validateExclusionConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	c *descpb.TableDescriptor_ExclusionConstraint,
+	ie sqlutil.InternalExecutor,
+	txn *kv.Txn,
+	user username.SQLUsername,
+	preExisting bool,
+) error {
+	query, colNames, err := exclusionViolationQuery(srcTable, c)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		c.Name,
+		srcTable.GetName(),
+		colNames,
+		query,
+	)
+
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	values, err := ie.QueryRowEx(ctx, "validate exclusion constraint", txn, sessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		valuesStr := make([]string, len(values))
+		for i := range values {
+			valuesStr[i] = values[i].String()
+		}
+		n := len(colNames)
+		// Note: this error message mirrors the message produced by Postgres
+		// when it fails to add an exclusion constraint due to conflicting rows.
+		errMsg := "could not create exclusion constraint"
+		if preExisting {
+			errMsg = "failed to validate exclusion constraint"
+		}
+		cols := strings.Join(colNames, ", ")
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(pgcode.ExclusionViolation, "%s %q", errMsg, c.Name),
+				c.Name,
+			),
+			fmt.Sprintf(
+				"Key (%s)=(%s) conflicts with key (%s)=(%s).",
+				cols, strings.Join(valuesStr[:n], ", "), cols, strings.Join(valuesStr[n:], ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// validateExclusionConstraintInTxn validates the EXCLUDE constraint with the
+// given name using the provided transaction.
+func validateExclusionConstraintInTxn(
+	ctx context.Context,
+	ie sqlutil.InternalExecutor,
+	tableDesc *tabledesc.Mutable,
+	txn *kv.Txn,
+	user username.SQLUsername,
+	constraintName string,
+) error {
+	var syntheticDescs []catalog.Descriptor
+	if tableDesc.Version > tableDesc.ClusterVersion().Version {
+		syntheticDescs = append(syntheticDescs, tableDesc)
+	}
+
+	var c *descpb.TableDescriptor_ExclusionConstraint
+	for i := range tableDesc.ExclusionConstraints {
+		if def := &tableDesc.ExclusionConstraints[i]; def.Name == constraintName {
+			c = def
+			break
+		}
+	}
+	if c == nil {
+		return errors.AssertionFailedf("exclusion constraint %s does not exist", constraintName)
+	}
+
+	return ie.WithSyntheticDescriptors(syntheticDescs, func() error {
+		return validateExclusionConstraint(ctx, tableDesc, c, ie, txn, user, true /* preExisting */)
+	})
+}
+
+// maybeValidateExclusionConstraints validates the EXCLUDE constraints of the
+// table which are in the Validating state. These constraints were added to an
+// existing table, and are validated once all nodes use a version of the
+// descriptor which includes them, so that no write which escaped the
+// constraint can be missed. A constraint which fails validation is removed
+// from the table and its validation error is returned.
+func (sc *SchemaChanger) maybeValidateExclusionConstraints(
+	ctx context.Context, table catalog.TableDescriptor,
+) error {
+	validating := false
+	for _, c := range table.GetExclusionConstraints() {
+		if c.Validity == descpb.ConstraintValidity_Validating {
+			validating = true
+			break
+		}
+	}
+	if !validating {
+		return nil
+	}
+
+	// Wait until the constraints are enforced by all nodes.
+	if _, err := WaitToUpdateLeases(ctx, sc.leaseMgr, sc.descID); err != nil {
+		return err
+	}
+	log.Info(ctx, "validating exclusion constraints")
+
+	var validationErr error
+	if err := sc.txn(ctx, func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		validationErr = nil
+		mut, err := descsCol.GetMutableTableVersionByID(ctx, table.GetID(), txn)
+		if err != nil {
+			return err
+		}
+		sd := NewFakeSessionData(sc.execCfg.SV())
+		ie := sc.ieFactory(ctx, sd)
+		kept := mut.ExclusionConstraints[:0]
+		for i := range mut.ExclusionConstraints {
+			c := mut.ExclusionConstraints[i]
+			if c.Validity == descpb.ConstraintValidity_Validating {
+				if err := validateExclusionConstraint(
+					ctx, mut, &c, ie, txn, sd.User(), false, /* preExisting */
+				); err != nil {
+					if pgerror.GetPGCode(err) != pgcode.ExclusionViolation {
+						return err
+					}
+					if validationErr == nil {
+						validationErr = err
+					}
+					continue
+				}
+				c.Validity = descpb.ConstraintValidity_Validated
+			}
+			kept = append(kept, c)
+		}
+		mut.ExclusionConstraints = kept
+		return descsCol.WriteDesc(ctx, true /* kvTrace */, mut, txn)
+	}); err != nil {
+		return err
+	}
+	return validationErr
+}
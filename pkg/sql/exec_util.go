@@ -67,6 +67,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/gcjob/gcjobnotifier"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -211,6 +212,17 @@ var secondaryTenantZoneConfigsEnabled = settings.RegisterBoolSetting(
 	false,
 )
 
+// allowReadCommittedIsolation controls whether transactions which request the
+// READ COMMITTED isolation level run at that level. When false, they are
+// upgraded to SERIALIZABLE.
+var allowReadCommittedIsolation = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.txn.read_committed_isolation.enabled",
+	"set to true to allow transactions to use the READ COMMITTED isolation level if specified "+
+		"by BEGIN/SET commands; when false, such transactions run at SERIALIZABLE",
+	false,
+).WithPublic()
+
 // traceTxnThreshold can be used to log SQL transactions that take
 // longer than duration to complete. For example, traceTxnThreshold=1s
 // will log the trace for any transaction that takes 1s or longer. To
@@ -1226,6 +1238,10 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// NotifyRegistry dispatches the notifications sent with NOTIFY to the
+	// sessions listening on their channels.
+	NotifyRegistry *notify.Registry
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	GCJobNotifier *gcjobnotifier.Notifier
@@ -1749,6 +1765,11 @@ func checkResultType(typ *types.T) error {
 	case types.TimestampTZFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.JsonpathFamily:
+	case types.TSQueryFamily:
+	case types.TSVectorFamily:
+	case types.PGVectorFamily:
+	case types.RangeFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -1938,6 +1959,10 @@ type SessionArgs struct {
 	// authentication is skipped. Once the token is used to authenticate, this
 	// value should be zeroed out.
 	SessionRevivalToken []byte
+	// LogicalReplication is set if the client connected with the
+	// replication=database startup parameter. Such connections can issue the
+	// commands of the replication protocol in addition to regular SQL.
+	LogicalReplication bool
 }
 
 // SessionRegistry stores a set of all sessions on this node.
@@ -2908,10 +2933,18 @@ func (m *sessionDataMutator) SetDefaultIntSize(size int32) {
 	}
 }
 
+func (m *sessionDataMutator) SetDefaultTextSearchConfig(val string) {
+	m.data.DefaultTextSearchConfig = val
+}
+
 func (m *sessionDataMutator) SetDefaultTransactionPriority(val tree.UserPriority) {
 	m.data.DefaultTxnPriority = int64(val)
 }
 
+func (m *sessionDataMutator) SetDefaultTransactionIsolationLevel(val tree.IsolationLevel) {
+	m.data.DefaultTxnIsolationLevel = int64(val)
+}
+
 func (m *sessionDataMutator) SetDefaultTransactionReadOnly(val bool) {
 	m.data.DefaultTxnReadOnly = val
 }
@@ -3193,6 +3226,10 @@ func (m *sessionDataMutator) SetTxnRowsReadErr(val int64) {
 	m.data.TxnRowsReadErr = val
 }
 
+func (m *sessionDataMutator) SetIvfflatProbes(val int64) {
+	m.data.IvfflatProbes = val
+}
+
 func (m *sessionDataMutator) SetLargeFullScanRows(val float64) {
 	m.data.LargeFullScanRows = val
 }
@@ -104,6 +104,14 @@ func GetAggregateConstructor(
 		argTypes[len(aggInfo.ColIdx)+j] = d.ResolvedType()
 		arguments[j] = d
 	}
+	if aggInfo.UserDefined != nil {
+		// The constant arguments of a user-defined aggregate are passed to its
+		// expressions after the other arguments.
+		constructor, outputType, err = GetUserDefinedAggregateInfo(
+			evalCtx, semaCtx, aggInfo.UserDefined, argTypes...,
+		)
+		return
+	}
 	constructor, outputType, err = GetAggregateInfo(aggInfo.Func, argTypes...)
 	return
 }
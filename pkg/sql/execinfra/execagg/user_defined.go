@@ -0,0 +1,256 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execagg
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// GetUserDefinedAggregateInfo returns the aggregate constructor and the return
+// type for the given user-defined aggregation when applied on the given types.
+// The expressions of the aggregate are type checked once and shared by all
+// the aggregate functions created by the constructor.
+//
+// evalCtx will not be mutated.
+func GetUserDefinedAggregateInfo(
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	uda *execinfrapb.AggregatorSpec_UserDefinedAggregation,
+	inputTypes ...*types.T,
+) (aggregateConstructor AggregateConstructor, returnType *types.T, err error) {
+	name, def := uda.Aggregate.Name, uda.Aggregate.Aggregate
+	if def == nil {
+		return nil, nil, errors.AssertionFailedf("function %s is not an aggregate", name)
+	}
+	var step, final tree.TypedExpr
+	var stepTypes []*types.T
+	switch uda.Stage {
+	case execinfrapb.AggregatorSpec_UserDefinedAggregation_FULL,
+		execinfrapb.AggregatorSpec_UserDefinedAggregation_PARTIAL:
+		if len(inputTypes) != len(uda.Aggregate.Args) {
+			return nil, nil, errors.AssertionFailedf(
+				"aggregate %s expects %d arguments, found %d", name, len(uda.Aggregate.Args), len(inputTypes))
+		}
+		// The transition expression refers to the state as @1 and to the
+		// arguments as @2, @3, ...
+		stepTypes = append([]*types.T{def.StateType}, inputTypes...)
+		if step, err = deserializeAggregateExpr(evalCtx, semaCtx, def.TransitionExpr, stepTypes); err != nil {
+			return nil, nil, err
+		}
+	case execinfrapb.AggregatorSpec_UserDefinedAggregation_FINAL:
+		if len(inputTypes) != 1 {
+			return nil, nil, errors.AssertionFailedf(
+				"final stage of aggregate %s expects a single state, found %d", name, len(inputTypes))
+		}
+		if def.CombineExpr == "" {
+			return nil, nil, errors.AssertionFailedf("aggregate %s has no combine expression", name)
+		}
+		// The combine expression refers to the two states as @1 and @2.
+		stepTypes = []*types.T{def.StateType, def.StateType}
+		if step, err = deserializeAggregateExpr(evalCtx, semaCtx, def.CombineExpr, stepTypes); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, errors.AssertionFailedf("unknown aggregation stage %s", uda.Stage)
+	}
+
+	returnType = uda.Aggregate.ReturnType
+	if uda.Stage == execinfrapb.AggregatorSpec_UserDefinedAggregation_PARTIAL {
+		// The partial stage outputs the state, which is finalized by the final
+		// stage.
+		returnType = def.StateType
+	} else if def.FinalExpr != "" {
+		// The final expression refers to the state as @1.
+		finalTypes := []*types.T{def.StateType}
+		if final, err = deserializeAggregateExpr(evalCtx, semaCtx, def.FinalExpr, finalTypes); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	initial := tree.Datum(tree.DNull)
+	if def.InitialCondition != nil {
+		if initial, _, err = tree.ParseAndRequireString(def.StateType, *def.InitialCondition, evalCtx); err != nil {
+			return nil, nil, errors.Wrapf(err, "initial condition of aggregate %s", name)
+		}
+	}
+
+	aggregateConstructor = func(evalCtx *eval.Context, arguments tree.Datums) eval.AggregateFunc {
+		a := &userDefinedAggregate{
+			evalCtx:   evalCtx,
+			combine:   uda.Stage == execinfrapb.AggregatorSpec_UserDefinedAggregation_FINAL,
+			step:      step,
+			final:     final,
+			initial:   initial,
+			state:     initial,
+			arguments: arguments,
+			types:     stepTypes,
+		}
+		if evalCtx.SingleDatumAggMemAccount != nil {
+			a.acc = evalCtx.SingleDatumAggMemAccount
+		} else {
+			acc := evalCtx.Mon.MakeBoundAccount()
+			a.acc, a.ownsAcc = &acc, true
+		}
+		return a
+	}
+	return aggregateConstructor, returnType, nil
+}
+
+// deserializeAggregateExpr deserializes one of the expressions of a
+// user-defined aggregate, whose ordinal references have the given types.
+func deserializeAggregateExpr(
+	evalCtx *eval.Context, semaCtx *tree.SemaContext, expr string, typs []*types.T,
+) (tree.TypedExpr, error) {
+	h := tree.MakeTypesOnlyIndexedVarHelper(typs)
+	typedExpr, err := execinfrapb.DeserializeExpr(expr, semaCtx, evalCtx, &h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", expr)
+	}
+	return typedExpr, nil
+}
+
+// userDefinedAggregate is the eval.AggregateFunc of an aggregate created with
+// CREATE AGGREGATE. Its state is folded with every input row using the
+// transition expression of the aggregate or, in the final stage of a
+// multi-stage aggregation, with every partial state using the combine
+// expression.
+type userDefinedAggregate struct {
+	evalCtx *eval.Context
+	// combine is set if the inputs are partial states.
+	combine bool
+	// step computes the next state from the current state and an input row.
+	step tree.TypedExpr
+	// final computes the result from the state. It is nil if the state is
+	// the result.
+	final   tree.TypedExpr
+	initial tree.Datum
+	state   tree.Datum
+	// seen is set once a partial state has been added in the final stage.
+	seen bool
+	// arguments are the constant arguments of the aggregate, which follow
+	// the arguments passed to Add.
+	arguments tree.Datums
+	// types are the types of the inputs of step.
+	types []*types.T
+	// row is the current input of step or final, whose first element is the
+	// state.
+	row tree.Datums
+	// acc accounts for the memory of the state, whose size may grow with
+	// every input. Like for the builtin aggregates, it is shared by all the
+	// aggregates of the processor if evalCtx.SingleDatumAggMemAccount is set,
+	// in which case ownsAcc is false.
+	acc     *mon.BoundAccount
+	ownsAcc bool
+	// accountedFor is the memory registered with acc by this aggregate.
+	accountedFor int64
+}
+
+var _ eval.AggregateFunc = &userDefinedAggregate{}
+var _ eval.IndexedVarContainer = &userDefinedAggregate{}
+
+// Add implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Add(
+	ctx context.Context, firstArg tree.Datum, otherArgs ...tree.Datum,
+) error {
+	if a.combine && !a.seen {
+		// The first partial state starts the combined state, since it has
+		// already been initialized by the local stage.
+		a.seen = true
+		return a.setState(ctx, firstArg)
+	}
+	a.row = append(a.row[:0], a.state, firstArg)
+	a.row = append(a.row, otherArgs...)
+	a.row = append(a.row, a.arguments...)
+	state, err := a.eval(a.step)
+	if err != nil {
+		return err
+	}
+	return a.setState(ctx, state)
+}
+
+// setState replaces the state and updates the memory account by the
+// difference between the sizes of the new and the previous states.
+func (a *userDefinedAggregate) setState(ctx context.Context, state tree.Datum) error {
+	newUsage := int64(state.Size())
+	if err := a.acc.Grow(ctx, newUsage-a.accountedFor); err != nil {
+		return err
+	}
+	a.state, a.accountedFor = state, newUsage
+	return nil
+}
+
+// Result implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Result() (tree.Datum, error) {
+	if a.final == nil {
+		return a.state, nil
+	}
+	a.row = append(a.row[:0], a.state)
+	return a.eval(a.final)
+}
+
+// Reset implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Reset(ctx context.Context) {
+	a.state = a.initial
+	a.seen = false
+	if a.ownsAcc {
+		a.acc.Clear(ctx)
+	} else {
+		a.acc.Shrink(ctx, a.accountedFor)
+	}
+	a.accountedFor = 0
+}
+
+// Close implements the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Close(ctx context.Context) {
+	if a.ownsAcc {
+		a.acc.Close(ctx)
+	} else {
+		a.acc.Shrink(ctx, a.accountedFor)
+	}
+	a.accountedFor = 0
+}
+
+var sizeOfUserDefinedAggregate = int64(unsafe.Sizeof(userDefinedAggregate{}))
+
+// Size implements the eval.AggregateFunc interface. It only includes the
+// aggregate itself: the memory of the state is registered with acc.
+func (a *userDefinedAggregate) Size() int64 {
+	return sizeOfUserDefinedAggregate
+}
+
+func (a *userDefinedAggregate) eval(expr tree.TypedExpr) (tree.Datum, error) {
+	a.evalCtx.PushIVarContainer(a)
+	defer a.evalCtx.PopIVarContainer()
+	return eval.Expr(a.evalCtx, expr)
+}
+
+// IndexedVarEval implements the eval.IndexedVarContainer interface.
+func (a *userDefinedAggregate) IndexedVarEval(idx int, _ tree.ExprEvaluator) (tree.Datum, error) {
+	return a.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (a *userDefinedAggregate) IndexedVarResolvedType(idx int) *types.T {
+	return a.types[idx]
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (a *userDefinedAggregate) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	return nil
+}
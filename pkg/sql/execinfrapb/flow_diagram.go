@@ -106,7 +106,14 @@ func (a *AggregatorSpec) summary() (string, []string) {
 	}
 	for _, agg := range a.Aggregations {
 		var buf bytes.Buffer
-		buf.WriteString(agg.Func.String())
+		if agg.UserDefined != nil {
+			buf.WriteString(agg.UserDefined.Aggregate.Name)
+			if agg.UserDefined.Stage != AggregatorSpec_UserDefinedAggregation_FULL {
+				fmt.Fprintf(&buf, "_%s", strings.ToLower(agg.UserDefined.Stage.String()))
+			}
+		} else {
+			buf.WriteString(agg.Func.String())
+		}
 		buf.WriteByte('(')
 
 		if agg.Distinct {
@@ -514,6 +521,15 @@ func (c *ReadImportDataSpec) summary() (string, []string) {
 	return "ReadImportData", ss
 }
 
+// summary implements the diagramCellType interface.
+func (s *ForeignScanSpec) summary() (string, []string) {
+	details := []string{
+		s.Table.Name,
+		fmt.Sprintf("%d files", len(s.Files)),
+	}
+	return "ForeignScan", details
+}
+
 // summary implements the diagramCellType interface.
 func (s *StreamIngestionDataSpec) summary() (string, []string) {
 	return "StreamIngestionData", []string{}
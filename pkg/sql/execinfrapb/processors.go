@@ -41,6 +41,11 @@ func (a AggregatorSpec_Aggregation) Equals(b AggregatorSpec_Aggregation) bool {
 	if a.Func != b.Func || a.Distinct != b.Distinct {
 		return false
 	}
+	// User-defined aggregations are only considered identical if they share
+	// the same definition.
+	if a.UserDefined != b.UserDefined {
+		return false
+	}
 	if a.FilterColIdx == nil {
 		if b.FilterColIdx != nil {
 			return false
@@ -524,7 +524,10 @@ func (c *stmtEnvCollector) PrintSessionSettings(w io.Writer) error {
 	relevantSettings := []struct {
 		sessionSetting string
 		clusterSetting settings.NonMaskedSetting
-		convFunc       func(string) string
+		// defaultValue is the default value of a session setting that has no
+		// cluster setting.
+		defaultValue string
+		convFunc     func(string) string
 	}{
 		{sessionSetting: "reorder_joins_limit", clusterSetting: ReorderJoinsLimitClusterValue},
 		{sessionSetting: "enable_zigzag_join", clusterSetting: zigzagJoinClusterMode, convFunc: boolToOnOff},
@@ -536,7 +539,8 @@ func (c *stmtEnvCollector) PrintSessionSettings(w io.Writer) error {
 		{sessionSetting: "disallow_full_table_scans", clusterSetting: disallowFullTableScans, convFunc: boolToOnOff},
 		{sessionSetting: "large_full_scan_rows", clusterSetting: largeFullScanRows},
 		{sessionSetting: "cost_scans_with_default_col_size", clusterSetting: costScansWithDefaultColSize, convFunc: boolToOnOff},
-		{sessionSetting: "default_transaction_quality_of_service"},
+		{sessionSetting: "ivfflat.probes", defaultValue: "1"},
+		{sessionSetting: "default_transaction_quality_of_service", defaultValue: sessiondatapb.Normal.String()},
 		{sessionSetting: "distsql", clusterSetting: DistSQLClusterExecMode, convFunc: distsqlConv},
 		{sessionSetting: "vectorize", clusterSetting: VectorizeClusterMode, convFunc: vectorizeConv},
 	}
@@ -549,9 +553,7 @@ func (c *stmtEnvCollector) PrintSessionSettings(w io.Writer) error {
 		// Get the default value for the cluster setting.
 		var def string
 		if s.clusterSetting == nil {
-			// Special handling for default_transaction_quality_of_service since it
-			// has no cluster setting.
-			def = sessiondatapb.Normal.String()
+			def = s.defaultValue
 		} else {
 			def = s.clusterSetting.EncodedDefault()
 		}
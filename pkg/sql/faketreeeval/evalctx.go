@@ -227,6 +227,30 @@ func (*DummyEvalPlanner) ExternalWriteFile(ctx context.Context, uri string, cont
 	return errors.WithStack(errEvalPlanner)
 }
 
+// SendNotification is part of the Planner interface.
+func (*DummyEvalPlanner) SendNotification(ctx context.Context, channel string, payload string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// AcquireAdvisoryLock is part of the Planner interface.
+func (*DummyEvalPlanner) AcquireAdvisoryLock(
+	ctx context.Context, lock eval.AdvisoryLock, wait bool,
+) (bool, error) {
+	return false, errors.WithStack(errEvalPlanner)
+}
+
+// ReleaseAdvisoryLock is part of the Planner interface.
+func (*DummyEvalPlanner) ReleaseAdvisoryLock(
+	ctx context.Context, lock eval.AdvisoryLock,
+) (bool, error) {
+	return false, errors.WithStack(errEvalPlanner)
+}
+
+// ReleaseAllAdvisoryLocks is part of the Planner interface.
+func (*DummyEvalPlanner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
 // DecodeGist is part of the Planner interface.
 func (*DummyEvalPlanner) DecodeGist(gist string, external bool) ([]string, error) {
 	return nil, errors.WithStack(errEvalPlanner)
@@ -0,0 +1,256 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// foreignScanNode reads the rows of a foreign table from its files. Like
+// scanNode, it cannot be run in local mode: it is always planned as
+// ForeignScan processors, which read the files in parallel when the plan is
+// distributed.
+type foreignScanNode struct {
+	desc catalog.TableDescriptor
+	// cols are the columns produced by the scan. There is a 1-1
+	// correspondence between cols and resultColumns.
+	cols          []catalog.Column
+	resultColumns colinfo.ResultColumns
+
+	reqOrdering ReqOrdering
+
+	// If non-zero, hardLimit indicates that the scan only needs to provide
+	// this many rows.
+	hardLimit int64
+
+	// rowIDSpans, if set, are the ranges of rowid values of the rows produced
+	// by the scan.
+	rowIDSpans []execinfrapb.ForeignScanSpec_RowIDSpan
+}
+
+// constructForeignScan creates a foreignScanNode for a scan of the primary
+// index of a foreign table. The rows of a foreign table can only be read in
+// the order of the rowid column, so reverse scans are not supported. The files
+// have no index either, so a constrained scan reads all the files whose rows
+// may satisfy the constraint and filters out the rows outside of its spans.
+func constructForeignScan(
+	table cat.Table, params exec.ScanParams, reqOrdering exec.OutputOrdering,
+) (exec.Node, error) {
+	desc := table.(*optTable).desc
+	colCfg := makeScanColumnsConfig(table, params.NeededCols)
+	cols, err := initColsForScan(desc, colCfg)
+	if err != nil {
+		return nil, err
+	}
+	resultColumns := colinfo.ResultColumnsFromColumns(desc.GetID(), cols)
+
+	if c := params.IndexConstraint; c != nil {
+		if c.IsContradiction() {
+			return newZeroNode(resultColumns), nil
+		}
+	}
+	if params.InvertedConstraint != nil {
+		return nil, unimplemented.Newf("foreign table inverted scan",
+			"inverted scans of foreign tables are not supported")
+	}
+	if params.Reverse {
+		return nil, unimplemented.Newf("foreign table reverse scan",
+			"reverse scans of foreign tables are not supported")
+	}
+	if params.Locking.IsLocking() {
+		return nil, unimplemented.Newf("foreign table locking",
+			"row-level locking is not supported with foreign tables")
+	}
+	for _, col := range cols {
+		if col.IsSystemColumn() {
+			return nil, unimplemented.Newf("foreign table system columns",
+				"system column %q is not supported with foreign tables", col.GetName())
+		}
+	}
+	if err := colCfg.assertValidReqOrdering(reqOrdering); err != nil {
+		return nil, err
+	}
+	var rowIDSpans []execinfrapb.ForeignScanSpec_RowIDSpan
+	if c := params.IndexConstraint; c != nil && !c.IsUnconstrained() {
+		rowIDSpans, err = foreignRowIDSpans(c)
+		if err != nil {
+			return nil, err
+		}
+		if len(rowIDSpans) == 0 {
+			return newZeroNode(resultColumns), nil
+		}
+	}
+
+	return &foreignScanNode{
+		desc:          desc,
+		cols:          cols,
+		resultColumns: resultColumns,
+		reqOrdering:   ReqOrdering(reqOrdering),
+		hardLimit:     params.HardLimit,
+		rowIDSpans:    rowIDSpans,
+	}, nil
+}
+
+// foreignRowIDSpans converts a constraint on the rowid column of a foreign
+// table into inclusive ranges of rowid values. NULL bounds are treated as
+// unbounded, since the rowid values are never NULL.
+func foreignRowIDSpans(
+	c *constraint.Constraint,
+) ([]execinfrapb.ForeignScanSpec_RowIDSpan, error) {
+	bound := func(key constraint.Key, def int64) (int64, error) {
+		if key.IsEmpty() || key.Value(0) == tree.DNull {
+			return def, nil
+		}
+		d, ok := key.Value(0).(*tree.DInt)
+		if !ok {
+			return 0, errors.AssertionFailedf("unexpected rowid constraint value %s", key.Value(0))
+		}
+		return int64(*d), nil
+	}
+	spans := make([]execinfrapb.ForeignScanSpec_RowIDSpan, 0, c.Spans.Count())
+	for i, n := 0, c.Spans.Count(); i < n; i++ {
+		sp := c.Spans.Get(i)
+		start, err := bound(sp.StartKey(), math.MinInt64)
+		if err != nil {
+			return nil, err
+		}
+		end, err := bound(sp.EndKey(), math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+		if !sp.StartKey().IsEmpty() && sp.StartBoundary() == constraint.ExcludeBoundary {
+			if start == math.MaxInt64 {
+				continue
+			}
+			start++
+		}
+		if !sp.EndKey().IsEmpty() && sp.EndBoundary() == constraint.ExcludeBoundary {
+			if end == math.MinInt64 {
+				continue
+			}
+			end--
+		}
+		if start <= end {
+			spans = append(spans, execinfrapb.ForeignScanSpec_RowIDSpan{Start: start, End: end})
+		}
+	}
+	return spans, nil
+}
+
+func (n *foreignScanNode) startExec(params runParams) error {
+	panic("foreignScanNode can't be run in local mode")
+}
+
+func (n *foreignScanNode) Next(params runParams) (bool, error) {
+	panic("foreignScanNode can't be run in local mode")
+}
+
+func (n *foreignScanNode) Values() tree.Datums {
+	panic("foreignScanNode can't be run in local mode")
+}
+
+func (n *foreignScanNode) Close(context.Context) {}
+
+// foreignTableMayHaveSeveralFiles returns whether the files of a foreign table
+// may expand into several files. The glob patterns are only expanded when the
+// scan is planned, so a pattern is assumed to match several files.
+func foreignTableMayHaveSeveralFiles(foreign *descpb.TableDescriptor_ForeignTable) bool {
+	if len(foreign.Files) != 1 {
+		return len(foreign.Files) > 1
+	}
+	uri, err := url.Parse(foreign.Files[0])
+	if err != nil {
+		return false
+	}
+	return cloud.GetPrefixBeforeWildcard(uri.Path) != uri.Path
+}
+
+// createPlanForForeignScan creates a plan of ForeignScan processors for a
+// foreignScanNode. The glob patterns of the files of the table are expanded
+// when the scan is planned, so that the scan reads the files which exist at
+// that time. The files are assigned round-robin to one processor per instance, and the streams are merged according to the rowid
+// ordering of the rows when an ordering is required. Scans with a hard limit
+// are planned as a single processor on the gateway which reads the files in
+// order, so that no more files than necessary are read.
+func (dsp *DistSQLPlanner) createPlanForForeignScan(
+	ctx context.Context, planCtx *PlanningCtx, n *foreignScanNode,
+) (*PhysicalPlan, error) {
+	files, err := ExpandForeignTableFiles(
+		ctx, planCtx.ExtendedEvalCtx.ExecCfg,
+		planCtx.ExtendedEvalCtx.SessionData().User(), n.desc.GetForeign(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sqlInstanceIDs := []base.SQLInstanceID{dsp.gatewaySQLInstanceID}
+	if !planCtx.isLocal && n.hardLimit == 0 && len(files) > 1 {
+		if dsp.codec.ForSystemTenant() {
+			sqlInstanceIDs, err = dsp.allHealthyNodes(ctx, planCtx, planCtx.ExtendedEvalCtx.ExecCfg)
+		} else {
+			sqlInstanceIDs, err = dsp.allInstances(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(sqlInstanceIDs) > len(files) {
+			sqlInstanceIDs = sqlInstanceIDs[:len(files)]
+		}
+	}
+
+	columnIDs := make([]descpb.ColumnID, len(n.cols))
+	typs := make([]*types.T, len(n.cols))
+	for i, col := range n.cols {
+		columnIDs[i] = col.GetID()
+		typs[i] = col.GetType()
+	}
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, len(sqlInstanceIDs))
+	for i, sqlInstanceID := range sqlInstanceIDs {
+		corePlacement[i].SQLInstanceID = sqlInstanceID
+		corePlacement[i].Core.ForeignScan = &execinfrapb.ForeignScanSpec{
+			Table:      *n.desc.TableDesc(),
+			Files:      make(map[int32]string),
+			ColumnIDs:  columnIDs,
+			UserProto:  planCtx.ExtendedEvalCtx.SessionData().User().EncodeProto(),
+			RowIDSpans: n.rowIDSpans,
+		}
+	}
+	for i, file := range files {
+		corePlacement[i%len(corePlacement)].Core.ForeignScan.Files[int32(i)] = file
+	}
+
+	var post execinfrapb.PostProcessSpec
+	if n.hardLimit != 0 {
+		post.Limit = uint64(n.hardLimit)
+	}
+	p := planCtx.NewPhysicalPlan()
+	// Note: we will set a merge ordering below.
+	p.AddNoInputStage(corePlacement, post, typs, execinfrapb.Ordering{})
+	p.PlanToStreamColMap = identityMap(make([]int, len(typs)), len(typs))
+	p.SetMergeOrdering(dsp.convertOrdering(n.reqOrdering, p.PlanToStreamColMap))
+	return p, nil
+}
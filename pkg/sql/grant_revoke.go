@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -41,6 +42,12 @@ import (
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
+	if n.ColumnPrivileges != nil {
+		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnTable)
+		return p.changeColumnPrivileges(
+			ctx, n.ColumnPrivileges, n.Targets, n.Grantees, true /* isGrant */, n.WithGrantOption,
+		)
+	}
 	grantOn, err := p.getGrantOnObject(ctx, n.Targets, sqltelemetry.IncIAMGrantPrivilegesCounter)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get the privileges on the grant targets")
@@ -97,6 +104,12 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error) {
+	if n.ColumnPrivileges != nil {
+		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnTable)
+		return p.changeColumnPrivileges(
+			ctx, n.ColumnPrivileges, n.Targets, n.Grantees, false /* isGrant */, n.GrantOptionFor,
+		)
+	}
 	grantOn, err := p.getGrantOnObject(ctx, n.Targets, sqltelemetry.IncIAMRevokePrivilegesCounter)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get the privileges on the grant targets")
@@ -277,6 +290,12 @@ func (n *changeDescriptorBackedPrivilegesNode) startExec(params runParams) error
 				changed := n.changePrivilege(privileges, n.desiredprivs, grantee)
 				descPrivsChanged = descPrivsChanged || changed
 			}
+			// Revoking privileges on a table also revokes them on all of its
+			// columns.
+			if tableDesc, ok := descriptor.(*tabledesc.Mutable); ok && !n.isGrant {
+				changed := revokeColumnPrivileges(tableDesc, n.desiredprivs, n.grantees, n.withGrantOption)
+				descPrivsChanged = descPrivsChanged || changed
+			}
 
 			if len(sequencePrivilegesNoOp) > 0 {
 				params.p.BufferClientNotice(
@@ -395,6 +414,14 @@ func (n *changeDescriptorBackedPrivilegesNode) startExec(params runParams) error
 						SchemaName:                     d.Name, // FIXME
 					}})
 			}
+		case *funcdesc.Mutable:
+			if err := p.writeFunctionDescChange(
+				ctx,
+				d,
+				fmt.Sprintf("updating privileges for function %d", d.ID),
+			); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -445,6 +472,9 @@ func (p *planner) getGrantOnObject(
 	case targets.Types != nil:
 		incIAMFunc(sqltelemetry.OnType)
 		return privilege.Type, nil
+	case targets.Functions != nil, targets.Procedures != nil:
+		incIAMFunc(sqltelemetry.OnFunction)
+		return privilege.Function, nil
 	case targets.System:
 		incIAMFunc(sqltelemetry.OnSystem)
 		return privilege.Global, nil
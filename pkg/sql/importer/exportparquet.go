@@ -131,7 +131,7 @@ func newParquetExporter(sp execinfrapb.ExportSpec, typs []*types.T) (*parquetExp
 	if err != nil {
 		return nil, err
 	}
-	schema := newParquetSchema(parquetColumns)
+	schema := NewParquetSchema(parquetColumns)
 
 	exporter = &parquetExporter{
 		buf:            buf,
@@ -159,6 +159,23 @@ type ParquetColumn struct {
 	DecodeFn func(interface{}) (tree.Datum, error)
 }
 
+// Name returns the name of the parquet column.
+func (c *ParquetColumn) Name() string {
+	return c.name
+}
+
+// EncodeDatum converts a crdb datum into the native go value that the parquet
+// vendor ingests for this column. NULLs are encoded as nil.
+func (c *ParquetColumn) EncodeDatum(d tree.Datum) (interface{}, error) {
+	if d == tree.DNull {
+		return nil, nil
+	}
+	// If we're encoding a DOidWrapper, then we want to cast the wrapped datum.
+	// Note that we pass in nil as the first argument since we're not interested
+	// in evaluating the evalCtx's placeholders.
+	return c.encodeFn(eval.UnwrapDatum(nil, d))
+}
+
 // newParquetColumns creates a list of parquet columns, given the input relation's column types.
 func newParquetColumns(typs []*types.T, sp execinfrapb.ExportSpec) ([]ParquetColumn, error) {
 	parquetColumns := make([]ParquetColumn, len(typs))
@@ -270,6 +287,47 @@ func NewParquetColumn(typ *types.T, name string, nullable bool) (ParquetColumn,
 			jsonStr := string(x.([]byte))
 			return tree.ParseDJSON(jsonStr)
 		}
+	case types.TSQueryFamily:
+		populateLogicalStringCol(schemaEl)
+		col.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DTSQuery).TSQuery.String()), nil
+		}
+		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTSQuery(string(x.([]byte)))
+		}
+	case types.TSVectorFamily:
+		populateLogicalStringCol(schemaEl)
+		col.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DTSVector).TSVector.String()), nil
+		}
+		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTSVector(string(x.([]byte)))
+		}
+	case types.JsonpathFamily:
+		populateLogicalStringCol(schemaEl)
+		col.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DJsonpath).Jsonpath.String()), nil
+		}
+		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDJsonpath(string(x.([]byte)))
+		}
+	case types.PGVectorFamily:
+		populateLogicalStringCol(schemaEl)
+		col.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DPGVector).T.String()), nil
+		}
+		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDPGVector(string(x.([]byte)))
+		}
+	case types.RangeFamily:
+		populateLogicalStringCol(schemaEl)
+		col.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return []byte(tree.AsStringWithFlags(d, tree.FmtBareStrings)), nil
+		}
+		col.DecodeFn = func(x interface{}) (tree.Datum, error) {
+			r, _, err := tree.ParseDRangeFromString(nil, string(x.([]byte)), typ)
+			return r, err
+		}
 
 	case types.IntFamily:
 		schemaEl.LogicalType = parquet.NewLogicalType()
@@ -657,12 +715,12 @@ func NewParquetColumn(typ *types.T, name string, nullable bool) (ParquetColumn,
 	return col, nil
 }
 
-// newParquetSchema creates the schema for the parquet file,
+// NewParquetSchema creates the schema for the parquet file,
 // see example schema:
 //     https://github.com/fraugster/parquet-go/issues/18#issuecomment-946013210
 // see docs here:
 //     https://pkg.go.dev/github.com/fraugster/parquet-go/parquetschema#SchemaDefinition
-func newParquetSchema(parquetFields []ParquetColumn) *parquetschema.SchemaDefinition {
+func NewParquetSchema(parquetFields []ParquetColumn) *parquetschema.SchemaDefinition {
 	schemaDefinition := new(parquetschema.SchemaDefinition)
 	schemaDefinition.RootColumn = new(parquetschema.ColumnDefinition)
 	schemaDefinition.RootColumn.SchemaElement = parquet.NewSchemaElement()
@@ -771,11 +829,7 @@ func (sp *parquetWriterProcessor) Run(ctx context.Context) {
 						if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
 							return err
 						}
-
-						// If we're encoding a DOidWrapper, then we want to cast the wrapped datum.
-						// Note that we pass in nil as the first argument since we're not interested
-						// in evaluating the evalCtx's placeholders.
-						edNative, err := exporter.parquetColumns[i].encodeFn(eval.UnwrapDatum(nil, ed.Datum))
+						edNative, err := exporter.parquetColumns[i].EncodeDatum(ed.Datum)
 						if err != nil {
 							return err
 						}
@@ -0,0 +1,299 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/errors"
+)
+
+const foreignScanProcessorName = "foreignScanProcessor"
+
+// foreignRowIDFileShift is the number of bits by which the index of a file is
+// shifted in the rowid values synthesized for the rows of a foreign table. The
+// lower bits hold the position of the row in its file.
+const foreignRowIDFileShift = 40
+
+// foreignScanProcessor reads the rows of a foreign table from a subset of its
+// files. The files are read one at a time in the order of their index, with a
+// single conversion worker, so that the rows are produced in the order of the
+// rowid values synthesized from their position. The files are read by a worker
+// goroutine started in Start(), which sends the rows to Next() over a channel.
+// When the spec has rowid spans, the files whose rows are all outside of them
+// are skipped and the rows outside of them are discarded.
+type foreignScanProcessor struct {
+	execinfra.ProcessorBase
+
+	flowCtx *execinfra.FlowCtx
+	spec    execinfrapb.ForeignScanSpec
+	desc    catalog.TableDescriptor
+
+	// colOrds maps each output column to the ordinal of the column among the
+	// visible columns of the table, or -1 for the rowid column.
+	colOrds []int
+	// notNullOrds are the ordinals of the visible columns which are NOT NULL.
+	notNullOrds []int
+	// colTypes are the types of the output columns.
+	colTypes []*types.T
+
+	rowCh  chan rowenc.EncDatumRow
+	cancel context.CancelFunc
+	group  ctxgroup.Group
+}
+
+var (
+	_ execinfra.Processor = &foreignScanProcessor{}
+	_ execinfra.RowSource = &foreignScanProcessor{}
+)
+
+func newForeignScanProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ForeignScanSpec,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	desc := tabledesc.NewBuilder(&spec.Table).BuildImmutableTable()
+	if !desc.IsForeign() {
+		return nil, errors.AssertionFailedf("%q is not a foreign table", desc.GetName())
+	}
+	fs := &foreignScanProcessor{
+		flowCtx: flowCtx,
+		spec:    spec,
+		desc:    desc,
+		colOrds: make([]int, len(spec.ColumnIDs)),
+		rowCh:   make(chan rowenc.EncDatumRow),
+	}
+
+	visibleCols := desc.VisibleColumns()
+	var visibleOrds catalog.TableColMap
+	for i, col := range visibleCols {
+		visibleOrds.Set(col.GetID(), i)
+		if !col.IsNullable() {
+			fs.notNullOrds = append(fs.notNullOrds, i)
+		}
+	}
+	rowIDColID := desc.GetPrimaryIndex().GetKeyColumnID(0)
+	fs.colTypes = make([]*types.T, len(spec.ColumnIDs))
+	for i, id := range spec.ColumnIDs {
+		col, err := desc.FindColumnWithID(id)
+		if err != nil {
+			return nil, err
+		}
+		fs.colTypes[i] = col.GetType()
+		if id == rowIDColID {
+			fs.colOrds[i] = -1
+			continue
+		}
+		ord, ok := visibleOrds.Get(id)
+		if !ok {
+			return nil, errors.AssertionFailedf("column %q is not readable from the files of %q",
+				col.GetName(), desc.GetName())
+		}
+		fs.colOrds[i] = ord
+	}
+
+	if err := fs.Init(fs, post, fs.colTypes, flowCtx, processorID, output, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				fs.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Start is part of the RowSource interface.
+func (fs *foreignScanProcessor) Start(ctx context.Context) {
+	ctx = fs.StartInternal(ctx, foreignScanProcessorName)
+	ctx, fs.cancel = context.WithCancel(ctx)
+	fs.group = ctxgroup.WithContext(ctx)
+	fs.group.GoCtx(func(ctx context.Context) error {
+		defer close(fs.rowCh)
+		return fs.readFiles(ctx)
+	})
+}
+
+// readFiles reads the files assigned to the processor in the order of their
+// index and sends their rows to rowCh.
+func (fs *foreignScanProcessor) readFiles(ctx context.Context) error {
+	evalCtx := fs.flowCtx.NewEvalCtx()
+	semaCtx := tree.MakeSemaContext()
+	format := fs.desc.GetForeign().Format
+	conv, err := makeForeignTableReader(
+		&semaCtx, evalCtx, fs.desc, format, fs.flowCtx.Cfg.DB, fs.emitRow,
+	)
+	if err != nil {
+		return err
+	}
+
+	fileIdxs := make([]int32, 0, len(fs.spec.Files))
+	for idx := range fs.spec.Files {
+		fileIdxs = append(fileIdxs, idx)
+	}
+	sort.Slice(fileIdxs, func(i, j int) bool { return fileIdxs[i] < fileIdxs[j] })
+	for _, idx := range fileIdxs {
+		start := int64(idx) << foreignRowIDFileShift
+		if !fs.inRowIDSpans(start, start|(1<<foreignRowIDFileShift-1)) {
+			continue
+		}
+		dataFiles := map[int32]string{idx: fs.spec.Files[idx]}
+		if err := conv.readFiles(
+			ctx, dataFiles, nil /* resumePos */, format, fs.flowCtx.Cfg.ExternalStorage, fs.spec.User(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitRow is called by the reader with the datums of the visible columns of
+// each row of the files.
+func (fs *foreignScanProcessor) emitRow(
+	ctx context.Context, source int32, rowNum int64, datums tree.Datums,
+) error {
+	rowID := int64(source)<<foreignRowIDFileShift | rowNum
+	if !fs.inRowIDSpans(rowID, rowID) {
+		return nil
+	}
+	for _, ord := range fs.notNullOrds {
+		if datums[ord] == tree.DNull {
+			err := sqlerrors.NewNonNullViolationError(fs.desc.VisibleColumns()[ord].GetName())
+			return newImportRowError(err, fmt.Sprintf("%v", datums), rowNum)
+		}
+	}
+	row := make(rowenc.EncDatumRow, len(fs.colOrds))
+	for i, ord := range fs.colOrds {
+		var d tree.Datum
+		if ord == -1 {
+			d = tree.NewDInt(tree.DInt(rowID))
+		} else {
+			d = datums[ord]
+		}
+		row[i] = rowenc.DatumToEncDatum(fs.colTypes[i], d)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case fs.rowCh <- row:
+		return nil
+	}
+}
+
+// inRowIDSpans returns whether the range [start, end] of rowid values
+// intersects the rowid spans of the spec, if any.
+func (fs *foreignScanProcessor) inRowIDSpans(start, end int64) bool {
+	if len(fs.spec.RowIDSpans) == 0 {
+		return true
+	}
+	for _, sp := range fs.spec.RowIDSpans {
+		if sp.Start <= end && start <= sp.End {
+			return true
+		}
+	}
+	return false
+}
+
+// Next is part of the RowSource interface.
+func (fs *foreignScanProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for fs.State == execinfra.StateRunning {
+		row, ok := <-fs.rowCh
+		if !ok {
+			fs.MoveToDraining(fs.group.Wait())
+			break
+		}
+		if outRow := fs.ProcessRowHelper(row); outRow != nil {
+			return outRow, nil
+		}
+	}
+	return nil, fs.DrainHelper()
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (fs *foreignScanProcessor) ConsumerClosed() {
+	fs.close()
+}
+
+func (fs *foreignScanProcessor) close() {
+	if fs.InternalClose() {
+		if fs.cancel != nil {
+			// Stop the worker goroutine, which may be blocked sending a row.
+			fs.cancel()
+			_ = fs.group.Wait()
+		}
+	}
+}
+
+// makeForeignTableReader creates the reader of the given format for the files
+// of a foreign table. The rows are passed to emitRow instead of being
+// converted into KVs.
+func makeForeignTableReader(
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	desc catalog.TableDescriptor,
+	format roachpb.IOFileFormat,
+	db *kv.DB,
+	emitRow func(ctx context.Context, source int32, rowNum int64, datums tree.Datums) error,
+) (inputConverter, error) {
+	var conv inputConverter
+	var importCtx *parallelImportContext
+	switch format.Format {
+	case roachpb.IOFileFormat_CSV:
+		r := newCSVInputReader(
+			semaCtx, nil /* kvCh */, format.Csv, 0 /* walltime */, 1, /* parallelism */
+			desc, nil /* targetCols */, evalCtx, nil /* seqChunkProvider */, db)
+		conv, importCtx = r, r.importCtx
+	case roachpb.IOFileFormat_MysqlOutfile:
+		r, err := newMysqloutfileReader(
+			semaCtx, format.MysqlOut, nil /* kvCh */, 0 /* walltime */, 1, /* parallelism */
+			desc, nil /* targetCols */, evalCtx, db)
+		if err != nil {
+			return nil, err
+		}
+		conv, importCtx = r, r.importCtx
+	case roachpb.IOFileFormat_Avro:
+		r, err := newAvroInputReader(
+			semaCtx, nil /* kvCh */, desc, format.Avro, 0 /* walltime */, 1, /* parallelism */
+			evalCtx, db)
+		if err != nil {
+			return nil, err
+		}
+		conv, importCtx = r, r.importContext
+	default:
+		return nil, errors.AssertionFailedf("unsupported foreign table format %s", format.Format)
+	}
+	importCtx.emitRow = emitRow
+	return conv, nil
+}
+
+func init() {
+	rowexec.NewForeignScanProcessor = newForeignScanProcessor
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// File formats supported for foreign tables.
+var allowedForeignTableFormats = map[string]struct{}{
+	"CSV":       {},
+	"AVRO":      {},
+	"DELIMITED": {},
+}
+
+// Options of IMPORT which only apply to the ingestion of the data and therefore
+// cannot be used with foreign tables.
+var foreignTableDisallowedOptions = makeStringSet(
+	importOptionSSTSize, importOptionOversample, importOptionSaveRejected,
+	importOptionDetached, importOptionSkipFKs,
+)
+
+// prepareForeignTable validates the file format and the options of a CREATE
+// FOREIGN TABLE statement and checks that the glob patterns of its files match
+// some files. The options are interpreted as the options of IMPORT INTO for the
+// same format, so only the formats which IMPORT INTO can read row by row are
+// supported; in particular, there is no PARQUET reader.
+func prepareForeignTable(
+	ctx context.Context, p sql.PlanHookState, n *tree.CreateForeignTable,
+) (*descpb.TableDescriptor_ForeignTable, error) {
+	if _, ok := allowedForeignTableFormats[n.FileFormat]; !ok {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s file format is not supported by foreign tables", n.FileFormat),
+			"Foreign tables can read CSV, DELIMITED and AVRO files.")
+	}
+
+	filesFn, err := p.TypeAsStringArray(ctx, n.Files, "CREATE FOREIGN TABLE")
+	if err != nil {
+		return nil, err
+	}
+	optsFn, err := p.TypeAsStringOpts(ctx, n.Options, importOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	filenamePatterns, err := filesFn()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := optsFn()
+	if err != nil {
+		return nil, err
+	}
+	for opt := range opts {
+		if _, ok := foreignTableDisallowedOptions[opt]; ok {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"option %q is not supported by foreign tables", opt)
+		}
+	}
+
+	if err := checkImportURIsAccess(ctx, p, filenamePatterns, "CREATE FOREIGN TABLE"); err != nil {
+		return nil, err
+	}
+	for _, file := range filenamePatterns {
+		if _, err := parseWorkloadConfig(file); err == nil {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"workload URIs are not supported by foreign tables")
+		}
+	}
+
+	var format roachpb.IOFileFormat
+	if err := parseImportFormatOptions(ctx, p, n.FileFormat, opts, &format); err != nil {
+		return nil, err
+	}
+	// The patterns are stored as they are and expanded by each scan, but
+	// expanding them now reports the patterns which match no files.
+	_, disableGlob := opts[importOptionDisableGlobMatch]
+	if _, err := expandFilePatterns(ctx, p, filenamePatterns, disableGlob); err != nil {
+		return nil, err
+	}
+
+	return &descpb.TableDescriptor_ForeignTable{
+		Format:  format,
+		Files:   filenamePatterns,
+		Options: opts,
+	}, nil
+}
+
+// expandForeignTableFiles expands the glob patterns of the files of a foreign
+// table into the list of files which match them at the time of the call.
+func expandForeignTableFiles(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	foreign *descpb.TableDescriptor_ForeignTable,
+) ([]string, error) {
+	_, disableGlob := foreign.Options[importOptionDisableGlobMatch]
+	return expandFilePatternsAsUser(ctx, execCfg, user, foreign.Files, disableGlob)
+}
+
+func init() {
+	sql.PrepareForeignTable = prepareForeignTable
+	sql.ExpandForeignTableFiles = expandForeignTableFiles
+}
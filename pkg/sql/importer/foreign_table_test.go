@@ -0,0 +1,142 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	baseDir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	// Write three CSV files of 100 rows each.
+	const numFiles, rowsPerFile = 3, 100
+	for f := 0; f < numFiles; f++ {
+		var sb strings.Builder
+		for i := 0; i < rowsPerFile; i++ {
+			k := f*rowsPerFile + i
+			fmt.Fprintf(&sb, "%d,v%d\n", k, k)
+		}
+		name := filepath.Join(baseDir, fmt.Sprintf("data-%d.csv", f))
+		require.NoError(t, os.WriteFile(name, []byte(sb.String()), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "nulls.csv"), []byte("1,\n2,v\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "secret-0.csv"), []byte("x,y\n"), 0644))
+
+	const nodes = 3
+	tc := serverutils.StartNewTestCluster(t, nodes, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{ExternalIODir: baseDir},
+	})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	sqlDB.Exec(t, `CREATE FOREIGN TABLE f (k INT NOT NULL, v STRING) CSV DATA ('nodelocal://1/data-*.csv')`)
+
+	t.Run("scan", func(t *testing.T) {
+		sqlDB.CheckQueryResults(t, `SELECT count(*), sum(k), min(v) FROM f`,
+			[][]string{{"300", "44850", "v0"}})
+		sqlDB.CheckQueryResults(t, `SELECT k FROM f WHERE v = 'v142'`, [][]string{{"142"}})
+		sqlDB.CheckQueryResults(t, `SELECT k FROM f ORDER BY rowid LIMIT 2`, [][]string{{"0"}, {"1"}})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM f AS a JOIN f AS b ON a.k = b.k`,
+			[][]string{{"300"}})
+	})
+
+	t.Run("constrained", func(t *testing.T) {
+		// The rowid of the nth row of file f, counting from 1, is f<<40 | n.
+		sqlDB.CheckQueryResults(t, `SELECT k FROM f WHERE rowid = 2199023255557`, [][]string{{"204"}})
+		sqlDB.CheckQueryResults(t, `SELECT count(*), max(k) FROM f WHERE rowid < 1099511627776`,
+			[][]string{{"100", "99"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT k FROM f WHERE rowid IN (3, 1099511627780, 4398046511104) ORDER BY k`,
+			[][]string{{"2"}, {"103"}})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM f WHERE rowid > 2199023255652`,
+			[][]string{{"0"}})
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		// The patterns are expanded by each scan, so files added or removed
+		// after the table was created are taken into account.
+		name := filepath.Join(baseDir, "data-3.csv")
+		require.NoError(t, os.WriteFile(name, []byte("1000,v1000\n"), 0644))
+		sqlDB.CheckQueryResults(t, `SELECT count(*), max(k) FROM f`, [][]string{{"301", "1000"}})
+		require.NoError(t, os.Remove(name))
+		sqlDB.CheckQueryResults(t, `SELECT count(*), max(k) FROM f`, [][]string{{"300", "299"}})
+	})
+
+	t.Run("show", func(t *testing.T) {
+		sqlDB.CheckQueryResults(t,
+			`SELECT table_type FROM information_schema.tables WHERE table_name = 'f'`,
+			[][]string{{"FOREIGN"}})
+		var create string
+		sqlDB.QueryRow(t, `SELECT create_statement FROM [SHOW CREATE TABLE f]`).Scan(&create)
+		require.Contains(t, create, "CREATE FOREIGN TABLE public.f")
+		require.Contains(t, create, "nodelocal://1/data-*.csv")
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE FOREIGN TABLE s (k INT) CSV DATA ('nodelocal://1/secret-*.csv?AWS_SESSION_TOKEN=secrets')`)
+		var create string
+		sqlDB.QueryRow(t, `SELECT create_statement FROM [SHOW CREATE TABLE s]`).Scan(&create)
+		require.Contains(t, create, "AWS_SESSION_TOKEN=redacted")
+		require.NotContains(t, create, "secrets")
+		_, err := sqlDB.DB.ExecContext(ctx, `SELECT * FROM s`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "AWS_SESSION_TOKEN=redacted")
+		require.NotContains(t, err.Error(), "secrets")
+		sqlDB.Exec(t, `DROP FOREIGN TABLE s`)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `cannot mutate foreign table "f"`, `INSERT INTO f VALUES (1, 'a')`)
+		sqlDB.ExpectErr(t, `cannot mutate foreign table "f"`, `DELETE FROM f WHERE true`)
+		sqlDB.ExpectErr(t, `cannot create index on foreign table "f"`, `CREATE INDEX ON f (k)`)
+		sqlDB.ExpectErr(t, `"f" is a foreign table`, `DROP TABLE f`)
+	})
+
+	t.Run("not-null", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE FOREIGN TABLE g (k INT, v STRING NOT NULL) CSV DATA ('nodelocal://1/nulls.csv') WITH nullif = ''`)
+		sqlDB.ExpectErr(t, `null value in column "v" violates not-null constraint`, `SELECT * FROM g`)
+		sqlDB.Exec(t, `DROP FOREIGN TABLE g`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `no files matched`,
+			`CREATE FOREIGN TABLE h (k INT) CSV DATA ('nodelocal://1/missing-*.csv')`)
+		sqlDB.ExpectErr(t, `option "sstsize" is not supported by foreign tables`,
+			`CREATE FOREIGN TABLE h (k INT) CSV DATA ('nodelocal://1/data-0.csv') WITH sstsize = '1MB'`)
+		sqlDB.ExpectErr(t, `PGDUMP file format is not supported by foreign tables`,
+			`CREATE FOREIGN TABLE h (k INT) PGDUMP DATA ('nodelocal://1/data-0.csv')`)
+		sqlDB.ExpectErr(t, `PARQUET file format is not supported by foreign tables`,
+			`CREATE FOREIGN TABLE h (k INT) PARQUET DATA ('nodelocal://1/data-0.csv')`)
+		sqlDB.ExpectErr(t, `can only be declared NULL or NOT NULL`,
+			`CREATE FOREIGN TABLE h (k INT PRIMARY KEY) CSV DATA ('nodelocal://1/data-0.csv')`)
+	})
+
+	sqlDB.Exec(t, `DROP FOREIGN TABLE f`)
+}
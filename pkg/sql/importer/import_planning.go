@@ -325,6 +325,343 @@ func resolveUDTsUsedByImportInto(
 	return typeDescs, err
 }
 
+// checkImportURIsAccess checks that the user is allowed to read from the given
+// URIs. Certain ExternalStorage URIs require super-user access.
+func checkImportURIsAccess(
+	ctx context.Context, p sql.PlanHookState, uris []string, op string,
+) error {
+	if p.ExecCfg().ExternalIODirConfig.EnableNonAdminImplicitAndArbitraryOutbound {
+		return nil
+	}
+	for _, file := range uris {
+		conf, err := cloud.ExternalStorageConfFromURI(file, p.User())
+		if err != nil {
+			// If it is a workload URI, it won't parse as a storage config, but it
+			// also doesn't have any auth concerns so just continue.
+			if _, workloadErr := parseWorkloadConfig(file); workloadErr == nil {
+				continue
+			}
+			return err
+		}
+		if !conf.AccessIsWithExplicitAuth() {
+			err := p.RequireAdminRole(ctx,
+				fmt.Sprintf("%s from the specified %s URI", op, conf.Provider.String()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandFilePatterns expands the glob patterns in the given file URIs into the
+// list of matching files, unless disableGlob is set.
+func expandFilePatterns(
+	ctx context.Context, p sql.PlanHookState, filenamePatterns []string, disableGlob bool,
+) ([]string, error) {
+	return expandFilePatternsAsUser(ctx, p.ExecCfg(), p.User(), filenamePatterns, disableGlob)
+}
+
+// expandFilePatternsAsUser is like expandFilePatterns, but lists the files
+// with the identity of the given user outside of a planner.
+func expandFilePatternsAsUser(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	filenamePatterns []string,
+	disableGlob bool,
+) ([]string, error) {
+	if disableGlob {
+		return filenamePatterns, nil
+	}
+	var files []string
+	for _, file := range filenamePatterns {
+		uri, err := url.Parse(file)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(uri.Scheme, "workload") || strings.HasPrefix(uri.Scheme, "http") {
+			files = append(files, file)
+			continue
+		}
+		prefix := cloud.GetPrefixBeforeWildcard(uri.Path)
+		if len(prefix) < len(uri.Path) {
+			pattern := uri.Path[len(prefix):]
+			uri.Path = prefix
+			s, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri.String(), user)
+			if err != nil {
+				return nil, err
+			}
+			var expandedFiles []string
+			if err := s.List(ctx, "", "", func(s string) error {
+				ok, err := path.Match(pattern, s)
+				if ok {
+					uri.Path = prefix + s
+					expandedFiles = append(expandedFiles, uri.String())
+				}
+				return err
+			}); err != nil {
+				return nil, err
+			}
+			if len(expandedFiles) < 1 {
+				clean, err := cloud.SanitizeExternalStorageURI(file, nil /* extraParams */)
+				if err != nil {
+					return nil, err
+				}
+				return nil, errors.Errorf(`no files matched %q in prefix %q in uri provided: %q`, pattern, prefix, clean)
+			}
+			files = append(files, expandedFiles...)
+		} else {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// parseImportFormatOptions validates the options of the given file format and
+// sets the corresponding fields of format.
+func parseImportFormatOptions(
+	ctx context.Context,
+	p sql.PlanHookState,
+	fileFormat string,
+	opts map[string]string,
+	format *roachpb.IOFileFormat,
+) error {
+	switch fileFormat {
+	case "CSV":
+		if err := validateFormatOptions(fileFormat, opts, csvAllowedOptions); err != nil {
+			return err
+		}
+		format.Format = roachpb.IOFileFormat_CSV
+		// Set the default CSV separator for the cases when it is not overwritten.
+		format.Csv.Comma = ','
+		if override, ok := opts[csvDelimiter]; ok {
+			comma, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrap(err, pgcode.Syntax, "invalid comma value")
+			}
+			format.Csv.Comma = comma
+		}
+
+		if override, ok := opts[csvComment]; ok {
+			comment, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrap(err, pgcode.Syntax, "invalid comment value")
+			}
+			format.Csv.Comment = comment
+		}
+
+		if override, ok := opts[csvNullIf]; ok {
+			format.Csv.NullEncoding = &override
+		}
+
+		if override, ok := opts[csvSkip]; ok {
+			skip, err := strconv.Atoi(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %s value", csvSkip)
+			}
+			if skip < 0 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be >= 0", csvSkip)
+			}
+			format.Csv.Skip = uint32(skip)
+		}
+		if _, ok := opts[csvStrictQuotes]; ok {
+			format.Csv.StrictQuotes = true
+		}
+		if _, ok := opts[importOptionSaveRejected]; ok {
+			format.SaveRejected = true
+		}
+		if override, ok := opts[csvRowLimit]; ok {
+			rowLimit, err := strconv.Atoi(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+			}
+			if rowLimit <= 0 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+			}
+			format.Csv.RowLimit = int64(rowLimit)
+		}
+	case "DELIMITED":
+		if err := validateFormatOptions(fileFormat, opts, mysqlOutAllowedOptions); err != nil {
+			return err
+		}
+		format.Format = roachpb.IOFileFormat_MysqlOutfile
+		format.MysqlOut = roachpb.MySQLOutfileOptions{
+			RowSeparator:   '\n',
+			FieldSeparator: '\t',
+		}
+		if override, ok := opts[mysqlOutfileRowSep]; ok {
+			c, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax,
+					"invalid %q value", mysqlOutfileRowSep)
+			}
+			format.MysqlOut.RowSeparator = c
+		}
+
+		if override, ok := opts[mysqlOutfileFieldSep]; ok {
+			c, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlOutfileFieldSep)
+			}
+			format.MysqlOut.FieldSeparator = c
+		}
+
+		if override, ok := opts[mysqlOutfileEnclose]; ok {
+			c, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlOutfileRowSep)
+			}
+			format.MysqlOut.Enclose = roachpb.MySQLOutfileOptions_Always
+			format.MysqlOut.Encloser = c
+		}
+
+		if override, ok := opts[mysqlOutfileEscape]; ok {
+			c, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlOutfileRowSep)
+			}
+			format.MysqlOut.HasEscape = true
+			format.MysqlOut.Escape = c
+		}
+		if override, ok := opts[csvSkip]; ok {
+			skip, err := strconv.Atoi(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %s value", csvSkip)
+			}
+			if skip < 0 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be >= 0", csvSkip)
+			}
+			format.MysqlOut.Skip = uint32(skip)
+		}
+		if override, ok := opts[csvNullIf]; ok {
+			format.MysqlOut.NullEncoding = &override
+		}
+		if _, ok := opts[importOptionSaveRejected]; ok {
+			format.SaveRejected = true
+		}
+		if override, ok := opts[csvRowLimit]; ok {
+			rowLimit, err := strconv.Atoi(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+			}
+			if rowLimit <= 0 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+			}
+			format.MysqlOut.RowLimit = int64(rowLimit)
+		}
+	case "MYSQLDUMP":
+		if err := validateFormatOptions(fileFormat, opts, mysqlDumpAllowedOptions); err != nil {
+			return err
+		}
+		format.Format = roachpb.IOFileFormat_Mysqldump
+		if override, ok := opts[csvRowLimit]; ok {
+			rowLimit, err := strconv.Atoi(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+			}
+			if rowLimit <= 0 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+			}
+			format.MysqlDump.RowLimit = int64(rowLimit)
+		}
+	case "PGCOPY":
+		if err := validateFormatOptions(fileFormat, opts, pgCopyAllowedOptions); err != nil {
+			return err
+		}
+		format.Format = roachpb.IOFileFormat_PgCopy
+		format.PgCopy = roachpb.PgCopyOptions{
+			Delimiter: '\t',
+			Null:      `\N`,
+		}
+		if override, ok := opts[pgCopyDelimiter]; ok {
+			c, err := util.GetSingleRune(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", pgCopyDelimiter)
+			}
+			format.PgCopy.Delimiter = c
+		}
+		if override, ok := opts[pgCopyNull]; ok {
+			format.PgCopy.Null = override
+		}
+		maxRowSize := int32(defaultScanBuffer)
+		if override, ok := opts[optMaxRowSize]; ok {
+			sz, err := humanizeutil.ParseBytes(override)
+			if err != nil {
+				return err
+			}
+			if sz < 1 || sz > math.MaxInt32 {
+				return errors.Errorf("%d out of range: %d", maxRowSize, sz)
+			}
+			maxRowSize = int32(sz)
+		}
+		format.PgCopy.MaxRowSize = maxRowSize
+	case "PGDUMP":
+		if err := validateFormatOptions(fileFormat, opts, pgDumpAllowedOptions); err != nil {
+			return err
+		}
+		format.Format = roachpb.IOFileFormat_PgDump
+		maxRowSize := int32(defaultScanBuffer)
+		if override, ok := opts[optMaxRowSize]; ok {
+			sz, err := humanizeutil.ParseBytes(override)
+			if err != nil {
+				return err
+			}
+			if sz < 1 || sz > math.MaxInt32 {
+				return errors.Errorf("%d out of range: %d", maxRowSize, sz)
+			}
+			maxRowSize = int32(sz)
+		}
+		format.PgDump.MaxRowSize = maxRowSize
+		if _, ok := opts[pgDumpIgnoreAllUnsupported]; ok {
+			format.PgDump.IgnoreUnsupported = true
+		}
+
+		if dest, ok := opts[pgDumpIgnoreShuntFileDest]; ok {
+			if !format.PgDump.IgnoreUnsupported {
+				return errors.New("cannot log unsupported PGDUMP stmts without `ignore_unsupported_statements` option")
+			}
+			format.PgDump.IgnoreUnsupportedLog = dest
+		}
+
+		if override, ok := opts[csvRowLimit]; ok {
+			rowLimit, err := strconv.Atoi(override)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+			}
+			if rowLimit <= 0 {
+				return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+			}
+			format.PgDump.RowLimit = int64(rowLimit)
+		}
+	case "AVRO":
+		if err := validateFormatOptions(fileFormat, opts, avroAllowedOptions); err != nil {
+			return err
+		}
+		err := parseAvroOptions(ctx, opts, p, format)
+		if err != nil {
+			return err
+		}
+	default:
+		return unimplemented.Newf("import.format", "unsupported import format: %q", fileFormat)
+	}
+
+	if override, ok := opts[importOptionDecompress]; ok {
+		found := false
+		for name, value := range roachpb.IOFileFormat_Compression_value {
+			if strings.EqualFold(name, override) {
+				format.Compression = roachpb.IOFileFormat_Compression(value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return unimplemented.Newf("import.compression", "unsupported compression value: %q", override)
+		}
+	}
+	return nil
+}
+
 // importPlanHook implements sql.PlanHookFn.
 func importPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -385,69 +722,13 @@ func importPlanHook(
 			return err
 		}
 
-		// Certain ExternalStorage URIs require super-user access. Check all the
-		// URIs passed to the IMPORT command.
-		if !p.ExecCfg().ExternalIODirConfig.EnableNonAdminImplicitAndArbitraryOutbound {
-			for _, file := range filenamePatterns {
-				conf, err := cloud.ExternalStorageConfFromURI(file, p.User())
-				if err != nil {
-					// If it is a workload URI, it won't parse as a storage config, but it
-					// also doesn't have any auth concerns so just continue.
-					if _, workloadErr := parseWorkloadConfig(file); workloadErr == nil {
-						continue
-					}
-					return err
-				}
-				if !conf.AccessIsWithExplicitAuth() {
-					err := p.RequireAdminRole(ctx,
-						fmt.Sprintf("IMPORT from the specified %s URI", conf.Provider.String()))
-					if err != nil {
-						return err
-					}
-				}
-			}
+		if err := checkImportURIsAccess(ctx, p, filenamePatterns, "IMPORT"); err != nil {
+			return err
 		}
-
-		var files []string
-		if _, ok := opts[importOptionDisableGlobMatch]; ok {
-			files = filenamePatterns
-		} else {
-			for _, file := range filenamePatterns {
-				uri, err := url.Parse(file)
-				if err != nil {
-					return err
-				}
-				if strings.Contains(uri.Scheme, "workload") || strings.HasPrefix(uri.Scheme, "http") {
-					files = append(files, file)
-					continue
-				}
-				prefix := cloud.GetPrefixBeforeWildcard(uri.Path)
-				if len(prefix) < len(uri.Path) {
-					pattern := uri.Path[len(prefix):]
-					uri.Path = prefix
-					s, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, uri.String(), p.User())
-					if err != nil {
-						return err
-					}
-					var expandedFiles []string
-					if err := s.List(ctx, "", "", func(s string) error {
-						ok, err := path.Match(pattern, s)
-						if ok {
-							uri.Path = prefix + s
-							expandedFiles = append(expandedFiles, uri.String())
-						}
-						return err
-					}); err != nil {
-						return err
-					}
-					if len(expandedFiles) < 1 {
-						return errors.Errorf(`no files matched %q in prefix %q in uri provided: %q`, pattern, prefix, file)
-					}
-					files = append(files, expandedFiles...)
-				} else {
-					files = append(files, file)
-				}
-			}
+		_, disableGlob := opts[importOptionDisableGlobMatch]
+		files, err := expandFilePatterns(ctx, p, filenamePatterns, disableGlob)
+		if err != nil {
+			return err
 		}
 
 		// Typically the SQL grammar means it is only possible to specifying exactly
@@ -515,223 +796,8 @@ func importPlanHook(
 		}
 
 		format := roachpb.IOFileFormat{}
-		switch importStmt.FileFormat {
-		case "CSV":
-			if err = validateFormatOptions(importStmt.FileFormat, opts, csvAllowedOptions); err != nil {
-				return err
-			}
-			format.Format = roachpb.IOFileFormat_CSV
-			// Set the default CSV separator for the cases when it is not overwritten.
-			format.Csv.Comma = ','
-			if override, ok := opts[csvDelimiter]; ok {
-				comma, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrap(err, pgcode.Syntax, "invalid comma value")
-				}
-				format.Csv.Comma = comma
-			}
-
-			if override, ok := opts[csvComment]; ok {
-				comment, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrap(err, pgcode.Syntax, "invalid comment value")
-				}
-				format.Csv.Comment = comment
-			}
-
-			if override, ok := opts[csvNullIf]; ok {
-				format.Csv.NullEncoding = &override
-			}
-
-			if override, ok := opts[csvSkip]; ok {
-				skip, err := strconv.Atoi(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %s value", csvSkip)
-				}
-				if skip < 0 {
-					return pgerror.Newf(pgcode.Syntax, "%s must be >= 0", csvSkip)
-				}
-				format.Csv.Skip = uint32(skip)
-			}
-			if _, ok := opts[csvStrictQuotes]; ok {
-				format.Csv.StrictQuotes = true
-			}
-			if _, ok := opts[importOptionSaveRejected]; ok {
-				format.SaveRejected = true
-			}
-			if override, ok := opts[csvRowLimit]; ok {
-				rowLimit, err := strconv.Atoi(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
-				}
-				if rowLimit <= 0 {
-					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
-				}
-				format.Csv.RowLimit = int64(rowLimit)
-			}
-		case "DELIMITED":
-			if err = validateFormatOptions(importStmt.FileFormat, opts, mysqlOutAllowedOptions); err != nil {
-				return err
-			}
-			format.Format = roachpb.IOFileFormat_MysqlOutfile
-			format.MysqlOut = roachpb.MySQLOutfileOptions{
-				RowSeparator:   '\n',
-				FieldSeparator: '\t',
-			}
-			if override, ok := opts[mysqlOutfileRowSep]; ok {
-				c, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax,
-						"invalid %q value", mysqlOutfileRowSep)
-				}
-				format.MysqlOut.RowSeparator = c
-			}
-
-			if override, ok := opts[mysqlOutfileFieldSep]; ok {
-				c, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlOutfileFieldSep)
-				}
-				format.MysqlOut.FieldSeparator = c
-			}
-
-			if override, ok := opts[mysqlOutfileEnclose]; ok {
-				c, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlOutfileRowSep)
-				}
-				format.MysqlOut.Enclose = roachpb.MySQLOutfileOptions_Always
-				format.MysqlOut.Encloser = c
-			}
-
-			if override, ok := opts[mysqlOutfileEscape]; ok {
-				c, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlOutfileRowSep)
-				}
-				format.MysqlOut.HasEscape = true
-				format.MysqlOut.Escape = c
-			}
-			if override, ok := opts[csvSkip]; ok {
-				skip, err := strconv.Atoi(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %s value", csvSkip)
-				}
-				if skip < 0 {
-					return pgerror.Newf(pgcode.Syntax, "%s must be >= 0", csvSkip)
-				}
-				format.MysqlOut.Skip = uint32(skip)
-			}
-			if override, ok := opts[csvNullIf]; ok {
-				format.MysqlOut.NullEncoding = &override
-			}
-			if _, ok := opts[importOptionSaveRejected]; ok {
-				format.SaveRejected = true
-			}
-			if override, ok := opts[csvRowLimit]; ok {
-				rowLimit, err := strconv.Atoi(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
-				}
-				if rowLimit <= 0 {
-					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
-				}
-				format.MysqlOut.RowLimit = int64(rowLimit)
-			}
-		case "MYSQLDUMP":
-			if err = validateFormatOptions(importStmt.FileFormat, opts, mysqlDumpAllowedOptions); err != nil {
-				return err
-			}
-			format.Format = roachpb.IOFileFormat_Mysqldump
-			if override, ok := opts[csvRowLimit]; ok {
-				rowLimit, err := strconv.Atoi(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
-				}
-				if rowLimit <= 0 {
-					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
-				}
-				format.MysqlDump.RowLimit = int64(rowLimit)
-			}
-		case "PGCOPY":
-			if err = validateFormatOptions(importStmt.FileFormat, opts, pgCopyAllowedOptions); err != nil {
-				return err
-			}
-			format.Format = roachpb.IOFileFormat_PgCopy
-			format.PgCopy = roachpb.PgCopyOptions{
-				Delimiter: '\t',
-				Null:      `\N`,
-			}
-			if override, ok := opts[pgCopyDelimiter]; ok {
-				c, err := util.GetSingleRune(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", pgCopyDelimiter)
-				}
-				format.PgCopy.Delimiter = c
-			}
-			if override, ok := opts[pgCopyNull]; ok {
-				format.PgCopy.Null = override
-			}
-			maxRowSize := int32(defaultScanBuffer)
-			if override, ok := opts[optMaxRowSize]; ok {
-				sz, err := humanizeutil.ParseBytes(override)
-				if err != nil {
-					return err
-				}
-				if sz < 1 || sz > math.MaxInt32 {
-					return errors.Errorf("%d out of range: %d", maxRowSize, sz)
-				}
-				maxRowSize = int32(sz)
-			}
-			format.PgCopy.MaxRowSize = maxRowSize
-		case "PGDUMP":
-			if err = validateFormatOptions(importStmt.FileFormat, opts, pgDumpAllowedOptions); err != nil {
-				return err
-			}
-			format.Format = roachpb.IOFileFormat_PgDump
-			maxRowSize := int32(defaultScanBuffer)
-			if override, ok := opts[optMaxRowSize]; ok {
-				sz, err := humanizeutil.ParseBytes(override)
-				if err != nil {
-					return err
-				}
-				if sz < 1 || sz > math.MaxInt32 {
-					return errors.Errorf("%d out of range: %d", maxRowSize, sz)
-				}
-				maxRowSize = int32(sz)
-			}
-			format.PgDump.MaxRowSize = maxRowSize
-			if _, ok := opts[pgDumpIgnoreAllUnsupported]; ok {
-				format.PgDump.IgnoreUnsupported = true
-			}
-
-			if dest, ok := opts[pgDumpIgnoreShuntFileDest]; ok {
-				if !format.PgDump.IgnoreUnsupported {
-					return errors.New("cannot log unsupported PGDUMP stmts without `ignore_unsupported_statements` option")
-				}
-				format.PgDump.IgnoreUnsupportedLog = dest
-			}
-
-			if override, ok := opts[csvRowLimit]; ok {
-				rowLimit, err := strconv.Atoi(override)
-				if err != nil {
-					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
-				}
-				if rowLimit <= 0 {
-					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
-				}
-				format.PgDump.RowLimit = int64(rowLimit)
-			}
-		case "AVRO":
-			if err = validateFormatOptions(importStmt.FileFormat, opts, avroAllowedOptions); err != nil {
-				return err
-			}
-			err := parseAvroOptions(ctx, opts, p, &format)
-			if err != nil {
-				return err
-			}
-		default:
-			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
+		if err := parseImportFormatOptions(ctx, p, importStmt.FileFormat, opts, &format); err != nil {
+			return err
 		}
 
 		// sstSize, if 0, will be set to an appropriate default by the specific
@@ -759,20 +825,6 @@ func importPlanHook(
 			skipFKs = true
 		}
 
-		if override, ok := opts[importOptionDecompress]; ok {
-			found := false
-			for name, value := range roachpb.IOFileFormat_Compression_value {
-				if strings.EqualFold(name, override) {
-					format.Compression = roachpb.IOFileFormat_Compression(value)
-					found = true
-					break
-				}
-			}
-			if !found {
-				return unimplemented.Newf("import.compression", "unsupported compression value: %q", override)
-			}
-		}
-
 		var tableDetails []jobspb.ImportDetails_Table
 		var typeDetails []jobspb.ImportDetails_Type
 		jobDesc, err := importJobDescription(p, importStmt, filenamePatterns, opts)
@@ -795,6 +847,9 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+			if err := sql.CheckNoIncrementalMaterializedViews(found, "import into"); err != nil {
+				return err
+			}
 
 			// Validate target columns.
 			var intoCols []string
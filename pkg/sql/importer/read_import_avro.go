@@ -196,6 +196,11 @@ var familyToAvroT = map[types.Family][]string{
 	types.BitFamily:            {"string"},
 	types.DecimalFamily:        {"string"}, //TODO(Butler): import avro with logical decimal
 	types.EnumFamily:           {"string"},
+	types.TSQueryFamily:        {"string"},
+	types.TSVectorFamily:       {"string"},
+	types.RangeFamily:          {"string"},
+	types.JsonpathFamily:       {"string"},
+	types.PGVectorFamily:       {"string"},
 }
 
 // avroConsumer implements importRowConsumer interface.
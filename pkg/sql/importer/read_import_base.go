@@ -215,6 +215,12 @@ func readInputFiles(
 		default:
 		}
 		if err := func() error {
+			// The errors are returned to the user, so they must not contain the
+			// auth information of dataFile either.
+			redactedDataFile, err := cloud.SanitizeExternalStorageURI(dataFile, nil /* extraParams */)
+			if err != nil {
+				return err
+			}
 			conf, err := cloud.ExternalStorageConfFromURI(dataFile, user)
 			if err != nil {
 				return err
@@ -256,7 +262,7 @@ func readInputFiles(
 								pgcode.DataCorrupted,
 								"too many parsing errors (%d) encountered for file %s",
 								countRejected,
-								dataFile,
+								redactedDataFile,
 							)
 						}
 						buf = append(buf, s...)
@@ -294,11 +300,11 @@ func readInputFiles(
 				})
 
 				if err := grp.Wait(); err != nil {
-					return errors.Wrapf(err, "%s", dataFile)
+					return errors.Wrapf(err, "%s", redactedDataFile)
 				}
 			} else {
 				if err := fileFunc(ctx, src, dataFileIndex, resumePos[dataFileIndex], nil /* rejected */); err != nil {
-					return errors.Wrapf(err, "%s", dataFile)
+					return errors.Wrapf(err, "%s", redactedDataFile)
 				}
 			}
 			return nil
@@ -462,6 +468,11 @@ type parallelImportContext struct {
 	kvCh             chan row.KVBatch        // Channel for sending KV batches.
 	seqChunkProvider *row.SeqChunkProvider   // Used to reserve chunks of sequence values.
 	db               *kv.DB
+	// emitRow, if set, is called with the datums of the target columns of each
+	// row instead of converting the row into KVs. It is used by the scans of
+	// foreign tables, which read the rows with a single worker so that emitRow
+	// is called in the order of the rows in the file.
+	emitRow func(ctx context.Context, source int32, rowNum int64, datums tree.Datums) error
 }
 
 // importFileContext describes state specific to a file being imported.
@@ -722,6 +733,14 @@ func (p *parallelImporter) importWorker(
 				continue
 			}
 
+			if importCtx.emitRow != nil {
+				datums := conv.Datums[:len(conv.VisibleCols)]
+				if err := importCtx.emitRow(ctx, fileCtx.source, rowNum, datums); err != nil {
+					return err
+				}
+				continue
+			}
+
 			rowIndex := int64(timestamp) + rowNum
 			if err := conv.Row(ctx, conv.KvBatch.Source, rowIndex); err != nil {
 				return newImportRowError(err, fmt.Sprintf("%v", record), rowNum)
@@ -0,0 +1,815 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// Incrementally refreshed materialized views are maintained by row-level
+// triggers which are synthesized when the mutations of the tables they depend
+// on are planned (see incrementalViewMaintainer.triggers). The triggers apply
+// the changes to the view in the mutating transaction, so the view is always
+// consistent with the tables it depends on.
+//
+// Only a subset of queries can be maintained incrementally: a single SELECT
+// clause over an inner join of distinct tables, with an optional WHERE clause
+// and optional grouping, whose aggregates are only sum() and count(). The view
+// query must be immutable (this is checked by the optbuilder).
+//
+// The rows of a view without aggregation are identified by the primary keys
+// of the rows of the tables from which they are derived, which are stored in
+// hidden columns of the view. The changes to a row of one of the tables are
+// applied by deleting the rows derived from the old version of the row before
+// it is modified, and by inserting the rows derived from the new version of
+// the row after it is modified.
+//
+// The rows of a view with aggregation are identified by the values of their
+// grouping columns. The number of rows in each group and the number of
+// non-NULL arguments of each sum() are stored in hidden columns of the view.
+// The changes to a row of one of the tables are applied by subtracting the
+// aggregates of the rows derived from the old version of the row before it is
+// modified, and by adding the aggregates of the rows derived from the new
+// version of the row after it is modified. Groups are added and removed as
+// their number of rows becomes positive or zero. The grouping columns are
+// indexed, so that the group of a modified row can be found without scanning
+// the view.
+//
+// The tables a view depends on cannot be modified by statements which do not
+// support triggers yet, such as UPSERT and INSERT ... ON CONFLICT (see
+// mutationBuilder.checkNoRowTriggers), or by transactions which run under READ
+// COMMITTED isolation, as concurrent maintenance of the view by transactions
+// which do not observe each other's writes could, for example, add the same
+// group twice.
+
+// CheckNoIncrementalMaterializedViews returns an error if an incrementally
+// refreshed materialized view depends on the given table. It is used by the
+// operations which modify the rows of a table without running its triggers.
+func CheckNoIncrementalMaterializedViews(desc catalog.TableDescriptor, op string) error {
+	for _, ref := range desc.GetDependedOnBy() {
+		if ref.IncrementalRefresh {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot %s table %q because incrementally refreshed materialized view [%d] depends on it",
+				op, desc.GetName(), ref.ID)
+		}
+	}
+	return nil
+}
+
+// incrementalViewOutputKind is the kind of an expression in the select list
+// of an incrementally refreshed materialized view.
+type incrementalViewOutputKind int
+
+const (
+	// incrementalViewGroupingOutput is an expression without aggregates. In a
+	// query with aggregation, these expressions identify the groups.
+	incrementalViewGroupingOutput incrementalViewOutputKind = iota
+	// incrementalViewSumOutput is a call to sum().
+	incrementalViewSumOutput
+	// incrementalViewCountOutput is a call to count().
+	incrementalViewCountOutput
+)
+
+// incrementalViewQuery is the analyzed query of an incrementally refreshed
+// materialized view.
+type incrementalViewQuery struct {
+	sel *tree.SelectClause
+	// sources contains the names of the tables in the FROM clause, in the order
+	// in which they appear.
+	sources []*tree.UnresolvedObjectName
+	// qualifiers contains the names by which the columns of each source are
+	// qualified in the query, formatted as SQL.
+	qualifiers []string
+	// aggregate is true if the query has aggregation.
+	aggregate bool
+	// outputs contains the kind of each expression in the select list.
+	outputs []incrementalViewOutputKind
+}
+
+// analyzeIncrementalViewQuery parses the query of a materialized view and
+// checks that it can be maintained incrementally. The query has already been
+// built by the optimizer, so only its shape is checked.
+func analyzeIncrementalViewQuery(
+	viewQuery string, searchPath tree.SearchPath,
+) (*incrementalViewQuery, error) {
+	notSupported := func(what string) error {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s are not supported in incrementally refreshed materialized views", what)
+	}
+	stmt, err := parser.ParseOne(viewQuery)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := stmt.AST.(*tree.Select)
+	for ok {
+		if s.With != nil {
+			return nil, notSupported("common table expressions")
+		}
+		if s.OrderBy != nil || s.Limit != nil || s.Locking != nil {
+			return nil, notSupported("ORDER BY, LIMIT and locking clauses")
+		}
+		var paren *tree.ParenSelect
+		if paren, ok = s.Select.(*tree.ParenSelect); ok {
+			s = paren.Select
+		}
+	}
+	if s == nil {
+		return nil, notSupported("statements other than SELECT")
+	}
+	sel, ok := s.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, notSupported("set operations and VALUES clauses")
+	}
+	switch {
+	case sel.TableSelect:
+		return nil, notSupported("TABLE statements")
+	case sel.Distinct || sel.DistinctOn != nil:
+		return nil, notSupported("DISTINCT clauses")
+	case sel.Having != nil:
+		return nil, notSupported("HAVING clauses")
+	case sel.Window != nil:
+		return nil, notSupported("window functions")
+	case sel.From.AsOf.Expr != nil:
+		return nil, notSupported("AS OF SYSTEM TIME clauses")
+	}
+
+	q := &incrementalViewQuery{sel: sel}
+	v := incrementalViewExprVisitor{searchPath: searchPath}
+	var addSources func(tree.TableExpr) error
+	addSources = func(expr tree.TableExpr) error {
+		switch t := expr.(type) {
+		case *tree.ParenTableExpr:
+			return addSources(t.Expr)
+		case *tree.JoinTableExpr:
+			if t.JoinType != "" && t.JoinType != tree.AstInner && t.JoinType != tree.AstCross {
+				return notSupported(fmt.Sprintf("%s joins", t.JoinType))
+			}
+			if err := addSources(t.Left); err != nil {
+				return err
+			}
+			if err := addSources(t.Right); err != nil {
+				return err
+			}
+			if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+				return v.check(on.Expr)
+			}
+			return nil
+		case *tree.AliasedTableExpr:
+			if t.Ordinality || t.Lateral || t.As.Cols != nil {
+				return notSupported("WITH ORDINALITY, LATERAL and column aliases")
+			}
+			tn, ok := t.Expr.(*tree.UnresolvedObjectName)
+			if !ok {
+				return notSupported("sources other than tables")
+			}
+			qualifier := tree.AsStringWithFlags(tn, tree.FmtParsable)
+			name := tn.Object()
+			if t.As.Alias != "" {
+				qualifier = tree.NameString(string(t.As.Alias))
+				name = string(t.As.Alias)
+			}
+			// The statements which maintain the view refer to the modified rows
+			// as old and new.
+			if name == "old" || name == "new" {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"tables named %q are not supported in incrementally refreshed materialized views", name)
+			}
+			q.sources = append(q.sources, tn)
+			q.qualifiers = append(q.qualifiers, qualifier)
+			return nil
+		default:
+			return notSupported("sources other than tables")
+		}
+	}
+	for _, t := range sel.From.Tables {
+		if err := addSources(t); err != nil {
+			return nil, err
+		}
+	}
+	if len(q.sources) == 0 {
+		return nil, notSupported("queries without a FROM clause")
+	}
+	if sel.Where != nil {
+		if err := v.check(sel.Where.Expr); err != nil {
+			return nil, err
+		}
+		if v.aggregates > 0 {
+			return nil, pgerror.New(pgcode.Grouping, "aggregate functions are not allowed in WHERE")
+		}
+	}
+
+	q.outputs = make([]incrementalViewOutputKind, len(sel.Exprs))
+	for i := range sel.Exprs {
+		v.aggregates = 0
+		expr := sel.Exprs[i].Expr
+		if err := v.check(expr); err != nil {
+			return nil, err
+		}
+		if v.aggregates == 0 {
+			continue
+		}
+		q.aggregate = true
+		f, ok := expr.(*tree.FuncExpr)
+		if !ok || v.aggregates > 1 {
+			return nil, notSupported("expressions containing aggregate functions")
+		}
+		if f.Type == tree.DistinctFuncType || f.Filter != nil || f.OrderBy != nil ||
+			f.AggType == tree.OrderedSetAgg {
+			return nil, notSupported("DISTINCT, FILTER and ORDER BY clauses of aggregate functions")
+		}
+		switch v.lastAggregate {
+		case "sum":
+			q.outputs[i] = incrementalViewSumOutput
+		case "count":
+			q.outputs[i] = incrementalViewCountOutput
+		default:
+			return nil, notSupported(fmt.Sprintf("aggregate functions other than sum() and count(), such as %s(),",
+				v.lastAggregate))
+		}
+	}
+
+	if len(sel.GroupBy) > 0 {
+		q.aggregate = true
+	}
+	for _, expr := range sel.GroupBy {
+		if _, ok := expr.(*tree.GroupingSet); ok {
+			return nil, notSupported("grouping sets")
+		}
+		if err := v.check(expr); err != nil {
+			return nil, err
+		}
+		if q.groupingOutput(expr) < 0 {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"GROUP BY expression %s must appear in the select list of an incrementally refreshed materialized view",
+				tree.AsStringWithFlags(expr, tree.FmtParsable))
+		}
+	}
+	return q, nil
+}
+
+// groupingOutput returns the index of the expression in the select list which
+// a GROUP BY expression refers to, or -1 if there is none.
+func (q *incrementalViewQuery) groupingOutput(expr tree.Expr) int {
+	switch t := expr.(type) {
+	case *tree.NumVal:
+		if ord, err := t.AsInt64(); err == nil && ord >= 1 && int(ord) <= len(q.sel.Exprs) &&
+			q.outputs[ord-1] == incrementalViewGroupingOutput {
+			return int(ord) - 1
+		}
+		return -1
+	case *tree.UnresolvedName:
+		if t.NumParts == 1 && !t.Star {
+			for i := range q.sel.Exprs {
+				if q.sel.Exprs[i].As != "" && string(q.sel.Exprs[i].As) == t.Parts[0] &&
+					q.outputs[i] == incrementalViewGroupingOutput {
+					return i
+				}
+			}
+		}
+	}
+	text := tree.AsStringWithFlags(expr, tree.FmtParsable)
+	for i := range q.sel.Exprs {
+		if q.outputs[i] == incrementalViewGroupingOutput &&
+			tree.AsStringWithFlags(q.sel.Exprs[i].Expr, tree.FmtParsable) == text {
+			return i
+		}
+	}
+	return -1
+}
+
+// incrementalViewExprVisitor checks that the expressions of the query of an
+// incrementally refreshed materialized view have no subqueries or window
+// functions, and counts their calls to aggregate functions.
+type incrementalViewExprVisitor struct {
+	searchPath tree.SearchPath
+	err        error
+	// aggregates is the number of calls to aggregate functions.
+	aggregates int
+	// lastAggregate is the name of the last aggregate function.
+	lastAggregate string
+}
+
+var _ tree.Visitor = &incrementalViewExprVisitor{}
+
+func (v *incrementalViewExprVisitor) check(expr tree.Expr) error {
+	v.err = nil
+	tree.WalkExprConst(v, expr)
+	return v.err
+}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *incrementalViewExprVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.err = pgerror.New(pgcode.FeatureNotSupported,
+			"subqueries are not supported in incrementally refreshed materialized views")
+		return false, expr
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.err = pgerror.New(pgcode.FeatureNotSupported,
+				"window functions are not supported in incrementally refreshed materialized views")
+			return false, expr
+		}
+		// Resolve a copy of the reference, which is modified by Resolve. The
+		// functions which cannot be resolved are not aggregates: user-defined
+		// aggregates cannot be used in views.
+		ref := t.Func
+		if def, err := ref.Resolve(v.searchPath); err == nil && def.Class == tree.AggregateClass {
+			v.aggregates++
+			v.lastAggregate = def.Name
+		}
+	}
+	return true, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (*incrementalViewExprVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// makeIncrementalRefresh sets up a new materialized view to be maintained
+// incrementally: it adds the hidden columns and the indexes which are used to
+// maintain the view, and records them in desc.IncrementalRefresh. The view is
+// populated by the schema changer once it is public.
+func (n *createViewNode) makeIncrementalRefresh(params runParams, desc *tabledesc.Mutable) error {
+	q, err := analyzeIncrementalViewQuery(n.viewQuery, &sessiondata.DefaultSearchPath)
+	if err != nil {
+		return err
+	}
+	if len(q.outputs) != len(n.columns) {
+		return errors.AssertionFailedf("view query has %d columns, expected %d",
+			len(q.outputs), len(n.columns))
+	}
+
+	addHiddenColumn := func(prefix string, typ *types.T) (descpb.ColumnID, string) {
+		name := tabledesc.GenerateUniqueName(prefix, func(name string) bool {
+			_, err := desc.FindColumnWithName(tree.Name(name))
+			return err == nil
+		})
+		id := desc.NextColumnID
+		desc.NextColumnID++
+		desc.AddColumn(&descpb.ColumnDescriptor{
+			Name:     name,
+			ID:       id,
+			Type:     typ,
+			Nullable: true,
+			Hidden:   true,
+		})
+		return id, name
+	}
+	addIndex := func(colNames []string) error {
+		idx := descpb.IndexDescriptor{
+			KeyColumnNames:      colNames,
+			KeyColumnDirections: make([]catpb.IndexColumn_Direction, len(colNames)),
+			Version:             descpb.StrictIndexColumnIDGuaranteesVersion,
+		}
+		return desc.AddSecondaryIndex(idx)
+	}
+
+	ir := &descpb.TableDescriptor_IncrementalRefresh{}
+	var seen catalog.DescriptorIDSet
+	for _, name := range q.sources {
+		src, err := params.p.ResolveExistingObjectEx(
+			params.ctx, name, true /* required */, tree.ResolveAnyTableKind,
+		)
+		if err != nil {
+			return err
+		}
+		if !src.IsTable() || src.IsVirtualTable() || src.IsForeign() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"incrementally refreshed materialized views can only depend on tables, but %q is not a table",
+				src.GetName())
+		}
+		if seen.Contains(src.GetID()) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"incrementally refreshed materialized views cannot refer to table %q more than once",
+				src.GetName())
+		}
+		seen.Add(src.GetID())
+
+		source := descpb.TableDescriptor_IncrementalRefresh_Source{TableID: src.GetID()}
+		if !q.aggregate {
+			pk := src.GetPrimaryIndex()
+			colNames := make([]string, pk.NumKeyColumns())
+			for i := range colNames {
+				col, err := src.FindColumnWithID(pk.GetKeyColumnID(i))
+				if err != nil {
+					return err
+				}
+				var id descpb.ColumnID
+				id, colNames[i] = addHiddenColumn(
+					fmt.Sprintf("crdb_internal_ivm_%s_%s", src.GetName(), col.GetName()), col.GetType(),
+				)
+				source.KeyColumnIDs = append(source.KeyColumnIDs, id)
+			}
+			if err := addIndex(colNames); err != nil {
+				return err
+			}
+		}
+		ir.Sources = append(ir.Sources, source)
+	}
+
+	if q.aggregate {
+		ir.CountColumnID, _ = addHiddenColumn("crdb_internal_ivm_count", types.Int)
+		var groupingCols []string
+		for i, kind := range q.outputs {
+			switch kind {
+			case incrementalViewGroupingOutput:
+				// Without an index on the grouping columns, each modified row
+				// would scan the view to find its group.
+				if !colinfo.ColumnTypeIsIndexable(n.columns[i].Typ) {
+					typ := n.columns[i].Typ
+					return unimplemented.NewWithIssueDetailf(35730, typ.DebugString(),
+						"grouping column %s of an incrementally refreshed materialized view is of type %s and thus is not indexable",
+						n.columns[i].Name, typ.Name())
+				}
+				groupingCols = append(groupingCols, n.columns[i].Name)
+			case incrementalViewSumOutput:
+				// Subtracting floats does not undo their addition.
+				if n.columns[i].Typ.Family() == types.FloatFamily {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"sums of floating-point values are not supported in incrementally refreshed materialized views")
+				}
+				id, _ := addHiddenColumn(fmt.Sprintf("crdb_internal_ivm_count_%s", n.columns[i].Name), types.Int)
+				ir.NonNullCountColumnIDs = append(ir.NonNullCountColumnIDs, id)
+			}
+		}
+		if len(groupingCols) > 0 {
+			if err := addIndex(groupingCols); err != nil {
+				return err
+			}
+		}
+	}
+
+	desc.IncrementalRefresh = ir
+	// The view is populated by the schema changer once the mutations of the
+	// tables it depends on maintain it.
+	desc.RefreshViewRequired = true
+	return nil
+}
+
+// incrementalViewMaintainer generates the statements which populate and
+// maintain an incrementally refreshed materialized view.
+type incrementalViewMaintainer struct {
+	view catalog.TableDescriptor
+	q    *incrementalViewQuery
+	// sourceIDs contains the IDs of the sources of the view query.
+	sourceIDs []descpb.ID
+	// viewCols contains the names of the visible columns of the view, which are
+	// computed by the expressions in the select list of the query, formatted as
+	// SQL.
+	viewCols []string
+	// keyCols contains, for each source, the names of its primary key columns,
+	// formatted as SQL.
+	keyCols [][]string
+	// hiddenCols contains the names of the hidden columns of the view which are
+	// maintained along with the visible columns, formatted as SQL.
+	hiddenCols []string
+	// hiddenExprs contains the expressions which compute the hidden columns,
+	// formatted as SQL.
+	hiddenExprs []string
+	// countCol is the name of the hidden column which stores the number of rows
+	// in each group, if the query has aggregation.
+	countCol string
+	// nonNullCountCols contains, for each sum() in the select list, the name of
+	// the hidden column which stores the number of its non-NULL arguments.
+	nonNullCountCols []string
+}
+
+// makeIncrementalViewMaintainer creates an incrementalViewMaintainer for the
+// given view. The descriptors of the tables the view depends on are looked up
+// with getSource.
+func makeIncrementalViewMaintainer(
+	view catalog.TableDescriptor, getSource func(descpb.ID) (catalog.TableDescriptor, error),
+) (*incrementalViewMaintainer, error) {
+	ir := view.GetIncrementalRefresh()
+	if ir == nil {
+		return nil, errors.AssertionFailedf("view %q is not refreshed incrementally", view.GetName())
+	}
+	q, err := analyzeIncrementalViewQuery(view.GetViewQuery(), &sessiondata.DefaultSearchPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(q.sources) != len(ir.Sources) {
+		return nil, errors.AssertionFailedf("view %q has %d sources, expected %d",
+			view.GetName(), len(q.sources), len(ir.Sources))
+	}
+	m := &incrementalViewMaintainer{view: view, q: q}
+	for _, col := range view.VisibleColumns() {
+		m.viewCols = append(m.viewCols, tree.NameString(col.GetName()))
+	}
+	if len(m.viewCols) != len(q.outputs) {
+		return nil, errors.AssertionFailedf("view %q has %d columns, expected %d",
+			view.GetName(), len(m.viewCols), len(q.outputs))
+	}
+	colName := func(id descpb.ColumnID) (string, error) {
+		col, err := view.FindColumnWithID(id)
+		if err != nil {
+			return "", err
+		}
+		return tree.NameString(col.GetName()), nil
+	}
+
+	m.keyCols = make([][]string, len(ir.Sources))
+	for i, source := range ir.Sources {
+		src, err := getSource(source.TableID)
+		if err != nil {
+			return nil, err
+		}
+		m.sourceIDs = append(m.sourceIDs, source.TableID)
+		pk := src.GetPrimaryIndex()
+		for j := 0; j < pk.NumKeyColumns(); j++ {
+			m.keyCols[i] = append(m.keyCols[i], tree.NameString(pk.GetKeyColumnName(j)))
+		}
+		if q.aggregate {
+			continue
+		}
+		if len(source.KeyColumnIDs) != len(m.keyCols[i]) {
+			return nil, errors.AssertionFailedf(
+				"view %q stores %d primary key columns of table %q, expected %d",
+				view.GetName(), len(source.KeyColumnIDs), src.GetName(), len(m.keyCols[i]))
+		}
+		for j, id := range source.KeyColumnIDs {
+			name, err := colName(id)
+			if err != nil {
+				return nil, err
+			}
+			m.hiddenCols = append(m.hiddenCols, name)
+			m.hiddenExprs = append(m.hiddenExprs, fmt.Sprintf("%s.%s", q.qualifiers[i], m.keyCols[i][j]))
+		}
+	}
+
+	if q.aggregate {
+		if m.countCol, err = colName(ir.CountColumnID); err != nil {
+			return nil, err
+		}
+		m.hiddenCols = append(m.hiddenCols, m.countCol)
+		m.hiddenExprs = append(m.hiddenExprs, "count(*)")
+		m.nonNullCountCols = make([]string, len(q.outputs))
+		sums := 0
+		for i, kind := range q.outputs {
+			if kind != incrementalViewSumOutput {
+				continue
+			}
+			if sums >= len(ir.NonNullCountColumnIDs) {
+				return nil, errors.AssertionFailedf("view %q is missing non-NULL counts", view.GetName())
+			}
+			if m.nonNullCountCols[i], err = colName(ir.NonNullCountColumnIDs[sums]); err != nil {
+				return nil, err
+			}
+			sums++
+			arg := q.sel.Exprs[i].Expr.(*tree.FuncExpr).Exprs[0]
+			m.hiddenCols = append(m.hiddenCols, m.nonNullCountCols[i])
+			m.hiddenExprs = append(m.hiddenExprs,
+				fmt.Sprintf("count(%s)", tree.AsStringWithFlags(arg, tree.FmtParsable)))
+		}
+	}
+	return m, nil
+}
+
+// newIncrementalViewMaintainer creates an incrementalViewMaintainer for the
+// given view, looking up the tables it depends on in the given transaction.
+func newIncrementalViewMaintainer(
+	ctx context.Context, descsCol *descs.Collection, txn *kv.Txn, view catalog.TableDescriptor,
+) (*incrementalViewMaintainer, error) {
+	return makeIncrementalViewMaintainer(view, func(id descpb.ID) (catalog.TableDescriptor, error) {
+		return descsCol.GetImmutableTableByID(ctx, txn, id, tree.ObjectLookupFlagsWithRequired())
+	})
+}
+
+// target returns a reference to the view with the given alias.
+func (m *incrementalViewMaintainer) target(alias string) string {
+	return fmt.Sprintf("[%d AS %s]", m.view.GetID(), alias)
+}
+
+// allCols returns the names of the visible and hidden columns of the view
+// which are computed by the view query, prefixed by the given qualifier.
+func (m *incrementalViewMaintainer) allCols(qualifier string) string {
+	var buf strings.Builder
+	for i, col := range append(m.viewCols[:len(m.viewCols):len(m.viewCols)], m.hiddenCols...) {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(qualifier)
+		buf.WriteString(col)
+	}
+	return buf.String()
+}
+
+// query returns the view query, extended with the expressions which compute
+// the hidden columns of the view, and restricted to the rows derived from the
+// given row of the given source if row is not empty.
+func (m *incrementalViewMaintainer) query(source int, row string) string {
+	sel := m.q.sel
+	var buf strings.Builder
+	buf.WriteString("SELECT ")
+	buf.WriteString(tree.AsStringWithFlags(&sel.Exprs, tree.FmtParsable))
+	for _, expr := range m.hiddenExprs {
+		buf.WriteString(", ")
+		buf.WriteString(expr)
+	}
+	buf.WriteString(" FROM ")
+	buf.WriteString(tree.AsStringWithFlags(&sel.From.Tables, tree.FmtParsable))
+	var conds []string
+	if sel.Where != nil {
+		conds = append(conds, fmt.Sprintf("(%s)", tree.AsStringWithFlags(sel.Where.Expr, tree.FmtParsable)))
+	}
+	if row != "" {
+		for _, col := range m.keyCols[source] {
+			conds = append(conds, fmt.Sprintf("%s.%s = %s.%s", m.q.qualifiers[source], col, row, col))
+		}
+	}
+	if len(conds) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(conds, " AND "))
+	}
+	if len(sel.GroupBy) > 0 {
+		buf.WriteByte(' ')
+		buf.WriteString(tree.AsStringWithFlags(&sel.GroupBy, tree.FmtParsable))
+	}
+	return buf.String()
+}
+
+// populateStmt returns the statement which inserts the result of the view
+// query into the empty view.
+func (m *incrementalViewMaintainer) populateStmt() string {
+	return fmt.Sprintf("INSERT INTO %s (%s) %s", m.target("v"), m.allCols(""), m.query(0, ""))
+}
+
+// delta returns the aggregates of the rows derived from the given row of the
+// given source, as a table expression with alias d.
+func (m *incrementalViewMaintainer) delta(source int, row string) string {
+	return fmt.Sprintf("(%s) AS d(%s)", m.query(source, row), m.allCols(""))
+}
+
+// sameGroup returns a predicate which is true if the rows with the given
+// aliases belong to the same group.
+func (m *incrementalViewMaintainer) sameGroup(left, right string) string {
+	var conds []string
+	for i, kind := range m.q.outputs {
+		if kind == incrementalViewGroupingOutput {
+			col := m.viewCols[i]
+			conds = append(conds, fmt.Sprintf("%s.%s IS NOT DISTINCT FROM %s.%s", left, col, right, col))
+		}
+	}
+	if len(conds) == 0 {
+		return "true"
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// updateGroupsStmt returns the statement which adds (or subtracts) the
+// aggregates of the rows derived from the given row of the given source to
+// (or from) the existing groups of the view.
+func (m *incrementalViewMaintainer) updateGroupsStmt(source int, row string, add bool) string {
+	var sets []string
+	setCount := func(col string) {
+		op := "-"
+		if add {
+			op = "+"
+		}
+		sets = append(sets, fmt.Sprintf("%[1]s = v.%[1]s %[2]s d.%[1]s", col, op))
+	}
+	for i, kind := range m.q.outputs {
+		col := m.viewCols[i]
+		switch kind {
+		case incrementalViewSumOutput:
+			// A sum is NULL if all its arguments are NULL.
+			if add {
+				sets = append(sets, fmt.Sprintf("%[1]s = COALESCE(v.%[1]s + d.%[1]s, v.%[1]s, d.%[1]s)", col))
+			} else {
+				sets = append(sets, fmt.Sprintf(
+					"%[1]s = CASE WHEN v.%[2]s = d.%[2]s THEN NULL WHEN d.%[2]s = 0 THEN v.%[1]s ELSE v.%[1]s - d.%[1]s END",
+					col, m.nonNullCountCols[i]))
+			}
+		case incrementalViewCountOutput:
+			setCount(col)
+		}
+	}
+	for _, col := range m.hiddenCols {
+		setCount(col)
+	}
+	return fmt.Sprintf("UPDATE %s SET %s FROM %s WHERE %s",
+		m.target("v"), strings.Join(sets, ", "), m.delta(source, row), m.sameGroup("v", "d"))
+}
+
+// triggers returns the row-level triggers which maintain the view when the
+// rows of the given table are modified.
+func (m *incrementalViewMaintainer) triggers(tableID descpb.ID) ([]cat.Trigger, error) {
+	source := -1
+	for i, id := range m.sourceIDs {
+		if id == tableID {
+			source = i
+		}
+	}
+	if source < 0 {
+		return nil, errors.AssertionFailedf("view %q does not depend on table %d",
+			m.view.GetName(), tableID)
+	}
+
+	var before, after []string
+	if !m.q.aggregate {
+		var conds []string
+		hidden := m.hiddenCols
+		for i := 0; i < source; i++ {
+			hidden = hidden[len(m.keyCols[i]):]
+		}
+		for j, col := range m.keyCols[source] {
+			conds = append(conds, fmt.Sprintf("v.%s = old.%s", hidden[j], col))
+		}
+		before = append(before, fmt.Sprintf("DELETE FROM %s WHERE %s",
+			m.target("v"), strings.Join(conds, " AND ")))
+		after = append(after, fmt.Sprintf("INSERT INTO %s (%s) %s",
+			m.target("v"), m.allCols(""), m.query(source, "new")))
+	} else {
+		before = append(before, m.updateGroupsStmt(source, "old", false /* add */))
+		after = append(after, m.updateGroupsStmt(source, "new", true /* add */))
+		// Without grouping columns, the view always has a single row.
+		if len(m.q.sel.GroupBy) > 0 {
+			before = append(before, fmt.Sprintf("DELETE FROM %s USING %s WHERE %s AND v.%s = 0",
+				m.target("v"), m.delta(source, "old"), m.sameGroup("v", "d"), m.countCol))
+			after = append(after, fmt.Sprintf(
+				"INSERT INTO %s (%s) SELECT %s FROM %s LEFT JOIN %s ON %s WHERE e.%s IS NULL",
+				m.target("v"), m.allCols(""), m.allCols("d."), m.delta(source, "new"),
+				m.target("e"), m.sameGroup("e", "d"), m.countCol))
+		}
+	}
+
+	name := fmt.Sprintf("crdb_internal_ivm_%d", m.view.GetID())
+	return []cat.Trigger{
+		{
+			Name:          name + "_before",
+			Before:        true,
+			OnUpdate:      true,
+			OnDelete:      true,
+			Stmts:         before,
+			MaintainsView: true,
+		},
+		{
+			Name:          name + "_after",
+			OnInsert:      true,
+			OnUpdate:      true,
+			Stmts:         after,
+			MaintainsView: true,
+		},
+	}, nil
+}
+
+// populateIncrementalMaterializedView recomputes the data of an incrementally
+// refreshed materialized view in the given transaction. The mutations of the
+// tables the view depends on must already maintain the view, so that the view
+// remains consistent with them once the transaction commits.
+func populateIncrementalMaterializedView(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	view catalog.TableDescriptor,
+) error {
+	m, err := newIncrementalViewMaintainer(ctx, descsCol, txn, view)
+	if err != nil {
+		return err
+	}
+	span := view.TableSpan(execCfg.Codec)
+	if _, err := txn.DelRange(ctx, span.Key, span.EndKey, false /* returnKeys */); err != nil {
+		return err
+	}
+	_, err = execCfg.InternalExecutor.ExecEx(
+		ctx, "populate-materialized-view", txn,
+		sessiondata.InternalExecutorOverride{
+			User:                      username.NodeUserName(),
+			MaintainMaterializedViews: true,
+		},
+		m.populateStmt(),
+	)
+	return err
+}
@@ -364,9 +364,8 @@ https://www.postgresql.org/docs/9.5/infoschema-column-privileges.html`,
 		) error {
 			dbNameStr := tree.NewDString(db.GetName())
 			scNameStr := tree.NewDString(scName)
-			columndata := privilege.List{privilege.SELECT, privilege.INSERT, privilege.UPDATE} // privileges for column level granularity
 			for _, u := range table.GetPrivileges().Users {
-				for _, priv := range columndata {
+				for _, priv := range privilege.ColumnPrivileges {
 					if priv.Mask()&u.Privileges != 0 {
 						for _, cd := range table.PublicColumns() {
 							if err := addRow(
@@ -385,6 +384,33 @@ https://www.postgresql.org/docs/9.5/infoschema-column-privileges.html`,
 					}
 				}
 			}
+			// Add the privileges which are granted on the columns themselves.
+			for _, cp := range table.GetColumnPrivileges() {
+				col, err := table.FindColumnWithID(cp.ColumnID)
+				if err != nil {
+					return err
+				}
+				for _, u := range cp.Users {
+					for _, priv := range privilege.ColumnPrivileges {
+						if priv.Mask()&u.Privileges == 0 {
+							continue
+						}
+						isGrantable := yesOrNoDatum(priv.Mask()&u.WithGrantOption != 0)
+						if err := addRow(
+							tree.DNull,                             // grantor
+							tree.NewDString(u.User().Normalized()), // grantee
+							dbNameStr,                              // table_catalog
+							scNameStr,                              // table_schema
+							tree.NewDString(table.GetName()),       // table_name
+							tree.NewDString(col.GetName()),         // column_name
+							tree.NewDString(priv.String()),         // privilege_type
+							isGrantable,                            // is_grantable
+						); err != nil {
+							return err
+						}
+					}
+				}
+			}
 			return nil
 		})
 	},
@@ -734,6 +760,10 @@ https://www.postgresql.org/docs/9.5/infoschema-constraint-column-usage.html`,
 			dbNameStr := tree.NewDString(db.GetName())
 
 			for conName, con := range conInfo {
+				// Like Postgres, exclude EXCLUDE constraints.
+				if con.Kind == descpb.ConstraintTypeExclusion {
+					continue
+				}
 				conTable := table
 				conCols := con.Columns
 				conNameStr := tree.NewDString(conName)
@@ -1243,6 +1273,14 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					// Like Postgres, exclude EXCLUDE constraints, whose type is not
+					// part of the SQL standard.
+					if c.Kind == descpb.ConstraintTypeExclusion {
+						continue
+					}
+					deferrability := c.Deferrability()
+					isDeferrable := deferrability != descpb.ConstraintDeferrability_NotDeferrable
+					initiallyDeferred := deferrability == descpb.ConstraintDeferrability_InitiallyDeferred
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1251,8 +1289,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(isDeferrable),      // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -1371,6 +1409,7 @@ var (
 	tableTypeBaseTable  = tree.NewDString("BASE TABLE")
 	tableTypeView       = tree.NewDString("VIEW")
 	tableTypeTemporary  = tree.NewDString("LOCAL TEMPORARY")
+	tableTypeForeign    = tree.NewDString("FOREIGN")
 )
 
 var informationSchemaTablesTable = virtualSchemaTable{
@@ -1402,6 +1441,9 @@ func addTablesTableRow(
 		} else if table.IsView() {
 			tableType = tableTypeView
 			insertable = noString
+		} else if table.IsForeign() {
+			tableType = tableTypeForeign
+			insertable = noString
 		} else if table.IsTemporary() {
 			tableType = tableTypeTemporary
 		}
@@ -600,6 +600,9 @@ func applyOverrides(o sessiondata.InternalExecutorOverride, sd *sessiondata.Sess
 	if o.QualityOfService != nil {
 		sd.DefaultTxnQualityOfService = o.QualityOfService.ValidateInternal()
 	}
+	if o.MaintainMaterializedViews {
+		sd.MaintainMaterializedViews = true
+	}
 }
 
 func (ie *InternalExecutor) maybeRootSessionDataOverride(
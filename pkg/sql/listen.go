@@ -0,0 +1,228 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// maxNotifyPayloadLength is the maximum length of the payload of a
+// notification, which matches the limit of Postgres.
+const maxNotifyPayloadLength = 8000
+
+// listenAction is a LISTEN or UNLISTEN statement executed in a transaction.
+// The actions are only applied to the session when the transaction commits.
+type listenAction struct {
+	// unlisten is set for UNLISTEN.
+	unlisten bool
+	// channel is the channel listened on, or empty for UNLISTEN *.
+	channel string
+}
+
+// listenActions is a slice because ROLLBACK TO SAVEPOINT truncates it.
+type listenActions []listenAction
+
+// sentNotification is a notification sent in a transaction.
+type sentNotification struct {
+	channel string
+	payload string
+}
+
+// sentNotifications are the notifications sent in a transaction, in the order
+// in which they were sent. The position of a notification in the list is its
+// sequence number in the transaction. Like in Postgres, identical
+// notifications are collapsed: only the first of them is written, so that the
+// transaction commits a single row for each distinct notification. ROLLBACK TO
+// SAVEPOINT truncates the list, since it discards the rows written after the
+// savepoint.
+type sentNotifications struct {
+	list []sentNotification
+	set  map[sentNotification]struct{}
+}
+
+// contains returns whether an identical notification was sent.
+func (s *sentNotifications) contains(n sentNotification) bool {
+	_, ok := s.set[n]
+	return ok
+}
+
+// add records a notification and returns its sequence number.
+func (s *sentNotifications) add(n sentNotification) int {
+	if s.set == nil {
+		s.set = make(map[sentNotification]struct{})
+	}
+	s.set[n] = struct{}{}
+	s.list = append(s.list, n)
+	return len(s.list) - 1
+}
+
+// truncate forgets the notifications sent after the first n ones.
+func (s *sentNotifications) truncate(n int) {
+	for _, sent := range s.list[n:] {
+		delete(s.set, sent)
+	}
+	s.list = s.list[:n]
+}
+
+func (s *sentNotifications) reset() {
+	*s = sentNotifications{}
+}
+
+type listenNode struct {
+	action listenAction
+}
+
+// Listen starts listening on a channel when the transaction commits.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+// Privileges: None.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := p.checkCanListen(ctx, "LISTEN"); err != nil {
+		return nil, err
+	}
+	return &listenNode{action: listenAction{channel: string(n.Channel)}}, nil
+}
+
+// Unlisten stops listening on a channel, or on all channels, when the
+// transaction commits.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+// Privileges: None.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	if err := p.checkCanListen(ctx, "UNLISTEN"); err != nil {
+		return nil, err
+	}
+	action := listenAction{unlisten: true}
+	if !n.All {
+		action.channel = string(n.Channel)
+	}
+	return &listenNode{action: action}, nil
+}
+
+func (p *planner) checkCanListen(ctx context.Context, opName string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use %s",
+			clusterversion.ByKey(clusterversion.NotificationsTable), opName)
+	}
+	if p.SessionData().Internal || p.extendedEvalCtx.ListenActions == nil {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s cannot be used by internal sessions", opName)
+	}
+	return nil
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	actions := params.extendedEvalCtx.ListenActions
+	*actions = append(*actions, n.action)
+	return nil
+}
+
+func (n *listenNode) Next(runParams) (bool, error) { return false, nil }
+func (n *listenNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *listenNode) Close(context.Context)        {}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+// Notify sends a notification on a channel when the transaction commits.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+// Privileges: None.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &notifyNode{n: n}, nil
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.SendNotification(params.ctx, string(n.n.Channel), n.n.Payload)
+}
+
+func (n *notifyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *notifyNode) Close(context.Context)        {}
+
+// SendNotification is part of the eval.Planner interface.
+func (p *planner) SendNotification(ctx context.Context, channel string, payload string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use NOTIFY",
+			clusterversion.ByKey(clusterversion.NotificationsTable))
+	}
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(payload) >= maxNotifyPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	if p.EvalContext().TxnReadOnly {
+		return pgerror.New(pgcode.ReadOnlySQLTransaction,
+			"cannot execute NOTIFY in a read-only transaction")
+	}
+	sent := p.extendedEvalCtx.SentNotifications
+	if p.SessionData().Internal || sent == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"NOTIFY cannot be used by internal sessions")
+	}
+	n := sentNotification{channel: channel, payload: payload}
+	if sent.contains(n) {
+		return nil
+	}
+	// The notification is written in the transaction, so that it is only
+	// delivered if the transaction commits.
+	if _, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx, "notify", p.Txn(),
+		sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.notifications (channel, payload, pid, txn_id, seq) VALUES ($1, $2, $3, $4, $5)`,
+		channel, payload, int64(p.EvalContext().QueryCancelKey.GetPGBackendPID()),
+		tree.NewDUuid(tree.DUuid{UUID: p.Txn().ID()}), len(sent.list),
+	); err != nil {
+		return err
+	}
+	sent.add(n)
+	return nil
+}
+
+// applyListenActions applies the LISTEN and UNLISTEN statements of a
+// transaction which committed.
+func (ex *connExecutor) applyListenActions(ctx context.Context) {
+	actions := ex.extraTxnState.listenActions
+	if len(actions) == 0 {
+		return
+	}
+	if ex.notifyListener == nil {
+		connCtx, stmtBuf := ex.ctxHolder.connCtx, ex.stmtBuf
+		ex.notifyListener = ex.server.cfg.NotifyRegistry.NewListener(func() {
+			// The command is processed once the session is done with the
+			// commands it already received. The error can only be that the
+			// buffer was closed, in which case the session is finishing.
+			_ = stmtBuf.Push(connCtx, DeliverNotifications{})
+		})
+	}
+	for _, a := range actions {
+		switch {
+		case !a.unlisten:
+			if err := ex.notifyListener.Listen(ctx, a.channel); err != nil {
+				// The transaction already committed, so the error cannot be
+				// returned to the client.
+				log.Warningf(ctx, "failed to listen on channel %q: %v", a.channel, err)
+			}
+		case a.channel == "":
+			ex.notifyListener.UnlistenAll()
+		default:
+			ex.notifyListener.Unlisten(a.channel)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// retention is how long the notifications are kept in system.notifications
+// before they are deleted. The rangefeed delivers a notification as soon as it
+// is committed, so this only needs to cover the delay of the rangefeed.
+var retention = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"sql.notifications.retention",
+	"the amount of time after which the notifications sent with NOTIFY are deleted",
+	time.Minute,
+	settings.PositiveDuration,
+)
+
+// cleanupInterval is how often each server deletes the expired
+// notifications.
+const cleanupInterval = 30 * time.Second
+
+// maxPendingNotifications is the maximum number of notifications queued for a
+// listener that has not delivered them to its client. Further notifications
+// are dropped.
+const maxPendingNotifications = 1 << 16
+
+// Notification is a notification sent with NOTIFY or pg_notify().
+type Notification struct {
+	// Channel is the channel the notification was sent on.
+	Channel string
+	// Payload is the payload of the notification, which may be empty.
+	Payload string
+	// PID is the backend PID of the session that sent the notification.
+	PID uint32
+}
+
+// notificationID identifies a notification by the transaction which sent it
+// and its sequence number in the transaction.
+type notificationID struct {
+	txnID uuid.UUID
+	seq   int64
+}
+
+// Registry tracks the listeners of a SQL server and dispatches to them the
+// notifications written to system.notifications by any server of the
+// cluster.
+//
+// The notifications are carried by a rangefeed over system.notifications,
+// which is started when a session first listens on a channel. Since the
+// notifications are dispatched as soon as the rangefeed emits them, the
+// notifications of concurrent transactions are not necessarily delivered in
+// their commit order. The rangefeed may emit a row more than once, so the
+// notifications are deduplicated by their ID until the frontier of the
+// rangefeed passes their timestamp.
+type Registry struct {
+	codec            keys.SQLCodec
+	clock            *hlc.Clock
+	settings         *cluster.Settings
+	stopper          *stop.Stopper
+	rangeFeedFactory *rangefeed.Factory
+	ie               sqlutil.InternalExecutor
+
+	// decoder and delivered are only used by the rangefeed callbacks, which
+	// are not called concurrently.
+	decoder rowDecoder
+	// delivered maps the IDs of the notifications delivered by the rangefeed
+	// to their timestamp. The notifications are forgotten once the frontier
+	// of the rangefeed passes them, since the rangefeed never emits them
+	// again.
+	delivered map[notificationID]hlc.Timestamp
+
+	mu struct {
+		syncutil.Mutex
+		// sysTableResolver is set when the Registry is started.
+		sysTableResolver catalog.SystemTableIDResolver
+		// rangefeed is the rangefeed over system.notifications, or nil if no
+		// session has listened on a channel yet.
+		rangefeed *rangefeed.RangeFeed
+		listeners map[*Listener]struct{}
+	}
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(
+	codec keys.SQLCodec,
+	clock *hlc.Clock,
+	st *cluster.Settings,
+	stopper *stop.Stopper,
+	rangeFeedFactory *rangefeed.Factory,
+	ie sqlutil.InternalExecutor,
+) *Registry {
+	r := &Registry{
+		codec:            codec,
+		clock:            clock,
+		settings:         st,
+		stopper:          stopper,
+		rangeFeedFactory: rangeFeedFactory,
+		ie:               ie,
+		decoder:          makeRowDecoder(),
+		delivered:        make(map[notificationID]hlc.Timestamp),
+	}
+	r.mu.listeners = make(map[*Listener]struct{})
+	return r
+}
+
+// Start starts the loop which deletes the expired notifications.
+func (r *Registry) Start(ctx context.Context, sysTableResolver catalog.SystemTableIDResolver) {
+	r.mu.Lock()
+	r.mu.sysTableResolver = sysTableResolver
+	r.mu.Unlock()
+
+	ctx, _ = r.stopper.WithCancelOnQuiesce(ctx)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = r.stopper.RunAsyncTask(ctx, "notifications-cleanup", func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(cleanupInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-ctx.Done():
+				return
+			}
+			if err := r.deleteExpiredNotifications(ctx); err != nil && ctx.Err() == nil {
+				log.Warningf(ctx, "error deleting expired notifications: %v", err)
+			}
+		}
+	})
+}
+
+// deleteExpiredNotifications deletes the notifications that are older than
+// the retention.
+func (r *Registry) deleteExpiredNotifications(ctx context.Context) error {
+	if !r.settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return nil
+	}
+	_, err := r.ie.ExecEx(
+		ctx, "delete-expired-notifications", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.notifications WHERE created < $1`,
+		timeutil.Now().Add(-retention.Get(&r.settings.SV)),
+	)
+	return err
+}
+
+// NewListener creates a Listener for a session. onNotify is called, without
+// any locks held, when notifications become pending for a Listener which had
+// none.
+func (r *Registry) NewListener(onNotify func()) *Listener {
+	l := &Listener{r: r, onNotify: onNotify}
+	l.mu.channels = make(map[string]hlc.Timestamp)
+	return l
+}
+
+// register adds a listener to the registry, starting the rangefeed if needed.
+// The rangefeed runs until the stopper stops.
+func (r *Registry) register(ctx context.Context, l *Listener) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.rangefeed == nil {
+		if r.mu.sysTableResolver == nil {
+			return errors.AssertionFailedf("notification registry was not started")
+		}
+		rf, err := r.startRangeFeed(ctx, r.mu.sysTableResolver)
+		if err != nil {
+			return err
+		}
+		r.stopper.AddCloser(rf)
+		r.mu.rangefeed = rf
+	}
+	r.mu.listeners[l] = struct{}{}
+	return nil
+}
+
+// unregister removes a listener from the registry.
+func (r *Registry) unregister(l *Listener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.listeners, l)
+}
+
+// startRangeFeed starts the rangefeed over system.notifications. Only the
+// notifications written after it starts are emitted.
+func (r *Registry) startRangeFeed(
+	ctx context.Context, sysTableResolver catalog.SystemTableIDResolver,
+) (*rangefeed.RangeFeed, error) {
+	tableID, err := sysTableResolver.LookupSystemTableID(ctx, systemschema.NotificationsTable.GetName())
+	if err != nil {
+		return nil, err
+	}
+	tablePrefix := r.codec.TablePrefix(uint32(tableID))
+	tableSpan := roachpb.Span{
+		Key:    tablePrefix,
+		EndKey: tablePrefix.PrefixEnd(),
+	}
+	onValue := func(ctx context.Context, kv *roachpb.RangeFeedValue) {
+		if !kv.Value.IsPresent() {
+			// The notification was deleted.
+			return
+		}
+		n, id, err := r.decoder.decodeRow(&kv.Value)
+		if err != nil {
+			log.Warningf(ctx, "failed to decode notification %v: %v", kv.Key, err)
+			return
+		}
+		r.deliver(ctx, n, id, kv.Value.Timestamp)
+	}
+	// The rangefeed outlives the session which started it, so it must not be
+	// canceled with its context. It is closed by the stopper instead.
+	ctx = logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	return r.rangeFeedFactory.RangeFeed(
+		ctx, "notifications", []roachpb.Span{tableSpan}, r.clock.Now(), onValue,
+		rangefeed.WithOnFrontierAdvance(r.forgetDelivered),
+	)
+}
+
+// deliver dispatches a notification emitted by the rangefeed at the given
+// timestamp, unless it was already delivered.
+func (r *Registry) deliver(
+	ctx context.Context, n Notification, id notificationID, ts hlc.Timestamp,
+) {
+	if _, ok := r.delivered[id]; ok {
+		return
+	}
+	r.delivered[id] = ts
+	r.dispatch(ctx, n, ts)
+}
+
+// forgetDelivered forgets the delivered notifications whose timestamp is
+// below the frontier of the rangefeed.
+func (r *Registry) forgetDelivered(_ context.Context, frontier hlc.Timestamp) {
+	for id, ts := range r.delivered {
+		if ts.Less(frontier) {
+			delete(r.delivered, id)
+		}
+	}
+}
+
+// dispatch queues a notification committed at the given timestamp for the
+// listeners of its channel.
+func (r *Registry) dispatch(ctx context.Context, n Notification, ts hlc.Timestamp) {
+	r.mu.Lock()
+	var toSignal []*Listener
+	for l := range r.mu.listeners {
+		if l.enqueue(ctx, n, ts) {
+			toSignal = append(toSignal, l)
+		}
+	}
+	r.mu.Unlock()
+	for _, l := range toSignal {
+		l.onNotify()
+	}
+}
+
+// Listener receives the notifications sent on the channels that a session
+// listens on.
+type Listener struct {
+	r        *Registry
+	onNotify func()
+
+	mu struct {
+		syncutil.Mutex
+		// channels maps the channels listened on to the time at which the
+		// listener started listening on them. Notifications committed before
+		// that time are ignored.
+		channels map[string]hlc.Timestamp
+		// pending are the notifications which have not been delivered to the
+		// client yet.
+		pending []Notification
+		// signaled is set once onNotify has been called for the pending
+		// notifications, and reset when they are taken.
+		signaled bool
+		// dropped is set once a notification was dropped because too many
+		// notifications were pending.
+		dropped bool
+	}
+}
+
+// Listen starts listening on a channel. It is a no-op if the listener is
+// already listening on the channel.
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	l.mu.Lock()
+	_, ok := l.mu.channels[channel]
+	first := len(l.mu.channels) == 0
+	l.mu.Unlock()
+	if ok {
+		return nil
+	}
+	if first {
+		if err := l.r.register(ctx, l); err != nil {
+			return err
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.channels[channel] = l.r.clock.Now()
+	return nil
+}
+
+// Unlisten stops listening on a channel.
+func (l *Listener) Unlisten(channel string) {
+	l.mu.Lock()
+	delete(l.mu.channels, channel)
+	empty := len(l.mu.channels) == 0
+	l.mu.Unlock()
+	if empty {
+		l.r.unregister(l)
+	}
+}
+
+// UnlistenAll stops listening on all channels.
+func (l *Listener) UnlistenAll() {
+	l.mu.Lock()
+	for channel := range l.mu.channels {
+		delete(l.mu.channels, channel)
+	}
+	l.mu.Unlock()
+	l.r.unregister(l)
+}
+
+// TakePending returns the pending notifications, in the order in which they
+// were received, and removes them from the listener.
+func (l *Listener) TakePending() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.mu.pending
+	l.mu.pending = nil
+	l.mu.signaled = false
+	l.mu.dropped = false
+	return pending
+}
+
+// Close stops listening on all channels and discards the pending
+// notifications.
+func (l *Listener) Close() {
+	l.UnlistenAll()
+	_ = l.TakePending()
+}
+
+// enqueue queues a notification committed at the given timestamp if the
+// listener listens on its channel. It returns true if onNotify needs to be
+// called.
+func (l *Listener) enqueue(ctx context.Context, n Notification, ts hlc.Timestamp) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	listenTS, ok := l.mu.channels[n.Channel]
+	if !ok || ts.Less(listenTS) {
+		return false
+	}
+	if len(l.mu.pending) >= maxPendingNotifications {
+		if !l.mu.dropped {
+			log.Warningf(ctx, "dropping notifications on channel %q: too many pending notifications", n.Channel)
+			l.mu.dropped = true
+		}
+		return false
+	}
+	l.mu.pending = append(l.mu.pending, n)
+	if l.mu.signaled {
+		return false
+	}
+	l.mu.signaled = true
+	return true
+}
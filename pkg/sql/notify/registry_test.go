@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryDispatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	r := NewRegistry(keys.SystemSQLCodec, nil, nil, nil, nil, nil)
+	// listen registers l on a channel at the given wall time, without starting
+	// the rangefeed.
+	listen := func(l *Listener, channel string, wallTime int64) {
+		r.mu.Lock()
+		r.mu.listeners[l] = struct{}{}
+		r.mu.Unlock()
+		l.mu.Lock()
+		l.mu.channels[channel] = hlc.Timestamp{WallTime: wallTime}
+		l.mu.Unlock()
+	}
+	dispatch := func(channel, payload string, wallTime int64) {
+		r.dispatch(ctx, Notification{Channel: channel, Payload: payload, PID: 1}, hlc.Timestamp{WallTime: wallTime})
+	}
+
+	var signals1, signals2 int
+	l1 := r.NewListener(func() { signals1++ })
+	l2 := r.NewListener(func() { signals2++ })
+	listen(l1, "a", 10)
+	listen(l1, "b", 10)
+	listen(l2, "a", 20)
+
+	// Notifications committed before a listener started listening are ignored.
+	dispatch("a", "x", 15)
+	dispatch("b", "y", 25)
+	dispatch("a", "z", 30)
+	dispatch("c", "w", 30)
+	require.Equal(t, 1, signals1)
+	require.Equal(t, 1, signals2)
+	require.Equal(t, []Notification{
+		{Channel: "a", Payload: "x", PID: 1},
+		{Channel: "b", Payload: "y", PID: 1},
+		{Channel: "a", Payload: "z", PID: 1},
+	}, l1.TakePending())
+	require.Equal(t, []Notification{{Channel: "a", Payload: "z", PID: 1}}, l2.TakePending())
+	require.Empty(t, l1.TakePending())
+
+	// The listeners are signaled again once they took their pending
+	// notifications.
+	dispatch("b", "v", 40)
+	require.Equal(t, 2, signals1)
+	require.Equal(t, 1, signals2)
+
+	// Unlistening removes the channel, and the listener once it does not
+	// listen on any channel.
+	l1.Unlisten("b")
+	l2.Unlisten("a")
+	dispatch("a", "u", 50)
+	dispatch("b", "t", 50)
+	require.Equal(t, []Notification{
+		{Channel: "b", Payload: "v", PID: 1},
+		{Channel: "a", Payload: "u", PID: 1},
+	}, l1.TakePending())
+	require.Empty(t, l2.TakePending())
+	r.mu.Lock()
+	require.Len(t, r.mu.listeners, 1)
+	r.mu.Unlock()
+
+	l1.Close()
+	dispatch("a", "s", 60)
+	require.Empty(t, l1.TakePending())
+	r.mu.Lock()
+	require.Empty(t, r.mu.listeners)
+	r.mu.Unlock()
+}
+
+func TestRegistryDeduplicate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	r := NewRegistry(keys.SystemSQLCodec, nil, nil, nil, nil, nil)
+	var signals int
+	l := r.NewListener(func() { signals++ })
+	r.mu.listeners[l] = struct{}{}
+	l.mu.channels["a"] = hlc.Timestamp{WallTime: 10}
+
+	txnID := uuid.MakeV4()
+	deliver := func(payload string, seq int64, wallTime int64) {
+		r.deliver(ctx, Notification{Channel: "a", Payload: payload, PID: 1},
+			notificationID{txnID: txnID, seq: seq}, hlc.Timestamp{WallTime: wallTime})
+	}
+
+	// The rows emitted again by the rangefeed are ignored.
+	deliver("x", 0, 20)
+	deliver("y", 1, 20)
+	deliver("x", 0, 20)
+	require.Equal(t, 1, signals)
+	require.Equal(t, []Notification{
+		{Channel: "a", Payload: "x", PID: 1},
+		{Channel: "a", Payload: "y", PID: 1},
+	}, l.TakePending())
+
+	// The notifications are forgotten once the frontier passes them.
+	r.forgetDelivered(ctx, hlc.Timestamp{WallTime: 20})
+	require.Len(t, r.delivered, 2)
+	r.forgetDelivered(ctx, hlc.Timestamp{WallTime: 21})
+	require.Empty(t, r.delivered)
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// rowDecoder decodes rows from the notifications table.
+type rowDecoder struct {
+	alloc   tree.DatumAlloc
+	decoder valueside.Decoder
+}
+
+func makeRowDecoder() rowDecoder {
+	return rowDecoder{
+		decoder: valueside.MakeDecoder(systemschema.NotificationsTable.PublicColumns()),
+	}
+}
+
+// decodeRow decodes a row of the system.notifications table into the
+// notification and its ID. The value must be present.
+func (d *rowDecoder) decodeRow(value *roachpb.Value) (Notification, notificationID, error) {
+	// The id column, which is the only column of the index key, is not needed.
+	// The rest of the columns are stored as a family.
+	bytes, err := value.GetTuple()
+	if err != nil {
+		return Notification{}, notificationID{}, err
+	}
+	datums, err := d.decoder.Decode(&d.alloc, bytes)
+	if err != nil {
+		return Notification{}, notificationID{}, err
+	}
+	var n Notification
+	if channel := datums[1]; channel != tree.DNull {
+		n.Channel = string(tree.MustBeDString(channel))
+	}
+	if payload := datums[2]; payload != tree.DNull {
+		n.Payload = string(tree.MustBeDString(payload))
+	}
+	if pid := datums[3]; pid != tree.DNull {
+		n.PID = uint32(tree.MustBeDInt(pid))
+	}
+	var id notificationID
+	if txnID := datums[5]; txnID != tree.DNull {
+		id.txnID = tree.MustBeDUuid(txnID).UUID
+	}
+	if seq := datums[6]; seq != tree.DNull {
+		id.seq = int64(tree.MustBeDInt(seq))
+	}
+	return n, id, nil
+}
@@ -145,6 +145,17 @@ type Catalog interface {
 	// the given catalog object. If not, then CheckAnyPrivilege returns an error.
 	CheckAnyPrivilege(ctx context.Context, o Object) error
 
+	// HasColumnPrivilege returns true if the current user has the given
+	// privilege on any column of the given table through the privileges granted
+	// on the columns.
+	HasColumnPrivilege(ctx context.Context, tab Table, priv privilege.Kind) (bool, error)
+
+	// CheckColumnPrivilege verifies that the current user has the given
+	// privilege on the column of the given table with the given ordinal, either
+	// through the privileges granted on the table or on the column. If not, then
+	// CheckColumnPrivilege returns an error.
+	CheckColumnPrivilege(ctx context.Context, tab Table, ord int, priv privilege.Kind) error
+
 	// HasAdminRole checks that the current user has admin privileges. If yes,
 	// returns true. Returns an error if query on the `system.users` table failed
 	HasAdminRole(ctx context.Context) (bool, error)
@@ -172,4 +183,13 @@ type Catalog interface {
 
 	// RoleExists returns true if the role exists.
 	RoleExists(ctx context.Context, role username.SQLUsername) (bool, error)
+
+	// HasOwnership returns true if the current user owns the given object,
+	// either directly or through a role of which it is a member.
+	HasOwnership(ctx context.Context, o Object) (bool, error)
+
+	// IsMemberOfRole returns true if the current user is the given role, or a
+	// direct or indirect member of it. Every user is a member of the public
+	// role.
+	IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error)
 }
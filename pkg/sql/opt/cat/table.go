@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 )
 
 // Table is an interface to a database table, exposing only the information
@@ -49,6 +50,13 @@ type Table interface {
 	// that they cannot be mutated.
 	IsMaterializedView() bool
 
+	// IsForeignTable returns true if this table is a foreign table, whose rows
+	// are read from files in external storage. Foreign tables cannot be
+	// mutated and have no indexes besides their primary index. A constrained
+	// scan of the primary index still reads the files in full and filters
+	// their rows.
+	IsForeignTable() bool
+
 	// ColumnCount returns the number of columns in the table. This includes
 	// public columns, write-only columns, etc.
 	ColumnCount() int
@@ -137,6 +145,39 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// TriggerCount returns the number of row-level triggers defined on this
+	// table.
+	TriggerCount() int
+
+	// Trigger returns the ith row-level trigger defined on this table, where
+	// i < TriggerCount.
+	Trigger(i int) Trigger
+
+	// ExclusionConstraintCount returns the number of EXCLUDE constraints
+	// defined on this table.
+	ExclusionConstraintCount() int
+
+	// ExclusionConstraint returns the ith EXCLUDE constraint defined on this
+	// table, where i < ExclusionConstraintCount.
+	ExclusionConstraint(i int) ExclusionConstraint
+
+	// IsRowLevelSecurityEnabled returns true if the policies of this table are
+	// enforced for the users which do not own the table.
+	IsRowLevelSecurityEnabled() bool
+
+	// IsRowLevelSecurityForced returns true if the policies of this table are
+	// also enforced for the owner of the table. It is only relevant if row-level
+	// security is enabled.
+	IsRowLevelSecurityForced() bool
+
+	// PolicyCount returns the number of row-level security policies defined on
+	// this table.
+	PolicyCount() int
+
+	// Policy returns the ith row-level security policy defined on this table,
+	// where i < PolicyCount.
+	Policy(i int) Policy
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -162,6 +203,102 @@ type CheckConstraint struct {
 	Validated  bool
 }
 
+// Trigger contains the metadata of a row-level trigger defined on a table.
+// The body of the trigger is a list of SQL statements which are executed once
+// for each row modified by the triggering statement. For example, this trigger
+// records every row inserted into table a:
+//
+//   CREATE TRIGGER audit AFTER INSERT ON a FOR EACH ROW
+//   BEGIN ATOMIC INSERT INTO a_audit VALUES (NEW.k, now()); END
+//
+type Trigger struct {
+	Name string
+	// Before is true if the trigger fires for all rows before the mutation
+	// modifies any of them, and false if it fires for each row afterwards. A
+	// BEFORE trigger defined by the user can modify the new values of the row or
+	// skip the row, using a query at the end of its body.
+	Before bool
+	// OnInsert, OnUpdate and OnDelete indicate the events that fire the
+	// trigger.
+	OnInsert bool
+	OnUpdate bool
+	OnDelete bool
+	// Stmts contains the SQL text of the statements in the trigger body. It is
+	// empty if Function is set.
+	Stmts []string
+	// Function is set if the trigger executes a trigger function for each row,
+	// instead of the statements of a body.
+	Function *TriggerFunction
+	// MaintainsView is true if the trigger is not defined by the user, but
+	// synthesized to incrementally maintain a materialized view which depends
+	// on the table. The statements of such triggers are allowed to mutate the
+	// materialized view, and are not subject to privilege checks.
+	MaintainsView bool
+}
+
+// TriggerFunction identifies the trigger function executed by a trigger. The
+// function belongs to the database of the table on which the trigger is
+// defined, and has no arguments.
+type TriggerFunction struct {
+	// ID is the ID of the descriptor of the function.
+	ID   StableID
+	Name string
+}
+
+// ExclusionConstraint contains the metadata of an EXCLUDE constraint defined
+// on a table. The constraint guarantees that, for any two rows of the table,
+// at least one of its operators returns false or NULL when comparing the
+// columns of the rows. For example, this constraint ensures that no two rows
+// have overlapping geometries in the same zone:
+//
+//   CREATE TABLE a (zone INT, g GEOMETRY, EXCLUDE (zone WITH =, g WITH &&))
+//
+type ExclusionConstraint struct {
+	Name string
+	// ColumnOrdinals contains the table column ordinals of the columns compared
+	// by the constraint.
+	ColumnOrdinals []int
+	// Operators contains the operator used to compare each column, in the same
+	// order as ColumnOrdinals. It is either treecmp.EQ or treecmp.Overlaps.
+	Operators []treecmp.ComparisonOperatorSymbol
+	// Predicate is the partial predicate expression of the constraint, or the
+	// empty string if the constraint applies to all rows.
+	Predicate string
+	// Validated is true if the existing data is known to satisfy the
+	// constraint.
+	Validated bool
+}
+
+// Policy contains the metadata of a row-level security policy defined on a
+// table. Once row-level security is enabled on the table, the rows which can
+// be read or written by a user are restricted by the expressions of the
+// policies which apply to the user. For example, this policy only allows
+// users to see the rows of table a which they own:
+//
+//   CREATE POLICY own ON a FOR SELECT USING (owner = current_user)
+//
+type Policy struct {
+	Name string
+	// Restrictive is true if all rows must satisfy the policy, and false if
+	// rows must satisfy at least one of the permissive policies.
+	Restrictive bool
+	// OnSelect, OnInsert, OnUpdate and OnDelete indicate the commands to which
+	// the policy applies.
+	OnSelect bool
+	OnInsert bool
+	OnUpdate bool
+	OnDelete bool
+	// Roles contains the names of the roles to which the policy applies. The
+	// public role stands for all users.
+	Roles []string
+	// UsingExpr is the expression which existing rows must satisfy, or the
+	// empty string.
+	UsingExpr string
+	// WithCheckExpr is the expression which new rows must satisfy, or the empty
+	// string, in which case new rows must satisfy UsingExpr.
+	WithCheckExpr string
+}
+
 // TableStatistic is an interface to a table statistic. Each statistic is
 // associated with a set of columns.
 type TableStatistic interface {
@@ -265,6 +402,11 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether checking the constraint can be deferred to
+	// the end of the transaction. Since a deferrable constraint can be violated
+	// by the data visible to a transaction, it is never considered validated.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -310,6 +452,11 @@ type UniqueConstraint interface {
 	// satisfied when building functional dependencies for the table. This enables
 	// additional optimizations, such as omission of uniqueness checks.
 	UniquenessGuaranteedByAnotherIndex() bool
+
+	// Deferrability returns whether checking the constraint can be deferred to
+	// the end of the transaction. Since a deferrable constraint can be violated
+	// by the data visible to a transaction, it is never considered validated.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
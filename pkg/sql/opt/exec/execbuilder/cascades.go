@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
@@ -153,7 +154,51 @@ func makeCascadeBuilder(b *Builder, mutationWithID opt.WithID) (*cascadeBuilder,
 }
 
 // setupCascade fills in an exec.Cascade struct for the given cascade.
-func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
+func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) (exec.Cascade, error) {
+	if cascade.TriggerFunction != nil {
+		oldOrdinals, err := cb.bufferOrdinals(cascade.OldValues)
+		if err != nil {
+			return exec.Cascade{}, err
+		}
+		newOrdinals, err := cb.bufferOrdinals(cascade.NewValues)
+		if err != nil {
+			return exec.Cascade{}, err
+		}
+		return exec.Cascade{
+			FKName:          cascade.FKName,
+			Buffer:          cb.mutationBuffer,
+			ForEachRow:      true,
+			TriggerFunction: cascade.TriggerFunction,
+			TriggerTable:    cascade.TriggerTable,
+			OldOrdinals:     oldOrdinals,
+			NewOrdinals:     newOrdinals,
+		}, nil
+	}
+	if cascade.ForEachRow {
+		var returnOrdinals []int
+		if cascade.ReturnsRow {
+			var err error
+			if returnOrdinals, err = cb.bufferOrdinals(cascade.ReturnCols); err != nil {
+				return exec.Cascade{}, err
+			}
+		}
+		return exec.Cascade{
+			FKName:         cascade.FKName,
+			Buffer:         cb.mutationBuffer,
+			ForEachRow:     true,
+			Before:         cascade.Before,
+			ModifiesRows:   cascade.ModifiesRows,
+			ReturnsRow:     cascade.ReturnsRow,
+			ReturnOrdinals: returnOrdinals,
+			PrepareRowFn: func(
+				ctx context.Context,
+				semaCtx *tree.SemaContext,
+				evalCtx *eval.Context,
+			) (exec.PlanRowFn, error) {
+				return cb.prepareTrigger(ctx, semaCtx, evalCtx, cascade)
+			},
+		}, nil
+	}
 	return exec.Cascade{
 		FKName: cascade.FKName,
 		Buffer: cb.mutationBuffer,
@@ -170,7 +215,24 @@ func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
 				ctx, semaCtx, evalCtx, execFactory, cascade, bufferRef, numBufferedRows, allowAutoCommit,
 			)
 		},
+	}, nil
+}
+
+// bufferOrdinals returns the ordinals in the mutation buffer of the given
+// columns of the original memo.
+func (cb *cascadeBuilder) bufferOrdinals(cols opt.ColList) ([]int, error) {
+	if len(cols) == 0 {
+		return nil, nil
+	}
+	ords := make([]int, len(cols))
+	for i, col := range cols {
+		ord, ok := cb.mutationBufferCols.Get(int(col))
+		if !ok {
+			return nil, errors.AssertionFailedf("column %d is not in the mutation buffer", col)
+		}
+		ords[i] = ord
 	}
+	return ords, nil
 }
 
 // planCascade is used to plan a cascade query. It is NOT run while
@@ -306,6 +368,117 @@ func (cb *cascadeBuilder) planCascade(
 	return plan, nil
 }
 
+// prepareTrigger is used to plan a row-level trigger query. Like planCascade,
+// it is run by the execution logic (through exec.Cascade.PrepareRowFn) after
+// the main query was executed.
+//
+// The process is similar to planCascade, except that the trigger query does not
+// read the buffered input through a WithScan. Instead, the memo.CascadeBuilder
+// builds a query in which the old and new values of the row are outer columns.
+// This query is built only once; for each row, it is copied into a new memo,
+// replacing these columns with the values in the row. For example:
+//
+//   CREATE TRIGGER audit AFTER INSERT ON parent FOR EACH ROW
+//   BEGIN ATOMIC INSERT INTO audit VALUES (NEW.p); END
+//
+// builds an insert into the audit table from a VALUES expression which refers
+// to the new value of column p. After replacement, that expression contains the
+// constant value of p in the given row.
+func (cb *cascadeBuilder) prepareTrigger(
+	ctx context.Context, semaCtx *tree.SemaContext, evalCtx *eval.Context, cascade *memo.FKCascade,
+) (exec.PlanRowFn, error) {
+	// 1. Set up a memo in which to build the trigger query, with metadata for
+	// the buffer columns. This memo is retained by the returned function.
+	var o xform.Optimizer
+	o.Init(evalCtx, cb.b.catalog)
+	factory := o.Factory()
+	md := factory.Metadata()
+
+	// rowColMap is the mapping between the column IDs in the new memo and the
+	// column ordinal in the row.
+	var rowColMap opt.ColMap
+	var withColRemap opt.ColMap
+	for i := range cb.colMeta {
+		id := md.AddColumn(cb.colMeta[i].Alias, cb.colMeta[i].Type)
+		ordinal, _ := cb.mutationBufferCols.Get(int(cb.colMeta[i].MetaID))
+		rowColMap.Set(int(id), ordinal)
+		withColRemap.Set(int(cb.colMeta[i].MetaID), int(id))
+	}
+	oldVals, err := remapColumns(cascade.OldValues, withColRemap)
+	if err != nil {
+		return nil, err
+	}
+	newVals, err := remapColumns(cascade.NewValues, withColRemap)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Invoke the memo.CascadeBuilder to build the trigger query.
+	relExpr, err := cascade.Builder.Build(
+		ctx,
+		semaCtx,
+		evalCtx,
+		cb.b.catalog,
+		factory,
+		0,   /* binding */
+		nil, /* bindingProps */
+		oldVals,
+		newVals,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while building trigger %q", cascade.FKName)
+	}
+
+	// The row returned by the last statement of a BEFORE trigger consists of all
+	// the output columns of the statement, in increasing order (see
+	// optbuilder.triggerBuilder).
+	required := &physical.Required{}
+	if cascade.ReturnsRow {
+		relExpr.Relational().OutputCols.ForEach(func(col opt.ColumnID) {
+			required.Presentation = append(required.Presentation, opt.AliasedColumn{
+				Alias: md.ColumnMeta(col).Alias,
+				ID:    col,
+			})
+		})
+	}
+
+	return func(
+		ctx context.Context, evalCtx *eval.Context, execFactory exec.Factory, row tree.Datums,
+	) (exec.Plan, error) {
+		// 3. Copy the expression into a new memo, replacing each reference to the
+		// old or new values with the corresponding value from the row.
+		var rowOpt xform.Optimizer
+		rowOpt.Init(evalCtx, cb.b.catalog)
+		f := rowOpt.Factory()
+		var replaceFn norm.ReplaceFunc
+		replaceFn = func(e opt.Expr) opt.Expr {
+			if t, ok := e.(*memo.VariableExpr); ok {
+				if ord, ok := rowColMap.Get(int(t.Col)); ok {
+					return f.ConstructConstVal(row[ord], t.Typ)
+				}
+			}
+			return f.CopyAndReplaceDefault(e, replaceFn)
+		}
+		f.CopyAndReplace(relExpr, required, replaceFn)
+
+		// 4. Optimize the expression.
+		optimizedExpr, err := rowOpt.Optimize()
+		if err != nil {
+			return nil, errors.Wrapf(err, "while optimizing trigger %q", cascade.FKName)
+		}
+
+		// 5. Execbuild the optimized expression. Trigger queries never commit the
+		// transaction, since the remaining rows still need to be processed.
+		eb := New(execFactory, &rowOpt, f.Memo(), cb.b.catalog, optimizedExpr, evalCtx, false /* allowAutoCommit */)
+		eb.disableTelemetry = true
+		plan, err := eb.Build()
+		if err != nil {
+			return nil, errors.Wrapf(err, "while building trigger %q plan", cascade.FKName)
+		}
+		return plan, nil
+	}, nil
+}
+
 // Remap columns according to a ColMap.
 func remapColumns(cols opt.ColList, m opt.ColMap) (opt.ColList, error) {
 	res := make(opt.ColList, len(cols))
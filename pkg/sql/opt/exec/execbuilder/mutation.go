@@ -71,6 +71,22 @@ func (b *Builder) buildMutationInput(
 
 		b.addBuiltWithExpr(p.WithID, input.outputCols, bufferNode)
 		input.root = bufferNode
+
+		if p.FKCascades.HasBeforeTriggers() {
+			// BEFORE triggers must run after the mutation input is buffered but
+			// before any rows are modified. Add the buffer as a subquery so that it
+			// gets executed ahead of time (like a With expression), and have the
+			// mutation read from it.
+			b.subqueries = append(b.subqueries, exec.Subquery{
+				Mode:     exec.SubqueryAllRows,
+				Root:     bufferNode,
+				RowCount: int64(inputExpr.Relational().Stats.RowCountIfAvailable()),
+			})
+			input.root, err = b.factory.ConstructScanBuffer(bufferNode, label)
+			if err != nil {
+				return execPlan{}, err
+			}
+		}
 	}
 	return input, nil
 }
@@ -123,6 +139,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -156,6 +176,11 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	// We cannot use the fast path if any triggers need to run.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
@@ -169,6 +194,10 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability() != tree.ConstraintNotDeferrable {
+			// The check may need to be deferred to the end of the transaction.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -490,8 +519,15 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	cnt := len(del.FetchCols) + len(del.PassthroughCols) + len(del.PartialIndexDelCols)
+	colList := make(opt.ColList, 0, cnt)
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
@@ -504,11 +540,22 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols colinfo.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, colinfo.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
 	)
 	if err != nil {
@@ -697,9 +744,29 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
+			if c.Exclusion {
+				return mkExclusionCheckErr(md, c, keyVals)
+			}
+			if c.RowLevelSecurity {
+				return mkRowLevelSecurityCheckErr(md, c)
+			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		var deferrable *exec.DeferrableCheck
+		tabMeta := md.TableMeta(c.Table)
+		// EXCLUDE constraints and row-level security checks are never
+		// deferrable.
+		if !c.Exclusion && !c.RowLevelSecurity {
+			if uc := tabMeta.Table.Unique(c.CheckOrdinal); uc.Deferrability() != tree.ConstraintNotDeferrable {
+				deferrable = &exec.DeferrableCheck{
+					ConstraintName:    uc.Name(),
+					InitiallyDeferred: uc.Deferrability() == tree.ConstraintInitiallyDeferred,
+					TableID:           tabMeta.Table.ID(),
+					KeyCols:           query.getNodeColumnOrdinals(c.KeyCols),
+				}
+			}
+		}
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -725,7 +792,7 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			}
 			return mkFKCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, b.deferrableFKCheck(c, query))
 		if err != nil {
 			return err
 		}
@@ -734,6 +801,36 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// deferrableFKCheck returns the exec.DeferrableCheck for the given FK check, or
+// nil if the check cannot be deferred. As in Postgres, checks for removed
+// values are never deferred if the constraint uses the RESTRICT action.
+func (b *Builder) deferrableFKCheck(c *memo.FKChecksItem, query execPlan) *exec.DeferrableCheck {
+	md := b.mem.Metadata()
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+		action := fk.UpdateReferenceAction()
+		if c.OpName == "delete" {
+			action = fk.DeleteReferenceAction()
+		}
+		if action == tree.Restrict {
+			return nil
+		}
+	}
+	if fk.Deferrability() == tree.ConstraintNotDeferrable {
+		return nil
+	}
+	return &exec.DeferrableCheck{
+		ConstraintName:    fk.Name(),
+		InitiallyDeferred: fk.Deferrability() == tree.ConstraintInitiallyDeferred,
+		ForeignKey:        true,
+		TableID:           fk.OriginTableID(),
+		KeyCols:           query.getNodeColumnOrdinals(c.KeyCols),
+	}
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -776,6 +873,54 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 	)
 }
 
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values that correspond to the
+// cat.ExclusionConstraint columns.
+func mkExclusionCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.ExclusionConstraint(c.CheckOrdinal)
+	var msg, details bytes.Buffer
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (k)=(2) conflicts with existing key.
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, ec.Name)
+
+	details.WriteString("Key (")
+	for i, ord := range ec.ColumnOrdinals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(string(tabMeta.Table.Column(ord).ColName()))
+	}
+	details.WriteString(")=(")
+	for i, d := range keyVals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+	details.WriteString(") conflicts with existing key.")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			ec.Name,
+		),
+		details.String(),
+	)
+}
+
+// mkRowLevelSecurityCheckErr generates an error describing a row written by a
+// mutation which does not satisfy the row-level security policies of the
+// table.
+func mkRowLevelSecurityCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem) error {
+	tabMeta := md.TableMeta(c.Table)
+	return pgerror.Newf(pgcode.InsufficientPrivilege,
+		"new row violates row-level security policy for table %q", string(tabMeta.Table.Name()))
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
@@ -877,8 +1022,24 @@ func (b *Builder) buildFKCascades(withID opt.WithID, cascades memo.FKCascades) e
 	if err != nil {
 		return err
 	}
+	// BEFORE triggers which modify the rows of the mutation read their own
+	// binding of the mutation input.
+	var beforeCB *cascadeBuilder
 	for i := range cascades {
-		b.cascades = append(b.cascades, cb.setupCascade(&cascades[i]))
+		cascadeCB := cb
+		if cascades[i].ModifiesRows {
+			if beforeCB == nil {
+				if beforeCB, err = makeCascadeBuilder(b, cascades[i].WithID); err != nil {
+					return err
+				}
+			}
+			cascadeCB = beforeCB
+		}
+		cascade, err := cascadeCB.setupCascade(&cascades[i])
+		if err != nil {
+			return err
+		}
+		b.cascades = append(b.cascades, cascade)
 	}
 	return nil
 }
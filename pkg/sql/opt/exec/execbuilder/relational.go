@@ -111,6 +111,16 @@ func (ep *execPlan) getNodeColumnOrdinal(col opt.ColumnID) exec.NodeColumnOrdina
 	return exec.NodeColumnOrdinal(ord)
 }
 
+// getNodeColumnOrdinals maps each column in cols to its ordinal in the
+// node's output columns.
+func (ep *execPlan) getNodeColumnOrdinals(cols opt.ColList) []exec.NodeColumnOrdinal {
+	res := make([]exec.NodeColumnOrdinal, len(cols))
+	for i, col := range cols {
+		res[i] = ep.getNodeColumnOrdinal(col)
+	}
+	return res
+}
+
 func (ep *execPlan) getNodeColumnOrdinalSet(cols opt.ColSet) exec.NodeColumnOrdinalSet {
 	var res exec.NodeColumnOrdinalSet
 	cols.ForEach(func(colID opt.ColumnID) {
@@ -245,6 +255,10 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.Max1RowExpr:
 		ep, err = b.buildMax1Row(t)
 
+	case *memo.BarrierExpr:
+		// Barrier only restricts the optimizer; it has no effect on execution.
+		ep, err = b.buildRelational(t.Input)
+
 	case *memo.ProjectSetExpr:
 		ep, err = b.buildProjectSet(t)
 
@@ -642,6 +656,7 @@ func (b *Builder) scanParams(
 		Locking:            locking,
 		EstimatedRowCount:  rowCount,
 		LocalityOptimized:  scan.LocalityOptimized,
+		Policies:           b.mem.Metadata().TableMeta(scan.Table).Policies,
 	}, outputMap, nil
 }
 
@@ -1314,14 +1329,20 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			agg = aggDistinct.Input
 		}
 
-		name, _ := memo.FindAggregateOverload(agg)
+		name, overload := memo.FindAggregateOverload(agg)
+
+		// The arguments of a user-defined aggregate are stored in a list.
+		var args opt.Expr = agg
+		if uda, ok := agg.(*memo.UserDefinedAggExpr); ok {
+			args = &uda.Args
+		}
 
 		// Accumulate variable arguments in argCols and constant arguments in
 		// constArgs. Constant arguments must follow variable arguments.
 		var argCols []exec.NodeColumnOrdinal
 		var constArgs tree.Datums
-		for j, n := 0, agg.ChildCount(); j < n; j++ {
-			child := agg.Child(j)
+		for j, n := 0, args.ChildCount(); j < n; j++ {
+			child := args.Child(j)
 			if variable, ok := child.(*memo.VariableExpr); ok {
 				if len(constArgs) != 0 {
 					return execPlan{}, errors.Errorf("constant args must come after variable args")
@@ -1336,12 +1357,13 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 		}
 
 		aggInfos[i] = exec.AggInfo{
-			FuncName:   name,
-			Distinct:   distinct,
-			ResultType: item.Agg.DataType(),
-			ArgCols:    argCols,
-			ConstArgs:  constArgs,
-			Filter:     filterOrd,
+			FuncName:    name,
+			Distinct:    distinct,
+			ResultType:  item.Agg.DataType(),
+			ArgCols:     argCols,
+			ConstArgs:   constArgs,
+			Filter:      filterOrd,
+			UserDefined: overload.UserDefinedAggregate,
 		}
 		ep.outputCols.Set(int(item.Col), len(groupingColIdx)+i)
 	}
@@ -70,6 +70,7 @@ func (b *Builder) buildCreateView(cv *memo.CreateViewExpr) (execPlan, error) {
 		cv.Deps,
 		cv.TypeDeps,
 		cv.WithData,
+		cv.IncrementalRefresh,
 	)
 	return execPlan{root: root}, err
 }
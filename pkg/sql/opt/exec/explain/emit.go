@@ -100,8 +100,21 @@ func Emit(plan *Plan, ob *OutputBuilder, spanFormatFn SpanFormatFn) error {
 	}
 
 	for i := range plan.Cascades {
-		ob.EnterMetaNode("fk-cascade")
-		ob.Attr("fk", plan.Cascades[i].FKName)
+		if plan.Cascades[i].ForEachRow {
+			ob.EnterMetaNode("trigger")
+			ob.Attr("name", plan.Cascades[i].FKName)
+			if plan.Cascades[i].Before {
+				ob.Attr("timing", "before")
+			} else {
+				ob.Attr("timing", "after")
+			}
+			if fn := plan.Cascades[i].TriggerFunction; fn != nil {
+				ob.Attr("function", fn.Name)
+			}
+		} else {
+			ob.EnterMetaNode("fk-cascade")
+			ob.Attr("fk", plan.Cascades[i].FKName)
+		}
 		if buffer := plan.Cascades[i].Buffer; buffer != nil {
 			ob.Attr("input", buffer.(*Node).args.(*bufferArgs).Label)
 		}
@@ -499,6 +512,9 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		if a.Params.Parallelize {
 			ob.VAttr("parallel", "")
 		}
+		if len(a.Params.Policies) > 0 {
+			ob.Attr("policies", strings.Join(a.Params.Policies, ", "))
+		}
 		e.emitLockingPolicy(a.Params.Locking)
 
 	case valuesOp:
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -472,6 +473,10 @@ func (u *unknownTable) IsMaterializedView() bool {
 	return false
 }
 
+func (u *unknownTable) IsForeignTable() bool {
+	return false
+}
+
 func (u *unknownTable) ColumnCount() int {
 	return 0
 }
@@ -544,6 +549,38 @@ func (u *unknownTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("not implemented"))
 }
 
+func (u *unknownTable) TriggerCount() int {
+	return 0
+}
+
+func (u *unknownTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
+func (u *unknownTable) ExclusionConstraintCount() int {
+	return 0
+}
+
+func (u *unknownTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
+func (u *unknownTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+func (u *unknownTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+func (u *unknownTable) PolicyCount() int {
+	return 0
+}
+
+func (u *unknownTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
 func (u *unknownTable) Zone() cat.Zone {
 	return cat.EmptyZone()
 }
@@ -657,6 +694,10 @@ func (u *unknownIndex) GeoConfig() geoindex.Config {
 	return geoindex.Config{}
 }
 
+func (u *unknownIndex) VectorConfig() vecindex.Config {
+	return vecindex.Config{}
+}
+
 func (u *unknownIndex) Version() descpb.IndexDescriptorVersion {
 	return descpb.LatestIndexDescriptorVersion
 }
@@ -70,6 +70,10 @@ type ScanParams struct {
 	// to work correctly, the execution engine must create a local DistSQL plan
 	// for the main query (subqueries and postqueries need not be local).
 	LocalityOptimized bool
+
+	// Policies contains the names of the row-level security policies which
+	// restrict the rows returned by the scan. It is only used by EXPLAIN.
+	Policies []string
 }
 
 // OutputOrdering indicates the required output ordering on a Node that is being
@@ -152,6 +156,10 @@ type AggInfo struct {
 	// Filter is the index of the column, if any, which should be used as the
 	// FILTER condition for the aggregate. If there is no filter, Filter is -1.
 	Filter NodeColumnOrdinal
+
+	// UserDefined is set if the aggregate was created with CREATE AGGREGATE,
+	// in which case it holds the definition of the aggregate.
+	UserDefined tree.UserDefinedAggregateOverload
 }
 
 // WindowInfo represents the information about a window function that must be
@@ -245,8 +253,67 @@ type Cascade struct {
 		numBufferedRows int,
 		allowAutoCommit bool,
 	) (Plan, error)
+
+	// ForEachRow is set if the cascade is a row-level trigger. In this case
+	// FKName is the name of the trigger and PrepareRowFn must be used instead
+	// of PlanFn.
+	ForEachRow bool
+
+	// Before is set if the cascade is a row-level trigger that must be executed
+	// before the main query. In this case the mutation input is buffered ahead
+	// of time, in a subquery.
+	Before bool
+
+	// ModifiesRows is set if the cascade is a statement of a BEFORE trigger
+	// which can modify or skip the rows written by the mutation. In this case
+	// Buffer is the With binding which buffers the mutation input before the
+	// computed columns and checks are added to it, and the trigger must be
+	// executed as soon as the subquery which fills it has run. The rows left in
+	// the buffer once the trigger has been executed for all of them are the rows
+	// which are written by the mutation.
+	ModifiesRows bool
+
+	// ReturnsRow is set if the cascade is the last statement of a trigger with
+	// ModifiesRows set, and returns the row to be written. If the statement
+	// returns no rows, the row is removed from Buffer. Otherwise, the values of
+	// the returned row replace the values at ReturnOrdinals in the buffered row.
+	ReturnsRow bool
+
+	// ReturnOrdinals contains the ordinals of the columns in Buffer which are
+	// replaced by the row returned by the statement, if ReturnsRow is set.
+	ReturnOrdinals []int
+
+	// PrepareRowFn builds the trigger query once for the whole mutation input,
+	// and returns a function which creates the plan for a single row of the
+	// input. It is only set if ForEachRow is set.
+	PrepareRowFn func(
+		ctx context.Context,
+		semaCtx *tree.SemaContext,
+		evalCtx *eval.Context,
+	) (PlanRowFn, error)
+
+	// TriggerFunction is set if the cascade is a row-level trigger which
+	// executes a trigger function of TriggerTable. In this case PrepareRowFn is
+	// not set: the function is called for each row of the Buffer, with the old
+	// and new values of the ordinary columns of the table found at the
+	// OldOrdinals and NewOrdinals of the row. OldOrdinals is empty for inserts,
+	// and NewOrdinals is empty for deletes.
+	TriggerFunction *cat.TriggerFunction
+	TriggerTable    cat.Table
+	OldOrdinals     []int
+	NewOrdinals     []int
 }
 
+// PlanRowFn creates the plan of a row-level trigger query for a single row of
+// the mutation input. The row contains the values of the columns in the Buffer
+// of the trigger's Cascade.
+type PlanRowFn func(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	execFactory Factory,
+	row tree.Datums,
+) (Plan, error)
+
 // InsertFastPathFKCheck contains information about a foreign key check to be
 // performed by the insert fast-path (see ConstructInsertFastPath). It
 // identifies the index into which we can perform the lookup.
@@ -271,6 +338,32 @@ type InsertFastPathFKCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableCheck contains information about a foreign key or uniqueness check
+// for a DEFERRABLE constraint (see ConstructErrorIfRows). If the constraint is
+// deferred in the current transaction, the rows returned by the check query are
+// not an error; instead, their keys are re-checked when the transaction
+// commits.
+type DeferrableCheck struct {
+	// ConstraintName is the name of the constraint.
+	ConstraintName string
+
+	// InitiallyDeferred is true if the constraint is deferred unless the
+	// transaction has made it immediate with SET CONSTRAINTS.
+	InitiallyDeferred bool
+
+	// ForeignKey is true if the constraint is a foreign key constraint, and
+	// false if it is a unique constraint.
+	ForeignKey bool
+
+	// TableID is the table on which the constraint is defined (the origin table
+	// for foreign keys).
+	TableID cat.StableID
+
+	// KeyCols contains the columns of the check query that hold the values of
+	// the constraint columns, in constraint order.
+	KeyCols []NodeColumnOrdinal
+}
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
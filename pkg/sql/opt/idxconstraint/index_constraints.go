@@ -192,6 +192,10 @@ func (c *indexConstraintCtx) makeSpansForSingleColumn(
 func (c *indexConstraintCtx) makeSpansForSingleColumnDatum(
 	offset int, op opt.Operator, datum tree.Datum, out *constraint.Constraint,
 ) (tight bool) {
+	if c.colType(offset).Family() == types.RangeFamily &&
+		(op == opt.ContainsOp || op == opt.OverlapsOp) {
+		return c.makeSpansForRangeColumn(offset, op, datum, out)
+	}
 	if !c.verifyType(offset, datum.ResolvedType()) {
 		c.unconstrained(offset, out)
 		return false
@@ -347,6 +351,88 @@ func (c *indexConstraintCtx) makeSpansForSingleColumnDatum(
 	return false
 }
 
+// makeSpansForRangeColumn creates spans for a range column from a containment
+// (@>) or overlap (&&) expression with a constant value on the right-hand
+// side. Ranges are ordered by their lower bounds first, so the spans only
+// constrain the lower bound of the column, and the upper bound is left to the
+// remaining filter. For example, (a @> 5) becomes (/'empty' - /'[5,)'], since
+// the empty range does not contain anything and a range containing 5 must
+// have a lower bound less than or equal to 5.
+func (c *indexConstraintCtx) makeSpansForRangeColumn(
+	offset int, op opt.Operator, datum tree.Datum, out *constraint.Constraint,
+) (tight bool) {
+	if datum == tree.DNull {
+		c.contradiction(offset, out)
+		return true
+	}
+	typ := c.colType(offset)
+	// maxLower is the largest lower bound of a range that can satisfy the
+	// expression, or DNull if only ranges with an infinite lower bound can.
+	var maxLower tree.Datum
+	switch op {
+	case opt.ContainsOp:
+		if r, ok := tree.AsDRange(datum); ok {
+			if !c.verifyType(offset, r.ResolvedType()) {
+				c.unconstrained(offset, out)
+				return false
+			}
+			if r.Empty {
+				// Every range contains the empty range.
+				c.makeNotNullSpan(offset, out)
+				return true
+			}
+			maxLower = r.Lower
+		} else {
+			if !typ.RangeContents().Equivalent(datum.ResolvedType()) {
+				c.unconstrained(offset, out)
+				return false
+			}
+			maxLower = datum
+		}
+
+	case opt.OverlapsOp:
+		r, ok := tree.AsDRange(datum)
+		if !ok || !c.verifyType(offset, r.ResolvedType()) {
+			c.unconstrained(offset, out)
+			return false
+		}
+		if r.Empty {
+			// No range overlaps the empty range.
+			c.contradiction(offset, out)
+			return true
+		}
+		if r.Upper == tree.DNull {
+			// Every non-empty range has a lower bound below an infinite upper
+			// bound.
+			c.singleSpan(
+				offset,
+				constraint.MakeKey(tree.NewDEmptyRange(typ)), excludeBoundary,
+				emptyKey, includeBoundary,
+				c.columns[offset].Descending(),
+				out,
+			)
+			return false
+		}
+		maxLower = r.Upper
+	}
+
+	// The largest range with the given lower bound is the one with an infinite
+	// upper bound.
+	end, err := tree.NewDRange(typ, maxLower, tree.DNull, maxLower != tree.DNull, false /* upperInc */)
+	if err != nil {
+		c.unconstrained(offset, out)
+		return false
+	}
+	c.singleSpan(
+		offset,
+		constraint.MakeKey(tree.NewDEmptyRange(typ)), excludeBoundary,
+		constraint.MakeKey(end), includeBoundary,
+		c.columns[offset].Descending(),
+		out,
+	)
+	return false
+}
+
 // makeSpansForTupleInequality creates spans for index columns starting at
 // <offset> from a tuple inequality.
 // Assumes that ev.Operator() is an inequality and both sides are tuples.
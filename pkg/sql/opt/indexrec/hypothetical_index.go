@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -219,6 +220,11 @@ func (hi *hypotheticalIndex) GeoConfig() geoindex.Config {
 	return geoindex.Config{}
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (hi *hypotheticalIndex) VectorConfig() vecindex.Config {
+	return vecindex.Config{}
+}
+
 // Version is part of the cat.Index interface.
 func (hi *hypotheticalIndex) Version() descpb.IndexDescriptorVersion {
 	return descpb.LatestIndexDescriptorVersion
@@ -80,6 +80,12 @@ func TryFilterInvertedIndex(
 	preFiltererState *invertedexpr.PreFiltererStateForInvertedFilterer,
 	ok bool,
 ) {
+	// Vector indexes cannot be used to filter rows, only to search for the
+	// nearest neighbors of a vector.
+	if !index.VectorConfig().IsEmpty() {
+		return nil, nil, nil, nil, false
+	}
+
 	// Attempt to constrain the prefix columns, if there are any. If they cannot
 	// be constrained to single values, the index cannot be used.
 	constraint, filters, ok = constrainPrefixColumns(
@@ -111,13 +117,20 @@ func TryFilterInvertedIndex(
 	} else {
 		col := index.InvertedColumn().InvertedSourceColumnOrdinal()
 		typ = factory.Metadata().Table(tabID).Column(col).DatumType()
-		if typ.Family() == types.StringFamily {
+		switch typ.Family() {
+		case types.StringFamily:
 			filterPlanner = &trigramFilterPlanner{
 				tabID:           tabID,
 				index:           index,
 				computedColumns: computedColumns,
 			}
-		} else {
+		case types.TSVectorFamily:
+			filterPlanner = &tsqueryFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+			}
+		default:
 			filterPlanner = &jsonOrArrayFilterPlanner{
 				tabID:           tabID,
 				index:           index,
@@ -175,7 +188,7 @@ func TryJoinInvertedIndex(
 	index cat.Index,
 	inputCols opt.ColSet,
 ) opt.ScalarExpr {
-	if !index.IsInverted() {
+	if !index.IsInverted() || !index.VectorConfig().IsEmpty() {
 		return nil
 	}
 
@@ -749,6 +749,55 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			indexOrd: jsonOrd,
 			ok:       false,
 		},
+		{
+			// JSONPathExists is supported for paths of keys. The spans are never
+			// tight, since the jsonpath is only approximated.
+			filters:          "j @? '$.a.b'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @? '$.a.b'",
+		},
+		{
+			// JSONPathExists with a filter comparing the current item to a
+			// constant is supported.
+			filters:          "j @? '$.a ? (@.b == 1)'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @? '$.a ? (@.b == 1)'",
+		},
+		{
+			// JSONPathExists with a filter that only constrains the existence
+			// of the compared items is supported.
+			filters:          "j @? 'strict $.a[*] ? (@.b > 1 && @.c starts with \"x\")'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @? 'strict $.a[*] ? (@.b > 1 && @.c starts with \"x\")'",
+		},
+		{
+			// JSONPathExists on the root item cannot constrain the index.
+			filters:  "j @? '$ ? (@ > 1)'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			// JSONPathExists with item methods isn't supported.
+			filters:  "j @? '$.a.size()'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			// JSONPathExists with paths that have too many alternatives isn't
+			// supported.
+			filters:  "j @? '$.a.b.c.d.e'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
 		{
 			// Overlaps is supported for arrays.
 			// Overlaps with a single element array produces
@@ -0,0 +1,309 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+// maxJSONPathAlternatives is the maximum number of paths through a JSON
+// document that are considered when constraining an inverted index with a
+// jsonpath. In lax mode, each accessor may or may not step into an array, so
+// the number of paths that can produce an item grows exponentially with the
+// length of the jsonpath.
+const maxJSONPathAlternatives = 16
+
+// jsonPathAlternatives is a set of paths through a JSON document. Each path
+// is a possible location of the items produced by a jsonpath.
+type jsonPathAlternatives [][]json.PathElement
+
+// extractJSONPathExistsCondition extracts an InvertedExpression representing
+// an inverted filter with the @? operator over the planner's inverted index,
+// based on the given left and right expression arguments. Returns an empty
+// InvertedExpression if no inverted filter could be extracted.
+//
+// Only jsonpaths made up of object key, [*] and filter accessors can be used
+// to constrain the index. Within filters, equality comparisons of the current
+// item with a constant are converted to containment spans, and other
+// comparisons require the compared items to exist. Because the jsonpath is
+// only approximated, the returned expression is never tight.
+func (j *jsonOrArrayFilterPlanner) extractJSONPathExistsCondition(
+	evalCtx *eval.Context, left, right opt.ScalarExpr,
+) inverted.Expression {
+	if !isIndexColumn(j.tabID, j.index, left, j.computedColumns) || !memo.CanExtractConstDatum(right) {
+		return inverted.NonInvertedColExpression{}
+	}
+	d, ok := memo.ExtractConstDatum(right).(*tree.DJsonpath)
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	path, ok := d.Expr.(jsonpath.Path)
+	if !ok {
+		path = jsonpath.Path{d.Expr}
+	}
+	if _, ok := path[0].(jsonpath.Root); !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	alts, invertedExpr, ok := applyJSONPathAccessors(evalCtx, jsonPathAlternatives{nil}, path[1:])
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	// If the path ends with a constrained filter, the filter already requires
+	// its items to exist.
+	if _, ok := path[len(path)-1].(jsonpath.Filter); !ok || invertedExpr == nil {
+		invertedExpr = andJSONPathExprs(invertedExpr, jsonPathExistsExpr(alts))
+	}
+	if invertedExpr == nil {
+		return inverted.NonInvertedColExpression{}
+	}
+	invertedExpr.SetNotTight()
+	return invertedExpr
+}
+
+// applyJSONPathAccessors returns the locations of the items produced by
+// applying the given jsonpath accessors to the items at the given locations.
+// It also returns an inverted expression for the filters among the accessors,
+// which is nil if they do not constrain the index. ok is false if the
+// accessors cannot be used to constrain the index.
+func applyJSONPathAccessors(
+	evalCtx *eval.Context, alts jsonPathAlternatives, accessors []jsonpath.Expr,
+) (_ jsonPathAlternatives, invertedExpr inverted.Expression, ok bool) {
+	for _, accessor := range accessors {
+		switch t := accessor.(type) {
+		case jsonpath.Key:
+			// In lax mode, arrays are unwrapped before the key is accessed.
+			alts = alts.maybeUnwrap().withKey(string(t))
+		case jsonpath.AnyArray:
+			alts = alts.maybeUnwrap()
+		case jsonpath.Filter:
+			// In lax mode, arrays are unwrapped before the filter is applied.
+			alts = alts.maybeUnwrap()
+			if len(alts) > maxJSONPathAlternatives {
+				return nil, nil, false
+			}
+			invertedExpr = andJSONPathExprs(invertedExpr, jsonPathCondExpr(evalCtx, alts, t.Cond))
+		default:
+			return nil, nil, false
+		}
+		if len(alts) > maxJSONPathAlternatives {
+			return nil, nil, false
+		}
+	}
+	return alts, invertedExpr, true
+}
+
+// jsonPathCondExpr returns an inverted expression that includes all JSON
+// documents for which the filter condition can be true for an item at one of
+// the given locations. It returns nil if the condition does not constrain the
+// index.
+func jsonPathCondExpr(
+	evalCtx *eval.Context, alts jsonPathAlternatives, cond jsonpath.Expr,
+) inverted.Expression {
+	switch t := cond.(type) {
+	case jsonpath.Operation:
+		switch t.Type {
+		case jsonpath.OpAnd:
+			return andJSONPathExprs(
+				jsonPathCondExpr(evalCtx, alts, t.Left), jsonPathCondExpr(evalCtx, alts, t.Right),
+			)
+		case jsonpath.OpOr:
+			left := jsonPathCondExpr(evalCtx, alts, t.Left)
+			right := jsonPathCondExpr(evalCtx, alts, t.Right)
+			if left == nil || right == nil {
+				return nil
+			}
+			return inverted.Or(left, right)
+		case jsonpath.OpEqual:
+			if val, ok := t.Right.(jsonpath.Scalar); ok {
+				if expr := jsonPathEqExpr(evalCtx, alts, t.Left, val.Val); expr != nil {
+					return expr
+				}
+			}
+			if val, ok := t.Left.(jsonpath.Scalar); ok {
+				if expr := jsonPathEqExpr(evalCtx, alts, t.Right, val.Val); expr != nil {
+					return expr
+				}
+			}
+			fallthrough
+		case jsonpath.OpNotEqual, jsonpath.OpLess, jsonpath.OpLessEqual, jsonpath.OpGreater,
+			jsonpath.OpGreaterEqual, jsonpath.OpStartsWith:
+			// A comparison can only be true if both of its operands produce
+			// items.
+			return andJSONPathExprs(
+				jsonPathItemsExistExpr(evalCtx, alts, t.Left),
+				jsonPathItemsExistExpr(evalCtx, alts, t.Right),
+			)
+		case jsonpath.OpExists:
+			return jsonPathItemsExistExpr(evalCtx, alts, t.Left)
+		}
+	case jsonpath.Regex:
+		return jsonPathItemsExistExpr(evalCtx, alts, t.Left)
+	}
+	return nil
+}
+
+// jsonPathEqExpr returns an inverted expression that includes all JSON
+// documents for which an item produced by the path relative to the current
+// item can be equal to val. It returns nil if the path is not relative to the
+// current item, or does not constrain the index.
+func jsonPathEqExpr(
+	evalCtx *eval.Context, alts jsonPathAlternatives, e jsonpath.Expr, val json.JSON,
+) inverted.Expression {
+	accessors, ok := currentItemAccessors(e)
+	if !ok {
+		return nil
+	}
+	alts, invertedExpr, ok := applyJSONPathAccessors(evalCtx, alts, accessors)
+	if !ok {
+		return nil
+	}
+	// In lax mode, arrays are unwrapped before they are compared.
+	alts = alts.maybeUnwrap()
+	if len(alts) > maxJSONPathAlternatives {
+		return nil
+	}
+	var eqExpr inverted.Expression
+	for _, alt := range alts {
+		expr := getInvertedExprForJSONOrArrayIndexForContaining(
+			evalCtx, tree.NewDJSON(buildJSONAtPath(alt, val)),
+		)
+		if eqExpr == nil {
+			eqExpr = expr
+		} else {
+			eqExpr = inverted.Or(eqExpr, expr)
+		}
+	}
+	return andJSONPathExprs(invertedExpr, eqExpr)
+}
+
+// jsonPathItemsExistExpr returns an inverted expression that includes all
+// JSON documents for which the path relative to the current item can produce
+// an item. It returns nil if the path is not relative to the current item, or
+// does not constrain the index.
+func jsonPathItemsExistExpr(
+	evalCtx *eval.Context, alts jsonPathAlternatives, e jsonpath.Expr,
+) inverted.Expression {
+	accessors, ok := currentItemAccessors(e)
+	if !ok {
+		return nil
+	}
+	alts, invertedExpr, ok := applyJSONPathAccessors(evalCtx, alts, accessors)
+	if !ok {
+		return nil
+	}
+	return andJSONPathExprs(invertedExpr, jsonPathExistsExpr(alts))
+}
+
+// jsonPathExistsExpr returns an inverted expression that includes all JSON
+// documents that have a value at one of the given locations. It returns nil
+// if one of the locations is the root of the document, which every document
+// has.
+func jsonPathExistsExpr(alts jsonPathAlternatives) inverted.Expression {
+	var invertedExpr inverted.Expression
+	for _, alt := range alts {
+		// An array element exists if the array does, so the spans for the
+		// array are used.
+		for len(alt) > 0 && alt[len(alt)-1].IsArray {
+			alt = alt[:len(alt)-1]
+		}
+		if len(alt) == 0 {
+			return nil
+		}
+		span, err := json.EncodePathExistsInvertedIndexSpan(nil /* inKey */, alt)
+		if err != nil {
+			panic(err)
+		}
+		expr := inverted.ExprForSpan(span, false /* tight */)
+		if invertedExpr == nil {
+			invertedExpr = expr
+		} else {
+			invertedExpr = inverted.Or(invertedExpr, expr)
+		}
+	}
+	return invertedExpr
+}
+
+// currentItemAccessors returns the accessors of a path that starts with the
+// current item (@), or ok=false if e is not such a path.
+func currentItemAccessors(e jsonpath.Expr) (_ []jsonpath.Expr, ok bool) {
+	switch t := e.(type) {
+	case jsonpath.Current:
+		return nil, true
+	case jsonpath.Path:
+		if _, ok := t[0].(jsonpath.Current); ok {
+			return t[1:], true
+		}
+	}
+	return nil, false
+}
+
+// andJSONPathExprs returns the intersection of the given inverted
+// expressions, either of which is nil if it does not constrain the index.
+func andJSONPathExprs(left, right inverted.Expression) inverted.Expression {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return inverted.And(left, right)
+}
+
+// maybeUnwrap returns the locations of the items produced by unwrapping the
+// items at the given locations if they are arrays, which includes both the
+// original locations and the elements of arrays at those locations.
+func (alts jsonPathAlternatives) maybeUnwrap() jsonPathAlternatives {
+	res := make(jsonPathAlternatives, 0, 2*len(alts))
+	for _, alt := range alts {
+		res = append(res, alt, appendJSONPathElement(alt, json.PathElement{IsArray: true}))
+	}
+	return res
+}
+
+// withKey returns the locations of the values of the given key of the objects
+// at the given locations.
+func (alts jsonPathAlternatives) withKey(key string) jsonPathAlternatives {
+	res := make(jsonPathAlternatives, len(alts))
+	for i, alt := range alts {
+		res[i] = appendJSONPathElement(alt, json.PathElement{Key: key})
+	}
+	return res
+}
+
+// appendJSONPathElement returns a copy of path with e appended to it.
+func appendJSONPathElement(path []json.PathElement, e json.PathElement) []json.PathElement {
+	res := make([]json.PathElement, len(path), len(path)+1)
+	copy(res, path)
+	return append(res, e)
+}
+
+// buildJSONAtPath returns a JSON document which only contains val at the given
+// location.
+func buildJSONAtPath(path []json.PathElement, val json.JSON) json.JSON {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].IsArray {
+			b := json.NewArrayBuilder(1)
+			b.Add(val)
+			val = b.Build()
+		} else {
+			b := json.NewObjectBuilder(1)
+			b.Add(path[i].Key, val)
+			val = b.Build()
+		}
+	}
+	return val
+}
@@ -948,12 +948,21 @@ func ExprIsNeverNull(e opt.ScalarExpr, notNullCols opt.ColSet) bool {
 	case *AndExpr, *OrExpr, *GeExpr, *GtExpr, *NeExpr, *EqExpr, *LeExpr, *LtExpr, *LikeExpr,
 		*NotLikeExpr, *ILikeExpr, *NotILikeExpr, *SimilarToExpr, *NotSimilarToExpr, *RegMatchExpr,
 		*NotRegMatchExpr, *RegIMatchExpr, *NotRegIMatchExpr, *ContainsExpr, *ContainedByExpr, *JsonExistsExpr,
-		*JsonAllExistsExpr, *JsonSomeExistsExpr, *AnyScalarExpr, *BitandExpr, *BitorExpr, *BitxorExpr,
-		*PlusExpr, *MinusExpr, *MultExpr, *DivExpr, *FloorDivExpr, *ModExpr, *PowExpr, *ConcatExpr,
-		*LShiftExpr, *RShiftExpr, *WhenExpr:
+		*JsonAllExistsExpr, *JsonSomeExistsExpr, *JsonPathExistsExpr, *AnyScalarExpr, *BitandExpr,
+		*BitorExpr, *BitxorExpr, *PlusExpr, *MinusExpr, *MultExpr, *DivExpr, *FloorDivExpr, *ModExpr,
+		*PowExpr, *ConcatExpr, *LShiftExpr, *RShiftExpr, *AdjacentExpr, *VectorDistanceExpr,
+		*VectorNegInnerProductExpr, *VectorCosDistanceExpr, *WhenExpr:
 		return ExprIsNeverNull(t.Child(0).(opt.ScalarExpr), notNullCols) &&
 			ExprIsNeverNull(t.Child(1).(opt.ScalarExpr), notNullCols)
 
+	case *TSMatchesExpr:
+		// The jsonb @@ jsonpath operator returns NULL if the path does not
+		// return a single boolean.
+		if t.Left.DataType().Family() == types.JsonFamily {
+			return false
+		}
+		return ExprIsNeverNull(t.Left, notNullCols) && ExprIsNeverNull(t.Right, notNullCols)
+
 	default:
 		return false
 	}
@@ -1027,6 +1036,29 @@ func CollectContiguousOrExprs(e opt.ScalarExpr) []opt.ScalarExpr {
 // FKCascades stores metadata necessary for building cascading queries.
 type FKCascades []FKCascade
 
+// HasRowTriggers returns true if any of the cascades is a row-level trigger.
+func (c FKCascades) HasRowTriggers() bool {
+	for i := range c {
+		if c[i].ForEachRow {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBeforeTriggers returns true if any of the cascades is a trigger that
+// fires before the mutation modifies any rows, and reads the buffered input of
+// the mutation. Triggers which modify the rows of the mutation read a separate
+// binding instead (see FKCascade.ModifiesRows).
+func (c FKCascades) HasBeforeTriggers() bool {
+	for i := range c {
+		if c[i].Before && !c[i].ModifiesRows {
+			return true
+		}
+	}
+	return false
+}
+
 // FKCascade stores metadata necessary for building a cascading query.
 // Cascading queries are built as needed, after the original query is executed.
 type FKCascade struct {
@@ -1051,6 +1083,49 @@ type FKCascade struct {
 	// It is empty if the mutation is a deletion. Empty if the cascade does not
 	// require input.
 	NewValues opt.ColList
+
+	// ForEachRow is true if the cascade is a row-level trigger rather than a
+	// foreign key action. In this case FKName is the name of the trigger and the
+	// query is built and executed separately for each row of the mutation
+	// input; OldValues and NewValues map 1-to-1 to the ordinary columns of the
+	// mutated table.
+	ForEachRow bool
+
+	// Before is true if the cascade is a trigger that fires before the mutation
+	// modifies any rows. It can only be set if ForEachRow is set.
+	Before bool
+
+	// ModifiesRows is true if the cascade is a statement of a BEFORE trigger
+	// which can modify the new values of each row, or skip the row entirely. It
+	// can only be set if Before is set; it is not set for the triggers which
+	// maintain a view, which run once the mutation input has been fully built.
+	//
+	// Such triggers must run before any values which depend on the new values of
+	// the row (e.g. computed columns or check constraints) are computed. WithID
+	// identifies a binding of the mutation input as it was before any of these
+	// values were added, which is read by the rest of the mutation input once
+	// all the triggers have run. OldValues and NewValues are columns of this
+	// binding.
+	ModifiesRows bool
+
+	// ReturnsRow is true if the cascade is the last statement of a trigger with
+	// ModifiesRows set, and that statement is a query which returns the row to
+	// be written by the mutation. If the query returns no rows, the row is
+	// skipped; otherwise, the values of the row replace ReturnCols.
+	ReturnsRow bool
+
+	// ReturnCols are the columns of the binding which are replaced by the row
+	// returned by the trigger. It is a subset of NewValues which maps 1-to-1 to
+	// the visible columns of the mutated table, and is empty if the mutation is
+	// a deletion (the returned row only determines whether the row is skipped).
+	ReturnCols opt.ColList
+
+	// TriggerFunction is set if the cascade is a row-level trigger which
+	// executes a trigger function of TriggerTable, instead of a statement. In
+	// this case Builder is nil; the function is called by the execution engine
+	// with the old and new values of each row of the mutation input.
+	TriggerFunction *cat.TriggerFunction
+	TriggerTable    cat.Table
 }
 
 // CascadeBuilder is an interface used to construct a cascading query for a
@@ -1094,6 +1094,22 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
+		if t.RowLevelSecurity {
+			fmt.Fprintf(f.Buffer, ": %s(policies)", tab.Alias.ObjectName)
+			break
+		}
+		if t.Exclusion {
+			ec := tab.Table.ExclusionConstraint(t.CheckOrdinal)
+			fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
+			for i, ord := range ec.ColumnOrdinals {
+				if i > 0 {
+					f.Buffer.WriteByte(',')
+				}
+				f.Buffer.WriteString(string(tab.Table.Column(ord).ColName()))
+			}
+			f.Buffer.WriteByte(')')
+			break
+		}
 		constraint := tab.Table.Unique(t.CheckOrdinal)
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
 		for i := 0; i < constraint.ColumnCount(); i++ {
@@ -1321,7 +1337,15 @@ func (f *ExprFmtCtx) formatMutationCommon(tp treeprinter.Node, p *MutationPrivat
 	if len(p.FKCascades) > 0 {
 		c := tp.Childf("cascades")
 		for i := range p.FKCascades {
-			c.Child(p.FKCascades[i].FKName)
+			if cascade := &p.FKCascades[i]; cascade.ForEachRow {
+				timing := "after"
+				if cascade.Before {
+					timing = "before"
+				}
+				c.Childf("%s (%s row trigger)", cascade.FKName, timing)
+			} else {
+				c.Child(cascade.FKName)
+			}
 		}
 	}
 }
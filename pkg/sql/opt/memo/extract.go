@@ -132,6 +132,16 @@ func ExtractAggInputColumns(e opt.ScalarExpr) opt.ColSet {
 		panic(errors.AssertionFailedf("not an Aggregate"))
 	}
 
+	if uda, ok := e.(*UserDefinedAggExpr); ok {
+		// The arguments of a user-defined aggregate are stored in a list.
+		for i := range uda.Args {
+			if variable, ok := uda.Args[i].(*VariableExpr); ok {
+				res.Add(variable.Col)
+			}
+		}
+		return res
+	}
+
 	for i, n := 0, e.ChildCount(); i < n; i++ {
 		if variable, ok := e.Child(i).(*VariableExpr); ok {
 			res.Add(variable.Col)
@@ -13,6 +13,7 @@ package memo
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
@@ -151,10 +152,16 @@ type Memo struct {
 	nullOrderedLast                        bool
 	costScansWithDefaultColSize            bool
 	allowUnconstrainedNonCoveringIndexScan bool
+	ivfflatProbes                          int64
 	testingOptimizerRandomSeed             int64
 	testingOptimizerCostPerturbation       float64
 	testingOptimizerDisableRuleProbability float64
 
+	// txnIsoLevel is the isolation level under which the plan was created. This
+	// affects the planning of some locking operations, so it must be included in
+	// memo staleness calculation.
+	txnIsoLevel isolation.Level
+
 	// curRank is the highest currently in-use scalar expression rank.
 	curRank opt.ScalarRank
 
@@ -200,9 +207,11 @@ func (m *Memo) Init(evalCtx *eval.Context) {
 		nullOrderedLast:                        evalCtx.SessionData().NullOrderedLast,
 		costScansWithDefaultColSize:            evalCtx.SessionData().CostScansWithDefaultColSize,
 		allowUnconstrainedNonCoveringIndexScan: evalCtx.SessionData().UnconstrainedNonCoveringIndexScanEnabled,
+		ivfflatProbes:                          evalCtx.SessionData().IvfflatProbes,
 		testingOptimizerRandomSeed:             evalCtx.SessionData().TestingOptimizerRandomSeed,
 		testingOptimizerCostPerturbation:       evalCtx.SessionData().TestingOptimizerCostPerturbation,
 		testingOptimizerDisableRuleProbability: evalCtx.SessionData().TestingOptimizerDisableRuleProbability,
+		txnIsoLevel:                            evalCtx.TxnIsoLevel,
 	}
 	m.metadata.Init()
 	m.logPropsBuilder.init(evalCtx, m)
@@ -333,9 +342,11 @@ func (m *Memo) IsStale(
 		m.nullOrderedLast != evalCtx.SessionData().NullOrderedLast ||
 		m.costScansWithDefaultColSize != evalCtx.SessionData().CostScansWithDefaultColSize ||
 		m.allowUnconstrainedNonCoveringIndexScan != evalCtx.SessionData().UnconstrainedNonCoveringIndexScanEnabled ||
+		m.ivfflatProbes != evalCtx.SessionData().IvfflatProbes ||
 		m.testingOptimizerRandomSeed != evalCtx.SessionData().TestingOptimizerRandomSeed ||
 		m.testingOptimizerCostPerturbation != evalCtx.SessionData().TestingOptimizerCostPerturbation ||
-		m.testingOptimizerDisableRuleProbability != evalCtx.SessionData().TestingOptimizerDisableRuleProbability {
+		m.testingOptimizerDisableRuleProbability != evalCtx.SessionData().TestingOptimizerDisableRuleProbability ||
+		m.txnIsoLevel != evalCtx.TxnIsoLevel {
 		return true, nil
 	}
 
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -458,6 +459,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.Max1RowOp:
 		return sb.colStatMax1Row(colSet, e.(*Max1RowExpr))
 
+	case opt.BarrierOp:
+		return sb.colStatBarrier(colSet, e.(*BarrierExpr))
+
 	case opt.OrdinalityOp:
 		return sb.colStatOrdinality(colSet, e.(*OrdinalityExpr))
 
@@ -836,6 +840,7 @@ func (sb *statisticsBuilder) constrainScan(
 ) {
 	var numUnappliedConjuncts float64
 	var constrainedCols, histCols opt.ColSet
+	vectorSelectivity := props.OneSelectivity
 	idx := sb.md.Table(scan.Table).Index(scan.Index)
 
 	// Calculate distinct counts and histograms for inverted constrained columns
@@ -867,12 +872,16 @@ func (sb *statisticsBuilder) constrainScan(
 					histCols.Add(invertedConstrainedCol)
 					sb.updateDistinctCountFromHistogram(colStat, inputStat.DistinctCount)
 				}
+			} else if cfg := idx.VectorConfig(); !cfg.IsEmpty() {
+				vectorSelectivity = vectorScanSelectivity(scan, cfg)
 			} else {
 				// Just assume a single closed span such as ["\xfd", "\xfe").
 				// This corresponds to two "conjuncts" as defined in
 				// numConjunctsInConstraint.
 				numUnappliedConjuncts += 2
 			}
+		} else if cfg := idx.VectorConfig(); !cfg.IsEmpty() {
+			vectorSelectivity = vectorScanSelectivity(scan, cfg)
 		} else {
 			// Assume a single closed span.
 			numUnappliedConjuncts += 2
@@ -918,9 +927,17 @@ func (sb *statisticsBuilder) constrainScan(
 	corr := sb.correlationFromMultiColDistinctCounts(constrainedCols, scan, s)
 	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, scan, s, corr))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
+	s.ApplySelectivity(vectorSelectivity)
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, notNullCols, constrainedCols))
 }
 
+// vectorScanSelectivity returns the selectivity of a scan of some of the lists
+// of a vector index, which has one span per list. Without a histogram, the
+// vectors are assumed to be evenly distributed among the lists.
+func vectorScanSelectivity(scan *ScanExpr, cfg vecindex.Config) props.Selectivity {
+	return props.MakeSelectivity(float64(len(scan.InvertedConstraint)) / float64(cfg.Lists))
+}
+
 func (sb *statisticsBuilder) colStatScan(colSet opt.ColSet, scan *ScanExpr) *props.ColumnStatistic {
 	relProps := scan.Relational()
 	s := &relProps.Stats
@@ -2236,6 +2253,45 @@ func (sb *statisticsBuilder) colStatMax1Row(
 	return colStat
 }
 
+// +---------+
+// | Barrier |
+// +---------+
+
+func (sb *statisticsBuilder) buildBarrier(barrier *BarrierExpr, relProps *props.Relational) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+	s.Available = sb.availabilityFromInput(barrier)
+
+	inputStats := &barrier.Input.Relational().Stats
+
+	// The row count of a barrier is equal to the row count of its input.
+	s.RowCount = inputStats.RowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatBarrier(
+	colSet opt.ColSet, barrier *BarrierExpr,
+) *props.ColumnStatistic {
+	relProps := barrier.Relational()
+	s := &relProps.Stats
+
+	colStat, _ := s.ColStats.Add(colSet)
+
+	inputColStat := sb.colStatFromChild(colSet, barrier, 0 /* childIdx */)
+	colStat.AvgSize = inputColStat.AvgSize
+	colStat.DistinctCount = inputColStat.DistinctCount
+	colStat.NullCount = inputColStat.NullCount
+
+	if colSet.Intersects(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +------------+
 // | Row Number |
 // +------------+
@@ -157,6 +157,11 @@ func FindWindowOverload(e opt.ScalarExpr) (name string, overload *tree.Overload)
 // FindAggregateOverload finds an aggregate function overload that matches the
 // given aggregate function expression. It panics if no match can be found.
 func FindAggregateOverload(e opt.ScalarExpr) (name string, overload *tree.Overload) {
+	if uda, ok := e.(*UserDefinedAggExpr); ok {
+		// User-defined aggregates are not builtins, so their overload is stored
+		// in the expression.
+		return uda.Name, uda.Overload
+	}
 	name = opt.AggregateOpReverseMap[e.Op()]
 	_, overload, ok := FindFunction(e, name)
 	if ok {
@@ -276,6 +276,13 @@ func (md *Metadata) AddDependency(name MDDepName, ds cat.DataSource, priv privil
 // objects. If the dependencies are no longer up-to-date, then CheckDependencies
 // returns false.
 //
+// A cached memo can be checked on behalf of a different user than the one who
+// built it. If the current user lacks one of the privileges, the memo is
+// reported as stale rather than raising an error, so that it is rebuilt for
+// the current user, who may still be allowed to run the statement (e.g.
+// through privileges on the columns it accesses). Building the memo again
+// raises the error if the user is not.
+//
 // This function cannot swallow errors and return only a boolean, as it may
 // perform KV operations on behalf of the transaction associated with the
 // provided catalog, and those errors are required to be propagated.
@@ -310,6 +317,9 @@ func (md *Metadata) CheckDependencies(
 			priv := privilege.Kind(bits.TrailingZeros32(uint32(privs)))
 			if priv != 0 {
 				if err := catalog.CheckPrivilege(ctx, toCheck, priv); err != nil {
+					if pgerror.GetPGCode(err) == pgcode.InsufficientPrivilege {
+						return false, nil
+					}
 					return false, err
 				}
 			}
@@ -598,9 +598,10 @@ func (c *CustomFuncs) TranslateNonIgnoreAggs(
 			if !opt.AggregateIsNullOnEmpty(agg.Op()) {
 				// If this gets triggered we need to modify constructCanaryChecker to
 				// have a special "on-empty" value. This shouldn't get triggered
-				// because as of writing the only operation that is false for both
-				// AggregateIgnoresNulls and AggregateIsNullOnEmpty is CountRows, and
-				// we translate that into Count.
+				// because as of writing the only operations that are false for both
+				// AggregateIgnoresNulls and AggregateIsNullOnEmpty are CountRows,
+				// which we translate into Count, and UserDefinedAgg, which prevents
+				// decorrelation.
 				// TestAllAggsIgnoreNullsOrNullOnEmpty verifies that this assumption is
 				// true.
 				panic(errors.AssertionFailedf("can't decorrelate with aggregate %s", redact.Safe(agg.Op())))
@@ -145,6 +145,16 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// Row-level triggers can refer to the old values of any of the ordinary
+	// columns of the table.
+	if op != opt.InsertOp && private.FKCascades.HasRowTriggers() {
+		for ord, n := 0, tabMeta.Table.ColumnCount(); ord < n; ord++ {
+			if tabMeta.Table.Column(ord).Kind() == cat.Ordinary {
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -520,6 +530,10 @@ func DerivePruneCols(e memo.RelExpr) opt.ColSet {
 		usedCols := sel.Filters.OuterCols()
 		relProps.Rule.PruneCols.DifferenceWith(usedCols)
 
+	case opt.BarrierOp:
+		// Any pruneable input columns can potentially be pruned.
+		relProps.Rule.PruneCols = DerivePruneCols(e.Child(0).(memo.RelExpr))
+
 	case opt.ProjectOp:
 		// All columns can potentially be pruned from the Project, if they're never
 		// used in a higher-level expression.
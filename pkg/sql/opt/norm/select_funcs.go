@@ -413,3 +413,34 @@ func (c *CustomFuncs) addConjuncts(
 	}
 	return filters, true
 }
+
+// IsLeakproofFilter returns true if the given filter condition cannot reveal
+// anything about the rows it is evaluated on, other than by filtering them.
+func (c *CustomFuncs) IsLeakproofFilter(item *memo.FiltersItem) bool {
+	return item.ScalarProps().VolatilitySet.IsLeakproof()
+}
+
+// ExtractLeakproofFilters returns a new list containing only the leakproof
+// conditions of the given list.
+func (c *CustomFuncs) ExtractLeakproofFilters(filters memo.FiltersExpr) memo.FiltersExpr {
+	newFilters := make(memo.FiltersExpr, 0, len(filters))
+	for i := range filters {
+		if c.IsLeakproofFilter(&filters[i]) {
+			newFilters = append(newFilters, filters[i])
+		}
+	}
+	return newFilters
+}
+
+// ExtractNonLeakproofFilters is the opposite of ExtractLeakproofFilters. It
+// returns a new list containing only the conditions of the given list which
+// are not leakproof.
+func (c *CustomFuncs) ExtractNonLeakproofFilters(filters memo.FiltersExpr) memo.FiltersExpr {
+	newFilters := make(memo.FiltersExpr, 0, len(filters))
+	for i := range filters {
+		if !c.IsLeakproofFilter(&filters[i]) {
+			newFilters = append(newFilters, filters[i])
+		}
+	}
+	return newFilters
+}
@@ -143,31 +143,37 @@ var ComparisonOpReverseMap = map[Operator]treecmp.ComparisonOperatorSymbol{
 	JsonExistsOp:     treecmp.JSONExists,
 	JsonSomeExistsOp: treecmp.JSONSomeExists,
 	JsonAllExistsOp:  treecmp.JSONAllExists,
+	JsonPathExistsOp: treecmp.JSONPathExists,
 	OverlapsOp:       treecmp.Overlaps,
 	BBoxCoversOp:     treecmp.RegMatch,
 	BBoxIntersectsOp: treecmp.Overlaps,
+	TSMatchesOp:      treecmp.TSMatches,
+	AdjacentOp:       treecmp.Adjacent,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
 // binary operator type.
 var BinaryOpReverseMap = map[Operator]treebin.BinaryOperatorSymbol{
-	BitandOp:        treebin.Bitand,
-	BitorOp:         treebin.Bitor,
-	BitxorOp:        treebin.Bitxor,
-	PlusOp:          treebin.Plus,
-	MinusOp:         treebin.Minus,
-	MultOp:          treebin.Mult,
-	DivOp:           treebin.Div,
-	FloorDivOp:      treebin.FloorDiv,
-	ModOp:           treebin.Mod,
-	PowOp:           treebin.Pow,
-	ConcatOp:        treebin.Concat,
-	LShiftOp:        treebin.LShift,
-	RShiftOp:        treebin.RShift,
-	FetchValOp:      treebin.JSONFetchVal,
-	FetchTextOp:     treebin.JSONFetchText,
-	FetchValPathOp:  treebin.JSONFetchValPath,
-	FetchTextPathOp: treebin.JSONFetchTextPath,
+	BitandOp:                treebin.Bitand,
+	BitorOp:                 treebin.Bitor,
+	BitxorOp:                treebin.Bitxor,
+	PlusOp:                  treebin.Plus,
+	MinusOp:                 treebin.Minus,
+	MultOp:                  treebin.Mult,
+	DivOp:                   treebin.Div,
+	FloorDivOp:              treebin.FloorDiv,
+	ModOp:                   treebin.Mod,
+	PowOp:                   treebin.Pow,
+	ConcatOp:                treebin.Concat,
+	LShiftOp:                treebin.LShift,
+	RShiftOp:                treebin.RShift,
+	FetchValOp:              treebin.JSONFetchVal,
+	FetchTextOp:             treebin.JSONFetchText,
+	FetchValPathOp:          treebin.JSONFetchValPath,
+	FetchTextPathOp:         treebin.JSONFetchTextPath,
+	VectorDistanceOp:        treebin.Distance,
+	VectorNegInnerProductOp: treebin.NegInnerProduct,
+	VectorCosDistanceOp:     treebin.CosDistance,
 }
 
 // UnaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -279,7 +285,8 @@ func ScalarOperatorTransmitsNulls(op Operator) bool {
 	case BitandOp, BitorOp, BitxorOp, PlusOp, MinusOp, MultOp, DivOp, FloorDivOp,
 		ModOp, PowOp, EqOp, NeOp, LtOp, GtOp, LeOp, GeOp, LikeOp, NotLikeOp, ILikeOp,
 		NotILikeOp, SimilarToOp, NotSimilarToOp, RegMatchOp, NotRegMatchOp, RegIMatchOp,
-		NotRegIMatchOp, ConstOp, BBoxCoversOp, BBoxIntersectsOp:
+		NotRegIMatchOp, ConstOp, BBoxCoversOp, BBoxIntersectsOp, TSMatchesOp, AdjacentOp,
+		JsonPathExistsOp:
 		return true
 
 	default:
@@ -295,7 +302,7 @@ func BoolOperatorRequiresNotNullArgs(op Operator) bool {
 		EqOp, LtOp, LeOp, GtOp, GeOp, NeOp,
 		LikeOp, NotLikeOp, ILikeOp, NotILikeOp, SimilarToOp, NotSimilarToOp,
 		RegMatchOp, NotRegMatchOp, RegIMatchOp, NotRegIMatchOp, BBoxCoversOp,
-		BBoxIntersectsOp:
+		BBoxIntersectsOp, TSMatchesOp, AdjacentOp, JsonPathExistsOp:
 		return true
 	}
 	return false
@@ -330,7 +337,7 @@ func AggregateIgnoresNulls(op Operator) bool {
 		return true
 
 	case ArrayAggOp, ConcatAggOp, ConstAggOp, CountRowsOp, FirstAggOp, JsonAggOp,
-		JsonbAggOp, JsonObjectAggOp, JsonbObjectAggOp:
+		JsonbAggOp, JsonObjectAggOp, JsonbObjectAggOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -355,7 +362,9 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		RegressionSXYOp, RegressionSYYOp:
 		return true
 
-	case CountOp, CountRowsOp, RegressionCountOp:
+	case CountOp, CountRowsOp, RegressionCountOp, UserDefinedAggOp:
+		// A user-defined aggregate returns its initial state (or the result of its
+		// final function applied to it) on empty input, which may not be NULL.
 		return false
 
 	default:
@@ -388,6 +397,11 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		// These aggregations return NULL if they are given a single not-NULL input.
 		return false
 
+	case UserDefinedAggOp:
+		// The transition and final expressions of a user-defined aggregate may
+		// return NULL for any input.
+		return false
+
 	default:
 		panic(errors.AssertionFailedf("unhandled op %s", redact.Safe(op)))
 	}
@@ -435,7 +449,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 		SqrDiffOp, STCollectOp, StdDevOp, StringAggOp, VarianceOp, StdDevPopOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -457,7 +471,7 @@ func AggregateIgnoresDuplicates(op Operator) bool {
 		VarPopOp, JsonObjectAggOp, JsonbObjectAggOp, STCollectOp, CovarPopOp,
 		CovarSampOp, RegressionAvgXOp, RegressionAvgYOp, RegressionInterceptOp,
 		RegressionR2Op, RegressionSlopeOp, RegressionSXXOp, RegressionSXYOp,
-		RegressionSYYOp, RegressionCountOp:
+		RegressionSYYOp, RegressionCountOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -202,12 +202,12 @@ func getIndexColumnNamesAndTypes(index cat.Index) (colNames []string, colTypes [
 		colNames[i] = string(c.ColName())
 		colTypes[i] = c.DatumType()
 	}
-	if index.IsInverted() && !index.GeoConfig().IsEmpty() {
+	if index.IsInverted() && (!index.GeoConfig().IsEmpty() || !index.VectorConfig().IsEmpty()) {
 		// TODO(sumeer): special case Array too. JSON is harder since the split
 		// needs to be a Datum and the JSON inverted column is not.
 		//
-		// Geospatial inverted index. The last explicit column is the inverted
-		// column and is an int.
+		// Geospatial or vector inverted index. The last explicit column is the
+		// inverted column and is an int.
 		colTypes[index.ExplicitColumnCount()-1] = types.Int
 	}
 	return colNames, colTypes
@@ -107,6 +107,19 @@ type Builder struct {
 	// be used with care.
 	skipSelectPrivilegeChecks bool
 
+	// If set, the statement being built maintains an incrementally refreshed
+	// materialized view, either on behalf of a mutation of one of the tables it
+	// depends on or to populate it. Such statements are allowed to mutate the
+	// materialized view, and privileges and row-level security policies are not
+	// checked, because they have already been checked for the triggering
+	// mutation or the statement is executed internally.
+	maintainingView bool
+
+	// columnPrivilegeCols contains the columns of the tables which the current
+	// user can only access through the privileges granted on the columns. The
+	// SELECT privilege is checked on these columns when they are referenced.
+	columnPrivilegeCols opt.ColSet
+
 	// views contains a cache of views that have already been parsed, in case they
 	// are referenced multiple times in the same query.
 	views map[cat.View]*tree.Select
@@ -204,6 +217,13 @@ func (b *Builder) Build() (err error) {
 	}
 	b.semaCtx.TypeResolver = typeTracker
 
+	// Statements which populate materialized views are allowed to mutate them.
+	// Their memo cannot be reused by other statements, which are not.
+	if sd := b.evalCtx.SessionData(); sd != nil && sd.MaintainMaterializedViews {
+		b.maintainingView = true
+		b.DisableMemoReuse = true
+	}
+
 	// Special case for CannedOptPlan.
 	if canned, ok := b.stmt.(*tree.CannedOptPlan); ok {
 		b.factory.DisableOptimizations()
@@ -268,7 +288,7 @@ func (b *Builder) buildStmt(
 	if b.insideViewDef {
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Update, *tree.Merge, *tree.CreateTable, *tree.CreateView,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
 			panic(pgerror.Newf(
@@ -299,16 +319,22 @@ func (b *Builder) buildStmt(
 			return b.buildUpdate(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		// MERGE may be built from multiple With expressions, which must be at
+		// the top level.
+		if !inScope.atRoot {
+			panic(pgerror.Newf(pgcode.FeatureNotSupported, "MERGE not supported in WITH query"))
+		}
+		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
+			return b.buildMerge(stmt, inScope)
+		})
+
 	case *tree.CreateTable:
 		return b.buildCreateTable(stmt, inScope)
 
 	case *tree.CreateView:
 		return b.buildCreateView(stmt, inScope)
 
-	case *tree.CreateFunction:
-		// TODO (Chengxiong): implement this
-		panic(unimplemented.NewWithIssue(83228, "CREATE FUNCTION unimplemented"))
-
 	case *tree.Explain:
 		return b.buildExplain(stmt, inScope)
 
@@ -401,10 +427,12 @@ func (b *Builder) allocScope() *scope {
 	return r
 }
 
-// trackReferencedColumnForViews is used to add a column to the view's
-// dependencies. This should be called whenever a column reference is made in a
-// view query.
-func (b *Builder) trackReferencedColumnForViews(col *scopeColumn) {
+// trackReferencedColumn is used to check the privileges on a column which can
+// only be accessed through the privileges granted on it, and to add the column
+// to the view's dependencies. This should be called whenever a column reference
+// is made in a query.
+func (b *Builder) trackReferencedColumn(col *scopeColumn) {
+	b.checkColumnPrivilege(col.id)
 	if b.trackViewDeps {
 		for i := range b.viewDeps {
 			dep := b.viewDeps[i]
@@ -0,0 +1,102 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+)
+
+// checkTableOrColumnPrivilege is like checkPrivilege, but if the current user
+// does not have the given privilege on the given table, it also allows access
+// to the table when the user has the privilege on any of its columns. In that
+// case it returns true, and the privilege must be checked on each column of
+// the table which is accessed.
+func (b *Builder) checkTableOrColumnPrivilege(
+	name opt.MDDepName, ds cat.DataSource, priv privilege.Kind,
+) (checkColumns bool) {
+	if (priv == privilege.SELECT && b.skipSelectPrivilegeChecks) || b.maintainingView {
+		b.checkPrivilege(name, ds, priv)
+		return false
+	}
+	err := b.catalog.CheckPrivilege(b.ctx, ds, priv)
+	if err == nil {
+		b.factory.Metadata().AddDependency(name, ds, priv)
+		return false
+	}
+	tab, ok := ds.(cat.Table)
+	if !ok || pgerror.GetPGCode(err) != pgcode.InsufficientPrivilege {
+		panic(err)
+	}
+	hasColumnPrivilege, colErr := b.catalog.HasColumnPrivilege(b.ctx, tab, priv)
+	if colErr != nil {
+		panic(colErr)
+	}
+	if !hasColumnPrivilege {
+		panic(err)
+	}
+
+	// The columns which can be accessed depend on the current user, so the memo
+	// cannot be reused.
+	b.DisableMemoReuse = true
+	b.factory.Metadata().AddDependency(name, ds, 0 /* priv */)
+	return true
+}
+
+// requireColumnPrivileges marks the columns of the given scope so that the
+// SELECT privilege is checked on them when they are referenced.
+func (b *Builder) requireColumnPrivileges(s *scope) {
+	for i := range s.cols {
+		b.columnPrivilegeCols.Add(s.cols[i].id)
+	}
+}
+
+// unrequireColumnPrivileges reverts requireColumnPrivileges. It is used once
+// the expressions written by the user which can reference the columns of the
+// scope have been built, so that the columns can be referenced by the
+// expressions which are synthesized for the statement.
+func (b *Builder) unrequireColumnPrivileges(s *scope) {
+	for i := range s.cols {
+		b.columnPrivilegeCols.Remove(s.cols[i].id)
+	}
+}
+
+// checkColumnPrivilege checks the SELECT privilege on the given column if it
+// was marked by requireColumnPrivileges, and raises an error if the current
+// user does not have it.
+func (b *Builder) checkColumnPrivilege(col opt.ColumnID) {
+	if !b.columnPrivilegeCols.Contains(col) {
+		return
+	}
+	md := b.factory.Metadata()
+	tabID := md.ColumnMeta(col).Table
+	err := b.catalog.CheckColumnPrivilege(
+		b.ctx, md.Table(tabID), tabID.ColumnOrdinal(col), privilege.SELECT,
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// checkTargetColumnPrivileges checks the given privilege on each of the target
+// columns of the mutation, and raises an error if the current user does not
+// have it.
+func (mb *mutationBuilder) checkTargetColumnPrivileges(priv privilege.Kind) {
+	for i := range mb.targetColList {
+		ord := mb.tabID.ColumnOrdinal(mb.targetColList[i])
+		if err := mb.b.catalog.CheckColumnPrivilege(mb.b.ctx, mb.tab, ord, priv); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -12,9 +12,15 @@ package optbuilder
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
 )
 
 func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope *scope) {
@@ -39,8 +45,32 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 		b.qualifyDataSourceNamesInAST = false
 	}()
 
+	var params viewStorageParamSetter
+	if err := storageparam.Set(b.semaCtx, b.evalCtx, cv.StorageParams, &params); err != nil {
+		panic(err)
+	}
+	if params.incrementalRefresh && !cv.Materialized {
+		panic(pgerror.New(pgcode.InvalidParameterValue,
+			"only materialized views can be refreshed incrementally"))
+	}
+	if params.incrementalRefresh && !cv.WithData {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"incrementally refreshed materialized views cannot be created WITH NO DATA"))
+	}
+
 	defScope := b.buildStmtAtRoot(cv.AsSource, nil /* desiredTypes */)
 
+	// The changes to an incrementally maintained view are computed separately
+	// for each modified row of the tables it depends on, which only produces
+	// the same result as the view query if the query is deterministic.
+	if params.incrementalRefresh {
+		vs := defScope.expr.Relational().VolatilitySet
+		if vs.HasStable() || vs.HasVolatile() {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"incrementally refreshed materialized views cannot contain stable or volatile expressions"))
+		}
+	}
+
 	p := defScope.makePhysicalProps().Presentation
 	if len(cv.ColumnNames) != 0 {
 		if len(p) != len(cv.ColumnNames) {
@@ -88,7 +118,47 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 			Deps:         b.viewDeps,
 			TypeDeps:     b.viewTypeDeps,
 			WithData:     cv.WithData,
+
+			IncrementalRefresh: params.incrementalRefresh,
 		},
 	)
 	return outScope
 }
+
+// viewStorageParamSetter is a storageparam.Setter for the storage parameters
+// of a materialized view.
+type viewStorageParamSetter struct {
+	incrementalRefresh bool
+}
+
+var _ storageparam.Setter = &viewStorageParamSetter{}
+
+// Set is part of the storageparam.Setter interface.
+func (s *viewStorageParamSetter) Set(
+	semaCtx *tree.SemaContext, evalCtx *eval.Context, key string, datum tree.Datum,
+) error {
+	switch key {
+	case `incremental_refresh`:
+		if stringVal, err := paramparse.DatumAsString(evalCtx, key, datum); err == nil {
+			s.incrementalRefresh, err = paramparse.ParseBoolVar(key, stringVal)
+			return err
+		}
+		v, err := paramparse.GetSingleBool(key, datum)
+		if err != nil {
+			return err
+		}
+		s.incrementalRefresh = bool(*v)
+		return nil
+	}
+	return pgerror.Newf(pgcode.InvalidParameterValue, "invalid storage parameter %q", key)
+}
+
+// Reset is part of the storageparam.Setter interface.
+func (s *viewStorageParamSetter) Reset(evalCtx *eval.Context, key string) error {
+	return errors.AssertionFailedf("storage parameters of views cannot be reset")
+}
+
+// RunPostChecks is part of the storageparam.Setter interface.
+func (s *viewStorageParamSetter) RunPostChecks() error {
+	return nil
+}
@@ -46,14 +46,18 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 			"cannot specify a list of column IDs with DELETE"))
 	}
 
-	// Check Select permission as well, since existing values must be read.
-	b.checkPrivilege(depName, tab, privilege.SELECT)
+	// Check Select permission as well, since existing values must be read. It
+	// may have been granted only on some columns of the table, in which case it
+	// is checked on the columns which are referenced.
+	checkSelectColumns := b.checkTableOrColumnPrivilege(depName, tab, privilege.SELECT)
 
 	// Check if this table has already been mutated in another subquery.
 	b.checkMultipleMutations(tab, false /* simpleInsert */)
 
 	var mb mutationBuilder
 	mb.init(b, "delete", tab, alias)
+	mb.initRowLevelSecurity()
+	mb.checkSelectColumns = checkSelectColumns
 
 	// Build the input expression that selects the rows that will be deleted:
 	//
@@ -62,7 +66,11 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the delete table will be projected.
-	mb.buildInputForDelete(inScope, del.Table, del.Where, del.Limit, del.OrderBy)
+	mb.buildInputForDelete(inScope, del.Table, del.Using, del.Where, del.Limit, del.OrderBy)
+
+	// The expressions written by the user have been built, so the privileges
+	// on the fetched columns no longer need to be checked.
+	b.unrequireColumnPrivileges(mb.fetchScope)
 
 	// Build the final delete statement, including any returned expressions.
 	if resultsNeeded(del.Returning) {
@@ -77,15 +85,27 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 // buildDelete constructs a Delete operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildDelete(returning tree.ReturningExprs) {
+	// BEFORE triggers can skip some of the rows, so they must run before the
+	// input is used by the checks and cascades.
+	mb.buildInputForBeforeTriggers(tree.TriggerEventDelete)
+
 	mb.buildFKChecksAndCascadesForDelete()
 
+	mb.buildRowTriggers(tree.TriggerEventDelete)
+
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols()
 
 	private := mb.makeMutationPrivate(returning != nil)
+	for _, col := range mb.extraAccessibleCols {
+		if col.id != 0 {
+			private.PassthroughCols = append(private.PassthroughCols, col.id)
+		}
+	}
 	mb.outScope.expr = mb.b.factory.ConstructDelete(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
+	mb.buildBeforeTriggersWith()
 
 	mb.buildReturning(returning)
 }
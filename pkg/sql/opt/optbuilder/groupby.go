@@ -100,6 +100,22 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping columns of each grouping set, if the
+	// GROUP BY clause has multiple grouping sets (see buildGroupingSets). It is
+	// nil otherwise.
+	groupingSets []opt.ColSet
+
+	// groupingSetCol is the grouping column which contains the ordinal of the
+	// grouping set of each row. It is only set if groupingSets is not nil, in
+	// which case it is the last grouping column.
+	groupingSetCol opt.ColumnID
+
+	// groupingSetPresentCol is true for the rows of the input of the
+	// aggregation, and NULL for the rows which are added to the input for the
+	// empty grouping sets. It is only set if there are multiple grouping sets
+	// and one of them is empty (see constructGroupingSetsInput).
+	groupingSetPresentCol opt.ColumnID
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -127,18 +143,26 @@ func (g *groupby) hasNonCommutativeAggregates() bool {
 	return false
 }
 
+// numGroupingCols returns the number of grouping columns.
+func (g *groupby) numGroupingCols() int {
+	if g.groupingSetCol != 0 {
+		return len(g.groupStrs) + 1
+	}
+	return len(g.groupStrs)
+}
+
 // groupingCols returns the columns in the aggInScope corresponding to grouping
 // columns.
 func (g *groupby) groupingCols() []scopeColumn {
 	// Grouping cols are always clustered at the end of the column list.
-	return g.aggInScope.cols[len(g.aggInScope.cols)-len(g.groupStrs):]
+	return g.aggInScope.cols[len(g.aggInScope.cols)-g.numGroupingCols():]
 }
 
 // getAggregateArgCols returns the columns in the aggInScope corresponding to
 // arguments to aggregate functions. If the aggregate has a filter, the column
 // corresponding to the filter's input will immediately follow the arguments.
 func (g *groupby) aggregateArgCols() []scopeColumn {
-	return g.aggInScope.cols[:len(g.aggInScope.cols)-len(g.groupStrs)]
+	return g.aggInScope.cols[:len(g.aggInScope.cols)-g.numGroupingCols()]
 }
 
 // getAggregateResultCols returns the columns in the aggOutScope corresponding
@@ -260,6 +284,12 @@ func (a aggregateInfo) isOrderingSensitive() bool {
 	}
 }
 
+// isUserDefined returns true if the aggregate was created with CREATE
+// AGGREGATE.
+func (a aggregateInfo) isUserDefined() bool {
+	return a.def.Overload.UserDefinedAggregate != nil
+}
+
 // isCommutative checks whether the aggregate is commutative. That is, if it is
 // ordering insensitive or if no ordering is specified.
 func (a aggregateInfo) isCommutative() bool {
@@ -343,6 +373,12 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (outScope *scope) {
 	g := fromScope.groupby
 
+	// If there are multiple grouping sets, each input row must be aggregated
+	// once for each grouping set.
+	if g.groupingSets != nil {
+		b.constructGroupingSetsInput(fromScope)
+	}
+
 	groupingCols := g.groupingCols()
 
 	// Build ColSet of grouping columns.
@@ -354,6 +390,12 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		for i := range g.aggs {
+			if g.aggs[i].isUserDefined() {
+				panic(unimplementedWithIssueDetailf(74775, "ordered",
+					"user-defined aggregate %s() cannot be used with ordered aggregates", g.aggs[i].def.Name))
+			}
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -368,6 +410,7 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		// Only calculate the set of fromScope columns if it will be used below.
 		fromCols = fromScope.colSet()
 	}
+	var filterProjections memo.ProjectionsExpr
 	for i, agg := range aggInfos {
 		// First accumulate the arguments to the aggregate function. These are
 		// always variables referencing columns in the GroupBy input expression,
@@ -385,7 +428,11 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 
 		// Construct the aggregate function from its name and arguments and store
 		// it in the corresponding scope column.
-		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		if agg.isUserDefined() {
+			aggCols[i].scalar = b.factory.ConstructUserDefinedAgg(args, &aggInfos[i].def)
+		} else {
+			aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		}
 
 		// Wrap the aggregate function with an AggDistinct operator if DISTINCT
 		// was specified in the query.
@@ -397,12 +444,16 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		// if FILTER (WHERE ...) was specified in the query.
 		// TODO(justin): add a norm rule to push these filters below GroupBy where
 		// possible.
+		var filterCol opt.ColumnID
 		if agg.filter != nil {
 			// Column containing filter expression is always after the argument
 			// columns (which have already been processed).
-			colID := argCols[0].id
+			filterCol = argCols[0].id
 			argCols = argCols[1:]
-			variable := b.factory.ConstructVariable(colID)
+		}
+		filterCol = b.addGroupingSetsFilter(g, filterCol, &filterProjections)
+		if filterCol != 0 {
+			variable := b.factory.ConstructVariable(filterCol)
 			aggCols[i].scalar = b.factory.ConstructAggFilter(aggCols[i].scalar, variable)
 		}
 
@@ -431,7 +482,7 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
 	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr,
+		b.constructGroupingSetsFilters(g.aggInScope.expr, filterProjections),
 		groupingColSet,
 		aggCols,
 		g.aggInScope.ordering,
@@ -507,15 +558,20 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if hasGroupingSets(groupBy) {
+		b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope)
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the set of grouping columns for the
+// expression, including any which were already added.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +585,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -627,7 +683,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -637,7 +694,9 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -879,6 +938,16 @@ func isAggregate(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.AggregateClass
 }
 
+// isUserDefinedAggregate returns true if the given function is an aggregate
+// created with CREATE AGGREGATE.
+func isUserDefinedAggregate(def *tree.FunctionDefinition) bool {
+	if !isAggregate(def) || len(def.Definition) == 0 {
+		return false
+	}
+	overload, ok := def.Definition[0].(*tree.Overload)
+	return ok && overload.UserDefinedAggregate != nil
+}
+
 func isWindow(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.WindowClass
 }
@@ -903,6 +972,11 @@ func newGroupingError(name tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The PK columns are not part of every grouping set, so they do not
+		// determine col.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
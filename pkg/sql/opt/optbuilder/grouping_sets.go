@@ -0,0 +1,412 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+// This file has builder code specific to GROUP BY clauses with ROLLUP, CUBE or
+// GROUPING SETS, and to the GROUPING() function.
+//
+// A GROUP BY clause with multiple grouping sets is built by expanding the
+// input of the aggregation: the input is cross joined with a VALUES expression
+// that produces the ordinal of each grouping set, so that each input row is
+// repeated once for each grouping set. The ordinal becomes an additional
+// grouping column, and each grouping column which is not part of every
+// grouping set is replaced in the pre-projection by a CASE expression that
+// produces NULL for the grouping sets which do not include it.
+//
+// For example:
+//   SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)
+//
+//   input:           t CROSS JOIN (VALUES (0), (1), (2)) AS v(grouping_set)
+//   pre-projection:  c,
+//                    CASE grouping_set WHEN 2 THEN NULL ELSE a END AS a',
+//                    CASE grouping_set WHEN 1 THEN NULL WHEN 2 THEN NULL
+//                      ELSE b END AS b',
+//                    grouping_set
+//   aggregation:     group by a', b', grouping_set, calculate sum(c)
+//   post-projection: a', b', sum(c)
+//
+// Since the expansion only uses existing operators, it is supported by all
+// of the execution engines.
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+const (
+	// maxGroupingSets is the maximum number of grouping sets in a GROUP BY
+	// clause, after expansion of ROLLUP, CUBE and GROUPING SETS. This matches
+	// Postgres.
+	maxGroupingSets = 4096
+
+	// maxCubeElements is the maximum number of elements of a CUBE. This matches
+	// Postgres.
+	maxCubeElements = 12
+
+	// maxGroupingArgs is the maximum number of arguments to GROUPING(), so that
+	// the result fits in an INT4. This matches Postgres.
+	maxGroupingArgs = 31
+)
+
+var errTooManyGroupingSets = pgerror.Newf(pgcode.StatementTooComplex,
+	"too many grouping sets present (maximum %d)", maxGroupingSets)
+
+var errGroupingArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level")
+
+// hasGroupingSets returns true if the GROUP BY clause contains ROLLUP, CUBE or
+// GROUPING SETS.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandGroupingSets returns the list of grouping sets described by a GROUP
+// BY clause. Each grouping set is a list of GROUP BY expressions. A tuple in a
+// grouping set represents multiple GROUP BY expressions (the empty tuple
+// represents none).
+//
+// As in Postgres, the top-level items of the GROUP BY clause are combined
+// using a cross product. For example, this clause:
+//
+//   GROUP BY a, ROLLUP (b, c)
+//
+// is expanded to:
+//
+//   GROUPING SETS ((a, b, c), (a, b), (a))
+//
+func expandGroupingSets(groupBy tree.GroupBy) [][]tree.Expr {
+	sets := [][]tree.Expr{nil}
+	for _, e := range groupBy {
+		sets = crossGroupingSets(sets, expandGroupingSetItem(e))
+	}
+	return sets
+}
+
+// expandGroupingSetItem returns the list of grouping sets described by an
+// item of a GROUP BY clause or of GROUPING SETS. See expandGroupingSets.
+func expandGroupingSetItem(e tree.Expr) [][]tree.Expr {
+	gs, ok := e.(*tree.GroupingSet)
+	if !ok {
+		return [][]tree.Expr{{e}}
+	}
+
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b) is GROUPING SETS ((a, b), (a), ()).
+		sets := make([][]tree.Expr, len(gs.Exprs)+1)
+		for i := range sets {
+			sets[i] = gs.Exprs[:len(gs.Exprs)-i]
+		}
+		return sets
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) is GROUPING SETS ((a, b), (a), (b), ()).
+		n := len(gs.Exprs)
+		if n > maxCubeElements {
+			panic(pgerror.Newf(pgcode.TooManyColumns, "CUBE is limited to %d elements", maxCubeElements))
+		}
+		sets := make([][]tree.Expr, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set []tree.Expr
+			for i := range gs.Exprs {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, gs.Exprs[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	default:
+		var sets [][]tree.Expr
+		for _, item := range gs.Exprs {
+			sets = append(sets, expandGroupingSetItem(item)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+	}
+}
+
+// crossGroupingSets returns the cross product of two lists of grouping sets.
+func crossGroupingSets(left, right [][]tree.Expr) [][]tree.Expr {
+	if len(left)*len(right) > maxGroupingSets {
+		panic(errTooManyGroupingSets)
+	}
+	res := make([][]tree.Expr, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			// Limit the capacity of l so that append always makes a copy.
+			res = append(res, append(l[:len(l):len(l)], r...))
+		}
+	}
+	return res
+}
+
+// buildGroupingSets builds the grouping columns for a GROUP BY clause which
+// contains ROLLUP, CUBE or GROUPING SETS. Each distinct GROUP BY expression is
+// built once as a grouping column, as in buildGroupingList.
+//
+// If there are multiple grouping sets, buildGroupingSets also adds the
+// grouping set ordinal column to the grouping columns, and replaces each
+// grouping column which is not part of all of the grouping sets with an
+// expression that produces NULL for the grouping sets which do not include it.
+// See the comment at the top of the file.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope *scope, fromScope *scope,
+) {
+	g := fromScope.groupby
+	sets := expandGroupingSets(groupBy)
+
+	colSets := make([]opt.ColSet, len(sets))
+	for i, set := range sets {
+		for _, e := range set {
+			colSets[i].UnionWith(b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope))
+		}
+	}
+	if len(sets) == 1 {
+		// There is a single grouping set, so this is a regular GROUP BY.
+		return
+	}
+
+	// Add the grouping set ordinal column after the other grouping columns.
+	md := b.factory.Metadata()
+	g.groupingSetCol = md.AddColumn("grouping_set", types.Int)
+	g.aggInScope.cols = append(g.aggInScope.cols, scopeColumn{
+		name:       scopeColName("grouping_set"),
+		typ:        types.Int,
+		id:         g.groupingSetCol,
+		visibility: inaccessible,
+	})
+
+	groupingCols := g.groupingCols()
+	groupingCols = groupingCols[:len(groupingCols)-1]
+	g.groupingSets = make([]opt.ColSet, len(sets))
+	for i := range groupingCols {
+		col := &groupingCols[i]
+		origID := col.id
+
+		var whens memo.ScalarListExpr
+		for j := range colSets {
+			if !colSets[j].Contains(origID) {
+				whens = append(whens, b.factory.ConstructWhen(
+					b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(j)), types.Int),
+					b.factory.ConstructNull(col.typ),
+				))
+			}
+		}
+		if len(whens) > 0 {
+			orElse := col.scalar
+			if orElse == nil {
+				orElse = b.factory.ConstructVariable(origID)
+			}
+			input := b.factory.ConstructVariable(g.groupingSetCol)
+			b.populateSynthesizedColumn(col, b.factory.ConstructCase(input, whens, orElse))
+
+			// The column no longer produces the value of the GROUP BY expression,
+			// so make sure it is not reused for other references to the
+			// expression (for example, in the arguments of aggregate functions).
+			col.expr = nil
+			col.exprStr = ""
+
+			for k, c := range g.groupStrs {
+				if c.id == origID {
+					g.groupStrs[k] = col
+				}
+			}
+		}
+		for j := range colSets {
+			if colSets[j].Contains(origID) {
+				g.groupingSets[j].Add(col.id)
+			}
+		}
+	}
+}
+
+// constructGroupingSetsInput cross joins the input of the aggregation with a
+// VALUES expression that produces the ordinal of each grouping set, so that
+// each input row is repeated once for each grouping set.
+//
+// As in Postgres, each empty grouping set (for example, the grand total of a
+// ROLLUP) produces a row even if the input is empty, like a scalar
+// aggregation. To support this, the input is left joined to the VALUES
+// expression instead, and the rows which only exist because of the outer join
+// are kept for the empty grouping sets. Such a row is recognized by the NULL
+// value of the "present" column that is projected over the input, and it is
+// filtered out of the input of each aggregate function (see
+// addGroupingSetsFilter). For example:
+//
+//   SELECT count(*) FROM t GROUP BY ROLLUP (a)
+//
+//   input:  (VALUES (0), (1)) AS v(grouping_set)
+//             LEFT JOIN (SELECT *, true AS present FROM t) ON true
+//             WHERE present OR grouping_set IN (1)
+//   aggregation: group by a', grouping_set, calculate
+//                count(*) FILTER (WHERE present)
+//
+func (b *Builder) constructGroupingSetsInput(fromScope *scope) {
+	g := fromScope.groupby
+	tupleTyp := types.MakeTuple([]*types.T{types.Int})
+	rows := make(memo.ScalarListExpr, len(g.groupingSets))
+	var emptySets memo.ScalarListExpr
+	for i := range rows {
+		ordinal := b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int)
+		rows[i] = b.factory.ConstructTuple(memo.ScalarListExpr{ordinal}, tupleTyp)
+		if g.groupingSets[i].Empty() {
+			emptySets = append(emptySets, ordinal)
+		}
+	}
+	values := b.factory.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{g.groupingSetCol},
+		ID:   b.factory.Metadata().NextUniqueID(),
+	})
+	if len(emptySets) == 0 {
+		fromScope.expr = b.factory.ConstructInnerJoin(
+			fromScope.expr, values, memo.TrueFilter, memo.EmptyJoinPrivate,
+		)
+		return
+	}
+
+	g.groupingSetPresentCol = b.factory.Metadata().AddColumn("grouping_set_present", types.Bool)
+	input := b.factory.ConstructProject(
+		fromScope.expr,
+		memo.ProjectionsExpr{b.factory.ConstructProjectionsItem(memo.TrueSingleton, g.groupingSetPresentCol)},
+		fromScope.expr.Relational().OutputCols,
+	)
+	join := b.factory.ConstructLeftJoin(values, input, memo.TrueFilter, memo.EmptyJoinPrivate)
+	emptySetsTyp := make([]*types.T, len(emptySets))
+	for i := range emptySetsTyp {
+		emptySetsTyp[i] = types.Int
+	}
+	filter := b.factory.ConstructOr(
+		b.factory.ConstructVariable(g.groupingSetPresentCol),
+		b.factory.ConstructIn(
+			b.factory.ConstructVariable(g.groupingSetCol),
+			b.factory.ConstructTuple(emptySets, types.MakeTuple(emptySetsTyp)),
+		),
+	)
+	fromScope.expr = b.factory.ConstructSelect(join, memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)})
+
+	// Pass the present column through the pre-projection. The grouping columns
+	// must remain at the end of the aggInScope columns.
+	n := len(g.aggInScope.cols) - g.numGroupingCols()
+	cols := make([]scopeColumn, 0, len(g.aggInScope.cols)+1)
+	cols = append(cols, g.aggInScope.cols[:n]...)
+	cols = append(cols, scopeColumn{
+		name:       scopeColName("grouping_set_present"),
+		typ:        types.Bool,
+		id:         g.groupingSetPresentCol,
+		visibility: inaccessible,
+	})
+	g.aggInScope.cols = append(cols, g.aggInScope.cols[n:]...)
+}
+
+// addGroupingSetsFilter returns the column which filters the rows aggregated
+// by an aggregate function with the given FILTER column (or 0 if it has no
+// FILTER clause), so that the rows added to the input for the empty grouping
+// sets are not aggregated (see constructGroupingSetsInput). If a new column is
+// needed, its projection is added to projections.
+func (b *Builder) addGroupingSetsFilter(
+	g *groupby, filterCol opt.ColumnID, projections *memo.ProjectionsExpr,
+) opt.ColumnID {
+	if g.groupingSetPresentCol == 0 {
+		return filterCol
+	}
+	if filterCol == 0 {
+		return g.groupingSetPresentCol
+	}
+	col := b.factory.Metadata().AddColumn("agg_filter", types.Bool)
+	*projections = append(*projections, b.factory.ConstructProjectionsItem(
+		b.factory.ConstructAnd(
+			b.factory.ConstructVariable(filterCol),
+			b.factory.ConstructVariable(g.groupingSetPresentCol),
+		),
+		col,
+	))
+	return col
+}
+
+// constructGroupingSetsFilters projects the columns built by
+// addGroupingSetsFilter over the input of the aggregation.
+func (b *Builder) constructGroupingSetsFilters(
+	input memo.RelExpr, projections memo.ProjectionsExpr,
+) memo.RelExpr {
+	if len(projections) == 0 {
+		return input
+	}
+	return b.factory.ConstructProject(input, projections, input.Relational().OutputCols)
+}
+
+// buildGroupingFunc builds a GROUPING() function, which returns a bit mask
+// with a bit for each argument (the last argument is the least significant
+// bit). A bit is set if the argument is not part of the grouping set of the
+// current row.
+func (b *Builder) buildGroupingFunc(f *tree.GroupingFuncExpr, inScope *scope) opt.ScalarExpr {
+	if !inScope.inGroupingContext() || inScope.inAgg || inScope.groupby.buildingGroupingCols {
+		panic(errGroupingArgs)
+	}
+	if len(f.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1))
+	}
+	g := inScope.groupby
+
+	cols := make([]opt.ColumnID, len(f.Exprs))
+	for i, e := range f.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(tree.StripParens(e))]
+		if !ok {
+			panic(errGroupingArgs)
+		}
+		cols[i] = col.id
+	}
+
+	if len(g.groupingSets) == 0 {
+		// All of the grouping columns are part of the only grouping set.
+		return b.factory.ConstructConstVal(tree.NewDInt(0), types.Int)
+	}
+
+	masks := make([]tree.DInt, len(g.groupingSets))
+	constant := true
+	for i := range g.groupingSets {
+		for _, col := range cols {
+			masks[i] <<= 1
+			if !g.groupingSets[i].Contains(col) {
+				masks[i] |= 1
+			}
+		}
+		constant = constant && masks[i] == masks[0]
+	}
+	if constant {
+		return b.factory.ConstructConstVal(tree.NewDInt(masks[0]), types.Int)
+	}
+
+	last := len(masks) - 1
+	whens := make(memo.ScalarListExpr, last)
+	for i := range whens {
+		whens[i] = b.factory.ConstructWhen(
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			b.factory.ConstructConstVal(tree.NewDInt(masks[i]), types.Int),
+		)
+	}
+	input := b.factory.ConstructVariable(g.groupingSetCol)
+	orElse := b.factory.ConstructConstVal(tree.NewDInt(masks[last]), types.Int)
+	return b.factory.ConstructCase(input, whens, orElse)
+}
@@ -182,7 +182,12 @@ func init() {
 // and thereby scrambles the input ordering.
 func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope) {
 	// Find which table we're working on, check the permissions.
-	tab, depName, alias, refColumns := b.resolveTableForMutation(ins.Table, privilege.INSERT)
+	tab, depName, alias, refColumns := b.resolveTableForMutation(ins.Table, 0 /* priv */)
+
+	// The INSERT privilege may have been granted only on some columns of the
+	// table, in which case it is checked on the target columns once they are
+	// known.
+	checkInsertColumns := b.checkTableOrColumnPrivilege(depName, tab, privilege.INSERT)
 
 	// It is possible to insert into specific columns using table reference
 	// syntax:
@@ -222,6 +227,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	} else {
 		mb.init(b, "insert", tab, alias)
 	}
+	mb.initRowLevelSecurity()
 
 	// Compute target columns in two cases:
 	//
@@ -272,6 +278,16 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		mb.buildInputForInsert(inScope, nil /* rows */)
 	}
 
+	if checkInsertColumns {
+		mb.checkTargetColumnPrivileges(privilege.INSERT)
+	}
+
+	// Upserts do not support triggers yet. Check for them before the input of
+	// any BEFORE triggers is built by addSynthesizedColsForInsert.
+	if ins.OnConflict != nil && !ins.OnConflict.DoNothing {
+		mb.checkNoRowTriggers()
+	}
+
 	// Add default columns that were not explicitly specified by name or
 	// implicitly targeted by input columns. Also add any computed columns. In
 	// both cases, include columns undergoing mutations in the write-only state.
@@ -362,6 +378,12 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 // of edge cases (that caused real correctness bugs #13437 #13962). As a result,
 // this support was removed and needs to re-enabled. See #14482.
 func (mb *mutationBuilder) needExistingRows() bool {
+	// The existing rows are needed to enforce the row-level security policies
+	// of the table on the rows which are updated.
+	if mb.rowLevelSecurity {
+		return true
+	}
+
 	if mb.tab.DeletableIndexCount() > 1 {
 		return true
 	}
@@ -660,6 +682,10 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
 
+	// BEFORE triggers can modify any of the values added so far, so they must
+	// run before the remaining columns are computed.
+	mb.buildInputForBeforeTriggers(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
 
@@ -677,17 +703,27 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(false /* isUpdate */)
 
+	// Add the row-level security policy check column to the input.
+	mb.projectPolicyCheckCol(policyInsert)
+
 	// Project partial index PUT boolean columns.
 	mb.projectPartialIndexPutCols()
 
 	mb.buildUniqueChecksForInsert()
 
+	mb.buildExclusionChecksForInsert()
+
+	mb.buildPolicyCheck()
+
 	mb.buildFKChecksForInsert()
 
+	mb.buildRowTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
+	mb.buildBeforeTriggersWith()
 
 	mb.buildReturning(returning)
 }
@@ -699,6 +735,11 @@ func (mb *mutationBuilder) buildInputForDoNothing(inScope *scope, onConflict *tr
 	// Determine the set of arbiter indexes and constraints to use to check for
 	// conflicts.
 	mb.arbiters = mb.findArbiters(onConflict)
+	if len(mb.arbiters.UniqueConstraintOrdinals()) > 0 {
+		// Conflicts with UNIQUE WITHOUT INDEX constraints are detected by
+		// searching the table, like the checks of those constraints.
+		mb.checkIsolationForConflictSearch("UNIQUE WITHOUT INDEX")
+	}
 	insertColScope := mb.outScope.replace()
 	insertColScope.appendColumnsFromScope(mb.outScope)
 
@@ -745,6 +786,11 @@ func (mb *mutationBuilder) buildInputForUpsert(
 	// Determine the set of arbiter indexes and constraints to use to check for
 	// conflicts.
 	mb.arbiters = mb.findArbiters(onConflict)
+	if len(mb.arbiters.UniqueConstraintOrdinals()) > 0 {
+		// Conflicts with UNIQUE WITHOUT INDEX constraints are detected by
+		// searching the table, like the checks of those constraints.
+		mb.checkIsolationForConflictSearch("UNIQUE WITHOUT INDEX")
+	}
 	// TODO(mgartner): Add support for multiple arbiter indexes or constraints,
 	//  similar to buildInputForDoNothing.
 	if mb.arbiters.Len() > 1 {
@@ -885,6 +931,9 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(false /* isUpdate */)
 
+	// Add the row-level security policy check column to the input.
+	mb.projectPolicyCheckCol(policyInsert)
+
 	// Add the partial index predicate expressions to the table metadata.
 	// These expressions are used to prune fetch columns during
 	// normalization.
@@ -905,8 +954,14 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpsert()
 
+	mb.buildExclusionChecksForUpsert()
+
+	mb.buildPolicyCheck()
+
 	mb.buildFKChecksForUpsert()
 
+	mb.checkNoRowTriggers()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -353,8 +353,8 @@ func (jb *usingJoinBuilder) buildUsingJoin(using *tree.UsingJoinCond) {
 			jb.raiseUndefinedColError(name, "right")
 		}
 
-		jb.b.trackReferencedColumnForViews(leftCol)
-		jb.b.trackReferencedColumnForViews(rightCol)
+		jb.b.trackReferencedColumn(leftCol)
+		jb.b.trackReferencedColumn(rightCol)
 		jb.addEqualityCondition(leftCol, rightCol)
 	}
 
@@ -385,8 +385,8 @@ func (jb *usingJoinBuilder) buildNaturalJoin(natural tree.NaturalJoinCond) {
 
 		rightCol := jb.findUsingColumn(jb.rightScope.cols, leftCol.name.ReferenceName(), "right table")
 		if rightCol != nil {
-			jb.b.trackReferencedColumnForViews(leftCol)
-			jb.b.trackReferencedColumnForViews(rightCol)
+			jb.b.trackReferencedColumn(leftCol)
+			jb.b.trackReferencedColumn(rightCol)
 			jb.addEqualityCondition(leftCol, rightCol)
 		}
 	}
@@ -0,0 +1,728 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// duplicateMergeErrText is the error raised when a row of the target table of
+// a MERGE statement is matched by more than one source row.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// buildMerge builds a memo group for a MERGE statement, which applies the
+// actions of its WHEN clauses to the rows of a target table depending on
+// whether they are matched by the rows of a source table expression:
+//
+//   MERGE INTO abc USING xyz ON a = x
+//   WHEN MATCHED AND z > 0 THEN UPDATE SET b = y
+//   WHEN MATCHED THEN DELETE
+//   WHEN NOT MATCHED THEN INSERT VALUES (x, y, z)
+//
+// The source is left-joined with the target table, and each joined row is
+// assigned the action of the first WHEN clause that applies to it (see
+// buildInputForMerge). The actions are then carried out by the existing
+// mutation operators:
+//
+//   1. If the statement only inserts, only updates, or only deletes rows, a
+//      single Insert, Update, or Delete operator is built.
+//
+//   2. If the statement inserts and updates rows, an Upsert operator is built.
+//      A primary key column fetched from the target table is used as the
+//      canary column, so matched rows are updated and the others inserted.
+//
+//   3. If the statement deletes rows and also inserts or updates rows, the
+//      input is buffered by a With expression. A Delete operator and an
+//      Insert, Update, or Upsert operator are built on top of the buffer in
+//      their own With expressions, and the statement counts the rows of the
+//      buffer to report the number of affected rows. Since the table is
+//      mutated twice, this is only allowed if multiple modifications of the
+//      table are enabled (see checkMultipleMutations).
+//
+// Rows whose action is DO NOTHING, and rows to which no WHEN clause applies,
+// are not affected by the statement.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	var hasInsert, hasUpdate, hasDelete bool
+	for i, when := range merge.Whens {
+		switch when.Action.(type) {
+		case *tree.MergeInsert:
+			hasInsert = true
+		case *tree.MergeUpdate:
+			hasUpdate = true
+		case *tree.MergeDelete:
+			hasDelete = true
+		}
+
+		// Like Postgres, disallow WHEN clauses that can never apply to a row.
+		if when.Cond == nil {
+			for _, later := range merge.Whens[i+1:] {
+				if later.Matched == when.Matched {
+					panic(pgerror.Newf(pgcode.Syntax,
+						"unreachable WHEN clause specified after unconditional WHEN clause"))
+				}
+			}
+		}
+	}
+
+	// Find which table we're working on, check the permissions. Select
+	// permission is always needed, since the target table is joined with the
+	// source.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	if hasInsert {
+		b.checkPrivilege(depName, tab, privilege.INSERT)
+	}
+	if hasUpdate {
+		b.checkPrivilege(depName, tab, privilege.UPDATE)
+	}
+	if hasDelete {
+		b.checkPrivilege(depName, tab, privilege.DELETE)
+	}
+
+	// Check if this table has already been mutated in another subquery.
+	b.checkMultipleMutations(tab, false /* simpleInsert */)
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+	mb.initRowLevelSecurity()
+	if mb.rowLevelSecurity {
+		panic(unimplemented.New("merge-row-level-security",
+			"MERGE is not supported on tables with row-level security enabled"))
+	}
+	if mb.hasUserBeforeTriggers() {
+		panic(unimplemented.NewWithIssuef(28296,
+			"MERGE into table %q with BEFORE triggers is not supported", tab.Name()))
+	}
+
+	// Build the input expression that joins the source with the target table
+	// and determines the action for each row.
+	numSourceCols := mb.buildInputForMerge(inScope, merge)
+	numFetchCols := len(mb.fetchScope.cols)
+
+	switch {
+	case !hasInsert && !hasUpdate && !hasDelete:
+		// Every WHEN clause does nothing, so no rows are affected.
+		return b.buildMergeRowCount(inScope, mb.outScope)
+
+	case !hasDelete || (!hasInsert && !hasUpdate):
+		// The actions can be carried out by a single mutation operator.
+		mb.buildMergeMutation(inScope, merge, numFetchCols, numSourceCols, hasDelete)
+		return mb.outScope
+	}
+
+	// The rows are deleted by one mutation of the target table and inserted or
+	// updated by another, so the statement is subject to the same restrictions
+	// as multiple modification subqueries of the table: the second mutation
+	// would not see the rows written by the first one.
+	b.checkMultipleMutations(tab, false /* simpleInsert */)
+
+	// Buffer the input so that the rows can be deleted by one mutation and
+	// inserted or updated by another. Materialize all of the With expressions
+	// so that each of them is executed exactly once.
+	f := b.factory
+	md := f.Metadata()
+	inputScope := mb.outScope
+	mtr := tree.MaterializeClause{Set: true, Materialize: true}
+	withIDs := make([]opt.WithID, 3)
+	bindings := make([]memo.RelExpr, 3)
+	withIDs[0] = f.Memo().NextWithID()
+	bindings[0] = inputScope.expr
+	md.AddWithBinding(withIDs[0], bindings[0])
+
+	for i, deleteRows := range []bool{true, false} {
+		var mutationMB mutationBuilder
+		mutationMB.init(b, "merge", tab, alias)
+		mutationMB.buildMergeWithScanInput(
+			inScope, withIDs[0], inputScope, numFetchCols, numSourceCols, deleteRows,
+		)
+		mutationMB.buildMergeMutation(inScope, merge, numFetchCols, numSourceCols, deleteRows)
+		withIDs[i+1] = f.Memo().NextWithID()
+		bindings[i+1] = mutationMB.outScope.expr
+		md.AddWithBinding(withIDs[i+1], bindings[i+1])
+	}
+
+	// The number of affected rows is the number of rows of the buffered input,
+	// since each of them is deleted, inserted, or updated.
+	outScope = b.buildMergeRowCount(inScope, b.buildMergeWithScan(inScope, withIDs[0], inputScope))
+	for i := len(withIDs) - 1; i >= 0; i-- {
+		outScope.expr = f.ConstructWith(bindings[i], outScope.expr, &memo.WithPrivate{
+			ID:           withIDs[i],
+			Name:         "merge",
+			Mtr:          mtr,
+			OriginalExpr: merge,
+		})
+	}
+	return outScope
+}
+
+// buildInputForMerge constructs the input expression of a MERGE statement. It
+// left-joins the source table expression with the target table using the ON
+// condition, and projects an action column that identifies the WHEN clause
+// that applies to each row:
+//
+//   SELECT <fetch-cols>, <source-cols>, <action> AS merge_action
+//   FROM <source> LEFT JOIN <table> ON <on-cond>
+//   WHERE merge_action != 0
+//
+// where <action> is:
+//
+//   CASE
+//     WHEN <canary> IS NULL THEN
+//       CASE WHEN <not-matched-cond1> THEN 1 WHEN ... ELSE 0 END
+//     ELSE
+//       CASE WHEN <matched-cond1> THEN 1 WHEN ... ELSE 0 END
+//   END
+//
+// The action of the i-th WHEN clause is i+1, negated if the clause deletes the
+// row, or 0 if the clause does nothing. Rows without an action are filtered
+// out. If any WHEN clause updates or deletes matched rows, the rows are also
+// required to be distinct on the primary key of the target table, so that an
+// error is raised if a row is matched by more than one source row.
+//
+// The output scope contains the fetch columns, followed by the source columns
+// and the action column. buildInputForMerge returns the number of source
+// columns.
+func (mb *mutationBuilder) buildInputForMerge(inScope *scope, merge *tree.Merge) (numSourceCols int) {
+	var indexFlags *tree.IndexFlags
+	if source, ok := merge.Table.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
+		indexFlags = source.IndexFlags
+		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
+	}
+
+	// Fetch columns from different instance of the table metadata, so that the
+	// target table can be joined with itself. See buildInputForUpdate.
+	mb.fetchScope = mb.b.buildScan(
+		mb.b.addTable(mb.tab, &mb.alias),
+		tableOrdinals(mb.tab, columnKinds{
+			includeMutations: true,
+			includeSystem:    true,
+			includeInverted:  false,
+		}),
+		indexFlags,
+		noRowLocking,
+		inScope,
+	)
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	sourceScope := mb.b.buildFromTables(tree.TableExprs{merge.Source}, noRowLocking, inScope)
+	numSourceCols = len(sourceScope.cols)
+
+	// Check that the same table name is not used by the source and target.
+	mb.b.validateJoinTableNames(mb.fetchScope, sourceScope)
+
+	// Both the fetch and source columns are visible to the ON condition.
+	joinScope := inScope.push()
+	joinScope.appendColumnsFromScope(mb.fetchScope)
+	joinScope.appendColumnsFromScope(sourceScope)
+	on := mb.b.resolveAndBuildScalar(
+		merge.On,
+		types.Bool,
+		exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications,
+		joinScope,
+	)
+	joinScope.expr = mb.b.factory.ConstructLeftJoin(
+		sourceScope.expr,
+		mb.fetchScope.expr,
+		memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(on)},
+		memo.EmptyJoinPrivate,
+	)
+
+	// Record a not-null "canary" column of the target table. After the
+	// left-join, it is null if the source row did not match any row.
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	canaryColID := mb.fetchColIDs[primaryIndex.Column(0).Ordinal()]
+
+	// Conditions of WHEN NOT MATCHED clauses can only refer to the source.
+	notMatchedScope := mergeSourceScope(inScope, joinScope, len(mb.fetchScope.cols), numSourceCols)
+
+	f := mb.b.factory
+	var matchedWhens, notMatchedWhens memo.ScalarListExpr
+	updatesOrDeletesMatched := false
+	for i, when := range merge.Whens {
+		var cond opt.ScalarExpr = memo.TrueSingleton
+		if when.Cond != nil {
+			condScope := joinScope
+			if !when.Matched {
+				condScope = notMatchedScope
+			}
+			cond = mb.b.resolveAndBuildScalar(
+				when.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, condScope,
+			)
+		}
+
+		action := i + 1
+		switch when.Action.(type) {
+		case *tree.MergeDoNothing:
+			action = 0
+		case *tree.MergeDelete:
+			action = -action
+		}
+		if action != 0 && when.Matched {
+			updatesOrDeletesMatched = true
+		}
+
+		actionWhen := f.ConstructWhen(cond, f.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int))
+		if when.Matched {
+			matchedWhens = append(matchedWhens, actionWhen)
+		} else {
+			notMatchedWhens = append(notMatchedWhens, actionWhen)
+		}
+	}
+
+	noAction := f.ConstructConstVal(tree.NewDInt(0), types.Int)
+	actionCase := func(whens memo.ScalarListExpr) opt.ScalarExpr {
+		if len(whens) == 0 {
+			return noAction
+		}
+		return f.ConstructCase(memo.TrueSingleton, whens, noAction)
+	}
+	actionExpr := f.ConstructCase(
+		memo.TrueSingleton,
+		memo.ScalarListExpr{
+			f.ConstructWhen(
+				f.ConstructIs(f.ConstructVariable(canaryColID), memo.NullSingleton),
+				actionCase(notMatchedWhens),
+			),
+		},
+		actionCase(matchedWhens),
+	)
+
+	projectionsScope := joinScope.replace()
+	projectionsScope.appendColumnsFromScope(joinScope)
+	actionColID := mb.b.synthesizeColumn(
+		projectionsScope,
+		scopeColName("").WithMetadataName("merge_action"),
+		types.Int,
+		nil, /* expr */
+		actionExpr,
+	).id
+	mb.b.constructProjectForScope(joinScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Filter out the rows that are not affected.
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructNe(f.ConstructVariable(actionColID), noAction),
+		)},
+	)
+
+	// Ensure that each row of the target table is updated or deleted at most
+	// once. Rows that are inserted have null fetch columns, and are always
+	// distinct.
+	if updatesOrDeletesMatched {
+		var pkCols opt.ColSet
+		for i, n := 0, primaryIndex.KeyColumnCount(); i < n; i++ {
+			pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+		}
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+		)
+	}
+
+	return numSourceCols
+}
+
+// mergeSourceScope returns a scope in which only the source columns of the
+// given MERGE input scope are visible. It is used to build the conditions and
+// values of WHEN NOT MATCHED clauses, which cannot refer to the target table.
+func mergeSourceScope(inScope, mergeScope *scope, numFetchCols, numSourceCols int) *scope {
+	sourceScope := inScope.push()
+	sourceScope.appendColumns(mergeScope.cols[numFetchCols : numFetchCols+numSourceCols])
+	return sourceScope
+}
+
+// buildMergeWithScan returns a scope with the same columns as bindingScope,
+// which scans the given With binding of the buffered MERGE input. The columns
+// of the scope are given new column IDs.
+func (b *Builder) buildMergeWithScan(
+	inScope *scope, withID opt.WithID, bindingScope *scope,
+) (outScope *scope) {
+	md := b.factory.Metadata()
+	inCols := make(opt.ColList, len(bindingScope.cols))
+	outCols := make(opt.ColList, len(bindingScope.cols))
+	outScope = inScope.push()
+	for i, col := range bindingScope.cols {
+		inCols[i] = col.id
+		outCols[i] = md.AddColumn(col.name.MetadataName(), col.typ)
+		col.id = outCols[i]
+		col.scalar = nil
+		outScope.cols = append(outScope.cols, col)
+	}
+	outScope.expr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    withID,
+		Name:    "merge",
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      md.NextUniqueID(),
+	})
+	return outScope
+}
+
+// buildMergeWithScanInput sets the input of the mutation to the rows of the
+// buffered MERGE input that are deleted, if deleteRows is true, or else to the
+// rows that are inserted or updated. See buildInputForMerge for the layout of
+// bindingScope.
+func (mb *mutationBuilder) buildMergeWithScanInput(
+	inScope *scope,
+	withID opt.WithID,
+	bindingScope *scope,
+	numFetchCols, numSourceCols int,
+	deleteRows bool,
+) {
+	mb.outScope = mb.b.buildMergeWithScan(inScope, withID, bindingScope)
+
+	// The fetch columns are the first columns of the input.
+	mb.fetchScope = mb.b.allocScope()
+	mb.fetchScope.appendColumns(mb.outScope.cols[:numFetchCols])
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// DELETE actions are negative, and INSERT and UPDATE actions positive.
+	f := mb.b.factory
+	action := f.ConstructVariable(mb.outScope.cols[numFetchCols+numSourceCols].id)
+	noAction := f.ConstructConstVal(tree.NewDInt(0), types.Int)
+	var filter opt.ScalarExpr
+	if deleteRows {
+		filter = f.ConstructLt(action, noAction)
+	} else {
+		filter = f.ConstructGt(action, noAction)
+	}
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(filter)},
+	)
+}
+
+// buildMergeRowCount returns a scope with a single column that counts the rows
+// of the given scope, which is used as the number of rows affected by a MERGE
+// statement that is not carried out by a single mutation operator.
+func (b *Builder) buildMergeRowCount(inScope, rowsScope *scope) (outScope *scope) {
+	outScope = inScope.push()
+	countColID := b.synthesizeColumn(
+		outScope, scopeColName("count"), types.Int, nil /* expr */, nil, /* scalar */
+	).id
+	outScope.expr = b.factory.ConstructScalarGroupBy(
+		rowsScope.expr,
+		memo.AggregationsExpr{
+			b.factory.ConstructAggregationsItem(b.factory.ConstructCountRows(), countColID),
+		},
+		&memo.GroupingPrivate{},
+	)
+	return outScope
+}
+
+// buildMergeMutation builds the mutation operator that carries out the actions
+// of a MERGE statement on the rows of mb.outScope, which has the layout
+// described in buildInputForMerge. If deleteRows is true, all of the rows are
+// deleted. Otherwise, they are inserted or updated by the INSERT and UPDATE
+// actions of the WHEN clauses.
+func (mb *mutationBuilder) buildMergeMutation(
+	inScope *scope, merge *tree.Merge, numFetchCols, numSourceCols int, deleteRows bool,
+) {
+	if deleteRows {
+		mb.buildDelete(nil /* returning */)
+		return
+	}
+
+	hasInsert, hasUpdate := mb.addMergeCols(inScope, merge, numFetchCols, numSourceCols)
+	switch {
+	case hasInsert && hasUpdate:
+		// Matched rows are updated and the others are inserted, depending on
+		// whether the canary column is null.
+		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+		mb.canaryColID = mb.fetchColIDs[primaryIndex.Column(0).Ordinal()]
+
+		mb.addSynthesizedColsForMergeInsert()
+
+		// Add additional columns for computed expressions that may depend on any
+		// updated columns, as well as mutation columns with default values.
+		mb.addSynthesizedColsForUpdate()
+
+		mb.buildUpsert(nil /* returning */)
+
+	case hasInsert:
+		mb.addSynthesizedColsForMergeInsert()
+
+		// None of the inserted rows exist in the table, so nothing is fetched.
+		for i := range mb.fetchColIDs {
+			mb.fetchColIDs[i] = 0
+		}
+		mb.buildInsert(nil /* returning */)
+
+	default:
+		mb.addSynthesizedColsForUpdate()
+		mb.buildUpdate(nil /* returning */)
+	}
+}
+
+// mergeValue is the value assigned to a column by an INSERT or UPDATE action
+// of a MERGE statement.
+type mergeValue struct {
+	action opt.ScalarExpr
+	value  opt.ScalarExpr
+}
+
+// addMergeCols projects a column for each table column that is assigned by the
+// INSERT or UPDATE actions of a MERGE statement. If more than one action
+// can apply to the rows, the column is a CASE expression on the action column:
+//
+//   CASE merge_action WHEN 1 THEN <value1> WHEN 3 THEN <value3> ELSE ... END
+//
+// Inserted values fall back to NULL, and updated values to the fetched value
+// of the column. If a column is assigned by one INSERT action, the other INSERT
+// actions assign its default value. The values of INSERT actions can only
+// refer to the source columns.
+//
+// addMergeCols returns whether there are any INSERT and UPDATE actions.
+func (mb *mutationBuilder) addMergeCols(
+	inScope *scope, merge *tree.Merge, numFetchCols, numSourceCols int,
+) (hasInsert, hasUpdate bool) {
+	// The values should reject aggregates, generators, etc.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE", tree.RejectSpecial)
+
+	f := mb.b.factory
+	mergeScope := mb.outScope
+	sourceScope := mergeSourceScope(inScope, mergeScope, numFetchCols, numSourceCols)
+	actionColID := mergeScope.cols[numFetchCols+numSourceCols].id
+
+	// buildValue builds the value of the table column with the given ordinal,
+	// adding an assignment cast to the type of the column if necessary.
+	buildValue := func(expr tree.Expr, ord int, valueScope *scope) opt.ScalarExpr {
+		col := mb.tab.Column(ord)
+		if _, ok := expr.(tree.DefaultVal); ok {
+			expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+		}
+		texpr := valueScope.resolveType(expr, col.DatumType())
+		value := mb.b.buildScalar(texpr, valueScope, nil, nil, nil)
+		if srcType, targetType := value.DataType(), col.DatumType(); !srcType.Identical(targetType) {
+			if !cast.ValidCast(srcType, targetType, cast.ContextAssignment) {
+				panic(sqlerrors.NewInvalidAssignmentCastError(srcType, targetType, string(col.ColName())))
+			}
+			value = f.ConstructAssignmentCast(value, targetType)
+		}
+		return value
+	}
+
+	n := mb.tab.ColumnCount()
+	insertValues := make([][]mergeValue, n)
+	updateValues := make([][]mergeValue, n)
+	var insertActions []opt.ScalarExpr
+	var insertTargets []opt.ColSet
+	numUpdateActions := 0
+	for i, when := range merge.Whens {
+		action := f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int)
+		switch t := when.Action.(type) {
+		case *tree.MergeInsert:
+			mb.addTargetColsForMergeInsert(t)
+			for j, colID := range mb.targetColList {
+				ord := mb.tabID.ColumnOrdinal(colID)
+				if col := mb.tab.Column(ord); col.IsGeneratedAlwaysAsIdentity() {
+					if _, ok := t.Values[j].(tree.DefaultVal); !ok {
+						panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnOverrideError(string(col.ColName())))
+					}
+				}
+				insertValues[ord] = append(insertValues[ord], mergeValue{
+					action: action,
+					value:  buildValue(t.Values[j], ord, sourceScope),
+				})
+			}
+			insertActions = append(insertActions, action)
+			insertTargets = append(insertTargets, mb.targetColSet.Copy())
+
+		case *tree.MergeUpdate:
+			mb.addTargetColsForMergeUpdate(t.Exprs)
+			var exprs tree.Exprs
+			for _, set := range t.Exprs {
+				if set.Tuple {
+					exprs = append(exprs, set.Expr.(*tree.Tuple).Exprs...)
+				} else {
+					exprs = append(exprs, set.Expr)
+				}
+			}
+			for j, colID := range mb.targetColList {
+				ord := mb.tabID.ColumnOrdinal(colID)
+				if col := mb.tab.Column(ord); col.IsGeneratedAlwaysAsIdentity() {
+					if _, ok := exprs[j].(tree.DefaultVal); !ok {
+						panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnUpdateError(string(col.ColName())))
+					}
+				}
+				updateValues[ord] = append(updateValues[ord], mergeValue{
+					action: action,
+					value:  buildValue(exprs[j], ord, mergeScope),
+				})
+			}
+			numUpdateActions++
+		}
+	}
+
+	// Columns that are assigned by some INSERT actions get their default values
+	// from the other INSERT actions.
+	for ord := range insertValues {
+		if len(insertValues[ord]) == 0 {
+			continue
+		}
+		colID := mb.tabID.ColumnID(ord)
+		for k, action := range insertActions {
+			if !insertTargets[k].Contains(colID) {
+				insertValues[ord] = append(insertValues[ord], mergeValue{
+					action: action,
+					value:  buildValue(tree.DefaultVal{}, ord, sourceScope),
+				})
+			}
+		}
+	}
+
+	projectionsScope := mergeScope.replace()
+	projectionsScope.appendColumnsFromScope(mergeScope)
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+
+	// addCol projects the values of a table column, using a CASE expression on
+	// the action column unless every row takes the same action. It is
+	// important to use the real column reference name, as this column may
+	// later be referred to by a computed column.
+	addCol := func(
+		ord int, values []mergeValue, orElse opt.ScalarExpr, singleAction bool, suffix string,
+	) opt.ColumnID {
+		col := mb.tab.Column(ord)
+		var value opt.ScalarExpr
+		if singleAction {
+			value = values[0].value
+		} else {
+			whens := make(memo.ScalarListExpr, len(values))
+			for i := range values {
+				whens[i] = f.ConstructWhen(values[i].action, values[i].value)
+			}
+			value = f.ConstructCase(f.ConstructVariable(actionColID), whens, orElse)
+		}
+		name := scopeColName(col.ColName()).WithMetadataName(string(col.ColName()) + suffix)
+		scopeCol := mb.b.synthesizeColumn(projectionsScope, name, col.DatumType(), nil /* expr */, value)
+
+		// Add corresponding target column.
+		if colID := mb.tabID.ColumnID(ord); !mb.targetColSet.Contains(colID) {
+			mb.targetColList = append(mb.targetColList, colID)
+			mb.targetColSet.Add(colID)
+		}
+		return scopeCol.id
+	}
+
+	for ord := 0; ord < n; ord++ {
+		if len(insertValues[ord]) != 0 {
+			mb.insertColIDs[ord] = addCol(
+				ord,
+				insertValues[ord],
+				f.ConstructNull(mb.tab.Column(ord).DatumType()),
+				len(insertActions) == 1 && numUpdateActions == 0,
+				"_ins",
+			)
+		}
+		if len(updateValues[ord]) != 0 {
+			mb.updateColIDs[ord] = addCol(
+				ord,
+				updateValues[ord],
+				f.ConstructVariable(mb.fetchColIDs[ord]),
+				numUpdateActions == 1 && len(insertActions) == 0,
+				"_new",
+			)
+		}
+	}
+
+	mb.b.constructProjectForScope(mergeScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	return len(insertActions) != 0, numUpdateActions != 0
+}
+
+// addTargetColsForMergeInsert sets the target columns to the columns assigned
+// by the given INSERT action of a MERGE statement, in the same way as the
+// target columns of an INSERT statement.
+func (mb *mutationBuilder) addTargetColsForMergeInsert(ins *tree.MergeInsert) {
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+
+	switch {
+	case ins.Values == nil:
+		// INSERT DEFAULT VALUES assigns no columns.
+
+	case len(ins.Columns) != 0:
+		mb.addTargetNamedColsForInsert(ins.Columns)
+		mb.checkNumCols(len(mb.targetColList), len(ins.Values))
+
+	default:
+		mb.addTargetTableColsForInsert(len(ins.Values))
+	}
+}
+
+// addTargetColsForMergeUpdate sets the target columns to the columns assigned
+// by the given SET expressions of an UPDATE action of a MERGE statement.
+func (mb *mutationBuilder) addTargetColsForMergeUpdate(exprs tree.UpdateExprs) {
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+
+	for _, set := range exprs {
+		if _, ok := set.Expr.(*tree.Subquery); ok && set.Tuple {
+			panic(unimplemented.New("merge-update-subquery",
+				"multiple-column sub-SELECT is not supported in MERGE UPDATE SET"))
+		}
+	}
+	mb.addTargetColsForUpdate(exprs)
+}
+
+// addSynthesizedColsForMergeInsert is like addSynthesizedColsForInsert, except
+// that all columns other than the insert columns are hidden while the default
+// and computed values are built. This is necessary because the fetch columns,
+// and possibly the source columns, have the same names as the insert columns.
+func (mb *mutationBuilder) addSynthesizedColsForMergeInsert() {
+	type colName struct {
+		name  scopeColumnName
+		table tree.TableName
+	}
+	insertCols := mb.insertColIDs.ToSet()
+	names := make([]colName, len(mb.outScope.cols))
+	for i := range mb.outScope.cols {
+		col := &mb.outScope.cols[i]
+		names[i] = colName{name: col.name, table: col.table}
+		if !insertCols.Contains(col.id) {
+			col.clearName()
+		}
+	}
+
+	mb.addSynthesizedColsForInsert()
+
+	// The synthesized columns are appended to the existing columns, so restore
+	// the names of the existing columns.
+	for i := range names {
+		mb.outScope.cols[i].name = names[i].name
+		mb.outScope.cols[i].table = names[i].table
+	}
+}
@@ -133,6 +133,12 @@ type mutationBuilder struct {
 	// the table.
 	partialIndexDelColIDs opt.OptionalColList
 
+	// policyCheckColID is the ID of the column which stores whether each row
+	// written by the mutation satisfies the row-level security policies of the
+	// target table. It is zero if row-level security is not enforced. See
+	// projectPolicyCheckCol.
+	policyCheckColID opt.ColumnID
+
 	// canaryColID is the ID of the column that is used to decide whether to
 	// insert or update each row. If the canary column's value is null, then it's
 	// an insert; otherwise it's an update.
@@ -142,6 +148,17 @@ type mutationBuilder struct {
 	// detect conflicts for UPSERT and INSERT ON CONFLICT statements.
 	arbiters arbiterSet
 
+	// rowLevelSecurity is true if the row-level security policies of the target
+	// table are enforced on the mutation, in which case policies contains the
+	// policies which apply to the current user. See initRowLevelSecurity.
+	rowLevelSecurity bool
+	policies         []cat.Policy
+
+	// checkSelectColumns is true if the current user can only read the target
+	// table through the SELECT privileges granted on its columns, in which case
+	// the privilege is checked on the columns referenced by the statement.
+	checkSelectColumns bool
+
 	// subqueries temporarily stores subqueries that were built during initial
 	// analysis of SET expressions. They will be used later when the subqueries
 	// are joined into larger LEFT OUTER JOIN expressions.
@@ -182,6 +199,13 @@ type mutationBuilder struct {
 	// checks.
 	withID opt.WithID
 
+	// beforeTriggerWithID is nonzero if the mutated table has BEFORE row-level
+	// triggers, which read the input of the mutation from a separate binding;
+	// beforeTriggerInput is the bound expression. See
+	// buildInputForBeforeTriggers.
+	beforeTriggerWithID opt.WithID
+	beforeTriggerInput  memo.RelExpr
+
 	// extraAccessibleCols stores all the columns that are available to the
 	// mutation that are not part of the target table. This is useful for
 	// UPDATE ... FROM queries, as the columns from the FROM tables must be
@@ -285,8 +309,9 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	//
 	// NOTE: Include mutation columns, but be careful to never use them for any
 	//       reason other than as "fetch columns". See buildScan comment.
+	fetchTabMeta := mb.b.addTable(mb.tab, &mb.alias)
 	mb.fetchScope = mb.b.buildScan(
-		mb.b.addTable(mb.tab, &mb.alias),
+		fetchTabMeta,
 		tableOrdinals(mb.tab, columnKinds{
 			includeMutations: true,
 			includeSystem:    true,
@@ -297,6 +322,15 @@ func (mb *mutationBuilder) buildInputForUpdate(
 		inScope,
 	)
 
+	// Only the rows which are visible according to the row-level security
+	// policies of the table can be updated.
+	if mb.rowLevelSecurity {
+		mb.b.buildPolicyFilter(fetchTabMeta, mb.fetchScope, policySelect, policyUpdate)
+	}
+	if mb.checkSelectColumns {
+		mb.b.requireColumnPrivileges(mb.fetchScope)
+	}
+
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
 
@@ -304,26 +338,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// together with the table being updated.
 	fromClausePresent := len(from) > 0
 	if fromClausePresent {
-		fromScope := mb.b.buildFromTables(from, noRowLocking, inScope)
-
-		// Check that the same table name is not used multiple times.
-		mb.b.validateJoinTableNames(mb.fetchScope, fromScope)
-
-		// The FROM table columns can be accessed by the RETURNING clause of the
-		// query and so we have to make them accessible.
-		mb.extraAccessibleCols = fromScope.cols
-
-		// Add the columns in the FROM scope.
-		// We create a new scope so that fetchScope is not modified. It will be
-		// used later to build partial index predicate expressions, and we do
-		// not want ambiguities with column names in the FROM clause.
-		mb.outScope = mb.fetchScope.replace()
-		mb.outScope.appendColumnsFromScope(mb.fetchScope)
-		mb.outScope.appendColumnsFromScope(fromScope)
-
-		left := mb.fetchScope.expr
-		right := fromScope.expr
-		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+		mb.joinInputWithTables(inScope, from)
 	} else {
 		mb.outScope = mb.fetchScope
 	}
@@ -348,27 +363,62 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Build a distinct on to ensure there is at most one row in the joined output
 	// for every row in the table.
 	if fromClausePresent {
-		var pkCols opt.ColSet
-
-		// We need to ensure that the join has a maximum of one row for every row
-		// in the table and we ensure this by constructing a distinct on the primary
-		// key columns.
-		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
-		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
-			// If the primary key column is hidden, then we don't need to use it
-			// for the distinct on.
-			// TODO(radu): this logic seems fragile, is it assuming that only an
-			// implicit `rowid` column can be a hidden PK column?
-			if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
-				pkCols.Add(mb.fetchColIDs[col.Ordinal()])
-			}
-		}
+		mb.buildDistinctOnPrimaryKey()
+	}
+}
 
-		if !pkCols.Empty() {
-			mb.outScope = mb.b.buildDistinctOn(
-				pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+// joinInputWithTables joins the rows fetched from the target table with the
+// tables of the FROM clause of an UPDATE or the USING clause of a DELETE
+// (LATERAL joins between the tables are allowed). The tables can't reference
+// the target table. The result is stored in mb.outScope.
+func (mb *mutationBuilder) joinInputWithTables(inScope *scope, tables tree.TableExprs) {
+	tablesScope := mb.b.buildFromTables(tables, noRowLocking, inScope)
+
+	// Check that the same table name is not used multiple times.
+	mb.b.validateJoinTableNames(mb.fetchScope, tablesScope)
+
+	// The columns of the joined tables can be accessed by the RETURNING clause
+	// of the query and so we have to make them accessible.
+	mb.extraAccessibleCols = tablesScope.cols
+
+	// Add the columns of the joined tables.
+	// We create a new scope so that fetchScope is not modified. It will be
+	// used later to build partial index predicate expressions, and we do
+	// not want ambiguities with column names in the joined tables.
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+	mb.outScope.appendColumnsFromScope(tablesScope)
+
+	left := mb.fetchScope.expr
+	right := tablesScope.expr
+	mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+}
+
+// buildDistinctOnPrimaryKey wraps mb.outScope in a distinct on the primary key
+// columns of the target table. It is used when the target table is joined with
+// other tables, so that each row of the target table is mutated at most once,
+// no matter how many rows of the other tables it joins with.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var pkCols opt.ColSet
+
+	// We need to ensure that the join has a maximum of one row for every row
+	// in the table and we ensure this by constructing a distinct on the primary
+	// key columns.
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		// If the primary key column is hidden, then we don't need to use it
+		// for the distinct on.
+		// TODO(radu): this logic seems fragile, is it assuming that only an
+		// implicit `rowid` column can be a hidden PK column?
+		if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
+			pkCols.Add(mb.fetchColIDs[col.Ordinal()])
 		}
 	}
+
+	if !pkCols.Empty() {
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
 }
 
 // buildInputForDelete constructs a Select expression from the fields in
@@ -381,9 +431,18 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+// If a USING clause is defined, its tables are joined with the target table
+// in the same way as the tables of the FROM clause of an UPDATE (see
+// buildInputForUpdate), and each row of the target table is deleted at most
+// once, no matter how many rows of the USING tables it joins with.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -400,8 +459,9 @@ func (mb *mutationBuilder) buildInputForDelete(
 	// NOTE: Include mutation columns, but be careful to never use them for any
 	//       reason other than as "fetch columns". See buildScan comment.
 	// TODO(andyk): Why does execution engine need mutation columns for Delete?
+	fetchTabMeta := mb.b.addTable(mb.tab, &mb.alias)
 	mb.fetchScope = mb.b.buildScan(
-		mb.b.addTable(mb.tab, &mb.alias),
+		fetchTabMeta,
 		tableOrdinals(mb.tab, columnKinds{
 			includeMutations: true,
 			includeSystem:    true,
@@ -411,11 +471,38 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
-	mb.outScope = mb.fetchScope
+
+	// Only the rows which are visible according to the row-level security
+	// policies of the table can be deleted.
+	if mb.rowLevelSecurity {
+		mb.b.buildPolicyFilter(fetchTabMeta, mb.fetchScope, policySelect, policyDelete)
+	}
+	if mb.checkSelectColumns {
+		mb.b.requireColumnPrivileges(mb.fetchScope)
+	}
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		mb.joinInputWithTables(inScope, using)
+	} else {
+		mb.outScope = mb.fetchScope
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
 
+	// Build a distinct on to ensure there is at most one row in the joined output
+	// for every row in the table. It is built before ORDER BY and LIMIT so that
+	// the LIMIT counts rows of the table rather than joined rows.
+	if usingClausePresent {
+		mb.buildDistinctOnPrimaryKey()
+	}
+
 	// SELECT + ORDER BY (which may add projected expressions)
 	projectionsScope := mb.outScope.replace()
 	projectionsScope.appendColumnsFromScope(mb.outScope)
@@ -429,9 +516,6 @@ func (mb *mutationBuilder) buildInputForDelete(
 	}
 
 	mb.outScope = projectionsScope
-
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -996,6 +1080,9 @@ func (mb *mutationBuilder) buildReturning(returning tree.ReturningExprs) {
 	inScope := mb.outScope.replace()
 	inScope.expr = mb.outScope.expr
 	inScope.appendOrdinaryColumnsFromTable(mb.md.TableMeta(mb.tabID), &mb.alias)
+	if mb.checkSelectColumns {
+		mb.b.requireColumnPrivileges(inScope)
+	}
 
 	// extraAccessibleCols contains all the columns that the RETURNING
 	// clause can refer to in addition to the table columns. This is useful for
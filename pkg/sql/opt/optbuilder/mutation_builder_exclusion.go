@@ -0,0 +1,204 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// buildExclusionChecksForInsert builds check queries for an insert. These
+// check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForInsert() {
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		mb.uniqueChecks = append(mb.uniqueChecks, mb.buildExclusionCheck(i))
+	}
+}
+
+// buildExclusionChecksForUpdate builds check queries for an update. These
+// check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpdate() {
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		// If this constraint doesn't include the updated columns we don't need to
+		// plan a check.
+		if !mb.exclusionColsUpdated(i) {
+			continue
+		}
+		mb.uniqueChecks = append(mb.uniqueChecks, mb.buildExclusionCheck(i))
+	}
+}
+
+// buildExclusionChecksForUpsert builds check queries for an upsert. These
+// check queries are used to enforce EXCLUDE constraints. Unlike unique
+// constraints, EXCLUDE constraints cannot be used as arbiters, so a check is
+// always needed for the inserted rows.
+func (mb *mutationBuilder) buildExclusionChecksForUpsert() {
+	mb.buildExclusionChecksForInsert()
+}
+
+// exclusionColsUpdated returns true if any of the columns of an EXCLUDE
+// constraint are being updated (according to updateColIDs). When the
+// constraint has a partial predicate, it also returns true if the predicate
+// references any of the columns being updated.
+func (mb *mutationBuilder) exclusionColsUpdated(exclusionOrdinal int) bool {
+	ec := mb.tab.ExclusionConstraint(exclusionOrdinal)
+
+	for _, ord := range ec.ColumnOrdinals {
+		if mb.updateColIDs[ord] != 0 {
+			return true
+		}
+	}
+
+	if ec.Predicate != "" {
+		pred := mb.parseExclusionConstraintPredicateExpr(ec)
+		typedPred := mb.fetchScope.resolveAndRequireType(pred, types.Bool)
+
+		var predCols opt.ColSet
+		mb.b.buildScalar(typedPred, mb.fetchScope, nil, nil, &predCols)
+		for colID, ok := predCols.Next(0); ok; colID, ok = predCols.Next(colID + 1) {
+			ord := mb.md.ColumnMeta(colID).Table.ColumnOrdinal(colID)
+			if mb.updateColIDs[ord] != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseExclusionConstraintPredicateExpr parses the predicate of the given
+// partial EXCLUDE constraint.
+func (mb *mutationBuilder) parseExclusionConstraintPredicateExpr(
+	ec cat.ExclusionConstraint,
+) tree.Expr {
+	expr, err := parser.ParseExpr(ec.Predicate)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// buildExclusionCheck creates a check for rows which are added to or updated
+// in a table with an EXCLUDE constraint. The check is a self semi-join, with
+// the new values on the left and the rows of the table on the right, which
+// returns the new rows that conflict with any other row:
+//
+//   SELECT new.a, new.b FROM new WHERE EXISTS (
+//     SELECT * FROM tab
+//     WHERE new.a = tab.a AND new.b && tab.b AND new.pk != tab.pk
+//   )
+//
+// Since checks run after the mutation, the scan of the table includes the new
+// rows, so conflicts between two new rows are detected as well.
+func (mb *mutationBuilder) buildExclusionCheck(exclusionOrdinal int) memo.UniqueChecksItem {
+	mb.checkIsolationForConflictSearch("EXCLUDE")
+	f := mb.b.factory
+	ec := mb.tab.ExclusionConstraint(exclusionOrdinal)
+
+	tabMeta := mb.b.addTable(mb.tab, tree.NewUnqualifiedTableName(mb.tab.Name()))
+	scanOrdinals := tableOrdinals(tabMeta.Table, columnKinds{
+		includeMutations: false,
+		includeSystem:    false,
+		includeInverted:  false,
+	})
+	scanScope := mb.b.buildScan(
+		tabMeta,
+		scanOrdinals,
+		nil, /* indexFlags */
+		noRowLocking,
+		mb.b.allocScope(),
+	)
+	withScanScope, _ := mb.buildCheckInputScan(
+		checkInputScanNewVals, scanOrdinals, false, /* isFK */
+	)
+
+	// Build the join filters:
+	//   (new_a op_a existing_a) AND (new_b op_b existing_b) AND ...
+	semiJoinFilters := make(memo.FiltersExpr, 0, len(ec.ColumnOrdinals)+3)
+	for i, ord := range ec.ColumnOrdinals {
+		left := f.ConstructVariable(withScanScope.cols[ord].id)
+		right := f.ConstructVariable(scanScope.cols[ord].id)
+		var cmp opt.ScalarExpr
+		switch ec.Operators[i] {
+		case treecmp.EQ:
+			cmp = f.ConstructEq(left, right)
+		case treecmp.Overlaps:
+			switch mb.tab.Column(ord).DatumType().Family() {
+			case types.GeometryFamily, types.Box2DFamily:
+				// The && operator means "intersects" when used with geometry or
+				// bounding box operands.
+				cmp = f.ConstructBBoxIntersects(left, right)
+			default:
+				cmp = f.ConstructOverlaps(left, right)
+			}
+		default:
+			panic(errors.AssertionFailedf(
+				"unsupported exclusion constraint operator: %s", ec.Operators[i],
+			))
+		}
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(cmp))
+	}
+
+	// If the constraint is partial, filter out both new and existing rows that
+	// don't satisfy the predicate.
+	if ec.Predicate != "" {
+		pred := mb.parseExclusionConstraintPredicateExpr(ec)
+
+		typedPred := withScanScope.resolveAndRequireType(pred, types.Bool)
+		withScanPred := mb.b.buildScalar(typedPred, withScanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(withScanPred))
+
+		typedPred = scanScope.resolveAndRequireType(pred, types.Bool)
+		scanPred := mb.b.buildScalar(typedPred, scanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(scanPred))
+	}
+
+	// Prevent rows from matching themselves in the semi join:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	var pkFilter opt.ScalarExpr
+	for i, ok := primaryOrds.Next(0); ok; i, ok = primaryOrds.Next(i + 1) {
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(withScanScope.cols[i].id),
+			f.ConstructVariable(scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(pkFilter))
+
+	semiJoin := f.ConstructSemiJoin(withScanScope.expr, scanScope.expr, semiJoinFilters, memo.EmptyJoinPrivate)
+
+	// Collect the key columns that will be shown in the error message if there
+	// is a violation resulting from this check.
+	keyCols := make(opt.ColList, len(ec.ColumnOrdinals))
+	for i, ord := range ec.ColumnOrdinals {
+		keyCols[i] = withScanScope.cols[ord].id
+	}
+	project := f.ConstructProject(semiJoin, nil /* projections */, keyCols.ToSet())
+
+	return f.ConstructUniqueChecksItem(project, &memo.UniqueChecksItemPrivate{
+		Table:        mb.tabID,
+		CheckOrdinal: exclusionOrdinal,
+		KeyCols:      keyCols,
+		OpName:       mb.opName,
+		Exclusion:    true,
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -130,6 +131,7 @@ func (mb *mutationBuilder) buildFKChecksAndCascadesForDelete() {
 		//  - with Restrict/NoAction, we create a check that causes an error if
 		//    there are any "orphaned" rows in the child table.
 		if a := h.fk.DeleteReferenceAction(); a != tree.Restrict && a != tree.NoAction {
+			mb.checkIsolationForForeignKeys(true /* inbound */)
 			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
 			var builder memo.CascadeBuilder
 			switch a {
@@ -278,6 +280,7 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 		}
 
 		if a := h.fk.UpdateReferenceAction(); a != tree.Restrict && a != tree.NoAction {
+			mb.checkIsolationForForeignKeys(true /* inbound */)
 			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
 			mb.ensureWithID()
 			builder := newOnUpdateCascadeBuilder(mb.tab, i, h.otherTab, a)
@@ -396,6 +399,7 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 		}
 
 		if a := h.fk.UpdateReferenceAction(); a != tree.Restrict && a != tree.NoAction {
+			mb.checkIsolationForForeignKeys(true /* inbound */)
 			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
 			mb.ensureWithID()
 			builder := newOnUpdateCascadeBuilder(mb.tab, i, h.otherTab, a)
@@ -623,6 +627,32 @@ func (h *fkCheckHelper) buildOtherTableScan() (outScope *scope, tabMeta *opt.Tab
 	), otherTabMeta
 }
 
+// checkIsolationForForeignKeys panics with an error if the current transaction
+// runs under an isolation level which tolerates write skew, such as READ
+// COMMITTED, and the mutation needs a foreign key check or cascade. inbound is
+// true if the foreign key references the mutated table.
+//
+// The checks and cascades read the other table at the snapshot of the
+// statement, and their reads are not validated at commit time under these
+// isolation levels. To be correct, they would have to lock the rows they rely
+// on until the transaction commits: the referenced rows for an insertion, and
+// the referencing rows (including those which don't exist yet) for a deletion.
+// Reads can only acquire unreplicated locks, which are lost on lease
+// transfers, range splits and merges, and node restarts, so a concurrent
+// transaction could orphan rows of the referencing table.
+func (mb *mutationBuilder) checkIsolationForForeignKeys(inbound bool) {
+	if !mb.b.evalCtx.TxnIsoLevel.ToleratesWriteSkew() {
+		return
+	}
+	reason := "it has foreign key constraints"
+	if inbound {
+		reason = "it is referenced by foreign key constraints"
+	}
+	panic(unimplemented.Newf("read committed foreign keys",
+		"writing to table %q is not supported under READ COMMITTED isolation because %s",
+		mb.tab.Name(), reason))
+}
+
 func (h *fkCheckHelper) allocOrdinals(numCols int) {
 	buf := make([]int, numCols*2)
 	h.tabOrdinals = buf[:numCols]
@@ -633,6 +663,7 @@ func (h *fkCheckHelper) allocOrdinals(numCols int) {
 // The input to the insertion check will be produced from the input to the
 // mutation operator.
 func (h *fkCheckHelper) buildInsertionCheck() memo.FKChecksItem {
+	h.mb.checkIsolationForForeignKeys(false /* inbound */)
 	withScanScope, notNullWithScanCols := h.mb.buildCheckInputScan(
 		checkInputScanNewVals, h.tabOrdinals, true, /* isFK */
 	)
@@ -745,6 +776,8 @@ func (h *fkCheckHelper) buildInsertionCheck() memo.FKChecksItem {
 func (h *fkCheckHelper) buildDeletionCheck(
 	deletedRows memo.RelExpr, deleteCols opt.ColList,
 ) memo.FKChecksItem {
+	h.mb.checkIsolationForForeignKeys(true /* inbound */)
+
 	// Build a semi join, with the referenced FK columns on the left and the
 	// origin columns on the right.
 	scanScope, origTabMeta := h.buildOtherTableScan()
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 // UniquenessChecksForGenRandomUUIDClusterMode controls the cluster setting for
@@ -136,6 +137,23 @@ func (mb *mutationBuilder) buildUniqueChecksForUpsert() {
 	telemetry.Inc(sqltelemetry.UniqueChecksUseCounter)
 }
 
+// checkIsolationForConflictSearch panics with an error if the current
+// transaction runs under an isolation level which tolerates write skew, such as
+// READ COMMITTED, and the mutation must search the table for rows which
+// conflict with the new rows in order to enforce the given kind of constraint.
+// The search is not validated at commit time under these isolation levels, and
+// a conflicting row may not exist yet when the search is performed, so it
+// cannot be locked either. A concurrent transaction could insert it without
+// either transaction noticing the conflict. See also
+// checkIsolationForForeignKeys.
+func (mb *mutationBuilder) checkIsolationForConflictSearch(constraintKind string) {
+	if mb.b.evalCtx.TxnIsoLevel.ToleratesWriteSkew() {
+		panic(unimplemented.Newf("read committed conflict checks",
+			"writing to table %q is not supported under READ COMMITTED isolation "+
+				"because it has %s constraints", mb.tab.Name(), constraintKind))
+	}
+}
+
 // hasUniqueWithoutIndexConstraints returns true if there are any
 // UNIQUE WITHOUT INDEX constraints on the table.
 func (mb *mutationBuilder) hasUniqueWithoutIndexConstraints() bool {
@@ -303,6 +321,7 @@ func (h *uniqueCheckHelper) init(mb *mutationBuilder, uniqueOrdinal int) bool {
 // table. The input to the insertion check will be produced from the input to
 // the mutation operator.
 func (h *uniqueCheckHelper) buildInsertionCheck() memo.UniqueChecksItem {
+	h.mb.checkIsolationForConflictSearch("UNIQUE WITHOUT INDEX")
 	f := h.mb.b.factory
 
 	// Build a self semi-join, with the new values on the left and the
@@ -0,0 +1,290 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// policyCommand identifies the type of access to the rows of a table which is
+// restricted by row-level security policies.
+type policyCommand uint8
+
+const (
+	policySelect policyCommand = iota
+	policyInsert
+	policyUpdate
+	policyDelete
+)
+
+// appliesTo returns true if the given policy restricts the command.
+func (c policyCommand) appliesTo(p *cat.Policy) bool {
+	switch c {
+	case policySelect:
+		return p.OnSelect
+	case policyInsert:
+		return p.OnInsert
+	case policyUpdate:
+		return p.OnUpdate
+	default:
+		return p.OnDelete
+	}
+}
+
+// rowLevelSecurityPolicies returns the row-level security policies of the
+// given table which apply to the current user. It also returns whether
+// row-level security is enforced on the table for the current user at all; if
+// it is, the rows which are not allowed by any of the returned policies cannot
+// be accessed. Admins are exempt from row-level security, and so is the owner
+// of the table, unless row-level security is forced on the table. Row-level
+// security is not enforced on the statements which maintain materialized
+// views.
+func (b *Builder) rowLevelSecurityPolicies(tab cat.Table) (_ []cat.Policy, enforced bool) {
+	if !tab.IsRowLevelSecurityEnabled() || b.maintainingView {
+		return nil, false
+	}
+
+	// The policies which apply depend on the current user, so the memo cannot
+	// be reused by other users.
+	b.DisableMemoReuse = true
+
+	isAdmin, err := b.catalog.HasAdminRole(b.ctx)
+	if err != nil {
+		panic(err)
+	}
+	if isAdmin {
+		return nil, false
+	}
+	if !tab.IsRowLevelSecurityForced() {
+		isOwner, err := b.catalog.HasOwnership(b.ctx, tab)
+		if err != nil {
+			panic(err)
+		}
+		if isOwner {
+			return nil, false
+		}
+	}
+
+	var policies []cat.Policy
+	for i, n := 0, tab.PolicyCount(); i < n; i++ {
+		p := tab.Policy(i)
+		for _, role := range p.Roles {
+			isMember, err := b.catalog.IsMemberOfRole(
+				b.ctx, username.MakeSQLUsernameFromPreNormalizedString(role),
+			)
+			if err != nil {
+				panic(err)
+			}
+			if isMember {
+				policies = append(policies, p)
+				break
+			}
+		}
+	}
+	return policies, true
+}
+
+// buildPolicyFilter wraps the expression of the given scan scope of a table in
+// a Select which filters out the rows that cannot be accessed by any of the
+// given commands according to the row-level security policies of the table.
+// It does nothing if row-level security is not enforced on the table for the
+// current user.
+//
+// The Select is wrapped in a Barrier, so that the other filters of the query
+// are only evaluated on the rows which satisfy the policies, unless they are
+// leakproof. Otherwise a filter calling a function which reveals its arguments
+// (for example by raising an error which contains them) could be evaluated
+// on the hidden rows.
+func (b *Builder) buildPolicyFilter(
+	tabMeta *opt.TableMeta, scanScope *scope, cmds ...policyCommand,
+) {
+	policies, enforced := b.rowLevelSecurityPolicies(tabMeta.Table)
+	if !enforced {
+		return
+	}
+	filters := make(memo.FiltersExpr, len(cmds))
+	tabMeta.Policies = []string{}
+	for i, cmd := range cmds {
+		expr := policyExpr(policies, cmd, false /* withCheck */)
+		texpr := scanScope.resolveAndRequireType(expr, types.Bool)
+		filters[i] = b.factory.ConstructFiltersItem(
+			b.buildScalar(texpr, scanScope, nil, nil, nil),
+		)
+		for j := range policies {
+			if cmd.appliesTo(&policies[j]) {
+				tabMeta.Policies = appendPolicyName(tabMeta.Policies, policies[j].Name)
+			}
+		}
+	}
+	scanScope.expr = b.factory.ConstructBarrier(b.factory.ConstructSelect(scanScope.expr, filters))
+}
+
+// appendPolicyName appends the given name to the list of policy names unless
+// it is already part of it.
+func appendPolicyName(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+// policyExpr returns the expression which rows must satisfy in order to be
+// accessed by the given command according to the given policies: the rows must
+// satisfy at least one of the permissive policies, and all of the restrictive
+// policies. If there are no permissive policies, no rows can be accessed.
+//
+// If withCheck is true, the expression applies to new rows, so the WITH CHECK
+// expressions of the policies are used instead of the USING expressions when
+// they are present.
+func policyExpr(policies []cat.Policy, cmd policyCommand, withCheck bool) tree.Expr {
+	var permissive, restrictive tree.Expr
+	for i := range policies {
+		p := &policies[i]
+		if !cmd.appliesTo(p) {
+			continue
+		}
+		exprStr := p.UsingExpr
+		if withCheck && p.WithCheckExpr != "" {
+			exprStr = p.WithCheckExpr
+		}
+		if exprStr == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			panic(err)
+		}
+		switch {
+		case p.Restrictive && restrictive == nil:
+			restrictive = expr
+		case p.Restrictive:
+			restrictive = &tree.AndExpr{Left: restrictive, Right: expr}
+		case permissive == nil:
+			permissive = expr
+		default:
+			permissive = &tree.OrExpr{Left: permissive, Right: expr}
+		}
+	}
+	if permissive == nil {
+		return tree.DBoolFalse
+	}
+	if restrictive == nil {
+		return permissive
+	}
+	return &tree.AndExpr{Left: permissive, Right: restrictive}
+}
+
+// initRowLevelSecurity determines whether the row-level security policies of
+// the target table are enforced on the mutation. It is not called for the
+// mutations of foreign key cascades, which are not subject to row-level
+// security.
+func (mb *mutationBuilder) initRowLevelSecurity() {
+	mb.policies, mb.rowLevelSecurity = mb.b.rowLevelSecurityPolicies(mb.tab)
+}
+
+// projectPolicyCheckCol synthesizes a boolean output column which is true for
+// the rows written by the mutation that satisfy the row-level security
+// policies of the target table. Inserted rows must satisfy the INSERT
+// policies, and updated rows must satisfy the UPDATE policies. In the case of
+// an upsert, the existing rows which are updated must also be visible
+// according to the SELECT and UPDATE policies. The column is checked by the
+// query built by buildPolicyCheck.
+func (mb *mutationBuilder) projectPolicyCheckCol(cmd policyCommand) {
+	if !mb.rowLevelSecurity {
+		return
+	}
+	f := mb.b.factory
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+
+	var check opt.ScalarExpr
+	if cmd == policyInsert && mb.canaryColID != 0 {
+		// The canary column is null if the row is inserted by the upsert.
+		check = f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{
+				f.ConstructWhen(
+					f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton),
+					mb.buildPolicyScalar(mb.outScope, policyInsert, true /* withCheck */),
+				),
+			},
+			f.ConstructAnd(
+				f.ConstructAnd(
+					mb.buildPolicyScalar(mb.fetchScope, policySelect, false /* withCheck */),
+					mb.buildPolicyScalar(mb.fetchScope, policyUpdate, false /* withCheck */),
+				),
+				mb.buildPolicyScalar(mb.outScope, policyUpdate, true /* withCheck */),
+			),
+		)
+	} else {
+		check = mb.buildPolicyScalar(mb.outScope, cmd, true /* withCheck */)
+	}
+
+	// Use an anonymous name because the column cannot be referenced in other
+	// expressions.
+	colName := scopeColName("").WithMetadataName("policy_check")
+	scopeCol := mb.b.synthesizeColumn(projectionsScope, colName, types.Bool, nil /* expr */, check)
+	mb.policyCheckColID = scopeCol.id
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildPolicyScalar builds the expression which rows must satisfy according
+// to the policies of the target table for the given command, with variables
+// resolved in the given scope.
+func (mb *mutationBuilder) buildPolicyScalar(
+	inScope *scope, cmd policyCommand, withCheck bool,
+) opt.ScalarExpr {
+	expr := policyExpr(mb.policies, cmd, withCheck)
+	texpr := inScope.resolveAndRequireType(expr, types.Bool)
+	return mb.b.buildScalar(texpr, inScope, nil, nil, nil)
+}
+
+// buildPolicyCheck builds a check query which returns the rows written by the
+// mutation that do not satisfy the row-level security policies of the target
+// table, according to the column synthesized by projectPolicyCheckCol:
+//
+//   SELECT policy_check FROM new WHERE policy_check IS NOT true
+//
+// The mutation fails if the query returns any rows.
+func (mb *mutationBuilder) buildPolicyCheck() {
+	if mb.policyCheckColID == 0 {
+		return
+	}
+	f := mb.b.factory
+	mb.ensureWithID()
+	outCol := mb.md.AddColumn("policy_check", types.Bool)
+	withScan := f.ConstructWithScan(&memo.WithScanPrivate{
+		With:    mb.withID,
+		InCols:  opt.ColList{mb.policyCheckColID},
+		OutCols: opt.ColList{outCol},
+		ID:      mb.md.NextUniqueID(),
+	})
+	violations := f.ConstructSelect(withScan, memo.FiltersExpr{f.ConstructFiltersItem(
+		f.ConstructIsNot(f.ConstructVariable(outCol), memo.TrueSingleton),
+	)})
+	mb.uniqueChecks = append(mb.uniqueChecks, f.ConstructUniqueChecksItem(
+		violations, &memo.UniqueChecksItemPrivate{
+			Table:            mb.tabID,
+			OpName:           mb.opName,
+			RowLevelSecurity: true,
+		},
+	))
+}
@@ -324,6 +324,9 @@ func (b *Builder) buildScalar(
 	case *tree.FuncExpr:
 		return b.buildFunction(t, inScope, outScope, outCol, colRefs)
 
+	case *tree.GroupingFuncExpr:
+		out = b.buildGroupingFunc(t, inScope)
+
 	case *tree.IfExpr:
 		valType := t.ResolvedType()
 		input := b.buildScalar(t.Cond.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -537,6 +540,16 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if f.ResolvedOverload().UserDefinedFunction != nil {
+		if b.insideViewDef {
+			panic(unimplementedWithIssueDetailf(83228, "view",
+				"user-defined function %s() cannot be used inside a view definition", def.Name))
+		}
+		// The definition and privileges of the function are stored in its
+		// descriptor, which is not tracked by the memo metadata.
+		b.DisableMemoReuse = true
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -744,6 +757,8 @@ func (b *Builder) constructComparison(
 		return b.factory.ConstructJsonExists(left, right)
 	case treecmp.JSONAllExists:
 		return b.factory.ConstructJsonAllExists(left, right)
+	case treecmp.JSONPathExists:
+		return b.factory.ConstructJsonPathExists(left, right)
 	case treecmp.JSONSomeExists:
 		return b.factory.ConstructJsonSomeExists(left, right)
 	case treecmp.Overlaps:
@@ -755,6 +770,10 @@ func (b *Builder) constructComparison(
 			return b.factory.ConstructBBoxIntersects(left, right)
 		}
 		return b.factory.ConstructOverlaps(left, right)
+	case treecmp.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
+	case treecmp.Adjacent:
+		return b.factory.ConstructAdjacent(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", redact.Safe(cmp.Operator)))
 }
@@ -797,6 +816,12 @@ func (b *Builder) constructBinary(
 		return b.factory.ConstructFetchValPath(left, right)
 	case treebin.JSONFetchTextPath:
 		return b.factory.ConstructFetchTextPath(left, right)
+	case treebin.Distance:
+		return b.factory.ConstructVectorDistance(left, right)
+	case treebin.NegInnerProduct:
+		return b.factory.ConstructVectorNegInnerProduct(left, right)
+	case treebin.CosDistance:
+		return b.factory.ConstructVectorCosDistance(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled binary operator: %s", redact.Safe(bin)))
 }
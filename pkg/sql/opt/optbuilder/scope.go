@@ -124,6 +124,7 @@ const (
 	exprKindHaving
 	exprKindLateralJoin
 	exprKindLimit
+	exprKindMergeWhen
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
@@ -145,6 +146,7 @@ var exprKindName = [...]string{
 	exprKindHaving:            "HAVING",
 	exprKindLateralJoin:       "LATERAL JOIN",
 	exprKindLimit:             "LIMIT",
+	exprKindMergeWhen:         "MERGE WHEN",
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
@@ -624,7 +626,7 @@ func findExistingColInList(
 func (s *scope) findExistingCol(expr tree.TypedExpr, allowSideEffects bool) *scopeColumn {
 	col := findExistingColInList(expr, s.cols, allowSideEffects, s.builder.evalCtx)
 	if col != nil {
-		s.builder.trackReferencedColumnForViews(col)
+		s.builder.trackReferencedColumn(col)
 	}
 	return col
 }
@@ -1030,6 +1032,10 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		}
 
 		if t.WindowDef != nil {
+			if isUserDefinedAggregate(def) {
+				panic(unimplementedWithIssueDetailf(74775, "window",
+					"user-defined aggregate %s() cannot be used as a window function", def.Name))
+			}
 			expr = s.replaceWindowFn(t, def)
 			break
 		}
@@ -1224,8 +1230,23 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.FunctionDefinition)
 
 	f = typedFunc.(*tree.FuncExpr)
 
+	if f.ResolvedOverload().UserDefinedAggregate != nil {
+		if f.OrderBy != nil {
+			panic(unimplementedWithIssueDetailf(74775, "orderby",
+				"ORDER BY is not supported for user-defined aggregate %s()", def.Name))
+		}
+		if s.builder.insideViewDef {
+			panic(unimplementedWithIssueDetailf(74775, "view",
+				"user-defined aggregate %s() cannot be used inside a view definition", def.Name))
+		}
+		// The definition and privileges of the aggregate are stored in its
+		// descriptor, which is not tracked by the memo metadata.
+		s.builder.DisableMemoReuse = true
+	}
+
 	private := memo.FunctionPrivate{
 		Name:       def.Name,
+		Typ:        f.ResolvedType(),
 		Properties: &def.FunctionProperties,
 		Overload:   f.ResolvedOverload(),
 	}
@@ -102,7 +102,8 @@ func (b *Builder) buildDataSource(
 			return outScope
 		}
 
-		ds, depName, resName := b.resolveDataSource(tn, privilege.SELECT)
+		ds, depName, resName := b.resolveDataSource(tn, 0 /* priv */)
+		checkColumns := b.checkTableOrColumnPrivilege(depName, ds, privilege.SELECT)
 
 		locking = locking.filter(tn.ObjectName)
 		if locking.isSet() {
@@ -113,7 +114,7 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
-			return b.buildScan(
+			outScope = b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations: false,
@@ -122,6 +123,11 @@ func (b *Builder) buildDataSource(
 				}),
 				indexFlags, locking, inScope,
 			)
+			b.buildPolicyFilter(tabMeta, outScope, policySelect)
+			if checkColumns {
+				b.requireColumnPrivileges(outScope)
+			}
+			return outScope
 
 		case cat.Sequence:
 			return b.buildSequenceSelect(t, &resName, inScope)
@@ -207,7 +213,8 @@ func (b *Builder) buildDataSource(
 		return outScope
 
 	case *tree.TableRef:
-		ds, depName := b.resolveDataSourceRef(source, privilege.SELECT)
+		ds, depName := b.resolveDataSourceRef(source, 0 /* priv */)
+		checkColumns := b.checkTableOrColumnPrivilege(depName, ds, privilege.SELECT)
 
 		locking = locking.filter(source.As.Alias)
 		if locking.isSet() {
@@ -218,6 +225,9 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			outScope = b.buildScanFromTableRef(t, source, indexFlags, locking, inScope)
+			if checkColumns {
+				b.requireColumnPrivileges(outScope)
+			}
 		case cat.View:
 			if source.Columns != nil {
 				panic(pgerror.Newf(pgcode.FeatureNotSupported,
@@ -396,6 +406,12 @@ func (b *Builder) buildScanFromTableRef(
 			panic(pgerror.Newf(pgcode.Syntax,
 				"an explicit list of column IDs must include at least one column"))
 		}
+		// The policies of the table may reference columns which are not part of
+		// the list.
+		if _, enforced := b.rowLevelSecurityPolicies(tab); enforced {
+			panic(pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot specify an explicit column list when accessing a table with row-level security by reference"))
+		}
 		ordinals = resolveNumericColumnRefs(tab, ref.Columns)
 	} else {
 		ordinals = tableOrdinals(tab, columnKinds{
@@ -407,7 +423,9 @@ func (b *Builder) buildScanFromTableRef(
 
 	tn := tree.MakeUnqualifiedTableName(tab.Name())
 	tabMeta := b.addTable(tab, &tn)
-	return b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	outScope = b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	b.buildPolicyFilter(tabMeta, outScope, policySelect)
+	return outScope
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
@@ -491,7 +509,9 @@ func (b *Builder) buildScan(
 		}
 	}
 
-	if tab.IsRefreshViewRequired() {
+	// The statements which maintain an incrementally refreshed materialized
+	// view can read it while it is being populated.
+	if tab.IsRefreshViewRequired() && !b.maintainingView {
 		err := errors.WithHint(
 			pgerror.Newf(pgcode.ObjectNotInPrerequisiteState, "materialized view %q has not been populated", tab.Name()),
 			"use the REFRESH MATERIALIZED VIEW command.")
@@ -512,6 +532,16 @@ func (b *Builder) buildScan(
 		// Note: virtual tables should not be collected as view dependencies.
 		return outScope
 	}
+	if tab.IsForeignTable() {
+		if indexFlags != nil {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"index flags not allowed with foreign tables"))
+		}
+		if locking.isSet() {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"%s not allowed with foreign tables", locking.get().Strength))
+		}
+	}
 
 	private := memo.ScanPrivate{Table: tabID, Cols: scanColIDs}
 	if indexFlags != nil {
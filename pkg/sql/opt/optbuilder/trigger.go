@@ -0,0 +1,554 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// buildRowTriggers adds a memo.FKCascade for each statement of each row-level
+// trigger on the mutated table that fires for the given event. Triggers are
+// executed by the same machinery as foreign key cascades, except that the
+// trigger statements are built and executed separately for each row of the
+// buffered mutation input; see triggerBuilder. A trigger which executes a
+// trigger function is added as a single memo.FKCascade without a builder.
+//
+// Assumes that outScope.expr is the input to the mutation.
+func (mb *mutationBuilder) buildRowTriggers(event tree.TriggerEvent) {
+	if mb.tab.TriggerCount() == 0 {
+		// No relevant triggers.
+		return
+	}
+
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.MaintainsView && mb.b.evalCtx.TxnIsoLevel.ToleratesWriteSkew() {
+			// The maintenance of a view reads the view and the other tables it
+			// depends on, so concurrent transactions which do not see each
+			// other's writes could, for example, both add the same new group to
+			// the view.
+			panic(unimplemented.NewWithIssuef(28296,
+				"mutations of table %q with incrementally maintained materialized views "+
+					"are not supported under READ COMMITTED isolation", mb.tab.Name()))
+		}
+		if !triggerFiresOn(&trigger, event) {
+			continue
+		}
+		if trigger.Before && !trigger.MaintainsView {
+			// These triggers were added by buildInputForBeforeTriggers.
+			continue
+		}
+
+		mb.ensureWithID()
+		var oldValues, newValues opt.ColList
+		if event != tree.TriggerEventInsert {
+			oldValues = mb.triggerRowCols(mb.fetchColIDs, nil /* overrides */)
+		}
+		switch event {
+		case tree.TriggerEventInsert:
+			newValues = mb.triggerRowCols(mb.insertColIDs, nil /* overrides */)
+		case tree.TriggerEventUpdate:
+			newValues = mb.triggerRowCols(mb.fetchColIDs, mb.updateColIDs)
+		}
+
+		if trigger.Function != nil {
+			// The trigger function is executed without being planned, so a single
+			// cascade is enough.
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				FKName:          trigger.Name,
+				WithID:          mb.withID,
+				OldValues:       oldValues,
+				NewValues:       newValues,
+				ForEachRow:      true,
+				TriggerFunction: trigger.Function,
+				TriggerTable:    mb.tab,
+			})
+			continue
+		}
+
+		// Each statement of the trigger body is planned as a separate cascade.
+		// The execution engine processes the statements of a trigger one row at
+		// a time, so that all of the statements are executed for a row before
+		// moving on to the next row.
+		for j := range trigger.Stmts {
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				FKName:     trigger.Name,
+				Builder:    newTriggerBuilder(mb.tab, i, j),
+				WithID:     mb.withID,
+				OldValues:  oldValues,
+				NewValues:  newValues,
+				ForEachRow: true,
+				Before:     trigger.Before,
+			})
+		}
+	}
+}
+
+// buildInputForBeforeTriggers buffers the mutation input in a With binding if
+// the mutated table has BEFORE row-level triggers which fire for the given
+// event, and adds a memo.FKCascade for each statement of these triggers. The
+// triggers are executed for each buffered row before the rest of the mutation
+// input is evaluated, and can modify the new values of the row or skip the row
+// (see triggerBuilder). The rest of the mutation input reads the rows which
+// are left in the binding.
+//
+// For this reason, this method must be called once the values of the row which
+// can be modified by the triggers have been built (e.g. default values), and
+// before any values which depend on them (e.g. computed columns or check
+// constraints). Triggers which maintain a view are not affected; they are
+// added by buildRowTriggers.
+//
+// The triggers are executed for all the rows before the mutation modifies any
+// of them, so unlike in Postgres they do not observe the rows written by the
+// mutation for the earlier rows of the same statement.
+func (mb *mutationBuilder) buildInputForBeforeTriggers(event tree.TriggerEvent) {
+	var triggers []int
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.Before && !trigger.MaintainsView && triggerFiresOn(&trigger, event) {
+			triggers = append(triggers, i)
+		}
+	}
+	if len(triggers) == 0 {
+		return
+	}
+
+	var oldValues, newValues, returnCols opt.ColList
+	if event != tree.TriggerEventInsert {
+		oldValues = mb.triggerRowCols(mb.fetchColIDs, nil /* overrides */)
+	}
+	if event != tree.TriggerEventDelete {
+		newValues = mb.projectBeforeTriggerNewValues(event == tree.TriggerEventInsert)
+		i := 0
+		for ord, n := 0, mb.tab.ColumnCount(); ord < n; ord++ {
+			if tabCol := mb.tab.Column(ord); tabCol.Kind() == cat.Ordinary {
+				if tabCol.Visibility() == cat.Visible {
+					returnCols = append(returnCols, newValues[i])
+				}
+				i++
+			}
+		}
+	}
+
+	f := mb.b.factory
+	mb.beforeTriggerWithID = f.Memo().NextWithID()
+	mb.beforeTriggerInput = mb.outScope.expr
+	mb.md.AddWithBinding(mb.beforeTriggerWithID, mb.beforeTriggerInput)
+
+	for _, i := range triggers {
+		trigger := mb.tab.Trigger(i)
+		returnsRow := triggerReturnsRow(&trigger)
+		for j := range trigger.Stmts {
+			cascade := memo.FKCascade{
+				FKName:       trigger.Name,
+				Builder:      newTriggerBuilder(mb.tab, i, j),
+				WithID:       mb.beforeTriggerWithID,
+				OldValues:    oldValues,
+				NewValues:    newValues,
+				ForEachRow:   true,
+				Before:       true,
+				ModifiesRows: true,
+			}
+			if returnsRow && j == len(trigger.Stmts)-1 {
+				cascade.ReturnsRow = true
+				cascade.ReturnCols = returnCols
+			}
+			mb.cascades = append(mb.cascades, cascade)
+		}
+	}
+
+	// Replace the input with a scan of the binding. The columns of the scan have
+	// new IDs, so all the references to the input columns must be remapped.
+	inCols := mb.beforeTriggerInput.Relational().OutputCols.ToList()
+	outCols := make(opt.ColList, len(inCols))
+	var colMap opt.ColMap
+	for i, col := range inCols {
+		colMeta := mb.md.ColumnMeta(col)
+		outCols[i] = mb.md.AddColumn(colMeta.Alias, colMeta.Type)
+		colMap.Set(int(col), int(outCols[i]))
+	}
+	mb.outScope.expr = f.ConstructWithScan(&memo.WithScanPrivate{
+		With:    mb.beforeTriggerWithID,
+		Name:    beforeTriggersWithName,
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      mb.md.NextUniqueID(),
+	})
+	mb.remapInputCols(colMap)
+}
+
+// beforeTriggersWithName is the name of the With binding which buffers the
+// input of BEFORE triggers, which is shown in EXPLAIN output.
+const beforeTriggersWithName = "before-triggers"
+
+// projectBeforeTriggerNewValues projects a copy of the new value of each
+// ordinary column of the mutated table, so that each of them can be modified
+// by BEFORE triggers independently of the other columns of the input. The
+// copies become the insert columns (or the update columns) of the mutation,
+// except for computed columns, whose values are computed afterwards; the new
+// value of a computed column is NULL for inserts, and its old value for
+// updates. Returns the copies in table order.
+//
+// For updates, every column which is not computed is updated, since its new
+// value can be changed by the triggers.
+func (mb *mutationBuilder) projectBeforeTriggerNewValues(isInsert bool) opt.ColList {
+	f := mb.b.factory
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	newValues := make(opt.ColList, 0, mb.tab.ColumnCount())
+	for ord, n := 0, mb.tab.ColumnCount(); ord < n; ord++ {
+		tabCol := mb.tab.Column(ord)
+		if tabCol.Kind() != cat.Ordinary {
+			continue
+		}
+		var src opt.ColumnID
+		switch {
+		case isInsert:
+			src = mb.insertColIDs[ord]
+		case mb.updateColIDs[ord] != 0:
+			src = mb.updateColIDs[ord]
+		default:
+			src = mb.fetchColIDs[ord]
+		}
+		var scalar opt.ScalarExpr
+		if src != 0 {
+			scalar = f.ConstructVariable(src)
+		} else if isInsert && tabCol.IsComputed() {
+			scalar = f.ConstructNull(tabCol.DatumType())
+		} else {
+			panic(errors.AssertionFailedf("missing value for column %d of trigger row", ord))
+		}
+		colName := scopeColName(tabCol.ColName()).WithMetadataName(string(tabCol.ColName()) + "_new")
+		col := mb.b.synthesizeColumn(projectionsScope, colName, tabCol.DatumType(), nil /* expr */, scalar)
+		newValues = append(newValues, col.id)
+		if !tabCol.IsComputed() {
+			if isInsert {
+				mb.insertColIDs[ord] = col.id
+			} else {
+				mb.updateColIDs[ord] = col.id
+			}
+		}
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// The copies are now the columns with the final values that the mutation
+	// applies; make sure that any references by name resolve to them.
+	mb.disambiguateColumns()
+	return newValues
+}
+
+// remapInputCols replaces the columns of the mutation input which are present
+// in the given map, wherever they are referenced by the mutationBuilder.
+func (mb *mutationBuilder) remapInputCols(colMap opt.ColMap) {
+	remap := func(col opt.ColumnID) opt.ColumnID {
+		if newCol, ok := colMap.Get(int(col)); ok {
+			return opt.ColumnID(newCol)
+		}
+		return col
+	}
+	for _, cols := range []opt.OptionalColList{
+		mb.insertColIDs,
+		mb.fetchColIDs,
+		mb.updateColIDs,
+		mb.upsertColIDs,
+		mb.checkColIDs,
+		mb.partialIndexPutColIDs,
+		mb.partialIndexDelColIDs,
+	} {
+		for i := range cols {
+			cols[i] = remap(cols[i])
+		}
+	}
+	mb.policyCheckColID = remap(mb.policyCheckColID)
+	mb.canaryColID = remap(mb.canaryColID)
+
+	remapScopeCols := func(cols []scopeColumn) {
+		for i := range cols {
+			cols[i].id = remap(cols[i].id)
+			cols[i].scalar = nil
+		}
+	}
+	remapScopeCols(mb.outScope.cols)
+	if mb.fetchScope != nil && mb.fetchScope != mb.outScope {
+		remapScopeCols(mb.fetchScope.cols)
+	}
+	remapScopeCols(mb.extraAccessibleCols)
+
+	// The ordering of the input is not preserved by the binding, and the input
+	// can no longer be used to build uniqueness checks.
+	mb.outScope.ordering = nil
+	mb.insertExpr = nil
+}
+
+// buildBeforeTriggersWith wraps the mutation in a With expression which binds
+// the input of the BEFORE triggers added by buildInputForBeforeTriggers, if
+// there are any.
+func (mb *mutationBuilder) buildBeforeTriggersWith() {
+	if mb.beforeTriggerWithID == 0 {
+		return
+	}
+	mb.outScope.expr = mb.b.factory.ConstructWith(
+		mb.beforeTriggerInput,
+		mb.outScope.expr,
+		&memo.WithPrivate{
+			ID:   mb.beforeTriggerWithID,
+			Name: beforeTriggersWithName,
+			Mtr:  tree.MaterializeClause{Set: true, Materialize: true},
+		},
+	)
+}
+
+// hasUserBeforeTriggers returns true if the mutated table has BEFORE row-level
+// triggers which were created by the user, as opposed to triggers which
+// maintain a view.
+func (mb *mutationBuilder) hasUserBeforeTriggers() bool {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		if trigger := mb.tab.Trigger(i); trigger.Before && !trigger.MaintainsView {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerFiresOn returns true if the trigger fires for the given event.
+func triggerFiresOn(trigger *cat.Trigger, event tree.TriggerEvent) bool {
+	switch event {
+	case tree.TriggerEventInsert:
+		return trigger.OnInsert
+	case tree.TriggerEventUpdate:
+		return trigger.OnUpdate
+	case tree.TriggerEventDelete:
+		return trigger.OnDelete
+	}
+	return false
+}
+
+// triggerReturnsRow returns true if the last statement in the body of the
+// given BEFORE trigger is a query which returns the row to be written.
+func triggerReturnsRow(trigger *cat.Trigger) bool {
+	stmt, err := parser.ParseOne(trigger.Stmts[len(trigger.Stmts)-1])
+	if err != nil {
+		panic(err)
+	}
+	_, ok := stmt.AST.(*tree.Select)
+	return ok
+}
+
+// checkNoRowTriggers raises an error if the mutated table has any row-level
+// triggers. It is used by mutations which do not yet support triggers.
+func (mb *mutationBuilder) checkNoRowTriggers() {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		if mb.tab.Trigger(i).MaintainsView {
+			panic(unimplemented.NewWithIssuef(28296,
+				"upserts into table %q with incrementally maintained materialized views are not supported",
+				mb.tab.Name()))
+		}
+	}
+	if mb.tab.TriggerCount() > 0 {
+		panic(unimplemented.NewWithIssuef(28296,
+			"upserts into table %q with triggers are not supported", mb.tab.Name()))
+	}
+}
+
+// triggerRowCols returns the input column IDs for each ordinary column of the
+// mutated table, in table order. If overrides is not empty, its nonzero
+// entries take precedence over cols.
+func (mb *mutationBuilder) triggerRowCols(cols, overrides opt.OptionalColList) opt.ColList {
+	res := make(opt.ColList, 0, mb.tab.ColumnCount())
+	for ord, n := 0, mb.tab.ColumnCount(); ord < n; ord++ {
+		if mb.tab.Column(ord).Kind() != cat.Ordinary {
+			continue
+		}
+		col := cols[ord]
+		if len(overrides) > 0 && overrides[ord] != 0 {
+			col = overrides[ord]
+		}
+		if col == 0 {
+			panic(errors.AssertionFailedf("missing value for column %d of trigger row", ord))
+		}
+		res = append(res, col)
+	}
+	return res
+}
+
+// triggerBuilder is a memo.CascadeBuilder implementation for a statement in the
+// body of a row-level trigger.
+//
+// The statement can refer to the old and new values of the modified row using
+// the OLD and NEW table names. For example, the body of this trigger:
+//
+//   CREATE TRIGGER audit AFTER UPDATE ON parent FOR EACH ROW
+//   BEGIN ATOMIC INSERT INTO audit VALUES (OLD.p, NEW.p); END
+//
+// is built as an insert whose input is a VALUES expression with outer columns
+// for the old and new values of p:
+//
+//   insert audit
+//    ├── columns: <none>
+//    ├── insert-mapping:
+//    │    ├── column1:3 => audit.old_p:5
+//    │    └── column2:4 => audit.new_p:6
+//    └── values
+//         ├── columns: column1:3 column2:4
+//         ├── outer: (1,2)
+//         └── (p:1, p:2)
+//
+// The execution engine replaces the outer columns with the values of each
+// modified row before optimizing the statement.
+type triggerBuilder struct {
+	mutatedTable cat.Table
+	// triggerOrdinal is the ordinal of the trigger on the mutated table (can be
+	// passed to mutatedTable.Trigger).
+	triggerOrdinal int
+	// stmtIdx is the index of the statement in the body of the trigger.
+	stmtIdx int
+}
+
+var _ memo.CascadeBuilder = &triggerBuilder{}
+
+func newTriggerBuilder(mutatedTable cat.Table, triggerOrdinal, stmtIdx int) *triggerBuilder {
+	return &triggerBuilder{
+		mutatedTable:   mutatedTable,
+		triggerOrdinal: triggerOrdinal,
+		stmtIdx:        stmtIdx,
+	}
+}
+
+// Build is part of the memo.CascadeBuilder interface. The binding is unused;
+// oldValues and newValues correspond 1-to-1 to the ordinary columns of the
+// mutated table. For inserts, oldValues is empty; for deletes, newValues is
+// empty.
+func (tb *triggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		trigger := tb.mutatedTable.Trigger(tb.triggerOrdinal)
+		stmt, err := parser.ParseOne(trigger.Stmts[tb.stmtIdx])
+		if err != nil {
+			panic(err)
+		}
+		b.maintainingView = trigger.MaintainsView
+
+		// The statement is built at the root, in a scope which contains the
+		// values of the modified row.
+		inScope := b.allocScope()
+		inScope.atRoot = true
+		tb.addRowCols(b, inScope, "old", oldValues)
+		tb.addRowCols(b, inScope, "new", newValues)
+
+		outScope := b.buildStmt(stmt.AST, nil /* desiredTypes */, inScope)
+		if _, ok := stmt.AST.(*tree.Select); ok && trigger.Before {
+			// This is the query which returns the row to be written at the end of a
+			// BEFORE trigger (see checkTriggerBodyStmt).
+			outScope.expr = tb.buildReturnedRow(b, outScope, newValues)
+		}
+		outScope.expr = b.buildWiths(outScope.expr, b.ctes)
+
+		// Trigger queries are executed like cascades, which cannot have
+		// subqueries.
+		if outScope.expr.Relational().HasSubquery {
+			panic(unimplemented.NewWithIssuef(28296,
+				"subqueries are not supported in the body of trigger %q", trigger.Name))
+		}
+		return outScope.expr
+	})
+}
+
+// buildReturnedRow projects the row returned by the query at the end of a
+// BEFORE trigger onto new columns, which have the types of the visible columns
+// of the mutated table, in table order. The new columns are the only output
+// columns of the returned expression, and their IDs are increasing. If
+// newValues is empty (the mutation is a deletion), the contents of the row are
+// not used and no columns are projected.
+func (tb *triggerBuilder) buildReturnedRow(
+	b *Builder, outScope *scope, newValues opt.ColList,
+) memo.RelExpr {
+	f := b.factory
+	if len(newValues) == 0 {
+		return f.ConstructProject(outScope.expr, nil /* projections */, opt.ColSet{})
+	}
+	outScope.removeHiddenCols()
+	var targetCols []*cat.Column
+	for ord, n := 0, tb.mutatedTable.ColumnCount(); ord < n; ord++ {
+		tabCol := tb.mutatedTable.Column(ord)
+		if tabCol.Kind() == cat.Ordinary && tabCol.Visibility() == cat.Visible {
+			targetCols = append(targetCols, tabCol)
+		}
+	}
+	if len(outScope.cols) != len(targetCols) {
+		panic(pgerror.New(pgcode.DatatypeMismatch,
+			"returned row structure does not match the structure of the triggering table"))
+	}
+	projections := make(memo.ProjectionsExpr, len(targetCols))
+	for i := range targetCols {
+		srcType := outScope.cols[i].typ
+		targetType := targetCols[i].DatumType()
+		var e opt.ScalarExpr = f.ConstructVariable(outScope.cols[i].id)
+		if !srcType.Identical(targetType) {
+			if !cast.ValidCast(srcType, targetType, cast.ContextAssignment) {
+				panic(sqlerrors.NewInvalidAssignmentCastError(
+					srcType, targetType, string(targetCols[i].ColName()),
+				))
+			}
+			e = f.ConstructAssignmentCast(e, targetType)
+		}
+		col := b.factory.Metadata().AddColumn(string(targetCols[i].ColName()), targetType)
+		projections[i] = f.ConstructProjectionsItem(e, col)
+	}
+	return f.ConstructProject(outScope.expr, projections, opt.ColSet{})
+}
+
+// addRowCols adds the given columns to the scope, using the names of the
+// ordinary columns of the mutated table qualified by the given table name.
+func (tb *triggerBuilder) addRowCols(b *Builder, s *scope, tabName tree.Name, cols opt.ColList) {
+	if len(cols) == 0 {
+		return
+	}
+	alias := tree.MakeUnqualifiedTableName(tabName)
+	md := b.factory.Metadata()
+	i := 0
+	for ord, n := 0, tb.mutatedTable.ColumnCount(); ord < n; ord++ {
+		tabCol := tb.mutatedTable.Column(ord)
+		if tabCol.Kind() != cat.Ordinary {
+			continue
+		}
+		s.cols = append(s.cols, scopeColumn{
+			name:       scopeColName(tabCol.ColName()),
+			table:      alias,
+			typ:        md.ColumnMeta(cols[i]).Type,
+			id:         cols[i],
+			visibility: columnVisibility(tabCol.Visibility()),
+		})
+		i++
+	}
+}
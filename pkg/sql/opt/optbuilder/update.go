@@ -75,21 +75,27 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	}
 
 	// Find which table we're working on, check the permissions.
-	tab, depName, alias, refColumns := b.resolveTableForMutation(upd.Table, privilege.UPDATE)
+	tab, depName, alias, refColumns := b.resolveTableForMutation(upd.Table, 0 /* priv */)
 
 	if refColumns != nil {
 		panic(pgerror.Newf(pgcode.Syntax,
 			"cannot specify a list of column IDs with UPDATE"))
 	}
 
+	// The privileges may have been granted only on some columns of the table,
+	// in which case they are checked on the columns once they are known.
+	checkUpdateColumns := b.checkTableOrColumnPrivilege(depName, tab, privilege.UPDATE)
+
 	// Check Select permission as well, since existing values must be read.
-	b.checkPrivilege(depName, tab, privilege.SELECT)
+	checkSelectColumns := b.checkTableOrColumnPrivilege(depName, tab, privilege.SELECT)
 
 	// Check if this table has already been mutated in another subquery.
 	b.checkMultipleMutations(tab, false /* simpleInsert */)
 
 	var mb mutationBuilder
 	mb.init(b, "update", tab, alias)
+	mb.initRowLevelSecurity()
+	mb.checkSelectColumns = checkSelectColumns
 
 	// Build the input expression that selects the rows that will be updated:
 	//
@@ -102,6 +108,9 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 
 	// Derive the columns that will be updated from the SET expressions.
 	mb.addTargetColsForUpdate(upd.Exprs)
+	if checkUpdateColumns {
+		mb.checkTargetColumnPrivileges(privilege.UPDATE)
+	}
 
 	// Build each of the SET expressions.
 	mb.addUpdateCols(upd.Exprs)
@@ -283,6 +292,10 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 	// Add assignment casts for update columns.
 	mb.addAssignmentCasts(mb.updateColIDs)
 
+	// The expressions written by the user have been built, so the privileges
+	// on the fetched columns no longer need to be checked.
+	mb.b.unrequireColumnPrivileges(mb.fetchScope)
+
 	// Add additional columns for computed expressions that may depend on the
 	// updated columns.
 	mb.addSynthesizedColsForUpdate()
@@ -313,6 +326,10 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.updateColIDs)
 
+	// BEFORE triggers can modify any of the values added so far, so they must
+	// run before the computed columns are added.
+	mb.buildInputForBeforeTriggers(tree.TriggerEventUpdate)
+
 	// Disambiguate names so that references in the computed expression refer to
 	// the correct columns.
 	mb.disambiguateColumns()
@@ -334,6 +351,9 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(true /* isUpdate */)
 
+	// Add the row-level security policy check column to the input.
+	mb.projectPolicyCheckCol(policyUpdate)
+
 	// Add the partial index predicate expressions to the table metadata.
 	// These expressions are used to prune fetch columns during
 	// normalization.
@@ -344,8 +364,14 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpdate()
 
+	mb.buildExclusionChecksForUpdate()
+
+	mb.buildPolicyCheck()
+
 	mb.buildFKChecksForUpdate()
 
+	mb.buildRowTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -355,5 +381,6 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	mb.outScope.expr = mb.b.factory.ConstructUpdate(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
+	mb.buildBeforeTriggersWith()
 	mb.buildReturning(returning)
 }
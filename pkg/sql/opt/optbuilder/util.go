@@ -513,7 +513,9 @@ func (b *Builder) checkMultipleMutations(tab cat.Table, simpleInsert bool) {
 //
 // If the name does not resolve to a table, then resolveTableForMutation raises
 // an error. Privileges are checked when resolving the table, and an error is
-// raised if the current user does not have the given privilege.
+// raised if the current user does not have the given privilege. If priv is
+// zero, the privileges are not checked, and the caller is responsible for
+// checking them.
 func (b *Builder) resolveTableForMutation(
 	n tree.TableExpr, priv privilege.Kind,
 ) (tab cat.Table, depName opt.MDDepName, alias tree.TableName, columns []tree.ColumnID) {
@@ -557,11 +559,17 @@ func (b *Builder) resolveTableForMutation(
 		alias = *outerAlias
 	}
 
-	// We can't mutate materialized views.
-	if tab.IsMaterializedView() {
+	// We can't mutate materialized views, except in order to maintain them
+	// incrementally.
+	if tab.IsMaterializedView() && !b.maintainingView {
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
+	// We can't mutate foreign tables.
+	if tab.IsForeignTable() {
+		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate foreign table %q", tab.Name()))
+	}
+
 	return tab, depName, alias, columns
 }
 
@@ -594,7 +602,8 @@ func (b *Builder) resolveTableRef(ref *tree.TableRef, priv privilege.Kind) cat.T
 // resolveDataSource returns the data source in the catalog with the given name,
 // along with the table's MDDepName and data source name. If the name does not
 // resolve to a table, or if the current user does not have the given privilege,
-// then resolveDataSource raises an error.
+// then resolveDataSource raises an error. If priv is zero, the privileges are
+// not checked, and the caller is responsible for checking them.
 //
 // If the b.qualifyDataSourceNamesInAST flag is set, tn is updated to contain
 // the fully qualified name.
@@ -611,7 +620,9 @@ func (b *Builder) resolveDataSource(
 		panic(err)
 	}
 	depName := opt.DepByName(tn)
-	b.checkPrivilege(depName, ds, priv)
+	if priv != 0 {
+		b.checkPrivilege(depName, ds, priv)
+	}
 
 	if b.qualifyDataSourceNamesInAST {
 		*tn = resName
@@ -624,7 +635,8 @@ func (b *Builder) resolveDataSource(
 // resolveDataSourceFromRef returns the data source in the catalog that matches
 // the given TableRef spec, along with the table's MDDepName. If no data source
 // matches, or if the current user does not have the given privilege, then
-// resolveDataSourceFromRef raises an error.
+// resolveDataSourceFromRef raises an error. If priv is zero, the privileges are
+// not checked, and the caller is responsible for checking them.
 func (b *Builder) resolveDataSourceRef(
 	ref *tree.TableRef, priv privilege.Kind,
 ) (cat.DataSource, opt.MDDepName) {
@@ -638,7 +650,9 @@ func (b *Builder) resolveDataSourceRef(
 		panic(pgerror.Wrapf(err, pgcode.UndefinedObject, "%s", tree.ErrString(ref)))
 	}
 	depName := opt.DepByID(cat.StableID(ref.TableID))
-	b.checkPrivilege(depName, ds, priv)
+	if priv != 0 {
+		b.checkPrivilege(depName, ds, priv)
+	}
 	return ds, depName
 }
 
@@ -648,7 +662,7 @@ func (b *Builder) resolveDataSourceRef(
 // dependency to the metadata, so that the privileges can be re-checked on reuse
 // of the memo.
 func (b *Builder) checkPrivilege(name opt.MDDepName, ds cat.DataSource, priv privilege.Kind) {
-	if !(priv == privilege.SELECT && b.skipSelectPrivilegeChecks) {
+	if !(priv == privilege.SELECT && b.skipSelectPrivilegeChecks) && !b.maintainingView {
 		err := b.catalog.CheckPrivilege(b.ctx, ds, priv)
 		if err != nil {
 			panic(err)
@@ -266,8 +266,14 @@ func (b *Builder) buildAggregationAsWindow(
 		}
 	}
 
+	// Exclude the rows added for the empty grouping sets from the aggregates.
+	var filterProjections memo.ProjectionsExpr
+	for i := range filterCols {
+		filterCols[i] = b.addGroupingSetsFilter(g, filterCols[i], &filterProjections)
+	}
+
 	// Initialize the aggregate expression.
-	aggregateExpr := g.aggInScope.expr
+	aggregateExpr := b.constructGroupingSetsFilters(g.aggInScope.expr, filterProjections)
 
 	// frames accumulates the set of distinct window frames we're computing over
 	// so that we can group functions over the same partition and ordering.
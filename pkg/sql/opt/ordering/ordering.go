@@ -154,6 +154,11 @@ func init() {
 		buildChildReqOrdering: invertedJoinBuildChildReqOrdering,
 		buildProvidedOrdering: invertedJoinBuildProvided,
 	}
+	funcMap[opt.BarrierOp] = funcs{
+		canProvideOrdering:    barrierCanProvideOrdering,
+		buildChildReqOrdering: barrierBuildChildReqOrdering,
+		buildProvidedOrdering: barrierBuildProvided,
+	}
 	funcMap[opt.OrdinalityOp] = funcs{
 		canProvideOrdering:    ordinalityCanProvideOrdering,
 		buildChildReqOrdering: ordinalityBuildChildReqOrdering,
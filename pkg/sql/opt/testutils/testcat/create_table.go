@@ -27,9 +27,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 )
 
 type indexType int
@@ -222,7 +224,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.Predicate, def.WithoutIndex)
+				tab.addUniqueConstraint(
+					def.Name, def.Columns, def.Predicate, def.WithoutIndex, def.Deferrability,
+				)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -233,6 +237,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.ColumnTableDef:
 			if def.Unique.IsUnique {
 				if def.Unique.WithoutIndex {
@@ -241,6 +248,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						tree.IndexElemList{{Column: def.Name}},
 						nil, /* predicate */
 						def.Unique.WithoutIndex,
+						tree.ConstraintNotDeferrable,
 					)
 				} else {
 					tab.addIndex(
@@ -535,13 +543,39 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	name := string(def.Name)
+	if name == "" {
+		name = fmt.Sprintf("%s_excl%d", tt.TabName.Table(), len(tt.Exclusions)+1)
+	}
+	c := cat.ExclusionConstraint{
+		Name:           name,
+		ColumnOrdinals: make([]int, len(def.Elems)),
+		Operators:      make([]treecmp.ComparisonOperatorSymbol, len(def.Elems)),
+		Validated:      true,
+	}
+	for i := range def.Elems {
+		c.ColumnOrdinals[i] = tt.FindOrdinal(string(def.Elems[i].Column))
+		c.Operators[i] = def.Elems[i].Operator.Symbol
+	}
+	if def.Predicate != nil {
+		c.Predicate = serializeTableDefExpr(def.Predicate)
+	}
+	tt.Exclusions = append(tt.Exclusions, c)
+}
+
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, predicate tree.Expr, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	predicate tree.Expr,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	// We don't currently use unique constraints with an index (those are already
 	// tracked with unique indexes), so don't bother adding them.
@@ -562,6 +596,7 @@ func (tt *Table) addUniqueConstraint(
 		columnOrdinals: cols,
 		withoutIndex:   withoutIndex,
 		validated:      true,
+		deferrability:  deferrability,
 	}
 	// Add partial unique constraint predicate.
 	if predicate != nil {
@@ -692,6 +727,36 @@ func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	tt.Columns = append(tt.Columns, col)
 }
 
+// makeVectorConfig returns the configuration of a vector index on a column of
+// the given type. Instead of being trained, the centroids of the lists are
+// [0, 0, ...], [1, 1, ...], [2, 2, ...] and so on, so that the lists searched
+// by a query are easy to predict. The number of lists is set by the lists
+// storage parameter, like it is for real vector indexes.
+func makeVectorConfig(
+	typ *types.T, colDef tree.IndexElem, params tree.StorageParams,
+) vecindex.Config {
+	if typ.Width() == 0 {
+		panic(fmt.Errorf("vector index column must have dimensions"))
+	}
+	cfg := vecindex.Config{
+		Dims:   typ.Width(),
+		Metric: vecindex.OpClassMetrics[string(colDef.OpClass)],
+		Lists:  vecindex.DefaultLists,
+	}
+	if expr := params.GetVal("lists"); expr != nil {
+		lists, err := strconv.Atoi(expr.String())
+		if err != nil {
+			panic(err)
+		}
+		cfg.Lists = int32(lists)
+	}
+	cfg.Centroids = make([]float32, cfg.Lists*cfg.Dims)
+	for i := range cfg.Centroids {
+		cfg.Centroids[i] = float32(i / int(cfg.Dims))
+	}
+	return cfg
+}
+
 func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 	return tt.addIndexWithVersion(def, typ, descpb.LatestIndexDescriptorVersion)
 }
@@ -701,7 +766,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, def.Predicate, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, def.Predicate, false /* withoutIndex */, tree.ConstraintNotDeferrable,
+		)
 	}
 
 	idx := &Index{
@@ -767,6 +834,11 @@ func (tt *Table) addIndexWithVersion(
 						MaxCells: 3,
 					}},
 				}
+
+			case types.PGVectorFamily:
+				idx.vectorConfig = makeVectorConfig(
+					tt.Columns[col.InvertedSourceColumnOrdinal()].DatumType(), colDef, def.StorageParams,
+				)
 			}
 		}
 	}
@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -272,6 +273,20 @@ func (tc *Catalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error {
 	return nil
 }
 
+// HasColumnPrivilege is part of the cat.Catalog interface.
+func (tc *Catalog) HasColumnPrivilege(
+	ctx context.Context, tab cat.Table, priv privilege.Kind,
+) (bool, error) {
+	return false, nil
+}
+
+// CheckColumnPrivilege is part of the cat.Catalog interface.
+func (tc *Catalog) CheckColumnPrivilege(
+	ctx context.Context, tab cat.Table, ord int, priv privilege.Kind,
+) error {
+	return tc.CheckPrivilege(ctx, tab, priv)
+}
+
 // HasAdminRole is part of the cat.Catalog interface.
 func (tc *Catalog) HasAdminRole(ctx context.Context) (bool, error) {
 	return true, nil
@@ -299,6 +314,16 @@ func (tc *Catalog) RoleExists(ctx context.Context, role username.SQLUsername) (b
 	return true, nil
 }
 
+// HasOwnership is part of the cat.Catalog interface.
+func (tc *Catalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	return true, nil
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (tc *Catalog) IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error) {
+	return true, nil
+}
+
 func (tc *Catalog) resolveSchema(toResolve *cat.SchemaName) (cat.Schema, cat.SchemaName, error) {
 	if string(toResolve.CatalogName) != testDB {
 		return nil, cat.SchemaName{}, pgerror.Newf(pgcode.InvalidSchemaName,
@@ -659,6 +684,8 @@ type Table struct {
 	Indexes    []*Index
 	Stats      TableStats
 	Checks     []cat.CheckConstraint
+	Triggers   []cat.Trigger
+	Exclusions []cat.ExclusionConstraint
 	Families   []*Family
 	IsVirtual  bool
 	IsSystem   bool
@@ -732,6 +759,11 @@ func (tt *Table) IsMaterializedView() bool {
 	return false
 }
 
+// IsForeignTable is part of the cat.Table interface.
+func (tt *Table) IsForeignTable() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (tt *Table) ColumnCount() int {
 	return len(tt.Columns)
@@ -822,6 +854,46 @@ func (tt *Table) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return len(tt.Triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	return tt.Triggers[i]
+}
+
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (tt *Table) ExclusionConstraintCount() int {
+	return len(tt.Exclusions)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (tt *Table) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return tt.Exclusions[i]
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (tt *Table) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (tt *Table) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // Zone is part of the cat.Table interface.
 func (tt *Table) Zone() cat.Zone {
 	zone := zonepb.DefaultZoneConfig()
@@ -949,6 +1021,10 @@ type Index struct {
 	// inverted index.
 	geoConfig geoindex.Config
 
+	// vectorConfig is the vector index configuration, if this is an ivfflat
+	// vector index.
+	vectorConfig vecindex.Config
+
 	// version is the index descriptor version of the index.
 	version descpb.IndexDescriptorVersion
 }
@@ -1056,6 +1132,11 @@ func (ti *Index) GeoConfig() geoindex.Config {
 	return ti.geoConfig
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (ti *Index) VectorConfig() vecindex.Config {
+	return ti.vectorConfig
+}
+
 // Version is part of the cat.Index interface.
 func (ti *Index) Version() descpb.IndexDescriptorVersion {
 	return ti.version
@@ -1227,10 +1308,11 @@ type ForeignKeyConstraint struct {
 	originColumnOrdinals     []int
 	referencedColumnOrdinals []int
 
-	validated    bool
-	matchMethod  tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validated     bool
+	matchMethod   tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1280,7 +1362,7 @@ func (fk *ForeignKeyConstraint) ReferencedColumnOrdinal(referencedTable cat.Tabl
 
 // Validated is part of the cat.ForeignKeyConstraint interface.
 func (fk *ForeignKeyConstraint) Validated() bool {
-	return fk.validated
+	return fk.validated && fk.deferrability == tree.ConstraintNotDeferrable
 }
 
 // MatchMethod is part of the cat.ForeignKeyConstraint interface.
@@ -1298,6 +1380,11 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // UniqueConstraint implements cat.UniqueConstraint. See that interface
 // for more information on the fields.
 type UniqueConstraint struct {
@@ -1307,6 +1394,7 @@ type UniqueConstraint struct {
 	predicate      string
 	withoutIndex   bool
 	validated      bool
+	deferrability  tree.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &UniqueConstraint{}
@@ -1349,7 +1437,7 @@ func (u *UniqueConstraint) WithoutIndex() bool {
 
 // Validated is part of the cat.UniqueConstraint interface.
 func (u *UniqueConstraint) Validated() bool {
-	return u.validated
+	return u.validated && u.deferrability == tree.ConstraintNotDeferrable
 }
 
 // UniquenessGuaranteedByAnotherIndex is part of the cat.UniqueConstraint
@@ -1358,6 +1446,11 @@ func (u *UniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return false
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
@@ -11,12 +11,15 @@
 package xform
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/ordering"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -332,6 +335,111 @@ func (c *CustomFuncs) GenerateLimitedTopKScans(
 	})
 }
 
+// GenerateVectorSearchScans generates a scan of each ivfflat vector index on
+// the scanned table that can find the rows nearest to a constant vector, when
+// the first column of the TopK ordering is the distance of the indexed column
+// from the vector. Only the lists of the index nearest to the vector are
+// scanned, and an IndexJoin supplies the columns missing from the index. See
+// the GenerateVectorSearch rule.
+func (c *CustomFuncs) GenerateVectorSearchScans(
+	grp memo.RelExpr,
+	sp *memo.ScanPrivate,
+	projections memo.ProjectionsExpr,
+	passthrough opt.ColSet,
+	tp *memo.TopKPrivate,
+) {
+	required := tp.Ordering
+	if len(required.Columns) == 0 || required.Columns[0].Descending {
+		return
+	}
+	var col opt.ColumnID
+	var vec vector.T
+	var metric vecindex.DistanceMetric
+	var ok bool
+	for i := range projections {
+		if required.Columns[0].Group.Contains(projections[i].Col) {
+			col, vec, metric, ok = vectorSearchArgs(projections[i].Element)
+			break
+		}
+	}
+	if !ok || !sp.Cols.Contains(col) {
+		return
+	}
+
+	probes := int(c.e.evalCtx.SessionData().IvfflatProbes)
+	if probes < 1 {
+		probes = 1
+	}
+	var pkCols opt.ColSet
+	var iter scanIndexIter
+	var sb indexScanBuilder
+	sb.Init(c, sp.Table)
+	iter.Init(c.e.evalCtx, c.e.f, c.e.mem, &c.im, sp, nil /* filters */, rejectNonInvertedIndexes)
+	iter.ForEach(func(index cat.Index, _ memo.FiltersExpr, _ opt.ColSet, _ bool, _ memo.ProjectionsExpr) {
+		cfg := index.VectorConfig()
+		if cfg.IsEmpty() || cfg.Metric != metric || int(cfg.Dims) != len(vec) ||
+			index.NonInvertedPrefixColumnCount() > 0 ||
+			sp.Table.ColumnID(index.InvertedColumn().InvertedSourceColumnOrdinal()) != col {
+			return
+		}
+		lists, err := cfg.NearestLists(vec, probes)
+		if err != nil {
+			return
+		}
+		// Each vector is stored in a single list, so the scanned spans never
+		// produce duplicate rows, and no inverted filter is needed.
+		spans := make(inverted.Spans, len(lists))
+		for i, list := range lists {
+			spans[i] = inverted.MakeSingleValSpan(vecindex.EncodeKey(nil /* inKey */, list))
+		}
+
+		// Calculate the PK columns once.
+		if pkCols.Empty() {
+			pkCols = c.PrimaryKeyCols(sp.Table)
+		}
+		newScanPrivate := *sp
+		newScanPrivate.Index = index.Ordinal()
+		newScanPrivate.InvertedConstraint = spans
+		newScanPrivate.Cols = pkCols.Copy()
+		sb.SetScan(&newScanPrivate)
+		sb.AddIndexJoin(sp.Cols)
+		input := c.e.f.ConstructProject(sb.BuildNewExpr(), projections, passthrough)
+		grp.Memo().AddTopKToGroup(&memo.TopKExpr{Input: input, TopKPrivate: *tp}, grp)
+	})
+}
+
+// vectorSearchArgs returns the column and the constant vector of a distance
+// between a column and a constant vector, along with the metric of the
+// distance. ok is false if e is not such a distance.
+func vectorSearchArgs(
+	e opt.ScalarExpr,
+) (col opt.ColumnID, vec vector.T, metric vecindex.DistanceMetric, ok bool) {
+	var left, right opt.ScalarExpr
+	switch t := e.(type) {
+	case *memo.VectorDistanceExpr:
+		left, right, metric = t.Left, t.Right, vecindex.DistanceMetric_L2
+	case *memo.VectorNegInnerProductExpr:
+		left, right, metric = t.Left, t.Right, vecindex.DistanceMetric_INNER_PRODUCT
+	case *memo.VectorCosDistanceExpr:
+		left, right, metric = t.Left, t.Right, vecindex.DistanceMetric_COSINE
+	default:
+		return 0, nil, 0, false
+	}
+	// All the distances are symmetric, so the column can be on either side.
+	if _, isVar := right.(*memo.VariableExpr); isVar {
+		left, right = right, left
+	}
+	v, isVar := left.(*memo.VariableExpr)
+	if !isVar || !memo.CanExtractConstDatum(right) {
+		return 0, nil, 0, false
+	}
+	d, isVec := memo.ExtractConstDatum(right).(*tree.DPGVector)
+	if !isVec {
+		return 0, nil, 0, false
+	}
+	return v.Col, d.T, metric, true
+}
+
 // getPrefixFromOrdering returns an OrderingChoice that holds the prefix
 // of Ordering o that satisfies part of the required OrderingChoice intraOrd,
 // a bool indicating whether the entire Ordering o was satisfied, and a bool
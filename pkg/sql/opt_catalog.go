@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -376,6 +377,43 @@ func (oc *optCatalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error
 	return oc.planner.CheckAnyPrivilege(ctx, desc)
 }
 
+// HasColumnPrivilege is part of the cat.Catalog interface.
+func (oc *optCatalog) HasColumnPrivilege(
+	ctx context.Context, tab cat.Table, priv privilege.Kind,
+) (bool, error) {
+	desc, err := getDescForDataSource(tab)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.hasColumnPrivilege(ctx, desc, 0 /* colID */, priv)
+}
+
+// CheckColumnPrivilege is part of the cat.Catalog interface.
+func (oc *optCatalog) CheckColumnPrivilege(
+	ctx context.Context, tab cat.Table, ord int, priv privilege.Kind,
+) error {
+	desc, err := getDescForDataSource(tab)
+	if err != nil {
+		return err
+	}
+	if err := oc.planner.CheckPrivilege(ctx, desc, priv); err == nil {
+		return nil
+	} else if pgerror.GetPGCode(err) != pgcode.InsufficientPrivilege {
+		return err
+	}
+	col := tab.Column(ord)
+	hasPriv, err := oc.planner.hasColumnPrivilege(ctx, desc, descpb.ColumnID(col.ColID()), priv)
+	if err != nil {
+		return err
+	}
+	if !hasPriv {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"user %s does not have %s privilege on column %s of table %s",
+			oc.planner.User(), priv, col.ColName(), tab.Name())
+	}
+	return nil
+}
+
 // HasAdminRole is part of the cat.Catalog interface.
 func (oc *optCatalog) HasAdminRole(ctx context.Context) (bool, error) {
 	return oc.planner.HasAdminRole(ctx)
@@ -444,6 +482,31 @@ func (oc *optCatalog) RoleExists(ctx context.Context, role username.SQLUsername)
 	return RoleExists(ctx, oc.planner.ExecCfg(), oc.planner.Txn(), role)
 }
 
+// HasOwnership is part of the cat.Catalog interface.
+func (oc *optCatalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	desc, err := getDescFromCatalogObjectForPermissions(o)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.HasOwnership(ctx, desc)
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (oc *optCatalog) IsMemberOfRole(
+	ctx context.Context, role username.SQLUsername,
+) (bool, error) {
+	user := oc.planner.User()
+	if role.IsPublicRole() || role == user {
+		return true, nil
+	}
+	memberOf, err := oc.planner.MemberOfWithAdminOption(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	_, ok := memberOf[role]
+	return ok, nil
+}
+
 // dataSourceForDesc returns a data source wrapper for the given descriptor.
 // The wrapper might come from the cache, or it may be created now.
 func (oc *optCatalog) dataSourceForDesc(
@@ -517,10 +580,40 @@ func (oc *optCatalog) dataSourceForTable(
 	if err != nil {
 		return nil, err
 	}
+	if err := oc.addIncrementalViewTriggers(ctx, ds); err != nil {
+		return nil, err
+	}
 	oc.dataSources[desc] = ds
 	return ds, nil
 }
 
+// addIncrementalViewTriggers adds the triggers which maintain the
+// incrementally refreshed materialized views that depend on the table to the
+// triggers of the table.
+func (oc *optCatalog) addIncrementalViewTriggers(ctx context.Context, ot *optTable) error {
+	for _, ref := range ot.desc.GetDependedOnBy() {
+		if !ref.IncrementalRefresh {
+			continue
+		}
+		view, err := oc.planner.Descriptors().GetImmutableTableByID(
+			ctx, oc.planner.Txn(), ref.ID, tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		m, err := newIncrementalViewMaintainer(ctx, oc.planner.Descriptors(), oc.planner.Txn(), view)
+		if err != nil {
+			return err
+		}
+		triggers, err := m.triggers(ot.desc.GetID())
+		if err != nil {
+			return err
+		}
+		ot.triggers = append(ot.triggers, triggers...)
+	}
+	return nil
+}
+
 var emptyZoneConfig = cat.EmptyZone()
 
 // getZoneConfig returns the ZoneConfig data structure for the given table.
@@ -714,6 +807,15 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []cat.CheckConstraint
 
+	// triggers is the set of row-level triggers for this table.
+	triggers []cat.Trigger
+
+	// exclusionConstraints is the set of EXCLUDE constraints for this table.
+	exclusionConstraints []cat.ExclusionConstraint
+
+	// policies is the set of row-level security policies for this table.
+	policies []cat.Policy
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -843,12 +945,13 @@ func newOptTable(
 	for i := range ot.desc.GetUniqueWithoutIndexConstraints() {
 		u := &ot.desc.GetUniqueWithoutIndexConstraints()[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			predicate:    u.Predicate,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:          u.Name,
+			table:         ot.ID(),
+			columns:       u.ColumnIDs,
+			predicate:     u.Predicate,
+			withoutIndex:  true,
+			validity:      u.Validity,
+			deferrability: u.Deferrability,
 		})
 	}
 
@@ -946,6 +1049,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 		return nil
 	})
@@ -960,6 +1064,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 		return nil
 	})
@@ -1006,6 +1111,99 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// Move all triggers into the opt table.
+	triggers := desc.GetTriggers()
+	ot.triggers = make([]cat.Trigger, len(triggers))
+	for i := range triggers {
+		t := &triggers[i]
+		ot.triggers[i] = cat.Trigger{
+			Name:   t.Name,
+			Before: t.ActionTime == descpb.TableDescriptor_Trigger_BEFORE,
+			Stmts:  t.Body,
+		}
+		if t.FunctionID != descpb.InvalidID {
+			ot.triggers[i].Function = &cat.TriggerFunction{
+				ID:   cat.StableID(t.FunctionID),
+				Name: t.FunctionName,
+			}
+		}
+		for _, e := range t.Events {
+			switch e {
+			case descpb.TableDescriptor_Trigger_INSERT:
+				ot.triggers[i].OnInsert = true
+			case descpb.TableDescriptor_Trigger_UPDATE:
+				ot.triggers[i].OnUpdate = true
+			case descpb.TableDescriptor_Trigger_DELETE:
+				ot.triggers[i].OnDelete = true
+			}
+		}
+	}
+
+	// Move all exclusion constraints into the opt table.
+	exclusionConstraints := desc.GetExclusionConstraints()
+	ot.exclusionConstraints = make([]cat.ExclusionConstraint, len(exclusionConstraints))
+	for i := range exclusionConstraints {
+		c := &exclusionConstraints[i]
+		ec := cat.ExclusionConstraint{
+			Name:           c.Name,
+			ColumnOrdinals: make([]int, len(c.ColumnIDs)),
+			Operators:      make([]treecmp.ComparisonOperatorSymbol, len(c.Operators)),
+			Predicate:      c.Predicate,
+			Validated:      c.Validity == descpb.ConstraintValidity_Validated,
+		}
+		for j, colID := range c.ColumnIDs {
+			ord, ok := ot.colMap.Get(colID)
+			if !ok {
+				return nil, errors.AssertionFailedf(
+					"exclusion constraint %q contains unknown column %d", c.Name, colID,
+				)
+			}
+			ec.ColumnOrdinals[j] = ord
+		}
+		for j, op := range c.Operators {
+			switch op {
+			case treecmp.EQ.String():
+				ec.Operators[j] = treecmp.EQ
+			case treecmp.Overlaps.String():
+				ec.Operators[j] = treecmp.Overlaps
+			default:
+				return nil, errors.AssertionFailedf(
+					"exclusion constraint %q contains unsupported operator %q", c.Name, op,
+				)
+			}
+		}
+		ot.exclusionConstraints[i] = ec
+	}
+
+	// Move all policies into the opt table.
+	policies := desc.GetPolicies()
+	ot.policies = make([]cat.Policy, len(policies))
+	for i := range policies {
+		p := &policies[i]
+		ot.policies[i] = cat.Policy{
+			Name:          p.Name,
+			Restrictive:   p.Restrictive,
+			Roles:         p.RoleNames,
+			UsingExpr:     p.UsingExpr,
+			WithCheckExpr: p.WithCheckExpr,
+		}
+		switch p.Command {
+		case descpb.TableDescriptor_Policy_ALL:
+			ot.policies[i].OnSelect = true
+			ot.policies[i].OnInsert = true
+			ot.policies[i].OnUpdate = true
+			ot.policies[i].OnDelete = true
+		case descpb.TableDescriptor_Policy_SELECT:
+			ot.policies[i].OnSelect = true
+		case descpb.TableDescriptor_Policy_INSERT:
+			ot.policies[i].OnInsert = true
+		case descpb.TableDescriptor_Policy_UPDATE:
+			ot.policies[i].OnUpdate = true
+		case descpb.TableDescriptor_Policy_DELETE:
+			ot.policies[i].OnDelete = true
+		}
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1127,6 +1325,11 @@ func (ot *optTable) IsMaterializedView() bool {
 	return ot.desc.MaterializedView()
 }
 
+// IsForeignTable implements the cat.Table interface.
+func (ot *optTable) IsForeignTable() bool {
+	return ot.desc.IsForeign()
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optTable) ColumnCount() int {
 	return len(ot.columns)
@@ -1231,6 +1434,46 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return ot.triggers[i]
+}
+
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraintCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return ot.exclusionConstraints[i]
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityEnabled() bool {
+	return ot.desc.GetRowLevelSecurity()
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityForced() bool {
+	return ot.desc.GetForceRowLevelSecurity()
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optTable) PolicyCount() int {
+	return len(ot.policies)
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optTable) Policy(i int) cat.Policy {
+	return ot.policies[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1544,6 +1787,11 @@ func (oi *optIndex) GeoConfig() geoindex.Config {
 	return oi.idx.IndexDesc().GeoConfig
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (oi *optIndex) VectorConfig() vecindex.Config {
+	return oi.idx.IndexDesc().VectorConfig
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optIndex) Version() descpb.IndexDescriptorVersion {
 	return oi.idx.GetVersion()
@@ -1712,8 +1960,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability descpb.ConstraintDeferrability
 
 	uniquenessGuaranteedByAnotherIndex bool
 }
@@ -1760,7 +2009,8 @@ func (u *optUniqueConstraint) WithoutIndex() bool {
 
 // Validated is part of the cat.UniqueConstraint interface.
 func (u *optUniqueConstraint) Validated() bool {
-	return u.validity == descpb.ConstraintValidity_Validated
+	return u.validity == descpb.ConstraintValidity_Validated &&
+		u.deferrability == descpb.ConstraintDeferrability_NotDeferrable
 }
 
 // UniquenessGuaranteedByAnotherIndex is part of the cat.UniqueConstraint
@@ -1771,6 +2021,11 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[u.deferrability]
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1784,10 +2039,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction catpb.ForeignKeyAction
-	updateAction catpb.ForeignKeyAction
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  catpb.ForeignKeyAction
+	updateAction  catpb.ForeignKeyAction
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1841,7 +2097,8 @@ func (fk *optForeignKeyConstraint) ReferencedColumnOrdinal(referencedTable cat.T
 
 // Validated is part of the cat.ForeignKeyConstraint interface.
 func (fk *optForeignKeyConstraint) Validated() bool {
-	return fk.validity == descpb.ConstraintValidity_Validated
+	return fk.validity == descpb.ConstraintValidity_Validated &&
+		fk.deferrability == descpb.ConstraintDeferrability_NotDeferrable
 }
 
 // MatchMethod is part of the cat.ForeignKeyConstraint interface.
@@ -1859,6 +2116,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[fk.deferrability]
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2051,6 +2313,11 @@ func (ot *optVirtualTable) IsMaterializedView() bool {
 	return false
 }
 
+// IsForeignTable implements the cat.Table interface.
+func (ot *optVirtualTable) IsForeignTable() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optVirtualTable) ColumnCount() int {
 	return len(ot.columns)
@@ -2156,6 +2423,46 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraintCount() int {
+	return 0
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optVirtualTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optVirtualTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
@@ -2336,6 +2643,11 @@ func (oi *optVirtualIndex) GeoConfig() geoindex.Config {
 	return geoindex.Config{}
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (oi *optVirtualIndex) VectorConfig() vecindex.Config {
+	return vecindex.Config{}
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optVirtualIndex) Version() descpb.IndexDescriptorVersion {
 	return 0
@@ -162,6 +162,30 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER foo AFTER INSERT ON bar FOR EACH ROW ??`, `CREATE TRIGGER`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+
+		{`CREATE POLICY ??`, `CREATE POLICY`},
+		{`CREATE POLICY p ON t FOR SELECT ??`, `CREATE POLICY`},
+		{`DROP POLICY ??`, `DROP POLICY`},
+
+		{`CREATE AGGREGATE ??`, `CREATE AGGREGATE`},
+		{`CREATE AGGREGATE foo(INT) (SFUNC = ??`, `CREATE AGGREGATE`},
+		{`DROP AGGREGATE ??`, `DROP AGGREGATE`},
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+		{`CREATE PROCEDURE ??`, `CREATE PROCEDURE`},
+		{`CREATE OR REPLACE PROCEDURE p(a INT) ??`, `CREATE PROCEDURE`},
+		{`DROP PROCEDURE ??`, `DROP PROCEDURE`},
+		{`ALTER AGGREGATE foo(INT) RENAME TO bar ??`, `ALTER AGGREGATE`},
+
+		{`CREATE FOREIGN TABLE ??`, `CREATE FOREIGN TABLE`},
+		{`CREATE FOREIGN TABLE foo (a INT) CSV DATA ??`, `CREATE FOREIGN TABLE`},
+
+		{`CREATE PUBLICATION ??`, `CREATE PUBLICATION`},
+		{`CREATE PUBLICATION foo FOR ??`, `CREATE PUBLICATION`},
+		{`DROP PUBLICATION ??`, `DROP PUBLICATION`},
+
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA bli ??`, `CREATE SCHEMA`},
@@ -174,6 +198,16 @@ func TestContextualHelp(t *testing.T) {
 		{`DISCARD ALL ??`, `DISCARD`},
 		{`DISCARD ??`, `DISCARD`},
 
+		{`CALL ??`, `CALL`},
+		{`CALL p(1, ??`, `CALL`},
+
+		{`DO ??`, `DO`},
+
+		{`LISTEN ??`, `LISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY foo, ??`, `NOTIFY`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+
 		{`DROP ??`, `DROP`},
 
 		{`DROP DATABASE IF ??`, `DROP DATABASE`},
@@ -197,6 +231,7 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP TABLE blah ??`, `DROP TABLE`},
 		{`DROP TABLE IF ??`, `DROP TABLE`},
 		{`DROP TABLE IF EXISTS blih, bloh ??`, `DROP TABLE`},
+		{`DROP FOREIGN TABLE ??`, `DROP TABLE`},
 
 		{`DROP VIEW blah ??`, `DROP VIEW`},
 		{`DROP VIEW IF ??`, `DROP VIEW`},
@@ -264,6 +299,10 @@ func TestContextualHelp(t *testing.T) {
 		{`UPDATE blah SET x = 3 ??`, `UPDATE`},
 		{`UPDATE blah SET x = 3 WHERE ??`, `UPDATE`},
 
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN ??`, `MERGE`},
+
 		{`GRANT ALL ??`, `GRANT`},
 		{`GRANT ALL ON foo TO ??`, `GRANT`},
 		{`GRANT ALL ON foo TO bar ??`, `GRANT`},
@@ -440,6 +479,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SET LOCAL TIME ??`, `SET LOCAL`},
 		{`SET LOCAL TIME ZONE 'UTC' ??`, `SET LOCAL`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
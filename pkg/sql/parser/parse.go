@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/scanner"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 )
 
@@ -414,6 +415,23 @@ func newBitType(width int32, varying bool) (*types.T, error) {
 	return types.MakeBit(width), nil
 }
 
+var errVectorDimsNotPositive = pgerror.WithCandidateCode(
+	errors.New("dimensions for type vector must be at least 1"), pgcode.InvalidParameterValue)
+var errVectorDimsTooLarge = pgerror.WithCandidateCode(
+	errors.Newf("dimensions for type vector cannot exceed %d", vector.MaxDim), pgcode.InvalidParameterValue)
+
+// newPGVectorType creates a new VECTOR type with the given number of
+// dimensions.
+func newPGVectorType(dims int32) (*types.T, error) {
+	if dims < 1 {
+		return nil, errVectorDimsNotPositive
+	}
+	if dims > vector.MaxDim {
+		return nil, errVectorDimsTooLarge
+	}
+	return types.MakePGVector(dims), nil
+}
+
 var errFloatPrecAtLeast1 = pgerror.WithCandidateCode(
 	errors.New("precision for type float must be at least 1 bit"), pgcode.InvalidParameterValue)
 var errFloatPrecMax54 = pgerror.WithCandidateCode(
@@ -39,6 +39,10 @@ func TestScanner(t *testing.T) {
 		{`<=`, []int{LESS_EQUALS}},
 		{`<<`, []int{LSHIFT}},
 		{`<<=`, []int{INET_CONTAINED_BY_OR_EQUALS}},
+		{`<->`, []int{DISTANCE}},
+		{`<-1`, []int{'<', '-', ICONST}},
+		{`<#>`, []int{NEG_INNER_PRODUCT}},
+		{`<=>`, []int{COS_DISTANCE}},
 		{`>`, []int{'>'}},
 		{`>=`, []int{GREATER_EQUALS}},
 		{`>>`, []int{RSHIFT}},
@@ -63,6 +67,9 @@ func TestScanner(t *testing.T) {
 		{`$`, []int{'$'}},
 		{`&`, []int{'&'}},
 		{`&&`, []int{AND_AND}},
+		{`@@`, []int{AT_AT}},
+		{`@?`, []int{JSON_PATH_EXISTS}},
+		{`-|-`, []int{ADJACENT}},
 		{`|`, []int{'|'}},
 		{`||`, []int{CONCAT}},
 		{`|/`, []int{SQRT}},
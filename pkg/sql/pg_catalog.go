@@ -21,6 +21,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -608,6 +609,7 @@ var (
 	relKindView             = tree.NewDString("v")
 	relKindMaterializedView = tree.NewDString("m")
 	relKindSequence         = tree.NewDString("S")
+	relKindForeignTable     = tree.NewDString("f")
 
 	relPersistencePermanent = tree.NewDString("p")
 	relPersistenceTemporary = tree.NewDString("t")
@@ -632,6 +634,9 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 		} else if table.IsSequence() {
 			relKind = relKindSequence
 			relAm = oidZero
+		} else if table.IsForeign() {
+			relKind = relKindForeignTable
+			relAm = oidZero
 		}
 		relPersistence := relPersistencePermanent
 		if table.IsTemporary() {
@@ -964,6 +969,7 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				f.FormatNode(descpb.ConstraintDeferrabilityType[con.UniqueWithoutIndexConstraint.Deferrability])
 				if con.UniqueWithoutIndexConstraint.Validity != descpb.ConstraintValidity_Validated {
 					f.WriteString(" NOT VALID")
 				}
@@ -998,15 +1004,35 @@ func populateTableConstraints(
 				validity = " NOT VALID"
 			}
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
+
+		case descpb.ConstraintTypeExclusion:
+			conoid = h.ExclusionConstraintOid(db.GetID(), scName, table.GetID(), con.ExclusionConstraint)
+			contype = conTypeExclusion
+			if conkey, err = colIDArrayToDatum(con.ExclusionConstraint.ColumnIDs); err != nil {
+				return err
+			}
+			f := tree.NewFmtCtx(tree.FmtSimple)
+			if err := formatExclusionConstraint(
+				ctx, table, con.ExclusionConstraint, p.SemaCtx(), p.SessionData(), tree.FmtPGCatalog, f,
+			); err != nil {
+				return err
+			}
+			if con.ExclusionConstraint.Validity != descpb.ConstraintValidity_Validated {
+				f.WriteString(" NOT VALID")
+			}
+			condef = tree.NewDString(f.CloseAndGetString())
 		}
 
+		deferrability := con.Deferrability()
+		condeferrable := tree.MakeDBool(tree.DBool(deferrability != descpb.ConstraintDeferrability_NotDeferrable))
+		condeferred := tree.MakeDBool(tree.DBool(deferrability == descpb.ConstraintDeferrability_InitiallyDeferred))
 		if err := addRow(
 			conoid,               // oid
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -2386,16 +2412,27 @@ https://www.postgresql.org/docs/9.5/catalog-pg-proc.html`,
 }
 
 var pgCatalogRangeTable = virtualSchemaTable{
-	comment: `range types (empty - feature does not exist)
+	comment: `range types
 https://www.postgresql.org/docs/9.5/catalog-pg-range.html`,
 	schema: vtable.PGCatalogRange,
 	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// We currently do not support any range types, so this table is empty.
-		// This table should be populated when any range types are added to
-		// oidToDatum (and therefore pg_type).
+		// Only the built-in range types are supported, none of which have a
+		// collatable subtype. Operator classes, canonical functions and subtype
+		// difference functions are not exposed.
+		for _, typ := range types.RangeTypes {
+			if err := addRow(
+				tree.NewDOid(typ.Oid()),                 // rngtypid
+				tree.NewDOid(typ.RangeContents().Oid()), // rngsubtype
+				oidZero,                                 // rngcollation
+				oidZero,                                 // rngsubopc
+				oidZero,                                 // rngcanonical
+				oidZero,                                 // rngsubdiff
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogRewriteTable = virtualSchemaTable{
@@ -2782,7 +2819,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typTypeDomain
 	_ = typTypePseudo
-	_ = typTypeRange
 
 	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
 	typCategoryArray       = tree.NewDString("A")
@@ -2804,7 +2840,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typCategoryEnum
 	_ = typCategoryGeometric
-	_ = typCategoryRange
 	_ = typCategoryBitString
 
 	commaTypDelim = tree.NewDString(",")
@@ -2905,6 +2940,10 @@ func addPGTypeRow(
 		builtinPrefix = "enum_"
 		typType = typTypeEnum
 	}
+	if typ.Family() == types.RangeFamily {
+		builtinPrefix = "range_"
+		typType = typTypeRange
+	}
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
@@ -3575,12 +3614,31 @@ var pgCatalogStatUserFunctionsTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications
+https://www.postgresql.org/docs/current/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for _, pub := range db.GetPublications() {
+					if err := addRow(
+						h.PublicationOid(db.GetID(), pub.Name),    // oid
+						tree.NewDName(pub.Name),                   // pubname
+						getOwnerOID(db),                           // pubowner
+						tree.MakeDBool(tree.DBool(pub.AllTables)), // puballtables
+						tree.DBoolTrue,                            // pubinsert
+						tree.DBoolTrue,                            // pubupdate
+						tree.DBoolTrue,                            // pubdelete
+						tree.DBoolFalse,                           // pubtruncate
+						tree.DBoolFalse,                           // pubviaroot
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAmprocTable = virtualSchemaTable{
@@ -3735,12 +3793,19 @@ var pgCatalogStatXactUserTablesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables of publications
+https://www.postgresql.org/docs/current/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachPublicationTable(ctx, p, dbContext,
+			func(db catalog.DatabaseDescriptor, pub *descpb.DatabaseDescriptor_Publication, scName string, table catalog.TableDescriptor) error {
+				return addRow(
+					tree.NewDName(pub.Name),        // pubname
+					tree.NewDName(scName),          // schemaname
+					tree.NewDName(table.GetName()), // tablename
+				)
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatProgressClusterTable = virtualSchemaTable{
@@ -3771,12 +3836,57 @@ var pgCatalogLargeobjectMetadataTable = virtualSchemaTable{
 }
 
 var pgCatalogReplicationSlotsTable = virtualSchemaTable{
-	comment: "pg_replication_slots was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogReplicationSlots,
+	comment: `logical replication slots
+https://www.postgresql.org/docs/current/view-pg-replication-slots.html`,
+	schema: vtable.PgCatalogReplicationSlots,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ReplicationSlotsTable) {
+			return nil
+		}
+		var temporary temporaryReplicationSlots
+		if p.extendedEvalCtx.TemporaryReplicationSlots != nil {
+			temporary = *p.extendedEvalCtx.TemporaryReplicationSlots
+		}
+		slots, err := listReplicationSlots(ctx, p.ExecCfg().InternalExecutor, p.Txn(), temporary)
+		if err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			dbName := tree.DNull
+			_, db, err := p.Descriptors().GetImmutableDatabaseByID(
+				ctx, p.Txn(), slot.dbID, tree.DatabaseLookupFlags{},
+			)
+			if err != nil {
+				return err
+			}
+			if db != nil {
+				dbName = tree.NewDName(db.GetName())
+			}
+			// The changes are not retained for the slots, so the restart LSN is
+			// the confirmed flush LSN.
+			confirmed := tree.NewDString(slot.confirmedFlushLSN.String())
+			isTemporary := tree.MakeDBool(tree.DBool(slot.temporary))
+			if err := addRow(
+				tree.NewDName(slot.name),   // slot_name
+				tree.NewDName(slot.plugin), // plugin
+				tree.NewDString("logical"), // slot_type
+				dbOid(slot.dbID),           // datoid
+				dbName,                     // database
+				isTemporary,                // temporary
+				tree.DBoolFalse,            // active
+				tree.DNull,                 // active_pid
+				tree.DNull,                 // xmin
+				tree.DNull,                 // catalog_xmin
+				confirmed,                  // restart_lsn
+				confirmed,                  // confirmed_flush_lsn
+				tree.DNull,                 // wal_status
+				tree.DNull,                 // safe_wal_size
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogSubscriptionRelTable = virtualSchemaTable{
@@ -4014,12 +4124,53 @@ var pgCatalogStatReplicationTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables listed by publications
+https://www.postgresql.org/docs/current/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachPublicationTable(ctx, p, dbContext,
+			func(db catalog.DatabaseDescriptor, pub *descpb.DatabaseDescriptor_Publication, scName string, table catalog.TableDescriptor) error {
+				// Like in Postgres, the tables of publications FOR ALL TABLES are not
+				// listed.
+				if pub.AllTables {
+					return nil
+				}
+				return addRow(
+					h.PublicationRelOid(db.GetID(), pub.Name, table.GetID()), // oid
+					h.PublicationOid(db.GetID(), pub.Name),                   // prpubid
+					tableOid(table.GetID()),                                  // prrelid
+				)
+			})
 	},
-	unimplemented: true,
+}
+
+// forEachPublicationTable calls fn for each table of each publication of the
+// databases visible to the user.
+func forEachPublicationTable(
+	ctx context.Context,
+	p *planner,
+	dbContext catalog.DatabaseDescriptor,
+	fn func(catalog.DatabaseDescriptor, *descpb.DatabaseDescriptor_Publication, string, catalog.TableDescriptor) error,
+) error {
+	return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+		func(db catalog.DatabaseDescriptor) error {
+			pubs := db.GetPublications()
+			if len(pubs) == 0 {
+				return nil
+			}
+			return forEachTableDesc(ctx, p, db, hideVirtual,
+				func(db catalog.DatabaseDescriptor, scName string, table catalog.TableDescriptor) error {
+					for i := range pubs {
+						if publicationIncludesTable(&pubs[i], table) {
+							if err := fn(db, &pubs[i], scName, table); err != nil {
+								return err
+							}
+						}
+					}
+					return nil
+				})
+		})
 }
 
 var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
@@ -4086,12 +4237,17 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.GeographyFamily:   typCategoryUserDefined,
 	types.GeometryFamily:    typCategoryUserDefined,
 	types.JsonFamily:        typCategoryUserDefined,
+	types.JsonpathFamily:    typCategoryUserDefined,
 	types.DecimalFamily:     typCategoryNumeric,
 	types.StringFamily:      typCategoryString,
 	types.TimestampFamily:   typCategoryDateTime,
 	types.TimestampTZFamily: typCategoryDateTime,
 	types.ArrayFamily:       typCategoryArray,
 	types.TupleFamily:       typCategoryPseudo,
+	types.RangeFamily:       typCategoryRange,
+	types.TSQueryFamily:     typCategoryUserDefined,
+	types.TSVectorFamily:    typCategoryUserDefined,
+	types.PGVectorFamily:    typCategoryUserDefined,
 	types.OidFamily:         typCategoryNumeric,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
@@ -4317,6 +4473,9 @@ const (
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
 	castTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
+	exclusionConstraintTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -4425,6 +4584,17 @@ func (h oidHasher) UniqueWithoutIndexConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scName string, tableID descpb.ID, c *descpb.TableDescriptor_ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scName)
+	h.writeTable(tableID)
+	h.writeStr(c.Name)
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	dbID descpb.ID, scName string, tableID descpb.ID, indexID descpb.IndexID,
 ) *tree.DOid {
@@ -4519,3 +4689,21 @@ func (h oidHasher) CastOid(srcID oid.Oid, tgtID oid.Oid) *tree.DOid {
 	h.writeUInt32(uint32(tgtID))
 	return h.getOid()
 }
+
+// PublicationOid creates an OID for a publication of a database.
+func (h oidHasher) PublicationOid(dbID descpb.ID, pubName string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(pubName)
+	return h.getOid()
+}
+
+// PublicationRelOid creates an OID for the membership of a table in a
+// publication.
+func (h oidHasher) PublicationRelOid(dbID descpb.ID, pubName string, tableID descpb.ID) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(pubName)
+	h.writeTable(tableID)
+	return h.getOid()
+}
@@ -0,0 +1,350 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/lsn"
+)
+
+// OutputPlugin is the name of the only supported logical decoding output
+// plugin.
+const OutputPlugin = "pgoutput"
+
+// ParseCommand parses a command of the streaming replication protocol. If the
+// command is not a replication command, ok is false and the command should be
+// parsed as a SQL statement instead.
+func ParseCommand(sql string) (stmt tree.Statement, ok bool, err error) {
+	s := scanner{in: sql}
+	first := s.peek()
+	if first.kind != tokWord {
+		return nil, false, nil
+	}
+	switch strings.ToUpper(first.val) {
+	case "IDENTIFY_SYSTEM":
+		s.next()
+		stmt = &tree.IdentifySystem{}
+	case "CREATE_REPLICATION_SLOT":
+		s.next()
+		stmt, err = s.parseCreateReplicationSlot()
+	case "DROP_REPLICATION_SLOT":
+		s.next()
+		stmt, err = s.parseDropReplicationSlot()
+	case "START_REPLICATION":
+		s.next()
+		stmt, err = s.parseStartReplication()
+	case "ALTER_REPLICATION_SLOT", "BASE_BACKUP", "READ_REPLICATION_SLOT", "TIMELINE_HISTORY":
+		return nil, true, unimplemented.Newf("replication command",
+			"replication command %s is not supported", strings.ToUpper(first.val))
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	if err := s.expectEnd(); err != nil {
+		return nil, true, err
+	}
+	return stmt, true, nil
+}
+
+// parseCreateReplicationSlot parses the arguments of:
+//
+//   CREATE_REPLICATION_SLOT slot_name [ TEMPORARY ] LOGICAL output_plugin
+//     [ ( option [, ...] ) | legacy_option [...] ]
+//
+// The options control the snapshot exported with the slot. Slots never export
+// snapshots, so the options are ignored.
+func (s *scanner) parseCreateReplicationSlot() (tree.Statement, error) {
+	slot, err := s.expectName()
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.CreateReplicationSlot{Slot: tree.Name(slot)}
+	if s.acceptKeyword("TEMPORARY") {
+		n.Temporary = true
+	}
+	if s.acceptKeyword("PHYSICAL") {
+		return nil, unimplemented.New("physical replication", "physical replication is not supported")
+	}
+	if err := s.expectKeyword("LOGICAL"); err != nil {
+		return nil, err
+	}
+	plugin, err := s.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if plugin != OutputPlugin {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"output plugin %q is not supported, use %q", plugin, OutputPlugin)
+	}
+	n.Plugin = tree.Name(plugin)
+	if s.peek().kind == tokLParen {
+		if _, err := s.parseOptions(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	for s.peek().kind == tokWord {
+		s.next()
+	}
+	return n, nil
+}
+
+// parseDropReplicationSlot parses the arguments of:
+//
+//   DROP_REPLICATION_SLOT slot_name [ WAIT ]
+//
+func (s *scanner) parseDropReplicationSlot() (tree.Statement, error) {
+	slot, err := s.expectName()
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.DropReplicationSlot{Slot: tree.Name(slot)}
+	if s.acceptKeyword("WAIT") {
+		n.Wait = true
+	}
+	return n, nil
+}
+
+// parseStartReplication parses the arguments of:
+//
+//   START_REPLICATION SLOT slot_name LOGICAL XXX/XXX
+//     [ ( option_name [ option_value ] [, ...] ) ]
+//
+func (s *scanner) parseStartReplication() (tree.Statement, error) {
+	if !s.acceptKeyword("SLOT") {
+		return nil, unimplemented.New("physical replication", "physical replication is not supported")
+	}
+	slot, err := s.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if s.acceptKeyword("PHYSICAL") {
+		return nil, unimplemented.New("physical replication", "physical replication is not supported")
+	}
+	if err := s.expectKeyword("LOGICAL"); err != nil {
+		return nil, err
+	}
+	t := s.next()
+	if t.kind != tokWord {
+		return nil, s.syntaxError(t)
+	}
+	startLSN, err := lsn.ParseLSN(t.val)
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.StartReplication{Slot: tree.Name(slot), StartLSN: startLSN}
+	if s.peek().kind == tokLParen {
+		if n.Options, err = s.parseOptions(); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// parseOptions parses a parenthesized list of options, each of which is a
+// name optionally followed by a value.
+func (s *scanner) parseOptions() (tree.KVOptions, error) {
+	s.next()
+	var opts tree.KVOptions
+	for {
+		key, err := s.expectName()
+		if err != nil {
+			return nil, err
+		}
+		opt := tree.KVOption{Key: tree.Name(key)}
+		switch t := s.peek(); t.kind {
+		case tokString, tokWord:
+			s.next()
+			opt.Value = tree.NewStrVal(t.val)
+		}
+		opts = append(opts, opt)
+		switch t := s.next(); t.kind {
+		case tokComma:
+		case tokRParen:
+			return opts, nil
+		default:
+			return nil, s.syntaxError(t)
+		}
+	}
+}
+
+// ParsePublicationNames parses the value of the publication_names option of
+// START_REPLICATION, which is a comma-separated list of identifiers.
+func ParsePublicationNames(names string) ([]string, error) {
+	s := scanner{in: names}
+	var res []string
+	for {
+		name, err := s.expectName()
+		if err != nil {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid publication_names syntax: %q", names)
+		}
+		res = append(res, name)
+		switch t := s.next(); t.kind {
+		case tokComma:
+		case tokEOF:
+			return res, nil
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid publication_names syntax: %q", names)
+		}
+	}
+}
+
+func (s *scanner) expectName() (string, error) {
+	t := s.next()
+	switch t.kind {
+	case tokWord:
+		return strings.ToLower(t.val), nil
+	case tokIdent:
+		return t.val, nil
+	}
+	return "", s.syntaxError(t)
+}
+
+func (s *scanner) acceptKeyword(kw string) bool {
+	if t := s.peek(); t.kind == tokWord && strings.EqualFold(t.val, kw) {
+		s.next()
+		return true
+	}
+	return false
+}
+
+func (s *scanner) expectKeyword(kw string) error {
+	if !s.acceptKeyword(kw) {
+		return s.syntaxError(s.peek())
+	}
+	return nil
+}
+
+func (s *scanner) expectEnd() error {
+	if s.peek().kind == tokSemicolon {
+		s.next()
+	}
+	if t := s.next(); t.kind != tokEOF {
+		return s.syntaxError(t)
+	}
+	return nil
+}
+
+func (s *scanner) syntaxError(t token) error {
+	if t.kind == tokEOF {
+		return pgerror.New(pgcode.Syntax, "syntax error at end of input")
+	}
+	return pgerror.Newf(pgcode.Syntax, "syntax error at or near %q", t.val)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokWord is an unquoted keyword, identifier or LSN.
+	tokWord
+	// tokIdent is a double-quoted identifier.
+	tokIdent
+	// tokString is a single-quoted string literal.
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokSemicolon
+	// tokInvalid is an unterminated quoted identifier or string literal.
+	tokInvalid
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+// scanner splits a replication command into tokens. The replication commands
+// have a much simpler lexical structure than SQL statements.
+type scanner struct {
+	in     string
+	pos    int
+	peeked *token
+}
+
+func (s *scanner) peek() token {
+	if s.peeked == nil {
+		t := s.scan()
+		s.peeked = &t
+	}
+	return *s.peeked
+}
+
+func (s *scanner) next() token {
+	t := s.peek()
+	s.peeked = nil
+	return t
+}
+
+func (s *scanner) scan() token {
+	for s.pos < len(s.in) && isSpace(s.in[s.pos]) {
+		s.pos++
+	}
+	if s.pos == len(s.in) {
+		return token{kind: tokEOF}
+	}
+	start := s.pos
+	switch c := s.in[s.pos]; c {
+	case '(':
+		s.pos++
+		return token{kind: tokLParen, val: "("}
+	case ')':
+		s.pos++
+		return token{kind: tokRParen, val: ")"}
+	case ',':
+		s.pos++
+		return token{kind: tokComma, val: ","}
+	case ';':
+		s.pos++
+		return token{kind: tokSemicolon, val: ";"}
+	case '\'', '"':
+		// Quotes are escaped by doubling them.
+		var b strings.Builder
+		s.pos++
+		for s.pos < len(s.in) {
+			if s.in[s.pos] == c {
+				if s.pos+1 < len(s.in) && s.in[s.pos+1] == c {
+					b.WriteByte(c)
+					s.pos += 2
+					continue
+				}
+				s.pos++
+				kind := tokString
+				if c == '"' {
+					kind = tokIdent
+				}
+				return token{kind: kind, val: b.String()}
+			}
+			b.WriteByte(s.in[s.pos])
+			s.pos++
+		}
+		return token{kind: tokInvalid, val: s.in[start:]}
+	default:
+		for s.pos < len(s.in) && !isSpace(s.in[s.pos]) && !strings.ContainsRune("(),;'\"", rune(s.in[s.pos])) {
+			s.pos++
+		}
+		return token{kind: tokWord, val: s.in[start:s.pos]}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out string
+		err string
+	}{
+		{in: `IDENTIFY_SYSTEM`, out: `IDENTIFY_SYSTEM`},
+		{in: ` identify_system ; `, out: `IDENTIFY_SYSTEM`},
+		{in: `CREATE_REPLICATION_SLOT s LOGICAL pgoutput`, out: `CREATE_REPLICATION_SLOT s LOGICAL pgoutput`},
+		{
+			in:  `CREATE_REPLICATION_SLOT "My Slot" TEMPORARY LOGICAL pgoutput NOEXPORT_SNAPSHOT`,
+			out: `CREATE_REPLICATION_SLOT "My Slot" TEMPORARY LOGICAL pgoutput`,
+		},
+		{
+			in:  `CREATE_REPLICATION_SLOT s LOGICAL pgoutput (SNAPSHOT 'nothing')`,
+			out: `CREATE_REPLICATION_SLOT s LOGICAL pgoutput`,
+		},
+		{in: `CREATE_REPLICATION_SLOT s LOGICAL test_decoding`, err: `output plugin "test_decoding" is not supported`},
+		{in: `CREATE_REPLICATION_SLOT s PHYSICAL`, err: `physical replication is not supported`},
+		{in: `CREATE_REPLICATION_SLOT s`, err: `syntax error at end of input`},
+		{in: `DROP_REPLICATION_SLOT s`, out: `DROP_REPLICATION_SLOT s`},
+		{in: `DROP_REPLICATION_SLOT S WAIT;`, out: `DROP_REPLICATION_SLOT s WAIT`},
+		{in: `DROP_REPLICATION_SLOT s NOW`, err: `syntax error at or near "NOW"`},
+		{in: `START_REPLICATION SLOT s LOGICAL 0/0`, out: `START_REPLICATION SLOT s LOGICAL 0/0`},
+		{
+			in:  `START_REPLICATION SLOT s LOGICAL 16/B374D848 ("proto_version" '1', "publication_names" 'p1,"P2"')`,
+			out: `START_REPLICATION SLOT s LOGICAL 16/B374D848 (proto_version '1', publication_names 'p1,"P2"')`,
+		},
+		{
+			in:  `START_REPLICATION SLOT s LOGICAL 0/1 (binary, messages 'it''s')`,
+			out: `START_REPLICATION SLOT s LOGICAL 0/1 (binary, messages e'it\'s')`,
+		},
+		{in: `START_REPLICATION 0/0`, err: `physical replication is not supported`},
+		{in: `START_REPLICATION SLOT s LOGICAL 0/0 (`, err: `syntax error at end of input`},
+		{in: `START_REPLICATION SLOT s LOGICAL 0/0 (a 'b'`, err: `syntax error at end of input`},
+		{in: `START_REPLICATION SLOT s LOGICAL 0/0 (a 'b`, err: `syntax error at or near "'b"`},
+		{in: `START_REPLICATION SLOT s LOGICAL x`, err: `invalid LSN: "x"`},
+		{in: `BASE_BACKUP`, err: `replication command BASE_BACKUP is not supported`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			stmt, ok, err := ParseCommand(tc.in)
+			require.True(t, ok)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.out, tree.AsString(stmt))
+		})
+	}
+
+	// SQL statements are not replication commands.
+	for _, sql := range []string{`SELECT 1`, `SHOW server_version`, `"IDENTIFY_SYSTEM"`, ``} {
+		_, ok, err := ParseCommand(sql)
+		require.NoError(t, err)
+		require.False(t, ok, sql)
+	}
+}
+
+func TestParsePublicationNames(t *testing.T) {
+	names, err := ParsePublicationNames(` p1, "P2",P3 `)
+	require.NoError(t, err)
+	require.Equal(t, []string{"p1", "P2", "p3"}, names)
+
+	for _, s := range []string{``, `p1,`, `p1 p2`, `"p1`} {
+		_, err := ParsePublicationNames(s)
+		require.Error(t, err, s)
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/lsn"
+	"github.com/lib/pq/oid"
+)
+
+// This file contains the encoding of the logical replication messages of the
+// pgoutput plugin, protocol version 1. See
+// https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html.
+
+// ProtoVersion is the version of the pgoutput protocol produced by this
+// package.
+const ProtoVersion = 1
+
+// Relation describes a published table. A Relation message is sent before the
+// first change to a table, and whenever the table's columns change.
+type Relation struct {
+	// ID is the OID of the table.
+	ID        oid.Oid
+	Namespace string
+	Name      string
+	Columns   []RelationColumn
+}
+
+// RelationColumn describes a column of a published table.
+type RelationColumn struct {
+	Name string
+	// Key is set if the column is part of the primary key of the table, which
+	// is the replica identity of the table.
+	Key     bool
+	TypeOID oid.Oid
+	TypeMod int32
+}
+
+// replicaIdentityDefault indicates that the replica identity of a relation is
+// its primary key.
+const replicaIdentityDefault = 'd'
+
+// Encode returns the Relation message for the table.
+func (r *Relation) Encode() []byte {
+	var w writer
+	w.byte('R')
+	w.uint32(uint32(r.ID))
+	w.string(r.Namespace)
+	w.string(r.Name)
+	w.byte(replicaIdentityDefault)
+	w.uint16(uint16(len(r.Columns)))
+	for _, c := range r.Columns {
+		var flags byte
+		if c.Key {
+			flags = 1
+		}
+		w.byte(flags)
+		w.string(c.Name)
+		w.uint32(uint32(c.TypeOID))
+		w.uint32(uint32(c.TypeMod))
+	}
+	return w.buf
+}
+
+// DecodeRelation decodes a Relation message.
+func DecodeRelation(b []byte) (Relation, error) {
+	r := reader{buf: b}
+	if err := r.expectType('R'); err != nil {
+		return Relation{}, err
+	}
+	rel := Relation{
+		ID:        oid.Oid(r.uint32()),
+		Namespace: r.string(),
+		Name:      r.string(),
+	}
+	_ = r.byte()
+	rel.Columns = make([]RelationColumn, r.uint16())
+	for i := range rel.Columns {
+		c := &rel.Columns[i]
+		c.Key = r.byte()&1 != 0
+		c.Name = r.string()
+		c.TypeOID = oid.Oid(r.uint32())
+		c.TypeMod = int32(r.uint32())
+	}
+	return rel, r.done()
+}
+
+// Equal returns true if the relations are identical.
+func (r *Relation) Equal(o *Relation) bool {
+	if r.ID != o.ID || r.Namespace != o.Namespace || r.Name != o.Name ||
+		len(r.Columns) != len(o.Columns) {
+		return false
+	}
+	for i := range r.Columns {
+		if r.Columns[i] != o.Columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeBegin returns the Begin message of a transaction.
+func EncodeBegin(finalLSN lsn.LSN, commitTime time.Time, xid uint32) []byte {
+	var w writer
+	w.byte('B')
+	w.uint64(uint64(finalLSN))
+	w.time(commitTime)
+	w.uint32(xid)
+	return w.buf
+}
+
+// EncodeCommit returns the Commit message of a transaction.
+func EncodeCommit(commitLSN, endLSN lsn.LSN, commitTime time.Time) []byte {
+	var w writer
+	w.byte('C')
+	w.byte(0 /* flags */)
+	w.uint64(uint64(commitLSN))
+	w.uint64(uint64(endLSN))
+	w.time(commitTime)
+	return w.buf
+}
+
+// EncodeInsert returns the Insert message for a new row. The datums must match
+// the columns of the relation.
+func EncodeInsert(relID oid.Oid, row tree.Datums) []byte {
+	var w writer
+	w.byte('I')
+	w.uint32(uint32(relID))
+	w.byte('N')
+	w.tuple(row)
+	return w.buf
+}
+
+// EncodeUpdate returns the Update message for the new version of a row. The
+// old version of the row is not included: since the replica identity is the
+// primary key, subscribers find the row using the key columns of the new
+// version.
+func EncodeUpdate(relID oid.Oid, row tree.Datums) []byte {
+	var w writer
+	w.byte('U')
+	w.uint32(uint32(relID))
+	w.byte('N')
+	w.tuple(row)
+	return w.buf
+}
+
+// EncodeDelete returns the Delete message for a deleted row. The datums must
+// match the columns of the relation; only the datums of the key columns are
+// required, the others should be NULL.
+func EncodeDelete(relID oid.Oid, key tree.Datums) []byte {
+	var w writer
+	w.byte('D')
+	w.uint32(uint32(relID))
+	w.byte('K')
+	w.tuple(key)
+	return w.buf
+}
+
+// pgEpoch is the epoch of the timestamps of the replication protocol.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// writer encodes the fields of replication messages.
+type writer struct {
+	buf     []byte
+	scratch [8]byte
+}
+
+func (w *writer) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *writer) uint16(v uint16) {
+	binary.BigEndian.PutUint16(w.scratch[:2], v)
+	w.buf = append(w.buf, w.scratch[:2]...)
+}
+
+func (w *writer) uint32(v uint32) {
+	binary.BigEndian.PutUint32(w.scratch[:4], v)
+	w.buf = append(w.buf, w.scratch[:4]...)
+}
+
+func (w *writer) uint64(v uint64) {
+	binary.BigEndian.PutUint64(w.scratch[:], v)
+	w.buf = append(w.buf, w.scratch[:]...)
+}
+
+// time writes a timestamp as the number of microseconds since pgEpoch.
+func (w *writer) time(t time.Time) {
+	w.uint64(uint64(t.Sub(pgEpoch).Microseconds()))
+}
+
+// string writes a null-terminated string.
+func (w *writer) string(s string) {
+	w.buf = append(w.buf, s...)
+	w.buf = append(w.buf, 0)
+}
+
+// tuple writes the TupleData of a row, using the text format for all the
+// values.
+func (w *writer) tuple(row tree.Datums) {
+	w.uint16(uint16(len(row)))
+	for _, d := range row {
+		if d == tree.DNull {
+			w.byte('n')
+			continue
+		}
+		s := tree.AsStringWithFlags(d, tree.FmtPgwireText)
+		w.byte('t')
+		w.uint32(uint32(len(s)))
+		w.buf = append(w.buf, s...)
+	}
+}
+
+// reader decodes the fields of replication messages. Once an error occurs,
+// all the fields are decoded as zero values and the error is returned by
+// done.
+type reader struct {
+	buf []byte
+	err error
+}
+
+var errTruncated = pgerror.New(pgcode.ProtocolViolation, "replication message is truncated")
+
+func (r *reader) expectType(typ byte) error {
+	if t := r.byte(); r.err == nil && t != typ {
+		r.err = pgerror.Newf(pgcode.ProtocolViolation,
+			"unexpected replication message type %q, expected %q", t, typ)
+	}
+	return r.err
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.buf) < n {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) time() time.Time {
+	return pgEpoch.Add(time.Duration(int64(r.uint64())) * time.Microsecond)
+}
+
+func (r *reader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	for i, c := range r.buf {
+		if c == 0 {
+			s := string(r.buf[:i])
+			r.buf = r.buf[i+1:]
+			return s
+		}
+	}
+	r.err = errTruncated
+	return ""
+}
+
+// done returns the decoding error, if any, or an error if the message was not
+// entirely consumed.
+func (r *reader) done() error {
+	if r.err == nil && len(r.buf) > 0 {
+		r.err = pgerror.Newf(pgcode.ProtocolViolation,
+			"unexpected %d bytes at the end of replication message", len(r.buf))
+	}
+	return r.err
+}
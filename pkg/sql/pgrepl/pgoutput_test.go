@@ -0,0 +1,128 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/lsn"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelation(t *testing.T) {
+	rel := Relation{
+		ID:        104,
+		Namespace: "public",
+		Name:      "t",
+		Columns: []RelationColumn{
+			{Name: "k", Key: true, TypeOID: oid.T_int8, TypeMod: -1},
+			{Name: "v", TypeOID: oid.T_varchar, TypeMod: 14},
+		},
+	}
+	b := rel.Encode()
+	require.Equal(t, []byte{
+		'R', 0, 0, 0, 104, 'p', 'u', 'b', 'l', 'i', 'c', 0, 't', 0, 'd', 0, 2,
+		1, 'k', 0, 0, 0, 0, 20, 0xff, 0xff, 0xff, 0xff,
+		0, 'v', 0, 0, 0, 4, 19, 0, 0, 0, 14,
+	}, b)
+
+	res, err := DecodeRelation(b)
+	require.NoError(t, err)
+	require.Equal(t, rel, res)
+	require.True(t, rel.Equal(&res))
+	res.Columns[1].TypeMod = -1
+	require.False(t, rel.Equal(&res))
+
+	for i := 0; i < len(b); i++ {
+		_, err := DecodeRelation(b[:i])
+		require.Error(t, err)
+	}
+	_, err = DecodeRelation(append(b, 0))
+	require.Error(t, err)
+}
+
+func TestEncodeMessages(t *testing.T) {
+	ts := time.Date(2000, time.January, 1, 0, 0, 1, 0, time.UTC)
+	require.Equal(t, []byte{
+		'B', 0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0x0f, 0x42, 0x40, 0, 0, 0, 7,
+	}, EncodeBegin(lsn.LSN(0x1_0000_0002), ts, 7))
+	require.Equal(t, []byte{
+		'C', 0, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0x0f, 0x42, 0x40,
+	}, EncodeCommit(3, 4, ts))
+
+	row := tree.Datums{tree.NewDInt(1), tree.NewDString("a"), tree.DNull}
+	require.Equal(t, []byte{
+		'I', 0, 0, 0, 104, 'N', 0, 3,
+		't', 0, 0, 0, 1, '1', 't', 0, 0, 0, 1, 'a', 'n',
+	}, EncodeInsert(104, row))
+	require.Equal(t, []byte{
+		'U', 0, 0, 0, 104, 'N', 0, 3,
+		't', 0, 0, 0, 1, '1', 't', 0, 0, 0, 1, 'a', 'n',
+	}, EncodeUpdate(104, row))
+	require.Equal(t, []byte{
+		'D', 0, 0, 0, 104, 'K', 0, 3, 't', 0, 0, 0, 1, '1', 'n', 'n',
+	}, EncodeDelete(104, tree.Datums{tree.NewDInt(1), tree.DNull, tree.DNull}))
+}
+
+func TestStreamMessages(t *testing.T) {
+	ts := time.Date(2000, time.January, 1, 0, 0, 0, 1000, time.UTC)
+	require.Equal(t, []byte{
+		'w', 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1, 'x',
+	}, EncodeXLogData(1, 2, ts, []byte{'x'}))
+	require.Equal(t, []byte{
+		'k', 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1, 1,
+	}, EncodeKeepalive(2, ts, true /* replyRequested */))
+
+	status := []byte{
+		'r', 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 1, 0,
+	}
+	require.True(t, IsStandbyStatus(status))
+	res, err := DecodeStandbyStatus(status)
+	require.NoError(t, err)
+	require.Equal(t, StandbyStatus{Written: 3, Flushed: 2, Applied: 1, ClientTime: ts}, res)
+	_, err = DecodeStandbyStatus(status[:len(status)-1])
+	require.Error(t, err)
+	require.False(t, IsStandbyStatus([]byte{'h'}))
+}
+
+func TestChange(t *testing.T) {
+	c := Change{
+		Timestamp: hlc.Timestamp{WallTime: 1234, Logical: 5},
+		Relation: Relation{
+			ID:      104,
+			Name:    "t",
+			Columns: []RelationColumn{{Name: "k", Key: true, TypeOID: oid.T_int8, TypeMod: -1}},
+		},
+		Message: EncodeInsert(104, tree.Datums{tree.NewDInt(1)}),
+	}
+	res, err := DecodeChange(c.Encode())
+	require.NoError(t, err)
+	require.Equal(t, c, res)
+
+	resolved := hlc.Timestamp{WallTime: 1234, Logical: 5}
+	ts, err := DecodeResolved(EncodeResolved(resolved))
+	require.NoError(t, err)
+	require.Equal(t, resolved, ts)
+}
+
+func TestLSNFromTimestamp(t *testing.T) {
+	require.Equal(t, lsn.LSN(10), LSNFromTimestamp(hlc.Timestamp{WallTime: 10}))
+	require.Equal(t, lsn.LSN(10), LSNFromTimestamp(hlc.Timestamp{WallTime: 10, Logical: 3}))
+	require.Equal(t, hlc.Timestamp{WallTime: 10, Logical: math.MaxInt32}, TimestampFromLSN(10))
+	require.Equal(t, lsn.LSN(9), ResolvedLSN(hlc.Timestamp{WallTime: 10}))
+	require.Equal(t, lsn.LSN(10), ResolvedLSN(hlc.Timestamp{WallTime: 10, Logical: math.MaxInt32}))
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/lsn"
+)
+
+// LSNs are derived from the MVCC timestamps of the changes: the LSN of a
+// change is the wall time of its timestamp. All the changes with the same LSN
+// are streamed in a single transaction, so that a subscriber which has
+// confirmed an LSN has received all the changes up to the highest timestamp
+// which maps to it.
+
+// LSNFromTimestamp returns the LSN of the changes at the given timestamp.
+func LSNFromTimestamp(ts hlc.Timestamp) lsn.LSN {
+	return lsn.LSN(ts.WallTime)
+}
+
+// TimestampFromLSN returns the highest timestamp which maps to the given LSN.
+// Resuming a stream after an LSN means resuming after this timestamp.
+func TimestampFromLSN(l lsn.LSN) hlc.Timestamp {
+	return hlc.Timestamp{WallTime: int64(l) + 1}.Prev()
+}
+
+// ResolvedLSN returns the highest LSN such that all the changes with that LSN
+// are at or below the given resolved timestamp.
+func ResolvedLSN(resolved hlc.Timestamp) lsn.LSN {
+	l := LSNFromTimestamp(resolved)
+	if TimestampFromLSN(l) != resolved {
+		l--
+	}
+	return l
+}
+
+// EncodeXLogData returns an XLogData message, which wraps the logical
+// replication messages sent in the CopyBoth stream.
+func EncodeXLogData(start, end lsn.LSN, now time.Time, data []byte) []byte {
+	var w writer
+	w.byte('w')
+	w.uint64(uint64(start))
+	w.uint64(uint64(end))
+	w.time(now)
+	w.buf = append(w.buf, data...)
+	return w.buf
+}
+
+// EncodeKeepalive returns a primary keepalive message, which reports the
+// current end of the stream to the subscriber.
+func EncodeKeepalive(end lsn.LSN, now time.Time, replyRequested bool) []byte {
+	var w writer
+	w.byte('k')
+	w.uint64(uint64(end))
+	w.time(now)
+	if replyRequested {
+		w.byte(1)
+	} else {
+		w.byte(0)
+	}
+	return w.buf
+}
+
+// StandbyStatus is a status update sent by a subscriber.
+type StandbyStatus struct {
+	Written        lsn.LSN
+	Flushed        lsn.LSN
+	Applied        lsn.LSN
+	ClientTime     time.Time
+	ReplyRequested bool
+}
+
+// IsStandbyStatus returns true if the CopyData payload sent by a subscriber
+// is a standby status update.
+func IsStandbyStatus(b []byte) bool {
+	return len(b) > 0 && b[0] == 'r'
+}
+
+// DecodeStandbyStatus decodes a standby status update.
+func DecodeStandbyStatus(b []byte) (StandbyStatus, error) {
+	r := reader{buf: b}
+	if err := r.expectType('r'); err != nil {
+		return StandbyStatus{}, err
+	}
+	s := StandbyStatus{
+		Written:        lsn.LSN(r.uint64()),
+		Flushed:        lsn.LSN(r.uint64()),
+		Applied:        lsn.LSN(r.uint64()),
+		ClientTime:     r.time(),
+		ReplyRequested: r.byte() != 0,
+	}
+	return s, r.done()
+}
+
+// Change is a change to a row of a published table, as produced by the
+// changefeeds backing replication streams. The changes are buffered until
+// they are resolved, and then streamed in transactions grouped by LSN.
+type Change struct {
+	Timestamp hlc.Timestamp
+	// Relation describes the table at the timestamp of the change. The
+	// Namespace of the relation is not set.
+	Relation Relation
+	// Message is the Insert, Update or Delete message of the change.
+	Message []byte
+}
+
+const (
+	sizeOfChange         = int64(unsafe.Sizeof(Change{}))
+	sizeOfRelationColumn = int64(unsafe.Sizeof(RelationColumn{}))
+)
+
+// Size returns the approximate number of bytes of memory used by the change.
+func (c *Change) Size() int64 {
+	size := sizeOfChange + int64(cap(c.Message)) + int64(len(c.Relation.Namespace)+len(c.Relation.Name))
+	for i := range c.Relation.Columns {
+		size += sizeOfRelationColumn + int64(len(c.Relation.Columns[i].Name))
+	}
+	return size
+}
+
+// Encode encodes the change.
+func (c *Change) Encode() []byte {
+	var w writer
+	w.uint64(uint64(c.Timestamp.WallTime))
+	w.uint32(uint32(c.Timestamp.Logical))
+	rel := c.Relation.Encode()
+	w.uint32(uint32(len(rel)))
+	w.buf = append(w.buf, rel...)
+	w.buf = append(w.buf, c.Message...)
+	return w.buf
+}
+
+// DecodeChange decodes a change encoded with Encode.
+func DecodeChange(b []byte) (Change, error) {
+	r := reader{buf: b}
+	var c Change
+	c.Timestamp.WallTime = int64(r.uint64())
+	c.Timestamp.Logical = int32(r.uint32())
+	rel := r.bytes(int(r.uint32()))
+	if r.err != nil {
+		return Change{}, r.err
+	}
+	var err error
+	if c.Relation, err = DecodeRelation(rel); err != nil {
+		return Change{}, err
+	}
+	c.Message = r.buf
+	return c, nil
+}
+
+// EncodeResolved encodes a resolved timestamp produced by the changefeeds
+// backing replication streams.
+func EncodeResolved(ts hlc.Timestamp) []byte {
+	var w writer
+	w.uint64(uint64(ts.WallTime))
+	w.uint32(uint32(ts.Logical))
+	return w.buf
+}
+
+// DecodeResolved decodes a resolved timestamp encoded with EncodeResolved.
+func DecodeResolved(b []byte) (hlc.Timestamp, error) {
+	r := reader{buf: b}
+	ts := hlc.Timestamp{WallTime: int64(r.uint64()), Logical: int32(r.uint32())}
+	return ts, r.done()
+}
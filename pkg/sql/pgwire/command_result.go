@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -59,6 +60,7 @@ type commandResult struct {
 	buffer struct {
 		notices            []pgnotice.Notice
 		paramStatusUpdates []paramStatusUpdate
+		notifications      []notify.Notification
 	}
 
 	err error
@@ -133,6 +135,12 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		}
 	}
 
+	for _, n := range r.buffer.notifications {
+		if err := r.conn.bufferNotification(n); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notification"))
+		}
+	}
+
 	for _, paramStatusUpdate := range r.buffer.paramStatusUpdates {
 		if err := r.conn.bufferParamStatus(
 			paramStatusUpdate.param,
@@ -252,6 +260,28 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 	r.buffer.notices = append(r.buffer.notices, notice)
 }
 
+// BufferNotification is part of the sql.NotificationResult interface.
+func (r *commandResult) BufferNotification(n notify.Notification) {
+	r.buffer.notifications = append(r.buffer.notifications, n)
+}
+
+// TruncateBufferedResults is part of the sql.RestrictedCommandResult
+// interface.
+func (r *commandResult) TruncateBufferedResults(ctx context.Context) bool {
+	r.assertNotReleased()
+	cl := (*clientConnLock)(&r.conn.writerState.fi)
+	if r.pos <= cl.ClientPos() {
+		// Some of the results have already been flushed to the client.
+		return false
+	}
+	cl.RTrim(ctx, r.pos)
+	r.rowsAffected = 0
+	// The notices are sent when the result is closed, and they are produced
+	// again if the statement is executed again.
+	r.buffer.notices = nil
+	return true
+}
+
 // SetColumns is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	r.assertNotReleased()
@@ -438,6 +468,14 @@ func (r *limitedCommandResult) AddRow(ctx context.Context, row tree.Datums) erro
 	return r.conn.maybeFlush(r.pos, r.bufferingDisabled)
 }
 
+// TruncateBufferedResults is part of the sql.RestrictedCommandResult
+// interface.
+func (r *limitedCommandResult) TruncateBufferedResults(context.Context) bool {
+	// The rows may have been delivered to the client when the portal was
+	// suspended.
+	return false
+}
+
 // SupportsAddBatch is part of the sql.RestrictedCommandResult interface.
 // TODO(yuzefovich): implement limiting behavior for AddBatch.
 func (r *limitedCommandResult) SupportsAddBatch() bool {
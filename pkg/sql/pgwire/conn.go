@@ -27,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -111,6 +113,21 @@ type conn struct {
 
 	// afterReadMsgTestingKnob is called after reading every message.
 	afterReadMsgTestingKnob func(context.Context) error
+
+	// replication is the replication stream started by a START_REPLICATION
+	// command, if any. It is only accessed by the network goroutine.
+	replication *replicationStream
+}
+
+// replicationStream is the state of the Copy-both subprotocol run by the
+// START_REPLICATION command. Unlike the Copy-in subprotocol, it doesn't block
+// the network goroutine; instead, the network goroutine forwards the copy
+// messages received from the client to the processor goroutine.
+type replicationStream struct {
+	// msgs is the channel on which the copy messages are forwarded.
+	msgs chan pgwirebase.CopyMessage
+	// done is closed by the processor goroutine once the stream ends.
+	done chan struct{}
 }
 
 // serveConn creates a conn that will serve the netConn. It returns once the
@@ -525,6 +542,12 @@ func (c *conn) serveImpl(
 				return false, isSimpleQuery, c.handleFlush(ctx)
 
 			case pgwirebase.ClientMsgCopyData, pgwirebase.ClientMsgCopyDone, pgwirebase.ClientMsgCopyFail:
+				if c.replication != nil {
+					forwarded, err := c.forwardReplicationMsg(ctx, typ)
+					if forwarded || err != nil {
+						return false, isSimpleQuery, err
+					}
+				}
 				// We're supposed to ignore these messages, per the protocol spec. This
 				// state will happen when an error occurs on the server-side during a copy
 				// operation: the server will send an error and a ready message back to
@@ -748,6 +771,14 @@ func (c *conn) bufferNotice(ctx context.Context, noticeErr pgnotice.Notice) erro
 	return writeErrFields(ctx, c.sv, noticeErr, &c.msgBuilder, &c.writerState.buf)
 }
 
+func (c *conn) bufferNotification(n notify.Notification) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	c.msgBuilder.putInt32(int32(n.PID))
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) sendInitialConnData(
 	ctx context.Context, sqlServer *sql.Server, onDefaultIntSizeChange func(newSize int32),
 ) (sql.ConnectionHandler, error) {
@@ -823,6 +854,17 @@ func (c *conn) handleSimpleQuery(
 	}
 
 	startParse := timeutil.Now()
+	if c.sessionArgs.LogicalReplication {
+		// Connections in replication mode accept the commands of the replication
+		// protocol in addition to regular SQL statements.
+		stmt, ok, err := pgrepl.ParseCommand(query)
+		if err != nil {
+			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+		}
+		if ok {
+			return c.handleReplicationCommand(ctx, query, stmt, timeReceived, startParse)
+		}
+	}
 	stmts, err := c.parser.ParseWithInt(query, unqualifiedIntSize)
 	if err != nil {
 		log.SqlExec.Errorf(ctx, "failed to parse simple query: %s", query)
@@ -881,6 +923,71 @@ func (c *conn) handleSimpleQuery(
 	return nil
 }
 
+// handleReplicationCommand queues a command of the replication protocol for
+// execution. The START_REPLICATION command is special: like COPY, it takes
+// over the connection, but the network goroutine is not blocked while it runs;
+// it keeps reading the copy messages sent by the client and forwards them to
+// the command.
+//
+// An error is returned iff the statement buffer has been closed. In that case,
+// the connection should be considered toast.
+func (c *conn) handleReplicationCommand(
+	ctx context.Context, query string, stmt tree.Statement, timeReceived, startParse time.Time,
+) error {
+	if sr, ok := stmt.(*tree.StartReplication); ok {
+		s := &replicationStream{
+			msgs: make(chan pgwirebase.CopyMessage, 16),
+			done: make(chan struct{}),
+		}
+		c.replication = s
+		return c.stmtBuf.Push(ctx, sql.StartReplication{
+			Stmt:       sr,
+			Conn:       c,
+			ClientMsgs: s.msgs,
+			StreamDone: s.done,
+		})
+	}
+	return c.stmtBuf.Push(
+		ctx,
+		sql.ExecStmt{
+			Statement:    parser.Statement{AST: stmt, SQL: query},
+			TimeReceived: timeReceived,
+			ParseStart:   startParse,
+			ParseEnd:     timeutil.Now(),
+			LastInBatch:  true,
+		})
+}
+
+// forwardReplicationMsg forwards the copy message that was just read to the
+// running replication stream. It returns false if the stream has already
+// ended, in which case the message should be ignored.
+func (c *conn) forwardReplicationMsg(
+	ctx context.Context, typ pgwirebase.ClientMessageType,
+) (bool, error) {
+	select {
+	case <-c.replication.done:
+		c.replication = nil
+		return false, nil
+	default:
+	}
+	// The read buffer is reused for the next message, so the payload needs to
+	// be copied.
+	msg := pgwirebase.CopyMessage{Typ: typ, Data: append([]byte(nil), c.readBuf.Msg...)}
+	select {
+	case c.replication.msgs <- msg:
+		if typ != pgwirebase.ClientMsgCopyData {
+			// The client ended the stream.
+			c.replication = nil
+		}
+		return true, nil
+	case <-c.replication.done:
+		c.replication = nil
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
 // An error is returned iff the statement buffer has been closed. In that case,
 // the connection should be considered toast.
 func (c *conn) handleParse(
@@ -1194,6 +1301,27 @@ func (c *conn) BeginCopyIn(
 	return c.msgBuilder.finishMsg(c.conn)
 }
 
+// BeginCopyBoth is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) BeginCopyBoth(ctx context.Context) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	c.msgBuilder.writeByte(byte(pgwirebase.FormatText))
+	c.msgBuilder.putInt16(0 /* number of columns */)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCopyData is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendCopyData(ctx context.Context, data []byte) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	c.msgBuilder.write(data)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCopyDone is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendCopyDone(ctx context.Context) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
 // SendCommandComplete is part of the pgwirebase.Conn interface.
 func (c *conn) SendCommandComplete(tag []byte) error {
 	c.bufferCommandComplete(tag)
@@ -1937,3 +1937,77 @@ func TestPGWireRejectsNewConnIfTooManyConns(t *testing.T) {
 		requireConnectionCount(t, 0)
 	})
 }
+
+// TestReplicationSlots checks that logical replication slots outlive the
+// sessions which create them, unless they are temporary.
+func TestReplicationSlots(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = "defaultdb"
+
+	openReplicationConn := func() *pgconn.PgConn {
+		cfg, err := pgconn.ParseConfig(pgURL.String())
+		require.NoError(t, err)
+		cfg.RuntimeParams["replication"] = "database"
+		conn, err := pgconn.ConnectConfig(ctx, cfg)
+		require.NoError(t, err)
+		return conn
+	}
+	exec := func(conn *pgconn.PgConn, query string) [][]string {
+		results, err := conn.Exec(ctx, query).ReadAll()
+		require.NoError(t, err)
+		var rows [][]string
+		for _, res := range results {
+			for _, row := range res.Rows {
+				var r []string
+				for _, val := range row {
+					r = append(r, string(val))
+				}
+				rows = append(rows, r)
+			}
+		}
+		return rows
+	}
+	execError := func(conn *pgconn.PgConn, query string, code pgcode.Code) {
+		_, err := conn.Exec(ctx, query).ReadAll()
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		require.Equal(t, code.String(), pgErr.Code)
+	}
+
+	conn := openReplicationConn()
+	rows := exec(conn, "CREATE_REPLICATION_SLOT s LOGICAL pgoutput")
+	require.Len(t, rows, 1)
+	consistentPoint := rows[0][1]
+	exec(conn, "CREATE_REPLICATION_SLOT t TEMPORARY LOGICAL pgoutput")
+	require.Equal(t,
+		[][]string{{"s", "f"}, {"t", "t"}},
+		exec(conn, "SELECT slot_name, temporary FROM pg_catalog.pg_replication_slots"),
+	)
+	require.NoError(t, conn.Close(ctx))
+
+	// The persistent slot outlives the session, and starts at its consistent
+	// point until its subscriber confirms a later position.
+	sqlDB.CheckQueryResults(t,
+		`SELECT slot_name, plugin, database, temporary, confirmed_flush_lsn
+   FROM pg_catalog.pg_replication_slots`,
+		[][]string{{"s", "pgoutput", "defaultdb", "false", consistentPoint}},
+	)
+
+	conn = openReplicationConn()
+	defer func() { require.NoError(t, conn.Close(ctx)) }()
+	execError(conn, "CREATE_REPLICATION_SLOT s LOGICAL pgoutput", pgcode.DuplicateObject)
+	exec(conn, "DROP_REPLICATION_SLOT s")
+	execError(conn, "DROP_REPLICATION_SLOT s", pgcode.UndefinedObject)
+	sqlDB.CheckQueryResults(t,
+		`SELECT count(*) FROM system.replication_slots`, [][]string{{"0"}},
+	)
+}
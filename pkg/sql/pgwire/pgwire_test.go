@@ -2037,3 +2037,87 @@ func TestFailPrepareFailsTxn(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	params := base.TestServerArgs{Insecure: true}
+	s, _, _ := serverutils.StartServer(t, params)
+
+	ctx := context.Background()
+	defer s.Stopper().Stop(ctx)
+
+	host, ports, _ := net.SplitHostPort(s.ServingSQLAddr())
+	port, _ := strconv.Atoi(ports)
+	connCfg, err := pgx.ParseConfig(
+		fmt.Sprintf("postgresql://%s@%s:%d/defaultdb?sslmode=disable", username.RootUser, host, port),
+	)
+	require.NoError(t, err)
+	connCfg.TLSConfig = nil
+	connCfg.Logger = pgxTestLogger{}
+
+	listener, err := pgx.ConnectConfig(ctx, connCfg)
+	require.NoError(t, err)
+	defer func() { _ = listener.Close(ctx) }()
+	notifier, err := pgx.ConnectConfig(ctx, connCfg)
+	require.NoError(t, err)
+	defer func() { _ = notifier.Close(ctx) }()
+
+	exec := func(conn *pgx.Conn, stmt string) {
+		t.Helper()
+		_, err := conn.Exec(ctx, stmt)
+		require.NoError(t, err)
+	}
+	// waitFor receives notifications until one is received with the given
+	// payload, and returns all the received notifications.
+	waitFor := func(payload string) (received []string) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+		defer cancel()
+		for {
+			n, err := listener.WaitForNotification(ctx)
+			require.NoError(t, err)
+			received = append(received, n.Channel+":"+n.Payload)
+			if n.Payload == payload {
+				return received
+			}
+		}
+	}
+
+	var notifierPID uint32
+	require.NoError(t, notifier.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&notifierPID))
+
+	exec(listener, "LISTEN foo")
+	// The LISTEN statements of a transaction which rolls back are discarded,
+	// as well as the ones rolled back to a savepoint.
+	exec(listener, "BEGIN; LISTEN bar; ROLLBACK")
+	exec(listener, "BEGIN; SAVEPOINT s; LISTEN baz; ROLLBACK TO SAVEPOINT s; COMMIT")
+
+	exec(notifier, "NOTIFY bar, 'not listened'")
+	exec(notifier, "SELECT pg_notify('baz', 'not listened')")
+	exec(notifier, "BEGIN; NOTIFY foo, 'rolled back'; ROLLBACK")
+	exec(notifier, "NOTIFY foo, 'hello'")
+	ctxTimeout, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+	defer cancel()
+	n, err := listener.WaitForNotification(ctxTimeout)
+	require.NoError(t, err)
+	require.Equal(t, notifierPID, n.PID)
+	require.Equal(t, "foo", n.Channel)
+	require.Equal(t, "hello", n.Payload)
+
+	// Notifications are delivered once the listening session is out of its
+	// transaction.
+	exec(listener, "BEGIN")
+	exec(notifier, "SELECT pg_notify('foo', 'in txn')")
+	exec(listener, "COMMIT")
+	require.Equal(t, []string{"foo:in txn"}, waitFor("in txn"))
+
+	exec(listener, "UNLISTEN *; LISTEN qux")
+	exec(notifier, "NOTIFY foo, 'unlistened'")
+	exec(notifier, "NOTIFY qux")
+	exec(notifier, "NOTIFY qux, 'done'")
+	received := waitFor("done")
+	require.NotContains(t, received, "foo:unlistened")
+	require.Contains(t, received, "qux:done")
+}
@@ -35,3 +35,33 @@ type Conn interface {
 	// payload.
 	SendCommandComplete(tag []byte) error
 }
+
+// ReplicationConn exposes some functionality of a pgwire network connection to
+// be used by the streaming replication protocol implemented in the sql
+// package.
+type ReplicationConn interface {
+	// BeginCopyBoth sends the server message initiating the Copy-both
+	// subprotocol, in which the server streams the changes and the client sends
+	// status updates.
+	BeginCopyBoth(ctx context.Context) error
+
+	// SendCopyData sends a CopyData message with the given payload, and flushes
+	// it to the network.
+	SendCopyData(ctx context.Context, data []byte) error
+
+	// SendCopyDone sends a CopyDone message, which ends the Copy-both
+	// subprotocol.
+	SendCopyDone(ctx context.Context) error
+
+	// SendCommandComplete sends a serverMsgCommandComplete with the given
+	// payload.
+	SendCommandComplete(tag []byte) error
+}
+
+// CopyMessage is a CopyData, CopyDone or CopyFail message sent by the client
+// during the Copy-both subprotocol.
+type CopyMessage struct {
+	Typ ClientMessageType
+	// Data is the payload of the message.
+	Data []byte
+}
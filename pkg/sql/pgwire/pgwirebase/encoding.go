@@ -37,7 +37,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/dustin/go-humanize"
 	"github.com/jackc/pgtype"
@@ -500,6 +502,33 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oidext.T_jsonpath:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(string(b))
+		case oid.T_tsquery:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSQuery(string(b))
+		case oid.T_tsvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSVector(string(b))
+		case oidext.T_pgvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDPGVector(string(b))
+		}
+		if typ.Family() == types.RangeFamily {
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDRangeFromString(evalCtx, string(b), typ)
+			return d, err
 		}
 		if typ.Family() == types.ArrayFamily {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -747,6 +776,19 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oidext.T_jsonpath:
+			if len(b) < 1 {
+				return nil, NewProtocolViolationErrorf("no data to decode")
+			}
+			if b[0] != 1 {
+				return nil, NewProtocolViolationErrorf("expected JSONPATH version 1")
+			}
+			// Skip over the version number.
+			b = b[1:]
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(string(b))
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -782,10 +824,31 @@ func DecodeDatum(
 			}
 			ba, err := bitarray.FromEncodingParts(words, lastBitsUsed)
 			return &tree.DBitArray{BitArray: ba}, err
+		case oid.T_tsquery:
+			q, err := tsearch.DecodeTSQuery(b)
+			if err != nil {
+				return nil, NewInvalidBinaryRepresentationErrorf("error decoding tsquery: %v", err)
+			}
+			return tree.NewDTSQuery(q), nil
+		case oid.T_tsvector:
+			v, err := tsearch.DecodeTSVector(b)
+			if err != nil {
+				return nil, NewInvalidBinaryRepresentationErrorf("error decoding tsvector: %v", err)
+			}
+			return tree.NewDTSVector(v), nil
+		case oidext.T_pgvector:
+			v, err := vector.Decode(b)
+			if err != nil {
+				return nil, NewInvalidBinaryRepresentationErrorf("error decoding vector: %v", err)
+			}
+			return tree.NewDPGVector(v), nil
 		default:
 			if typ.Family() == types.ArrayFamily {
 				return decodeBinaryArray(evalCtx, typ.ArrayContents(), b, code)
 			}
+			if typ.Family() == types.RangeFamily {
+				return decodeBinaryRange(evalCtx, typ, b)
+			}
 		}
 	default:
 		return nil, errors.AssertionFailedf(
@@ -988,6 +1051,44 @@ func decodeBinaryArray(
 	return arr, nil
 }
 
+// decodeBinaryRange decodes the binary form of a range, which is a flags byte
+// followed by the length-prefixed binary forms of its finite bounds.
+func decodeBinaryRange(evalCtx *eval.Context, t *types.T, b []byte) (tree.Datum, error) {
+	if len(b) < 1 {
+		return nil, NewInvalidBinaryRepresentationErrorf("range requires a flags byte")
+	}
+	flags := b[0]
+	r := bytes.NewBuffer(b[1:])
+	readBound := func() (tree.Datum, error) {
+		var vlen int32
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, NewInvalidBinaryRepresentationErrorf("error decoding range bound: %v", err)
+		}
+		if vlen < 0 || int(vlen) > r.Len() {
+			return nil, NewInvalidBinaryRepresentationErrorf("invalid range bound length %d", vlen)
+		}
+		return DecodeDatum(evalCtx, t.RangeContents(), FormatBinary, r.Next(int(vlen)))
+	}
+	lower, upper := tree.Datum(tree.DNull), tree.Datum(tree.DNull)
+	if flags&tree.RangeEmpty == 0 {
+		var err error
+		if flags&tree.RangeLowerInf == 0 {
+			if lower, err = readBound(); err != nil {
+				return nil, err
+			}
+		}
+		if flags&tree.RangeUpperInf == 0 {
+			if upper, err = readBound(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if r.Len() != 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("%d trailing bytes in range", r.Len())
+	}
+	return tree.NewDRangeFromFlags(t, flags, lower, upper)
+}
+
 const tupleHeaderSize, oidSize, elementSize = 4, 4, 4
 
 func decodeBinaryTuple(evalCtx *eval.Context, b []byte) (tree.Datum, error) {
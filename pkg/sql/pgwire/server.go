@@ -1035,6 +1035,22 @@ func parseClientProvidedSessionParameters(
 			}
 			args.RemoteAddr = &net.TCPAddr{IP: ip, Port: port}
 
+		case "replication":
+			// Only logical replication, which is requested with the special value
+			// "database", is supported. See:
+			// https://www.postgresql.org/docs/current/protocol-replication.html
+			switch strings.ToLower(value) {
+			case "database":
+				args.LogicalReplication = true
+			case "false", "off", "no", "0":
+			case "true", "on", "yes", "1":
+				return sql.SessionArgs{}, pgerror.New(pgcode.FeatureNotSupported,
+					"physical replication is not supported")
+			default:
+				return sql.SessionArgs{}, pgerror.Newf(pgcode.ProtocolViolation,
+					"invalid value for parameter \"replication\": %q", value)
+			}
+
 		case "options":
 			opts, err := parseOptions(value)
 			if err != nil {
@@ -37,7 +37,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -211,6 +213,18 @@ func writeTextDatumNotNull(
 	case *tree.DVoid:
 		b.putInt32(0)
 
+	case *tree.DJsonpath:
+		b.writeLengthPrefixedString(v.Jsonpath.String())
+
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DPGVector:
+		b.writeLengthPrefixedString(v.T.String())
+
 	case *tree.DBox2D:
 		s := v.Repr()
 		b.putInt32(int32(len(s)))
@@ -248,6 +262,11 @@ func writeTextDatumNotNull(
 		b.textFormatter.FormatNode(d)
 		b.writeFromFmtCtx(b.textFormatter)
 
+	case *tree.DRange:
+		// The bounds of TIMESTAMPTZ ranges are shown in the session time zone.
+		b.textFormatter.FormatNode(v.InLocation(sessionLoc))
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DOid:
 		b.writeLengthPrefixedDatum(v)
 
@@ -684,9 +703,50 @@ func writeBinaryDatumNotNull(
 		lengthToWrite := b.Len() - (initialLen + 4)
 		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
 
+	case *tree.DRange:
+		initialLen := b.Len()
+
+		// Reserve bytes for writing length later.
+		b.putInt32(int32(0))
+
+		// Put the flags, followed by the length-prefixed finite bounds.
+		b.writeByte(v.Flags())
+		if !v.Empty {
+			for _, bound := range [...]tree.Datum{v.Lower, v.Upper} {
+				if bound != tree.DNull {
+					b.writeBinaryDatum(ctx, bound, sessionLoc, t.RangeContents())
+				}
+			}
+		}
+
+		lengthToWrite := b.Len() - (initialLen + 4)
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
+
 	case *tree.DVoid:
 		b.putInt32(0)
 
+	case *tree.DJsonpath:
+		s := v.Jsonpath.String()
+		b.putInt32(int32(len(s) + 1))
+		// Postgres version number, as of writing, `1` is the only valid value.
+		b.writeByte(1)
+		b.writeString(s)
+
+	case *tree.DTSQuery:
+		enc := tsearch.EncodeTSQuery(nil, v.TSQuery)
+		b.putInt32(int32(len(enc)))
+		b.write(enc)
+
+	case *tree.DTSVector:
+		enc := tsearch.EncodeTSVector(nil, v.TSVector)
+		b.putInt32(int32(len(enc)))
+		b.write(enc)
+
+	case *tree.DPGVector:
+		enc := vector.Encode(nil, v.T)
+		b.putInt32(int32(len(enc)))
+		b.write(enc)
+
 	case *tree.DBox2D:
 		b.putInt32(32)
 		b.putInt64(int64(math.Float64bits(v.LoX)))
@@ -93,6 +93,22 @@ type extendedEvalContext struct {
 	// jobsCollection.
 	Jobs *jobsCollection
 
+	// ListenActions refers to listenActions in extraTxnState.
+	ListenActions *listenActions
+
+	// SentNotifications refers to sentNotifications in extraTxnState.
+	SentNotifications *sentNotifications
+
+	// AdvisoryLocks refers to advisoryLocks in the connExecutor.
+	AdvisoryLocks *advisoryLocks
+
+	// TemporaryReplicationSlots refers to temporaryReplicationSlots in the
+	// connExecutor.
+	TemporaryReplicationSlots *temporaryReplicationSlots
+
+	// procedureTxn refers to procedureTxn in the connExecutor.
+	procedureTxn *procedureTxnState
+
 	// SchemaChangeJobRecords refers to schemaChangeJobsCache in extraTxnState of
 	// in sql.connExecutor. sql.connExecutor.createJobs() enqueues jobs with these
 	// records when transaction is committed.
@@ -193,6 +209,8 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	deferredChecks deferredChecks
+
 	// autoCommit indicates whether the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations. Committing the txn
 	// might be beneficial because it may enable the 1PC optimization. Note that
@@ -350,7 +368,7 @@ func newInternalPlanner(
 	p.isInternalPlanner = true
 
 	p.semaCtx = tree.MakeSemaContext()
-	p.semaCtx.SearchPath = &sd.SearchPath
+	p.semaCtx.SearchPath = aggregateResolver{SearchPath: &sd.SearchPath, p: p}
 	p.semaCtx.TypeResolver = p
 	p.semaCtx.DateStyle = sd.GetDateStyle()
 	p.semaCtx.IntervalStyle = sd.GetIntervalStyle()
@@ -400,6 +418,7 @@ func newInternalPlanner(
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
 	p.createdSequences = emptyCreatedSequences{}
+	p.deferredChecks = emptyDeferredChecks{}
 
 	p.schemaResolver.descCollection = p.Descriptors()
 	p.schemaResolver.sessionDataStack = sds
@@ -0,0 +1,395 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package plpgsql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// Stmt is a PL/pgSQL statement.
+type Stmt interface {
+	// Line returns the line of the body on which the statement starts. The
+	// first line of the body is line 1.
+	Line() int
+	// Tag returns the name of the statement used in the context of errors,
+	// e.g. "RAISE".
+	Tag() string
+	format(p *printer)
+}
+
+type stmtBase struct {
+	line int
+}
+
+// Line implements the Stmt interface.
+func (s *stmtBase) Line() int { return s.line }
+
+// Expr is a SQL expression or statement embedded in a PL/pgSQL statement. It
+// is kept as a sequence of tokens of the body, so that the references to
+// variables can be replaced with placeholders every time it is evaluated.
+type Expr struct {
+	src    string
+	tokens []token
+	// columns contains the indexes of the tokens which name the target columns
+	// of a mutation. They are never replaced with variables.
+	columns util.FastIntSet
+	// tables contains the names of the tables referenced by the expression,
+	// whose columns may conflict with the variables of the function.
+	tables []string
+}
+
+// String returns the SQL text of the expression.
+func (e *Expr) String() string {
+	return e.sql(func(int, token) (string, int) { return "", 0 })
+}
+
+// sql returns the SQL text of the expression. The given function can replace
+// the text of a token, along with the n-1 tokens which follow it, by returning
+// a non-empty string and n.
+func (e *Expr) sql(replace func(idx int, tok token) (string, int)) string {
+	var b []byte
+	for i := 0; i < len(e.tokens); i++ {
+		tok := e.tokens[i]
+		if i > 0 && tok.pos != e.tokens[i-1].end {
+			b = append(b, ' ')
+		}
+		if r, n := replace(i, tok); r != "" {
+			b = append(b, r...)
+			i += n - 1
+		} else {
+			b = append(b, e.src[tok.pos:tok.end]...)
+		}
+	}
+	return string(b)
+}
+
+// Block is a block of statements, with the variables declared for them and
+// the handlers of the errors they raise:
+//
+//   [ <<label>> ]
+//   [ DECLARE declarations ]
+//   BEGIN
+//     statements
+//   [ EXCEPTION WHEN condition [ OR condition ... ] THEN statements ... ]
+//   END [ label ];
+//
+type Block struct {
+	stmtBase
+	Label    string
+	Decls    []Declaration
+	Body     []Stmt
+	Handlers []ExceptionHandler
+}
+
+// Tag implements the Stmt interface.
+func (*Block) Tag() string { return "statement block" }
+
+// Declaration declares a variable of a block:
+//
+//   name [ CONSTANT ] type [ NOT NULL ] [ { DEFAULT | := | = } expression ];
+//
+type Declaration struct {
+	Line int
+	Name string
+	// Type is the SQL syntax of the type of the variable. It is resolved when
+	// the block is executed.
+	Type     string
+	Constant bool
+	NotNull  bool
+	Default  *Expr
+}
+
+// ExceptionHandler handles the errors raised by the statements of a block
+// which match one of its conditions.
+type ExceptionHandler struct {
+	Line       int
+	Conditions []Condition
+	Body       []Stmt
+}
+
+// Condition is an error condition, given by its name or by its SQLSTATE code.
+type Condition struct {
+	// Name is the name of the condition, e.g. "division_by_zero". It is empty
+	// if the condition is given with SQLSTATE.
+	Name string
+	Code pgcode.Code
+}
+
+// Matches returns whether an error with the given code matches the
+// condition. The conditions of error classes, whose code ends with 000,
+// match all the errors of the class, and the special condition OTHERS matches
+// all the errors.
+func (c Condition) Matches(code pgcode.Code) bool {
+	if c.Name == othersCondition {
+		return true
+	}
+	s := c.Code.String()
+	if s[2:] == "000" {
+		return code.String()[:2] == s[:2]
+	}
+	return code == c.Code
+}
+
+// Assign assigns the value of an expression to a variable:
+//
+//   variable { := | = } expression;
+//
+type Assign struct {
+	stmtBase
+	Var   string
+	Value *Expr
+}
+
+// Tag implements the Stmt interface.
+func (*Assign) Tag() string { return "assignment" }
+
+// If executes the statements of the first branch whose condition is true:
+//
+//   IF condition THEN statements
+//   [ { ELSIF | ELSEIF } condition THEN statements ... ]
+//   [ ELSE statements ]
+//   END IF;
+//
+type If struct {
+	stmtBase
+	Cond   *Expr
+	Then   []Stmt
+	ElsIfs []ElsIf
+	Else   []Stmt
+}
+
+// ElsIf is an ELSIF branch of an IF statement.
+type ElsIf struct {
+	Line int
+	Cond *Expr
+	Then []Stmt
+}
+
+// Tag implements the Stmt interface.
+func (*If) Tag() string { return "IF" }
+
+// Loop executes its statements until it is exited:
+//
+//   [ <<label>> ] LOOP statements END LOOP [ label ];
+//
+type Loop struct {
+	stmtBase
+	Label string
+	Body  []Stmt
+}
+
+// Tag implements the Stmt interface.
+func (*Loop) Tag() string { return "LOOP" }
+
+// While executes its statements as long as its condition is true:
+//
+//   [ <<label>> ] WHILE condition LOOP statements END LOOP [ label ];
+//
+type While struct {
+	stmtBase
+	Label string
+	Cond  *Expr
+	Body  []Stmt
+}
+
+// Tag implements the Stmt interface.
+func (*While) Tag() string { return "WHILE" }
+
+// ForInt executes its statements for every integer of a range:
+//
+//   [ <<label>> ]
+//   FOR name IN [ REVERSE ] expression .. expression [ BY expression ] LOOP
+//     statements
+//   END LOOP [ label ];
+//
+// The loop variable is declared by the loop.
+type ForInt struct {
+	stmtBase
+	Label   string
+	Var     string
+	Reverse bool
+	Lower   *Expr
+	Upper   *Expr
+	Step    *Expr
+	Body    []Stmt
+}
+
+// Tag implements the Stmt interface.
+func (*ForInt) Tag() string { return "FOR with integer loop variable" }
+
+// ForQuery executes its statements for every row returned by a query:
+//
+//   [ <<label>> ] FOR target [, ...] IN query LOOP statements END LOOP [ label ];
+//
+// The targets are variables declared by the enclosing blocks.
+type ForQuery struct {
+	stmtBase
+	Label   string
+	Targets []string
+	Query   *Expr
+	Body    []Stmt
+}
+
+// Tag implements the Stmt interface.
+func (*ForQuery) Tag() string { return "FOR over SELECT rows" }
+
+// Exit exits a loop or a labeled block, or starts the next iteration of a
+// loop:
+//
+//   { EXIT | CONTINUE } [ label ] [ WHEN condition ];
+//
+type Exit struct {
+	stmtBase
+	Continue bool
+	Label    string
+	Cond     *Expr
+}
+
+// Tag implements the Stmt interface.
+func (s *Exit) Tag() string {
+	if s.Continue {
+		return "CONTINUE"
+	}
+	return "EXIT"
+}
+
+// Return returns from the function:
+//
+//   RETURN [ expression ];
+//
+type Return struct {
+	stmtBase
+	Value *Expr
+}
+
+// Tag implements the Stmt interface.
+func (*Return) Tag() string { return "RETURN" }
+
+// Raise reports a message or raises an error:
+//
+//   RAISE [ level ] 'format' [, expression ...] [ USING option = expression [, ...] ];
+//   RAISE [ level ] { condition_name | SQLSTATE 'code' } [ USING ... ];
+//   RAISE [ level ] USING option = expression [, ...];
+//   RAISE;
+//
+// The last form raises again the error handled by the enclosing exception
+// handler.
+type Raise struct {
+	stmtBase
+	// Level is one of debug, log, info, notice, warning and exception.
+	Level     string
+	Condition *Condition
+	Format    string
+	Params    []*Expr
+	Options   []RaiseOption
+	// Rethrow is set for a RAISE statement without arguments.
+	Rethrow bool
+}
+
+// RaiseOption is an option of a RAISE statement. Its name is one of message,
+// detail, hint and errcode.
+type RaiseOption struct {
+	Name  string
+	Value *Expr
+}
+
+// Tag implements the Stmt interface.
+func (*Raise) Tag() string { return "RAISE" }
+
+// Assert raises an error if its condition is not true:
+//
+//   ASSERT condition [, message ];
+//
+type Assert struct {
+	stmtBase
+	Cond    *Expr
+	Message *Expr
+}
+
+// Tag implements the Stmt interface.
+func (*Assert) Tag() string { return "ASSERT" }
+
+// Perform evaluates a query and discards its results:
+//
+//   PERFORM query;
+//
+// The query is the text of a SELECT statement without the SELECT keyword.
+type Perform struct {
+	stmtBase
+	Query *Expr
+}
+
+// Tag implements the Stmt interface.
+func (*Perform) Tag() string { return "PERFORM" }
+
+// ExecSQL executes a SQL statement. The first row returned by the statement
+// can be stored in variables:
+//
+//   statement [ INTO [ STRICT ] target [, ...] ];
+//
+type ExecSQL struct {
+	stmtBase
+	// Query is the statement without its INTO clause.
+	Query *Expr
+	// AST is the parsed statement, in which variables have not been replaced.
+	AST    tree.Statement
+	Into   []string
+	Strict bool
+	// Result is set for the last statement of a SQL function, if it returns
+	// rows: the first column of its first row is the result of the function.
+	Result bool
+}
+
+// Tag implements the Stmt interface.
+func (*ExecSQL) Tag() string { return "SQL statement" }
+
+// DynamicExecute executes a SQL statement given by the value of an
+// expression:
+//
+//   EXECUTE expression [ INTO [ STRICT ] target [, ...] ] [ USING expression [, ...] ];
+//
+// The values of the USING expressions are the values of the placeholders of
+// the statement.
+type DynamicExecute struct {
+	stmtBase
+	Query  *Expr
+	Into   []string
+	Strict bool
+	Using  []*Expr
+}
+
+// Tag implements the Stmt interface.
+func (*DynamicExecute) Tag() string { return "EXECUTE" }
+
+// GetDiagnostics retrieves the status of the previous SQL statement:
+//
+//   GET [ CURRENT ] DIAGNOSTICS variable { = | := } ROW_COUNT [, ...];
+//
+type GetDiagnostics struct {
+	stmtBase
+	Vars []string
+}
+
+// Tag implements the Stmt interface.
+func (*GetDiagnostics) Tag() string { return "GET DIAGNOSTICS" }
+
+// Null does nothing:
+//
+//   NULL;
+//
+type Null struct {
+	stmtBase
+}
+
+// Tag implements the Stmt interface.
+func (*Null) Tag() string { return "NULL" }
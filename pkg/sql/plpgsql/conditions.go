@@ -0,0 +1,148 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package plpgsql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// othersCondition is the name of the condition which matches all errors.
+const othersCondition = "others"
+
+// conditionCodes maps the names of the error conditions which can be used in
+// exception handlers and RAISE statements to their SQLSTATE codes. The other
+// errors can be referenced with SQLSTATE 'code'.
+var conditionCodes = map[string]string{
+	"no_data":                               "02000",
+	"feature_not_supported":                 "0A000",
+	"cardinality_violation":                 "21000",
+	"data_exception":                        "22000",
+	"string_data_right_truncation":          "22001",
+	"numeric_value_out_of_range":            "22003",
+	"null_value_not_allowed":                "22004",
+	"error_in_assignment":                   "22005",
+	"invalid_datetime_format":               "22007",
+	"datetime_field_overflow":               "22008",
+	"division_by_zero":                      "22012",
+	"invalid_regular_expression":            "2201B",
+	"character_not_in_repertoire":           "22021",
+	"invalid_parameter_value":               "22023",
+	"invalid_escape_sequence":               "22025",
+	"string_data_length_mismatch":           "22026",
+	"array_subscript_error":                 "2202E",
+	"floating_point_exception":              "22P01",
+	"invalid_text_representation":           "22P02",
+	"invalid_binary_representation":         "22P03",
+	"integrity_constraint_violation":        "23000",
+	"restrict_violation":                    "23001",
+	"not_null_violation":                    "23502",
+	"foreign_key_violation":                 "23503",
+	"unique_violation":                      "23505",
+	"check_violation":                       "23514",
+	"exclusion_violation":                   "23P01",
+	"invalid_cursor_state":                  "24000",
+	"invalid_transaction_state":             "25000",
+	"active_sql_transaction":                "25001",
+	"read_only_sql_transaction":             "25006",
+	"in_failed_sql_transaction":             "25P02",
+	"invalid_sql_statement_name":            "26000",
+	"invalid_authorization_specification":   "28000",
+	"dependent_objects_still_exist":         "2BP01",
+	"invalid_transaction_termination":       "2D000",
+	"sql_routine_exception":                 "2F000",
+	"function_executed_no_return_statement": "2F005",
+	"invalid_cursor_name":                   "34000",
+	"savepoint_exception":                   "3B000",
+	"invalid_catalog_name":                  "3D000",
+	"invalid_schema_name":                   "3F000",
+	"transaction_rollback":                  "40000",
+	"serialization_failure":                 "40001",
+	"deadlock_detected":                     "40P01",
+	"syntax_error_or_access_rule_violation": "42000",
+	"insufficient_privilege":                "42501",
+	"syntax_error":                          "42601",
+	"invalid_name":                          "42602",
+	"name_too_long":                         "42622",
+	"duplicate_column":                      "42701",
+	"ambiguous_column":                      "42702",
+	"undefined_column":                      "42703",
+	"undefined_object":                      "42704",
+	"duplicate_object":                      "42710",
+	"duplicate_function":                    "42723",
+	"ambiguous_function":                    "42725",
+	"grouping_error":                        "42803",
+	"datatype_mismatch":                     "42804",
+	"wrong_object_type":                     "42809",
+	"cannot_coerce":                         "42846",
+	"undefined_function":                    "42883",
+	"undefined_table":                       "42P01",
+	"undefined_parameter":                   "42P02",
+	"duplicate_database":                    "42P04",
+	"duplicate_schema":                      "42P06",
+	"duplicate_table":                       "42P07",
+	"invalid_column_reference":              "42P10",
+	"invalid_function_definition":           "42P13",
+	"with_check_option_violation":           "44000",
+	"insufficient_resources":                "53000",
+	"disk_full":                             "53100",
+	"out_of_memory":                         "53200",
+	"program_limit_exceeded":                "54000",
+	"statement_too_complex":                 "54001",
+	"object_not_in_prerequisite_state":      "55000",
+	"object_in_use":                         "55006",
+	"lock_not_available":                    "55P03",
+	"operator_intervention":                 "57000",
+	"query_canceled":                        "57014",
+	"plpgsql_error":                         "P0000",
+	"raise_exception":                       "P0001",
+	"no_data_found":                         "P0002",
+	"too_many_rows":                         "P0003",
+	"assert_failure":                        "P0004",
+	"internal_error":                        "XX000",
+	"data_corrupted":                        "XX001",
+	"index_corrupted":                       "XX002",
+}
+
+// lookupCondition returns the condition with the given name.
+func lookupCondition(name string) (Condition, error) {
+	if name == othersCondition {
+		return Condition{Name: othersCondition}, nil
+	}
+	code, ok := conditionCodes[name]
+	if !ok {
+		return Condition{}, pgerror.Newf(pgcode.UndefinedObject,
+			"unrecognized exception condition %q", name)
+	}
+	return Condition{Name: name, Code: pgcode.MakeCode(code)}, nil
+}
+
+// makeSQLStateCondition returns the condition with the given SQLSTATE code.
+func makeSQLStateCondition(code string) (Condition, error) {
+	if !isValidSQLState(code) {
+		return Condition{}, pgerror.Newf(pgcode.Syntax, "invalid SQLSTATE code %q", code)
+	}
+	return Condition{Code: pgcode.MakeCode(code)}, nil
+}
+
+// isValidSQLState returns whether the given string is a valid SQLSTATE code,
+// which consists of five digits or upper case letters.
+func isValidSQLState(code string) bool {
+	if len(code) != 5 {
+		return false
+	}
+	for _, c := range code {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,1274 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package plpgsql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// Function is a PL/pgSQL function, or the code of a DO statement.
+type Function struct {
+	// Name identifies the function in the context of errors, e.g. "f(INT8)".
+	Name   string
+	Params []Param
+	// ReturnType is nil for the code of DO statements.
+	ReturnType *types.T
+	Body       *Block
+	// Trigger is set for trigger functions, which can only be executed with
+	// CallTrigger.
+	Trigger bool
+	// SQL is set for functions written in SQL, whose body is parsed with
+	// ParseSQL.
+	SQL bool
+}
+
+// Param is a parameter of a function. Unnamed parameters can only be
+// referenced with $n.
+type Param struct {
+	Name string
+	Type *types.T
+}
+
+// maxCallDepth is the maximum number of nested calls of PL/pgSQL functions.
+const maxCallDepth = 64
+
+type callDepthKey struct{}
+
+// opName is the name of the internal queries which evaluate the expressions
+// and the SQL statements of functions.
+const opName = "plpgsql"
+
+// TxnController lets a procedure end the transaction in which it executes.
+type TxnController interface {
+	// EndTxn commits or rolls back the current transaction, and starts a new
+	// one. It returns the evaluation context of the new transaction, in which
+	// the procedure continues.
+	EndTxn(ctx context.Context, commit bool) (*eval.Context, error)
+}
+
+// Call executes the function with the given arguments and returns its result.
+// The statements of the function are executed in the transaction of the
+// evaluation context, which must be a root transaction if the function has
+// exception handlers.
+func (f *Function) Call(evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+	if f.Trigger {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"trigger functions can only be called as triggers")
+	}
+	return f.call(evalCtx, args, nil /* txnCtl */, nil /* trigger */)
+}
+
+// CallProcedure executes the function as a procedure. If txnCtl is not nil,
+// the COMMIT and ROLLBACK statements of the procedure end its transaction
+// through txnCtl. Otherwise, they fail like in functions.
+func (f *Function) CallProcedure(
+	evalCtx *eval.Context, args tree.Datums, txnCtl TxnController,
+) error {
+	_, err := f.call(evalCtx, args, txnCtl, nil /* trigger */)
+	return err
+}
+
+// TriggerData describes the row-level trigger event for which a trigger
+// function is executed.
+type TriggerData struct {
+	// Name is the name of the trigger.
+	Name string
+	// Op is the event which fired the trigger: INSERT, UPDATE or DELETE.
+	Op          string
+	TableName   string
+	TableSchema string
+	// ColNames and ColTypes describe the columns of the table.
+	ColNames []string
+	ColTypes []*types.T
+	// Old and New contain the values of the row before and after it was
+	// modified. Old is nil for inserts, and New is nil for deletes.
+	Old tree.Datums
+	New tree.Datums
+}
+
+// CallTrigger executes a trigger function for a row modified by a mutation.
+// The function can reference the row with the NEW and OLD record variables,
+// and the trigger event with the TG_ variables. Only AFTER triggers are
+// supported, so the value returned by the function is ignored.
+func (f *Function) CallTrigger(evalCtx *eval.Context, data *TriggerData) error {
+	if !f.Trigger {
+		return errors.AssertionFailedf("%s is not a trigger function", f.Name)
+	}
+	_, err := f.call(evalCtx, nil /* args */, nil /* txnCtl */, data)
+	return err
+}
+
+func (f *Function) call(
+	evalCtx *eval.Context, args tree.Datums, txnCtl TxnController, trigger *TriggerData,
+) (tree.Datum, error) {
+	ctx := evalCtx.Ctx()
+	depth, _ := ctx.Value(callDepthKey{}).(int)
+	if depth >= maxCallDepth {
+		return nil, pgerror.New(pgcode.ProgramLimitExceeded, "stack depth limit exceeded")
+	}
+	e := executor{
+		fn:      f,
+		evalCtx: evalCtx,
+		ctx:     context.WithValue(ctx, callDepthKey{}, depth+1),
+		txnCtl:  txnCtl,
+	}
+	e.vars = append(e.vars, &variable{name: "found", typ: types.Bool, val: tree.DBoolFalse})
+	e.found = e.vars[0]
+	for i, p := range f.Params {
+		v := &variable{name: p.Name, typ: p.Type, val: args[i]}
+		e.params = append(e.params, v)
+		if p.Name != "" {
+			e.vars = append(e.vars, v)
+		}
+	}
+	if trigger != nil {
+		e.vars = append(e.vars, triggerVariables(trigger)...)
+	}
+	c, err := e.execStmt(f.Body)
+	if err != nil {
+		if se, ok := err.(*stmtError); ok {
+			return nil, se.cause
+		}
+		return nil, err
+	}
+	switch {
+	case c.kind == ctrlReturn:
+		return e.result, nil
+	case f.Trigger:
+		return nil, pgerror.New(pgcode.RoutineExceptionFunctionExecutedNoReturnStatement,
+			"control reached end of trigger procedure without RETURN")
+	case f.ReturnType == nil:
+		return tree.DNull, nil
+	case f.ReturnType.Family() == types.VoidFamily:
+		return tree.DVoidDatum, nil
+	}
+	return nil, pgerror.New(pgcode.RoutineExceptionFunctionExecutedNoReturnStatement,
+		"control reached end of function without RETURN")
+}
+
+type variable struct {
+	name     string
+	typ      *types.T
+	val      tree.Datum
+	constant bool
+	notNull  bool
+	// fields is set for record variables, whose fields are referenced as
+	// name.field. The value of a record variable itself cannot be used.
+	fields []*variable
+}
+
+// triggerVariables returns the special variables of a trigger function.
+func triggerVariables(data *TriggerData) []*variable {
+	str := func(name, val string) *variable {
+		return &variable{name: name, typ: types.String, val: tree.NewDString(val), constant: true}
+	}
+	record := func(name string, row tree.Datums) *variable {
+		v := &variable{name: name, typ: types.AnyTuple, val: tree.DNull, constant: true}
+		for i, colName := range data.ColNames {
+			val := tree.Datum(tree.DNull)
+			if row != nil {
+				val = row[i]
+			}
+			v.fields = append(v.fields,
+				&variable{name: colName, typ: data.ColTypes[i], val: val, constant: true})
+		}
+		return v
+	}
+	return []*variable{
+		str("tg_name", data.Name),
+		str("tg_when", "AFTER"),
+		str("tg_level", "ROW"),
+		str("tg_op", data.Op),
+		str("tg_table_name", data.TableName),
+		str("tg_table_schema", data.TableSchema),
+		record("new", data.New),
+		record("old", data.Old),
+	}
+}
+
+type controlKind int
+
+const (
+	// ctrlNext continues with the next statement.
+	ctrlNext controlKind = iota
+	ctrlExit
+	ctrlContinue
+	ctrlReturn
+)
+
+// control describes how the execution continues after a statement.
+type control struct {
+	kind controlKind
+	// label is the label of the block or loop targeted by EXIT or CONTINUE.
+	label string
+}
+
+// stmtError is an error raised by a statement, to which the context of the
+// statement has been added. The context is only added once, by the innermost
+// statement.
+type stmtError struct {
+	cause error
+}
+
+func (e *stmtError) Error() string { return e.cause.Error() }
+
+// executor interprets the statements of a function.
+type executor struct {
+	fn      *Function
+	evalCtx *eval.Context
+	ctx     context.Context
+	// txnCtl is set if COMMIT and ROLLBACK are allowed.
+	txnCtl TxnController
+	// subtxns is the number of enclosing blocks with exception handlers whose
+	// body is being executed. The transaction cannot end while the savepoint
+	// of such a block is active.
+	subtxns int
+	// vars contains the variables in scope, with the innermost last.
+	vars   []*variable
+	params []*variable
+	found  *variable
+	// rowCount is the number of rows processed by the last SQL statement.
+	rowCount int
+	// handled contains the errors handled by the enclosing exception
+	// handlers, with the innermost last.
+	handled []*stmtError
+	result  tree.Datum
+	// tableColumns caches the names of the columns of the tables referenced by
+	// the expressions of the function, keyed by the name of the table.
+	tableColumns map[string]map[string]struct{}
+	// complexExprs contains the expressions which cannot be evaluated in
+	// process, see evalSimple.
+	complexExprs map[*Expr]struct{}
+}
+
+func (e *executor) lookup(name string) *variable {
+	for i := len(e.vars) - 1; i >= 0; i-- {
+		if e.vars[i].name == name {
+			return e.vars[i]
+		}
+	}
+	return nil
+}
+
+func (e *executor) execStmts(stmts []Stmt) (control, error) {
+	for _, s := range stmts {
+		c, err := e.execStmt(s)
+		if err != nil || c.kind != ctrlNext {
+			return c, err
+		}
+	}
+	return control{}, nil
+}
+
+func (e *executor) execStmt(s Stmt) (control, error) {
+	c, err := e.execStmtImpl(s)
+	if err == nil {
+		return c, nil
+	}
+	if _, ok := err.(*stmtError); ok {
+		return c, err
+	}
+	if e.fn.SQL {
+		return c, &stmtError{cause: errors.WithDetailf(err,
+			"SQL function %s statement %d", e.fn.Name, s.Line())}
+	}
+	return c, &stmtError{cause: errors.WithDetailf(err,
+		"PL/pgSQL function %s line %d at %s", e.fn.Name, s.Line(), s.Tag())}
+}
+
+func (e *executor) execStmtImpl(s Stmt) (control, error) {
+	if err := e.ctx.Err(); err != nil {
+		return control{}, err
+	}
+	switch s := s.(type) {
+	case *Block:
+		return e.execBlock(s)
+	case *Assign:
+		return control{}, e.execAssign(s)
+	case *If:
+		return e.execIf(s)
+	case *Loop:
+		return e.execLoop(s.Label, nil /* cond */, s.Body)
+	case *While:
+		return e.execLoop(s.Label, s.Cond, s.Body)
+	case *ForInt:
+		return e.execForInt(s)
+	case *ForQuery:
+		return e.execForQuery(s)
+	case *Exit:
+		return e.execExit(s)
+	case *Return:
+		return e.execReturn(s)
+	case *Raise:
+		return control{}, e.execRaise(s)
+	case *Assert:
+		return control{}, e.execAssert(s)
+	case *Perform:
+		sql, args, err := e.bind(s.Query)
+		if err != nil {
+			return control{}, err
+		}
+		rows, err := e.query("SELECT "+sql, args...)
+		if err != nil {
+			return control{}, err
+		}
+		e.setFound(len(rows) > 0)
+		return control{}, nil
+	case *ExecSQL:
+		return e.execSQL(s)
+	case *DynamicExecute:
+		return control{}, e.execDynamic(s)
+	case *GetDiagnostics:
+		for _, name := range s.Vars {
+			if err := e.assign(name, tree.NewDInt(tree.DInt(e.rowCount))); err != nil {
+				return control{}, err
+			}
+		}
+		return control{}, nil
+	case *Null:
+		return control{}, nil
+	}
+	return control{}, errors.AssertionFailedf("unknown PL/pgSQL statement %T", s)
+}
+
+func (e *executor) execBlock(b *Block) (control, error) {
+	scope := len(e.vars)
+	defer func() { e.vars = e.vars[:scope] }()
+	for i := range b.Decls {
+		if err := e.declare(&b.Decls[i]); err != nil {
+			return control{}, err
+		}
+	}
+	if len(b.Handlers) == 0 {
+		c, err := e.execStmts(b.Body)
+		return exitBlock(b.Label, c), err
+	}
+
+	// The changes made by the statements of a block with exception handlers
+	// are rolled back if an error is handled.
+	txn := e.evalCtx.Txn
+	if txn == nil || txn.Type() != kv.RootTxn {
+		return control{}, errors.AssertionFailedf("exception handlers require a root transaction")
+	}
+	sp, err := txn.CreateSavepoint(e.ctx)
+	if err != nil {
+		return control{}, err
+	}
+	e.subtxns++
+	c, err := e.execStmts(b.Body)
+	e.subtxns--
+	if err == nil {
+		return exitBlock(b.Label, c), txn.ReleaseSavepoint(e.ctx, sp)
+	}
+	se, ok := err.(*stmtError)
+	if !ok || !isCatchable(se.cause) {
+		return c, err
+	}
+	code := pgerror.GetPGCode(se.cause)
+	for i := range b.Handlers {
+		h := &b.Handlers[i]
+		matches := false
+		for _, cond := range h.Conditions {
+			matches = matches || cond.Matches(code)
+		}
+		if !matches {
+			continue
+		}
+		if err := txn.RollbackToSavepoint(e.ctx, sp); err != nil {
+			return control{}, errors.CombineErrors(se, err)
+		}
+		e.vars = append(e.vars,
+			&variable{name: "sqlstate", typ: types.String, val: tree.NewDString(code.String())},
+			&variable{name: "sqlerrm", typ: types.String, val: tree.NewDString(pgerror.Flatten(se.cause).Message)},
+		)
+		e.handled = append(e.handled, se)
+		c, err := e.execStmts(h.Body)
+		e.handled = e.handled[:len(e.handled)-1]
+		return exitBlock(b.Label, c), err
+	}
+	return c, err
+}
+
+// isCatchable returns whether an error can be handled by an exception
+// handler. The errors which abort the transaction and the cancellation of the
+// query cannot be handled.
+func isCatchable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		pgerror.IsSQLRetryableError(err) {
+		return false
+	}
+	switch pgerror.GetPGCode(err) {
+	case pgcode.SerializationFailure, pgcode.QueryCanceled, pgcode.AdminShutdown,
+		pgcode.Internal:
+		return false
+	}
+	return true
+}
+
+// exitBlock returns how the execution continues after a block or a loop which
+// ended with the given control.
+func exitBlock(label string, c control) control {
+	if c.kind == ctrlExit && label != "" && c.label == label {
+		return control{}
+	}
+	return c
+}
+
+func (e *executor) declare(d *Declaration) error {
+	typ, err := e.evalCtx.Planner.GetTypeFromValidSQLSyntax(d.Type)
+	if err != nil {
+		return err
+	}
+	v := &variable{name: d.Name, typ: typ, val: tree.DNull, constant: d.Constant, notNull: d.NotNull}
+	if d.Default != nil {
+		if v.val, err = e.eval(d.Default, typ); err != nil {
+			return err
+		}
+	}
+	if v.notNull && v.val == tree.DNull {
+		return pgerror.Newf(pgcode.NullValueNotAllowed,
+			"variable %q declared NOT NULL cannot default to NULL", d.Name)
+	}
+	e.vars = append(e.vars, v)
+	return nil
+}
+
+// assign assigns a value to a variable. The value is cast to the type of the
+// variable.
+func (e *executor) assign(name string, val tree.Datum) error {
+	v := e.lookup(name)
+	if v == nil {
+		return pgerror.Newf(pgcode.Syntax, "%q is not a known variable", name)
+	}
+	if v.constant {
+		return pgerror.Newf(pgcode.ErrorInAssignment, "variable %q is declared CONSTANT", name)
+	}
+	if val == tree.DNull {
+		if v.notNull {
+			return pgerror.Newf(pgcode.NullValueNotAllowed,
+				"null value cannot be assigned to variable %q declared NOT NULL", name)
+		}
+	} else if !val.ResolvedType().Identical(v.typ) {
+		var err error
+		if val, err = eval.PerformCast(e.evalCtx, val, v.typ); err != nil {
+			return err
+		}
+	}
+	v.val = val
+	return nil
+}
+
+func (e *executor) setFound(found bool) {
+	e.found.val = tree.MakeDBool(tree.DBool(found))
+}
+
+func (e *executor) execAssign(s *Assign) error {
+	v := e.lookup(s.Var)
+	if v == nil {
+		return pgerror.Newf(pgcode.Syntax, "%q is not a known variable", s.Var)
+	}
+	val, err := e.eval(s.Value, v.typ)
+	if err != nil {
+		return err
+	}
+	return e.assign(s.Var, val)
+}
+
+func (e *executor) execIf(s *If) (control, error) {
+	ok, err := e.evalBool(s.Cond)
+	if err != nil {
+		return control{}, err
+	}
+	if ok {
+		return e.execStmts(s.Then)
+	}
+	for i := range s.ElsIfs {
+		if ok, err = e.evalBool(s.ElsIfs[i].Cond); err != nil {
+			return control{}, err
+		}
+		if ok {
+			return e.execStmts(s.ElsIfs[i].Then)
+		}
+	}
+	return e.execStmts(s.Else)
+}
+
+// loopControl returns whether a loop must stop after an iteration which ended
+// with the given control, and how the execution continues after the loop.
+func loopControl(label string, c control) (stop bool, _ control) {
+	switch c.kind {
+	case ctrlExit:
+		if c.label == "" || c.label == label {
+			return true, control{}
+		}
+		return true, c
+	case ctrlContinue:
+		if c.label == "" || c.label == label {
+			return false, control{}
+		}
+		return true, c
+	case ctrlReturn:
+		return true, c
+	}
+	return false, control{}
+}
+
+// execLoop executes a LOOP statement, or a WHILE statement if cond is not
+// nil.
+func (e *executor) execLoop(label string, cond *Expr, body []Stmt) (control, error) {
+	for {
+		if err := e.ctx.Err(); err != nil {
+			return control{}, err
+		}
+		if cond != nil {
+			ok, err := e.evalBool(cond)
+			if err != nil || !ok {
+				return control{}, err
+			}
+		}
+		c, err := e.execStmts(body)
+		if err != nil {
+			return c, err
+		}
+		if stop, c := loopControl(label, c); stop {
+			return c, nil
+		}
+	}
+}
+
+func (e *executor) execForInt(s *ForInt) (control, error) {
+	bound := func(x *Expr, name string) (int64, error) {
+		d, err := e.eval(x, types.Int)
+		if err != nil {
+			return 0, err
+		}
+		if d == tree.DNull {
+			return 0, pgerror.Newf(pgcode.NullValueNotAllowed,
+				"%s bound of FOR loop cannot be null", name)
+		}
+		return int64(tree.MustBeDInt(d)), nil
+	}
+	from, err := bound(s.Lower, "lower")
+	if err != nil {
+		return control{}, err
+	}
+	to, err := bound(s.Upper, "upper")
+	if err != nil {
+		return control{}, err
+	}
+	step := int64(1)
+	if s.Step != nil {
+		if step, err = bound(s.Step, "BY value of"); err != nil {
+			return control{}, err
+		}
+		if step <= 0 {
+			return control{}, pgerror.New(pgcode.InvalidParameterValue,
+				"BY value of FOR loop must be greater than zero")
+		}
+	}
+	if s.Reverse {
+		step = -step
+	}
+
+	v := &variable{name: s.Var, typ: types.Int}
+	e.vars = append(e.vars, v)
+	defer func() { e.vars = e.vars[:len(e.vars)-1] }()
+	found := false
+	for i := from; (step > 0 && i <= to) || (step < 0 && i >= to); i += step {
+		if err := e.ctx.Err(); err != nil {
+			return control{}, err
+		}
+		found = true
+		v.val = tree.NewDInt(tree.DInt(i))
+		c, err := e.execStmts(s.Body)
+		if err != nil {
+			return c, err
+		}
+		if stop, c := loopControl(s.Label, c); stop {
+			e.setFound(found)
+			return c, nil
+		}
+		// Stop before the loop variable overflows.
+		if (step > 0 && i > to-step) || (step < 0 && i < to-step) {
+			break
+		}
+	}
+	e.setFound(found)
+	return control{}, nil
+}
+
+func (e *executor) execForQuery(s *ForQuery) (control, error) {
+	sql, args, err := e.bind(s.Query)
+	if err != nil {
+		return control{}, err
+	}
+	rows, err := e.query(sql, args...)
+	if err != nil {
+		return control{}, err
+	}
+	for _, row := range rows {
+		if err := e.ctx.Err(); err != nil {
+			return control{}, err
+		}
+		if err := e.assignRow(s.Targets, row); err != nil {
+			return control{}, err
+		}
+		c, err := e.execStmts(s.Body)
+		if err != nil {
+			return c, err
+		}
+		if stop, c := loopControl(s.Label, c); stop {
+			e.setFound(true)
+			return c, nil
+		}
+	}
+	e.setFound(len(rows) > 0)
+	return control{}, nil
+}
+
+// assignRow assigns the values of a row to the given variables. The variables
+// without a value are set to NULL, and the values without a variable are
+// ignored.
+func (e *executor) assignRow(targets []string, row tree.Datums) error {
+	for i, name := range targets {
+		val := tree.Datum(tree.DNull)
+		if row != nil && i < len(row) {
+			val = row[i]
+		}
+		if err := e.assign(name, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *executor) execExit(s *Exit) (control, error) {
+	if s.Cond != nil {
+		ok, err := e.evalBool(s.Cond)
+		if err != nil || !ok {
+			return control{}, err
+		}
+	}
+	if s.Continue {
+		return control{kind: ctrlContinue, label: s.Label}, nil
+	}
+	return control{kind: ctrlExit, label: s.Label}, nil
+}
+
+func (e *executor) execReturn(s *Return) (control, error) {
+	if e.fn.Trigger {
+		// The value returned by the function of an AFTER trigger is ignored,
+		// so it is not evaluated.
+		e.result = tree.DNull
+		return control{kind: ctrlReturn}, nil
+	}
+	typ := e.fn.ReturnType
+	if typ == nil || typ.Family() == types.VoidFamily {
+		if s.Value != nil {
+			return control{}, pgerror.New(pgcode.DatatypeMismatch,
+				"RETURN cannot have a parameter in function returning void")
+		}
+		e.result = tree.DNull
+		if typ != nil {
+			e.result = tree.DVoidDatum
+		}
+		return control{kind: ctrlReturn}, nil
+	}
+	if s.Value == nil {
+		return control{}, pgerror.New(pgcode.Syntax, "missing expression at or near \"RETURN\"")
+	}
+	var err error
+	if e.result, err = e.eval(s.Value, typ); err != nil {
+		return control{}, err
+	}
+	return control{kind: ctrlReturn}, nil
+}
+
+// noticeSeverities maps the levels of RAISE statements to the severities of
+// the notices they send.
+var noticeSeverities = map[string]string{
+	"debug":   "DEBUG1",
+	"log":     "LOG",
+	"info":    "INFO",
+	"notice":  "NOTICE",
+	"warning": "WARNING",
+}
+
+func (e *executor) execRaise(s *Raise) error {
+	if s.Rethrow {
+		if len(e.handled) == 0 {
+			return pgerror.New(pgcode.StackedDiagnosticsAccessedWithoutActiveHandler,
+				"RAISE without parameters cannot be used outside an exception handler")
+		}
+		return e.handled[len(e.handled)-1]
+	}
+
+	code := pgcode.RaiseException
+	var msg string
+	if s.Condition != nil {
+		code = s.Condition.Code
+		msg = s.Condition.String()
+	}
+	if s.Format != "" {
+		params := make([]string, len(s.Params))
+		for i, param := range s.Params {
+			d, err := e.eval(param, types.String)
+			if err != nil {
+				return err
+			}
+			if d == tree.DNull {
+				params[i] = "<NULL>"
+			} else {
+				params[i] = string(tree.MustBeDString(d))
+			}
+		}
+		msg = formatRaiseMessage(s.Format, params)
+	}
+	var detail, hint string
+	for _, opt := range s.Options {
+		d, err := e.eval(opt.Value, types.String)
+		if err != nil {
+			return err
+		}
+		if d == tree.DNull {
+			return pgerror.Newf(pgcode.NullValueNotAllowed,
+				"RAISE statement option cannot be null")
+		}
+		val := string(tree.MustBeDString(d))
+		switch opt.Name {
+		case "message":
+			msg = val
+		case "detail":
+			detail = val
+		case "hint":
+			hint = val
+		case "errcode":
+			cond, err := lookupCondition(val)
+			if err != nil {
+				if cond, err = makeSQLStateCondition(val); err != nil {
+					return err
+				}
+			}
+			if cond.Name == othersCondition {
+				return pgerror.Newf(pgcode.UndefinedObject, "unrecognized exception condition %q", val)
+			}
+			code = cond.Code
+			if msg == "" {
+				msg = cond.String()
+			}
+		}
+	}
+	if msg == "" {
+		msg = code.String()
+	}
+
+	if severity, ok := noticeSeverities[s.Level]; ok {
+		var notice error = pgnotice.NewWithSeverityf(severity, "%s", msg)
+		if detail != "" {
+			notice = errors.WithDetail(notice, detail)
+		}
+		if hint != "" {
+			notice = errors.WithHint(notice, hint)
+		}
+		if e.evalCtx.ClientNoticeSender != nil {
+			e.evalCtx.ClientNoticeSender.BufferClientNotice(e.ctx, pgnotice.Notice(notice))
+		}
+		return nil
+	}
+	err := pgerror.New(code, msg)
+	if detail != "" {
+		err = errors.WithDetail(err, detail)
+	}
+	if hint != "" {
+		err = errors.WithHint(err, hint)
+	}
+	return err
+}
+
+// formatRaiseMessage replaces the % placeholders of the format string of a
+// RAISE statement with the given parameters.
+func formatRaiseMessage(format string, params []string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			b.WriteByte('%')
+			i++
+			continue
+		}
+		if len(params) > 0 {
+			b.WriteString(params[0])
+			params = params[1:]
+		}
+	}
+	return b.String()
+}
+
+func (e *executor) execAssert(s *Assert) error {
+	ok, err := e.evalBool(s.Cond)
+	if err != nil || ok {
+		return err
+	}
+	msg := "assertion failed"
+	if s.Message != nil {
+		d, err := e.eval(s.Message, types.String)
+		if err != nil {
+			return err
+		}
+		if d != tree.DNull {
+			msg = string(tree.MustBeDString(d))
+		}
+	}
+	return pgerror.New(pgcode.AssertFailure, msg)
+}
+
+func (e *executor) execSQL(s *ExecSQL) (control, error) {
+	if IsTransactionControl(s.AST) {
+		return control{}, e.endTxn(s.AST)
+	}
+	sql, args, err := e.bind(s.Query)
+	if err != nil {
+		return control{}, err
+	}
+	rows, err := e.query(sql, args...)
+	if err != nil {
+		return control{}, err
+	}
+	e.setFound(e.rowCount > 0)
+	if s.Result {
+		return control{kind: ctrlReturn}, e.setResult(rows)
+	}
+	if s.Into != nil {
+		return control{}, e.assignInto(s.Into, s.Strict, rows)
+	}
+	return control{}, nil
+}
+
+// setResult sets the result of a SQL function to the first column of the
+// first of the rows returned by its last statement, or to NULL if there is no
+// row.
+func (e *executor) setResult(rows []tree.Datums) error {
+	typ := e.fn.ReturnType
+	if typ.Family() == types.VoidFamily {
+		e.result = tree.DVoidDatum
+		return nil
+	}
+	e.result = tree.DNull
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil
+	}
+	d := rows[0][0]
+	if d != tree.DNull && !d.ResolvedType().Identical(typ) {
+		var err error
+		if d, err = eval.PerformAssignmentCast(e.evalCtx, d, typ); err != nil {
+			return err
+		}
+	}
+	e.result = d
+	return nil
+}
+
+// endTxn executes a transaction control statement of a procedure.
+func (e *executor) endTxn(stmt tree.Statement) error {
+	var commit bool
+	switch stmt.(type) {
+	case *tree.CommitTransaction:
+		commit = true
+	case *tree.RollbackTransaction:
+	default:
+		return pgerror.New(pgcode.FeatureNotSupported, "unsupported transaction command in PL/pgSQL")
+	}
+	if e.txnCtl == nil {
+		return pgerror.New(pgcode.InvalidTransactionTermination, "invalid transaction termination")
+	}
+	if e.subtxns > 0 {
+		if commit {
+			return pgerror.New(pgcode.InvalidTransactionTermination,
+				"cannot commit while a subtransaction is active")
+		}
+		return pgerror.New(pgcode.InvalidTransactionTermination,
+			"cannot roll back while a subtransaction is active")
+	}
+	evalCtx, err := e.txnCtl.EndTxn(e.ctx, commit)
+	if err != nil {
+		return err
+	}
+	// The procedure continues in the context of the new transaction.
+	depth, _ := e.ctx.Value(callDepthKey{}).(int)
+	e.evalCtx = evalCtx
+	e.ctx = context.WithValue(evalCtx.Ctx(), callDepthKey{}, depth)
+	return nil
+}
+
+// assignInto assigns the first of the rows returned by a statement to the
+// targets of its INTO clause. With STRICT, the statement must return exactly
+// one row.
+func (e *executor) assignInto(into []string, strict bool, rows []tree.Datums) error {
+	if strict {
+		switch {
+		case len(rows) == 0:
+			return pgerror.New(pgcode.NoDataFound, "query returned no rows")
+		case len(rows) > 1:
+			return pgerror.New(pgcode.TooManyRows, "query returned more than one row")
+		}
+	}
+	var row tree.Datums
+	if len(rows) > 0 {
+		row = rows[0]
+	}
+	return e.assignRow(into, row)
+}
+
+func (e *executor) execDynamic(s *DynamicExecute) error {
+	d, err := e.eval(s.Query, types.String)
+	if err != nil {
+		return err
+	}
+	if d == tree.DNull {
+		return pgerror.New(pgcode.NullValueNotAllowed, "query string argument of EXECUTE is null")
+	}
+	sql := string(tree.MustBeDString(d))
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return err
+	}
+	if IsTransactionControl(stmt.AST) {
+		return pgerror.New(pgcode.InvalidTransactionTermination, "invalid transaction termination")
+	}
+	args := make([]interface{}, len(s.Using))
+	for i, x := range s.Using {
+		if args[i], err = e.eval(x, nil /* typ */); err != nil {
+			return err
+		}
+	}
+	rows, err := e.query(sql, args...)
+	if err != nil {
+		return err
+	}
+	if s.Into != nil {
+		if stmt.AST.StatementReturnType() != tree.Rows {
+			return pgerror.New(pgcode.Syntax, "INTO used with a command that cannot return data")
+		}
+		return e.assignInto(s.Into, s.Strict, rows)
+	}
+	return nil
+}
+
+// query executes a SQL statement in the transaction of the function, and
+// returns the rows it returned. The number of rows returned or affected by
+// the statement is stored in rowCount.
+func (e *executor) query(sql string, args ...interface{}) (_ []tree.Datums, retErr error) {
+	it, err := e.evalCtx.Planner.QueryIteratorEx(e.ctx, opName, sessiondata.NoSessionDataOverride, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { retErr = errors.CombineErrors(retErr, it.Close()) }()
+	// The rows are buffered, so that the statements executed for each row do
+	// not run concurrently with the query in the same transaction.
+	var rows []tree.Datums
+	for {
+		ok, err := it.Next(e.ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		rows = append(rows, it.Cur())
+	}
+	e.rowCount = len(rows)
+	if rows == nil {
+		e.rowCount = it.RowsAffected()
+	}
+	return rows, nil
+}
+
+// eval evaluates a scalar expression. The result is cast to the given type,
+// unless it is nil. Simple expressions are evaluated in process, and the
+// others by a query.
+func (e *executor) eval(x *Expr, typ *types.T) (tree.Datum, error) {
+	sql, args, err := e.bind(x)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := e.complexExprs[x]; !ok {
+		if d, ok, err := e.evalSimple(sql, args, typ); ok || err != nil {
+			return d, err
+		}
+		if e.complexExprs == nil {
+			e.complexExprs = make(map[*Expr]struct{})
+		}
+		e.complexExprs[x] = struct{}{}
+	}
+	sql = "SELECT (" + sql + ")"
+	if typ != nil {
+		sql += "::" + formatType(typ)
+	}
+	row, err := e.evalCtx.Planner.QueryRowEx(e.ctx, opName, sessiondata.NoSessionDataOverride, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(row) != 1 {
+		return nil, errors.AssertionFailedf("expected one column, got %d", len(row))
+	}
+	return row[0], nil
+}
+
+// evalSimple evaluates an expression in process, without running a query,
+// if it is a simple expression: an expression which only refers to builtin
+// functions, and which doesn't contain subqueries, aggregates, window
+// functions or set-returning functions. It returns false if the expression is
+// not simple, or if it is not valid, in which case the query which evaluates
+// the expression reports the error.
+func (e *executor) evalSimple(
+	sql string, args []interface{}, typ *types.T,
+) (_ tree.Datum, ok bool, _ error) {
+	expr, err := parser.ParseExpr(sql)
+	if err != nil {
+		return nil, false, nil //nolint:returnerrcheck
+	}
+	// The placeholders are replaced with the values of the variables.
+	expr, err = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if p, ok := expr.(*tree.Placeholder); ok {
+			if int(p.Idx) >= len(args) {
+				return false, nil, errors.Newf("unknown placeholder %s", p)
+			}
+			return false, args[p.Idx].(tree.Datum), nil
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		return nil, false, nil //nolint:returnerrcheck
+	}
+	if typ != nil {
+		expr = &tree.CastExpr{Expr: expr, Type: typ, SyntaxMode: tree.CastShort}
+	}
+	sd := e.evalCtx.SessionData()
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.SearchPath = &sd.SearchPath
+	semaCtx.TypeResolver = e.evalCtx.Planner
+	semaCtx.DateStyle = sd.GetDateStyle()
+	semaCtx.IntervalStyle = sd.GetIntervalStyle()
+	semaCtx.Properties.Require("PL/pgSQL expressions", tree.RejectSpecial|tree.RejectSubqueries)
+	typedExpr, err := tree.TypeCheck(e.ctx, expr, &semaCtx, types.Any)
+	if err != nil {
+		return nil, false, nil //nolint:returnerrcheck
+	}
+	d, err := eval.Expr(e.evalCtx, typedExpr)
+	return d, true, err
+}
+
+func (e *executor) evalBool(x *Expr) (bool, error) {
+	d, err := e.eval(x, types.Bool)
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
+
+// bind returns the SQL text of an expression, in which the references to
+// variables are replaced with placeholders, and the values of the
+// placeholders.
+func (e *executor) bind(x *Expr) (string, []interface{}, error) {
+	var args []interface{}
+	var retErr error
+	placeholders := make(map[*variable]int)
+	sql := x.sql(func(idx int, tok token) (string, int) {
+		if retErr != nil {
+			return "", 0
+		}
+		v, ntoks, err := e.reference(x, idx)
+		if err != nil {
+			retErr = err
+		}
+		if v == nil {
+			return "", 0
+		}
+		n, ok := placeholders[v]
+		if !ok {
+			args = append(args, v.val)
+			n = len(args)
+			placeholders[v] = n
+		}
+		return fmt.Sprintf("($%d::%s)", n, formatType(v.typ)), ntoks
+	})
+	if retErr != nil {
+		return "", nil, retErr
+	}
+	return sql, args, nil
+}
+
+// variableConflictSetting is the name of the setting which determines how a
+// name which refers both to a variable and to a column is resolved.
+const variableConflictSetting = "plpgsql.variable_conflict"
+
+// reference returns the variable referenced by a token of an expression, and
+// the number of tokens which make up the reference, or nil if the token does
+// not refer to a variable. Words which are qualified names, function names,
+// aliases, table names or target columns are never variables, except for the
+// fields of record variables, and $n refers to the n-th parameter of the
+// function.
+//
+// A word which names both a variable and a column of one of the tables
+// referenced by the expression is ambiguous. Like in Postgres, this is an
+// error, unless the plpgsql.variable_conflict setting is use_variable or
+// use_column.
+func (e *executor) reference(x *Expr, idx int) (*variable, int, error) {
+	tok := x.tokens[idx]
+	if tok.id == lexbase.PLACEHOLDER {
+		n, err := strconv.Atoi(tok.str)
+		if err != nil || n < 1 || n > len(e.params) {
+			return nil, 0, nil //nolint:returnerrcheck
+		}
+		return e.params[n-1], 1, nil
+	}
+	if !isWord(tok) || x.columns.Contains(idx) {
+		return nil, 0, nil
+	}
+	if idx > 0 {
+		prev := x.tokens[idx-1]
+		if prev.id == '.' || prev.id == lexbase.TYPECAST {
+			return nil, 0, nil
+		}
+		if isWord(prev) {
+			switch prev.str {
+			case "as", "from", "join", "into", "update", "table", "only":
+				return nil, 0, nil
+			}
+		}
+	}
+	if idx+2 < len(x.tokens) && x.tokens[idx+1].id == '.' && isWord(x.tokens[idx+2]) {
+		if v := e.lookup(tok.str); v != nil && v.fields != nil {
+			for _, f := range v.fields {
+				if f.name == x.tokens[idx+2].str {
+					return f, 3, nil
+				}
+			}
+		}
+	}
+	if idx+1 < len(x.tokens) {
+		if next := x.tokens[idx+1]; next.id == '.' || next.id == '(' {
+			return nil, 0, nil
+		}
+	}
+	v := e.lookup(tok.str)
+	if v == nil || v.fields != nil {
+		return nil, 0, nil
+	}
+	isColumn, err := e.isColumn(x, tok.str)
+	if err != nil || !isColumn {
+		return v, 1, err
+	}
+	switch conflict := e.evalCtx.SessionData().CustomOptions[variableConflictSetting]; conflict {
+	case "", "error":
+		return nil, 0, errors.WithDetail(
+			pgerror.Newf(pgcode.AmbiguousColumn, "column reference %q is ambiguous", tok.str),
+			"It could refer to either a PL/pgSQL variable or a table column.",
+		)
+	case "use_variable":
+		return v, 1, nil
+	case "use_column":
+		return nil, 0, nil
+	default:
+		return nil, 0, errors.WithHint(
+			pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid value for parameter %q: %q", variableConflictSetting, conflict),
+			"Available values: error, use_variable, use_column.",
+		)
+	}
+}
+
+// isColumn returns whether a name refers to a column of one of the tables
+// referenced by an expression. The names which don't refer to tables, such as
+// the names of common table expressions, are ignored.
+func (e *executor) isColumn(x *Expr, name string) (bool, error) {
+	for _, table := range x.tables {
+		cols, ok := e.tableColumns[table]
+		if !ok {
+			var err error
+			if cols, err = e.resolveTableColumns(table); err != nil {
+				return false, err
+			}
+			if e.tableColumns == nil {
+				e.tableColumns = make(map[string]map[string]struct{})
+			}
+			e.tableColumns[table] = cols
+		}
+		if _, ok := cols[name]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveTableColumns returns the names of the columns of a table, or nil if
+// the table doesn't exist.
+func (e *executor) resolveTableColumns(table string) (map[string]struct{}, error) {
+	tn, err := parser.ParseQualifiedTableName(table)
+	if err != nil {
+		return nil, nil //nolint:returnerrcheck
+	}
+	id, err := e.evalCtx.Planner.ResolveTableName(e.ctx, tn)
+	if err != nil {
+		switch pgerror.GetPGCode(err) {
+		case pgcode.UndefinedTable, pgcode.InvalidSchemaName, pgcode.InvalidCatalogName:
+			return nil, nil
+		}
+		return nil, err
+	}
+	it, err := e.evalCtx.Planner.QueryIteratorEx(
+		e.ctx, opName, sessiondata.NoSessionDataOverride,
+		`SELECT attname::STRING FROM pg_catalog.pg_attribute WHERE attrelid = $1 AND attnum > 0 AND NOT attisdropped`,
+		tree.NewDOid(oid.Oid(id)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]struct{})
+	for {
+		ok, err := it.Next(e.ctx)
+		if err != nil {
+			return nil, errors.CombineErrors(err, it.Close())
+		}
+		if !ok {
+			break
+		}
+		cols[string(tree.MustBeDString(it.Cur()[0]))] = struct{}{}
+	}
+	return cols, it.Close()
+}
+
+// formatType returns the SQL syntax of a type, which also refers to
+// user-defined types.
+func formatType(typ *types.T) string {
+	f := tree.NewFmtCtx(tree.FmtSerializable)
+	f.FormatTypeReference(typ)
+	return f.CloseAndGetString()
+}
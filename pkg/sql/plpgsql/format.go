@@ -0,0 +1,265 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package plpgsql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+)
+
+// printer formats statements as PL/pgSQL code, with one statement per line
+// and nested statements indented.
+type printer struct {
+	b      strings.Builder
+	indent int
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	for i := 0; i < p.indent; i++ {
+		p.b.WriteString("  ")
+	}
+	fmt.Fprintf(&p.b, format, args...)
+	p.b.WriteByte('\n')
+}
+
+func (p *printer) stmts(stmts []Stmt) {
+	p.indent++
+	for _, s := range stmts {
+		s.format(p)
+	}
+	p.indent--
+}
+
+func (p *printer) label(label string) {
+	if label != "" {
+		p.printf("<<%s>>", label)
+	}
+}
+
+// withLabel returns the given text followed by the label, if any.
+func withLabel(text, label string) string {
+	if label != "" {
+		return text + " " + label
+	}
+	return text
+}
+
+// String returns the canonical PL/pgSQL code of the block.
+func (s *Block) String() string {
+	var p printer
+	s.format(&p)
+	return p.b.String()
+}
+
+// String returns the condition as it is written in PL/pgSQL code.
+func (c Condition) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return "SQLSTATE " + lexbase.EscapeSQLString(c.Code.String())
+}
+
+func (s *Block) format(p *printer) {
+	p.label(s.Label)
+	if len(s.Decls) > 0 {
+		p.printf("DECLARE")
+		p.indent++
+		for i := range s.Decls {
+			d := &s.Decls[i]
+			var b strings.Builder
+			b.WriteString(d.Name)
+			if d.Constant {
+				b.WriteString(" CONSTANT")
+			}
+			b.WriteString(" ")
+			b.WriteString(d.Type)
+			if d.NotNull {
+				b.WriteString(" NOT NULL")
+			}
+			if d.Default != nil {
+				b.WriteString(" := ")
+				b.WriteString(d.Default.String())
+			}
+			p.printf("%s;", b.String())
+		}
+		p.indent--
+	}
+	p.printf("BEGIN")
+	p.stmts(s.Body)
+	if len(s.Handlers) > 0 {
+		p.printf("EXCEPTION")
+		p.indent++
+		for i := range s.Handlers {
+			h := &s.Handlers[i]
+			conds := make([]string, len(h.Conditions))
+			for j, c := range h.Conditions {
+				conds[j] = c.String()
+			}
+			p.printf("WHEN %s THEN", strings.Join(conds, " OR "))
+			p.stmts(h.Body)
+		}
+		p.indent--
+	}
+	p.printf("%s;", withLabel("END", s.Label))
+}
+
+func (s *Assign) format(p *printer) {
+	p.printf("%s := %s;", s.Var, s.Value)
+}
+
+func (s *If) format(p *printer) {
+	p.printf("IF %s THEN", s.Cond)
+	p.stmts(s.Then)
+	for i := range s.ElsIfs {
+		p.printf("ELSIF %s THEN", s.ElsIfs[i].Cond)
+		p.stmts(s.ElsIfs[i].Then)
+	}
+	if s.Else != nil {
+		p.printf("ELSE")
+		p.stmts(s.Else)
+	}
+	p.printf("END IF;")
+}
+
+func (s *Loop) format(p *printer) {
+	p.label(s.Label)
+	p.printf("LOOP")
+	p.stmts(s.Body)
+	p.printf("%s;", withLabel("END LOOP", s.Label))
+}
+
+func (s *While) format(p *printer) {
+	p.label(s.Label)
+	p.printf("WHILE %s LOOP", s.Cond)
+	p.stmts(s.Body)
+	p.printf("%s;", withLabel("END LOOP", s.Label))
+}
+
+func (s *ForInt) format(p *printer) {
+	p.label(s.Label)
+	var b strings.Builder
+	fmt.Fprintf(&b, "FOR %s IN ", s.Var)
+	if s.Reverse {
+		b.WriteString("REVERSE ")
+	}
+	fmt.Fprintf(&b, "%s .. %s", s.Lower, s.Upper)
+	if s.Step != nil {
+		fmt.Fprintf(&b, " BY %s", s.Step)
+	}
+	p.printf("%s LOOP", b.String())
+	p.stmts(s.Body)
+	p.printf("%s;", withLabel("END LOOP", s.Label))
+}
+
+func (s *ForQuery) format(p *printer) {
+	p.label(s.Label)
+	p.printf("FOR %s IN %s LOOP", strings.Join(s.Targets, ", "), s.Query)
+	p.stmts(s.Body)
+	p.printf("%s;", withLabel("END LOOP", s.Label))
+}
+
+func (s *Exit) format(p *printer) {
+	text := withLabel(s.Tag(), s.Label)
+	if s.Cond != nil {
+		text += fmt.Sprintf(" WHEN %s", s.Cond)
+	}
+	p.printf("%s;", text)
+}
+
+func (s *Return) format(p *printer) {
+	if s.Value == nil {
+		p.printf("RETURN;")
+		return
+	}
+	p.printf("RETURN %s;", s.Value)
+}
+
+func (s *Raise) format(p *printer) {
+	if s.Rethrow {
+		p.printf("RAISE;")
+		return
+	}
+	var b strings.Builder
+	b.WriteString("RAISE ")
+	b.WriteString(strings.ToUpper(s.Level))
+	if s.Condition != nil {
+		fmt.Fprintf(&b, " %s", s.Condition)
+	} else if s.Format != "" || len(s.Options) == 0 {
+		fmt.Fprintf(&b, " %s", lexbase.EscapeSQLString(s.Format))
+		for _, param := range s.Params {
+			fmt.Fprintf(&b, ", %s", param)
+		}
+	}
+	for i, opt := range s.Options {
+		if i == 0 {
+			b.WriteString(" USING ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s = %s", strings.ToUpper(opt.Name), opt.Value)
+	}
+	p.printf("%s;", b.String())
+}
+
+func (s *Assert) format(p *printer) {
+	if s.Message == nil {
+		p.printf("ASSERT %s;", s.Cond)
+		return
+	}
+	p.printf("ASSERT %s, %s;", s.Cond, s.Message)
+}
+
+func (s *Perform) format(p *printer) {
+	p.printf("PERFORM %s;", s.Query)
+}
+
+// formatInto returns the INTO clause of a statement.
+func formatInto(into []string, strict bool) string {
+	if into == nil {
+		return ""
+	}
+	if strict {
+		return " INTO STRICT " + strings.Join(into, ", ")
+	}
+	return " INTO " + strings.Join(into, ", ")
+}
+
+func (s *ExecSQL) format(p *printer) {
+	p.printf("%s%s;", s.Query, formatInto(s.Into, s.Strict))
+}
+
+func (s *DynamicExecute) format(p *printer) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "EXECUTE %s%s", s.Query, formatInto(s.Into, s.Strict))
+	for i, e := range s.Using {
+		if i == 0 {
+			b.WriteString(" USING ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(e.String())
+	}
+	p.printf("%s;", b.String())
+}
+
+func (s *GetDiagnostics) format(p *printer) {
+	items := make([]string, len(s.Vars))
+	for i, v := range s.Vars {
+		items[i] = v + " = ROW_COUNT"
+	}
+	p.printf("GET DIAGNOSTICS %s;", strings.Join(items, ", "))
+}
+
+func (s *Null) format(p *printer) {
+	p.printf("NULL;")
+}
@@ -0,0 +1,1293 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package plpgsql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/scanner"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// token is a lexical token of the body of a function. The body is split into
+// tokens with the SQL scanner.
+type token struct {
+	id  int32
+	str string
+	// pos and end are the offsets of the token in the body.
+	pos int32
+	end int32
+}
+
+// symType implements scanner.ScanSymType.
+type symType struct {
+	id    int32
+	pos   int32
+	str   string
+	union interface{}
+}
+
+var _ scanner.ScanSymType = &symType{}
+
+func (s *symType) ID() int32                 { return s.id }
+func (s *symType) SetID(id int32)            { s.id = id }
+func (s *symType) Pos() int32                { return s.pos }
+func (s *symType) SetPos(pos int32)          { s.pos = pos }
+func (s *symType) Str() string               { return s.str }
+func (s *symType) SetStr(str string)         { s.str = str }
+func (s *symType) UnionVal() interface{}     { return s.union }
+func (s *symType) SetUnionVal(v interface{}) { s.union = v }
+
+// isWord returns whether the token is an identifier or a keyword. The string
+// of unquoted identifiers and keywords is lower case.
+func isWord(tok token) bool {
+	return tok.id == lexbase.IDENT || (tok.str != "" && lexbase.GetKeywordID(tok.str) == tok.id)
+}
+
+// Parse parses the body of a PL/pgSQL function. The name of the function is
+// only used in the context of errors. The SQL statements and expressions of
+// the body are checked for syntax errors, but the references to objects and
+// variables are only resolved when they are executed.
+func Parse(name, body string) (*Block, error) {
+	p := bodyParser{fnName: name, src: body}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	line := p.line(p.peek())
+	var label string
+	if p.peek().id == lexbase.LSHIFT {
+		var err error
+		if label, err = p.parseLabel(); err != nil {
+			return nil, err
+		}
+	}
+	if !p.isWord("declare") && !p.isWord("begin") {
+		return nil, p.syntaxError(p.peek(), "")
+	}
+	block, err := p.parseBlock(label, line, true /* top */)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.id != 0 {
+		return nil, p.syntaxError(tok, "")
+	}
+	return block, nil
+}
+
+// ParseSQL parses the body of a function written in SQL, which is a list of
+// SQL statements separated by semicolons. The statements are returned as a
+// block of ExecSQL statements, whose lines are the positions of the
+// statements in the body, starting at 1. The result of the function is the
+// first row returned by the last statement, which has Result set if it
+// returns rows.
+func ParseSQL(name, body string) (*Block, error) {
+	p := bodyParser{fnName: name, src: body}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	block := &Block{stmtBase: stmtBase{line: 1}}
+	for p.peek().id != 0 {
+		if p.accept(';') {
+			continue
+		}
+		first := p.peek()
+		e, err := p.parseExpr(func(tok token) bool { return tok.id == ';' || tok.id == 0 })
+		if err != nil {
+			return nil, err
+		}
+		markColumns(e)
+		stmt, err := parser.ParseOne(e.String())
+		if err != nil {
+			return nil, p.wrapError(err, first)
+		}
+		if IsTransactionControl(stmt.AST) {
+			return nil, p.errorf(first, pgcode.FeatureNotSupported,
+				"%s is not allowed in a SQL function", stmt.AST.StatementTag())
+		}
+		block.Body = append(block.Body, &ExecSQL{
+			stmtBase: stmtBase{line: len(block.Body) + 1},
+			Query:    e,
+			AST:      stmt.AST,
+		})
+	}
+	if n := len(block.Body); n > 0 {
+		last := block.Body[n-1].(*ExecSQL)
+		last.Result = stmtReturnsRows(last.AST)
+	}
+	return block, nil
+}
+
+// stmtReturnsRows returns whether a SQL statement returns rows.
+func stmtReturnsRows(stmt tree.Statement) bool {
+	if stmt.StatementReturnType() == tree.Rows {
+		return true
+	}
+	_, ok := stmt.(*tree.Explain)
+	return ok
+}
+
+// bodyParser is a recursive descent parser of PL/pgSQL code.
+type bodyParser struct {
+	fnName string
+	src    string
+	toks   []token
+	// pos is the index of the next token.
+	pos int
+	// labels contains the labels of the enclosing blocks and loops. Unlabeled
+	// loops have an empty label.
+	labels []scopeLabel
+	// handlers is the number of enclosing exception handlers.
+	handlers int
+}
+
+type scopeLabel struct {
+	name string
+	loop bool
+}
+
+func (p *bodyParser) tokenize() error {
+	var s scanner.Scanner
+	s.Init(p.src)
+	for {
+		var lval symType
+		s.Scan(&lval)
+		switch lval.id {
+		case 0:
+			return nil
+		case lexbase.ERROR:
+			return p.wrapError(
+				pgerror.WithCandidateCode(errors.Newf("%s", lval.str), pgcode.Syntax),
+				token{pos: lval.pos},
+			)
+		}
+		p.toks = append(p.toks, token{id: lval.id, str: lval.str, pos: lval.pos, end: int32(s.Pos())})
+	}
+}
+
+// line returns the line of the body on which the given token starts.
+func (p *bodyParser) line(tok token) int {
+	return strings.Count(p.src[:tok.pos], "\n") + 1
+}
+
+// wrapError adds the location of the given token to an error.
+func (p *bodyParser) wrapError(err error, tok token) error {
+	return errors.WithDetailf(err, "compilation of PL/pgSQL function %q near line %d",
+		p.fnName, p.line(tok))
+}
+
+// syntaxError returns a syntax error at the given token.
+func (p *bodyParser) syntaxError(tok token, format string, args ...interface{}) error {
+	text := "EOF"
+	if tok.id != 0 {
+		text = p.src[tok.pos:tok.end]
+	}
+	var err error
+	if format == "" {
+		err = pgerror.Newf(pgcode.Syntax, "at or near %q: syntax error", text)
+	} else {
+		err = pgerror.Newf(pgcode.Syntax, "at or near %q: syntax error: %s",
+			text, fmt.Sprintf(format, args...))
+	}
+	return p.wrapError(err, tok)
+}
+
+// errorf returns an error with the given code at the given token.
+func (p *bodyParser) errorf(tok token, code pgcode.Code, format string, args ...interface{}) error {
+	return p.wrapError(pgerror.Newf(code, format, args...), tok)
+}
+
+func (p *bodyParser) peekN(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return token{pos: int32(len(p.src)), end: int32(len(p.src))}
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *bodyParser) peek() token {
+	return p.peekN(0)
+}
+
+func (p *bodyParser) next() token {
+	tok := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return tok
+}
+
+// isWord returns whether the next token is the given keyword.
+func (p *bodyParser) isWord(w string) bool {
+	tok := p.peek()
+	return isWord(tok) && tok.str == w
+}
+
+func (p *bodyParser) acceptWord(w string) bool {
+	if p.isWord(w) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *bodyParser) expectWord(w string) error {
+	if !p.acceptWord(w) {
+		return p.syntaxError(p.peek(), "expected %s", strings.ToUpper(w))
+	}
+	return nil
+}
+
+func (p *bodyParser) accept(id int32) bool {
+	if p.peek().id == id {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *bodyParser) expect(id int32) error {
+	if !p.accept(id) {
+		return p.syntaxError(p.peek(), "")
+	}
+	return nil
+}
+
+// acceptAssign consumes an assignment operator, either := or =.
+func (p *bodyParser) acceptAssign() bool {
+	if p.peek().id == ':' && p.peekN(1).id == '=' {
+		p.pos += 2
+		return true
+	}
+	return p.accept('=')
+}
+
+// ident consumes an identifier.
+func (p *bodyParser) ident() (string, error) {
+	tok := p.peek()
+	if !isWord(tok) {
+		return "", p.syntaxError(tok, "expected identifier")
+	}
+	p.pos++
+	return tok.str, nil
+}
+
+// identList consumes a comma-separated list of identifiers.
+func (p *bodyParser) identList() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.accept(',') {
+			return names, nil
+		}
+	}
+}
+
+// parseLabel parses a label: <<name>>.
+func (p *bodyParser) parseLabel() (string, error) {
+	if err := p.expect(lexbase.LSHIFT); err != nil {
+		return "", err
+	}
+	name, err := p.ident()
+	if err != nil {
+		return "", err
+	}
+	if err := p.expect(lexbase.RSHIFT); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// parseEndLabel parses the optional label which follows the END of a block
+// or loop, and the semicolon that ends the statement. The semicolon of the
+// outermost block is optional.
+func (p *bodyParser) parseEndLabel(label string, optionalSemicolon bool) error {
+	if tok := p.peek(); isWord(tok) {
+		p.pos++
+		if label == "" {
+			return p.syntaxError(tok, "end label %q specified for unlabeled block", tok.str)
+		}
+		if tok.str != label {
+			return p.syntaxError(tok, "end label %q differs from block's label %q", tok.str, label)
+		}
+	}
+	if optionalSemicolon && p.peek().id == 0 {
+		return nil
+	}
+	return p.expect(';')
+}
+
+// parseExpr consumes the tokens of an expression up to the first token at
+// nesting level 0 for which stop returns true. Parentheses, brackets and CASE
+// expressions increase the nesting level.
+func (p *bodyParser) parseExpr(stop func(tok token) bool) (*Expr, error) {
+	start := p.pos
+	depth, caseDepth := 0, 0
+	for {
+		tok := p.peek()
+		if depth == 0 && caseDepth == 0 && stop(tok) {
+			break
+		}
+		if tok.id == 0 {
+			return nil, p.syntaxError(tok, "")
+		}
+		switch tok.id {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth--; depth < 0 {
+				return nil, p.syntaxError(tok, "")
+			}
+		}
+		if isWord(tok) {
+			if tok.str == "case" {
+				caseDepth++
+			} else if tok.str == "end" && caseDepth > 0 {
+				caseDepth--
+			}
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.syntaxError(p.peek(), "missing expression")
+	}
+	e := &Expr{src: p.src, tokens: p.toks[start:p.pos]}
+	markTables(e)
+	return e, nil
+}
+
+// stopAt returns a function that stops parseExpr at the given token IDs and
+// keywords.
+func stopAt(ids []int32, words ...string) func(tok token) bool {
+	return func(tok token) bool {
+		for _, id := range ids {
+			if tok.id == id {
+				return true
+			}
+		}
+		if isWord(tok) {
+			for _, w := range words {
+				if tok.str == w {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+var semicolon = []int32{';'}
+var commaOrSemicolon = []int32{',', ';'}
+
+// parseScalar parses a scalar expression and checks its syntax.
+func (p *bodyParser) parseScalar(stop func(tok token) bool) (*Expr, error) {
+	first := p.peek()
+	e, err := p.parseExpr(stop)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parser.ParseExpr(e.String()); err != nil {
+		return nil, p.wrapError(err, first)
+	}
+	return e, nil
+}
+
+// parseStmts parses statements up to one of the given keywords.
+func (p *bodyParser) parseStmts(terminators ...string) ([]Stmt, error) {
+	var stmts []Stmt
+	for {
+		tok := p.peek()
+		if tok.id == 0 {
+			return nil, p.syntaxError(tok, "")
+		}
+		if isWord(tok) {
+			for _, t := range terminators {
+				if tok.str == t {
+					return stmts, nil
+				}
+			}
+		}
+		stmt, err := p.parseStmt()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+}
+
+func (p *bodyParser) parseStmt() (Stmt, error) {
+	tok := p.peek()
+	line := p.line(tok)
+	if tok.id == lexbase.LSHIFT {
+		name, err := p.parseLabel()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.isWord("declare"), p.isWord("begin"):
+			return p.parseBlock(name, line, false /* top */)
+		case p.isWord("loop"), p.isWord("while"), p.isWord("for"):
+			return p.parseLoop(name, line)
+		}
+		return nil, p.syntaxError(p.peek(), "")
+	}
+	if !isWord(tok) {
+		return nil, p.syntaxError(tok, "")
+	}
+	switch tok.str {
+	case "declare", "begin":
+		return p.parseBlock("", line, false /* top */)
+	case "if":
+		return p.parseIf(line)
+	case "loop", "while", "for":
+		return p.parseLoop("", line)
+	case "exit", "continue":
+		return p.parseExit(line)
+	case "return":
+		return p.parseReturn(line)
+	case "raise":
+		return p.parseRaise(line)
+	case "assert":
+		return p.parseAssert(line)
+	case "perform":
+		return p.parsePerform(line)
+	case "execute":
+		return p.parseDynamicExecute(line)
+	case "get":
+		return p.parseGetDiagnostics(line)
+	case "null":
+		if p.peekN(1).id == ';' {
+			p.pos += 2
+			return &Null{stmtBase: stmtBase{line: line}}, nil
+		}
+	case "open", "fetch", "move", "close":
+		return nil, p.errorf(tok, pgcode.FeatureNotSupported,
+			"cursors are not supported in PL/pgSQL functions")
+	case "case":
+		return nil, p.errorf(tok, pgcode.FeatureNotSupported,
+			"CASE statements are not supported in PL/pgSQL functions")
+	}
+	if next := p.peekN(1); next.id == '=' || (next.id == ':' && p.peekN(2).id == '=') {
+		return p.parseAssign(line)
+	}
+	return p.parseExecSQL(line)
+}
+
+// parseBlock parses a block, after its label.
+func (p *bodyParser) parseBlock(label string, line int, top bool) (*Block, error) {
+	b := &Block{stmtBase: stmtBase{line: line}, Label: label}
+	if p.acceptWord("declare") {
+		for !p.isWord("begin") {
+			decl, err := p.parseDeclaration()
+			if err != nil {
+				return nil, err
+			}
+			b.Decls = append(b.Decls, decl)
+		}
+	}
+	if err := p.expectWord("begin"); err != nil {
+		return nil, err
+	}
+	p.labels = append(p.labels, scopeLabel{name: label})
+	defer func() { p.labels = p.labels[:len(p.labels)-1] }()
+	var err error
+	if b.Body, err = p.parseStmts("end", "exception"); err != nil {
+		return nil, err
+	}
+	if p.acceptWord("exception") {
+		p.handlers++
+		defer func() { p.handlers-- }()
+		for p.isWord("when") {
+			h := ExceptionHandler{Line: p.line(p.next())}
+			for {
+				cond, err := p.parseCondition()
+				if err != nil {
+					return nil, err
+				}
+				h.Conditions = append(h.Conditions, cond)
+				if !p.acceptWord("or") {
+					break
+				}
+			}
+			if err := p.expectWord("then"); err != nil {
+				return nil, err
+			}
+			if h.Body, err = p.parseStmts("when", "end"); err != nil {
+				return nil, err
+			}
+			b.Handlers = append(b.Handlers, h)
+		}
+		if len(b.Handlers) == 0 {
+			return nil, p.syntaxError(p.peek(), "expected WHEN")
+		}
+	}
+	if err := p.expectWord("end"); err != nil {
+		return nil, err
+	}
+	if err := p.parseEndLabel(label, top); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// parseDeclaration parses the declaration of a variable.
+func (p *bodyParser) parseDeclaration() (Declaration, error) {
+	tok := p.peek()
+	d := Declaration{Line: p.line(tok)}
+	var err error
+	if d.Name, err = p.ident(); err != nil {
+		return d, err
+	}
+	if p.isWord("alias") || p.isWord("cursor") {
+		return d, p.errorf(p.peek(), pgcode.FeatureNotSupported,
+			"%s declarations are not supported in PL/pgSQL functions", strings.ToUpper(p.peek().str))
+	}
+	d.Constant = p.acceptWord("constant")
+	typeStart := p.peek()
+	typ, err := p.parseExpr(func(tok token) bool {
+		if tok.id == ';' || tok.id == '=' || (tok.id == ':' && p.peekN(1).id == '=') {
+			return true
+		}
+		return isWord(tok) && (tok.str == "default" || tok.str == "collate" ||
+			(tok.str == "not" && p.isWordAt(1, "null")))
+	})
+	if err != nil {
+		return d, err
+	}
+	d.Type = typ.String()
+	for _, t := range typ.tokens {
+		if t.id == '%' {
+			return d, p.errorf(typeStart, pgcode.FeatureNotSupported,
+				"%%TYPE and %%ROWTYPE are not supported in PL/pgSQL functions")
+		}
+	}
+	if _, err := parser.GetTypeFromValidSQLSyntax(d.Type); err != nil {
+		return d, p.wrapError(err, typeStart)
+	}
+	if p.isWord("collate") {
+		return d, p.errorf(p.peek(), pgcode.FeatureNotSupported,
+			"COLLATE is not supported in PL/pgSQL declarations")
+	}
+	if p.acceptWord("not") {
+		if err := p.expectWord("null"); err != nil {
+			return d, err
+		}
+		d.NotNull = true
+	}
+	if p.acceptWord("default") || p.acceptAssign() {
+		if d.Default, err = p.parseScalar(stopAt(semicolon)); err != nil {
+			return d, err
+		}
+	}
+	return d, p.expect(';')
+}
+
+// isWordAt returns whether the n-th next token is the given keyword.
+func (p *bodyParser) isWordAt(n int, w string) bool {
+	tok := p.peekN(n)
+	return isWord(tok) && tok.str == w
+}
+
+// parseCondition parses the condition of an exception handler.
+func (p *bodyParser) parseCondition() (Condition, error) {
+	tok := p.peek()
+	if p.acceptWord("sqlstate") {
+		code := p.peek()
+		if err := p.expect(lexbase.SCONST); err != nil {
+			return Condition{}, err
+		}
+		cond, err := makeSQLStateCondition(code.str)
+		if err != nil {
+			return Condition{}, p.wrapError(err, code)
+		}
+		return cond, nil
+	}
+	name, err := p.ident()
+	if err != nil {
+		return Condition{}, err
+	}
+	cond, err := lookupCondition(name)
+	if err != nil {
+		return Condition{}, p.wrapError(err, tok)
+	}
+	return cond, nil
+}
+
+func (p *bodyParser) parseAssign(line int) (Stmt, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	p.acceptAssign()
+	value, err := p.parseScalar(stopAt(semicolon))
+	if err != nil {
+		return nil, err
+	}
+	return &Assign{stmtBase: stmtBase{line: line}, Var: name, Value: value}, p.expect(';')
+}
+
+func (p *bodyParser) parseIf(line int) (Stmt, error) {
+	p.next()
+	s := &If{stmtBase: stmtBase{line: line}}
+	var err error
+	if s.Cond, err = p.parseScalar(stopAt(nil, "then")); err != nil {
+		return nil, err
+	}
+	if err := p.expectWord("then"); err != nil {
+		return nil, err
+	}
+	if s.Then, err = p.parseStmts("elsif", "elseif", "else", "end"); err != nil {
+		return nil, err
+	}
+	for p.isWord("elsif") || p.isWord("elseif") {
+		elsIf := ElsIf{Line: p.line(p.next())}
+		if elsIf.Cond, err = p.parseScalar(stopAt(nil, "then")); err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("then"); err != nil {
+			return nil, err
+		}
+		if elsIf.Then, err = p.parseStmts("elsif", "elseif", "else", "end"); err != nil {
+			return nil, err
+		}
+		s.ElsIfs = append(s.ElsIfs, elsIf)
+	}
+	if p.acceptWord("else") {
+		if s.Else, err = p.parseStmts("end"); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectWord("end"); err != nil {
+		return nil, err
+	}
+	if err := p.expectWord("if"); err != nil {
+		return nil, err
+	}
+	return s, p.expect(';')
+}
+
+// parseLoop parses a LOOP, WHILE or FOR statement, after its label.
+func (p *bodyParser) parseLoop(label string, line int) (Stmt, error) {
+	base := stmtBase{line: line}
+	switch p.next().str {
+	case "loop":
+		body, err := p.parseLoopBody(label)
+		if err != nil {
+			return nil, err
+		}
+		return &Loop{stmtBase: base, Label: label, Body: body}, nil
+
+	case "while":
+		cond, err := p.parseScalar(stopAt(nil, "loop"))
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("loop"); err != nil {
+			return nil, err
+		}
+		body, err := p.parseLoopBody(label)
+		if err != nil {
+			return nil, err
+		}
+		return &While{stmtBase: base, Label: label, Cond: cond, Body: body}, nil
+	}
+
+	targets, err := p.identList()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectWord("in"); err != nil {
+		return nil, err
+	}
+	if !p.isIntegerRange() {
+		tok := p.peek()
+		query, err := p.parseExpr(stopAt(nil, "loop"))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parser.ParseOne(query.String()); err != nil {
+			return nil, p.wrapError(err, tok)
+		}
+		if err := p.expectWord("loop"); err != nil {
+			return nil, err
+		}
+		body, err := p.parseLoopBody(label)
+		if err != nil {
+			return nil, err
+		}
+		return &ForQuery{stmtBase: base, Label: label, Targets: targets, Query: query, Body: body}, nil
+	}
+
+	if len(targets) != 1 {
+		return nil, p.syntaxError(p.peek(), "integer FOR loop must have only one target variable")
+	}
+	s := &ForInt{stmtBase: base, Label: label, Var: targets[0]}
+	if p.isWord("reverse") && p.peekN(1).id != '(' {
+		p.pos++
+		s.Reverse = true
+	}
+	if s.Lower, err = p.parseScalar(stopAt([]int32{lexbase.DOT_DOT})); err != nil {
+		return nil, err
+	}
+	p.next()
+	if s.Upper, err = p.parseScalar(stopAt(nil, "by", "loop")); err != nil {
+		return nil, err
+	}
+	if p.acceptWord("by") {
+		if s.Step, err = p.parseScalar(stopAt(nil, "loop")); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectWord("loop"); err != nil {
+		return nil, err
+	}
+	if s.Body, err = p.parseLoopBody(label); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// isIntegerRange returns whether the tokens before the next LOOP keyword at
+// nesting level 0 contain "..", which distinguishes the FOR loops over integers
+// from the FOR loops over the rows of a query.
+func (p *bodyParser) isIntegerRange() bool {
+	depth := 0
+	for i := p.pos; i < len(p.toks); i++ {
+		tok := p.toks[i]
+		switch {
+		case tok.id == '(' || tok.id == '[':
+			depth++
+		case tok.id == ')' || tok.id == ']':
+			depth--
+		case depth == 0 && tok.id == lexbase.DOT_DOT:
+			return true
+		case depth == 0 && (tok.id == ';' || (isWord(tok) && tok.str == "loop")):
+			return false
+		}
+	}
+	return false
+}
+
+// parseLoopBody parses the statements of a loop and the END LOOP that follows
+// them.
+func (p *bodyParser) parseLoopBody(name string) ([]Stmt, error) {
+	p.labels = append(p.labels, scopeLabel{name: name, loop: true})
+	defer func() { p.labels = p.labels[:len(p.labels)-1] }()
+	body, err := p.parseStmts("end")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectWord("end"); err != nil {
+		return nil, err
+	}
+	if err := p.expectWord("loop"); err != nil {
+		return nil, err
+	}
+	return body, p.parseEndLabel(name, false /* optionalSemicolon */)
+}
+
+func (p *bodyParser) parseExit(line int) (Stmt, error) {
+	tok := p.next()
+	s := &Exit{stmtBase: stmtBase{line: line}, Continue: tok.str == "continue"}
+	if next := p.peek(); isWord(next) && next.str != "when" {
+		p.pos++
+		s.Label = next.str
+		found := false
+		for i := len(p.labels) - 1; i >= 0 && !found; i-- {
+			if p.labels[i].name != s.Label {
+				continue
+			}
+			found = true
+			if s.Continue && !p.labels[i].loop {
+				return nil, p.syntaxError(next, "block label %q cannot be used in CONTINUE", s.Label)
+			}
+		}
+		if !found {
+			return nil, p.syntaxError(next,
+				"there is no label %q attached to any block or loop enclosing this statement", s.Label)
+		}
+	} else {
+		inLoop := false
+		for i := range p.labels {
+			inLoop = inLoop || p.labels[i].loop
+		}
+		if !inLoop {
+			if s.Continue {
+				return nil, p.syntaxError(tok, "CONTINUE cannot be used outside a loop")
+			}
+			return nil, p.syntaxError(tok, "EXIT cannot be used outside a loop, unless it has a label")
+		}
+	}
+	if p.acceptWord("when") {
+		var err error
+		if s.Cond, err = p.parseScalar(stopAt(semicolon)); err != nil {
+			return nil, err
+		}
+	}
+	return s, p.expect(';')
+}
+
+func (p *bodyParser) parseReturn(line int) (Stmt, error) {
+	p.next()
+	s := &Return{stmtBase: stmtBase{line: line}}
+	if p.peek().id != ';' {
+		var err error
+		if s.Value, err = p.parseScalar(stopAt(semicolon)); err != nil {
+			return nil, err
+		}
+	}
+	return s, p.expect(';')
+}
+
+var raiseLevels = map[string]struct{}{
+	"debug": {}, "log": {}, "info": {}, "notice": {}, "warning": {}, "exception": {},
+}
+
+var raiseOptions = map[string]struct{}{
+	"message": {}, "detail": {}, "hint": {}, "errcode": {},
+}
+
+func (p *bodyParser) parseRaise(line int) (Stmt, error) {
+	raiseTok := p.next()
+	s := &Raise{stmtBase: stmtBase{line: line}, Level: "exception"}
+	if p.accept(';') {
+		if p.handlers == 0 {
+			return nil, p.syntaxError(raiseTok,
+				"RAISE without parameters cannot be used outside an exception handler")
+		}
+		s.Rethrow = true
+		return s, nil
+	}
+	if tok := p.peek(); isWord(tok) {
+		if _, ok := raiseLevels[tok.str]; ok {
+			s.Level = tok.str
+			p.pos++
+		}
+	}
+
+	switch tok := p.peek(); {
+	case tok.id == lexbase.SCONST:
+		p.pos++
+		s.Format = tok.str
+		for p.accept(',') {
+			param, err := p.parseScalar(stopAt(commaOrSemicolon, "using"))
+			if err != nil {
+				return nil, err
+			}
+			s.Params = append(s.Params, param)
+		}
+		if n := countFormatParams(s.Format); n < len(s.Params) {
+			return nil, p.syntaxError(tok, "too many parameters specified for RAISE")
+		} else if n > len(s.Params) {
+			return nil, p.syntaxError(tok, "too few parameters specified for RAISE")
+		}
+
+	case isWord(tok) && tok.str != "using":
+		cond, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		if cond.Name == othersCondition {
+			return nil, p.errorf(tok, pgcode.UndefinedObject,
+				"unrecognized exception condition %q", cond.Name)
+		}
+		s.Condition = &cond
+	}
+
+	if p.acceptWord("using") {
+		for {
+			tok := p.peek()
+			name, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := raiseOptions[name]; !ok {
+				return nil, p.syntaxError(tok, "unrecognized RAISE statement option")
+			}
+			for _, opt := range s.Options {
+				if opt.Name == name {
+					return nil, p.syntaxError(tok, "RAISE option already specified: %s", strings.ToUpper(name))
+				}
+			}
+			if (name == "message" && (s.Format != "" || len(s.Params) > 0)) ||
+				(name == "errcode" && s.Condition != nil) {
+				return nil, p.syntaxError(tok, "RAISE option already specified: %s", strings.ToUpper(name))
+			}
+			if !p.acceptAssign() {
+				return nil, p.syntaxError(p.peek(), "")
+			}
+			value, err := p.parseScalar(stopAt(commaOrSemicolon))
+			if err != nil {
+				return nil, err
+			}
+			s.Options = append(s.Options, RaiseOption{Name: name, Value: value})
+			if !p.accept(',') {
+				break
+			}
+		}
+	}
+	return s, p.expect(';')
+}
+
+// countFormatParams returns the number of % placeholders in the format
+// string of a RAISE statement. %% is a literal % sign.
+func countFormatParams(format string) int {
+	n := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			i++
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+func (p *bodyParser) parseAssert(line int) (Stmt, error) {
+	p.next()
+	s := &Assert{stmtBase: stmtBase{line: line}}
+	var err error
+	if s.Cond, err = p.parseScalar(stopAt(commaOrSemicolon)); err != nil {
+		return nil, err
+	}
+	if p.accept(',') {
+		if s.Message, err = p.parseScalar(stopAt(semicolon)); err != nil {
+			return nil, err
+		}
+	}
+	return s, p.expect(';')
+}
+
+func (p *bodyParser) parsePerform(line int) (Stmt, error) {
+	p.next()
+	tok := p.peek()
+	query, err := p.parseExpr(stopAt(semicolon))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parser.ParseOne("SELECT " + query.String()); err != nil {
+		return nil, p.wrapError(err, tok)
+	}
+	return &Perform{stmtBase: stmtBase{line: line}, Query: query}, p.expect(';')
+}
+
+func (p *bodyParser) parseDynamicExecute(line int) (Stmt, error) {
+	p.next()
+	s := &DynamicExecute{stmtBase: stmtBase{line: line}}
+	var err error
+	if s.Query, err = p.parseScalar(stopAt(semicolon, "into", "using")); err != nil {
+		return nil, err
+	}
+	for p.peek().id != ';' {
+		switch tok := p.peek(); {
+		case isWord(tok) && tok.str == "into" && s.Into == nil:
+			p.pos++
+			s.Strict = p.acceptWord("strict")
+			if s.Into, err = p.identList(); err != nil {
+				return nil, err
+			}
+		case isWord(tok) && tok.str == "using" && s.Using == nil:
+			p.pos++
+			for {
+				e, err := p.parseScalar(stopAt(commaOrSemicolon, "into"))
+				if err != nil {
+					return nil, err
+				}
+				s.Using = append(s.Using, e)
+				if !p.accept(',') {
+					break
+				}
+			}
+		default:
+			return nil, p.syntaxError(tok, "")
+		}
+	}
+	return s, p.expect(';')
+}
+
+func (p *bodyParser) parseGetDiagnostics(line int) (Stmt, error) {
+	p.next()
+	p.acceptWord("current")
+	if p.isWord("stacked") {
+		return nil, p.errorf(p.peek(), pgcode.FeatureNotSupported,
+			"GET STACKED DIAGNOSTICS is not supported in PL/pgSQL functions")
+	}
+	if err := p.expectWord("diagnostics"); err != nil {
+		return nil, err
+	}
+	s := &GetDiagnostics{stmtBase: stmtBase{line: line}}
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptAssign() {
+			return nil, p.syntaxError(p.peek(), "")
+		}
+		item := p.peek()
+		if !p.acceptWord("row_count") {
+			if isWord(item) {
+				return nil, p.errorf(item, pgcode.FeatureNotSupported,
+					"GET DIAGNOSTICS %s is not supported in PL/pgSQL functions", strings.ToUpper(item.str))
+			}
+			return nil, p.syntaxError(item, "")
+		}
+		s.Vars = append(s.Vars, name)
+		if !p.accept(',') {
+			break
+		}
+	}
+	return s, p.expect(';')
+}
+
+// parseExecSQL parses a SQL statement with an optional INTO clause.
+func (p *bodyParser) parseExecSQL(line int) (Stmt, error) {
+	first := p.peek()
+	e, err := p.parseExpr(stopAt(semicolon))
+	if err != nil {
+		return nil, err
+	}
+	s := &ExecSQL{stmtBase: stmtBase{line: line}}
+	if s.Into, s.Strict, err = p.extractInto(e); err != nil {
+		return nil, err
+	}
+	markColumns(e)
+	s.Query = e
+	stmt, err := parser.ParseOne(e.String())
+	if err != nil {
+		return nil, p.wrapError(err, first)
+	}
+	s.AST = stmt.AST
+	returnsRows := stmtReturnsRows(s.AST)
+	if returnsRows && s.Into == nil {
+		return nil, p.wrapError(errors.WithHint(
+			pgerror.New(pgcode.Syntax, "query has no destination for result data"),
+			"If you want to discard the results of a SELECT, use PERFORM instead.",
+		), first)
+	}
+	if !returnsRows && s.Into != nil {
+		return nil, p.errorf(first, pgcode.Syntax, "INTO used with a command that cannot return data")
+	}
+	if _, ok := s.AST.(*tree.BeginTransaction); ok {
+		return nil, p.errorf(first, pgcode.FeatureNotSupported,
+			"BEGIN is not supported in PL/pgSQL functions")
+	}
+	return s, p.expect(';')
+}
+
+// extractInto removes the INTO clause from a SQL statement and returns its
+// targets. The INTO keyword of INSERT, UPSERT and MERGE statements is part of
+// the statement.
+func (p *bodyParser) extractInto(e *Expr) (into []string, strict bool, _ error) {
+	depth := 0
+	for i, tok := range e.tokens {
+		switch tok.id {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		}
+		if depth > 0 || !isWord(tok) || tok.str != "into" {
+			continue
+		}
+		if i > 0 && isWord(e.tokens[i-1]) {
+			switch e.tokens[i-1].str {
+			case "insert", "upsert", "merge":
+				continue
+			}
+		}
+		if into != nil {
+			return nil, false, p.syntaxError(tok, "INTO specified more than once")
+		}
+		j := i + 1
+		if j < len(e.tokens) && isWord(e.tokens[j]) && e.tokens[j].str == "strict" {
+			strict = true
+			j++
+		}
+		for {
+			if j >= len(e.tokens) || !isWord(e.tokens[j]) {
+				return nil, false, p.syntaxError(tok, "expected INTO target")
+			}
+			into = append(into, e.tokens[j].str)
+			j++
+			if j >= len(e.tokens) || e.tokens[j].id != ',' {
+				break
+			}
+			j++
+		}
+		tokens := append([]token(nil), e.tokens[:i]...)
+		e.tokens = append(tokens, e.tokens[j:]...)
+		return into, strict, nil
+	}
+	return nil, false, nil
+}
+
+// markColumns marks the tokens of a SQL statement which name target columns,
+// and which therefore do not refer to variables: the columns listed after
+// INSERT INTO table and ON CONFLICT, and the columns assigned by SET clauses.
+func markColumns(e *Expr) {
+	toks := e.tokens
+	markParens := func(i int) {
+		// Mark the identifiers in the parentheses which start at i.
+		for depth := 0; i < len(toks); i++ {
+			switch toks[i].id {
+			case '(':
+				depth++
+			case ')':
+				if depth--; depth == 0 {
+					return
+				}
+			}
+			if depth == 1 && isWord(toks[i]) {
+				e.columns.Add(i)
+			}
+		}
+	}
+	inSet := false
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		if !isWord(tok) {
+			if inSet && tok.id == ',' && i+2 < len(toks) && isWord(toks[i+1]) && toks[i+2].id == '=' {
+				e.columns.Add(i + 1)
+			}
+			continue
+		}
+		switch tok.str {
+		case "into":
+			if i == 0 || !isWord(toks[i-1]) || (toks[i-1].str != "insert" && toks[i-1].str != "upsert") {
+				continue
+			}
+			// Skip the table name and its alias.
+			j := i + 1
+			for j < len(toks) && (isWord(toks[j]) || toks[j].id == '.') {
+				j++
+			}
+			if j < len(toks) && toks[j].id == '(' {
+				markParens(j)
+			}
+		case "conflict":
+			if i+1 < len(toks) && toks[i+1].id == '(' {
+				markParens(i + 1)
+			}
+		case "set":
+			inSet = true
+			if i+2 < len(toks) && isWord(toks[i+1]) && toks[i+2].id == '=' {
+				e.columns.Add(i + 1)
+			}
+		case "from", "where", "returning":
+			inSet = false
+		}
+	}
+}
+
+// markTables records the names of the tables referenced by a SQL statement or
+// expression: the tables which follow FROM, JOIN, UPDATE, INSERT INTO and
+// USING, along with the other tables of a FROM clause. The FROM keywords in
+// the arguments of functions, such as in EXTRACT(YEAR FROM x), are ignored.
+func markTables(e *Expr) {
+	toks := e.tokens
+	// name returns the text of the qualified name which starts at i, if any,
+	// and the index of the token which follows it.
+	name := func(i int) (string, int) {
+		if i >= len(toks) || !isWord(toks[i]) {
+			return "", i
+		}
+		start := i
+		for i+2 < len(toks) && toks[i+1].id == '.' && isWord(toks[i+2]) {
+			i += 2
+		}
+		return e.src[toks[start].pos:toks[i].end], i + 1
+	}
+	// inFunc contains, for each enclosing parenthesis, whether it contains the
+	// arguments of a function.
+	var inFunc []bool
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		switch tok.id {
+		case '(':
+			inFunc = append(inFunc, i > 0 && isWord(toks[i-1]))
+			continue
+		case ')':
+			if len(inFunc) > 0 {
+				inFunc = inFunc[:len(inFunc)-1]
+			}
+			continue
+		}
+		if !isWord(tok) || (len(inFunc) > 0 && inFunc[len(inFunc)-1]) {
+			continue
+		}
+		switch tok.str {
+		case "into":
+			if i == 0 || (toks[i-1].str != "insert" && toks[i-1].str != "upsert") {
+				continue
+			}
+		case "update":
+			if i > 0 && toks[i-1].str == "for" {
+				// This is a locking clause.
+				continue
+			}
+		case "join", "using":
+		case "from":
+			if i > 0 && toks[i-1].str == "distinct" {
+				// This is an IS DISTINCT FROM comparison.
+				continue
+			}
+			// The tables of a FROM clause are separated by commas, and can be
+			// followed by aliases.
+			for j := i + 1; ; {
+				n, next := name(j)
+				if n == "" {
+					break
+				}
+				e.tables = append(e.tables, n)
+				if next < len(toks) && toks[next].str == "as" {
+					next++
+				}
+				if next < len(toks) && toks[next].id == lexbase.IDENT {
+					next++
+				}
+				if next >= len(toks) || toks[next].id != ',' {
+					break
+				}
+				j = next + 1
+			}
+			continue
+		default:
+			continue
+		}
+		if n, _ := name(i + 1); n != "" {
+			e.tables = append(e.tables, n)
+		}
+	}
+}
+
+// IsTransactionControl returns whether the statement commits or rolls back
+// the current transaction.
+func IsTransactionControl(stmt tree.Statement) bool {
+	switch stmt.(type) {
+	case *tree.CommitTransaction, *tree.RollbackTransaction, *tree.BeginTransaction,
+		*tree.Savepoint, *tree.ReleaseSavepoint, *tree.RollbackToSavepoint,
+		*tree.PrepareTransaction, *tree.CommitPrepared, *tree.RollbackPrepared:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package plpgsql_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/plpgsql"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/datadriven"
+)
+
+// TestParse verifies that PL/pgSQL code is parsed and formatted back, and that
+// the errors of invalid code have the expected code and context. The body of
+// functions written in SQL is parsed instead by the parse-sql and error-sql
+// commands.
+func TestParse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	datadriven.Walk(t, testutils.TestDataPath(t), func(t *testing.T, path string) {
+		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
+			if d.Cmd == "parse-sql" || d.Cmd == "error-sql" {
+				block, err := plpgsql.ParseSQL("f", d.Input)
+				if d.Cmd == "error-sql" {
+					if err == nil {
+						d.Fatalf(t, "expected error, parsed:\n%s", block)
+					}
+					pgErr := pgerror.Flatten(err)
+					return fmt.Sprintf("%s: %s\n", pgErr.Code, pgErr.Message)
+				}
+				if err != nil {
+					d.Fatalf(t, "unexpected error: %v", err)
+				}
+				var b strings.Builder
+				for _, s := range block.Body {
+					s := s.(*plpgsql.ExecSQL)
+					fmt.Fprintf(&b, "%d: %s (result: %t)\n", s.Line(), s.Query, s.Result)
+				}
+				return b.String()
+			}
+			block, err := plpgsql.Parse("f", d.Input)
+			switch d.Cmd {
+			case "parse":
+				if err != nil {
+					d.Fatalf(t, "unexpected error: %v", err)
+				}
+				formatted := block.String()
+				// The formatted code must parse to the same code.
+				reparsed, err := plpgsql.Parse("f", formatted)
+				if err != nil {
+					d.Fatalf(t, "error parsing formatted code: %v\n%s", err, formatted)
+				}
+				if s := reparsed.String(); s != formatted {
+					d.Fatalf(t, "formatted code does not round trip:\n%s\n%s", formatted, s)
+				}
+				return formatted
+
+			case "error":
+				if err == nil {
+					d.Fatalf(t, "expected error, parsed:\n%s", block)
+				}
+				pgErr := pgerror.Flatten(err)
+				return fmt.Sprintf("%s: %s\nDETAIL: %s\n", pgErr.Code, pgErr.Message, pgErr.Detail)
+			}
+			d.Fatalf(t, "unknown command %s", d.Cmd)
+			return ""
+		})
+	})
+}
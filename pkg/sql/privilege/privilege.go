@@ -46,6 +46,7 @@ const (
 	CONNECT              Kind = 11
 	RULE                 Kind = 12
 	MODIFYCLUSTERSETTING Kind = 13
+	EXECUTE              Kind = 14
 )
 
 // Privilege represents a privilege parsed from an Access Privilege Inquiry
@@ -75,6 +76,8 @@ const (
 	Type ObjectType = "type"
 	// Sequence represents a sequence object.
 	Sequence ObjectType = "sequence"
+	// Function represents a function, procedure or aggregate object.
+	Function ObjectType = "function"
 	// Global represents global privileges.
 	Global ObjectType = "global"
 )
@@ -85,24 +88,29 @@ var isDescriptorBacked = map[ObjectType]bool{
 	Table:    true,
 	Type:     true,
 	Sequence: true,
+	Function: true,
 	Global:   false,
 }
 
 // Predefined sets of privileges.
 var (
-	AllPrivileges    = List{ALL, CONNECT, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, USAGE, ZONECONFIG}
-	ReadData         = List{SELECT}
-	ReadWriteData    = List{SELECT, INSERT, DELETE, UPDATE}
-	DBPrivileges     = List{ALL, CONNECT, CREATE, DROP, ZONECONFIG}
-	TablePrivileges  = List{ALL, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG}
-	SchemaPrivileges = List{ALL, CREATE, USAGE}
-	TypePrivileges   = List{ALL, USAGE}
+	AllPrivileges      = List{ALL, CONNECT, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, USAGE, ZONECONFIG, EXECUTE}
+	ReadData           = List{SELECT}
+	ReadWriteData      = List{SELECT, INSERT, DELETE, UPDATE}
+	DBPrivileges       = List{ALL, CONNECT, CREATE, DROP, ZONECONFIG}
+	TablePrivileges    = List{ALL, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG}
+	SchemaPrivileges   = List{ALL, CREATE, USAGE}
+	TypePrivileges     = List{ALL, USAGE}
+	FunctionPrivileges = List{ALL, EXECUTE}
 	// SequencePrivileges is appended with TablePrivileges as well. This is because
 	// before v22.2 we treated Sequences the same as Tables. This is to avoid making
 	// certain privileges unavailable after upgrade migration.
 	// Note that "CREATE, INSERT, DELETE, ZONECONFIG" are no-op privileges on sequences.
 	SequencePrivileges = List{ALL, USAGE, SELECT, UPDATE, CREATE, DROP, INSERT, DELETE, ZONECONFIG}
 	SystemPrivileges   = List{ALL, MODIFYCLUSTERSETTING}
+	// ColumnPrivileges are the privileges which can be granted on individual
+	// columns of a table.
+	ColumnPrivileges = List{SELECT, INSERT, UPDATE}
 )
 
 // Mask returns the bitmask for a given privilege.
@@ -117,7 +125,7 @@ func (k Kind) IsSetIn(bits uint32) bool {
 
 // ByValue is just an array of privilege kinds sorted by value.
 var ByValue = [...]Kind{
-	ALL, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, USAGE, ZONECONFIG, CONNECT, RULE, MODIFYCLUSTERSETTING,
+	ALL, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, USAGE, ZONECONFIG, CONNECT, RULE, MODIFYCLUSTERSETTING, EXECUTE,
 }
 
 // ByName is a map of string -> kind value.
@@ -134,6 +142,7 @@ var ByName = map[string]Kind{
 	"USAGE":                USAGE,
 	"RULE":                 RULE,
 	"MODIFYCLUSTERSETTING": MODIFYCLUSTERSETTING,
+	"EXECUTE":              EXECUTE,
 }
 
 // List is a list of privileges.
@@ -292,6 +301,8 @@ func GetValidPrivilegesForObject(objectType ObjectType) List {
 		return TypePrivileges
 	case Sequence:
 		return SequencePrivileges
+	case Function:
+		return FunctionPrivileges
 	case Any:
 		return AllPrivileges
 	case Global:
@@ -310,10 +321,11 @@ var privToACL = map[Kind]string{
 	UPDATE:  "w",
 	USAGE:   "U",
 	CONNECT: "c",
+	EXECUTE: "X",
 }
 
-// orderedPrivs is the list of privileges sorted in alphanumeric order based on the ACL character -> CUacdrw
-var orderedPrivs = List{CREATE, USAGE, INSERT, CONNECT, DELETE, SELECT, UPDATE}
+// orderedPrivs is the list of privileges sorted in alphanumeric order based on the ACL character -> CUXacdrw
+var orderedPrivs = List{CREATE, USAGE, EXECUTE, INSERT, CONNECT, DELETE, SELECT, UPDATE}
 
 // ListToACL converts a list of privileges to a list of Postgres
 // ACL items.
@@ -0,0 +1,103 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// publicationIncludesTable returns true if the changes to the table are
+// published by the publication.
+func publicationIncludesTable(
+	pub *descpb.DatabaseDescriptor_Publication, table catalog.TableDescriptor,
+) bool {
+	if !table.IsTable() || table.IsVirtualTable() || table.IsTemporary() {
+		return false
+	}
+	if pub.AllTables {
+		return true
+	}
+	for _, id := range pub.TableIDs {
+		if id == table.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+// publicationTable is a table published by a publication.
+type publicationTable struct {
+	id descpb.ID
+	// name is the fully qualified name of the table.
+	name tree.TableName
+}
+
+// getPublicationTables returns the tables published by the given publications
+// of a database. It is an error if one of the publications does not exist.
+func getPublicationTables(
+	ctx context.Context,
+	txn *kv.Txn,
+	col *descs.Collection,
+	db catalog.DatabaseDescriptor,
+	pubNames []string,
+) ([]publicationTable, error) {
+	pubs := make([]*descpb.DatabaseDescriptor_Publication, len(pubNames))
+	dbPubs := db.GetPublications()
+	for i, name := range pubNames {
+		for j := range dbPubs {
+			if dbPubs[j].Name == name {
+				pubs[i] = &dbPubs[j]
+			}
+		}
+		if pubs[i] == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+		}
+	}
+
+	tables, err := col.GetAllTableDescriptorsInDatabase(ctx, txn, db.GetID())
+	if err != nil {
+		return nil, err
+	}
+	var res []publicationTable
+	for _, table := range tables {
+		if table.Dropped() || table.Offline() {
+			continue
+		}
+		included := false
+		for _, pub := range pubs {
+			included = included || publicationIncludesTable(pub, table)
+		}
+		if !included {
+			continue
+		}
+		sc, err := col.GetImmutableSchemaByID(
+			ctx, txn, table.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, publicationTable{
+			id: table.GetID(),
+			name: tree.MakeTableNameWithSchema(
+				tree.Name(db.GetName()), tree.Name(sc.GetName()), tree.Name(table.GetName()),
+			),
+		})
+	}
+	return res, nil
+}
@@ -28,11 +28,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -185,6 +188,16 @@ func RandDatumWithNullChance(
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSQueryFamily:
+		return tree.NewDTSQuery(randTSQuery(rng))
+	case types.TSVectorFamily:
+		return tree.NewDTSVector(randTSVector(rng))
+	case types.RangeFamily:
+		return randRange(rng, typ)
+	case types.JsonpathFamily:
+		return tree.NewDJsonpath(randJsonpath(rng))
+	case types.PGVectorFamily:
+		return tree.NewDPGVector(randPGVector(rng, typ))
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		if nullChance == 0 {
@@ -469,6 +482,122 @@ func randStringSimple(rng *rand.Rand) string {
 	return string(rune('A' + rng.Intn(simpleRange)))
 }
 
+// randTSVector generates a random tsvector made of a few short lexemes, so
+// that the lexemes of different vectors are likely to overlap.
+func randTSVector(rng *rand.Rand) tsearch.TSVector {
+	var buf bytes.Buffer
+	for i, n := 0, rng.Intn(5); i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(randStringSimple(rng))
+		if rng.Intn(2) == 0 {
+			fmt.Fprintf(&buf, ":%d%c", 1+rng.Intn(100), 'A'+rng.Intn(4))
+		}
+	}
+	v, err := tsearch.ParseTSVector(buf.String())
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// randTSQuery generates a random tsquery over the same lexemes as
+// randTSVector.
+func randTSQuery(rng *rand.Rand) tsearch.TSQuery {
+	var buf bytes.Buffer
+	for i, n := 0, rng.Intn(4); i < n; i++ {
+		if i > 0 {
+			buf.WriteString([]string{" & ", " | ", " <-> "}[rng.Intn(3)])
+		}
+		if rng.Intn(4) == 0 {
+			buf.WriteByte('!')
+		}
+		buf.WriteString(randStringSimple(rng))
+	}
+	q, err := tsearch.ParseTSQuery(buf.String())
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// randRange generates a random range of the given range type. Each bound is
+// infinite with some small probability.
+func randRange(rng *rand.Rand, typ *types.T) tree.Datum {
+	if rng.Intn(10) == 0 {
+		return tree.NewDEmptyRange(typ)
+	}
+	var bounds [2]tree.Datum
+	for i := range bounds {
+		if rng.Intn(5) == 0 {
+			bounds[i] = tree.DNull
+		} else {
+			bounds[i] = RandDatum(rng, typ.RangeContents(), false /* nullOk */)
+		}
+	}
+	lowerInc, upperInc := rng.Intn(2) == 0, rng.Intn(2) == 0
+	r, err := tree.NewDRange(typ, bounds[0], bounds[1], lowerInc, upperInc)
+	if err != nil {
+		// The bounds were out of order (or could not be used as bounds at all);
+		// try again with them swapped before giving up.
+		r, err = tree.NewDRange(typ, bounds[1], bounds[0], lowerInc, upperInc)
+		if err != nil {
+			return tree.NewDEmptyRange(typ)
+		}
+	}
+	return r
+}
+
+// randJsonpath generates a random jsonpath made up of a chain of simple
+// accessors, optionally followed by a comparison filter.
+func randJsonpath(rng *rand.Rand) *jsonpath.Jsonpath {
+	path := jsonpath.Path{jsonpath.Root{}}
+	for i, n := 0, rng.Intn(4); i < n; i++ {
+		switch rng.Intn(4) {
+		case 0:
+			path = append(path, jsonpath.AnyArray{})
+		case 1:
+			path = append(path, jsonpath.AnyKey{})
+		default:
+			path = append(path, jsonpath.Key(randStringSimple(rng)))
+		}
+	}
+	if rng.Intn(3) == 0 {
+		var val json.JSON
+		if rng.Intn(2) == 0 {
+			val = json.FromInt(rng.Intn(simpleRange))
+		} else {
+			val = json.FromString(randStringSimple(rng))
+		}
+		path = append(path, jsonpath.Filter{Cond: jsonpath.Operation{
+			Type:  jsonpath.OpEqual,
+			Left:  jsonpath.Current{},
+			Right: jsonpath.Scalar{Val: val},
+		}})
+	}
+	// Like the parser, don't wrap a lone primary in a Path.
+	var expr jsonpath.Expr = path
+	if len(path) == 1 {
+		expr = path[0]
+	}
+	return &jsonpath.Jsonpath{Strict: rng.Intn(2) == 0, Expr: expr}
+}
+
+// randPGVector generates a random vector with the number of dimensions of the
+// given type, or a random number of dimensions if the type has no width.
+func randPGVector(rng *rand.Rand, typ *types.T) vector.T {
+	dims := int(typ.Width())
+	if dims == 0 {
+		dims = 1 + rng.Intn(8)
+	}
+	v := make(vector.T, dims)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
 func randJSONSimple(rng *rand.Rand) json.JSON {
 	switch rng.Intn(10) {
 	case 0:
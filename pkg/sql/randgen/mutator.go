@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 )
 
 var (
@@ -363,6 +364,10 @@ func encodeInvertedIndexHistogramUpperBounds(colType *types.T, val tree.Datum) (
 		keys, err = rowenc.EncodeGeoInvertedIndexTableKeys(val, nil, *geoindex.DefaultGeometryIndexConfig())
 	case types.GeographyFamily:
 		keys, err = rowenc.EncodeGeoInvertedIndexTableKeys(val, nil, *geoindex.DefaultGeographyIndexConfig())
+	case types.PGVectorFamily:
+		// Vector index keys depend on the centroids of the index, so use the key
+		// of the first list, which exists in every vector index.
+		keys = [][]byte{vecindex.EncodeKey(nil /* inKey */, 0 /* list */)}
 	default:
 		keys, err = rowenc.EncodeInvertedIndexTableKeys(val, nil, descpb.LatestIndexDescriptorVersion)
 	}
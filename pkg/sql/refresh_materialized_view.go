@@ -50,6 +50,17 @@ func (p *planner) RefreshMaterializedView(
 		}
 	}
 
+	if desc.GetIncrementalRefresh() != nil {
+		if desc.IsRefreshViewRequired() {
+			return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"materialized view %q is still being populated", desc.Name)
+		}
+		if n.RefreshDataOption == tree.RefreshDataClear {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"incrementally refreshed materialized view %q cannot be refreshed WITH NO DATA", desc.Name)
+		}
+	}
+
 	// Only the owner or an admin (superuser) can refresh the view.
 	hasAdminRole, err := p.HasAdminRole(ctx)
 	if err != nil {
@@ -90,6 +101,10 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 		)
 	}
 
+	if n.desc.GetIncrementalRefresh() != nil {
+		return n.refreshIncremental(params)
+	}
+
 	// Prepare the new set of indexes by cloning all existing indexes on the view.
 	newPrimaryIndex := n.desc.GetPrimaryIndex().IndexDescDeepCopy()
 	newIndexes := make([]descpb.IndexDescriptor, len(n.desc.PublicNonPrimaryIndexes()))
@@ -127,6 +142,25 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 	)
 }
 
+// refreshIncremental recomputes the data of an incrementally refreshed
+// materialized view in the current transaction. The view is maintained by the
+// mutations of the tables it depends on, so its indexes can be rewritten in
+// place without a schema change.
+func (n *refreshMaterializedViewNode) refreshIncremental(params runParams) error {
+	if err := populateIncrementalMaterializedView(
+		params.ctx, params.ExecCfg(), params.p.Txn(), params.p.Descriptors(), n.desc,
+	); err != nil {
+		return err
+	}
+	n.desc.LastRefreshTime = params.p.Txn().ReadTimestamp()
+	return params.p.writeSchemaChange(
+		params.ctx,
+		n.desc,
+		descpb.InvalidMutationID,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
 func (n *refreshMaterializedViewNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *refreshMaterializedViewNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *refreshMaterializedViewNode) Close(ctx context.Context)           {}
@@ -81,6 +81,11 @@ func (p *planner) RenameTable(ctx context.Context, n *tree.RenameTable) (planNod
 	// Check if any objects depend on this table/view/sequence via its name.
 	// If so, then we disallow renaming, otherwise we allow it.
 	for _, dependent := range tableDesc.DependedOnBy {
+		if dependent.Trigger {
+			return nil, p.dependentTriggerError(
+				ctx, string(tableDesc.DescriptorType()), oldTn.String(), dependent.ID, "rename",
+			)
+		}
 		if !dependent.ByID {
 			return nil, p.dependentViewError(
 				ctx, string(tableDesc.DescriptorType()), oldTn.String(),
@@ -0,0 +1,346 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/lsn"
+)
+
+// This file contains the replication commands which are executed like regular
+// statements. START_REPLICATION takes over the connection, and is implemented
+// in start_replication.go.
+
+var identifySystemColumns = colinfo.ResultColumns{
+	{Name: "systemid", Typ: types.String},
+	{Name: "timeline", Typ: types.Int4},
+	{Name: "xlogpos", Typ: types.String},
+	{Name: "dbname", Typ: types.String},
+}
+
+// IdentifySystem returns the identity of the cluster and the current position
+// of the stream of changes.
+func (p *planner) IdentifySystem(ctx context.Context, n *tree.IdentifySystem) (planNode, error) {
+	return &delayedNode{
+		name:    n.String(),
+		columns: identifySystemColumns,
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			dbName := tree.DNull
+			if p.CurrentDatabase() != "" {
+				dbName = tree.NewDString(p.CurrentDatabase())
+			}
+			// There is a single timeline, since there is no point-in-time recovery
+			// which would fork the history of the cluster.
+			const timeline = 1
+			xlogPos := pgrepl.LSNFromTimestamp(p.ExecCfg().Clock.Now())
+			v := p.newContainerValuesNode(identifySystemColumns, 1)
+			if _, err := v.rows.AddRow(ctx, tree.Datums{
+				tree.NewDString(p.ExecCfg().LogicalClusterID().String()),
+				tree.NewDInt(timeline),
+				tree.NewDString(xlogPos.String()),
+				dbName,
+			}); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}, nil
+}
+
+var createReplicationSlotColumns = colinfo.ResultColumns{
+	{Name: "slot_name", Typ: types.String},
+	{Name: "consistent_point", Typ: types.String},
+	{Name: "snapshot_name", Typ: types.String},
+	{Name: "output_plugin", Typ: types.String},
+}
+
+// CreateReplicationSlot creates a logical replication slot. The slot starts at
+// the current position of the stream of changes: streams started on the slot
+// include the changes made from now on, until its subscriber confirms that it
+// flushed them. Temporary slots are only usable by the session which created
+// them, and are dropped when it ends.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *tree.CreateReplicationSlot,
+) (planNode, error) {
+	return &delayedNode{
+		name:    n.String(),
+		columns: createReplicationSlotColumns,
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if err := p.checkCanUseReplicationSlots(ctx); err != nil {
+				return nil, err
+			}
+			dbName := p.CurrentDatabase()
+			if dbName == "" {
+				return nil, pgerror.New(pgcode.InvalidCatalogName,
+					"logical replication slots require a current database")
+			}
+			db, err := p.Descriptors().GetImmutableDatabaseByName(
+				ctx, p.Txn(), dbName, tree.DatabaseLookupFlags{Required: true},
+			)
+			if err != nil {
+				return nil, err
+			}
+			slot := &replicationSlot{
+				name:              string(n.Slot),
+				plugin:            string(n.Plugin),
+				dbID:              db.GetID(),
+				confirmedFlushLSN: pgrepl.LSNFromTimestamp(p.ExecCfg().Clock.Now()),
+				temporary:         n.Temporary,
+			}
+			if err := createReplicationSlot(
+				ctx, p.ExecCfg().InternalExecutor, p.Txn(), p.extendedEvalCtx.TemporaryReplicationSlots, slot,
+			); err != nil {
+				return nil, err
+			}
+			v := p.newContainerValuesNode(createReplicationSlotColumns, 1)
+			if _, err := v.rows.AddRow(ctx, tree.Datums{
+				tree.NewDString(slot.name),
+				tree.NewDString(slot.confirmedFlushLSN.String()),
+				tree.DNull, /* snapshot_name */
+				tree.NewDString(slot.plugin),
+			}); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}, nil
+}
+
+// DropReplicationSlot drops a logical replication slot.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *tree.DropReplicationSlot,
+) (planNode, error) {
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if err := p.checkCanUseReplicationSlots(ctx); err != nil {
+				return nil, err
+			}
+			if err := dropReplicationSlot(
+				ctx, p.ExecCfg().InternalExecutor, p.Txn(), *p.extendedEvalCtx.TemporaryReplicationSlots,
+				string(n.Slot),
+			); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+func (p *planner) checkCanUseReplicationSlots(ctx context.Context) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ReplicationSlotsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use replication slots",
+			clusterversion.ByKey(clusterversion.ReplicationSlotsTable))
+	}
+	if p.extendedEvalCtx.TemporaryReplicationSlots == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"replication slots cannot be used in this context")
+	}
+	// Replication streams are backed by changefeeds, so the slots are managed
+	// by the users which may create changefeeds.
+	ok, err := p.HasRoleOption(ctx, roleoption.CONTROLCHANGEFEED)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return pgerror.New(pgcode.InsufficientPrivilege,
+			"current user must have a role WITH CONTROLCHANGEFEED to use replication slots")
+	}
+	return nil
+}
+
+// replicationSlot is a logical replication slot. The slots are stored in
+// system.replication_slots, except for the temporary slots, which are only
+// kept by the session which created them.
+type replicationSlot struct {
+	name   string
+	plugin string
+	dbID   descpb.ID
+	// confirmedFlushLSN is the LSN up to which the subscriber confirmed that it
+	// flushed the changes. Replication resumes after it, unless the subscriber
+	// requests to resume after a later LSN.
+	confirmedFlushLSN lsn.LSN
+	temporary         bool
+}
+
+// temporaryReplicationSlots are the temporary replication slots of a
+// session, by name.
+type temporaryReplicationSlots map[string]*replicationSlot
+
+func (t *temporaryReplicationSlots) add(slot *replicationSlot) {
+	if *t == nil {
+		*t = make(temporaryReplicationSlots)
+	}
+	(*t)[slot.name] = slot
+}
+
+func createReplicationSlot(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	temporary *temporaryReplicationSlots,
+	slot *replicationSlot,
+) error {
+	existing, err := getReplicationSlot(ctx, ie, txn, *temporary, slot.name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"replication slot %q already exists", slot.name)
+	}
+	if slot.temporary {
+		temporary.add(slot)
+		return nil
+	}
+	_, err = ie.ExecEx(
+		ctx, "create-replication-slot", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.replication_slots (slot_name, plugin, database_id, confirmed_flush_lsn)
+VALUES ($1, $2, $3, $4)`,
+		slot.name, slot.plugin, slot.dbID, int64(slot.confirmedFlushLSN),
+	)
+	return err
+}
+
+func dropReplicationSlot(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	temporary temporaryReplicationSlots,
+	name string,
+) error {
+	if _, ok := temporary[name]; ok {
+		delete(temporary, name)
+		return nil
+	}
+	n, err := ie.ExecEx(
+		ctx, "drop-replication-slot", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.replication_slots WHERE slot_name = $1`,
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"replication slot %q does not exist", name)
+	}
+	return nil
+}
+
+// getReplicationSlot returns the replication slot with the given name, or nil
+// if there is none.
+func getReplicationSlot(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	temporary temporaryReplicationSlots,
+	name string,
+) (*replicationSlot, error) {
+	if slot, ok := temporary[name]; ok {
+		return slot, nil
+	}
+	row, err := ie.QueryRowEx(
+		ctx, "get-replication-slot", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT plugin, database_id, confirmed_flush_lsn FROM system.replication_slots
+WHERE slot_name = $1`,
+		name,
+	)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return &replicationSlot{
+		name:              name,
+		plugin:            string(tree.MustBeDString(row[0])),
+		dbID:              descpb.ID(tree.MustBeDInt(row[1])),
+		confirmedFlushLSN: lsn.LSN(tree.MustBeDInt(row[2])),
+	}, nil
+}
+
+// listReplicationSlots returns the replication slots, followed by the
+// temporary replication slots of the session.
+func listReplicationSlots(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, temporary temporaryReplicationSlots,
+) ([]*replicationSlot, error) {
+	rows, err := ie.QueryBufferedEx(
+		ctx, "list-replication-slots", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT slot_name, plugin, database_id, confirmed_flush_lsn FROM system.replication_slots
+ORDER BY slot_name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]*replicationSlot, 0, len(rows)+len(temporary))
+	for _, row := range rows {
+		slots = append(slots, &replicationSlot{
+			name:              string(tree.MustBeDString(row[0])),
+			plugin:            string(tree.MustBeDString(row[1])),
+			dbID:              descpb.ID(tree.MustBeDInt(row[2])),
+			confirmedFlushLSN: lsn.LSN(tree.MustBeDInt(row[3])),
+		})
+	}
+	names := make([]string, 0, len(temporary))
+	for name := range temporary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		slots = append(slots, temporary[name])
+	}
+	return slots, nil
+}
+
+// confirmReplicationSlot records that the subscriber of a replication slot
+// flushed the changes up to the given LSN.
+func confirmReplicationSlot(
+	ctx context.Context, ie *InternalExecutor, slot *replicationSlot, flushed lsn.LSN,
+) error {
+	if flushed <= slot.confirmedFlushLSN {
+		return nil
+	}
+	if !slot.temporary {
+		n, err := ie.ExecEx(
+			ctx, "confirm-replication-slot", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`UPDATE system.replication_slots SET confirmed_flush_lsn = $2 WHERE slot_name = $1`,
+			slot.name, int64(flushed),
+		)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"replication slot %q was dropped", slot.name)
+		}
+	}
+	slot.confirmedFlushLSN = flushed
+	return nil
+}
@@ -447,6 +447,49 @@ func (p *planner) getDescriptorsFromTargetListForPrivilegeChange(
 		return descs, nil
 	}
 
+	if targets.Functions != nil || targets.Procedures != nil {
+		isProcedure, sigs := false, targets.Functions
+		if targets.Procedures != nil {
+			isProcedure, sigs = true, targets.Procedures
+		}
+		descs := make([]DescriptorWithObjectType, 0, len(sigs))
+		for i := range sigs {
+			sig := &sigs[i]
+			argTypes := make([]*types.T, len(sig.ArgTypes))
+			for j, ref := range sig.ArgTypes {
+				var err error
+				if argTypes[j], err = p.resolveFunctionType(ctx, ref); err != nil {
+					return nil, err
+				}
+			}
+			db, err := p.getFunctionDatabase(ctx, functionKind(isProcedure), &sig.Name)
+			if err != nil {
+				return nil, err
+			}
+			fn, err := p.findFunction(ctx, db, &sig.Name, argTypes)
+			if err != nil {
+				return nil, err
+			}
+			if fn == nil {
+				return nil, errFunctionDoesNotExist(isProcedure, sig.Name.Object(), argTypes)
+			}
+			if fn.GetIsProcedure() != isProcedure {
+				return nil, errWrongFunctionKind(isProcedure, sig.Name.Object(), argTypes)
+			}
+			descriptor, err := p.Descriptors().GetMutableFunctionByID(
+				ctx, p.txn, fn.GetID(), tree.ObjectLookupFlagsWithRequired(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, DescriptorWithObjectType{
+				descriptor: descriptor,
+				objectType: privilege.Function,
+			})
+		}
+		return descs, nil
+	}
+
 	if targets.Schemas != nil {
 		if len(targets.Schemas) == 0 {
 			return nil, errNoSchema
@@ -16,6 +16,7 @@ import (
 	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
@@ -245,6 +246,7 @@ type kvBatchFetcherArgs struct {
 	lockStrength               descpb.ScanLockingStrength
 	lockWaitPolicy             descpb.ScanLockingWaitPolicy
 	lockTimeout                time.Duration
+	isoLevel                   isolation.Level
 	acc                        *mon.BoundAccount
 	forceProductionKVBatchSize bool
 	requestAdmissionHeader     roachpb.AdmissionHeader
@@ -260,7 +262,7 @@ func newKVBatchFetcher(args kvBatchFetcherArgs) *txnKVFetcher {
 	return &txnKVFetcher{
 		sendFn:                     args.sendFn,
 		reverse:                    args.reverse,
-		lockStrength:               getKeyLockingStrength(args.lockStrength),
+		lockStrength:               getKeyLockingStrength(args.lockStrength, args.isoLevel),
 		lockWaitPolicy:             getWaitPolicy(args.lockWaitPolicy),
 		lockTimeout:                args.lockTimeout,
 		acc:                        args.acc,
@@ -116,6 +116,7 @@ func NewKVFetcher(
 		// this check.
 		fetcherArgs.requestAdmissionHeader = txn.AdmissionHeader()
 		fetcherArgs.responseAdmissionQ = txn.DB().SQLKVResponseAdmissionQ
+		fetcherArgs.isoLevel = txn.IsoLevel()
 	}
 	return newKVFetcher(newKVBatchFetcher(fetcherArgs), &batchRequestsIssued)
 }
@@ -140,6 +141,7 @@ func NewStreamingKVFetcher(
 	kvFetcherMemAcc *mon.BoundAccount,
 ) *KVFetcher {
 	var batchRequestsIssued int64
+	keyLocking := getKeyLockingStrength(lockStrength, txn.IsoLevel())
 	streamer := kvstreamer.NewStreamer(
 		distSender,
 		stopper,
@@ -149,7 +151,7 @@ func NewStreamingKVFetcher(
 		streamerBudgetLimit,
 		streamerBudgetAcc,
 		&batchRequestsIssued,
-		getKeyLockingStrength(lockStrength),
+		keyLocking,
 	)
 	mode := kvstreamer.OutOfOrder
 	if maintainOrdering {
@@ -164,7 +166,7 @@ func NewStreamingKVFetcher(
 		maxKeysPerRow,
 		diskBuffer,
 	)
-	return newKVFetcher(newTxnKVStreamer(streamer, lockStrength, kvFetcherMemAcc), &batchRequestsIssued)
+	return newKVFetcher(newTxnKVStreamer(streamer, keyLocking, kvFetcherMemAcc), &batchRequestsIssued)
 }
 
 func newKVFetcher(batchFetcher KVBatchFetcher, batchRequestsIssued *int64) *KVFetcher {
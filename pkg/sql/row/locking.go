@@ -11,14 +11,18 @@
 package row
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/errors"
 )
 
 // getKeyLockingStrength returns the configured per-key locking strength to use
-// for key-value scans.
-func getKeyLockingStrength(lockStrength descpb.ScanLockingStrength) lock.Strength {
+// for key-value scans performed by a transaction with the given isolation
+// level.
+func getKeyLockingStrength(
+	lockStrength descpb.ScanLockingStrength, isoLevel isolation.Level,
+) lock.Strength {
 	switch lockStrength {
 	case descpb.ScanLockingStrength_FOR_NONE:
 		return lock.None
@@ -27,8 +31,22 @@ func getKeyLockingStrength(lockStrength descpb.ScanLockingStrength) lock.Strengt
 		// Promote to FOR_SHARE.
 		fallthrough
 	case descpb.ScanLockingStrength_FOR_SHARE:
-		// We currently perform no per-key locking when FOR_SHARE is used
-		// because Shared locks have not yet been implemented.
+		// Shared locks have not yet been implemented. Under serializable
+		// isolation, we perform no per-key locking when FOR_SHARE is used, as
+		// the transaction's reads are validated at commit time anyway. Under
+		// weaker isolation levels, the reads are not validated, so we promote
+		// to exclusive per-key locking to prevent concurrent writes to the
+		// locked keys.
+		//
+		// Note that this protection is best-effort. The promoted locks are
+		// unreplicated, so they are only held in memory by the leaseholder of
+		// the range and are lost if the lease is transferred, the range is
+		// split or merged, or the leaseholder restarts. A concurrent transaction
+		// could then write to the keys, which would not be detected, as the
+		// reads are not validated at commit time under these isolation levels.
+		if isoLevel.ToleratesWriteSkew() {
+			return lock.Exclusive
+		}
 		return lock.None
 
 	case descpb.ScanLockingStrength_FOR_NO_KEY_UPDATE:
@@ -37,7 +37,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/unique"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -523,6 +525,9 @@ func EncodeInvertedIndexKeys(
 	if !indexGeoConfig.IsEmpty() {
 		return EncodeGeoInvertedIndexTableKeys(val, keyPrefix, indexGeoConfig)
 	}
+	if indexVectorConfig := index.GetVectorConfig(); !indexVectorConfig.IsEmpty() {
+		return EncodeVectorInvertedIndexTableKeys(val, keyPrefix, indexVectorConfig)
+	}
 	return EncodeInvertedIndexTableKeys(val, keyPrefix, index.GetVersion())
 }
 
@@ -588,6 +593,8 @@ func EncodeInvertedIndexTableKeys(
 		// We pad the keys when writing them to the index.
 		// TODO(jordan): why are we doing this padding at all? Postgres does it.
 		return encodeTrigramInvertedIndexTableKeys(string(*val.(*tree.DString)), inKey, version, true /* pad */)
+	case types.TSVectorFamily:
+		return tsearch.EncodeInvertedIndexKeys(inKey, val.(*tree.DTSVector).TSVector), nil
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType())
 }
@@ -972,6 +979,22 @@ func EncodeGeoInvertedIndexTableKeys(
 	}
 }
 
+// EncodeVectorInvertedIndexTableKeys produces the inverted index key of a
+// vector, which is the key of the list whose centroid is nearest to it. Each
+// vector has exactly one key, prefixed by inKey.
+func EncodeVectorInvertedIndexTableKeys(
+	val tree.Datum, inKey []byte, indexVectorConfig vecindex.Config,
+) (key [][]byte, err error) {
+	if val == tree.DNull {
+		return nil, nil
+	}
+	k, err := indexVectorConfig.EncodeInvertedIndexKey(inKey, tree.MustBeDPGVector(val).T)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{k}, nil
+}
+
 func encodeGeoKeys(
 	inKey []byte, geoKeys []geoindex.Key, bbox geopb.BoundingBox,
 ) (keys [][]byte, err error) {
@@ -116,14 +116,14 @@ func Decode(
 		}
 		d, err := a.NewDCollatedString(r, valType.Locale())
 		return d, rkey, err
-	case types.JsonFamily:
-		// Don't attempt to decode the JSON value. Instead, just return the
-		// remaining bytes of the key.
-		jsonLen, err := encoding.PeekLength(key)
+	case types.JsonFamily, types.TSVectorFamily:
+		// Don't attempt to decode the inverted index key. Instead, just return
+		// the remaining bytes of the key.
+		keyLen, err := encoding.PeekLength(key)
 		if err != nil {
 			return nil, nil, err
 		}
-		return tree.DNull, key[jsonLen:], nil
+		return tree.DNull, key[keyLen:], nil
 	case types.BytesFamily:
 		var r []byte
 		if dir == encoding.Ascending {
@@ -274,6 +274,8 @@ func Decode(
 			return nil, nil, err
 		}
 		return a.NewDEnum(tree.DEnum{EnumTyp: valType, PhysicalRep: phys, LogicalRep: log}), rkey, nil
+	case types.RangeFamily:
+		return decodeRangeKey(a, valType, key, dir)
 	case types.EncodedKeyFamily:
 		// We don't actually decode anything; we wrap the raw key bytes into a
 		// DEncodedKey.
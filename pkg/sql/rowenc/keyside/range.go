@@ -0,0 +1,154 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package keyside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// The markers below are used within the key encoding of a range. They are
+// chosen so that the byte-wise ordering of encoded ranges matches the
+// ordering of tree.DRange.
+const (
+	rangeKeyEmpty    byte = 0
+	rangeKeyNonEmpty byte = 1
+
+	// The lower bound sorts -infinity first and, for equal values, inclusive
+	// bounds before exclusive ones.
+	rangeKeyLowerInf       byte = 0
+	rangeKeyLowerFinite    byte = 1
+	rangeKeyLowerInclusive byte = 0
+	rangeKeyLowerExclusive byte = 1
+
+	// The upper bound sorts +infinity last and, for equal values, exclusive
+	// bounds before inclusive ones.
+	rangeKeyUpperFinite    byte = 0
+	rangeKeyUpperInf       byte = 1
+	rangeKeyUpperExclusive byte = 0
+	rangeKeyUpperInclusive byte = 1
+)
+
+// encodeRangeKey generates an ordered key encoding of a range. The bounds of
+// the range and the markers describing them are first encoded in ascending
+// order as follows:
+// [rangeKeyNonEmpty, rangeKeyLowerFinite, enc(lower), rangeKeyLowerInclusive,
+//  rangeKeyUpperFinite, enc(upper), rangeKeyUpperExclusive].
+// An empty range is encoded as [rangeKeyEmpty], and infinite bounds omit the
+// encoded value and the inclusivity marker. The result is then wrapped in a
+// byte string encoded in the requested direction.
+func encodeRangeKey(b []byte, r *tree.DRange, dir encoding.Direction) ([]byte, error) {
+	var inner []byte
+	if r.Empty {
+		inner = append(inner, rangeKeyEmpty)
+	} else {
+		var err error
+		inner = append(inner, rangeKeyNonEmpty)
+		if r.Lower == tree.DNull {
+			inner = append(inner, rangeKeyLowerInf)
+		} else {
+			inner = append(inner, rangeKeyLowerFinite)
+			if inner, err = Encode(inner, r.Lower, encoding.Ascending); err != nil {
+				return nil, err
+			}
+			if r.LowerInc {
+				inner = append(inner, rangeKeyLowerInclusive)
+			} else {
+				inner = append(inner, rangeKeyLowerExclusive)
+			}
+		}
+		if r.Upper == tree.DNull {
+			inner = append(inner, rangeKeyUpperInf)
+		} else {
+			inner = append(inner, rangeKeyUpperFinite)
+			if inner, err = Encode(inner, r.Upper, encoding.Ascending); err != nil {
+				return nil, err
+			}
+			if r.UpperInc {
+				inner = append(inner, rangeKeyUpperInclusive)
+			} else {
+				inner = append(inner, rangeKeyUpperExclusive)
+			}
+		}
+	}
+	if dir == encoding.Ascending {
+		return encoding.EncodeBytesAscending(b, inner), nil
+	}
+	return encoding.EncodeBytesDescending(b, inner), nil
+}
+
+// decodeRangeKey decodes a range key generated by encodeRangeKey.
+func decodeRangeKey(
+	a *tree.DatumAlloc, t *types.T, buf []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	var inner []byte
+	var err error
+	if dir == encoding.Ascending {
+		buf, inner, err = encoding.DecodeBytesAscending(buf, nil)
+	} else {
+		buf, inner, err = encoding.DecodeBytesDescending(buf, nil)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	marker := func() (byte, error) {
+		if len(inner) == 0 {
+			return 0, errors.AssertionFailedf("range key is truncated")
+		}
+		m := inner[0]
+		inner = inner[1:]
+		return m, nil
+	}
+
+	m, err := marker()
+	if err != nil {
+		return nil, nil, err
+	}
+	if m == rangeKeyEmpty {
+		return tree.NewDEmptyRange(t), buf, nil
+	}
+	lower, upper := tree.Datum(tree.DNull), tree.Datum(tree.DNull)
+	var lowerInc, upperInc bool
+	if m, err = marker(); err != nil {
+		return nil, nil, err
+	}
+	if m == rangeKeyLowerFinite {
+		if lower, inner, err = Decode(a, t.RangeContents(), inner, encoding.Ascending); err != nil {
+			return nil, nil, err
+		}
+		if m, err = marker(); err != nil {
+			return nil, nil, err
+		}
+		lowerInc = m == rangeKeyLowerInclusive
+	}
+	if m, err = marker(); err != nil {
+		return nil, nil, err
+	}
+	if m == rangeKeyUpperFinite {
+		if upper, inner, err = Decode(a, t.RangeContents(), inner, encoding.Ascending); err != nil {
+			return nil, nil, err
+		}
+		if m, err = marker(); err != nil {
+			return nil, nil, err
+		}
+		upperInc = m == rangeKeyUpperInclusive
+	}
+	if len(inner) != 0 {
+		return nil, nil, errors.AssertionFailedf("%d trailing bytes in range key", len(inner))
+	}
+	r, err := tree.NewDRange(t, lower, upper, lowerInc, upperInc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, buf, nil
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -233,6 +235,9 @@ func DatumTypeToArrayElementEncodingType(t *types.T) (encoding.Type, error) {
 		return encoding.IPAddr, nil
 	case types.JsonFamily:
 		return encoding.JSON, nil
+	case types.JsonpathFamily, types.TSQueryFamily, types.TSVectorFamily, types.PGVectorFamily,
+		types.RangeFamily:
+		return encoding.Bytes, nil
 	case types.TupleFamily:
 		return encoding.Tuple, nil
 	default:
@@ -313,6 +318,20 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 			return nil, err
 		}
 		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
+	case *tree.DJsonpath:
+		return encoding.EncodeUntaggedBytesValue(b, []byte(t.Jsonpath.String())), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeUntaggedBytesValue(b, tsearch.EncodeTSQuery(nil, t.TSQuery)), nil
+	case *tree.DTSVector:
+		return encoding.EncodeUntaggedBytesValue(b, tsearch.EncodeTSVector(nil, t.TSVector)), nil
+	case *tree.DPGVector:
+		return encoding.EncodeUntaggedBytesValue(b, vector.Encode(nil, t.T)), nil
+	case *tree.DRange:
+		encoded, err := encodeRange(t, nil /* scratch */)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
 	case *tree.DTuple:
 		return encodeUntaggedTuple(t, b, encoding.NoColumnID, nil)
 	default:
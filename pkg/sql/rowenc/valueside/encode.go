@@ -16,6 +16,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 )
 
@@ -81,6 +83,23 @@ func Encode(appendTo []byte, colID ColumnIDDelta, val tree.Datum, scratch []byte
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DJsonpath:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Jsonpath.String())), nil
+	case *tree.DTSQuery:
+		encoded := tsearch.EncodeTSQuery(scratch, t.TSQuery)
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DTSVector:
+		encoded := tsearch.EncodeTSVector(scratch, t.TSVector)
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DPGVector:
+		encoded := vector.Encode(scratch, t.T)
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DRange:
+		encoded, err := encodeRange(t, scratch)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encoded), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -0,0 +1,66 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package valueside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// encodeRange produces the contents of the value encoding of a range, which
+// is the flags byte of the range followed by the value encodings of its
+// finite bounds. The flags determine which of the bounds are present.
+func encodeRange(r *tree.DRange, scratch []byte) ([]byte, error) {
+	scratch = append(scratch[:0], r.Flags())
+	if r.Empty {
+		return scratch, nil
+	}
+	var err error
+	for _, bound := range [...]tree.Datum{r.Lower, r.Upper} {
+		if bound == tree.DNull {
+			continue
+		}
+		scratch, err = Encode(scratch, NoColumnID, bound, nil /* scratch */)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return scratch, nil
+}
+
+// decodeRange decodes a range from the contents of its value encoding. It is
+// the counterpart of encodeRange().
+func decodeRange(a *tree.DatumAlloc, rangeTyp *types.T, b []byte) (*tree.DRange, error) {
+	if len(b) == 0 {
+		return nil, errors.AssertionFailedf("missing flags in encoded range")
+	}
+	flags := b[0]
+	b = b[1:]
+	lower, upper := tree.Datum(tree.DNull), tree.Datum(tree.DNull)
+	if flags&tree.RangeEmpty == 0 {
+		var err error
+		if flags&tree.RangeLowerInf == 0 {
+			if lower, b, err = Decode(a, rangeTyp.RangeContents(), b); err != nil {
+				return nil, err
+			}
+		}
+		if flags&tree.RangeUpperInf == 0 {
+			if upper, b, err = Decode(a, rangeTyp.RangeContents(), b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(b) != 0 {
+		return nil, errors.AssertionFailedf("%d trailing bytes in encoded range", len(b))
+	}
+	return tree.NewDRangeFromFlags(rangeTyp, flags, lower, upper)
+}
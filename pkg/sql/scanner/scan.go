@@ -279,6 +279,12 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			lval.SetID(lexbase.NOT_EQUALS)
 			return
 		case '=': // <=
+			if s.peekN(1) == '>' {
+				// <=>
+				s.pos += 2
+				lval.SetID(lexbase.COS_DISTANCE)
+				return
+			}
 			s.pos++
 			lval.SetID(lexbase.LESS_EQUALS)
 			return
@@ -286,6 +292,18 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.CONTAINED_BY)
 			return
+		case '-': // <->
+			if s.peekN(1) == '>' {
+				s.pos += 2
+				lval.SetID(lexbase.DISTANCE)
+				return
+			}
+		case '#': // <#>
+			if s.peekN(1) == '>' {
+				s.pos += 2
+				lval.SetID(lexbase.NEG_INNER_PRODUCT)
+				return
+			}
 		}
 		return
 
@@ -366,6 +384,14 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.CONTAINS)
 			return
+		case '@': // @@
+			s.pos++
+			lval.SetID(lexbase.AT_AT)
+			return
+		case '?': // @?
+			s.pos++
+			lval.SetID(lexbase.JSON_PATH_EXISTS)
+			return
 		}
 		return
 
@@ -380,6 +406,12 @@ func (s *Scanner) Scan(lval ScanSymType) {
 
 	case '-':
 		switch s.peek() {
+		case '|': // -|-
+			if s.peekN(1) == '-' {
+				s.pos += 2
+				lval.SetID(lexbase.ADJACENT)
+				return
+			}
 		case '>': // ->
 			if s.peekN(1) == '>' {
 				// ->>
@@ -419,6 +419,12 @@ func (sc *SchemaChanger) maybeBackfillMaterializedView(
 	if !(table.Adding() && table.MaterializedView()) {
 		return nil
 	}
+	// Incrementally refreshed materialized views are populated once they are
+	// public (see maybePopulateIncrementalMaterializedView).
+	if table.GetIncrementalRefresh() != nil {
+		log.Infof(ctx, "skipping backfill for incrementally refreshed materialized view %s", table.GetName())
+		return nil
+	}
 	// If materialized view is created with the WITH NO DATA option, skip backfill of the view
 	// during creation. The RefreshViewRequired field within the table descriptor indicates
 	// that a materialized view has been created with no data and a REFRESH VIEW operation needs
@@ -454,6 +460,62 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 	})
 }
 
+// maybePopulateIncrementalMaterializedView populates an incrementally
+// refreshed materialized view which has been made public. The references to
+// the view in the tables it depends on are first marked as incrementally
+// refreshed, so that the mutations of these tables start maintaining the view
+// once the new versions of their descriptors are in use. The view is then
+// populated in a single transaction, which is serialized with these mutations.
+func (sc *SchemaChanger) maybePopulateIncrementalMaterializedView(
+	ctx context.Context, table catalog.TableDescriptor,
+) error {
+	if !table.MaterializedView() || table.GetIncrementalRefresh() == nil ||
+		!table.IsRefreshViewRequired() || table.Dropped() {
+		return nil
+	}
+	log.Infof(ctx, "populating incrementally refreshed materialized view with query %q", table.GetViewQuery())
+
+	if err := sc.txn(ctx, func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		for _, source := range table.GetIncrementalRefresh().Sources {
+			mut, err := descsCol.GetMutableTableVersionByID(ctx, source.TableID, txn)
+			if err != nil {
+				return err
+			}
+			changed := false
+			for i := range mut.DependedOnBy {
+				if ref := &mut.DependedOnBy[i]; ref.ID == table.GetID() && !ref.IncrementalRefresh {
+					ref.IncrementalRefresh = true
+					changed = true
+				}
+			}
+			if changed {
+				if err := descsCol.WriteDesc(ctx, true /* kvTrace */, mut, txn); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return sc.txn(ctx, func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		mut, err := descsCol.GetMutableTableVersionByID(ctx, table.GetID(), txn)
+		if err != nil {
+			return err
+		}
+		if mut.Dropped() || !mut.IsRefreshViewRequired() {
+			return nil
+		}
+		if err := populateIncrementalMaterializedView(ctx, sc.execCfg, txn, descsCol, mut); err != nil {
+			return err
+		}
+		mut.RefreshViewRequired = false
+		mut.LastRefreshTime = txn.ReadTimestamp()
+		return descsCol.WriteDesc(ctx, true /* kvTrace */, mut, txn)
+	})
+}
+
 // ignoreRevertedDropIndex finds all add index mutations that are the
 // result of a rollback and changes their direction to DROP.
 //
@@ -773,7 +835,7 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 		}
 		// Some descriptors should be deleted if they are in the DROP state.
 		switch desc.(type) {
-		case catalog.SchemaDescriptor, catalog.DatabaseDescriptor:
+		case catalog.SchemaDescriptor, catalog.DatabaseDescriptor, catalog.FunctionDescriptor:
 			if desc.Dropped() {
 				if err := sc.execCfg.DB.Del(ctx, catalogkeys.MakeDescMetadataKey(sc.execCfg.Codec, desc.GetID())); err != nil {
 					return err
@@ -831,10 +893,18 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 		return err
 	}
 
+	if err := sc.maybePopulateIncrementalMaterializedView(ctx, tableDesc); err != nil {
+		return err
+	}
+
 	if err := sc.maybeUpdateScheduledJobsForRowLevelTTL(ctx, tableDesc); err != nil {
 		return err
 	}
 
+	if err := sc.maybeValidateExclusionConstraints(ctx, tableDesc); err != nil {
+		return err
+	}
+
 	if sc.mutationID == descpb.InvalidMutationID {
 		// Nothing more to do.
 		isCreateTableAs := tableDesc.Adding() && tableDesc.IsAs()
@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -920,6 +921,18 @@ func (b *builderState) ensureDescriptor(id catid.DescID) {
 			}
 		}
 	case catalog.SchemaDescriptor:
+		// User-defined functions are not addressed by name, and are only
+		// dropped by the legacy schema changer.
+		hasFunctions := false
+		_ = d.ForEachFunctionOverload(func(string, descpb.SchemaDescriptor_Function_Overload) error {
+			hasFunctions = true
+			return iterutil.StopIteration()
+		})
+		if hasFunctions {
+			panic(scerrors.NotImplementedErrorf(nil, /* n */
+				"schema %q (%d) with user-defined functions",
+				d.GetName(), d.GetID()))
+		}
 		b.ensureDescriptor(c.desc.GetParentID())
 		db := b.descCache[c.desc.GetParentID()].desc
 		c.prefix.CatalogName = tree.Name(db.GetName())
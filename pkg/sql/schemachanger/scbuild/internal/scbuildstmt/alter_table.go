@@ -87,6 +87,10 @@ func AlterTable(b BuildCtx, n *tree.AlterTable) {
 		panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
 			"table %q is being dropped, try again later", n.Table.Object()))
 	}
+	if tbl.IsForeign {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot alter foreign table %q", n.Table.Object()))
+	}
 	tn.ObjectNamePrefix = b.NamePrefix(tbl)
 	b.SetUnresolvedNameAnnotation(n.Table, &tn)
 	b.IncrementSchemaChangeAlterCounter("table")
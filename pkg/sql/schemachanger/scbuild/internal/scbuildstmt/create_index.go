@@ -53,6 +53,10 @@ func CreateIndex(b BuildCtx, n *tree.CreateIndex) {
 			if descpb.IsVirtualTable(t.TableID) {
 				return
 			}
+			if t.IsForeign {
+				panic(pgerror.Newf(pgcode.WrongObjectType,
+					"cannot create index on foreign table %q", n.Table.ObjectName))
+			}
 			index.TableID = t.TableID
 			relation = e
 
@@ -165,6 +169,13 @@ func CreateIndex(b BuildCtx, n *tree.CreateIndex) {
 		if columnID == 0 {
 			panic(colinfo.NewUndefinedColumnError(colName))
 		}
+		if n.Inverted && i == len(n.Columns)-1 {
+			scpb.ForEachColumnType(relationElements, func(_ scpb.Status, target scpb.TargetStatus, col *scpb.ColumnType) {
+				if target == scpb.ToPublic && col.ColumnID == columnID && col.Type.Family() == types.PGVectorFamily {
+					panic(scerrors.NotImplementedErrorf(n, "ivfflat indexes are not supported"))
+				}
+			})
+		}
 		keyColNames[i] = colName
 		direction := catpb.IndexColumn_ASC
 		if columnNode.Direction == tree.Descending {
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
 )
 
 // DropTable implements DROP TABLE.
@@ -44,6 +45,13 @@ func DropTable(b BuildCtx, n *tree.DropTable) {
 		if tbl.IsTemporary {
 			panic(scerrors.NotImplementedErrorf(n, "dropping a temporary table"))
 		}
+		if tbl.IsForeign && !n.IsForeign {
+			panic(errors.WithHint(pgerror.Newf(pgcode.WrongObjectType, "%q is a foreign table", name.ObjectName),
+				"use the corresponding FOREIGN TABLE command"))
+		}
+		if !tbl.IsForeign && n.IsForeign {
+			panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a foreign table", name.ObjectName))
+		}
 		// Only decompose the tables first into elements, next we will check for
 		// dependent objects, in case they are all dropped *together*.
 		if n.DropBehavior == tree.DropCascade {
@@ -68,7 +76,11 @@ func DropTable(b BuildCtx, n *tree.DropTable) {
 			}
 		}
 		b.IncrementSubWorkID()
-		b.IncrementSchemaChangeDropCounter("table")
+		if tbl.IsForeign {
+			b.IncrementSchemaChangeDropCounter("foreign_table")
+		} else {
+			b.IncrementSchemaChangeDropCounter("table")
+		}
 	}
 	// Check if there are any back-references which would prevent a DROP RESTRICT.
 	for _, tableID := range toCheckBackrefs {
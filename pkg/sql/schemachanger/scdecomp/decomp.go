@@ -250,6 +250,7 @@ func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
 		w.ev(descriptorStatus(tbl), &scpb.Table{
 			TableID:     tbl.GetID(),
 			IsTemporary: tbl.IsTemporary(),
+			IsForeign:   tbl.IsForeign(),
 		})
 	}
 
@@ -41,6 +41,10 @@ func init() {
 	initGeneratorBuiltins()
 	initGeoBuiltins()
 	initTrigramBuiltins()
+	initTSearchBuiltins()
+	initRangeBuiltins()
+	initJsonpathBuiltins()
+	initPGVectorBuiltins()
 	initPGBuiltins()
 	initMathBuiltins()
 	initOverlapsBuiltins()
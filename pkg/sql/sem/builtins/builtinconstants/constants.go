@@ -50,12 +50,14 @@ const (
 	CategoryJSON                = "JSONB"
 	CategoryMultiRegion         = "Multi-region"
 	CategoryMultiTenancy        = "Multi-tenancy"
+	CategoryRange               = "Range"
 	CategorySequences           = "Sequence"
 	CategorySpatial             = "Spatial"
 	CategoryString              = "String and byte"
 	CategorySystemInfo          = "System info"
 	CategorySystemRepair        = "System repair"
 	CategoryStreamIngestion     = "Stream Ingestion"
+	CategoryVector              = "Vector"
 )
 
 const (
@@ -0,0 +1,231 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+func initJsonpathBuiltins() {
+	for k, v := range jsonpathBuiltins {
+		v.props.Category = builtinconstants.CategoryJSON
+		v.props.AvailableOnPublicSchema = true
+		registerBuiltin(k, v)
+	}
+}
+
+var jsonpathBuiltins = map[string]builtinDefinition{
+	"jsonb_path_exists": makeBuiltin(tree.FunctionProperties{},
+		makeJsonpathOverloads(types.Bool, jsonpathExists,
+			"Returns whether the path returns any item for the target JSON value.")...,
+	),
+	"jsonb_path_match": makeBuiltin(tree.FunctionProperties{},
+		makeJsonpathOverloads(types.Bool, jsonpathMatch,
+			"Returns the result of the path predicate for the target JSON value. "+
+				"Only the first item of the result is taken into account. If the "+
+				"result is not a boolean, then NULL is returned.")...,
+	),
+	"jsonb_path_query_array": makeBuiltin(tree.FunctionProperties{},
+		makeJsonpathOverloads(types.Jsonb, jsonpathQueryArray,
+			"Returns all items returned by the path for the target JSON value, "+
+				"wrapped in a JSON array.")...,
+	),
+	"jsonb_path_query_first": makeBuiltin(tree.FunctionProperties{},
+		makeJsonpathOverloads(types.Jsonb, jsonpathQueryFirst,
+			"Returns the first item returned by the path for the target JSON value, "+
+				"or NULL if there are no results.")...,
+	),
+	"jsonb_path_exists_opr": makeBuiltin(tree.FunctionProperties{},
+		makeJsonpathOperatorOverload(jsonpathExists, "Implements the @? operator."),
+	),
+	"jsonb_path_match_opr": makeBuiltin(tree.FunctionProperties{},
+		makeJsonpathOperatorOverload(jsonpathMatch, "Implements the @@ operator."),
+	),
+}
+
+// jsonpathFn is the implementation of a jsonb_path_* builtin. vars is nil if
+// the builtin was called without variables.
+type jsonpathFn func(
+	target json.JSON, path *jsonpath.Jsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error)
+
+// jsonpathArgs are the arguments of the jsonb_path_* builtins. The vars and
+// silent arguments are optional.
+var jsonpathArgs = tree.ArgTypes{
+	{"target", types.Jsonb},
+	{"path", types.Jsonpath},
+	{"vars", types.Jsonb},
+	{"silent", types.Bool},
+}
+
+const jsonpathSilentInfo = "\n\nIf vars is specified, it must be an object whose fields " +
+	"provide the values of the variables referenced by the path. If silent is " +
+	"true, errors caused by the contents of the target JSON value (such as " +
+	"missing keys in strict mode) are suppressed."
+
+// makeJsonpathOverloads returns the overloads of a jsonb_path_* builtin that
+// takes a target, a path and optionally vars and silent arguments.
+func makeJsonpathOverloads(ret *types.T, fn jsonpathFn, info string) []tree.Overload {
+	overloads := make([]tree.Overload, 0, 3)
+	for n := 2; n <= len(jsonpathArgs); n++ {
+		overloads = append(overloads, tree.Overload{
+			Types:      jsonpathArgs[:n],
+			ReturnType: tree.FixedReturnType(ret),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				target, path, vars, silent := unpackJsonpathArgs(args)
+				return fn(target, path, vars, silent)
+			},
+			Info:       info + jsonpathSilentInfo,
+			Volatility: volatility.Immutable,
+		})
+	}
+	return overloads
+}
+
+// makeJsonpathOperatorOverload returns the overload of a builtin that
+// implements a jsonpath operator, which always suppresses errors.
+func makeJsonpathOperatorOverload(fn jsonpathFn, info string) tree.Overload {
+	return tree.Overload{
+		Types:      jsonpathArgs[:2],
+		ReturnType: tree.FixedReturnType(types.Bool),
+		Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+			target, path, _, _ := unpackJsonpathArgs(args)
+			return fn(target, path, nil /* vars */, true /* silent */)
+		},
+		Info:       info,
+		Volatility: volatility.Immutable,
+	}
+}
+
+func unpackJsonpathArgs(
+	args tree.Datums,
+) (target json.JSON, path *jsonpath.Jsonpath, vars json.JSON, silent bool) {
+	target = tree.MustBeDJSON(args[0]).JSON
+	path = tree.MustBeDJsonpath(args[1]).Jsonpath
+	if len(args) > 2 {
+		vars = tree.MustBeDJSON(args[2]).JSON
+	}
+	if len(args) > 3 {
+		silent = bool(tree.MustBeDBool(args[3]))
+	}
+	return target, path, vars, silent
+}
+
+func jsonpathExists(
+	target json.JSON, path *jsonpath.Jsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error) {
+	exists, ok, err := jsonpath.Exists(path, target, vars, silent)
+	if err != nil || !ok {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(exists)), nil
+}
+
+func jsonpathMatch(
+	target json.JSON, path *jsonpath.Jsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error) {
+	match, ok, err := jsonpath.Match(path, target, vars, silent)
+	if err != nil || !ok {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(match)), nil
+}
+
+func jsonpathQueryArray(
+	target json.JSON, path *jsonpath.Jsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error) {
+	// Like Postgres, return an empty array rather than NULL if an error was
+	// suppressed.
+	res, _, err := jsonpath.Query(path, target, vars, silent)
+	if err != nil {
+		return nil, err
+	}
+	b := json.NewArrayBuilder(len(res))
+	for _, j := range res {
+		b.Add(j)
+	}
+	return tree.NewDJSON(b.Build()), nil
+}
+
+func jsonpathQueryFirst(
+	target json.JSON, path *jsonpath.Jsonpath, vars json.JSON, silent bool,
+) (tree.Datum, error) {
+	res, _, err := jsonpath.Query(path, target, vars, silent)
+	if err != nil || len(res) == 0 {
+		return tree.DNull, err
+	}
+	return tree.NewDJSON(res[0]), nil
+}
+
+// jsonpathQueryImpls are the overloads of jsonb_path_query, which is a
+// generator and is therefore registered with the other generators.
+var jsonpathQueryImpls = func() []tree.Overload {
+	overloads := make([]tree.Overload, 0, 3)
+	for n := 2; n <= len(jsonpathArgs); n++ {
+		overloads = append(overloads, makeGeneratorOverload(
+			jsonpathArgs[:n],
+			types.Jsonb,
+			makeJsonpathQueryGenerator,
+			"Returns all items returned by the path for the target JSON value."+jsonpathSilentInfo,
+			volatility.Immutable,
+		))
+	}
+	return overloads
+}()
+
+// jsonpathQueryGenerator supports jsonb_path_query.
+type jsonpathQueryGenerator struct {
+	res       []json.JSON
+	nextIndex int
+}
+
+var _ eval.ValueGenerator = &jsonpathQueryGenerator{}
+
+func makeJsonpathQueryGenerator(_ *eval.Context, args tree.Datums) (eval.ValueGenerator, error) {
+	target, path, vars, silent := unpackJsonpathArgs(args)
+	res, _, err := jsonpath.Query(path, target, vars, silent)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonpathQueryGenerator{res: res}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) ResolvedType() *types.T { return types.Jsonb }
+
+// Start implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Start(_ context.Context, _ *kv.Txn) error {
+	g.nextIndex = -1
+	return nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Next(_ context.Context) (bool, error) {
+	g.nextIndex++
+	return g.nextIndex < len(g.res), nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Values() (tree.Datums, error) {
+	return tree.Datums{tree.NewDJSON(g.res[g.nextIndex])}, nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Close(_ context.Context) {}
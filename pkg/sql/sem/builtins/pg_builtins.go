@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
@@ -532,6 +533,110 @@ func makeToRegOverload(typ *types.T, helpText string) builtinDefinition {
 	)
 }
 
+// makeAdvisoryLockBuiltin creates an advisory lock builtin, which takes either
+// an int8 key or a pair of int4 keys. The two kinds of keys never conflict.
+func makeAdvisoryLockBuiltin(
+	retType *types.T, info string, fn func(*eval.Context, eval.AdvisoryLock) (tree.Datum, error),
+) builtinDefinition {
+	return makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key", types.Int}},
+			ReturnType: tree.FixedReturnType(retType),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(ctx, eval.AdvisoryLock{Key: int64(tree.MustBeDInt(args[0]))})
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key1", types.Int4}, {"key2", types.Int4}},
+			ReturnType: tree.FixedReturnType(retType),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				key1, key2 := int64(tree.MustBeDInt(args[0])), int64(tree.MustBeDInt(args[1]))
+				return fn(ctx, eval.AdvisoryLock{Key: key1<<32 | int64(uint32(key2)), KeyPair: true})
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+	)
+}
+
+// advisoryLockDescription describes an advisory lock for the builtin docs.
+func advisoryLockDescription(shared, xact bool) string {
+	mode, scope := "an exclusive", "session-level"
+	if shared {
+		mode = "a shared"
+	}
+	if xact {
+		scope = "transaction-level"
+	}
+	return mode + " " + scope + " advisory lock"
+}
+
+// advisoryLockModeName returns the name of the mode of an advisory lock used
+// by Postgres.
+func advisoryLockModeName(shared bool) string {
+	if shared {
+		return "ShareLock"
+	}
+	return "ExclusiveLock"
+}
+
+// makeAcquireAdvisoryLockBuiltin creates a builtin which acquires an advisory
+// lock. If wait is set, the builtin returns void once the lock is acquired,
+// otherwise it returns whether the lock was acquired.
+func makeAcquireAdvisoryLockBuiltin(shared, xact, wait bool) builtinDefinition {
+	retType := types.Void
+	info := "Obtains " + advisoryLockDescription(shared, xact) +
+		", waiting if the lock is held by another session."
+	if !wait {
+		retType = types.Bool
+		info = "Obtains " + advisoryLockDescription(shared, xact) + " if it is " +
+			"available. Returns false without waiting if the lock is held by " +
+			"another session."
+	}
+	if xact {
+		info += " The lock is released when the current transaction ends."
+	}
+	return makeAdvisoryLockBuiltin(retType, info,
+		func(ctx *eval.Context, lock eval.AdvisoryLock) (tree.Datum, error) {
+			lock.Shared, lock.Xact = shared, xact
+			acquired, err := ctx.Planner.AcquireAdvisoryLock(ctx.Context, lock, wait)
+			if err != nil {
+				return nil, err
+			}
+			if wait {
+				return tree.DVoidDatum, nil
+			}
+			return tree.MakeDBool(tree.DBool(acquired)), nil
+		},
+	)
+}
+
+// makeAdvisoryUnlockBuiltin creates a builtin which releases a session-level
+// advisory lock.
+func makeAdvisoryUnlockBuiltin(shared bool) builtinDefinition {
+	info := "Releases " + advisoryLockDescription(shared, false /* xact */) +
+		" held by the current session. Returns false, with a warning, if the " +
+		"lock is not held."
+	return makeAdvisoryLockBuiltin(types.Bool, info,
+		func(ctx *eval.Context, lock eval.AdvisoryLock) (tree.Datum, error) {
+			lock.Shared = shared
+			released, err := ctx.Planner.ReleaseAdvisoryLock(ctx.Context, lock)
+			if err != nil {
+				return nil, err
+			}
+			if !released {
+				ctx.ClientNoticeSender.BufferClientNotice(ctx.Context, pgnotice.NewWithSeverityf(
+					"WARNING", "you don't own a lock of type %s", advisoryLockModeName(shared),
+				))
+			}
+			return tree.MakeDBool(tree.DBool(released)), nil
+		},
+	)
+}
+
 var pgBuiltins = map[string]builtinDefinition{
 	// See https://www.postgresql.org/docs/9.6/static/functions-info.html.
 	"pg_backend_pid": makeBuiltin(defProps(),
@@ -1083,26 +1188,31 @@ SELECT description
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-	),
-
-	"pg_advisory_unlock": makeBuiltin(defProps(),
+	// See https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADVISORY-LOCKS.
+	"pg_advisory_lock":                 makeAcquireAdvisoryLockBuiltin(false /* shared */, false /* xact */, true /* wait */),
+	"pg_advisory_lock_shared":          makeAcquireAdvisoryLockBuiltin(true /* shared */, false /* xact */, true /* wait */),
+	"pg_advisory_xact_lock":            makeAcquireAdvisoryLockBuiltin(false /* shared */, true /* xact */, true /* wait */),
+	"pg_advisory_xact_lock_shared":     makeAcquireAdvisoryLockBuiltin(true /* shared */, true /* xact */, true /* wait */),
+	"pg_try_advisory_lock":             makeAcquireAdvisoryLockBuiltin(false /* shared */, false /* xact */, false /* wait */),
+	"pg_try_advisory_lock_shared":      makeAcquireAdvisoryLockBuiltin(true /* shared */, false /* xact */, false /* wait */),
+	"pg_try_advisory_xact_lock":        makeAcquireAdvisoryLockBuiltin(false /* shared */, true /* xact */, false /* wait */),
+	"pg_try_advisory_xact_lock_shared": makeAcquireAdvisoryLockBuiltin(true /* shared */, true /* xact */, false /* wait */),
+	"pg_advisory_unlock":               makeAdvisoryUnlockBuiltin(false /* shared */),
+	"pg_advisory_unlock_shared":        makeAdvisoryUnlockBuiltin(true /* shared */),
+
+	"pg_advisory_unlock_all": makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
 		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.Void),
+			Fn: func(ctx *eval.Context, _ tree.Datums) (tree.Datum, error) {
+				if err := ctx.Planner.ReleaseAllAdvisoryLocks(ctx.Context); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
 			},
-			Info:       notUsableInfo,
+			Info: "Releases all the session-level advisory locks held by the current " +
+				"session. The locks are also released when the session ends.",
 			Volatility: volatility.Volatile,
 		},
 	),
@@ -1261,6 +1371,33 @@ SELECT description
 		},
 	),
 
+	// See https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-NOTIFY.
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Void),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := ctx.Planner.SendNotification(ctx.Context, channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
+			},
+			Info: "Sends a notification with the given payload on a channel. The " +
+				"notification is delivered to the sessions listening on the channel " +
+				"when the current transaction commits.",
+			Volatility:   volatility.Volatile,
+			NullableArgs: true,
+		},
+	),
+
 	// pg_is_in_recovery returns true if the Postgres database is currently in
 	// recovery.  This is not applicable so this can always return false.
 	// https://www.postgresql.org/docs/current/static/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
@@ -0,0 +1,88 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+)
+
+func initPGVectorBuiltins() {
+	for k, v := range pgvectorBuiltins {
+		v.props.Category = builtinconstants.CategoryVector
+		v.props.AvailableOnPublicSchema = true
+		registerBuiltin(k, v)
+	}
+}
+
+var pgvectorBuiltins = map[string]builtinDefinition{
+	"l2_distance": makeBuiltin(tree.FunctionProperties{},
+		makeVectorDistanceOverload(vector.L2Distance,
+			"Returns the Euclidean distance between the two vectors. "+
+				"Equivalent to the <-> operator."),
+	),
+	"inner_product": makeBuiltin(tree.FunctionProperties{},
+		makeVectorDistanceOverload(vector.InnerProduct,
+			"Returns the inner product of the two vectors. The <#> operator "+
+				"returns the negative of this value."),
+	),
+	"cosine_distance": makeBuiltin(tree.FunctionProperties{},
+		makeVectorDistanceOverload(vector.CosDistance,
+			"Returns the cosine distance between the two vectors. "+
+				"Equivalent to the <=> operator."),
+	),
+	"vector_dims": makeBuiltin(tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.PGVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDPGVector(args[0])
+				return tree.NewDInt(tree.DInt(len(v.T))), nil
+			},
+			Info:       "Returns the number of dimensions of the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"vector_norm": makeBuiltin(tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.PGVector}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDPGVector(args[0])
+				return tree.NewDFloat(tree.DFloat(vector.Norm(v.T))), nil
+			},
+			Info:       "Returns the Euclidean norm of the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+}
+
+// makeVectorDistanceOverload returns the overload of a builtin that computes
+// a distance between two vectors with the given function.
+func makeVectorDistanceOverload(fn func(a, b vector.T) (float64, error), info string) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"v1", types.PGVector}, {"v2", types.PGVector}},
+		ReturnType: tree.FixedReturnType(types.Float),
+		Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+			d, err := fn(tree.MustBeDPGVector(args[0]).T, tree.MustBeDPGVector(args[1]).T)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDFloat(tree.DFloat(d)), nil
+		},
+		Info:       info,
+		Volatility: volatility.Immutable,
+	}
+}
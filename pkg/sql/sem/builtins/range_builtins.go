@@ -0,0 +1,160 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+func initRangeBuiltins() {
+	for k, v := range rangeBuiltins {
+		v.props.Category = builtinconstants.CategoryRange
+		v.props.AvailableOnPublicSchema = true
+		registerBuiltin(k, v)
+	}
+	// Each range type has a constructor function of the same name.
+	for _, t := range types.RangeTypes {
+		registerBuiltin(t.Name(), makeRangeConstructor(t))
+	}
+}
+
+var rangeBuiltins = map[string]builtinDefinition{
+	"isempty": makeRangePredicate(
+		func(r *tree.DRange) bool { return r.Empty },
+		"Returns whether the range is empty.",
+	),
+	"lower_inc": makeRangePredicate(
+		func(r *tree.DRange) bool { return r.LowerInc },
+		"Returns whether the lower bound of the range is inclusive.",
+	),
+	"upper_inc": makeRangePredicate(
+		func(r *tree.DRange) bool { return r.UpperInc },
+		"Returns whether the upper bound of the range is inclusive.",
+	),
+	"lower_inf": makeRangePredicate(
+		func(r *tree.DRange) bool { return !r.Empty && r.Lower == tree.DNull },
+		"Returns whether the lower bound of the range is infinite.",
+	),
+	"upper_inf": makeRangePredicate(
+		func(r *tree.DRange) bool { return !r.Empty && r.Upper == tree.DNull },
+		"Returns whether the upper bound of the range is infinite.",
+	),
+}
+
+// makeRangePredicate returns a builtin that evaluates f on its range argument,
+// with one overload for each range type.
+func makeRangePredicate(f func(r *tree.DRange) bool, info string) builtinDefinition {
+	overloads := make([]tree.Overload, len(types.RangeTypes))
+	for i, t := range types.RangeTypes {
+		overloads[i] = tree.Overload{
+			Types:      tree.ArgTypes{{"range", t}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.MakeDBool(tree.DBool(f(tree.MustBeDRange(args[0])))), nil
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		}
+	}
+	return makeBuiltin(tree.FunctionProperties{}, overloads...)
+}
+
+// withRangeBoundOverloads returns the string overload of lower() or upper()
+// followed by the overloads that return the lower or upper bound of a range,
+// which are NULL if the range is empty or the bound is infinite. The string
+// overload is preferred so that calls with NULL or placeholder arguments keep
+// resolving to it, as they do in Postgres.
+func withRangeBoundOverloads(stringOverload tree.Overload, lower bool) []tree.Overload {
+	info := "Returns the upper bound of the range, or NULL if it is empty or unbounded above."
+	if lower {
+		info = "Returns the lower bound of the range, or NULL if it is empty or unbounded below."
+	}
+	stringOverload.PreferredOverload = true
+	overloads := []tree.Overload{stringOverload}
+	for _, t := range types.RangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ArgTypes{{"range", t}},
+			ReturnType: tree.FixedReturnType(t.RangeContents()),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				r := tree.MustBeDRange(args[0])
+				if lower {
+					return r.Lower, nil
+				}
+				return r.Upper, nil
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		})
+	}
+	return overloads
+}
+
+// makeRangeConstructor returns the constructor function of the range type t,
+// which builds a range from its bounds. NULL bounds are infinite, and the
+// optional bounds argument determines which bounds are inclusive.
+func makeRangeConstructor(t *types.T) builtinDefinition {
+	elem := t.RangeContents()
+	return makeBuiltin(
+		tree.FunctionProperties{
+			Category:                builtinconstants.CategoryRange,
+			AvailableOnPublicSchema: true,
+		},
+		tree.Overload{
+			Types:        tree.ArgTypes{{"lower", elem}, {"upper", elem}},
+			ReturnType:   tree.FixedReturnType(t),
+			NullableArgs: true,
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDRange(t, args[0], args[1], true /* lowerInc */, false /* upperInc */)
+			},
+			Info: "Constructs a range from the given bounds, which are inclusive below " +
+				"and exclusive above. A NULL bound is infinite.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types:        tree.ArgTypes{{"lower", elem}, {"upper", elem}, {"bounds", types.String}},
+			ReturnType:   tree.FixedReturnType(t),
+			NullableArgs: true,
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[2] == tree.DNull {
+					return nil, pgerror.New(pgcode.DataException, "range constructor flags argument must not be null")
+				}
+				lowerInc, upperInc, err := parseRangeBoundsFlags(string(tree.MustBeDString(args[2])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDRange(t, args[0], args[1], lowerInc, upperInc)
+			},
+			Info: "Constructs a range from the given bounds. The `bounds` argument is " +
+				"one of '[]', '[)', '(]' or '()' and determines whether each bound is " +
+				"inclusive. A NULL bound is infinite.",
+			Volatility: volatility.Immutable,
+		},
+	)
+}
+
+// parseRangeBoundsFlags parses the bounds argument of a range constructor,
+// returning whether its lower and upper bounds are inclusive.
+func parseRangeBoundsFlags(s string) (lowerInc, upperInc bool, _ error) {
+	if len(s) == 2 && (s[0] == '[' || s[0] == '(') && (s[1] == ']' || s[1] == ')') {
+		return s[0] == '[', s[1] == ']', nil
+	}
+	return false, false, errors.WithHint(
+		pgerror.New(pgcode.Syntax, "invalid range bound flags"),
+		`Valid values are "[]", "[)", "(]", and "()".`,
+	)
+}
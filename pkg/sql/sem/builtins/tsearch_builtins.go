@@ -0,0 +1,350 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+func initTSearchBuiltins() {
+	for k, v := range tsearchBuiltins {
+		v.props.Category = builtinconstants.CategoryFullTextSearch
+		v.props.AvailableOnPublicSchema = true
+		registerBuiltin(k, v)
+	}
+}
+
+var tsearchBuiltins = map[string]builtinDefinition{
+	"to_tsvector": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := string(tree.MustBeDString(args[0]))
+				document := string(tree.MustBeDString(args[1]))
+				v, err := tsearch.ToTSVector(config, document)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(v), nil
+			},
+			Info: "Converts the document to a tsvector, normalizing its words into " +
+				"lexemes with the given text search configuration.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				document := string(tree.MustBeDString(args[0]))
+				v, err := tsearch.ToTSVector(defaultTSConfig(evalCtx), document)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(v), nil
+			},
+			Info: "Converts the document to a tsvector, normalizing its words into " +
+				"lexemes with the default_text_search_config text search configuration.",
+			Volatility: volatility.Stable,
+		},
+	),
+	"to_tsquery":       makeTSQueryBuiltin(tsearch.ToTSQuery, "Converts the input text, which must consist of lexemes separated by tsquery operators, to a tsquery"),
+	"plainto_tsquery":  makeTSQueryBuiltin(tsearch.PlainToTSQuery, "Converts the input text to a tsquery that matches all of its words"),
+	"phraseto_tsquery": makeTSQueryBuiltin(tsearch.PhraseToTSQuery, "Converts the input text to a tsquery that matches its words as a phrase"),
+	"ts_rank": makeBuiltin(
+		tree.FunctionProperties{},
+		makeTSRankOverload(false /* hasWeights */, false /* hasNormalization */),
+		makeTSRankOverload(false /* hasWeights */, true /* hasNormalization */),
+		makeTSRankOverload(true /* hasWeights */, false /* hasNormalization */),
+		makeTSRankOverload(true /* hasWeights */, true /* hasNormalization */),
+	),
+	"ts_match_vq": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDTSVector(args[0])
+				q := tree.MustBeDTSQuery(args[1])
+				return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(q.TSQuery, v.TSVector))), nil
+			},
+			Info:       "Returns whether the vector matches the query. Equivalent to vector @@ query.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"ts_match_qv": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}, {"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				q := tree.MustBeDTSQuery(args[0])
+				v := tree.MustBeDTSVector(args[1])
+				return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(q.TSQuery, v.TSVector))), nil
+			},
+			Info:       "Returns whether the vector matches the query. Equivalent to query @@ vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"tsvector_concat": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.TSVector}, {"right", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				l := tree.MustBeDTSVector(args[0])
+				r := tree.MustBeDTSVector(args[1])
+				return tree.NewDTSVector(l.TSVector.Concat(r.TSVector)), nil
+			},
+			Info:       "Concatenates two tsvectors. Equivalent to left || right.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"strip": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).TSVector.Strip()), nil
+			},
+			Info:       "Removes the positions and weights from the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"setweight": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"weight", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDTSVector(args[0])
+				ret, err := v.TSVector.SetWeight(string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(ret), nil
+			},
+			Info:       "Sets the weight of every position of the vector, which must be one of A, B, C or D.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"tsvector_to_array": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				lexemes := tree.MustBeDTSVector(args[0]).TSVector.Lexemes()
+				ret := tree.NewDArray(types.String)
+				ret.Array = make(tree.Datums, 0, len(lexemes))
+				for _, l := range lexemes {
+					if err := ret.Append(tree.NewDString(l)); err != nil {
+						return nil, err
+					}
+				}
+				return ret, nil
+			},
+			Info:       "Returns the lexemes of the vector as an array.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"numnode": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDInt(tree.DInt(tree.MustBeDTSQuery(args[0]).TSQuery.NumNodes())), nil
+			},
+			Info:       "Returns the number of lexemes and operators in the query.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"tsquery_phrase": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query1", types.TSQuery}, {"query2", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				q1 := tree.MustBeDTSQuery(args[0])
+				q2 := tree.MustBeDTSQuery(args[1])
+				ret, err := q1.TSQuery.FollowedBy(q2.TSQuery, 1 /* distance */)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(ret), nil
+			},
+			Info:       "Returns a query that matches query1 followed by query2. Equivalent to query1 <-> query2.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"query1", types.TSQuery},
+				{"query2", types.TSQuery},
+				{"distance", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				q1 := tree.MustBeDTSQuery(args[0])
+				q2 := tree.MustBeDTSQuery(args[1])
+				ret, err := q1.TSQuery.FollowedBy(q2.TSQuery, int(tree.MustBeDInt(args[2])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(ret), nil
+			},
+			Info: "Returns a query that matches query1 followed by query2 at exactly the " +
+				"given distance. Equivalent to query1 <distance> query2.",
+			Volatility: volatility.Immutable,
+		},
+	),
+
+	"array_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"get_current_ts_config":          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"json_to_tsvector":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"jsonb_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"querytree":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_debug":                       makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_delete":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_filter":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_headline":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_lexize":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_rank_cd":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"ts_rewrite":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"tsvector_cmp":                   makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"tsvector_update_trigger":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"tsvector_update_trigger_column": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+	"websearch_to_tsquery":           makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821}),
+}
+
+// defaultTSConfig returns the text search configuration used by the builtins
+// that aren't given one explicitly.
+func defaultTSConfig(evalCtx *eval.Context) string {
+	if config := evalCtx.SessionData().DefaultTextSearchConfig; config != "" {
+		return config
+	}
+	return tsearch.DefaultConfig
+}
+
+// makeTSQueryBuiltin returns a builtin with a variant that takes a text search
+// configuration and one that uses the default configuration, both of which
+// convert their input to a tsquery with the given function.
+func makeTSQueryBuiltin(
+	toTSQuery func(config string, input string) (tsearch.TSQuery, error), info string,
+) builtinDefinition {
+	return makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				q, err := toTSQuery(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(q), nil
+			},
+			Info:       info + ", normalizing its words with the given text search configuration.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				q, err := toTSQuery(defaultTSConfig(evalCtx), string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(q), nil
+			},
+			Info: info + ", normalizing its words with the default_text_search_config " +
+				"text search configuration.",
+			Volatility: volatility.Stable,
+		},
+	)
+}
+
+// makeTSRankOverload returns a ts_rank overload, which optionally takes an
+// array of weights before the vector and query, and a normalization bitmask
+// after them.
+func makeTSRankOverload(hasWeights, hasNormalization bool) tree.Overload {
+	var argTypes tree.ArgTypes
+	if hasWeights {
+		argTypes = append(argTypes, tree.ArgTypes{{"weights", types.FloatArray}}...)
+	}
+	argTypes = append(argTypes, tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}}...)
+	if hasNormalization {
+		argTypes = append(argTypes, tree.ArgTypes{{"normalization", types.Int}}...)
+	}
+	info := "Ranks the vector by how relevant it is to the query"
+	if hasWeights {
+		info += ", weighting the positions of weights D, C, B and A by the given weights"
+	}
+	if hasNormalization {
+		info += ", and adjusts the rank for the length of the document as specified " +
+			"by the normalization bitmask"
+	}
+	return tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(types.Float4),
+		Fn: func(_ *eval.Context, args tree.Datums) (tree.Datum, error) {
+			weights := tsearch.DefaultWeights
+			if hasWeights {
+				var err error
+				if weights, err = getTSRankWeights(tree.MustBeDArray(args[0])); err != nil {
+					return nil, err
+				}
+				args = args[1:]
+			}
+			v := tree.MustBeDTSVector(args[0])
+			q := tree.MustBeDTSQuery(args[1])
+			method := 0
+			if hasNormalization {
+				method = int(tree.MustBeDInt(args[2]))
+			}
+			return tree.NewDFloat(tree.DFloat(tsearch.Rank(weights, v.TSVector, q.TSQuery, method))), nil
+		},
+		Info:       info + ".",
+		Volatility: volatility.Immutable,
+	}
+}
+
+// getTSRankWeights validates the weights array given to ts_rank and converts
+// it to the weights of the D, C, B and A positions.
+func getTSRankWeights(arr *tree.DArray) ([4]float32, error) {
+	var weights [4]float32
+	if len(arr.Array) < len(weights) {
+		return weights, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	if arr.HasNulls {
+		return weights, pgerror.New(pgcode.NullValueNotAllowed, "array of weight must not contain nulls")
+	}
+	for i := range weights {
+		w := float32(tree.MustBeDFloat(arr.Array[i]))
+		if w > 1 {
+			return weights, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		}
+		weights[i] = w
+		if w < 0 {
+			weights[i] = tsearch.DefaultWeights[i]
+		}
+	}
+	return weights, nil
+}
@@ -286,6 +286,26 @@ func LookupCast(src, tgt *types.T) (Cast, bool) {
 		}, true
 	}
 
+	// Vectors have casts to and from numeric arrays, which cannot be stored in
+	// castMap because the array types are not in it. Like in pgvector, they are
+	// immutable and allowed in assignment contexts.
+	if srcFamily == types.ArrayFamily && tgtFamily == types.PGVectorFamily {
+		switch src.ArrayContents().Family() {
+		case types.IntFamily, types.FloatFamily, types.DecimalFamily:
+			return Cast{
+				MaxContext: ContextAssignment,
+				Volatility: volatility.Immutable,
+			}, true
+		}
+	}
+	if srcFamily == types.PGVectorFamily && tgtFamily == types.ArrayFamily &&
+		tgt.ArrayContents().Family() == types.FloatFamily {
+		return Cast{
+			MaxContext: ContextAssignment,
+			Volatility: volatility.Immutable,
+		}, true
+	}
+
 	if tgts, ok := castMap[src.Oid()]; ok {
 		if c, ok := tgts[tgt.Oid()]; ok {
 			return c, true
@@ -82,6 +82,9 @@ const (
 	TenantSettingsTableName                SystemTableName = "tenant_settings"
 	SpanCountTableName                     SystemTableName = "span_count"
 	SystemPrivilegeTableName               SystemTableName = "privileges"
+	NotificationsTableName                 SystemTableName = "notifications"
+	AdvisoryLocksTableName                 SystemTableName = "advisory_locks"
+	ReplicationSlotsTableName              SystemTableName = "replication_slots"
 )
 
 // Oid for virtual database and table.
@@ -157,6 +160,8 @@ const (
 	CrdbInternalTenantUsageDetailsViewID
 	CrdbInternalPgCatalogTableIsImplementedTableID
 	CrdbInternalSuperRegions
+	CrdbInternalClusterAdvisoryLocksTableID
+	CrdbInternalMaterializedViewsTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
 	InformationSchemaApplicableRolesID
@@ -26,8 +26,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 )
 
@@ -36,6 +39,12 @@ func BinaryOp(ctx *Context, op tree.BinaryEvalOp, left, right tree.Datum) (tree.
 	return op.Eval((*evaluator)(ctx), left, right)
 }
 
+func (e *evaluator) EvalAdjacentRangeOp(
+	_ *tree.AdjacentRangeOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(tree.MustBeDRange(a).Adjacent(tree.MustBeDRange(b)))), nil
+}
+
 func (e *evaluator) EvalAppendToMaybeNullArrayOp(
 	op *tree.AppendToMaybeNullArrayOp, a, b tree.Datum,
 ) (tree.Datum, error) {
@@ -253,6 +262,14 @@ func (e *evaluator) EvalConcatStringOp(
 
 }
 
+func (e *evaluator) EvalConcatTSVectorOp(
+	_ *tree.ConcatTSVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.NewDTSVector(
+		tree.MustBeDTSVector(left).TSVector.Concat(tree.MustBeDTSVector(right).TSVector),
+	), nil
+}
+
 func (e *evaluator) EvalConcatVarBitOp(
 	_ *tree.ConcatVarBitOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -271,6 +288,12 @@ func (e *evaluator) EvalContainedByArrayOp(
 	return tree.ArrayContains(e.ctx(), haystack, needles)
 }
 
+func (e *evaluator) EvalContainedByElemRangeOp(
+	_ *tree.ContainedByElemRangeOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(tree.MustBeDRange(b).ContainsElem(a))), nil
+}
+
 func (e *evaluator) EvalContainedByJsonbOp(
 	_ *tree.ContainedByJsonbOp, a, b tree.Datum,
 ) (tree.Datum, error) {
@@ -281,6 +304,12 @@ func (e *evaluator) EvalContainedByJsonbOp(
 	return tree.MakeDBool(tree.DBool(c)), nil
 }
 
+func (e *evaluator) EvalContainedByRangeOp(
+	_ *tree.ContainedByRangeOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(tree.MustBeDRange(b).ContainsRange(tree.MustBeDRange(a)))), nil
+}
+
 func (e *evaluator) EvalContainsArrayOp(
 	_ *tree.ContainsArrayOp, a, b tree.Datum,
 ) (tree.Datum, error) {
@@ -299,6 +328,38 @@ func (e *evaluator) EvalContainsJsonbOp(
 	return tree.MakeDBool(tree.DBool(c)), nil
 }
 
+func (e *evaluator) EvalContainsRangeElemOp(
+	_ *tree.ContainsRangeElemOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(tree.MustBeDRange(a).ContainsElem(b))), nil
+}
+
+func (e *evaluator) EvalContainsRangeOp(
+	_ *tree.ContainsRangeOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(tree.MustBeDRange(a).ContainsRange(tree.MustBeDRange(b)))), nil
+}
+
+func (e *evaluator) EvalCosDistanceVectorOp(
+	_ *tree.CosDistanceVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	d, err := vector.CosDistance(tree.MustBeDPGVector(left).T, tree.MustBeDPGVector(right).T)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(d)), nil
+}
+
+func (e *evaluator) EvalDistanceVectorOp(
+	_ *tree.DistanceVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	d, err := vector.L2Distance(tree.MustBeDPGVector(left).T, tree.MustBeDPGVector(right).T)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(d)), nil
+}
+
 func (e *evaluator) EvalDivDecimalIntOp(
 	_ *tree.DivDecimalIntOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -645,6 +706,38 @@ func (e *evaluator) EvalJSONFetchValStringOp(
 	return &tree.DJSON{JSON: j}, nil
 }
 
+func (e *evaluator) EvalJSONPathExistsOp(
+	_ *tree.JSONPathExistsOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	// Like Postgres, the operator suppresses errors caused by the contents of
+	// the document, and returns NULL in their place.
+	exists, ok, err := jsonpath.Exists(
+		tree.MustBeDJsonpath(right).Jsonpath, tree.MustBeDJSON(left).JSON, nil /* vars */, true, /* silent */
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return tree.DNull, nil
+	}
+	return tree.MakeDBool(tree.DBool(exists)), nil
+}
+
+func (e *evaluator) EvalJSONPathMatchOp(
+	_ *tree.JSONPathMatchOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	match, ok, err := jsonpath.Match(
+		tree.MustBeDJsonpath(right).Jsonpath, tree.MustBeDJSON(left).JSON, nil /* vars */, true, /* silent */
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return tree.DNull, nil
+	}
+	return tree.MakeDBool(tree.DBool(match)), nil
+}
+
 func (e *evaluator) EvalJSONSomeExistsOp(
 	_ *tree.JSONSomeExistsOp, a, b tree.Datum,
 ) (tree.Datum, error) {
@@ -1136,6 +1229,16 @@ func (e *evaluator) EvalMultIntervalIntOp(
 	}, nil
 }
 
+func (e *evaluator) EvalNegInnerProductVectorOp(
+	_ *tree.NegInnerProductVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	d, err := vector.NegInnerProduct(tree.MustBeDPGVector(left).T, tree.MustBeDPGVector(right).T)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(d)), nil
+}
+
 func (e *evaluator) EvalOverlapsArrayOp(
 	_ *tree.OverlapsArrayOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -1152,6 +1255,12 @@ func (e *evaluator) EvalOverlapsINetOp(
 	return tree.MakeDBool(tree.DBool(ipAddr.ContainsOrContainedBy(&other))), nil
 }
 
+func (e *evaluator) EvalOverlapsRangeOp(
+	_ *tree.OverlapsRangeOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(tree.MustBeDRange(a).Overlaps(tree.MustBeDRange(b)))), nil
+}
+
 func (e *evaluator) EvalPlusDateIntOp(
 	_ *tree.PlusDateIntOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -1466,3 +1575,19 @@ func (e *evaluator) EvalSimilarToOp(
 	key := similarToKey{s: string(tree.MustBeDString(right)), escape: '\\'}
 	return matchRegexpWithKey(e.ctx(), left, key)
 }
+
+func (e *evaluator) EvalTSMatchesQueryVectorOp(
+	_ *tree.TSMatchesQueryVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	q := tree.MustBeDTSQuery(left)
+	v := tree.MustBeDTSVector(right)
+	return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(q.TSQuery, v.TSVector))), nil
+}
+
+func (e *evaluator) EvalTSMatchesVectorQueryOp(
+	_ *tree.TSMatchesVectorQueryOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	v := tree.MustBeDTSVector(left)
+	q := tree.MustBeDTSQuery(right)
+	return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(q.TSQuery, v.TSVector))), nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -430,6 +431,14 @@ func performCastWithoutPrecisionTruncation(
 				tree.FmtPgwireText,
 				tree.FmtDataConversionConfig(ctx.SessionData().DataConversionConfig),
 			)
+		case *tree.DRange:
+			// Convert any TIMESTAMPTZ bounds to the context timezone for correct
+			// display.
+			s = tree.AsStringWithFlags(
+				t.InLocation(ctx.GetLocation()),
+				tree.FmtPgwireText,
+				tree.FmtDataConversionConfig(ctx.SessionData().DataConversionConfig),
+			)
 		case *tree.DArray:
 			s = tree.AsStringWithFlags(
 				d,
@@ -463,6 +472,14 @@ func performCastWithoutPrecisionTruncation(
 			s = t.String()
 		case *tree.DJSON:
 			s = t.JSON.String()
+		case *tree.DJsonpath:
+			s = t.Jsonpath.String()
+		case *tree.DTSQuery:
+			s = t.TSQuery.String()
+		case *tree.DTSVector:
+			s = t.TSVector.String()
+		case *tree.DPGVector:
+			s = t.T.String()
 		case *tree.DEnum:
 			s = t.LogicalRep
 		case *tree.DVoid:
@@ -831,6 +848,57 @@ func performCastWithoutPrecisionTruncation(
 			}
 			return tree.ParseDJSON(string(j))
 		}
+	case types.JsonpathFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDJsonpath(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDJsonpath(v.Contents)
+		case *tree.DJsonpath:
+			return v, nil
+		}
+	case types.TSQueryFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDTSQuery(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDTSQuery(v.Contents)
+		case *tree.DTSQuery:
+			return v, nil
+		}
+	case types.TSVectorFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDTSVector(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDTSVector(v.Contents)
+		case *tree.DTSVector:
+			return v, nil
+		}
+	case types.PGVectorFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDPGVector(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDPGVector(v.Contents)
+		case *tree.DArray:
+			return arrayToPGVector(v)
+		case *tree.DPGVector:
+			return v, nil
+		}
+	case types.RangeFamily:
+		switch v := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDRangeFromString(ctx, string(*v), t)
+			return res, err
+		case *tree.DCollatedString:
+			res, _, err := tree.ParseDRangeFromString(ctx, v.Contents, t)
+			return res, err
+		case *tree.DRange:
+			if v.ResolvedType().Equivalent(t) {
+				return v, nil
+			}
+		}
 	case types.ArrayFamily:
 		switch v := d.(type) {
 		case *tree.DString:
@@ -856,6 +924,18 @@ func performCastWithoutPrecisionTruncation(
 				}
 			}
 			return dcast, nil
+		case *tree.DPGVector:
+			dcast := tree.NewDArray(t.ArrayContents())
+			for _, f := range v.T {
+				ecast, err := PerformCast(ctx, tree.NewDFloat(tree.DFloat(f)), t.ArrayContents())
+				if err != nil {
+					return nil, err
+				}
+				if err := dcast.Append(ecast); err != nil {
+					return nil, err
+				}
+			}
+			return dcast, nil
 		}
 	case types.OidFamily:
 		switch v := d.(type) {
@@ -907,6 +987,36 @@ func performCastWithoutPrecisionTruncation(
 		pgcode.CannotCoerce, "invalid cast: %s -> %s", d.ResolvedType(), t)
 }
 
+// arrayToPGVector converts an array of numbers to a vector.
+func arrayToPGVector(a *tree.DArray) (tree.Datum, error) {
+	if a.HasNulls {
+		return nil, pgerror.New(pgcode.NullValueNotAllowed, "array must not contain nulls")
+	}
+	fs := make([]float64, len(a.Array))
+	for i, e := range a.Array {
+		switch v := tree.UnwrapDOidWrapper(e).(type) {
+		case *tree.DInt:
+			fs[i] = float64(*v)
+		case *tree.DFloat:
+			fs[i] = float64(*v)
+		case *tree.DDecimal:
+			f, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			fs[i] = f
+		default:
+			return nil, pgerror.Newf(pgcode.CannotCoerce,
+				"invalid cast: %s -> %s", a.ResolvedType(), types.PGVector)
+		}
+	}
+	v, err := vector.FromFloats(fs)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDPGVector(v), nil
+}
+
 // performIntToOidCast casts the input integer to the OID type given by the
 // input types.T.
 func performIntToOidCast(
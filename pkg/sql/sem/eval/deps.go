@@ -255,6 +255,23 @@ type Planner interface {
 	// ExternalWriteFile writes the content to an external file URI.
 	ExternalWriteFile(ctx context.Context, uri string, content []byte) error
 
+	// SendNotification sends a notification on a channel, which is delivered
+	// to the sessions listening on it if the current transaction commits.
+	SendNotification(ctx context.Context, channel string, payload string) error
+
+	// AcquireAdvisoryLock acquires an advisory lock. If wait is set, it waits
+	// until the lock is available, otherwise it returns false if the lock is
+	// held by another session.
+	AcquireAdvisoryLock(ctx context.Context, lock AdvisoryLock, wait bool) (bool, error)
+
+	// ReleaseAdvisoryLock releases a session-level advisory lock. It returns
+	// false if the session did not hold the lock.
+	ReleaseAdvisoryLock(ctx context.Context, lock AdvisoryLock) (bool, error)
+
+	// ReleaseAllAdvisoryLocks releases the session-level advisory locks held
+	// by the session.
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
+
 	// DecodeGist exposes gist functionality to the builtin functions.
 	DecodeGist(gist string, external bool) ([]string, error)
 
@@ -326,6 +343,25 @@ type Planner interface {
 	QueryIteratorEx(ctx context.Context, opName string, override sessiondata.InternalExecutorOverride, stmt string, qargs ...interface{}) (InternalRows, error)
 }
 
+// AdvisoryLock identifies an advisory lock acquired or released with the
+// pg_advisory_* builtins.
+type AdvisoryLock struct {
+	// Key is the key of the lock. For the locks acquired with a pair of int4
+	// keys, the first key is in the high 32 bits and the second key is in the
+	// low 32 bits.
+	Key int64
+	// KeyPair is set for the locks acquired with a pair of int4 keys, which
+	// never conflict with the locks acquired with an int8 key.
+	KeyPair bool
+	// Shared is set for shared locks, which only conflict with exclusive
+	// locks.
+	Shared bool
+	// Xact is set for transaction-level locks, which are released when the
+	// transaction ends. The other locks are held until they are explicitly
+	// released or the session ends.
+	Xact bool
+}
+
 // InternalRows is an iterator interface that's exposed by the internal
 // executor. It provides access to the rows from a query.
 // InternalRows is a copy of the one in sql/internal.go excluding the
@@ -348,6 +384,11 @@ type InternalRows interface {
 	// invalidate it).
 	Cur() tree.Datums
 
+	// RowsAffected returns the count of rows affected by the statement.
+	// This is only guaranteed to be accurate after Next() has returned
+	// false (no more rows).
+	RowsAffected() int
+
 	// Close closes this iterator, releasing any resources it held open. Close
 	// is idempotent and *must* be called once the caller is done with the
 	// iterator.
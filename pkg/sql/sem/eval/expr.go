@@ -353,6 +353,10 @@ func (e *evaluator) EvalDefaultVal(expr *tree.DefaultVal) (tree.Datum, error) {
 	return nil, errors.AssertionFailedf("unhandled type %T", expr)
 }
 
+func (e *evaluator) EvalGroupingFuncExpr(expr *tree.GroupingFuncExpr) (tree.Datum, error) {
+	return nil, errors.AssertionFailedf("unhandled type %T", expr)
+}
+
 func (e *evaluator) EvalIsNotNullExpr(expr *tree.IsNotNullExpr) (tree.Datum, error) {
 	d, err := expr.Expr.(tree.TypedExpr).Eval(e)
 	if err != nil {
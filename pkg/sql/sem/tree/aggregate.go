@@ -0,0 +1,147 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+)
+
+// AggregateOption is an attribute of a CREATE AGGREGATE statement, such as
+// SFUNC or STYPE. Its value is either a name, which is parsed as a type
+// reference because type names and function names share the same syntax, or
+// a string constant.
+type AggregateOption struct {
+	Name Name
+	// Type is set if the value of the attribute is a name.
+	Type ResolvableTypeReference
+	// Str is the value of the attribute if Type is not set.
+	Str string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateOption) Format(ctx *FmtCtx) {
+	ctx.WriteString(strings.ToUpper(string(node.Name)))
+	ctx.WriteString(" = ")
+	if node.Type != nil {
+		ctx.FormatTypeReference(node.Type)
+		return
+	}
+	lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, node.Str, ctx.flags.EncodeFlags())
+}
+
+// AggregateOptions is a list of AggregateOption.
+type AggregateOptions []AggregateOption
+
+// Format implements the NodeFormatter interface.
+func (node AggregateOptions) Format(ctx *FmtCtx) {
+	for i := range node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&node[i])
+	}
+}
+
+// AggregateSignature identifies a user-defined aggregate by its name and the
+// types of its arguments.
+type AggregateSignature struct {
+	Name     FunctionName
+	ArgTypes []ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateSignature) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte('(')
+	for i, typ := range node.ArgTypes {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatTypeReference(typ)
+	}
+	ctx.WriteByte(')')
+}
+
+// AggregateSignatures is a list of AggregateSignature.
+type AggregateSignatures []AggregateSignature
+
+// Format implements the NodeFormatter interface.
+func (node AggregateSignatures) Format(ctx *FmtCtx) {
+	for i := range node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&node[i])
+	}
+}
+
+// CreateAggregate represents a CREATE AGGREGATE statement. The attributes of
+// the aggregate are not validated by the parser.
+type CreateAggregate struct {
+	Replace   bool
+	Aggregate AggregateSignature
+	Options   AggregateOptions
+}
+
+var _ Statement = &CreateAggregate{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateAggregate) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("AGGREGATE ")
+	ctx.FormatNode(&node.Aggregate)
+	ctx.WriteString(" (")
+	ctx.FormatNode(node.Options)
+	ctx.WriteByte(')')
+}
+
+// DropAggregate represents a DROP AGGREGATE statement.
+type DropAggregate struct {
+	Aggregates   AggregateSignatures
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropAggregate{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropAggregate) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP AGGREGATE ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(node.Aggregates)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// AlterAggregateRename represents an ALTER AGGREGATE ... RENAME TO statement.
+type AlterAggregateRename struct {
+	Aggregate AggregateSignature
+	NewName   Name
+}
+
+var _ Statement = &AlterAggregateRename{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterAggregateRename) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER AGGREGATE ")
+	ctx.FormatNode(&node.Aggregate)
+	ctx.WriteString(" RENAME TO ")
+	ctx.FormatNode(&node.NewName)
+}
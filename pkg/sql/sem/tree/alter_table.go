@@ -76,6 +76,7 @@ func (*AlterTablePartitionByTable) alterTableCmd()   {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
 func (*AlterTableSetStorageParams) alterTableCmd()   {}
 func (*AlterTableResetStorageParams) alterTableCmd() {}
+func (*AlterTableRowLevelSecurity) alterTableCmd()   {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -96,6 +97,7 @@ var _ AlterTableCmd = &AlterTablePartitionByTable{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
 var _ AlterTableCmd = &AlterTableSetStorageParams{}
 var _ AlterTableCmd = &AlterTableResetStorageParams{}
+var _ AlterTableCmd = &AlterTableRowLevelSecurity{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -639,6 +641,47 @@ func (node *AlterTableResetStorageParams) Format(ctx *FmtCtx) {
 	ctx.WriteString(")")
 }
 
+// RowLevelSecurityAction represents the change made to the row-level security
+// of a table by an ALTER TABLE ... ROW LEVEL SECURITY command.
+type RowLevelSecurityAction int
+
+// RowLevelSecurityAction values.
+const (
+	RowLevelSecurityEnable RowLevelSecurityAction = iota
+	RowLevelSecurityDisable
+	RowLevelSecurityForce
+	RowLevelSecurityNoForce
+)
+
+var rowLevelSecurityActionName = [...]string{
+	RowLevelSecurityEnable:  "ENABLE",
+	RowLevelSecurityDisable: "DISABLE",
+	RowLevelSecurityForce:   "FORCE",
+	RowLevelSecurityNoForce: "NO FORCE",
+}
+
+func (a RowLevelSecurityAction) String() string {
+	return rowLevelSecurityActionName[a]
+}
+
+// AlterTableRowLevelSecurity represents an ALTER TABLE ... ROW LEVEL SECURITY
+// command.
+type AlterTableRowLevelSecurity struct {
+	Action RowLevelSecurityAction
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableRowLevelSecurity) TelemetryName() string {
+	return "row_level_security"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableRowLevelSecurity) Format(ctx *FmtCtx) {
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.Action.String())
+	ctx.WriteString(" ROW LEVEL SECURITY")
+}
+
 // AlterTableLocality represents an ALTER TABLE LOCALITY command.
 type AlterTableLocality struct {
 	Name     *UnresolvedObjectName
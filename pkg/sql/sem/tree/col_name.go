@@ -144,6 +144,9 @@ func ComputeColNameInternal(sp SearchPath, target Expr) (int, string, error) {
 	case *CoalesceExpr:
 		return 2, "coalesce", nil
 
+	case *GroupingFuncExpr:
+		return 2, "grouping", nil
+
 		// CockroachDB-specific nodes follow.
 	case *IfErrExpr:
 		if e.Else == nil {
@@ -519,6 +519,16 @@ var (
 		types.VarBitArray,
 		types.AnyTuple,
 		types.AnyTupleArray,
+		types.Jsonpath,
+		types.TSQuery,
+		types.TSVector,
+		types.PGVector,
+		types.Int4Range,
+		types.Int8Range,
+		types.NumRange,
+		types.TSRange,
+		types.TSTZRange,
+		types.DateRange,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []*types.T{types.Bytes, types.Uuid, types.String, types.AnyEnum}
@@ -311,6 +311,13 @@ func mustParseDJSON(t *testing.T, s string) tree.Datum {
 	}
 	return d
 }
+func mustParseDJsonpath(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDJsonpath(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
 func mustParseDUuid(t *testing.T, s string) tree.Datum {
 	d, err := tree.ParseDUuidFromString(s)
 	if err != nil {
@@ -378,6 +385,7 @@ var parseFuncs = map[*types.T]func(*testing.T, string) tree.Datum{
 	types.TimestampTZ:      mustParseDTimestampTZ,
 	types.Interval:         mustParseDInterval,
 	types.Jsonb:            mustParseDJSON,
+	types.Jsonpath:         mustParseDJsonpath,
 	types.Uuid:             mustParseDUuid,
 	types.Box2D:            mustParseDBox2D,
 	types.Geography:        mustParseDGeography,
@@ -427,11 +435,18 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c:            tree.NewStrVal("true"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Bool, types.Jsonb),
+			parseOptions: typeSet(types.String, types.Bytes, types.Bool, types.Jsonb, types.Jsonpath),
 		},
 		{
-			c:            tree.NewStrVal("2010-09-28"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Date, types.Timestamp, types.TimestampTZ),
+			c: tree.NewStrVal("2010-09-28"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.Date,
+				types.Timestamp,
+				types.TimestampTZ,
+				// This is parsed as the jsonpath 2010 - 9 - 28.
+				types.Jsonpath),
 		},
 		{
 			c:            tree.NewStrVal("2010-09-28 12:00:00.1"),
@@ -487,7 +502,8 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 				types.Float,
 				types.Decimal,
 				types.Interval,
-				types.Jsonb),
+				types.Jsonb,
+				types.Jsonpath),
 		},
 		{
 			c:            tree.NewStrVal(`{"a": 1}`),
@@ -603,6 +619,12 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			if availType.Family() == types.EnumFamily {
 				continue
 			}
+			// Almost any string is a valid tsvector or tsquery, so they are not
+			// listed in the expected parse options. Their parsing is tested in the
+			// tsearch package.
+			if availType.Family() == types.TSQueryFamily || availType.Family() == types.TSVectorFamily {
+				continue
+			}
 
 			semaCtx := tree.MakeSemaContext()
 			typedExpr, err := test.c.ResolveAsType(context.Background(), &semaCtx, availType)
@@ -38,13 +38,16 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/stringencoding"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 	"github.com/lib/pq/oid"
@@ -3714,7 +3717,8 @@ func AsJSON(
 	case *DTimestamp:
 		// This is RFC3339Nano, but without the TZ fields.
 		return json.FromString(t.UTC().Format("2006-01-02T15:04:05.999999999")), nil
-	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D:
+	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D,
+		*DTSQuery, *DTSVector, *DPGVector, *DRange:
 		return json.FromString(AsStringWithFlags(t, FmtBareStrings, FmtDataConversionConfig(dcc))), nil
 	case *DGeometry:
 		return json.FromSpatialObject(t.Geometry.SpatialObject(), geo.DefaultGeoJSONDecimalDigits)
@@ -3815,6 +3819,987 @@ func (d *DJSON) Size() uintptr {
 	return unsafe.Sizeof(*d) + d.JSON.Size()
 }
 
+// DJsonpath is the jsonpath Datum.
+type DJsonpath struct {
+	*jsonpath.Jsonpath
+}
+
+// NewDJsonpath is a helper routine to create a DJsonpath initialized from its
+// argument.
+func NewDJsonpath(p *jsonpath.Jsonpath) *DJsonpath {
+	return &DJsonpath{Jsonpath: p}
+}
+
+// ParseDJsonpath takes a string of jsonpath and returns a DJsonpath value.
+func ParseDJsonpath(s string) (*DJsonpath, error) {
+	p, err := jsonpath.Parse(s)
+	if err != nil {
+		return nil, MakeParseError(s, types.Jsonpath, err)
+	}
+	return NewDJsonpath(p), nil
+}
+
+// AsDJsonpath attempts to retrieve a *DJsonpath from an Expr, returning a
+// *DJsonpath and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DJsonpath wrapped by a *DOidWrapper is possible.
+func AsDJsonpath(e Expr) (*DJsonpath, bool) {
+	switch t := e.(type) {
+	case *DJsonpath:
+		return t, true
+	case *DOidWrapper:
+		return AsDJsonpath(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDJsonpath attempts to retrieve a *DJsonpath from an Expr, panicking
+// if the assertion fails.
+func MustBeDJsonpath(e Expr) *DJsonpath {
+	p, ok := AsDJsonpath(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DJsonpath, found %T", e))
+	}
+	return p
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DJsonpath) ResolvedType() *types.T {
+	return types.Jsonpath
+}
+
+// Compare implements the Datum interface.
+func (d *DJsonpath) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface. Like in Postgres, there are no
+// comparison operators for jsonpath, so this is only used internally (e.g. to
+// deduplicate values), and compares the canonical text forms of the paths.
+func (d *DJsonpath) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DJsonpath)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return strings.Compare(d.Jsonpath.String(), v.Jsonpath.String()), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DJsonpath) Prev(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DJsonpath) Next(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DJsonpath) IsMax(ctx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DJsonpath) IsMin(ctx CompareContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DJsonpath) Max(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DJsonpath) Min(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DJsonpath) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DJsonpath) Format(ctx *FmtCtx) {
+	s := d.Jsonpath.String()
+	if ctx.flags.HasFlags(fmtRawStrings) || ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings)) {
+		ctx.WriteString(s)
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DJsonpath) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.Jsonpath.String()))
+}
+
+// DTSQuery is the tsquery Datum.
+type DTSQuery struct {
+	tsearch.TSQuery
+}
+
+// NewDTSQuery is a helper routine to create a DTSQuery initialized from its
+// argument.
+func NewDTSQuery(q tsearch.TSQuery) *DTSQuery {
+	return &DTSQuery{TSQuery: q}
+}
+
+// ParseDTSQuery takes a string of tsquery and returns a DTSQuery value.
+func ParseDTSQuery(s string) (*DTSQuery, error) {
+	q, err := tsearch.ParseTSQuery(s)
+	if err != nil {
+		return nil, MakeParseError(s, types.TSQuery, err)
+	}
+	return NewDTSQuery(q), nil
+}
+
+// AsDTSQuery attempts to retrieve a *DTSQuery from an Expr, returning a
+// *DTSQuery and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DTSQuery wrapped by a *DOidWrapper is possible.
+func AsDTSQuery(e Expr) (*DTSQuery, bool) {
+	switch t := e.(type) {
+	case *DTSQuery:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSQuery(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSQuery attempts to retrieve a *DTSQuery from an Expr, panicking if
+// the assertion fails.
+func MustBeDTSQuery(e Expr) *DTSQuery {
+	q, ok := AsDTSQuery(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DTSQuery, found %T", e))
+	}
+	return q
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSQuery) ResolvedType() *types.T {
+	return types.TSQuery
+}
+
+// Compare implements the Datum interface.
+func (d *DTSQuery) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DTSQuery) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DTSQuery)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return d.TSQuery.Compare(v.TSQuery), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DTSQuery) Prev(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSQuery) Next(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSQuery) IsMax(ctx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSQuery) IsMin(ctx CompareContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DTSQuery) Max(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSQuery) Min(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSQuery) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSQuery) Format(ctx *FmtCtx) {
+	s := d.TSQuery.String()
+	if ctx.flags.HasFlags(fmtRawStrings) || ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings)) {
+		ctx.WriteString(s)
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTSQuery) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.TSQuery.Size()
+}
+
+// DTSVector is the tsvector Datum.
+type DTSVector struct {
+	tsearch.TSVector
+}
+
+// NewDTSVector is a helper routine to create a DTSVector initialized from its
+// argument.
+func NewDTSVector(v tsearch.TSVector) *DTSVector {
+	return &DTSVector{TSVector: v}
+}
+
+// ParseDTSVector takes a string of tsvector and returns a DTSVector value.
+func ParseDTSVector(s string) (*DTSVector, error) {
+	v, err := tsearch.ParseTSVector(s)
+	if err != nil {
+		return nil, MakeParseError(s, types.TSVector, err)
+	}
+	return NewDTSVector(v), nil
+}
+
+// AsDTSVector attempts to retrieve a *DTSVector from an Expr, returning a
+// *DTSVector and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DTSVector wrapped by a *DOidWrapper is possible.
+func AsDTSVector(e Expr) (*DTSVector, bool) {
+	switch t := e.(type) {
+	case *DTSVector:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSVector(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSVector attempts to retrieve a *DTSVector from an Expr, panicking
+// if the assertion fails.
+func MustBeDTSVector(e Expr) *DTSVector {
+	v, ok := AsDTSVector(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DTSVector, found %T", e))
+	}
+	return v
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSVector) ResolvedType() *types.T {
+	return types.TSVector
+}
+
+// Compare implements the Datum interface.
+func (d *DTSVector) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DTSVector) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DTSVector)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return d.TSVector.Compare(v.TSVector), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DTSVector) Prev(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSVector) Next(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSVector) IsMax(ctx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSVector) IsMin(ctx CompareContext) bool {
+	return len(d.TSVector) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DTSVector) Max(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSVector) Min(ctx CompareContext) (Datum, bool) {
+	return &DTSVector{}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSVector) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSVector) Format(ctx *FmtCtx) {
+	s := d.TSVector.String()
+	if ctx.flags.HasFlags(fmtRawStrings) || ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings)) {
+		ctx.WriteString(s)
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTSVector) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.TSVector.Size()
+}
+
+// DPGVector is the Datum for the vector type, which is compatible with the
+// vector type of the pgvector extension.
+type DPGVector struct {
+	vector.T
+}
+
+// NewDPGVector is a helper routine to create a DPGVector initialized from its
+// argument.
+func NewDPGVector(v vector.T) *DPGVector {
+	return &DPGVector{T: v}
+}
+
+// ParseDPGVector takes a string of vector and returns a DPGVector value.
+func ParseDPGVector(s string) (*DPGVector, error) {
+	v, err := vector.ParseVector(s)
+	if err != nil {
+		return nil, MakeParseError(s, types.PGVector, err)
+	}
+	return NewDPGVector(v), nil
+}
+
+// AsDPGVector attempts to retrieve a *DPGVector from an Expr, returning a
+// *DPGVector and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DPGVector wrapped by a *DOidWrapper is possible.
+func AsDPGVector(e Expr) (*DPGVector, bool) {
+	switch t := e.(type) {
+	case *DPGVector:
+		return t, true
+	case *DOidWrapper:
+		return AsDPGVector(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDPGVector attempts to retrieve a *DPGVector from an Expr, panicking
+// if the assertion fails.
+func MustBeDPGVector(e Expr) *DPGVector {
+	v, ok := AsDPGVector(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DPGVector, found %T", e))
+	}
+	return v
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DPGVector) ResolvedType() *types.T {
+	return types.PGVector
+}
+
+// Compare implements the Datum interface.
+func (d *DPGVector) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (d *DPGVector) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DPGVector)
+	if !ok {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	return d.T.Compare(v.T), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DPGVector) Prev(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DPGVector) Next(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DPGVector) IsMax(ctx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DPGVector) IsMin(ctx CompareContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (d *DPGVector) Max(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DPGVector) Min(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DPGVector) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DPGVector) Format(ctx *FmtCtx) {
+	s := d.T.String()
+	if ctx.flags.HasFlags(fmtRawStrings) || ctx.flags.HasFlags(FmtFlags(lexbase.EncBareStrings)) {
+		ctx.WriteString(s)
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DPGVector) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.T.Size()
+}
+
+// DRange is the Datum for the range types, such as INT4RANGE and TSTZRANGE.
+//
+// A non-empty range is stored in its canonical form, so two ranges containing
+// the same set of points always have identical bounds. For the discrete range
+// types (INT4RANGE, INT8RANGE and DATERANGE) this means that the lower bound
+// is always inclusive and the upper bound is always exclusive.
+type DRange struct {
+	typ *types.T
+	// Lower and Upper are the bounds of the range. An unbounded (infinite)
+	// bound is represented by DNull.
+	Lower, Upper Datum
+	// LowerInc and UpperInc are true if the corresponding bound is inclusive.
+	// An infinite bound is never inclusive.
+	LowerInc, UpperInc bool
+	// Empty is true if the range contains no points, in which case the bounds
+	// are unset.
+	Empty bool
+}
+
+// Flags describing a range. They match the flags used by Postgres in the
+// binary representation of ranges.
+const (
+	RangeEmpty    = 0x01
+	RangeLowerInc = 0x02
+	RangeUpperInc = 0x04
+	RangeLowerInf = 0x08
+	RangeUpperInf = 0x10
+)
+
+// NewDEmptyRange returns an empty range of the given range type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{typ: typ, Lower: DNull, Upper: DNull, Empty: true}
+}
+
+// NewDRange returns a range of the given range type with the given bounds. A
+// DNull bound is treated as infinite. The range is returned in its canonical
+// form, which is the empty range if the bounds do not contain any points.
+func NewDRange(typ *types.T, lower, upper Datum, lowerInc, upperInc bool) (*DRange, error) {
+	lower, upper = UnwrapDOidWrapper(lower), UnwrapDOidWrapper(upper)
+	if lower == DNull {
+		lowerInc = false
+	}
+	if upper == DNull {
+		upperInc = false
+	}
+	if lower != DNull && upper != DNull {
+		cmp, err := lower.CompareError(rangeBoundCompareContext{}, upper)
+		if err != nil {
+			return nil, err
+		}
+		if cmp > 0 {
+			return nil, pgerror.New(pgcode.DataException,
+				"range lower bound must be less than or equal to range upper bound")
+		}
+		if cmp == 0 && !(lowerInc && upperInc) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	d := &DRange{typ: typ, Lower: lower, Upper: upper, LowerInc: lowerInc, UpperInc: upperInc}
+	if err := d.canonicalize(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NewDRangeFromFlags returns a range of the given range type with the given
+// bounds and flags, which are a combination of RangeEmpty, RangeLowerInc,
+// RangeUpperInc, RangeLowerInf and RangeUpperInf. The bound of an infinite
+// side of the range is ignored.
+func NewDRangeFromFlags(typ *types.T, flags byte, lower, upper Datum) (*DRange, error) {
+	if flags&RangeEmpty != 0 {
+		return NewDEmptyRange(typ), nil
+	}
+	if flags&RangeLowerInf != 0 {
+		lower = DNull
+	}
+	if flags&RangeUpperInf != 0 {
+		upper = DNull
+	}
+	return NewDRange(typ, lower, upper, flags&RangeLowerInc != 0, flags&RangeUpperInc != 0)
+}
+
+// canonicalize converts the bounds of a range over a discrete subtype to the
+// form [lower, upper), which may turn out to be empty.
+func (d *DRange) canonicalize() error {
+	switch d.typ.RangeContents().Family() {
+	case types.IntFamily, types.DateFamily:
+	default:
+		return nil
+	}
+	if d.Lower != DNull && !d.LowerInc {
+		next, err := nextDiscreteRangeBound(d.typ, d.Lower)
+		if err != nil {
+			return err
+		}
+		d.Lower, d.LowerInc = next, true
+	}
+	if d.Upper != DNull && d.UpperInc {
+		next, err := nextDiscreteRangeBound(d.typ, d.Upper)
+		if err != nil {
+			return err
+		}
+		d.Upper, d.UpperInc = next, false
+	}
+	if d.Lower != DNull && d.Upper != DNull &&
+		d.Lower.Compare(rangeBoundCompareContext{}, d.Upper) >= 0 {
+		*d = *NewDEmptyRange(d.typ)
+	}
+	return nil
+}
+
+// nextDiscreteRangeBound returns the value following the given bound of a
+// range over a discrete subtype.
+func nextDiscreteRangeBound(typ *types.T, bound Datum) (Datum, error) {
+	switch t := bound.(type) {
+	case *DInt:
+		if typ.RangeContents().Width() == 32 {
+			if *t >= math.MaxInt32 {
+				return nil, ErrInt4OutOfRange
+			}
+		} else if *t == math.MaxInt64 {
+			return nil, ErrIntOutOfRange
+		}
+		return NewDInt(*t + 1), nil
+	case *DDate:
+		if !t.IsFinite() {
+			// The infinite dates have no successor.
+			return t, nil
+		}
+		next, err := t.AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(next), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected discrete range bound %T", bound)
+}
+
+// rangeBoundCompareContext is the CompareContext used to compare the bounds
+// of ranges. The bounds of a range always have the subtype of the range and
+// are never wrapped, so comparing them does not depend on the session.
+type rangeBoundCompareContext struct{}
+
+var _ CompareContext = rangeBoundCompareContext{}
+
+// UnwrapDatum implements the CompareContext interface.
+func (rangeBoundCompareContext) UnwrapDatum(d Datum) Datum { return d }
+
+// GetLocation implements the CompareContext interface.
+func (rangeBoundCompareContext) GetLocation() *time.Location { return time.UTC }
+
+// GetRelativeParseTime implements the CompareContext interface.
+func (rangeBoundCompareContext) GetRelativeParseTime() time.Time { return time.Time{} }
+
+// MustGetPlaceholderValue implements the CompareContext interface.
+func (rangeBoundCompareContext) MustGetPlaceholderValue(p *Placeholder) Datum {
+	panic(errors.AssertionFailedf("unexpected placeholder %s in range bound", p))
+}
+
+// rangeBound is one of the bounds of a non-empty range.
+type rangeBound struct {
+	// val is the value of the bound, or DNull if the bound is infinite.
+	val   Datum
+	inc   bool
+	lower bool
+}
+
+func (d *DRange) lowerBound() rangeBound {
+	return rangeBound{val: d.Lower, inc: d.LowerInc, lower: true}
+}
+
+func (d *DRange) upperBound() rangeBound {
+	return rangeBound{val: d.Upper, inc: d.UpperInc, lower: false}
+}
+
+// compareRangeBounds compares two range bounds, taking into account whether
+// they are lower or upper bounds and whether they are inclusive. For example,
+// the lower bound [5 is less than the lower bound (5, which is greater than
+// the upper bound 5]. It returns -1, 0 or +1.
+func compareRangeBounds(a, b rangeBound) int {
+	aInf, bInf := a.val == DNull, b.val == DNull
+	switch {
+	case aInf && bInf:
+		if a.lower == b.lower {
+			return 0
+		}
+		if a.lower {
+			return -1
+		}
+		return 1
+	case aInf:
+		if a.lower {
+			return -1
+		}
+		return 1
+	case bInf:
+		if b.lower {
+			return 1
+		}
+		return -1
+	}
+	if cmp := a.val.Compare(rangeBoundCompareContext{}, b.val); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case a.inc && b.inc:
+		return 0
+	case !a.inc && !b.inc:
+		if a.lower == b.lower {
+			return 0
+		}
+		if a.lower {
+			return 1
+		}
+		return -1
+	case !a.inc:
+		if a.lower {
+			return 1
+		}
+		return -1
+	default:
+		if b.lower {
+			return -1
+		}
+		return 1
+	}
+}
+
+// compareRangeBoundValues compares only the values of two range bounds, where
+// an infinite lower bound is less than and an infinite upper bound is greater
+// than any other value.
+func compareRangeBoundValues(a, b rangeBound) int {
+	if a.val == DNull || b.val == DNull {
+		return compareRangeBounds(
+			rangeBound{val: a.val, lower: a.lower}, rangeBound{val: b.val, lower: b.lower},
+		)
+	}
+	return a.val.Compare(rangeBoundCompareContext{}, b.val)
+}
+
+// ContainsRange returns whether every point of other is also in the range.
+func (d *DRange) ContainsRange(other *DRange) bool {
+	if other.Empty {
+		return true
+	}
+	if d.Empty {
+		return false
+	}
+	return compareRangeBounds(d.lowerBound(), other.lowerBound()) <= 0 &&
+		compareRangeBounds(other.upperBound(), d.upperBound()) <= 0
+}
+
+// ContainsElem returns whether the given value of the subtype of the range is
+// in the range.
+func (d *DRange) ContainsElem(elem Datum) bool {
+	if d.Empty {
+		return false
+	}
+	elem = UnwrapDOidWrapper(elem)
+	if d.Lower != DNull {
+		cmp := d.Lower.Compare(rangeBoundCompareContext{}, elem)
+		if cmp > 0 || (cmp == 0 && !d.LowerInc) {
+			return false
+		}
+	}
+	if d.Upper != DNull {
+		cmp := d.Upper.Compare(rangeBoundCompareContext{}, elem)
+		if cmp < 0 || (cmp == 0 && !d.UpperInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps returns whether the range has any points in common with other.
+func (d *DRange) Overlaps(other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return compareRangeBounds(d.lowerBound(), other.upperBound()) <= 0 &&
+		compareRangeBounds(other.lowerBound(), d.upperBound()) <= 0
+}
+
+// Adjacent returns whether the range and other do not overlap but their union
+// has no gap between them, as is the case for [1,5) and [5,10).
+func (d *DRange) Adjacent(other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return rangeBoundsAdjacent(d.upperBound(), other.lowerBound()) ||
+		rangeBoundsAdjacent(other.upperBound(), d.lowerBound())
+}
+
+// InLocation returns the range with its TIMESTAMPTZ bounds, if any, converted
+// to the given location. This only changes how the bounds are displayed.
+func (d *DRange) InLocation(loc *time.Location) *DRange {
+	if d.Empty || d.typ.RangeContents().Family() != types.TimestampTZFamily {
+		return d
+	}
+	res := *d
+	for _, bound := range []*Datum{&res.Lower, &res.Upper} {
+		if ts, ok := (*bound).(*DTimestampTZ); ok {
+			*bound = &DTimestampTZ{Time: ts.Time.In(loc)}
+		}
+	}
+	return &res
+}
+
+// rangeBoundsAdjacent returns whether the given upper and lower bounds touch
+// each other without overlapping. Since the ranges over discrete subtypes are
+// canonical, there is always a point between the bounds if the value of the
+// upper bound is less than the value of the lower bound.
+func rangeBoundsAdjacent(upper, lower rangeBound) bool {
+	if compareRangeBoundValues(upper, lower) != 0 {
+		return false
+	}
+	return upper.inc != lower.inc
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DRange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// Compare implements the Datum interface.
+func (d *DRange) Compare(ctx CompareContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface. Ranges are ordered by their
+// lower bounds, then by their upper bounds, with the empty range sorting
+// before all other ranges.
+func (d *DRange) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DRange)
+	if !ok || !d.typ.Equivalent(v.typ) {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	switch {
+	case d.Empty && v.Empty:
+		return 0, nil
+	case d.Empty:
+		return -1, nil
+	case v.Empty:
+		return 1, nil
+	}
+	if cmp := compareRangeBounds(d.lowerBound(), v.lowerBound()); cmp != 0 {
+		return cmp, nil
+	}
+	return compareRangeBounds(d.upperBound(), v.upperBound()), nil
+}
+
+// Prev implements the Datum interface.
+func (d *DRange) Prev(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DRange) Next(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DRange) IsMax(ctx CompareContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DRange) IsMin(ctx CompareContext) bool {
+	return d.Empty
+}
+
+// Max implements the Datum interface.
+func (d *DRange) Max(ctx CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DRange) Min(ctx CompareContext) (Datum, bool) {
+	return NewDEmptyRange(d.typ), true
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DRange) IsComposite() bool {
+	for _, bound := range [...]Datum{d.Lower, d.Upper} {
+		if cdatum, ok := bound.(CompositeDatum); ok && cdatum.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// Flags returns the flags describing the range, which are a combination of
+// RangeEmpty, RangeLowerInc, RangeUpperInc, RangeLowerInf and RangeUpperInf.
+func (d *DRange) Flags() byte {
+	if d.Empty {
+		return RangeEmpty
+	}
+	var flags byte
+	if d.LowerInc {
+		flags |= RangeLowerInc
+	}
+	if d.UpperInc {
+		flags |= RangeUpperInc
+	}
+	if d.Lower == DNull {
+		flags |= RangeLowerInf
+	}
+	if d.Upper == DNull {
+		flags |= RangeUpperInf
+	}
+	return flags
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DRange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DRange) Format(ctx *FmtCtx) {
+	if ctx.HasFlags(fmtPgwireFormat) || ctx.HasFlags(FmtFlags(lexbase.EncBareStrings)) {
+		d.formatBounds(ctx)
+		return
+	}
+	s := AsStringWithFlags(d, FmtBareStrings, FmtDataConversionConfig(ctx.dataConversionConfig))
+	lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+}
+
+// formatBounds writes the text representation of the range, such as [1,10)
+// or empty, in which the bounds are quoted if necessary.
+func (d *DRange) formatBounds(ctx *FmtCtx) {
+	if d.Empty {
+		ctx.WriteString("empty")
+		return
+	}
+	flags := FmtBareStrings
+	if ctx.HasFlags(fmtPgwireFormat) {
+		flags = FmtPgwireText
+	}
+	if d.LowerInc {
+		ctx.WriteByte('[')
+	} else {
+		ctx.WriteByte('(')
+	}
+	if d.Lower != DNull {
+		s := AsStringWithFlags(d.Lower, flags, FmtDataConversionConfig(ctx.dataConversionConfig))
+		formatStringInRange(&ctx.Buffer, s)
+	}
+	ctx.WriteByte(',')
+	if d.Upper != DNull {
+		s := AsStringWithFlags(d.Upper, flags, FmtDataConversionConfig(ctx.dataConversionConfig))
+		formatStringInRange(&ctx.Buffer, s)
+	}
+	if d.UpperInc {
+		ctx.WriteByte(']')
+	} else {
+		ctx.WriteByte(')')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DRange) Size() uintptr {
+	sz := unsafe.Sizeof(*d)
+	if d.Lower != DNull {
+		sz += d.Lower.Size()
+	}
+	if d.Upper != DNull {
+		sz += d.Upper.Size()
+	}
+	return sz
+}
+
+// AsDRange attempts to retrieve a *DRange from an Expr, returning a *DRange
+// and a flag signifying whether the assertion was successful. The function
+// should be used instead of direct type assertions wherever a *DRange wrapped
+// by a *DOidWrapper is possible.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a *DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	r, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return r
+}
+
 // DTuple is the tuple Datum.
 type DTuple struct {
 	D Datums
@@ -5358,6 +6343,23 @@ func NewDefaultDatum(collationEnv *CollationEnvironment, t *types.T) (d Datum, e
 		return dTimeMin, nil
 	case types.JsonFamily:
 		return dNullJSON, nil
+	case types.JsonpathFamily:
+		return NewDJsonpath(&jsonpath.Jsonpath{Expr: jsonpath.Root{}}), nil
+	case types.TSQueryFamily:
+		return &DTSQuery{}, nil
+	case types.TSVectorFamily:
+		return &DTSVector{}, nil
+	case types.PGVectorFamily:
+		if t.Width() > 0 {
+			return NewDPGVector(make(vector.T, t.Width())), nil
+		}
+		return nil, pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			"%s must be set or be NULL",
+			t.Name(),
+		)
+	case types.RangeFamily:
+		return NewDEmptyRange(t), nil
 	case types.TimeTZFamily:
 		return dZeroTimeTZ, nil
 	case types.GeometryFamily, types.GeographyFamily, types.Box2DFamily:
@@ -5472,6 +6474,11 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.TimestampTZFamily:    {unsafe.Sizeof(DTimestampTZ{}), fixedSize},
 	types.IntervalFamily:       {unsafe.Sizeof(DInterval{}), fixedSize},
 	types.JsonFamily:           {unsafe.Sizeof(DJSON{}), variableSize},
+	types.JsonpathFamily:       {unsafe.Sizeof(DJsonpath{}), variableSize},
+	types.TSQueryFamily:        {unsafe.Sizeof(DTSQuery{}), variableSize},
+	types.TSVectorFamily:       {unsafe.Sizeof(DTSVector{}), variableSize},
+	types.PGVectorFamily:       {unsafe.Sizeof(DPGVector{}), variableSize},
+	types.RangeFamily:          {unsafe.Sizeof(DRange{}), variableSize},
 	types.UuidFamily:           {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INetFamily:           {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	types.OidFamily:            {unsafe.Sizeof(DInt(0)), fixedSize},
@@ -5692,6 +6699,13 @@ func AdjustValueToType(typ *types.T, inVal Datum) (outVal Datum, err error) {
 				}
 			}
 		}
+	case types.PGVectorFamily:
+		if v, ok := AsDPGVector(inVal); ok {
+			if typ.Width() > 0 && len(v.T) != int(typ.Width()) {
+				return nil, pgerror.Newf(pgcode.DataException,
+					"expected %d dimensions, not %d", typ.Width(), len(v.T))
+			}
+		}
 	case types.DecimalFamily:
 		if inDec, ok := inVal.(*DDecimal); ok {
 			if inDec.Form != apd.Finite || typ.Precision() == 0 {
@@ -88,11 +88,16 @@ type DropTable struct {
 	Names        TableNames
 	IfExists     bool
 	DropBehavior DropBehavior
+	IsForeign    bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *DropTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("DROP TABLE ")
+	ctx.WriteString("DROP ")
+	if node.IsForeign {
+		ctx.WriteString("FOREIGN ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfExists {
 		ctx.WriteString("IF EXISTS ")
 	}
@@ -1138,6 +1138,13 @@ var BinOps = map[treebin.BinaryOperatorSymbol]binOpOverload{
 			EvalOp:     &ConcatJsonbOp{},
 			Volatility: volatility.Immutable,
 		},
+		&BinOp{
+			LeftType:   types.TSVector,
+			RightType:  types.TSVector,
+			ReturnType: types.TSVector,
+			EvalOp:     &ConcatTSVectorOp{},
+			Volatility: volatility.Immutable,
+		},
 	},
 
 	// TODO(pmattis): Check that the shift is valid.
@@ -1282,6 +1289,36 @@ var BinOps = map[treebin.BinaryOperatorSymbol]binOpOverload{
 			Volatility: volatility.Immutable,
 		},
 	},
+
+	treebin.Distance: {
+		&BinOp{
+			LeftType:   types.PGVector,
+			RightType:  types.PGVector,
+			ReturnType: types.Float,
+			EvalOp:     &DistanceVectorOp{},
+			Volatility: volatility.Immutable,
+		},
+	},
+
+	treebin.NegInnerProduct: {
+		&BinOp{
+			LeftType:   types.PGVector,
+			RightType:  types.PGVector,
+			ReturnType: types.Float,
+			EvalOp:     &NegInnerProductVectorOp{},
+			Volatility: volatility.Immutable,
+		},
+	},
+
+	treebin.CosDistance: {
+		&BinOp{
+			LeftType:   types.PGVector,
+			RightType:  types.PGVector,
+			ReturnType: types.Float,
+			EvalOp:     &CosDistanceVectorOp{},
+			Volatility: volatility.Immutable,
+		},
+	},
 }
 
 // CmpOp is a comparison operator.
@@ -1366,6 +1403,56 @@ func cmpOpFixups(
 		})
 	}
 
+	// Range comparisons.
+	for _, t := range types.RangeTypes {
+		elem := t.RangeContents()
+		cmpOps[treecmp.EQ] = append(cmpOps[treecmp.EQ], makeEqFn(t, t, volatility.Immutable))
+		cmpOps[treecmp.LT] = append(cmpOps[treecmp.LT], makeLtFn(t, t, volatility.Immutable))
+		cmpOps[treecmp.LE] = append(cmpOps[treecmp.LE], makeLeFn(t, t, volatility.Immutable))
+		cmpOps[treecmp.IsNotDistinctFrom] = append(cmpOps[treecmp.IsNotDistinctFrom],
+			makeIsFn(t, t, volatility.Immutable))
+		cmpOps[treecmp.Contains] = append(cmpOps[treecmp.Contains],
+			&CmpOp{
+				LeftType:   t,
+				RightType:  t,
+				EvalOp:     &ContainsRangeOp{},
+				Volatility: volatility.Immutable,
+			},
+			&CmpOp{
+				LeftType:   t,
+				RightType:  elem,
+				EvalOp:     &ContainsRangeElemOp{},
+				Volatility: volatility.Immutable,
+			},
+		)
+		cmpOps[treecmp.ContainedBy] = append(cmpOps[treecmp.ContainedBy],
+			&CmpOp{
+				LeftType:   t,
+				RightType:  t,
+				EvalOp:     &ContainedByRangeOp{},
+				Volatility: volatility.Immutable,
+			},
+			&CmpOp{
+				LeftType:   elem,
+				RightType:  t,
+				EvalOp:     &ContainedByElemRangeOp{},
+				Volatility: volatility.Immutable,
+			},
+		)
+		cmpOps[treecmp.Overlaps] = append(cmpOps[treecmp.Overlaps], &CmpOp{
+			LeftType:   t,
+			RightType:  t,
+			EvalOp:     &OverlapsRangeOp{},
+			Volatility: volatility.Immutable,
+		})
+		cmpOps[treecmp.Adjacent] = append(cmpOps[treecmp.Adjacent], &CmpOp{
+			LeftType:   t,
+			RightType:  t,
+			EvalOp:     &AdjacentRangeOp{},
+			Volatility: volatility.Immutable,
+		})
+	}
+
 	for op, overload := range cmpOps {
 		for i, impl := range overload {
 			casted := impl.(*CmpOp)
@@ -1769,6 +1856,15 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]cmpOpOverload{
 		},
 	},
 
+	treecmp.JSONPathExists: {
+		&CmpOp{
+			LeftType:   types.Jsonb,
+			RightType:  types.Jsonpath,
+			EvalOp:     &JSONPathExistsOp{},
+			Volatility: volatility.Immutable,
+		},
+	},
+
 	treecmp.Contains: {
 		&CmpOp{
 			LeftType:   types.AnyArray,
@@ -1819,6 +1915,27 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]cmpOpOverload{
 			},
 		)...,
 	),
+
+	treecmp.TSMatches: {
+		&CmpOp{
+			LeftType:   types.TSVector,
+			RightType:  types.TSQuery,
+			EvalOp:     &TSMatchesVectorQueryOp{},
+			Volatility: volatility.Immutable,
+		},
+		&CmpOp{
+			LeftType:   types.TSQuery,
+			RightType:  types.TSVector,
+			EvalOp:     &TSMatchesQueryVectorOp{},
+			Volatility: volatility.Immutable,
+		},
+		&CmpOp{
+			LeftType:   types.Jsonb,
+			RightType:  types.Jsonpath,
+			EvalOp:     &JSONPathMatchOp{},
+			Volatility: volatility.Immutable,
+		},
+	},
 })
 
 func makeBox2DComparisonOperators(op func(lhs, rhs *geo.CartesianBoundingBox) bool) cmpOpOverload {
@@ -73,6 +73,18 @@ type OverlapsArrayOp struct{}
 // OverlapsINetOp is a BinaryEvalOp.
 type OverlapsINetOp struct{}
 
+// OverlapsRangeOp is a BinaryEvalOp.
+type OverlapsRangeOp struct{}
+
+// AdjacentRangeOp is a BinaryEvalOp.
+type AdjacentRangeOp struct{}
+
+// TSMatchesVectorQueryOp is a BinaryEvalOp.
+type TSMatchesVectorQueryOp struct{}
+
+// TSMatchesQueryVectorOp is a BinaryEvalOp.
+type TSMatchesQueryVectorOp struct{}
+
 // AppendToMaybeNullArrayOp is a BinaryEvalOp.
 type AppendToMaybeNullArrayOp struct {
 	Typ *types.T
@@ -297,6 +309,8 @@ type (
 	ConcatJsonbOp struct{}
 	// ConcatStringOp is a BinaryEvalOp.
 	ConcatStringOp struct{}
+	// ConcatTSVectorOp is a BinaryEvalOp.
+	ConcatTSVectorOp struct{}
 	// ConcatVarBitOp is a BinaryEvalOp.
 	ConcatVarBitOp struct{}
 )
@@ -354,12 +368,27 @@ type JSONSomeExistsOp struct{}
 // JSONAllExistsOp is a BinaryEvalOp.
 type JSONAllExistsOp struct{}
 
+// JSONPathExistsOp is a BinaryEvalOp.
+type JSONPathExistsOp struct{}
+
+// JSONPathMatchOp is a BinaryEvalOp.
+type JSONPathMatchOp struct{}
+
 // JSONFetchValPathOp is a BinaryEvalOp.
 type JSONFetchValPathOp struct{}
 
 // JSONFetchTextPathOp is a BinaryEvalOp.
 type JSONFetchTextPathOp struct{}
 
+// DistanceVectorOp is a BinaryEvalOp.
+type DistanceVectorOp struct{}
+
+// NegInnerProductVectorOp is a BinaryEvalOp.
+type NegInnerProductVectorOp struct{}
+
+// CosDistanceVectorOp is a BinaryEvalOp.
+type CosDistanceVectorOp struct{}
+
 // ContainsArrayOp is a BinaryEvalOp.
 type ContainsArrayOp struct{}
 
@@ -371,3 +400,15 @@ type ContainedByArrayOp struct{}
 
 // ContainedByJsonbOp is a BinaryEvalOp.
 type ContainedByJsonbOp struct{}
+
+// ContainsRangeOp is a BinaryEvalOp.
+type ContainsRangeOp struct{}
+
+// ContainsRangeElemOp is a BinaryEvalOp.
+type ContainsRangeElemOp struct{}
+
+// ContainedByRangeOp is a BinaryEvalOp.
+type ContainedByRangeOp struct{}
+
+// ContainedByElemRangeOp is a BinaryEvalOp.
+type ContainedByElemRangeOp struct{}
@@ -32,6 +32,7 @@ type ExprEvaluator interface {
 	EvalComparisonExpr(*ComparisonExpr) (Datum, error)
 	EvalDefaultVal(*DefaultVal) (Datum, error)
 	EvalFuncExpr(*FuncExpr) (Datum, error)
+	EvalGroupingFuncExpr(*GroupingFuncExpr) (Datum, error)
 	EvalIfErrExpr(*IfErrExpr) (Datum, error)
 	EvalIfExpr(*IfExpr) (Datum, error)
 	EvalIndexedVar(*IndexedVar) (Datum, error)
@@ -200,6 +201,11 @@ func (node *DJSON) Eval(v ExprEvaluator) (Datum, error) {
 	return node, nil
 }
 
+// Eval is part of the TypedExpr interface.
+func (node *DJsonpath) Eval(v ExprEvaluator) (Datum, error) {
+	return node, nil
+}
+
 // Eval is part of the TypedExpr interface.
 func (node *DOid) Eval(v ExprEvaluator) (Datum, error) {
 	return node, nil
@@ -210,11 +216,31 @@ func (node *DOidWrapper) Eval(v ExprEvaluator) (Datum, error) {
 	return node, nil
 }
 
+// Eval is part of the TypedExpr interface.
+func (node *DPGVector) Eval(v ExprEvaluator) (Datum, error) {
+	return node, nil
+}
+
+// Eval is part of the TypedExpr interface.
+func (node *DRange) Eval(v ExprEvaluator) (Datum, error) {
+	return node, nil
+}
+
 // Eval is part of the TypedExpr interface.
 func (node *DString) Eval(v ExprEvaluator) (Datum, error) {
 	return node, nil
 }
 
+// Eval is part of the TypedExpr interface.
+func (node *DTSQuery) Eval(v ExprEvaluator) (Datum, error) {
+	return node, nil
+}
+
+// Eval is part of the TypedExpr interface.
+func (node *DTSVector) Eval(v ExprEvaluator) (Datum, error) {
+	return node, nil
+}
+
 // Eval is part of the TypedExpr interface.
 func (node *DTime) Eval(v ExprEvaluator) (Datum, error) {
 	return node, nil
@@ -260,6 +286,11 @@ func (node *FuncExpr) Eval(v ExprEvaluator) (Datum, error) {
 	return v.EvalFuncExpr(node)
 }
 
+// Eval is part of the TypedExpr interface.
+func (node *GroupingFuncExpr) Eval(v ExprEvaluator) (Datum, error) {
+	return v.EvalGroupingFuncExpr(node)
+}
+
 // Eval is part of the TypedExpr interface.
 func (node *IfErrExpr) Eval(v ExprEvaluator) (Datum, error) {
 	return v.EvalIfErrExpr(node)
@@ -50,6 +50,7 @@ type UnaryOpEvaluator interface {
 
 // UnaryOpEvaluator knows how to evaluate BinaryEvalOps.
 type BinaryOpEvaluator interface {
+	EvalAdjacentRangeOp(*AdjacentRangeOp, Datum, Datum) (Datum, error)
 	EvalAppendToMaybeNullArrayOp(*AppendToMaybeNullArrayOp, Datum, Datum) (Datum, error)
 	EvalBitAndINetOp(*BitAndINetOp, Datum, Datum) (Datum, error)
 	EvalBitAndIntOp(*BitAndIntOp, Datum, Datum) (Datum, error)
@@ -67,11 +68,18 @@ type BinaryOpEvaluator interface {
 	EvalConcatJsonbOp(*ConcatJsonbOp, Datum, Datum) (Datum, error)
 	EvalConcatOp(*ConcatOp, Datum, Datum) (Datum, error)
 	EvalConcatStringOp(*ConcatStringOp, Datum, Datum) (Datum, error)
+	EvalConcatTSVectorOp(*ConcatTSVectorOp, Datum, Datum) (Datum, error)
 	EvalConcatVarBitOp(*ConcatVarBitOp, Datum, Datum) (Datum, error)
 	EvalContainedByArrayOp(*ContainedByArrayOp, Datum, Datum) (Datum, error)
+	EvalContainedByElemRangeOp(*ContainedByElemRangeOp, Datum, Datum) (Datum, error)
 	EvalContainedByJsonbOp(*ContainedByJsonbOp, Datum, Datum) (Datum, error)
+	EvalContainedByRangeOp(*ContainedByRangeOp, Datum, Datum) (Datum, error)
 	EvalContainsArrayOp(*ContainsArrayOp, Datum, Datum) (Datum, error)
 	EvalContainsJsonbOp(*ContainsJsonbOp, Datum, Datum) (Datum, error)
+	EvalContainsRangeElemOp(*ContainsRangeElemOp, Datum, Datum) (Datum, error)
+	EvalContainsRangeOp(*ContainsRangeOp, Datum, Datum) (Datum, error)
+	EvalCosDistanceVectorOp(*CosDistanceVectorOp, Datum, Datum) (Datum, error)
+	EvalDistanceVectorOp(*DistanceVectorOp, Datum, Datum) (Datum, error)
 	EvalDivDecimalIntOp(*DivDecimalIntOp, Datum, Datum) (Datum, error)
 	EvalDivDecimalOp(*DivDecimalOp, Datum, Datum) (Datum, error)
 	EvalDivFloatOp(*DivFloatOp, Datum, Datum) (Datum, error)
@@ -93,6 +101,8 @@ type BinaryOpEvaluator interface {
 	EvalJSONFetchValIntOp(*JSONFetchValIntOp, Datum, Datum) (Datum, error)
 	EvalJSONFetchValPathOp(*JSONFetchValPathOp, Datum, Datum) (Datum, error)
 	EvalJSONFetchValStringOp(*JSONFetchValStringOp, Datum, Datum) (Datum, error)
+	EvalJSONPathExistsOp(*JSONPathExistsOp, Datum, Datum) (Datum, error)
+	EvalJSONPathMatchOp(*JSONPathMatchOp, Datum, Datum) (Datum, error)
 	EvalJSONSomeExistsOp(*JSONSomeExistsOp, Datum, Datum) (Datum, error)
 	EvalLShiftINetOp(*LShiftINetOp, Datum, Datum) (Datum, error)
 	EvalLShiftIntOp(*LShiftIntOp, Datum, Datum) (Datum, error)
@@ -140,8 +150,10 @@ type BinaryOpEvaluator interface {
 	EvalMultIntervalDecimalOp(*MultIntervalDecimalOp, Datum, Datum) (Datum, error)
 	EvalMultIntervalFloatOp(*MultIntervalFloatOp, Datum, Datum) (Datum, error)
 	EvalMultIntervalIntOp(*MultIntervalIntOp, Datum, Datum) (Datum, error)
+	EvalNegInnerProductVectorOp(*NegInnerProductVectorOp, Datum, Datum) (Datum, error)
 	EvalOverlapsArrayOp(*OverlapsArrayOp, Datum, Datum) (Datum, error)
 	EvalOverlapsINetOp(*OverlapsINetOp, Datum, Datum) (Datum, error)
+	EvalOverlapsRangeOp(*OverlapsRangeOp, Datum, Datum) (Datum, error)
 	EvalPlusDateIntOp(*PlusDateIntOp, Datum, Datum) (Datum, error)
 	EvalPlusDateIntervalOp(*PlusDateIntervalOp, Datum, Datum) (Datum, error)
 	EvalPlusDateTimeOp(*PlusDateTimeOp, Datum, Datum) (Datum, error)
@@ -176,6 +188,8 @@ type BinaryOpEvaluator interface {
 	EvalRShiftIntOp(*RShiftIntOp, Datum, Datum) (Datum, error)
 	EvalRShiftVarBitIntOp(*RShiftVarBitIntOp, Datum, Datum) (Datum, error)
 	EvalSimilarToOp(*SimilarToOp, Datum, Datum) (Datum, error)
+	EvalTSMatchesQueryVectorOp(*TSMatchesQueryVectorOp, Datum, Datum) (Datum, error)
+	EvalTSMatchesVectorQueryOp(*TSMatchesVectorQueryOp, Datum, Datum) (Datum, error)
 }
 
 
@@ -234,6 +248,11 @@ func (op *UnaryMinusIntervalOp) Eval(e OpEvaluator, v Datum) (Datum, error) {
 	return e.EvalUnaryMinusIntervalOp(op, v)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *AdjacentRangeOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalAdjacentRangeOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *AppendToMaybeNullArrayOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalAppendToMaybeNullArrayOp(op, a, b)
@@ -319,6 +338,11 @@ func (op *ConcatStringOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalConcatStringOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *ConcatTSVectorOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalConcatTSVectorOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *ConcatVarBitOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalConcatVarBitOp(op, a, b)
@@ -329,11 +353,21 @@ func (op *ContainedByArrayOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalContainedByArrayOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *ContainedByElemRangeOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalContainedByElemRangeOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *ContainedByJsonbOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalContainedByJsonbOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *ContainedByRangeOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalContainedByRangeOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *ContainsArrayOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalContainsArrayOp(op, a, b)
@@ -344,6 +378,26 @@ func (op *ContainsJsonbOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalContainsJsonbOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *ContainsRangeElemOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalContainsRangeElemOp(op, a, b)
+}
+
+// Eval is part of the BinaryEvalOp interface.
+func (op *ContainsRangeOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalContainsRangeOp(op, a, b)
+}
+
+// Eval is part of the BinaryEvalOp interface.
+func (op *CosDistanceVectorOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalCosDistanceVectorOp(op, a, b)
+}
+
+// Eval is part of the BinaryEvalOp interface.
+func (op *DistanceVectorOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalDistanceVectorOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *DivDecimalIntOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalDivDecimalIntOp(op, a, b)
@@ -449,6 +503,16 @@ func (op *JSONFetchValStringOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalJSONFetchValStringOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *JSONPathExistsOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalJSONPathExistsOp(op, a, b)
+}
+
+// Eval is part of the BinaryEvalOp interface.
+func (op *JSONPathMatchOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalJSONPathMatchOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *JSONSomeExistsOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalJSONSomeExistsOp(op, a, b)
@@ -684,6 +748,11 @@ func (op *MultIntervalIntOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalMultIntervalIntOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *NegInnerProductVectorOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalNegInnerProductVectorOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *OverlapsArrayOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalOverlapsArrayOp(op, a, b)
@@ -694,6 +763,11 @@ func (op *OverlapsINetOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalOverlapsINetOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *OverlapsRangeOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalOverlapsRangeOp(op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *PlusDateIntOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalPlusDateIntOp(op, a, b)
@@ -864,3 +938,13 @@ func (op *SimilarToOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalSimilarToOp(op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *TSMatchesQueryVectorOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalTSMatchesQueryVectorOp(op, a, b)
+}
+
+// Eval is part of the BinaryEvalOp interface.
+func (op *TSMatchesVectorQueryOp) Eval(e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalTSMatchesVectorQueryOp(op, a, b)
+}
+
@@ -722,6 +722,23 @@ func (node *CoalesceExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// GroupingFuncExpr represents a GROUPING(a, b, ...) expression, which returns
+// a bit mask indicating which of its arguments are not included in the
+// grouping set of the current row. The argument expressions must match GROUP
+// BY expressions.
+type GroupingFuncExpr struct {
+	Exprs Exprs
+
+	typeAnnotation
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingFuncExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING(")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DefaultVal represents the DEFAULT expression.
 type DefaultVal struct{}
 
@@ -1002,6 +1019,7 @@ var binaryOpPrio = [...]int{
 	treebin.Bitxor: 6,
 	treebin.Bitor:  7,
 	treebin.Concat: 8, treebin.JSONFetchVal: 8, treebin.JSONFetchText: 8, treebin.JSONFetchValPath: 8, treebin.JSONFetchTextPath: 8,
+	treebin.Distance: 8, treebin.NegInnerProduct: 8, treebin.CosDistance: 8,
 }
 
 // binaryOpFullyAssoc indicates whether an operator is fully associative.
@@ -1015,6 +1033,7 @@ var binaryOpFullyAssoc = [...]bool{
 	treebin.Bitxor: true,
 	treebin.Bitor:  true,
 	treebin.Concat: true, treebin.JSONFetchVal: false, treebin.JSONFetchText: false, treebin.JSONFetchValPath: false, treebin.JSONFetchTextPath: false,
+	treebin.Distance: false, treebin.NegInnerProduct: false, treebin.CosDistance: false,
 }
 
 // BinaryExpr represents a binary value expression.
@@ -1648,6 +1667,8 @@ func (node *CaseExpr) String() string         { return AsString(node) }
 func (node *CastExpr) String() string         { return AsString(node) }
 func (node *CoalesceExpr) String() string     { return AsString(node) }
 func (node *ColumnAccessExpr) String() string { return AsString(node) }
+func (node *GroupingFuncExpr) String() string { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *CollateExpr) String() string      { return AsString(node) }
 func (node *ComparisonExpr) String() string   { return AsString(node) }
 func (node *Datums) String() string           { return AsString(node) }
@@ -1666,12 +1687,17 @@ func (node *DGeometry) String() string        { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DJsonpath) String() string        { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
+func (node *DPGVector) String() string        { return AsString(node) }
+func (node *DRange) String() string           { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
 func (node *DArray) String() string           { return AsString(node) }
 func (node *DOid) String() string             { return AsString(node) }
@@ -23,10 +23,37 @@ import "github.com/cockroachdb/cockroach/pkg/sql/privilege"
 
 // Grant represents a GRANT statement.
 type Grant struct {
-	Privileges      privilege.List
-	Targets         TargetList
-	Grantees        RoleSpecList
-	WithGrantOption bool
+	Privileges privilege.List
+	// ColumnPrivileges is set instead of Privileges when the privileges are
+	// granted on columns of tables, as in GRANT SELECT (a, b) ON t.
+	ColumnPrivileges ColumnPrivileges
+	Targets          TargetList
+	Grantees         RoleSpecList
+	WithGrantOption  bool
+}
+
+// ColumnPrivilege represents a privilege on a list of columns, as in
+// SELECT (a, b).
+type ColumnPrivilege struct {
+	Privilege privilege.Kind
+	Columns   NameList
+}
+
+// ColumnPrivileges represents a list of privileges on columns.
+type ColumnPrivileges []ColumnPrivilege
+
+// Format implements the NodeFormatter interface.
+func (l *ColumnPrivileges) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		p := &(*l)[i]
+		ctx.WriteString(p.Privilege.String())
+		ctx.WriteString(" (")
+		ctx.FormatNode(&p.Columns)
+		ctx.WriteByte(')')
+	}
 }
 
 // TargetList represents a list of targets.
@@ -37,6 +64,10 @@ type TargetList struct {
 	Tables    TableAttrs
 	TenantID  TenantID
 	Types     []*UnresolvedObjectName
+	// Functions and Procedures identify user-defined functions and
+	// procedures by their signatures.
+	Functions  AggregateSignatures
+	Procedures AggregateSignatures
 	// If the target is for all sequences in a set of schemas.
 	AllSequencesInSchema bool
 	// If the target is for all tables in a set of schemas.
@@ -76,6 +107,12 @@ func (tl *TargetList) Format(ctx *FmtCtx) {
 			}
 			ctx.FormatNode(typ)
 		}
+	} else if tl.Functions != nil {
+		ctx.WriteString("FUNCTION ")
+		ctx.FormatNode(tl.Functions)
+	} else if tl.Procedures != nil {
+		ctx.WriteString("PROCEDURE ")
+		ctx.FormatNode(tl.Procedures)
 	} else {
 		if tl.Tables.SequenceOnly {
 			ctx.WriteString("SEQUENCE ")
@@ -92,7 +129,11 @@ func (node *Grant) Format(ctx *FmtCtx) {
 	if node.Targets.System {
 		ctx.WriteString(" SYSTEM ")
 	}
-	node.Privileges.Format(&ctx.Buffer)
+	if node.ColumnPrivileges != nil {
+		ctx.FormatNode(&node.ColumnPrivileges)
+	} else {
+		node.Privileges.Format(&ctx.Buffer)
+	}
 	if !node.Targets.System {
 		ctx.WriteString(" ON ")
 		ctx.FormatNode(&node.Targets)
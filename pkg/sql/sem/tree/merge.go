@@ -0,0 +1,122 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Merge represents a MERGE statement.
+type Merge struct {
+	With   *With
+	Table  TableExpr
+	Source TableExpr
+	On     Expr
+	Whens  []*MergeWhen
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Merge) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.With)
+	ctx.WriteString("MERGE INTO ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" USING ")
+	ctx.FormatNode(node.Source)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.On)
+	for _, w := range node.Whens {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(w)
+	}
+}
+
+// MergeWhen represents a WHEN clause of a MERGE statement. Its action is
+// applied to the rows which satisfy the condition and either match a target
+// row or not, as specified by Matched.
+type MergeWhen struct {
+	Matched bool
+	// Cond is nil if the clause has no AND condition.
+	Cond   Expr
+	Action MergeAction
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhen) Format(ctx *FmtCtx) {
+	if node.Matched {
+		ctx.WriteString("WHEN MATCHED")
+	} else {
+		ctx.WriteString("WHEN NOT MATCHED")
+	}
+	if node.Cond != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Cond)
+	}
+	ctx.WriteString(" THEN ")
+	ctx.FormatNode(node.Action)
+}
+
+// MergeAction is the action of a WHEN clause of a MERGE statement.
+type MergeAction interface {
+	NodeFormatter
+	mergeAction()
+}
+
+func (*MergeUpdate) mergeAction()    {}
+func (*MergeDelete) mergeAction()    {}
+func (*MergeInsert) mergeAction()    {}
+func (*MergeDoNothing) mergeAction() {}
+
+// MergeUpdate represents an UPDATE action of a MERGE statement.
+type MergeUpdate struct {
+	Exprs UpdateExprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeUpdate) Format(ctx *FmtCtx) {
+	ctx.WriteString("UPDATE SET ")
+	ctx.FormatNode(&node.Exprs)
+}
+
+// MergeDelete represents a DELETE action of a MERGE statement.
+type MergeDelete struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeDelete) Format(ctx *FmtCtx) {
+	ctx.WriteString("DELETE")
+}
+
+// MergeInsert represents an INSERT action of a MERGE statement.
+type MergeInsert struct {
+	Columns NameList
+	// Values is nil for INSERT DEFAULT VALUES.
+	Values Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeInsert) Format(ctx *FmtCtx) {
+	ctx.WriteString("INSERT")
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" (")
+		ctx.FormatNode(&node.Columns)
+		ctx.WriteByte(')')
+	}
+	if node.Values == nil {
+		ctx.WriteString(" DEFAULT VALUES")
+		return
+	}
+	ctx.WriteString(" VALUES (")
+	ctx.FormatNode(&node.Values)
+	ctx.WriteByte(')')
+}
+
+// MergeDoNothing represents a DO NOTHING action of a MERGE statement.
+type MergeDoNothing struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeDoNothing) Format(ctx *FmtCtx) {
+	ctx.WriteString("DO NOTHING")
+}
@@ -143,7 +143,9 @@ type SearchPath interface {
 // function definition resolution functionality.
 type CustomFunctionDefinitionResolver interface {
 	// Resolve resolves function with specified name, and returns
-	// non-nil function definition if resolved successfully.
+	// non-nil function definition if resolved successfully. The name is
+	// prefixed with the catalog and schema of the function if they were
+	// specified, e.g. "db.sc.fn".
 	Resolve(name string) *FunctionDefinition
 }
 
@@ -195,9 +197,9 @@ func (n *UnresolvedName) ResolveFunction(searchPath SearchPath) (*FunctionDefini
 
 	resolveFn := getFunctionDefinitionResolver(searchPath)
 
-	// We ignore the catalog part. Like explained above, we currently
-	// only support functions in virtual schemas, which always exist
-	// independently of the database/catalog prefix.
+	// We ignore the catalog part, unless a custom resolver resolves the
+	// user-defined functions of the databases. The functions of virtual
+	// schemas always exist independently of the database/catalog prefix.
 	function, prefix := n.Parts[0], n.Parts[1]
 
 	if d := resolveFn(function); d != nil && prefix == "" {
@@ -223,6 +225,9 @@ func (n *UnresolvedName) ResolveFunction(searchPath SearchPath) (*FunctionDefini
 
 	if prefix != "" {
 		fullName = prefix + "." + function
+		if _, ok := searchPath.(CustomFunctionDefinitionResolver); ok && n.NumParts == 3 {
+			fullName = n.Parts[2] + "." + fullName
+		}
 	}
 	def := resolveFn(fullName)
 	if def == nil {
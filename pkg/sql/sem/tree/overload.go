@@ -83,6 +83,20 @@ type SQLFnOverload interface {
 	SQLFn()
 }
 
+// UserDefinedAggregateOverload is an opaque type used to box the definition of
+// an aggregate created with CREATE AGGREGATE. It ought to be a
+// *descpb.FunctionDescriptor whose Aggregate field is set.
+type UserDefinedAggregateOverload interface {
+	UserDefinedAggregate()
+}
+
+// UserDefinedFunctionOverload is an opaque type used to box the definition of
+// a function created with CREATE FUNCTION. It ought to be a
+// *descpb.FunctionDescriptor.
+type UserDefinedFunctionOverload interface {
+	UserDefinedFunction()
+}
+
 // Overload is one of the overloads of a built-in function.
 // Each FunctionDefinition may contain one or more overloads.
 type Overload struct {
@@ -133,6 +147,16 @@ type Overload struct {
 	// statement which will be executed as a common table expression in the query.
 	SQLFn SQLFnOverload
 
+	// UserDefinedAggregate is set for the overloads of aggregates created with
+	// CREATE AGGREGATE instead of AggregateFunc. The aggregate is evaluated by
+	// the execution engine using the expressions in its definition.
+	UserDefinedAggregate UserDefinedAggregateOverload
+
+	// UserDefinedFunction is set for the overloads of functions created with
+	// CREATE FUNCTION, in addition to Fn, which interprets the body of the
+	// function.
+	UserDefinedFunction UserDefinedFunctionOverload
+
 	// OnTypeCheck is incremented every time this overload is type checked.
 	OnTypeCheck func()
 
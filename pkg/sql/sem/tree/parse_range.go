@@ -0,0 +1,179 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+var malformedRangeError = pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed range literal")
+
+type rangeParseState struct {
+	s                string
+	ctx              ParseTimeContext
+	dependsOnContext bool
+	t                *types.T
+}
+
+func (p *rangeParseState) eatWhitespace() {
+	p.s = strings.TrimLeftFunc(p.s, unicode.IsSpace)
+}
+
+func (p *rangeParseState) peek() byte {
+	if len(p.s) == 0 {
+		return 0
+	}
+	return p.s[0]
+}
+
+// isRangeBoundTerminator returns whether the character ends an unquoted bound
+// of a range.
+func isRangeBoundTerminator(ch byte) bool {
+	return ch == ',' || ch == ')' || ch == ']'
+}
+
+// parseBound parses a bound of the range. It returns DNull if the bound is
+// empty, which denotes an infinite bound. Like in tuples, the bound can be
+// double quoted, and a backslash escapes the following character.
+func (p *rangeParseState) parseBound() (Datum, error) {
+	if len(p.s) > 0 && isRangeBoundTerminator(p.s[0]) {
+		return DNull, nil
+	}
+	var result strings.Builder
+	inQuote, quoted := false, false
+	i := 0
+	for ; i < len(p.s); i++ {
+		ch := p.s[i]
+		if !inQuote && isRangeBoundTerminator(ch) {
+			break
+		}
+		switch ch {
+		case '\\':
+			i++
+			if i >= len(p.s) {
+				return nil, errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+			}
+			result.WriteByte(p.s[i])
+		case '"':
+			if inQuote && i+1 < len(p.s) && p.s[i+1] == '"' {
+				// Two double quotes within a quoted string are an escaped double
+				// quote.
+				result.WriteByte('"')
+				i++
+			} else {
+				inQuote, quoted = !inQuote, true
+			}
+		default:
+			result.WriteByte(ch)
+		}
+	}
+	if i >= len(p.s) {
+		return nil, errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+	}
+	p.s = p.s[i:]
+	str := result.String()
+	if !quoted {
+		// Surrounding whitespace is only significant within quotes.
+		str = strings.TrimSpace(str)
+	}
+	d, dependsOnContext, err := ParseAndRequireString(p.t.RangeContents(), str, p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dependsOnContext {
+		p.dependsOnContext = true
+	}
+	return d, nil
+}
+
+// ParseDRangeFromString parses the string-form of a range, handling cases such
+// as `'[1,10)'::INT4RANGE` and `'empty'::NUMRANGE`. The input type t is the
+// type of the range to parse.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseTimeContext (either for the time or the local timezone).
+func ParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	ret, dependsOnContext, err := doParseDRangeFromString(ctx, s, t)
+	if err != nil {
+		return ret, false, MakeParseError(s, t, err)
+	}
+	return ret, dependsOnContext, nil
+}
+
+// doParseDRangeFromString does most of the work of ParseDRangeFromString,
+// except the error it returns isn't prettified as a parsing error.
+func doParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	if t.RangeContents() == nil {
+		return nil, false, errors.AssertionFailedf("not a range type %s (%T)", t, t)
+	}
+	parser := rangeParseState{s: s, ctx: ctx, t: t}
+
+	parser.eatWhitespace()
+	if len(parser.s) >= len("empty") && strings.EqualFold(parser.s[:len("empty")], "empty") {
+		parser.s = parser.s[len("empty"):]
+		parser.eatWhitespace()
+		if parser.s != "" {
+			return nil, false, errors.WithDetail(malformedRangeError, "Junk after \"empty\" key word.")
+		}
+		return NewDEmptyRange(t), false, nil
+	}
+
+	var lowerInc bool
+	switch parser.peek() {
+	case '[':
+		lowerInc = true
+	case '(':
+	default:
+		return nil, false, errors.WithDetail(malformedRangeError, "Missing left parenthesis or bracket.")
+	}
+	parser.s = parser.s[1:]
+	lower, err := parser.parseBound()
+	if err != nil {
+		return nil, false, err
+	}
+	if parser.peek() != ',' {
+		return nil, false, errors.WithDetail(malformedRangeError, "Missing comma after lower bound.")
+	}
+	parser.s = parser.s[1:]
+	upper, err := parser.parseBound()
+	if err != nil {
+		return nil, false, err
+	}
+	var upperInc bool
+	switch parser.peek() {
+	case ']':
+		upperInc = true
+	case ')':
+	default:
+		return nil, false, errors.WithDetail(malformedRangeError, "Too many commas.")
+	}
+	parser.s = parser.s[1:]
+	parser.eatWhitespace()
+	if parser.s != "" {
+		return nil, false, errors.WithDetail(malformedRangeError, "Junk after right parenthesis or bracket.")
+	}
+
+	r, err := NewDRange(t, lower, upper, lowerInc, upperInc)
+	if err != nil {
+		return nil, false, err
+	}
+	return r, parser.dependsOnContext, nil
+}
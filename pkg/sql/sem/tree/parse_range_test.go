@@ -0,0 +1,106 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	mustRange := func(typ *types.T, lower, upper tree.Datum, lowerInc, upperInc bool) *tree.DRange {
+		r, err := tree.NewDRange(typ, lower, upper, lowerInc, upperInc)
+		require.NoError(t, err)
+		return r
+	}
+	one, two := tree.NewDInt(1), tree.NewDInt(2)
+	dec := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		return d
+	}
+
+	testData := []struct {
+		str      string
+		typ      *types.T
+		expected *tree.DRange
+	}{
+		{`empty`, types.Int4Range, tree.NewDEmptyRange(types.Int4Range)},
+		{` EMPTY `, types.Int4Range, tree.NewDEmptyRange(types.Int4Range)},
+		{`[1,2)`, types.Int4Range, mustRange(types.Int4Range, one, two, true, false)},
+		{`[1,1]`, types.Int8Range, mustRange(types.Int8Range, one, two, true, false)},
+		{`(0,1]`, types.Int8Range, mustRange(types.Int8Range, one, two, true, false)},
+		{`[1,1)`, types.Int8Range, tree.NewDEmptyRange(types.Int8Range)},
+		{`( , 2)`, types.Int4Range, mustRange(types.Int4Range, tree.DNull, two, false, false)},
+		{`[1,]`, types.Int4Range, mustRange(types.Int4Range, one, tree.DNull, true, false)},
+		{`(,)`, types.NumRange, mustRange(types.NumRange, tree.DNull, tree.DNull, false, false)},
+		{`[ 1.5 , 2.5 ]`, types.NumRange, mustRange(types.NumRange, dec("1.5"), dec("2.5"), true, true)},
+		{`("1.5","2.5")`, types.NumRange, mustRange(types.NumRange, dec("1.5"), dec("2.5"), false, false)},
+		{`[\1,2\.5)`, types.NumRange, mustRange(types.NumRange, dec("1"), dec("2.5"), true, false)},
+	}
+	for _, td := range testData {
+		t.Run(td.str, func(t *testing.T) {
+			evalContext := eval.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+			actual, _, err := tree.ParseDRangeFromString(evalContext, td.str, td.typ)
+			if err != nil {
+				t.Fatalf("range %s: got error %s, expected %s", td.str, err.Error(), td.expected)
+			}
+			if actual.Compare(evalContext, td.expected) != 0 {
+				t.Fatalf("range %s: got %s, expected %s", td.str, actual, td.expected)
+			}
+		})
+	}
+}
+
+func TestParseRangeError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testData := []struct {
+		str           string
+		typ           *types.T
+		expectedError string
+	}{
+		{``, types.Int4Range, `malformed range literal`},
+		{`1,2`, types.Int4Range, `malformed range literal`},
+		{`[1,2`, types.Int4Range, `malformed range literal`},
+		{`[1,2,3)`, types.Int4Range, `malformed range literal`},
+		{`[1,2) x`, types.Int4Range, `malformed range literal`},
+		{`empty x`, types.Int4Range, `malformed range literal`},
+		{`["1,2)`, types.Int4Range, `malformed range literal`},
+		{`[1,hello)`, types.Int4Range, `strconv.ParseInt: parsing "hello": invalid syntax`},
+		{`[2,1)`, types.Int4Range, `range lower bound must be less than or equal to range upper bound`},
+		{`[2.5,1)`, types.NumRange, `range lower bound must be less than or equal to range upper bound`},
+	}
+	for _, td := range testData {
+		t.Run(td.str, func(t *testing.T) {
+			_, _, err := tree.ParseDRangeFromString(
+				eval.NewTestingEvalContext(cluster.MakeTestingClusterSettings()), td.str, td.typ)
+			if err == nil {
+				t.Fatalf("expected %#v to error with message %#v", td.str, td.expectedError)
+			}
+			if !strings.HasSuffix(err.Error(), td.expectedError) {
+				t.Fatalf("range %s: got error %s, expected suffix %s", td.str, err.Error(), td.expectedError)
+			}
+		})
+	}
+}
@@ -93,6 +93,16 @@ func ParseAndRequireString(
 		d, err = MakeDEnumFromLogicalRepresentation(t, s)
 	case types.TupleFamily:
 		d, dependsOnContext, err = ParseDTupleFromString(ctx, s, t)
+	case types.RangeFamily:
+		d, dependsOnContext, err = ParseDRangeFromString(ctx, s, t)
+	case types.JsonpathFamily:
+		d, err = ParseDJsonpath(s)
+	case types.TSQueryFamily:
+		d, err = ParseDTSQuery(s)
+	case types.TSVectorFamily:
+		d, err = ParseDTSVector(s)
+	case types.PGVectorFamily:
+		d, err = ParseDPGVector(s)
 	case types.VoidFamily:
 		d = DVoidDatum
 	default:
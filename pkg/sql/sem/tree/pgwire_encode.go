@@ -99,6 +99,28 @@ func pgwireFormatStringInTuple(buf *bytes.Buffer, in string) {
 	}
 }
 
+// formatStringInRange writes the text representation of a bound of a range,
+// which is quoted in the same way as the elements of a tuple, except that
+// square brackets also require quoting.
+func formatStringInRange(buf *bytes.Buffer, in string) {
+	quote := in == "" || rangeQuoteSet.in(in)
+	if quote {
+		buf.WriteByte('"')
+	}
+	for _, r := range in {
+		if r == '"' || r == '\\' {
+			// Like in tuples, " and \ are doubled.
+			buf.WriteByte(byte(r))
+			buf.WriteByte(byte(r))
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	if quote {
+		buf.WriteByte('"')
+	}
+}
+
 func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	// When converting an array to text in "postgres mode" there is
 	// special behavior: values are printed in "postgres mode" then the
@@ -160,7 +182,7 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	}
 }
 
-var tupleQuoteSet, arrayQuoteSet asciiSet
+var tupleQuoteSet, arrayQuoteSet, rangeQuoteSet asciiSet
 
 func init() {
 	var ok bool
@@ -172,6 +194,10 @@ func init() {
 	if !ok {
 		panic("array asciiset")
 	}
+	rangeQuoteSet, ok = makeASCIISet(" \t\v\f\r\n()[],\"\\")
+	if !ok {
+		panic("range asciiset")
+	}
 }
 
 // PgwireFormatFloat returns a []byte representing a float according to
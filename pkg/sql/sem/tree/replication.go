@@ -0,0 +1,103 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/util/lsn"
+
+// This file contains the commands of the streaming replication protocol,
+// which can be sent by clients that connect with the "replication" startup
+// parameter set to "database". These commands are not part of the SQL
+// grammar; they are parsed by the pgrepl package.
+//
+// See https://www.postgresql.org/docs/current/protocol-replication.html.
+
+// IdentifySystem represents an IDENTIFY_SYSTEM replication command.
+type IdentifySystem struct{}
+
+var _ Statement = &IdentifySystem{}
+
+// Format implements the NodeFormatter interface.
+func (node *IdentifySystem) Format(ctx *FmtCtx) {
+	ctx.WriteString("IDENTIFY_SYSTEM")
+}
+
+// CreateReplicationSlot represents a CREATE_REPLICATION_SLOT replication
+// command. Only logical replication slots are supported.
+type CreateReplicationSlot struct {
+	Slot      Name
+	Temporary bool
+	Plugin    Name
+}
+
+var _ Statement = &CreateReplicationSlot{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Slot)
+	if node.Temporary {
+		ctx.WriteString(" TEMPORARY")
+	}
+	ctx.WriteString(" LOGICAL ")
+	ctx.FormatNode(&node.Plugin)
+}
+
+// DropReplicationSlot represents a DROP_REPLICATION_SLOT replication command.
+type DropReplicationSlot struct {
+	Slot Name
+	Wait bool
+}
+
+var _ Statement = &DropReplicationSlot{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Slot)
+	if node.Wait {
+		ctx.WriteString(" WAIT")
+	}
+}
+
+// StartReplication represents a START_REPLICATION replication command for a
+// logical replication slot.
+type StartReplication struct {
+	Slot     Name
+	StartLSN lsn.LSN
+	// Options contains the options passed to the output plugin. The values
+	// are string literals, or nil if no value was specified.
+	Options KVOptions
+}
+
+var _ Statement = &StartReplication{}
+
+// Format implements the NodeFormatter interface.
+func (node *StartReplication) Format(ctx *FmtCtx) {
+	ctx.WriteString("START_REPLICATION SLOT ")
+	ctx.FormatNode(&node.Slot)
+	ctx.WriteString(" LOGICAL ")
+	ctx.WriteString(node.StartLSN.String())
+	if len(node.Options) > 0 {
+		ctx.WriteString(" (")
+		for i := range node.Options {
+			o := &node.Options[i]
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&o.Key)
+			if o.Value != nil {
+				ctx.WriteByte(' ')
+				ctx.FormatNode(o.Value)
+			}
+		}
+		ctx.WriteByte(')')
+	}
+}
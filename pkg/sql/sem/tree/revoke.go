@@ -24,10 +24,13 @@ import "github.com/cockroachdb/cockroach/pkg/sql/privilege"
 // Revoke represents a REVOKE statement.
 // PrivilegeList and TargetList are defined in grant.go
 type Revoke struct {
-	Privileges     privilege.List
-	Targets        TargetList
-	Grantees       RoleSpecList
-	GrantOptionFor bool
+	Privileges privilege.List
+	// ColumnPrivileges is set instead of Privileges when the privileges are
+	// revoked on columns of tables, as in REVOKE SELECT (a, b) ON t.
+	ColumnPrivileges ColumnPrivileges
+	Targets          TargetList
+	Grantees         RoleSpecList
+	GrantOptionFor   bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -39,7 +42,11 @@ func (node *Revoke) Format(ctx *FmtCtx) {
 	// NB: we cannot use FormatNode() here because node.Privileges is
 	// not an AST node. This is OK, because a privilege list cannot
 	// contain sensitive information.
-	node.Privileges.Format(&ctx.Buffer)
+	if node.ColumnPrivileges != nil {
+		ctx.FormatNode(&node.ColumnPrivileges)
+	} else {
+		node.Privileges.Format(&ctx.Buffer)
+	}
 	if !node.Targets.System {
 		ctx.WriteString(" ON ")
 		ctx.FormatNode(&node.Targets)
@@ -697,6 +697,48 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType is the type of a GroupingSet.
+type GroupingSetType int
+
+// GroupingSetType values.
+const (
+	// RollupGroupingSet is ROLLUP (a, b, ...), which groups by each prefix of
+	// the given expressions (including the empty prefix).
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet is CUBE (a, b, ...), which groups by each subset of the
+	// given expressions.
+	CubeGroupingSet
+	// ExplicitGroupingSets is GROUPING SETS (...), which groups by each of the
+	// given grouping sets.
+	ExplicitGroupingSets
+)
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. Each of the Exprs of a ROLLUP or CUBE is either an expression or a
+// Tuple of expressions which are grouped together. Each of the Exprs of
+// GROUPING SETS is an expression, a Tuple of expressions (the empty Tuple is
+// the empty grouping set) or a nested GroupingSet.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+var _ Expr = &GroupingSet{}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	switch node.Type {
+	case RollupGroupingSet:
+		ctx.WriteString("ROLLUP (")
+	case CubeGroupingSet:
+		ctx.WriteString("CUBE (")
+	case ExplicitGroupingSets:
+		ctx.WriteString("GROUPING SETS (")
+	}
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
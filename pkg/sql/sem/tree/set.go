@@ -102,6 +102,34 @@ func (node *SetTransaction) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Modes)
 }
 
+// SetConstraints represents a SET CONSTRAINTS statement, which changes when
+// deferrable constraints are checked in the current transaction.
+type SetConstraints struct {
+	// Names are the names of the constraints whose mode is changed. If it is
+	// empty, the mode of all deferrable constraints is changed (SET CONSTRAINTS
+	// ALL).
+	Names NameList
+	// Deferred is true if checking of the constraints is deferred to the end of
+	// the transaction, and false if they are checked at the end of each
+	// statement.
+	Deferred bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *SetConstraints) Format(ctx *FmtCtx) {
+	ctx.WriteString("SET CONSTRAINTS ")
+	if len(node.Names) == 0 {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Names)
+	}
+	if node.Deferred {
+		ctx.WriteString(" DEFERRED")
+	} else {
+		ctx.WriteString(" IMMEDIATE")
+	}
+}
+
 // SetSessionAuthorizationDefault represents a SET SESSION AUTHORIZATION DEFAULT
 // statement. This can be extended (and renamed) if we ever support names in the
 // last position.
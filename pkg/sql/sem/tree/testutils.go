@@ -79,6 +79,9 @@ func SampleDatum(t *types.T) Datum {
 	case types.JsonFamily:
 		j, _ := ParseDJSON(`{"a": "b"}`)
 		return j
+	case types.JsonpathFamily:
+		p, _ := ParseDJsonpath(`$.a[*] ? (@ > 1)`)
+		return p
 	case types.OidFamily:
 		return NewDOid(1009)
 	case types.Box2DFamily:
@@ -88,6 +91,18 @@ func SampleDatum(t *types.T) Datum {
 		return NewDGeography(geo.MustParseGeographyFromEWKB([]byte("\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\xf0\x3f")))
 	case types.GeometryFamily:
 		return NewDGeometry(geo.MustParseGeometryFromEWKB([]byte("\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\xf0\x3f")))
+	case types.TSQueryFamily:
+		q, _ := ParseDTSQuery(`fat & (rat | cat)`)
+		return q
+	case types.TSVectorFamily:
+		v, _ := ParseDTSVector(`a:1 fat:2 cat:3A`)
+		return v
+	case types.PGVectorFamily:
+		v, _ := ParseDPGVector(`[1,2,3]`)
+		return v
+	case types.RangeFamily:
+		r, _ := NewDRange(t, SampleDatum(t.RangeContents()), DNull, true /* lowerInc */, false /* upperInc */)
+		return r
 	default:
 		panic(errors.AssertionFailedf("SampleDatum not implemented for %s", t))
 	}
@@ -60,6 +60,9 @@ const (
 	JSONFetchText
 	JSONFetchValPath
 	JSONFetchTextPath
+	Distance
+	NegInnerProduct
+	CosDistance
 
 	NumBinaryOperatorSymbols
 )
@@ -84,6 +87,9 @@ var binaryOpName = [...]string{
 	JSONFetchText:     "->>",
 	JSONFetchValPath:  "#>",
 	JSONFetchTextPath: "#>>",
+	Distance:          "<->",
+	NegInnerProduct:   "<#>",
+	CosDistance:       "<=>",
 }
 
 // IsPadded returns whether the binary operator needs to be padded.
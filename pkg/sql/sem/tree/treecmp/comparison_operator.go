@@ -68,7 +68,10 @@ const (
 	JSONExists
 	JSONSomeExists
 	JSONAllExists
+	JSONPathExists
 	Overlaps
+	TSMatches
+	Adjacent
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -119,7 +122,10 @@ var comparisonOpName = [...]string{
 	JSONExists:        "?",
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
+	JSONPathExists:    "@?",
 	Overlaps:          "&&",
+	TSMatches:         "@@",
+	Adjacent:          "-|-",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -51,17 +51,23 @@ func (node *CreateFunction) Format(ctx *FmtCtx) {
 	if node.Replace {
 		ctx.WriteString("OR REPLACE ")
 	}
-	ctx.WriteString("FUNCTION ")
+	if node.IsProcedure {
+		ctx.WriteString("PROCEDURE ")
+	} else {
+		ctx.WriteString("FUNCTION ")
+	}
 	ctx.FormatNode(&node.FuncName)
 	ctx.WriteString("(")
 	ctx.FormatNode(node.Args)
 	ctx.WriteString(") ")
-	ctx.WriteString("RETURNS ")
-	if node.ReturnType.IsSet {
-		ctx.WriteString("SETOF ")
+	if !node.IsProcedure {
+		ctx.WriteString("RETURNS ")
+		if node.ReturnType.IsSet {
+			ctx.WriteString("SETOF ")
+		}
+		ctx.WriteString(node.ReturnType.Type.SQLString())
+		ctx.WriteString(" ")
 	}
-	ctx.WriteString(node.ReturnType.Type.SQLString())
-	ctx.WriteString(" ")
 	var funcBody FunctionBodyStr
 	for _, option := range node.Options {
 		switch t := option.(type) {
@@ -189,6 +195,8 @@ const (
 	_ FunctionLanguage = iota
 	// FunctionLangSQL represent SQL language.
 	FunctionLangSQL
+	// FunctionLangPLpgSQL represent PL/pgSQL language.
+	FunctionLangPLpgSQL
 )
 
 // Format implements the NodeFormatter interface.
@@ -197,6 +205,8 @@ func (node FunctionLanguage) Format(ctx *FmtCtx) {
 	switch node {
 	case FunctionLangSQL:
 		ctx.WriteString("SQL")
+	case FunctionLangPLpgSQL:
+		ctx.WriteString("plpgsql")
 	default:
 		panic(pgerror.New(pgcode.InvalidParameterValue, "Unknown function option"))
 	}
@@ -208,6 +218,8 @@ func AsFunctionLanguage(lang string) (FunctionLanguage, error) {
 	switch strings.ToLower(lang) {
 	case "sql":
 		return FunctionLangSQL, nil
+	case "plpgsql":
+		return FunctionLangPLpgSQL, nil
 	}
 	return 0, errors.Newf("language %q does not exist", lang)
 }
@@ -289,3 +301,71 @@ type FuncReturnType struct {
 	Type  ResolvableTypeReference
 	IsSet bool
 }
+
+// DropFunction represents a DROP FUNCTION or DROP PROCEDURE statement.
+// Functions are identified by their name and the types of their arguments,
+// like aggregates.
+type DropFunction struct {
+	IsProcedure  bool
+	Functions    AggregateSignatures
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	if node.IsProcedure {
+		ctx.WriteString("DROP PROCEDURE ")
+	} else {
+		ctx.WriteString("DROP FUNCTION ")
+	}
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// DoBlock represents a DO statement, which executes an anonymous code block.
+type DoBlock struct {
+	Code string
+	// Language is the language of the code. It is zero if the statement does
+	// not specify it, in which case the code is PL/pgSQL.
+	Language FunctionLanguage
+}
+
+var _ Statement = &DoBlock{}
+
+// Format implements the NodeFormatter interface.
+func (node *DoBlock) Format(ctx *FmtCtx) {
+	ctx.WriteString("DO ")
+	if node.Language != 0 {
+		ctx.FormatNode(node.Language)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("$$")
+	ctx.WriteString(node.Code)
+	ctx.WriteString("$$")
+}
+
+// Call represents a CALL statement, which invokes a procedure.
+type Call struct {
+	Procedure FunctionName
+	Args      Exprs
+}
+
+var _ Statement = &Call{}
+
+// Format implements the NodeFormatter interface.
+func (node *Call) Format(ctx *FmtCtx) {
+	ctx.WriteString("CALL ")
+	ctx.FormatNode(&node.Procedure)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteByte(')')
+}
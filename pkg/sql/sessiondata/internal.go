@@ -34,6 +34,9 @@ type InternalExecutorOverride struct {
 	// used as long as that value has a QoSLevel defined
 	// (see QoSLevel.ValidateInternal).
 	QualityOfService *sessiondatapb.QoSLevel
+	// MaintainMaterializedViews allows the query to mutate materialized views,
+	// in order to populate incrementally refreshed materialized views.
+	MaintainMaterializedViews bool
 }
 
 // NoSessionDataOverride is the empty InternalExecutorOverride which does not
@@ -0,0 +1,54 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type setConstraintsNode struct {
+	n *tree.SetConstraints
+}
+
+// SetConstraints changes whether the checks of DEFERRABLE constraints are
+// performed at the end of each statement or at the end of the transaction.
+// Privileges: None.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	return &setConstraintsNode{n: n}, nil
+}
+
+func (n *setConstraintsNode) startExec(params runParams) error {
+	if params.p.extendedEvalCtx.TxnImplicit {
+		// This no-ops in postgres with a warning, so copy accordingly.
+		params.p.BufferClientNotice(
+			params.ctx,
+			pgnotice.NewWithSeverityf(
+				"WARNING",
+				"SET CONSTRAINTS can only be used in transaction blocks",
+			),
+		)
+		return nil
+	}
+	// As in Postgres, any checks that were queued for constraints which are
+	// made immediate are performed right away.
+	pending := params.p.deferredChecks.setMode(n.n.Names, n.n.Deferred)
+	if len(pending) == 0 {
+		return nil
+	}
+	return params.p.validatePendingChecks(params.ctx, pending)
+}
+
+func (n *setConstraintsNode) Next(runParams) (bool, error) { return false, nil }
+func (n *setConstraintsNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *setConstraintsNode) Close(context.Context)        {}
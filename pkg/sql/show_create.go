@@ -13,8 +13,11 @@ package sql
 import (
 	"bytes"
 	"context"
+	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catformat"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -72,6 +75,9 @@ func ShowCreateTable(
 	lCtx simpleSchemaResolver,
 	displayOptions ShowCreateDisplayOptions,
 ) (string, error) {
+	if desc.IsForeign() {
+		return showCreateForeignTable(ctx, p, tn, desc, displayOptions)
+	}
 	a := &tree.DatumAlloc{}
 
 	f := p.ExtendedEvalContext().FmtCtx(tree.FmtSimple)
@@ -195,6 +201,83 @@ func ShowCreateTable(
 	return f.CloseAndGetString(), nil
 }
 
+// showCreateForeignTable returns the CREATE FOREIGN TABLE statement used to
+// create the given foreign table. The credentials in the URIs of the data
+// files are redacted.
+func showCreateForeignTable(
+	ctx context.Context,
+	p PlanHookState,
+	tn *tree.TableName,
+	desc catalog.TableDescriptor,
+	displayOptions ShowCreateDisplayOptions,
+) (string, error) {
+	foreign := desc.GetForeign()
+	f := p.ExtendedEvalContext().FmtCtx(tree.FmtSimple)
+	f.WriteString("CREATE FOREIGN TABLE ")
+	f.FormatNode(tn)
+	f.WriteString(" (")
+	for i, col := range desc.VisibleColumns() {
+		if i != 0 {
+			f.WriteString(",")
+		}
+		f.WriteString("\n\t")
+		colstr, err := schemaexpr.FormatColumnForDisplay(
+			ctx, desc, col, &p.RunParams(ctx).p.semaCtx, p.RunParams(ctx).p.SessionData(),
+		)
+		if err != nil {
+			return "", err
+		}
+		f.WriteString(colstr)
+	}
+	f.WriteString("\n) ")
+	f.WriteString(foreignTableFormatName(foreign.Format))
+	f.WriteString(" DATA (")
+	for i, file := range foreign.Files {
+		if i != 0 {
+			f.WriteString(",")
+		}
+		sanitized, err := cloud.SanitizeExternalStorageURI(file, nil /* extraParams */)
+		if err != nil {
+			return "", err
+		}
+		f.WriteString("\n\t")
+		f.FormatNode(tree.NewStrVal(sanitized))
+	}
+	f.WriteString("\n)")
+	if len(foreign.Options) > 0 {
+		opts := make(tree.KVOptions, 0, len(foreign.Options))
+		for k, v := range foreign.Options {
+			opt := tree.KVOption{Key: tree.Name(k)}
+			if v != "" {
+				opt.Value = tree.NewStrVal(v)
+			}
+			opts = append(opts, opt)
+		}
+		sort.Slice(opts, func(i, j int) bool { return opts[i].Key < opts[j].Key })
+		f.WriteString(" WITH ")
+		f.FormatNode(&opts)
+	}
+
+	if !displayOptions.IgnoreComments {
+		if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
+			return "", err
+		}
+	}
+
+	return f.CloseAndGetString(), nil
+}
+
+// foreignTableFormatName returns the name of the format of the files of a
+// foreign table, as it is written in CREATE FOREIGN TABLE.
+func foreignTableFormatName(format roachpb.IOFileFormat) string {
+	switch format.Format {
+	case roachpb.IOFileFormat_MysqlOutfile:
+		return "DELIMITED"
+	default:
+		return strings.ToUpper(format.Format.String())
+	}
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
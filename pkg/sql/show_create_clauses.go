@@ -113,6 +113,11 @@ func ShowCreateView(
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	cols := desc.PublicColumns()
+	// The hidden columns of an incrementally refreshed materialized view are
+	// maintained along with the view and are not part of its definition.
+	if desc.GetIncrementalRefresh() != nil {
+		cols = desc.VisibleColumns()
+	}
 	for i, col := range cols {
 		f.WriteString("\n\t")
 		name := col.GetName()
@@ -123,7 +128,11 @@ func ShowCreateView(
 			f.WriteRune(',')
 		}
 	}
-	f.WriteString(") AS ")
+	f.WriteString(")")
+	if desc.GetIncrementalRefresh() != nil {
+		f.WriteString(" WITH (incremental_refresh = true)")
+	}
+	f.WriteString(" AS ")
 
 	cfg := tree.DefaultPrettyCfg()
 	cfg.UseTabs = true
@@ -391,6 +400,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.Deferrability != descpb.ConstraintDeferrability_NotDeferrable {
+		buf.WriteByte(' ')
+		buf.WriteString(descpb.ConstraintDeferrabilityType[fk.Deferrability].String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -637,6 +650,7 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		f.FormatNode(descpb.ConstraintDeferrabilityType[c.Deferrability])
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
@@ -649,6 +663,21 @@ func showConstraintClause(
 			f.WriteString(" NOT VALID")
 		}
 	}
+	exclusionConstraints := desc.GetExclusionConstraints()
+	for i := range exclusionConstraints {
+		c := &exclusionConstraints[i]
+		f.WriteString(",\n\tCONSTRAINT ")
+		formatQuoteNames(&f.Buffer, c.Name)
+		f.WriteString(" ")
+		if err := formatExclusionConstraint(
+			ctx, desc, c, semaCtx, sessionData, tree.FmtParsable, f,
+		); err != nil {
+			return err
+		}
+		if c.Validity != descpb.ConstraintValidity_Validated {
+			f.WriteString(" NOT VALID")
+		}
+	}
 	f.WriteString("\n)")
 	return nil
 }
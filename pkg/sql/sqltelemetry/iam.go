@@ -36,6 +36,9 @@ const (
 	OnSequence = "on_sequence"
 	// OnType is used when a GRANT/REVOKE is happening on a type.
 	OnType = "on_type"
+	// OnFunction is used when a GRANT/REVOKE is happening on a function or
+	// procedure.
+	OnFunction = "on_function"
 	// OnAllTablesInSchema is used when a GRANT/REVOKE is happening on
 	// all tables in a set of schemas.
 	OnAllTablesInSchema = "on_all_tables_in_schemas"
@@ -0,0 +1,481 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/lsn"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// replicationKeepaliveInterval is the interval at which keepalive messages
+// are sent to idle subscribers.
+const replicationKeepaliveInterval = 10 * time.Second
+
+// replicationMaxPendingBytes limits the memory used by each replication stream
+// to buffer the changes whose timestamp is not resolved yet.
+var replicationMaxPendingBytes = settings.RegisterByteSizeSetting(
+	settings.TenantWritable,
+	"sql.replication.max_pending_bytes",
+	"maximum memory used by a logical replication stream to buffer the changes "+
+		"whose timestamp is not resolved yet",
+	64<<20, /* 64 MiB */
+	settings.PositiveInt,
+)
+
+// execStartReplication runs the START_REPLICATION command, which streams the
+// changes to the tables of the requested publications over the Copy-both
+// subprotocol until the client ends the stream.
+//
+// The changes are produced by a sinkless changefeed on the published tables,
+// run on behalf of the session's user, which thus needs the privileges
+// required by changefeeds. The changes are buffered until the changefeed
+// resolves their timestamp, and are then sent in transactions grouped by LSN,
+// in LSN order. The stream fails if the buffered changes exceed
+// sql.replication.max_pending_bytes.
+//
+// The stream resumes after the LSN up to which the subscriber of the slot
+// confirmed that it flushed the changes in its standby status updates, or
+// after the LSN passed to START_REPLICATION if it is later. A slot does not
+// prevent the garbage collection of the changes which have not been
+// confirmed, so a stream cannot be resumed from an LSN older than the garbage
+// collection TTL of the published tables.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication,
+) (fsm.Event, fsm.EventPayload) {
+	// When we're done, stop forwarding the client's copy messages.
+	defer close(cmd.StreamDone)
+
+	ex.incrementStartedStmtCounter(cmd.Stmt)
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: pgerror.New(pgcode.ActiveSQLTransaction,
+			"START_REPLICATION cannot run inside a transaction block")}
+		return ev, payload
+	}
+	if err := ex.runReplicationStream(ctx, cmd); err != nil {
+		log.SqlExec.Errorf(ctx, "error executing %s: %+v", cmd, err)
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload
+	}
+	ex.incrementExecutedStmtCounter(cmd.Stmt)
+	return nil, nil
+}
+
+// parseStartReplicationOptions validates the options passed to the pgoutput
+// plugin and returns the requested publications.
+func parseStartReplicationOptions(opts tree.KVOptions) ([]string, error) {
+	var protoVersion string
+	var pubNames []string
+	for _, opt := range opts {
+		var val string
+		if opt.Value != nil {
+			val = opt.Value.(*tree.StrVal).RawString()
+		}
+		switch key := string(opt.Key); key {
+		case "proto_version":
+			if val != fmt.Sprint(pgrepl.ProtoVersion) {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"client sent proto_version=%s but server only supports protocol %d",
+					val, pgrepl.ProtoVersion)
+			}
+			protoVersion = val
+		case "publication_names":
+			names, err := pgrepl.ParsePublicationNames(val)
+			if err != nil {
+				return nil, err
+			}
+			pubNames = append(pubNames, names...)
+		case "binary", "messages", "streaming", "two_phase":
+			// These options request features which are not supported, so they
+			// are only accepted if disabled.
+			enabled := true
+			if opt.Value != nil {
+				var err error
+				if enabled, err = tree.ParseBool(val); err != nil {
+					return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "%s", key)
+				}
+			}
+			if enabled {
+				return nil, unimplemented.Newf("pgoutput "+key,
+					"pgoutput option %s is not supported", key)
+			}
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", key)
+		}
+	}
+	if protoVersion == "" {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "proto_version option missing")
+	}
+	if len(pubNames) == 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "publication_names parameter missing")
+	}
+	return pubNames, nil
+}
+
+// replicationEvent is a row produced by the changefeed backing a replication
+// stream: either a change, or a resolved timestamp.
+type replicationEvent struct {
+	change   *pgrepl.Change
+	resolved hlc.Timestamp
+}
+
+func (ex *connExecutor) runReplicationStream(ctx context.Context, cmd StartReplication) error {
+	execCfg := ex.server.cfg
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.ReplicationSlotsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to start replication",
+			clusterversion.ByKey(clusterversion.ReplicationSlotsTable))
+	}
+	pubNames, err := parseStartReplicationOptions(cmd.Stmt.Options)
+	if err != nil {
+		return err
+	}
+	dbName := ex.sessionData().Database
+	if dbName == "" {
+		return pgerror.New(pgcode.InvalidCatalogName,
+			"cannot start replication without a current database")
+	}
+
+	var tables []publicationTable
+	var slot *replicationSlot
+	if err := DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		db, err := col.GetImmutableDatabaseByName(
+			ctx, txn, dbName, tree.DatabaseLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		slotName := string(cmd.Stmt.Slot)
+		slot, err = getReplicationSlot(
+			ctx, execCfg.InternalExecutor, txn, ex.temporaryReplicationSlots, slotName,
+		)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"replication slot %q does not exist", slotName)
+		}
+		if slot.dbID != db.GetID() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication slot %q was not created in this database", slotName)
+		}
+		tables, err = getPublicationTables(ctx, txn, col, db, pubNames)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	memMon := mon.NewMonitorInheritWithLimit(
+		"replication-stream", replicationMaxPendingBytes.Get(&execCfg.Settings.SV), ex.sessionMon,
+	)
+	memMon.StartNoReserved(ctx, ex.sessionMon)
+	defer memMon.Stop(ctx)
+	s := replicationSender{
+		conn:       cmd.Conn,
+		namespaces: make(map[oid.Oid]string, len(tables)),
+		relations:  make(map[oid.Oid]pgrepl.Relation, len(tables)),
+		pendingAcc: memMon.MakeBoundAccount(),
+		sentLSN:    cmd.Stmt.StartLSN,
+	}
+	if s.sentLSN < slot.confirmedFlushLSN {
+		s.sentLSN = slot.confirmedFlushLSN
+	}
+	defer s.pendingAcc.Close(ctx)
+	names := make([]string, len(tables))
+	for i := range tables {
+		s.namespaces[oid.Oid(tables[i].id)] = string(tables[i].name.SchemaName)
+		names[i] = tree.AsString(&tables[i].name)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	g := ctxgroup.WithContext(ctx)
+	defer func() {
+		cancel()
+		_ = g.Wait()
+	}()
+	var events chan replicationEvent
+	if len(tables) > 0 {
+		// The changefeed resumes after the changes which have been confirmed by
+		// the subscriber.
+		cursor := pgrepl.TimestampFromLSN(s.sentLSN)
+		if now := execCfg.Clock.Now(); now.Less(cursor) {
+			cursor = now
+		}
+		opts := []string{
+			"format = 'pgoutput'", "diff", "resolved = '1s'", "min_checkpoint_frequency = '1s'",
+			fmt.Sprintf("cursor = '%s'", cursor.AsOfSystemTime()),
+		}
+		stmt := fmt.Sprintf("EXPERIMENTAL CHANGEFEED FOR TABLE %s WITH %s",
+			strings.Join(names, ", "), strings.Join(opts, ", "))
+		override := sessiondata.InternalExecutorOverride{
+			User:     ex.sessionData().User(),
+			Database: dbName,
+		}
+		events = make(chan replicationEvent)
+		g.GoCtx(func(ctx context.Context) error {
+			defer close(events)
+			return runReplicationChangefeed(ctx, execCfg.InternalExecutor, override, stmt, events)
+		})
+	}
+
+	if err := s.conn.BeginCopyBoth(ctx); err != nil {
+		return err
+	}
+	keepalive := time.NewTicker(replicationKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				if err := g.Wait(); err != nil {
+					return err
+				}
+				return errors.AssertionFailedf("changefeed ended unexpectedly")
+			}
+			if ev.change != nil {
+				if err := s.addPending(ctx, *ev.change); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.flush(ctx, ev.resolved); err != nil {
+				return err
+			}
+		case msg := <-cmd.ClientMsgs:
+			switch msg.Typ {
+			case pgwirebase.ClientMsgCopyDone:
+				if err := s.conn.SendCopyDone(ctx); err != nil {
+					return err
+				}
+				return s.conn.SendCommandComplete([]byte("START_REPLICATION"))
+			case pgwirebase.ClientMsgCopyFail:
+				return pgerror.Newf(pgcode.QueryCanceled,
+					"COPY from stdin failed: %s", strings.TrimSuffix(string(msg.Data), "\x00"))
+			default:
+				if !pgrepl.IsStandbyStatus(msg.Data) {
+					// Other messages, such as hot standby feedback, don't apply to
+					// logical replication.
+					continue
+				}
+				status, err := pgrepl.DecodeStandbyStatus(msg.Data)
+				if err != nil {
+					return err
+				}
+				// The subscriber can only have flushed the changes which were sent
+				// to it.
+				flushed := status.Flushed
+				if flushed > s.sentLSN {
+					flushed = s.sentLSN
+				}
+				if err := confirmReplicationSlot(ctx, execCfg.InternalExecutor, slot, flushed); err != nil {
+					return err
+				}
+				if status.ReplyRequested {
+					if err := s.sendKeepalive(ctx); err != nil {
+						return err
+					}
+				}
+			}
+		case <-keepalive.C:
+			if len(tables) == 0 {
+				// Nothing is published, so the stream is always up to date.
+				s.sentLSN = pgrepl.LSNFromTimestamp(execCfg.Clock.Now())
+			}
+			if err := s.sendKeepalive(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// runReplicationChangefeed runs the changefeed backing a replication stream
+// and sends the decoded rows it produces on the events channel.
+func runReplicationChangefeed(
+	ctx context.Context,
+	ie *InternalExecutor,
+	override sessiondata.InternalExecutorOverride,
+	stmt string,
+	events chan<- replicationEvent,
+) (retErr error) {
+	it, err := ie.QueryIteratorEx(ctx, "start-replication", nil /* txn */, override, stmt)
+	if err != nil {
+		return err
+	}
+	defer func() { retErr = errors.CombineErrors(retErr, it.Close()) }()
+	for {
+		ok, err := it.Next(ctx)
+		if err != nil || !ok {
+			return err
+		}
+		// The rows are (table, key, value), with a NULL table for the resolved
+		// timestamps.
+		row := it.Cur()
+		value := []byte(tree.MustBeDBytes(row[2]))
+		var ev replicationEvent
+		if row[0] == tree.DNull {
+			if ev.resolved, err = pgrepl.DecodeResolved(value); err != nil {
+				return err
+			}
+		} else {
+			change, err := pgrepl.DecodeChange(value)
+			if err != nil {
+				return err
+			}
+			ev.change = &change
+		}
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// replicationSender sends the changes of a replication stream to the
+// subscriber.
+type replicationSender struct {
+	conn pgwirebase.ReplicationConn
+	// namespaces maps the OIDs of the published tables to the names of their
+	// schemas.
+	namespaces map[oid.Oid]string
+	// relations contains the last Relation message sent for each table. A new
+	// one is sent before a change if the table was altered.
+	relations map[oid.Oid]pgrepl.Relation
+	// pending contains the changes whose timestamp is not resolved yet.
+	pending []pgrepl.Change
+	// pendingAcc accounts for the memory used by the pending changes.
+	pendingAcc mon.BoundAccount
+	// sentLSN is the LSN up to which all the changes have been sent.
+	sentLSN lsn.LSN
+	// xid is the identifier of the last transaction sent. Transaction
+	// identifiers are only meaningful within a stream.
+	xid uint32
+}
+
+// addPending buffers a change until its timestamp is resolved.
+func (s *replicationSender) addPending(ctx context.Context, change pgrepl.Change) error {
+	if err := s.pendingAcc.Grow(ctx, change.Size()); err != nil {
+		return errors.WithHint(
+			errors.Wrap(err, "buffering the unresolved changes of the replication stream"),
+			"The replication stream can be restarted from the last confirmed LSN once fewer "+
+				"changes are made to the published tables, or sql.replication.max_pending_bytes "+
+				"can be increased.",
+		)
+	}
+	s.pending = append(s.pending, change)
+	return nil
+}
+
+// flush sends the pending changes made at or before the resolved timestamp,
+// followed by a keepalive message reporting the new position of the stream.
+func (s *replicationSender) flush(ctx context.Context, resolved hlc.Timestamp) error {
+	end := pgrepl.ResolvedLSN(resolved)
+	sort.Slice(s.pending, func(i, j int) bool {
+		return s.pending[i].Timestamp.Less(s.pending[j].Timestamp)
+	})
+	i := 0
+	for i < len(s.pending) {
+		l := pgrepl.LSNFromTimestamp(s.pending[i].Timestamp)
+		if l > end {
+			break
+		}
+		j := i + 1
+		for j < len(s.pending) && pgrepl.LSNFromTimestamp(s.pending[j].Timestamp) == l {
+			j++
+		}
+		// Changefeeds deliver changes at least once, so changes may be repeated
+		// after they have been sent.
+		if l > s.sentLSN {
+			if err := s.sendTxn(ctx, l, s.pending[i:j]); err != nil {
+				return err
+			}
+		}
+		i = j
+	}
+	var flushed int64
+	for k := range s.pending[:i] {
+		flushed += s.pending[k].Size()
+	}
+	s.pendingAcc.Shrink(ctx, flushed)
+	n := copy(s.pending, s.pending[i:])
+	// Release the flushed changes which are still referenced by the backing
+	// array.
+	for k := n; k < len(s.pending); k++ {
+		s.pending[k] = pgrepl.Change{}
+	}
+	s.pending = s.pending[:n]
+	if end > s.sentLSN {
+		s.sentLSN = end
+	}
+	return s.sendKeepalive(ctx)
+}
+
+// sendTxn sends the changes with the given LSN as a transaction.
+func (s *replicationSender) sendTxn(ctx context.Context, l lsn.LSN, changes []pgrepl.Change) error {
+	s.xid++
+	commitTime := timeutil.Unix(0, changes[0].Timestamp.WallTime)
+	if err := s.sendXLogData(ctx, l, pgrepl.EncodeBegin(l, commitTime, s.xid)); err != nil {
+		return err
+	}
+	for i := range changes {
+		rel := changes[i].Relation
+		rel.Namespace = s.namespaces[rel.ID]
+		if prev, ok := s.relations[rel.ID]; !ok || !prev.Equal(&rel) {
+			if err := s.sendXLogData(ctx, l, rel.Encode()); err != nil {
+				return err
+			}
+			s.relations[rel.ID] = rel
+		}
+		if err := s.sendXLogData(ctx, l, changes[i].Message); err != nil {
+			return err
+		}
+	}
+	return s.sendXLogData(ctx, l, pgrepl.EncodeCommit(l, l, commitTime))
+}
+
+func (s *replicationSender) sendXLogData(ctx context.Context, l lsn.LSN, data []byte) error {
+	return s.conn.SendCopyData(ctx, pgrepl.EncodeXLogData(l, l, timeutil.Now(), data))
+}
+
+func (s *replicationSender) sendKeepalive(ctx context.Context) error {
+	return s.conn.SendCopyData(ctx, pgrepl.EncodeKeepalive(s.sentLSN, timeutil.Now(), false /* replyRequested */))
+}
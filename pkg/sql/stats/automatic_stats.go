@@ -524,6 +524,9 @@ WHERE
 	AND (
 			crdb_internal.pb_to_json('cockroach.sql.sqlbase.Descriptor', d.descriptor, false)->'table'->>'viewQuery'
 		) IS NULL
+	AND (
+			crdb_internal.pb_to_json('cockroach.sql.sqlbase.Descriptor', d.descriptor, false)->'table'->'foreign'
+		) IS NULL
 	%s`
 
 	explicitlyEnabledTablesPredicate = `AND
@@ -557,7 +560,7 @@ func (r *Refresher) getApplicableTables(
 			row := it.Cur()
 			tableID := descpb.ID(*row[0].(*tree.DInt))
 			// Don't create statistics for virtual tables.
-			// The query already excludes views and system tables.
+			// The query already excludes views, foreign tables and system tables.
 			if !descpb.IsVirtualTable(tableID) {
 				r.mutationCounts[tableID] += 0
 				if forTesting {
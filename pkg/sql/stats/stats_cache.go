@@ -284,6 +284,11 @@ func tableTypeCanHaveStats(table catalog.TableDescriptor) bool {
 		// Don't try to get statistics for views.
 		return false
 	}
+	if table.IsForeign() {
+		// Don't try to get statistics for foreign tables, whose rows are not
+		// stored in the cluster.
+		return false
+	}
 	return true
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/vector/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -105,6 +106,29 @@ func (po *Setter) applyGeometryIndexSetting(
 	return nil
 }
 
+func (po *Setter) applyVectorIndexSetting(
+	evalCtx *eval.Context, key string, expr tree.Datum,
+) error {
+	if po.IndexDesc.VectorConfig.IsEmpty() {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "%q can only be applied to ivfflat indexes", key)
+	}
+	val, err := paramparse.DatumAsInt(evalCtx, key, expr)
+	if err != nil {
+		return errors.Wrapf(err, "error decoding %q", key)
+	}
+	if val < 1 || val > vecindex.MaxLists {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			"%q value must be between %d and %d inclusive",
+			key,
+			1,
+			vecindex.MaxLists,
+		)
+	}
+	po.IndexDesc.VectorConfig.Lists = int32(val)
+	return nil
+}
+
 // Set implements the Setter interface.
 func (po *Setter) Set(
 	semaCtx *tree.SemaContext, evalCtx *eval.Context, key string, expr tree.Datum,
@@ -120,6 +144,8 @@ func (po *Setter) Set(
 		return po.applyS2ConfigSetting(evalCtx, key, expr, 1, 32)
 	case `geometry_min_x`, `geometry_max_x`, `geometry_min_y`, `geometry_max_y`:
 		return po.applyGeometryIndexSetting(evalCtx, key, expr)
+	case `lists`:
+		return po.applyVectorIndexSetting(evalCtx, key, expr)
 	// `bucket_count` is handled in schema changer when creating hash sharded
 	// indexes.
 	case `bucket_count`:
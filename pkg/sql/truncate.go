@@ -25,6 +25,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -71,6 +73,13 @@ func (t *truncateNode) startExec(params runParams) error {
 		if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 			return err
 		}
+		if tableDesc.IsForeign() {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"cannot truncate foreign table %q", tableDesc.Name)
+		}
+		if err := CheckNoIncrementalMaterializedViews(tableDesc, "truncate"); err != nil {
+			return err
+		}
 
 		toTruncate[tableDesc.ID] = tn.FQString()
 		toTraverse = append(toTraverse, *tableDesc)
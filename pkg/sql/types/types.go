@@ -420,6 +420,11 @@ var (
 	Jsonb = &T{InternalType: InternalType{
 		Family: JsonFamily, Oid: oid.T_jsonb, Locale: &emptyLocale}}
 
+	// Jsonpath is the type of a SQL/JSON path expression, which is used to
+	// query jsonb values.
+	Jsonpath = &T{InternalType: InternalType{
+		Family: JsonpathFamily, Oid: oidext.T_jsonpath, Locale: &emptyLocale}}
+
 	// Uuid is the type of a universally unique identifier (UUID), which is a
 	// 128-bit quantity that is very unlikely to ever be generated again, and so
 	// can be relied on to be distinct from all other UUID values.
@@ -463,6 +468,88 @@ var (
 		},
 	}
 
+	// TSQuery is the type of a full text search query.
+	TSQuery = &T{
+		InternalType: InternalType{
+			Family: TSQueryFamily,
+			Oid:    oid.T_tsquery,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSVector is the type of a full text search document.
+	TSVector = &T{
+		InternalType: InternalType{
+			Family: TSVectorFamily,
+			Oid:    oid.T_tsvector,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// PGVector is the type of a vector of 32-bit floats with any number of
+	// dimensions, like the vector type of the pgvector extension.
+	PGVector = &T{
+		InternalType: InternalType{
+			Family: PGVectorFamily,
+			Oid:    oidext.T_pgvector,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int4Range is the type of a range of INT4 values.
+	Int4Range = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_int4range,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int8Range is the type of a range of INT8 values.
+	Int8Range = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_int8range,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// NumRange is the type of a range of DECIMAL values.
+	NumRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_numrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSRange is the type of a range of TIMESTAMP values.
+	TSRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_tsrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSTZRange is the type of a range of TIMESTAMPTZ values.
+	TSTZRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_tstzrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// DateRange is the type of a range of DATE values.
+	DateRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_daterange,
+			Locale: &emptyLocale,
+		},
+	}
+
 	// Void is the type representing void.
 	Void = &T{
 		InternalType: InternalType{
@@ -510,7 +597,23 @@ var (
 		Time,
 		TimeTZ,
 		Jsonb,
+		Jsonpath,
 		VarBit,
+		TSQuery,
+		TSVector,
+		PGVector,
+	}
+
+	// RangeTypes contains all of the built-in range types. Unlike the other
+	// type families, there is no canonical type for the RangeFamily, since
+	// range types over different subtypes are not equivalent to each other.
+	RangeTypes = []*T{
+		Int4Range,
+		Int8Range,
+		NumRange,
+		TSRange,
+		TSTZRange,
+		DateRange,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -775,7 +878,7 @@ func MakeScalar(family Family, o oid.Oid, precision, width int32, locale string)
 			panic(errors.AssertionFailedf(
 				"decimal scale %d cannot be larger than precision %d", width, precision))
 		}
-	case StringFamily, BytesFamily, CollatedStringFamily, BitFamily:
+	case StringFamily, BytesFamily, CollatedStringFamily, BitFamily, PGVectorFamily:
 		// These types can have any width.
 	case GeometryFamily:
 		geoMetadata = &GeoMetadata{}
@@ -825,6 +928,19 @@ func MakeVarBit(width int32) *T {
 		Family: BitFamily, Width: width, Oid: oid.T_varbit, Locale: &emptyLocale}}
 }
 
+// MakePGVector constructs a new instance of the VECTOR type (oid = T_pgvector)
+// having the given number of dimensions (0 = unspecified number).
+func MakePGVector(dims int32) *T {
+	if dims == 0 {
+		return PGVector
+	}
+	if dims < 0 {
+		panic(errors.AssertionFailedf("dimensions %d cannot be negative", dims))
+	}
+	return &T{InternalType: InternalType{
+		Family: PGVectorFamily, Oid: oidext.T_pgvector, Width: dims, Locale: &emptyLocale}}
+}
+
 // MakeString constructs a new instance of the STRING type (oid = T_text) having
 // the given max # characters (0 = unspecified number).
 func MakeString(width int32) *T {
@@ -1207,6 +1323,7 @@ func (t *T) Locale() string {
 //   STRING        : max # of characters
 //   COLLATEDSTRING: max # of characters
 //   BIT           : max # of bits
+//   VECTOR        : # of dimensions
 //
 // Width is always 0 for other types.
 func (t *T) Width() int32 {
@@ -1257,7 +1374,7 @@ func (t *T) TypeModifier() int32 {
 			// var header size.
 			return width + 4
 		}
-	case BitFamily:
+	case BitFamily, PGVectorFamily:
 		if width := t.Width(); width != 0 {
 			return width
 		}
@@ -1348,6 +1465,26 @@ func (t *T) TupleLabels() []string {
 	return t.InternalType.TupleLabels
 }
 
+// rangeContents maps the Oid of each range type to the type of its bounds.
+var rangeContents = map[oid.Oid]*T{
+	oid.T_int4range: Int4,
+	oid.T_int8range: Int,
+	oid.T_numrange:  Decimal,
+	oid.T_tsrange:   Timestamp,
+	oid.T_tstzrange: TimestampTZ,
+	oid.T_daterange: Date,
+}
+
+// RangeContents returns the type of the bounds of the range, which is known as
+// the subtype of the range. This is nil for types that are not in the
+// RangeFamily.
+func (t *T) RangeContents() *T {
+	if t.Family() != RangeFamily {
+		return nil
+	}
+	return rangeContents[t.Oid()]
+}
+
 // UserDefinedArrayOID returns the OID of the array type that corresponds to
 // this user defined type. This function only can only be called on user
 // defined types and returns non-zero data only for user defined types that
@@ -1403,12 +1540,17 @@ var familyNames = map[Family]string{
 	IntFamily:            "int",
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
+	JsonpathFamily:       "jsonpath",
+	PGVectorFamily:       "vector",
 	OidFamily:            "oid",
+	RangeFamily:          "range",
 	StringFamily:         "string",
 	TimeFamily:           "time",
 	TimestampFamily:      "timestamp",
 	TimestampTZFamily:    "timestamptz",
 	TimeTZFamily:         "timetz",
+	TSQueryFamily:        "tsquery",
+	TSVectorFamily:       "tsvector",
 	TupleFamily:          "tuple",
 	UnknownFamily:        "unknown",
 	UuidFamily:           "uuid",
@@ -1476,7 +1618,7 @@ func (t *T) Name() string {
 			panic(errors.AssertionFailedf("programming error: unknown int width: %d", t.Width()))
 		}
 
-	case OidFamily:
+	case OidFamily, RangeFamily:
 		return t.SQLStandardName()
 
 	case StringFamily, CollatedStringFamily:
@@ -1652,6 +1794,13 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 	case JsonFamily:
 		// Only binary JSON is currently supported.
 		return "jsonb"
+	case JsonpathFamily:
+		return "jsonpath"
+	case PGVectorFamily:
+		if !haveTypmod || typmod <= 0 {
+			return "vector"
+		}
+		return fmt.Sprintf("vector(%d)", typmod)
 	case OidFamily:
 		switch t.Oid() {
 		case oid.T_oid:
@@ -1728,6 +1877,12 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case RangeFamily:
+		return t.PGName()
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case TupleFamily:
 		if t.UserDefined() {
 			// If we have a user-defined tuple type, use its user-defined name.
@@ -1819,6 +1974,10 @@ func (t *T) SQLString() string {
 		}
 	case GeometryFamily, GeographyFamily:
 		return strings.ToUpper(t.Name() + t.InternalType.GeoMetadata.SQLString())
+	case PGVectorFamily:
+		if t.Width() > 0 {
+			return fmt.Sprintf("VECTOR(%d)", t.Width())
+		}
 	case IntervalFamily:
 		switch t.InternalType.IntervalDurationField.DurationType {
 		case IntervalDurationType_UNSET:
@@ -1925,6 +2084,12 @@ func (t *T) Equivalent(other *T) bool {
 		if t.Oid() != other.Oid() {
 			return false
 		}
+
+	case RangeFamily:
+		// Ranges over different subtypes are not equivalent.
+		if t.Oid() != other.Oid() {
+			return false
+		}
 	}
 
 	return true
@@ -2763,6 +2928,7 @@ var unreservedTypeTokens = map[string]*T{
 	"int2vector": Int2Vector,
 	"json":       Jsonb,
 	"jsonb":      Jsonb,
+	"jsonpath":   Jsonpath,
 	"name":       Name,
 	"oid":        Oid,
 	"oidvector":  OidVector,
@@ -2780,8 +2946,18 @@ var unreservedTypeTokens = map[string]*T{
 	"smallserial": &Serial2Type,
 	"bigserial":   &Serial8Type,
 
-	"string": String,
-	"uuid":   Uuid,
+	"string":   String,
+	"tsquery":  TSQuery,
+	"tsvector": TSVector,
+	"uuid":     Uuid,
+
+	// Postgres built-in range types.
+	"daterange": DateRange,
+	"int4range": Int4Range,
+	"int8range": Int8Range,
+	"numrange":  NumRange,
+	"tsrange":   TSRange,
+	"tstzrange": TSTZRange,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -2792,7 +2968,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"box":           21286,
 	"cidr":          18846,
 	"circle":        21286,
-	"jsonpath":      22513,
 	"line":          21286,
 	"lseg":          21286,
 	"macaddr":       45813,
@@ -2800,8 +2975,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"money":         41578,
 	"path":          21286,
 	"pg_lsn":        -1,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           43355,
 }
@@ -346,6 +346,20 @@ func TestTypes(t *testing.T) {
 			Family: JsonFamily, Oid: oid.T_jsonb, Locale: &emptyLocale}}},
 		{Jsonb, MakeScalar(JsonFamily, oid.T_jsonb, 0, 0, emptyLocale)},
 
+		// JSONPATH
+		{Jsonpath, &T{InternalType: InternalType{
+			Family: JsonpathFamily, Oid: oidext.T_jsonpath, Locale: &emptyLocale}}},
+		{Jsonpath, MakeScalar(JsonpathFamily, oidext.T_jsonpath, 0, 0, emptyLocale)},
+
+		// VECTOR
+		{PGVector, &T{InternalType: InternalType{
+			Family: PGVectorFamily, Oid: oidext.T_pgvector, Locale: &emptyLocale}}},
+		{PGVector, MakeScalar(PGVectorFamily, oidext.T_pgvector, 0, 0, emptyLocale)},
+		{MakePGVector(0), PGVector},
+		{MakePGVector(3), &T{InternalType: InternalType{
+			Family: PGVectorFamily, Oid: oidext.T_pgvector, Width: 3, Locale: &emptyLocale}}},
+		{MakePGVector(3), MakeScalar(PGVectorFamily, oidext.T_pgvector, 0, 3, emptyLocale)},
+
 		// OID
 		{Oid, &T{InternalType: InternalType{
 			Family: OidFamily, Oid: oid.T_oid, Locale: &emptyLocale}}},
@@ -481,6 +495,24 @@ func TestTypes(t *testing.T) {
 			Family: TimestampTZFamily, Oid: oid.T_timestamptz, Precision: 6, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTimestampTZ(6), MakeScalar(TimestampTZFamily, oid.T_timestamptz, 6, 0, emptyLocale)},
 
+		// TSQUERY
+		{TSQuery, &T{InternalType: InternalType{
+			Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}},
+		{TSQuery, MakeScalar(TSQueryFamily, oid.T_tsquery, 0, 0, emptyLocale)},
+
+		// RANGE
+		{Int4Range, &T{InternalType: InternalType{
+			Family: RangeFamily, Oid: oid.T_int4range, Locale: &emptyLocale}}},
+		{Int4Range, MakeScalar(RangeFamily, oid.T_int4range, 0, 0, emptyLocale)},
+		{TSTZRange, &T{InternalType: InternalType{
+			Family: RangeFamily, Oid: oid.T_tstzrange, Locale: &emptyLocale}}},
+		{TSTZRange, MakeScalar(RangeFamily, oid.T_tstzrange, 0, 0, emptyLocale)},
+
+		// TSVECTOR
+		{TSVector, &T{InternalType: InternalType{
+			Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}},
+		{TSVector, MakeScalar(TSVectorFamily, oid.T_tsvector, 0, 0, emptyLocale)},
+
 		// TUPLE
 		{MakeTuple(nil), EmptyTuple},
 		{MakeTuple([]*T{Any}), AnyTuple},
@@ -603,6 +635,12 @@ func TestEquivalent(t *testing.T) {
 		{Int, Any, true},
 		{Int, IntArray, false},
 
+		// RANGE
+		{Int4Range, Int4Range, true},
+		{Int4Range, Any, true},
+		{Int4Range, Int8Range, false},
+		{NumRange, Decimal, false},
+
 		// TUPLE
 		{MakeTuple([]*T{}), MakeTuple([]*T{}), true},
 		{MakeTuple([]*T{Int, String}), MakeTuple([]*T{Int4, VarChar}), true},
@@ -980,6 +1018,30 @@ func TestSQLStandardName(t *testing.T) {
 	}
 }
 
+func TestRangeContents(t *testing.T) {
+	for _, tc := range []struct {
+		typ      *T
+		expected *T
+	}{
+		{Int4Range, Int4},
+		{Int8Range, Int},
+		{NumRange, Decimal},
+		{TSRange, Timestamp},
+		{TSTZRange, TimestampTZ},
+		{DateRange, Date},
+		{Int, nil},
+		{IntArray, nil},
+	} {
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.typ.RangeContents())
+		})
+	}
+	for _, typ := range RangeTypes {
+		require.Equal(t, typ, OidToType[typ.Oid()])
+		require.Equal(t, typ.Name(), typ.SQLStandardName())
+	}
+}
+
 func TestWithoutTypeModifiers(t *testing.T) {
 	testCases := []struct {
 		t        *T
@@ -1008,6 +1070,7 @@ func TestWithoutTypeModifiers(t *testing.T) {
 			MakeTuple([]*T{String, Time, Decimal})},
 		{MakeGeography(geopb.ShapeType_Point, 3857), Geography},
 		{MakeGeometry(geopb.ShapeType_PointZ, 4326), Geometry},
+		{MakePGVector(3), PGVector},
 
 		// Types without modifiers.
 		{Bool, Bool},
@@ -1062,11 +1125,15 @@ func TestDelimiter(t *testing.T) {
 		{TimestampTZ, ","},
 		{Interval, ","},
 		{Jsonb, ","},
+		{Jsonpath, ","},
+		{PGVector, ","},
 		{Uuid, ","},
 		{INet, ","},
 		{Geometry, ":"},
 		{Geography, ":"},
 		{Box2D, ","},
+		{TSQuery, ","},
+		{TSVector, ","},
 		{Void, ","},
 		{EncodedKey, ","},
 	}
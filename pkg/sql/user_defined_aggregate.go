@@ -0,0 +1,218 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// aggregateResolver is the search path of the semantic context of a planner.
+// In addition to the builtin functions, it resolves the user-defined
+// aggregates and functions, which have their own descriptors.
+type aggregateResolver struct {
+	tree.SearchPath
+	p *planner
+}
+
+var _ tree.CustomFunctionDefinitionResolver = aggregateResolver{}
+
+// Resolve implements tree.CustomFunctionDefinitionResolver. User-defined
+// aggregates and functions are only resolved by their schema-qualified name;
+// unqualified names are qualified with the schemas of the search path by
+// tree.ResolveFunction. They are resolved in the current database unless
+// their name is also qualified with a database.
+func (r aggregateResolver) Resolve(name string) *tree.FunctionDefinition {
+	if fn, ok := tree.FunDefs[name]; ok {
+		return fn
+	}
+	if r.p.txn == nil {
+		return nil
+	}
+	dbName := r.p.CurrentDatabase()
+	parts := strings.Split(name, ".")
+	switch len(parts) {
+	case 2:
+	case 3:
+		// The functions of virtual schemas exist in every database.
+		if fn, ok := tree.FunDefs[parts[1]+"."+parts[2]]; ok {
+			return fn
+		}
+		dbName, parts = parts[0], parts[1:]
+	default:
+		return nil
+	}
+	if dbName == "" {
+		return nil
+	}
+	scName, fnName := parts[0], parts[1]
+	ctx := r.p.EvalContext().Context
+	db, err := r.p.Descriptors().GetImmutableDatabaseByName(
+		ctx, r.p.txn, dbName, tree.DatabaseLookupFlags{},
+	)
+	if err != nil || db == nil {
+		// The error, if any, is surfaced by the resolution of the objects of
+		// the statement.
+		return nil
+	}
+	sc, err := r.p.Descriptors().GetImmutableSchemaByName(
+		ctx, r.p.txn, db, scName, tree.SchemaLookupFlags{},
+	)
+	if err != nil || sc == nil {
+		return nil
+	}
+	fns, err := r.p.getFunctionsNamed(ctx, sc, fnName)
+	if err != nil {
+		return nil
+	}
+	var aggregates, overloads []tree.Overload
+	for _, fn := range fns {
+		if fn.GetAggregate() != nil {
+			aggregates = append(aggregates, makeUserDefinedAggregateOverload(fn))
+			continue
+		}
+		// Procedures can only be invoked with CALL.
+		if fn.GetIsProcedure() {
+			continue
+		}
+		// The function is resolved every time the statement is planned, so
+		// the privileges of the user are checked again by every execution.
+		privErr := r.p.CheckPrivilege(ctx, fn, privilege.EXECUTE)
+		overloads = append(overloads, makeUserDefinedFunctionOverloads(fn, privErr)...)
+	}
+	// An aggregate and a function cannot have the same name in a schema.
+	if len(aggregates) > 0 {
+		props := &tree.FunctionProperties{
+			Class:    tree.AggregateClass,
+			Category: "User-defined",
+		}
+		return tree.NewFunctionDefinition(fnName, props, aggregates)
+	}
+	if len(overloads) == 0 {
+		return nil
+	}
+	props := &tree.FunctionProperties{
+		Category:         "User-defined",
+		DistsqlBlocklist: true,
+	}
+	return tree.NewFunctionDefinition(fnName, props, overloads)
+}
+
+// makeUserDefinedAggregateOverload returns the overload of a user-defined
+// aggregate. The overload has no AggregateFunc: the aggregate is evaluated by
+// the execution engine using the expressions of its definition.
+func makeUserDefinedAggregateOverload(agg catalog.FunctionDescriptor) tree.Overload {
+	argTypes := make(tree.ArgTypes, len(agg.GetArgs()))
+	for i, arg := range agg.GetArgs() {
+		argTypes[i].Name = fmt.Sprintf("arg%d", i+1)
+		argTypes[i].Typ = arg.Type
+	}
+	return tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(agg.GetReturnType()),
+		// The support expressions of the aggregate are arbitrary, so the
+		// aggregate is conservatively assumed to be volatile.
+		Volatility:           volatility.Volatile,
+		NullableArgs:         true,
+		UserDefinedAggregate: agg.FuncDesc(),
+	}
+}
+
+// checkAggregatePrivilege checks that the user has the EXECUTE privilege on
+// the given user-defined aggregate, if it is set. User-defined aggregates are
+// resolved every time the statement is planned, so the privileges of the user
+// are checked again by every execution.
+func (p *planner) checkAggregatePrivilege(
+	ctx context.Context, agg tree.UserDefinedAggregateOverload,
+) error {
+	if agg == nil {
+		return nil
+	}
+	desc, err := p.Descriptors().GetImmutableFunctionByID(
+		ctx, p.txn, agg.(*descpb.FunctionDescriptor).ID, tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	return p.CheckPrivilege(ctx, desc, privilege.EXECUTE)
+}
+
+// resolveAggregateTypes resolves the argument types of the signature of a
+// user-defined aggregate.
+func (p *planner) resolveAggregateTypes(
+	ctx context.Context, sig *tree.AggregateSignature,
+) ([]*types.T, error) {
+	typs := make([]*types.T, len(sig.ArgTypes))
+	for i, ref := range sig.ArgTypes {
+		typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, err
+		}
+		if typ.UserDefined() {
+			return nil, unimplemented.NewWithIssuef(74775,
+				"user-defined types cannot be used by aggregates: %s", typ.SQLString())
+		}
+		typs[i] = typ
+	}
+	return typs, nil
+}
+
+// findAggregate returns the descriptor of the user-defined aggregate with the
+// given signature in the given database, or nil if there is no such
+// aggregate. An unqualified aggregate name is looked up in the schemas of the
+// search path.
+func (p *planner) findAggregate(
+	ctx context.Context, db catalog.DatabaseDescriptor, name *tree.FunctionName, argTypes []*types.T,
+) (catalog.FunctionDescriptor, error) {
+	fn, err := p.findFunction(ctx, db, name, argTypes)
+	if err != nil || fn == nil {
+		return nil, err
+	}
+	if fn.GetAggregate() == nil {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "function %s is not an aggregate",
+			aggregateSignatureString(fn.GetName(), argTypes))
+	}
+	return fn, nil
+}
+
+// aggregateSignatureString formats the signature of a user-defined aggregate
+// for error messages.
+func aggregateSignatureString(name string, argTypes []*types.T) string {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('(')
+	for i, typ := range argTypes {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(typ.SQLString())
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// errAggregateDoesNotExist is returned when DROP or ALTER AGGREGATE refers to
+// an aggregate that does not exist.
+func errAggregateDoesNotExist(name string, argTypes []*types.T) error {
+	return pgerror.Newf(pgcode.UndefinedFunction,
+		"aggregate %s does not exist", aggregateSignatureString(name, argTypes))
+}
@@ -0,0 +1,436 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/plpgsql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// makeUserDefinedFunctionOverloads returns the overloads of a user-defined
+// function: one for each number of arguments with which the function can be
+// called, given the defaults of its arguments. The body of the function is
+// parsed when the overloads are built, and interpreted every time the
+// function is called. If privErr is set, it is returned when the function is
+// called; it is used to report that the user lacks the EXECUTE privilege.
+func makeUserDefinedFunctionOverloads(
+	fn catalog.FunctionDescriptor, privErr error,
+) []tree.Overload {
+	desc := fn.FuncDesc()
+	fnArgs := desc.Args
+	required := len(fnArgs)
+	for required > 0 && fnArgs[required-1].DefaultExpr != nil {
+		required--
+	}
+	f, parseErr := makePLpgSQLFunction(desc)
+	overloads := make([]tree.Overload, 0, len(fnArgs)-required+1)
+	for n := required; n <= len(fnArgs); n++ {
+		argTypes := make(tree.ArgTypes, n)
+		for i := range argTypes {
+			argTypes[i].Name = fnArgs[i].Name
+			if argTypes[i].Name == "" {
+				argTypes[i].Name = fmt.Sprintf("arg%d", i+1)
+			}
+			argTypes[i].Typ = fnArgs[i].Type
+		}
+		overloads = append(overloads, tree.Overload{
+			Types:      argTypes,
+			ReturnType: tree.FixedReturnType(desc.ReturnType),
+			Volatility: functionVolatility(desc.Volatility),
+			Fn: eval.FnOverload(func(evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if privErr != nil {
+					return nil, privErr
+				}
+				if parseErr != nil {
+					return nil, parseErr
+				}
+				args, err := addDefaultArgs(evalCtx, desc, args)
+				if err != nil {
+					return nil, err
+				}
+				if desc.Strict {
+					// The arguments given by the caller are not NULL, but the
+					// defaults can be.
+					for _, arg := range args {
+						if arg == tree.DNull {
+							return tree.DNull, nil
+						}
+					}
+				}
+				return f.Call(evalCtx, args)
+			}),
+			// The function executes its statements with the planner of the
+			// evaluation context, which is only available on the gateway.
+			DistsqlBlocklist:    true,
+			NullableArgs:        !desc.Strict,
+			UserDefinedFunction: desc,
+		})
+	}
+	return overloads
+}
+
+// addDefaultArgs returns the arguments of a call to a user-defined function or
+// procedure, completed with the defaults of the arguments which were not given
+// by the caller. The default expressions are evaluated at every call.
+func addDefaultArgs(
+	evalCtx *eval.Context, fn *descpb.FunctionDescriptor, args tree.Datums,
+) (tree.Datums, error) {
+	if len(args) == len(fn.Args) {
+		return args, nil
+	}
+	res := make(tree.Datums, len(fn.Args))
+	copy(res, args)
+	for i := len(args); i < len(fn.Args); i++ {
+		arg := &fn.Args[i]
+		if arg.DefaultExpr == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedFunction,
+				"no value given for argument %d of function %s", i+1, fn.Name)
+		}
+		expr, err := parser.ParseExpr(*arg.DefaultExpr)
+		if err != nil {
+			return nil, err
+		}
+		semaCtx := tree.MakeSemaContext()
+		typedExpr, err := tree.TypeCheck(evalCtx.Context, expr, &semaCtx, arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		d, err := eval.Expr(evalCtx, typedExpr)
+		if err != nil {
+			return nil, err
+		}
+		if d != tree.DNull && !d.ResolvedType().Identical(arg.Type) {
+			if d, err = eval.PerformAssignmentCast(evalCtx, d, arg.Type); err != nil {
+				return nil, err
+			}
+		}
+		res[i] = d
+	}
+	return res, nil
+}
+
+// makePLpgSQLFunction parses the body of a user-defined function or
+// procedure, which is written in PL/pgSQL or in SQL.
+func makePLpgSQLFunction(fn *descpb.FunctionDescriptor) (*plpgsql.Function, error) {
+	params := make([]plpgsql.Param, len(fn.Args))
+	argTypes := make([]*types.T, len(fn.Args))
+	for i := range fn.Args {
+		params[i] = plpgsql.Param{Name: fn.Args[i].Name, Type: fn.Args[i].Type}
+		argTypes[i] = fn.Args[i].Type
+	}
+	name := aggregateSignatureString(fn.Name, argTypes)
+	isSQL := fn.Language == descpb.FunctionDescriptor_SQL
+	parse := plpgsql.Parse
+	if isSQL {
+		parse = plpgsql.ParseSQL
+	}
+	body, err := parse(name, fn.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &plpgsql.Function{
+		Name:       name,
+		Params:     params,
+		ReturnType: fn.ReturnType,
+		Body:       body,
+		Trigger:    fn.ReturnsTrigger,
+		SQL:        isSQL,
+	}, nil
+}
+
+// makeTriggerCall returns the trigger function executed by a row-level
+// trigger, and the description of the trigger event, in which only the values
+// of the row remain to be set.
+func (p *planner) makeTriggerCall(
+	ctx context.Context, trigger *exec.Cascade,
+) (*plpgsql.Function, *plpgsql.TriggerData, error) {
+	tableDesc, err := p.LookupTableByID(ctx, descpb.ID(trigger.TriggerTable.ID()))
+	if err != nil {
+		return nil, nil, err
+	}
+	tn, err := p.getQualifiedTableName(ctx, tableDesc)
+	if err != nil {
+		return nil, nil, err
+	}
+	fn, err := p.Descriptors().GetImmutableFunctionByID(
+		ctx, p.txn, descpb.ID(trigger.TriggerFunction.ID), tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := makePLpgSQLFunction(fn.FuncDesc())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := &plpgsql.TriggerData{
+		Name:        trigger.FKName,
+		TableName:   tn.Object(),
+		TableSchema: tn.Schema(),
+	}
+	switch {
+	case len(trigger.OldOrdinals) == 0:
+		data.Op = "INSERT"
+	case len(trigger.NewOrdinals) == 0:
+		data.Op = "DELETE"
+	default:
+		data.Op = "UPDATE"
+	}
+	tab := trigger.TriggerTable
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		if col := tab.Column(i); col.Kind() == cat.Ordinary {
+			data.ColNames = append(data.ColNames, string(col.ColName()))
+			data.ColTypes = append(data.ColTypes, col.DatumType())
+		}
+	}
+	return f, data, nil
+}
+
+// functionVolatility returns the volatility of a user-defined function.
+func functionVolatility(v descpb.FunctionDescriptor_Volatility) volatility.V {
+	switch v {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		return volatility.Immutable
+	case descpb.FunctionDescriptor_STABLE:
+		return volatility.Stable
+	default:
+		return volatility.Volatile
+	}
+}
+
+// getFunctionDatabase returns the descriptor of the database of the
+// user-defined function or procedure with the given name, which is the current
+// database unless the name is fully qualified. The kind of the object, e.g.
+// "function" or "procedure", is used in errors.
+func (p *planner) getFunctionDatabase(
+	ctx context.Context, kind string, name *tree.FunctionName,
+) (catalog.DatabaseDescriptor, error) {
+	dbName := p.CurrentDatabase()
+	if name.ExplicitCatalog {
+		dbName = string(name.CatalogName)
+	}
+	if dbName == "" {
+		return nil, pgerror.Newf(pgcode.UndefinedDatabase,
+			"cannot use user-defined %ss without being connected to a database", kind)
+	}
+	return p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn, dbName,
+		tree.DatabaseLookupFlags{Required: true})
+}
+
+// getFunctionsNamed returns the descriptors of the user-defined functions and
+// procedures with the given name in the given schema.
+func (p *planner) getFunctionsNamed(
+	ctx context.Context, sc catalog.SchemaDescriptor, name string,
+) ([]catalog.FunctionDescriptor, error) {
+	fn, ok := sc.GetFunction(name)
+	if !ok {
+		return nil, nil
+	}
+	fns := make([]catalog.FunctionDescriptor, len(fn.Overloads))
+	for i := range fn.Overloads {
+		var err error
+		if fns[i], err = p.Descriptors().GetImmutableFunctionByID(
+			ctx, p.txn, fn.Overloads[i].ID, tree.ObjectLookupFlagsWithRequired(),
+		); err != nil {
+			return nil, err
+		}
+	}
+	return fns, nil
+}
+
+// findFunction returns the descriptor of the user-defined function or
+// procedure with the given signature in the given database, or nil if there is
+// no such function. An unqualified function name is looked up in the schemas
+// of the search path.
+func (p *planner) findFunction(
+	ctx context.Context, db catalog.DatabaseDescriptor, name *tree.FunctionName, argTypes []*types.T,
+) (catalog.FunctionDescriptor, error) {
+	find := func(scName string) (catalog.FunctionDescriptor, error) {
+		sc, err := p.Descriptors().GetImmutableSchemaByName(
+			ctx, p.txn, db, scName, tree.SchemaLookupFlags{},
+		)
+		if err != nil || sc == nil {
+			return nil, err
+		}
+		return p.findFunctionInSchema(ctx, sc, name.Object(), argTypes)
+	}
+	if name.ExplicitSchema {
+		return find(string(name.SchemaName))
+	}
+	var fn catalog.FunctionDescriptor
+	err := p.SessionData().SearchPath.IterateSearchPath(func(scName string) error {
+		var err error
+		if fn, err = find(scName); err != nil {
+			return err
+		}
+		if fn != nil {
+			return iterutil.StopIteration()
+		}
+		return nil
+	})
+	return fn, err
+}
+
+// findFunctionInSchema returns the descriptor of the user-defined function or
+// procedure with the given signature in the given schema, or nil if there is
+// no such function.
+func (p *planner) findFunctionInSchema(
+	ctx context.Context, sc catalog.SchemaDescriptor, name string, argTypes []*types.T,
+) (catalog.FunctionDescriptor, error) {
+	fn, _ := sc.GetFunction(name)
+	for _, o := range fn.Overloads {
+		if identicalTypes(o.ArgTypes, argTypes) {
+			return p.Descriptors().GetImmutableFunctionByID(
+				ctx, p.txn, o.ID, tree.ObjectLookupFlagsWithRequired(),
+			)
+		}
+	}
+	return nil, nil
+}
+
+// hasFunctionNamed returns whether the given schema contains a user-defined
+// function with the given name. Procedures are ignored, since they are not
+// resolved like aggregates and functions, and so are aggregates.
+func (p *planner) hasFunctionNamed(
+	ctx context.Context, sc catalog.SchemaDescriptor, name string,
+) (bool, error) {
+	fns, err := p.getFunctionsNamed(ctx, sc, name)
+	if err != nil {
+		return false, err
+	}
+	for _, fn := range fns {
+		if !fn.GetIsProcedure() && fn.GetAggregate() == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasAggregateNamed returns whether the given schema contains a user-defined
+// aggregate with the given name.
+func (p *planner) hasAggregateNamed(
+	ctx context.Context, sc catalog.SchemaDescriptor, name string,
+) (bool, error) {
+	fns, err := p.getFunctionsNamed(ctx, sc, name)
+	if err != nil {
+		return false, err
+	}
+	for _, fn := range fns {
+		if fn.GetAggregate() != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// identicalTypes returns whether the two lists contain identical types.
+func identicalTypes(a, b []*types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Identical(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// functionKind returns the kind of a user-defined function, as used in
+// errors.
+func functionKind(isProcedure bool) string {
+	if isProcedure {
+		return "procedure"
+	}
+	return "function"
+}
+
+// functionDescKind returns the kind of the given user-defined function, as
+// used in errors.
+func functionDescKind(fn catalog.FunctionDescriptor) string {
+	if fn.GetAggregate() != nil {
+		return "aggregate"
+	}
+	return functionKind(fn.GetIsProcedure())
+}
+
+// errFunctionDoesNotExist is returned when DROP FUNCTION, DROP PROCEDURE or
+// CALL refers to a function or procedure that does not exist.
+func errFunctionDoesNotExist(isProcedure bool, name string, argTypes []*types.T) error {
+	return pgerror.Newf(pgcode.UndefinedFunction, "%s %s does not exist",
+		functionKind(isProcedure), aggregateSignatureString(name, argTypes))
+}
+
+// errWrongFunctionKind is returned when a statement for functions refers to
+// a procedure, or conversely.
+func errWrongFunctionKind(isProcedure bool, name string, argTypes []*types.T) error {
+	return pgerror.Newf(pgcode.WrongObjectType, "%s is not a %s",
+		aggregateSignatureString(name, argTypes), functionKind(isProcedure))
+}
+
+func (p *planner) writeFunctionDesc(ctx context.Context, desc *funcdesc.Mutable) error {
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+func (p *planner) writeFunctionDescChange(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	record, recordExists := p.extendedEvalCtx.SchemaChangeJobRecords[desc.ID]
+	if recordExists {
+		// Update it.
+		record.AppendDescription(jobDesc)
+		log.Infof(ctx, "job %d: updated job's specification for change on function %d", record.JobID, desc.ID)
+	} else {
+		// Or, create a new job.
+		jobRecord := jobs.Record{
+			JobID:         p.extendedEvalCtx.ExecCfg.JobRegistry.MakeJobID(),
+			Description:   jobDesc,
+			Username:      p.User(),
+			DescriptorIDs: descpb.IDs{desc.ID},
+			Details: jobspb.SchemaChangeDetails{
+				DescID: desc.ID,
+				// The version distinction for database jobs doesn't matter for
+				// function jobs.
+				FormatVersion: jobspb.DatabaseJobFormatVersion,
+			},
+			Progress:      jobspb.SchemaChangeProgress{},
+			NonCancelable: true,
+		}
+		p.extendedEvalCtx.SchemaChangeJobRecords[desc.ID] = &jobRecord
+		log.Infof(ctx, "queued new schema change job %d for function %d", jobRecord.JobID, desc.ID)
+	}
+
+	return p.writeFunctionDesc(ctx, desc)
+}
@@ -20,7 +20,7 @@ var descriptorMarshalOptions = Options{
 	Doc:      `check for correct unmarshaling of descpb descriptors`,
 	Package:  "github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb",
 	Type:     "Descriptor",
-	Method:   "^Get(Table|Database|Type|Schema)$",
+	Method:   "^Get(Table|Database|Type|Schema|Function)$",
 	Hint:     "see descpb.FromDescriptorWithMVCCTimestamp()",
 }
 
@@ -157,6 +157,24 @@ var upgrades = []upgrade.Upgrade{
 		NoPrecondition,
 		alterSystemSQLInstancesAddLocality,
 	),
+	upgrade.NewTenantUpgrade(
+		"add the system.notifications table",
+		toCV(clusterversion.NotificationsTable),
+		NoPrecondition,
+		notificationsTableMigration,
+	),
+	upgrade.NewTenantUpgrade(
+		"add the system.advisory_locks table",
+		toCV(clusterversion.AdvisoryLocksTable),
+		NoPrecondition,
+		advisoryLocksTableMigration,
+	),
+	upgrade.NewTenantUpgrade(
+		"add the system.replication_slots table",
+		toCV(clusterversion.ReplicationSlotsTable),
+		NoPrecondition,
+		replicationSlotsTableMigration,
+	),
 }
 
 func init() {
@@ -916,6 +916,41 @@ func EncodeExistsInvertedIndexSpans(
 	), nil
 }
 
+// PathElement is an element of a path from the root of a JSON document to
+// one of its values. It is either the value of an object key, or an element of
+// an array if IsArray is true.
+type PathElement struct {
+	Key     string
+	IsArray bool
+}
+
+// EncodePathExistsInvertedIndexSpan takes in a key prefix and returns the
+// span that must be scanned in the inverted index to find the JSON documents
+// that have a value at the given path. The path must end with an object key.
+//
+// The input inKey is prefixed to the keys in the returned span.
+func EncodePathExistsInvertedIndexSpan(b []byte, path []PathElement) (inverted.Span, error) {
+	if len(path) == 0 || path[len(path)-1].IsArray {
+		return inverted.Span{}, errors.AssertionFailedf("path must end with an object key")
+	}
+	b = encoding.EncodeJSONAscending(b)
+	for _, e := range path[:len(path)-1] {
+		if e.IsArray {
+			b = encoding.EncodeArrayAscending(b)
+		} else {
+			b = encoding.EncodeJSONKeyStringAscending(b, e.Key, false /* end */)
+		}
+	}
+	// As in EncodeExistsInvertedIndexSpans, the span must include both the
+	// keys of scalar values (which have no separator after the object key) and
+	// the keys of non-scalar values, but no keys with a longer object key.
+	key := encoding.EncodeJSONKeyStringAscending(b, path[len(path)-1].Key, true /* end */)
+	return inverted.Span{
+		Start: key,
+		End:   keysbase.PrefixEnd(encoding.AddJSONPathSeparator(key[:len(key):len(key)])),
+	}, nil
+}
+
 func (j jsonNull) encodeInvertedIndexKeys(b []byte) ([][]byte, error) {
 	b = encoding.AddJSONPathTerminator(b)
 	return [][]byte{encoding.EncodeNullAscending(b)}, nil
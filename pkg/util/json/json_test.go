@@ -1826,6 +1826,52 @@ func TestEncodeExistsJSONInvertedIndexSpans(t *testing.T) {
 	}
 }
 
+func TestEncodePathExistsInvertedIndexSpan(t *testing.T) {
+	key := func(k string) PathElement { return PathElement{Key: k} }
+	arr := PathElement{IsArray: true}
+	testCases := []struct {
+		indexedValue string
+		path         []PathElement
+		expected     bool
+	}{
+		{`{"a": 1}`, []PathElement{key("a")}, true},
+		{`{"a": null}`, []PathElement{key("a")}, true},
+		{`{"a": []}`, []PathElement{key("a")}, true},
+		{`{"a": {}}`, []PathElement{key("a")}, true},
+		{`{"a": {"b": [1, 2]}}`, []PathElement{key("a")}, true},
+		{`{"a": {"b": [1, 2]}}`, []PathElement{key("a"), key("b")}, true},
+		{`{"a": [{"b": 1}]}`, []PathElement{key("a"), arr, key("b")}, true},
+		{`[{"a": "b"}]`, []PathElement{arr, key("a")}, true},
+
+		{`{"a": 1}`, []PathElement{key("b")}, false},
+		{`{"ab": 1}`, []PathElement{key("a")}, false},
+		{`{"a\u0000": 1}`, []PathElement{key("a")}, false},
+		{`{"a": 1}`, []PathElement{key("a"), key("b")}, false},
+		{`{"a": {"b": 1}}`, []PathElement{key("b")}, false},
+		{`{"a": [{"b": 1}]}`, []PathElement{key("a"), key("b")}, false},
+		{`[{"a": "b"}]`, []PathElement{key("a")}, false},
+		{`"a"`, []PathElement{key("a")}, false},
+		{`["a"]`, []PathElement{arr, key("a")}, false},
+	}
+
+	for _, c := range testCases {
+		keys, err := EncodeInvertedIndexKeys(nil, jsonTestShorthand(c.indexedValue))
+		require.NoError(t, err)
+		span, err := EncodePathExistsInvertedIndexSpan(nil, c.path)
+		require.NoError(t, err)
+		spanExpr := inverted.ExprForSpan(span, true /* tight */)
+		containsKeys, err := spanExpr.ContainsKeys(keys)
+		require.NoError(t, err)
+		if containsKeys != c.expected {
+			t.Errorf("expected span of %v to include %s: %v, but got %v",
+				c.path, c.indexedValue, c.expected, containsKeys)
+		}
+	}
+
+	_, err := EncodePathExistsInvertedIndexSpan(nil, []PathElement{key("a"), arr})
+	require.Error(t, err)
+}
+
 func TestNumInvertedIndexEntries(t *testing.T) {
 	testCases := []struct {
 		value    string
@@ -0,0 +1,799 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// decimalCtx is the context used for arithmetic on numbers.
+var decimalCtx = apd.BaseContext.WithPrecision(20)
+
+// errSuppressed is returned in place of an evaluation error when errors are
+// not being thrown.
+var errSuppressed = errors.New("suppressed jsonpath error")
+
+// Query evaluates the path against the target JSON document, returning the
+// sequence of items that it produces. The vars argument, if not nil, must be
+// an object whose keys are the variables referenced by the path.
+//
+// If silent is true, errors caused by the structure or contents of target,
+// such as a missing key in strict mode or a non-numeric arithmetic operand,
+// are suppressed and Query returns ok=false instead.
+func Query(path *Jsonpath, target, vars json.JSON, silent bool) (_ []json.JSON, ok bool, _ error) {
+	if vars != nil && vars.Type() != json.ObjectJSONType {
+		return nil, false, errors.WithDetail(
+			pgerror.New(pgcode.InvalidParameterValue, `"vars" argument is not an object`),
+			`Jsonpath parameters should be encoded as key-value pairs of "vars" object.`)
+	}
+	c := evalCtx{
+		strict:    path.Strict,
+		throw:     !silent,
+		root:      target,
+		vars:      vars,
+		arraySize: -1,
+	}
+	res, err := c.eval(path.Expr, target, false /* unwrap */)
+	if err != nil {
+		if errors.Is(err, errSuppressed) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return res, true, nil
+}
+
+// Exists returns whether evaluating the path against the target produces
+// any items. It implements jsonb_path_exists and the @? operator.
+func Exists(path *Jsonpath, target, vars json.JSON, silent bool) (exists, ok bool, _ error) {
+	res, ok, err := Query(path, target, vars, silent)
+	if err != nil || !ok {
+		return false, ok, err
+	}
+	return len(res) > 0, true, nil
+}
+
+// Match returns the result of a path predicate evaluated against the target.
+// It implements jsonb_path_match and the @@ operator. If ok is false, the
+// result is unknown (i.e. NULL).
+func Match(path *Jsonpath, target, vars json.JSON, silent bool) (match, ok bool, _ error) {
+	res, ok, err := Query(path, target, vars, silent)
+	if err != nil || !ok {
+		return false, ok, err
+	}
+	if len(res) == 1 {
+		if b, isBool := res[0].AsBool(); isBool {
+			return b, true, nil
+		}
+		if res[0].Type() == json.NullJSONType {
+			return false, false, nil
+		}
+	}
+	if silent {
+		return false, false, nil
+	}
+	return false, false, pgerror.New(pgcode.SingletonSQLJSONItemRequired,
+		"single boolean result is expected")
+}
+
+// predicateResult is the result of a predicate, which uses three-valued
+// logic.
+type predicateResult int
+
+const (
+	predFalse predicateResult = iota
+	predTrue
+	predUnknown
+)
+
+func (r predicateResult) toJSON() json.JSON {
+	switch r {
+	case predTrue:
+		return json.TrueJSONValue
+	case predFalse:
+		return json.FalseJSONValue
+	}
+	return json.NullJSONValue
+}
+
+type evalCtx struct {
+	strict bool
+	// throw is false if errors should be suppressed, either because the
+	// caller asked for it or because a predicate is being evaluated, in which
+	// case errors make the predicate unknown.
+	throw bool
+	root  json.JSON
+	vars  json.JSON
+	// arraySize is the size of the innermost array being subscripted, which
+	// is used to evaluate last.
+	arraySize int
+}
+
+// error returns err if errors are being thrown, and errSuppressed otherwise.
+func (c *evalCtx) error(err error) error {
+	if c.throw {
+		return err
+	}
+	return errSuppressed
+}
+
+// eval evaluates the expression for the given current item, which is the
+// value of @. If unwrap is true and the path is evaluated in lax mode, any
+// arrays in the result are replaced by their elements.
+func (c *evalCtx) eval(e Expr, current json.JSON, unwrap bool) ([]json.JSON, error) {
+	res, err := c.evalNoUnwrap(e, current)
+	if err != nil || !unwrap || c.strict {
+		return res, err
+	}
+	var unwrapped []json.JSON
+	for _, item := range res {
+		if item.Type() != json.ArrayJSONType {
+			unwrapped = append(unwrapped, item)
+			continue
+		}
+		if unwrapped, err = appendArrayElements(unwrapped, item); err != nil {
+			return nil, err
+		}
+	}
+	return unwrapped, nil
+}
+
+func (c *evalCtx) evalNoUnwrap(e Expr, current json.JSON) ([]json.JSON, error) {
+	switch t := e.(type) {
+	case Path:
+		items, err := c.evalNoUnwrap(t[0], current)
+		if err != nil {
+			return nil, err
+		}
+		for _, accessor := range t[1:] {
+			var next []json.JSON
+			for _, item := range items {
+				if next, err = c.applyAccessor(next, accessor, item, current, !c.strict /* unwrap */); err != nil {
+					return nil, err
+				}
+			}
+			items = next
+		}
+		return items, nil
+	case Root:
+		return []json.JSON{c.root}, nil
+	case Current:
+		return []json.JSON{current}, nil
+	case Last:
+		if c.arraySize < 0 {
+			return nil, errors.AssertionFailedf("evaluating jsonpath LAST outside of array subscript")
+		}
+		return []json.JSON{json.FromInt(c.arraySize - 1)}, nil
+	case Variable:
+		var val json.JSON
+		if c.vars != nil {
+			var err error
+			if val, err = c.vars.FetchValKey(string(t)); err != nil {
+				return nil, err
+			}
+		}
+		if val == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "could not find jsonpath variable %q", string(t))
+		}
+		return []json.JSON{val}, nil
+	case Scalar:
+		return []json.JSON{t.Val}, nil
+	case Operation:
+		switch {
+		case t.Type.isArithmetic() && t.Type.isUnary():
+			return c.evalUnaryArithmetic(t, current)
+		case t.Type.isArithmetic():
+			res, err := c.evalBinaryArithmetic(t, current)
+			if err != nil {
+				return nil, err
+			}
+			return []json.JSON{res}, nil
+		}
+	}
+	if IsPredicate(e) {
+		res, err := c.evalPredicate(e, current)
+		if err != nil {
+			return nil, err
+		}
+		return []json.JSON{res.toJSON()}, nil
+	}
+	return nil, errors.AssertionFailedf("unexpected jsonpath expression %T", e)
+}
+
+// appendArrayElements appends the elements of the array to res.
+func appendArrayElements(res []json.JSON, array json.JSON) ([]json.JSON, error) {
+	for i := 0; i < array.Len(); i++ {
+		elem, err := array.FetchValIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, elem)
+	}
+	return res, nil
+}
+
+// applyAccessor applies the accessor to the item and appends the results to
+// res. If unwrap is true, accessors that expect an object are applied to
+// each element of an array instead, which is how lax mode unwraps arrays.
+func (c *evalCtx) applyAccessor(
+	res []json.JSON, accessor Expr, item, current json.JSON, unwrap bool,
+) ([]json.JSON, error) {
+	unwrapArray := func() ([]json.JSON, error) {
+		for i := 0; i < item.Len(); i++ {
+			elem, err := item.FetchValIdx(i)
+			if err != nil {
+				return nil, err
+			}
+			if res, err = c.applyAccessor(res, accessor, elem, current, false /* unwrap */); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	isArray := item.Type() == json.ArrayJSONType
+
+	switch t := accessor.(type) {
+	case Key:
+		switch {
+		case item.Type() == json.ObjectJSONType:
+			val, err := item.FetchValKey(string(t))
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				return append(res, val), nil
+			}
+			if c.strict {
+				return nil, c.error(pgerror.Newf(pgcode.SQLJSONMemberNotFound,
+					"JSON object does not contain key %q", string(t)))
+			}
+		case unwrap && isArray:
+			return unwrapArray()
+		case c.strict:
+			return nil, c.error(pgerror.New(pgcode.SQLJSONMemberNotFound,
+				"jsonpath member accessor can only be applied to an object"))
+		}
+		return res, nil
+
+	case AnyKey:
+		switch {
+		case item.Type() == json.ObjectJSONType:
+			iter, err := item.ObjectIter()
+			if err != nil {
+				return nil, err
+			}
+			for iter.Next() {
+				res = append(res, iter.Value())
+			}
+		case unwrap && isArray:
+			return unwrapArray()
+		case c.strict:
+			return nil, c.error(pgerror.New(pgcode.SQLJSONObjectNotFound,
+				"jsonpath wildcard member accessor can only be applied to an object"))
+		}
+		return res, nil
+
+	case AnyArray:
+		switch {
+		case isArray:
+			return appendArrayElements(res, item)
+		case c.strict:
+			return nil, c.error(pgerror.New(pgcode.SQLJSONArrayNotFound,
+				"jsonpath wildcard array accessor can only be applied to an array"))
+		}
+		// In lax mode, a non-array is treated as an array of one element.
+		return append(res, item), nil
+
+	case ArrayList:
+		return c.applyArrayList(res, t, item, current)
+
+	case AnyPath:
+		return c.applyAnyPath(res, t, item, 0 /* level */)
+
+	case Filter:
+		if unwrap && isArray {
+			return unwrapArray()
+		}
+		pred, err := c.evalPredicate(t.Cond, item)
+		if err != nil {
+			return nil, err
+		}
+		if pred == predTrue {
+			res = append(res, item)
+		}
+		return res, nil
+
+	case Method:
+		if unwrap && isArray && t.Type != TypeMethod && t.Type != SizeMethod {
+			return unwrapArray()
+		}
+		val, err := c.applyMethod(t.Type, item)
+		if err != nil {
+			return nil, err
+		}
+		if t.Type == KeyValueMethod {
+			return appendArrayElements(res, val)
+		}
+		return append(res, val), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected jsonpath accessor %T", accessor)
+}
+
+// applyArrayList returns the elements of the array item at the subscripts
+// of the list.
+func (c *evalCtx) applyArrayList(
+	res []json.JSON, list ArrayList, item, current json.JSON,
+) ([]json.JSON, error) {
+	if item.Type() != json.ArrayJSONType {
+		if c.strict {
+			return nil, c.error(pgerror.New(pgcode.SQLJSONArrayNotFound,
+				"jsonpath array accessor can only be applied to an array"))
+		}
+		// In lax mode, a non-array is treated as an array of one element.
+		b := json.NewArrayBuilder(1)
+		b.Add(item)
+		item = b.Build()
+	}
+	size := item.Len()
+	outerSize := c.arraySize
+	c.arraySize = size
+	defer func() { c.arraySize = outerSize }()
+
+	for _, sub := range list {
+		from, err := c.evalSubscript(sub.From, current)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if sub.To != nil {
+			if to, err = c.evalSubscript(sub.To, current); err != nil {
+				return nil, err
+			}
+		}
+		if c.strict && (from < 0 || from > to || to >= size) {
+			return nil, c.error(pgerror.New(pgcode.InvalidSQLJSONSubscript,
+				"jsonpath array subscript is out of bounds"))
+		}
+		if from < 0 {
+			from = 0
+		}
+		if to >= size {
+			to = size - 1
+		}
+		for i := from; i <= to; i++ {
+			elem, err := item.FetchValIdx(i)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, elem)
+		}
+	}
+	return res, nil
+}
+
+// evalSubscript evaluates an array subscript, which must be a single number.
+// Non-integral subscripts are truncated.
+func (c *evalCtx) evalSubscript(e Expr, current json.JSON) (int, error) {
+	res, err := c.eval(e, current, true /* unwrap */)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 1 {
+		if d, ok := res[0].AsDecimal(); ok {
+			f, err := d.Float64()
+			if err != nil || math.IsNaN(f) || f < math.MinInt32 || f > math.MaxInt32 {
+				return 0, c.error(pgerror.New(pgcode.InvalidSQLJSONSubscript,
+					"jsonpath array subscript is out of integer range"))
+			}
+			return int(math.Trunc(f)), nil
+		}
+	}
+	return 0, c.error(pgerror.New(pgcode.InvalidSQLJSONSubscript,
+		"jsonpath array subscript is not a single numeric value"))
+}
+
+// applyAnyPath appends the item, if it is within the levels of the .**
+// accessor, and its descendants to res.
+func (c *evalCtx) applyAnyPath(
+	res []json.JSON, anyPath AnyPath, item json.JSON, level int,
+) ([]json.JSON, error) {
+	if level >= anyPath.First && (anyPath.Last == AnyLevel || level <= anyPath.Last) {
+		res = append(res, item)
+	}
+	if anyPath.Last != AnyLevel && level >= anyPath.Last {
+		return res, nil
+	}
+	var children []json.JSON
+	var err error
+	switch item.Type() {
+	case json.ArrayJSONType:
+		if children, err = appendArrayElements(nil, item); err != nil {
+			return nil, err
+		}
+	case json.ObjectJSONType:
+		iter, err := item.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		for iter.Next() {
+			children = append(children, iter.Value())
+		}
+	}
+	for _, child := range children {
+		if res, err = c.applyAnyPath(res, anyPath, child, level+1); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+var jsonTypeNames = map[json.Type]string{
+	json.NullJSONType:   "null",
+	json.StringJSONType: "string",
+	json.NumberJSONType: "number",
+	json.FalseJSONType:  "boolean",
+	json.TrueJSONType:   "boolean",
+	json.ArrayJSONType:  "array",
+	json.ObjectJSONType: "object",
+}
+
+// applyMethod returns the result of the item method applied to the item. For
+// .keyvalue(), the result is an array of the items produced.
+func (c *evalCtx) applyMethod(method MethodType, item json.JSON) (json.JSON, error) {
+	switch method {
+	case TypeMethod:
+		return json.FromString(jsonTypeNames[item.Type()]), nil
+
+	case SizeMethod:
+		if item.Type() == json.ArrayJSONType {
+			return json.FromInt(item.Len()), nil
+		}
+		if c.strict {
+			return nil, c.error(pgerror.New(pgcode.SQLJSONArrayNotFound,
+				"jsonpath item method .size() can only be applied to an array"))
+		}
+		return json.FromInt(1), nil
+
+	case DoubleMethod:
+		var f float64
+		switch item.Type() {
+		case json.NumberJSONType:
+			d, _ := item.AsDecimal()
+			var err error
+			if f, err = d.Float64(); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, c.error(pgerror.New(pgcode.NonNumericSQLJSONItem,
+					"numeric argument of jsonpath item method .double() is out of range for type double precision"))
+			}
+		case json.StringJSONType:
+			s, err := item.AsText()
+			if err != nil {
+				return nil, err
+			}
+			if f, err = strconv.ParseFloat(strings.TrimSpace(*s), 64); err != nil ||
+				math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, c.error(pgerror.New(pgcode.NonNumericSQLJSONItem,
+					"string argument of jsonpath item method .double() is not a valid representation of a double precision number"))
+			}
+		default:
+			return nil, c.error(pgerror.New(pgcode.NonNumericSQLJSONItem,
+				"jsonpath item method .double() can only be applied to a string or numeric value"))
+		}
+		return json.FromFloat64(f)
+
+	case CeilingMethod, FloorMethod, AbsMethod:
+		d, ok := item.AsDecimal()
+		if !ok {
+			return nil, c.error(pgerror.Newf(pgcode.NonNumericSQLJSONItem,
+				"jsonpath item method .%s() can only be applied to a numeric value", method))
+		}
+		var res apd.Decimal
+		var err error
+		switch method {
+		case CeilingMethod:
+			_, err = decimalCtx.Ceil(&res, d)
+		case FloorMethod:
+			_, err = decimalCtx.Floor(&res, d)
+		case AbsMethod:
+			_, err = decimalCtx.Abs(&res, d)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return json.FromDecimal(res), nil
+
+	case KeyValueMethod:
+		if item.Type() != json.ObjectJSONType {
+			return nil, c.error(pgerror.New(pgcode.SQLJSONObjectNotFound,
+				"jsonpath item method .keyvalue() can only be applied to an object"))
+		}
+		iter, err := item.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		b := json.NewArrayBuilder(item.Len())
+		for iter.Next() {
+			obj := json.NewObjectBuilder(2)
+			obj.Add("key", json.FromString(iter.Key()))
+			obj.Add("value", iter.Value())
+			b.Add(obj.Build())
+		}
+		return b.Build(), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected jsonpath method %s", method)
+}
+
+// evalUnaryArithmetic applies a unary + or - to each item of its operand.
+func (c *evalCtx) evalUnaryArithmetic(op Operation, current json.JSON) ([]json.JSON, error) {
+	items, err := c.eval(op.Left, current, true /* unwrap */)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]json.JSON, 0, len(items))
+	for _, item := range items {
+		d, ok := item.AsDecimal()
+		if !ok {
+			return nil, c.error(pgerror.Newf(pgcode.NonNumericSQLJSONItem,
+				"operand of unary jsonpath operator %s is not a numeric value", op.Type))
+		}
+		if op.Type == OpMinus {
+			var neg apd.Decimal
+			neg.Neg(d)
+			item = json.FromDecimal(neg)
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// evalBinaryArithmetic evaluates a binary arithmetic operation, whose
+// operands must both be single numbers.
+func (c *evalCtx) evalBinaryArithmetic(op Operation, current json.JSON) (json.JSON, error) {
+	operand := func(e Expr, side string) (*apd.Decimal, error) {
+		items, err := c.eval(e, current, true /* unwrap */)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 1 {
+			if d, ok := items[0].AsDecimal(); ok {
+				return d, nil
+			}
+		}
+		return nil, c.error(pgerror.Newf(pgcode.SingletonSQLJSONItemRequired,
+			"%s operand of jsonpath operator %s is not a single numeric value", side, op.Type))
+	}
+	left, err := operand(op.Left, "left")
+	if err != nil {
+		return nil, err
+	}
+	right, err := operand(op.Right, "right")
+	if err != nil {
+		return nil, err
+	}
+	if (op.Type == OpDiv || op.Type == OpMod) && right.IsZero() {
+		return nil, c.error(pgerror.New(pgcode.DivisionByZero, "division by zero"))
+	}
+	var res apd.Decimal
+	switch op.Type {
+	case OpAdd:
+		_, err = decimalCtx.Add(&res, left, right)
+	case OpSub:
+		_, err = decimalCtx.Sub(&res, left, right)
+	case OpMul:
+		_, err = decimalCtx.Mul(&res, left, right)
+	case OpDiv:
+		_, err = decimalCtx.Quo(&res, left, right)
+	case OpMod:
+		_, err = decimalCtx.Rem(&res, left, right)
+	}
+	if err != nil {
+		return nil, c.error(pgerror.Wrapf(err, pgcode.NumericValueOutOfRange,
+			"jsonpath operator %s", op.Type))
+	}
+	return json.FromDecimal(res), nil
+}
+
+// evalPredicate evaluates a predicate for the given current item. Errors that
+// occur while evaluating the operands of the predicate make the result
+// unknown rather than being returned.
+func (c *evalCtx) evalPredicate(e Expr, current json.JSON) (predicateResult, error) {
+	nested := *c
+	nested.throw = false
+	res, err := nested.evalPredicateInternal(e, current)
+	if errors.Is(err, errSuppressed) {
+		return predUnknown, nil
+	}
+	return res, err
+}
+
+func (c *evalCtx) evalPredicateInternal(e Expr, current json.JSON) (predicateResult, error) {
+	if re, ok := e.(Regex); ok {
+		return c.evalComparison(re.Left, nil /* right */, current, func(l, _ json.JSON) predicateResult {
+			s, ok := asString(l)
+			if !ok {
+				return predUnknown
+			}
+			return boolToPredicate(re.re.MatchString(s))
+		})
+	}
+	op := e.(Operation)
+	switch op.Type {
+	case OpAnd, OpOr:
+		left, err := c.evalPredicate(op.Left, current)
+		if err != nil {
+			return predUnknown, err
+		}
+		if (op.Type == OpAnd && left == predFalse) || (op.Type == OpOr && left == predTrue) {
+			return left, nil
+		}
+		right, err := c.evalPredicate(op.Right, current)
+		if err != nil {
+			return predUnknown, err
+		}
+		if (op.Type == OpAnd && right == predTrue) || (op.Type == OpOr && right == predFalse) {
+			return left, nil
+		}
+		if right == predUnknown {
+			return predUnknown, nil
+		}
+		return right, nil
+
+	case OpNot:
+		res, err := c.evalPredicate(op.Left, current)
+		switch {
+		case err != nil || res == predUnknown:
+			return predUnknown, err
+		case res == predTrue:
+			return predFalse, nil
+		}
+		return predTrue, nil
+
+	case OpIsUnknown:
+		res, err := c.evalPredicate(op.Left, current)
+		if err != nil {
+			return predUnknown, err
+		}
+		return boolToPredicate(res == predUnknown), nil
+
+	case OpExists:
+		res, err := c.eval(op.Left, current, false /* unwrap */)
+		if err != nil {
+			return predUnknown, err
+		}
+		return boolToPredicate(len(res) > 0), nil
+
+	case OpStartsWith:
+		return c.evalComparison(op.Left, op.Right, current, func(l, r json.JSON) predicateResult {
+			ls, lok := asString(l)
+			rs, rok := asString(r)
+			if !lok || !rok {
+				return predUnknown
+			}
+			return boolToPredicate(strings.HasPrefix(ls, rs))
+		})
+
+	case OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+		return c.evalComparison(op.Left, op.Right, current, func(l, r json.JSON) predicateResult {
+			return compareItems(op.Type, l, r)
+		})
+	}
+	return predUnknown, errors.AssertionFailedf("unexpected jsonpath predicate %s", op.Type)
+}
+
+// evalComparison evaluates a predicate that compares each item of the left
+// operand with each item of the right operand (or just tests each item of the
+// left operand, if right is nil). The predicate is true if the comparison is
+// true for any pair of items. If a comparison is unknown, the predicate is
+// unknown, unless (in lax mode only) another comparison is true.
+func (c *evalCtx) evalComparison(
+	left, right Expr, current json.JSON, cmp func(l, r json.JSON) predicateResult,
+) (predicateResult, error) {
+	leftItems, err := c.eval(left, current, true /* unwrap */)
+	if err != nil {
+		return predUnknown, err
+	}
+	rightItems := []json.JSON{nil}
+	if right != nil {
+		if rightItems, err = c.eval(right, current, true /* unwrap */); err != nil {
+			return predUnknown, err
+		}
+	}
+	found, unknown := false, false
+	for _, l := range leftItems {
+		for _, r := range rightItems {
+			switch cmp(l, r) {
+			case predUnknown:
+				if c.strict {
+					return predUnknown, nil
+				}
+				unknown = true
+			case predTrue:
+				if !c.strict {
+					return predTrue, nil
+				}
+				found = true
+			}
+		}
+	}
+	if found {
+		return predTrue, nil
+	}
+	if unknown {
+		return predUnknown, nil
+	}
+	return predFalse, nil
+}
+
+// compareItems compares two items with the given comparison operator. Items
+// of different types are not comparable, except that null is not equal to
+// anything other than null.
+func compareItems(op OperationType, l, r json.JSON) predicateResult {
+	lt, rt := l.Type(), r.Type()
+	if lt == json.TrueJSONType {
+		lt = json.FalseJSONType
+	}
+	if rt == json.TrueJSONType {
+		rt = json.FalseJSONType
+	}
+	if lt != rt {
+		if lt == json.NullJSONType || rt == json.NullJSONType {
+			return boolToPredicate(op == OpNotEqual)
+		}
+		return predUnknown
+	}
+	if lt == json.ArrayJSONType || lt == json.ObjectJSONType {
+		return predUnknown
+	}
+	cmp, err := l.Compare(r)
+	if err != nil {
+		return predUnknown
+	}
+	switch op {
+	case OpEqual:
+		return boolToPredicate(cmp == 0)
+	case OpNotEqual:
+		return boolToPredicate(cmp != 0)
+	case OpLess:
+		return boolToPredicate(cmp < 0)
+	case OpLessEqual:
+		return boolToPredicate(cmp <= 0)
+	case OpGreater:
+		return boolToPredicate(cmp > 0)
+	case OpGreaterEqual:
+		return boolToPredicate(cmp >= 0)
+	}
+	return predUnknown
+}
+
+func boolToPredicate(b bool) predicateResult {
+	if b {
+		return predTrue
+	}
+	return predFalse
+}
+
+func asString(j json.JSON) (string, bool) {
+	if j == nil || j.Type() != json.StringJSONType {
+		return "", false
+	}
+	s, err := j.AsText()
+	if err != nil || s == nil {
+		return "", false
+	}
+	return *s, true
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	for _, tc := range []struct {
+		target   string
+		path     string
+		vars     string
+		expected string
+		err      string
+	}{
+		{target: `{"a": {"b": 1}}`, path: `$.a.b`, expected: `[1]`},
+		{target: `{"a": {"b": 1}}`, path: `$.a.c`, expected: `[]`},
+		{target: `{"a": {"b": 1}}`, path: `strict $.a.c`, err: `JSON object does not contain key "c"`},
+		{target: `{"a": [{"b": 1}, {"b": 2}, 3]}`, path: `$.a.b`, expected: `[1, 2]`},
+		{target: `{"a": [{"b": 1}, {"b": 2}]}`, path: `strict $.a.b`,
+			err: `jsonpath member accessor can only be applied to an object`},
+		{target: `{"a": [{"b": 1}, {"b": 2}]}`, path: `strict $.a[*].b`, expected: `[1, 2]`},
+		{target: `{"a": 1}`, path: `$.a[*]`, expected: `[1]`},
+		{target: `{"a": 1}`, path: `strict $.a[*]`,
+			err: `jsonpath wildcard array accessor can only be applied to an array`},
+		{target: `{"a": 1, "b": [2]}`, path: `$.*`, expected: `[1, [2]]`},
+		{target: `[1, 2, 3, 4]`, path: `$[last]`, expected: `[4]`},
+		{target: `[1, 2, 3, 4]`, path: `$[0 to 1, last - 1]`, expected: `[1, 2, 3]`},
+		{target: `[1, 2, 3, 4]`, path: `$[2 to 10]`, expected: `[3, 4]`},
+		{target: `[1, 2, 3, 4]`, path: `$[1.7]`, expected: `[2]`},
+		{target: `[1, 2, 3, 4]`, path: `strict $[10]`, err: `jsonpath array subscript is out of bounds`},
+		{target: `[1, 2, 3, 4]`, path: `$["a"]`, err: `jsonpath array subscript is not a single numeric value`},
+		{target: `{"a": {"b": [1]}}`, path: `$.**`, expected: `[{"a": {"b": [1]}}, {"b": [1]}, [1], 1]`},
+		{target: `{"a": {"b": [1]}}`, path: `$.**{2 to last}`, expected: `[[1], 1]`},
+		{target: `{"a": [1, 5, 3]}`, path: `$.a[*] ? (@ > 2)`, expected: `[5, 3]`},
+		{target: `{"a": [1, 5, 3]}`, path: `$.a ? (@ > 2)`, expected: `[5, 3]`},
+		{target: `{"a": [1, 5, 3]}`, path: `$ ? (@.a > 4)`, expected: `[{"a": [1, 5, 3]}]`},
+		{target: `{"a": [1, 5, 3]}`, path: `strict $ ? (@.a > 4)`, expected: `[]`},
+		{target: `{"a": [1, 5, 3]}`, path: `$.a[*] ? (@ > $min && @ < $max)`, vars: `{"min": 2, "max": 4}`,
+			expected: `[3]`},
+		{target: `{"a": [1, 5, 3]}`, path: `$.a[*] ? (@ > $min)`, err: `could not find jsonpath variable "min"`},
+		{target: `{"a": [1, 5, 3]}`, path: `$.a[*] ? (@ > $min)`, vars: `[]`, err: `"vars" argument is not an object`},
+		{target: `["abc", "xabc", 1]`, path: `$[*] ? (@ starts with "ab")`, expected: `["abc"]`},
+		{target: `["abc", "ABD", "xyz"]`, path: `$[*] ? (@ like_regex "^ab" flag "i")`, expected: `["abc", "ABD"]`},
+		{target: `["a.c", "abc"]`, path: `$[*] ? (@ like_regex "a.c" flag "q")`, expected: `["a.c"]`},
+		{target: `[1, "a", null, true]`, path: `$[*] ? (@ == null)`, expected: `[null]`},
+		{target: `[1, "a", null, true]`, path: `$[*] ? (@ != null)`, expected: `[1, "a", true]`},
+		{target: `[1, "a", null, true]`, path: `$[*] ? ((@ > 0) is unknown)`, expected: `["a", true]`},
+		{target: `[1, "a", null, true]`, path: `$[*] ? (!(@ > 0))`, expected: `[null]`},
+		{target: `[{"a": 1}, {"b": 2}]`, path: `$[*] ? (exists (@.a))`, expected: `[{"a": 1}]`},
+		{target: `[1, 2]`, path: `$[*] > 1`, expected: `[true]`},
+		{target: `[1, "a"]`, path: `$[*] > 0`, expected: `[true]`},
+		{target: `[1, "a"]`, path: `strict $[*] > 0`, expected: `[null]`},
+		{target: `[1, 2]`, path: `$[*] > 5`, expected: `[false]`},
+		{target: `{"a": 7, "b": 2}`, path: `$.a + $.b * 2`, expected: `[11]`},
+		{target: `{"a": 7, "b": 2}`, path: `$.a / $.b`, expected: `[3.5]`},
+		{target: `{"a": 7, "b": 2}`, path: `$.a % $.b`, expected: `[1]`},
+		{target: `{"a": 7, "b": 0}`, path: `$.a / $.b`, err: `division by zero`},
+		{target: `{"a": [1, 2]}`, path: `strict $.a + 1`,
+			err: `left operand of jsonpath operator + is not a single numeric value`},
+		{target: `{"a": [1, 2]}`, path: `-$.a`, expected: `[-1, -2]`},
+		{target: `{"a": "x"}`, path: `-$.a`, err: `operand of unary jsonpath operator - is not a numeric value`},
+		{target: `[1, "a", [], {}, null, false]`, path: `$[*].type()`,
+			expected: `["number", "string", "array", "object", "null", "boolean"]`},
+		{target: `[[1, 2], 3]`, path: `$[*].size()`, expected: `[2, 1]`},
+		{target: `[[1, 2], 3]`, path: `strict $[*].size()`,
+			err: `jsonpath item method .size() can only be applied to an array`},
+		{target: `[1.5, -1.5]`, path: `$.ceiling()`, expected: `[2, -1]`},
+		{target: `[1.5, -1.5]`, path: `$.floor()`, expected: `[1, -2]`},
+		{target: `[1.5, -1.5]`, path: `$.abs()`, expected: `[1.5, 1.5]`},
+		{target: `["1.5", 2]`, path: `$.double()`, expected: `[1.5, 2]`},
+		{target: `["x"]`, path: `$.double()`,
+			err: `string argument of jsonpath item method .double() is not a valid representation`},
+		{target: `{"a": 1, "b": [2]}`, path: `$.keyvalue()`,
+			expected: `[{"key": "a", "value": 1}, {"key": "b", "value": [2]}]`},
+		{target: `[1]`, path: `$.keyvalue()`,
+			err: `jsonpath item method .keyvalue() can only be applied to an object`},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			path, err := Parse(tc.path)
+			require.NoError(t, err)
+			target, err := json.ParseJSON(tc.target)
+			require.NoError(t, err)
+			var vars json.JSON
+			if tc.vars != "" {
+				vars, err = json.ParseJSON(tc.vars)
+				require.NoError(t, err)
+			}
+			res, ok, err := Query(path, target, vars, false /* silent */)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+			b := json.NewArrayBuilder(len(res))
+			for _, item := range res {
+				b.Add(item)
+			}
+			assert.Equal(t, tc.expected, b.Build().String())
+		})
+	}
+}
+
+func TestExistsAndMatch(t *testing.T) {
+	target, err := json.ParseJSON(`{"a": 1, "b": [1, 2]}`)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		path   string
+		silent bool
+		exists string
+		match  string
+	}{
+		{path: `$.a`, exists: `true`, match: `error`},
+		{path: `$.a`, silent: true, exists: `true`, match: `null`},
+		{path: `$.c`, exists: `false`, match: `error`},
+		{path: `strict $.c`, exists: `error`, match: `error`},
+		{path: `strict $.c`, silent: true, exists: `null`, match: `null`},
+		{path: `$.a == 1`, exists: `true`, match: `true`},
+		{path: `$.b[*] > 1`, exists: `true`, match: `true`},
+		{path: `$.b[*] > 5`, exists: `true`, match: `false`},
+		{path: `$.a == "x"`, exists: `true`, match: `null`},
+		{path: `$.b ? (@ > 1)`, exists: `true`, match: `error`},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			path, err := Parse(tc.path)
+			require.NoError(t, err)
+			format := func(res, ok bool, err error) string {
+				switch {
+				case err != nil:
+					return "error"
+				case !ok:
+					return "null"
+				case res:
+					return "true"
+				}
+				return "false"
+			}
+			assert.Equal(t, tc.exists, format(Exists(path, target, nil /* vars */, tc.silent)))
+			assert.Equal(t, tc.match, format(Match(path, target, nil /* vars */, tc.silent)))
+		})
+	}
+}
@@ -0,0 +1,426 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jsonpath implements the SQL/JSON path language, which is used by
+// the jsonpath type, the jsonb_path_* builtins and the @? and @@ operators.
+package jsonpath
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// Jsonpath is a parsed SQL/JSON path expression.
+type Jsonpath struct {
+	// Strict is true if the path is evaluated in strict mode, in which
+	// structural errors (such as accessing a missing key) are reported instead
+	// of being ignored, and arrays are not automatically unwrapped.
+	Strict bool
+	// Expr is the root of the expression.
+	Expr Expr
+}
+
+// String returns the canonical text form of the path.
+func (p *Jsonpath) String() string {
+	var buf bytes.Buffer
+	if p.Strict {
+		buf.WriteString("strict ")
+	}
+	formatExpr(&buf, p.Expr, true /* parens */)
+	return buf.String()
+}
+
+// Expr is a node of a jsonpath expression. Expressions are either a Path, a
+// primary (such as Root or a Scalar), an accessor within a Path, or an
+// operation combining other expressions.
+type Expr interface {
+	jsonpathExpr()
+}
+
+// Path is a primary followed by a chain of accessors, e.g. $.a[0].b. The
+// first element is the primary, and the remaining elements are accessors that
+// are applied in order to each item produced by the previous element.
+type Path []Expr
+
+// Root is the $ primary, which refers to the JSON document being queried.
+type Root struct{}
+
+// Current is the @ primary, which refers to the item being tested by the
+// innermost filter.
+type Current struct{}
+
+// Last is the last primary, which refers to the last index of the innermost
+// array being subscripted.
+type Last struct{}
+
+// Variable is a $name primary, which refers to a value passed in the vars
+// argument of the jsonb_path_* builtins.
+type Variable string
+
+// Scalar is a literal string, number, boolean or null.
+type Scalar struct {
+	Val json.JSON
+}
+
+// Key is the .name accessor, which returns the value of the given key of an
+// object.
+type Key string
+
+// AnyKey is the .* accessor, which returns all values of an object.
+type AnyKey struct{}
+
+// AnyArray is the [*] accessor, which returns all elements of an array.
+type AnyArray struct{}
+
+// ArraySubscript is a single subscript of an ArrayList accessor. If To is
+// nil, the subscript selects the single element at From.
+type ArraySubscript struct {
+	From, To Expr
+}
+
+// ArrayList is the [...] accessor, which returns the array elements at the
+// given subscripts.
+type ArrayList []ArraySubscript
+
+// AnyLevel is the upper bound of an AnyPath accessor that is unbounded.
+const AnyLevel = -1
+
+// AnyPath is the .** accessor, which returns the item and all of its
+// descendants at the nesting levels [First, Last]. Last is AnyLevel if there
+// is no upper bound.
+type AnyPath struct {
+	First, Last int
+}
+
+// Filter is the ?(...) accessor, which returns the items for which the
+// condition is true.
+type Filter struct {
+	Cond Expr
+}
+
+// MethodType is the type of a Method accessor.
+type MethodType int
+
+// Method types.
+const (
+	TypeMethod MethodType = iota
+	SizeMethod
+	DoubleMethod
+	CeilingMethod
+	FloorMethod
+	AbsMethod
+	KeyValueMethod
+)
+
+var methodNames = [...]string{
+	TypeMethod:     "type",
+	SizeMethod:     "size",
+	DoubleMethod:   "double",
+	CeilingMethod:  "ceiling",
+	FloorMethod:    "floor",
+	AbsMethod:      "abs",
+	KeyValueMethod: "keyvalue",
+}
+
+func (m MethodType) String() string {
+	return methodNames[m]
+}
+
+// Method is an item method accessor such as .size().
+type Method struct {
+	Type MethodType
+}
+
+// OperationType is the type of an Operation.
+type OperationType int
+
+// Operation types. The arithmetic operations produce numbers, and the others
+// are predicates, which produce a boolean.
+const (
+	OpAnd OperationType = iota
+	OpOr
+	OpNot
+	OpIsUnknown
+	OpExists
+	OpEqual
+	OpNotEqual
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+	OpStartsWith
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpPlus
+	OpMinus
+)
+
+var operationNames = [...]string{
+	OpAnd:          "&&",
+	OpOr:           "||",
+	OpNot:          "!",
+	OpIsUnknown:    "is unknown",
+	OpExists:       "exists",
+	OpEqual:        "==",
+	OpNotEqual:     "!=",
+	OpLess:         "<",
+	OpLessEqual:    "<=",
+	OpGreater:      ">",
+	OpGreaterEqual: ">=",
+	OpStartsWith:   "starts with",
+	OpAdd:          "+",
+	OpSub:          "-",
+	OpMul:          "*",
+	OpDiv:          "/",
+	OpMod:          "%",
+	OpPlus:         "+",
+	OpMinus:        "-",
+}
+
+func (o OperationType) String() string {
+	return operationNames[o]
+}
+
+// isUnary returns whether the operation only has a Left operand.
+func (o OperationType) isUnary() bool {
+	switch o {
+	case OpNot, OpIsUnknown, OpExists, OpPlus, OpMinus:
+		return true
+	}
+	return false
+}
+
+// isArithmetic returns whether the operation is one of the arithmetic
+// operators.
+func (o OperationType) isArithmetic() bool {
+	return o >= OpAdd
+}
+
+// priority returns the precedence of the operation, which is used to decide
+// where parentheses are needed when formatting. Operations with a higher
+// priority bind more tightly.
+func (o OperationType) priority() int {
+	switch o {
+	case OpOr:
+		return 0
+	case OpAnd:
+		return 1
+	case OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpStartsWith:
+		return 2
+	case OpAdd, OpSub:
+		return 3
+	case OpMul, OpDiv, OpMod:
+		return 4
+	case OpPlus, OpMinus:
+		return 5
+	}
+	return 6
+}
+
+// Operation is an arithmetic, comparison or logical operation. Unary
+// operations only have a Left operand.
+type Operation struct {
+	Type        OperationType
+	Left, Right Expr
+}
+
+// Regex is the like_regex predicate, which tests whether a string matches a
+// regular expression.
+type Regex struct {
+	Left    Expr
+	Pattern string
+	Flags   string
+
+	re *regexp.Regexp
+}
+
+func (Path) jsonpathExpr()      {}
+func (Root) jsonpathExpr()      {}
+func (Current) jsonpathExpr()   {}
+func (Last) jsonpathExpr()      {}
+func (Variable) jsonpathExpr()  {}
+func (Scalar) jsonpathExpr()    {}
+func (Key) jsonpathExpr()       {}
+func (AnyKey) jsonpathExpr()    {}
+func (AnyArray) jsonpathExpr()  {}
+func (ArrayList) jsonpathExpr() {}
+func (AnyPath) jsonpathExpr()   {}
+func (Filter) jsonpathExpr()    {}
+func (Method) jsonpathExpr()    {}
+func (Operation) jsonpathExpr() {}
+func (Regex) jsonpathExpr()     {}
+
+// IsPredicate returns whether the expression produces a boolean result that
+// can be used as a filter condition.
+func IsPredicate(e Expr) bool {
+	switch t := e.(type) {
+	case Operation:
+		return !t.Type.isArithmetic()
+	case Regex:
+		return true
+	}
+	return false
+}
+
+// priority returns the precedence of the expression. See
+// OperationType.priority.
+func priority(e Expr) int {
+	switch t := e.(type) {
+	case Operation:
+		return t.Type.priority()
+	case Regex:
+		return OpStartsWith.priority()
+	}
+	return 6
+}
+
+// formatExpr writes the canonical text form of e to buf, which is the same
+// form that Postgres uses. If parens is true, operations are wrapped in
+// parentheses.
+func formatExpr(buf *bytes.Buffer, e Expr, parens bool) {
+	switch t := e.(type) {
+	case Path:
+		for i, elem := range t {
+			// A path that begins with an operation, as in ($ + 1).a, must
+			// parenthesize it.
+			formatExpr(buf, elem, i == 0 /* parens */)
+		}
+	case Root:
+		buf.WriteByte('$')
+	case Current:
+		buf.WriteByte('@')
+	case Last:
+		buf.WriteString("last")
+	case Variable:
+		buf.WriteByte('$')
+		formatString(buf, string(t))
+	case Scalar:
+		formatScalar(buf, t.Val)
+	case Key:
+		buf.WriteByte('.')
+		formatString(buf, string(t))
+	case AnyKey:
+		buf.WriteString(".*")
+	case AnyArray:
+		buf.WriteString("[*]")
+	case ArrayList:
+		buf.WriteByte('[')
+		for i, sub := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			formatExpr(buf, sub.From, false /* parens */)
+			if sub.To != nil {
+				buf.WriteString(" to ")
+				formatExpr(buf, sub.To, false /* parens */)
+			}
+		}
+		buf.WriteByte(']')
+	case AnyPath:
+		buf.WriteString(".**")
+		if t.First == t.Last {
+			buf.WriteByte('{')
+			formatLevel(buf, t.First)
+			buf.WriteByte('}')
+		} else if t.First != 0 || t.Last != AnyLevel {
+			buf.WriteByte('{')
+			formatLevel(buf, t.First)
+			buf.WriteString(" to ")
+			formatLevel(buf, t.Last)
+			buf.WriteByte('}')
+		}
+	case Filter:
+		buf.WriteString("?(")
+		formatExpr(buf, t.Cond, false /* parens */)
+		buf.WriteByte(')')
+	case Method:
+		buf.WriteByte('.')
+		buf.WriteString(t.Type.String())
+		buf.WriteString("()")
+	case Regex:
+		if parens {
+			buf.WriteByte('(')
+		}
+		formatExpr(buf, t.Left, priority(t.Left) <= priority(t))
+		buf.WriteString(" like_regex ")
+		formatString(buf, t.Pattern)
+		if t.Flags != "" {
+			buf.WriteString(" flag ")
+			formatString(buf, t.Flags)
+		}
+		if parens {
+			buf.WriteByte(')')
+		}
+	case Operation:
+		formatOperation(buf, t, parens)
+	}
+}
+
+func formatOperation(buf *bytes.Buffer, op Operation, parens bool) {
+	switch op.Type {
+	case OpNot:
+		buf.WriteString("!(")
+		formatExpr(buf, op.Left, false /* parens */)
+		buf.WriteByte(')')
+		return
+	case OpIsUnknown:
+		buf.WriteByte('(')
+		formatExpr(buf, op.Left, false /* parens */)
+		buf.WriteString(") is unknown")
+		return
+	case OpExists:
+		buf.WriteString("exists (")
+		formatExpr(buf, op.Left, false /* parens */)
+		buf.WriteByte(')')
+		return
+	}
+	if parens {
+		buf.WriteByte('(')
+	}
+	if op.Type.isUnary() {
+		buf.WriteString(op.Type.String())
+		formatExpr(buf, op.Left, priority(op.Left) <= op.Type.priority())
+	} else {
+		formatExpr(buf, op.Left, priority(op.Left) <= op.Type.priority())
+		buf.WriteByte(' ')
+		buf.WriteString(op.Type.String())
+		buf.WriteByte(' ')
+		formatExpr(buf, op.Right, priority(op.Right) <= op.Type.priority())
+	}
+	if parens {
+		buf.WriteByte(')')
+	}
+}
+
+func formatLevel(buf *bytes.Buffer, level int) {
+	if level == AnyLevel {
+		buf.WriteString("last")
+	} else {
+		buf.WriteString(strconv.Itoa(level))
+	}
+}
+
+func formatString(buf *bytes.Buffer, s string) {
+	json.FromString(s).Format(buf)
+}
+
+func formatScalar(buf *bytes.Buffer, j json.JSON) {
+	if d, ok := j.AsDecimal(); ok {
+		buf.WriteString(d.Text('f'))
+		return
+	}
+	j.Format(buf)
+}
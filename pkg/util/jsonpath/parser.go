@@ -0,0 +1,845 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// Parse parses the input string using the SQL/JSON path syntax, e.g.
+// strict $.a[*] ? (@.b > 1).
+func Parse(input string) (*Jsonpath, error) {
+	p := parser{input: input}
+	var path Jsonpath
+	if tok := p.peek(); tok.kind == identToken && (tok.text == "strict" || tok.text == "lax") {
+		path.Strict = tok.text == "strict"
+		p.next()
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok.kind != eofToken {
+		return nil, p.syntaxError(tok)
+	}
+	path.Expr = expr
+	return &path, nil
+}
+
+// MustParse is like Parse, but panics on error.
+func MustParse(input string) *Jsonpath {
+	path, err := Parse(input)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	// punctToken is an operator or punctuation character, such as && or [.
+	punctToken
+	numberToken
+	stringToken
+	// identToken is an unquoted key or keyword. Keywords are not reserved, so
+	// they can also be used as keys.
+	identToken
+	// variableToken is a $name or $"name" variable. The text of the token is
+	// the name of the variable.
+	variableToken
+)
+
+type token struct {
+	kind tokenKind
+	// text is the text of the token. For strings and variables, it has quotes
+	// and escapes removed.
+	text string
+	// start and end are the positions of the token in the input.
+	start, end int
+}
+
+type parser struct {
+	input string
+	pos   int
+	// peeked is the next token, if it has already been scanned.
+	peeked *token
+	// filterDepth is the number of filters that enclose the current
+	// position, which is used to reject @ outside of filters.
+	filterDepth int
+	// subscriptDepth is the number of array subscripts that enclose the
+	// current position, which is used to reject last outside of subscripts.
+	subscriptDepth int
+}
+
+func (p *parser) syntaxError(tok token) error {
+	if tok.kind == eofToken {
+		return pgerror.New(pgcode.Syntax, "syntax error at end of jsonpath input")
+	}
+	return pgerror.Newf(pgcode.Syntax,
+		"syntax error at or near %q of jsonpath input", p.input[tok.start:tok.end])
+}
+
+func (p *parser) peek() token {
+	if p.peeked == nil {
+		tok := p.scan()
+		p.peeked = &tok
+	}
+	return *p.peeked
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	p.peeked = nil
+	return tok
+}
+
+// accept consumes the next token and returns true if it is the given
+// punctuation.
+func (p *parser) accept(punct string) bool {
+	if tok := p.peek(); tok.kind == punctToken && tok.text == punct {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// acceptKeyword consumes the next token and returns true if it is the given
+// keyword.
+func (p *parser) acceptKeyword(keyword string) bool {
+	if tok := p.peek(); tok.kind == identToken && tok.text == keyword {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	if tok := p.next(); tok.kind != punctToken || tok.text != punct {
+		return p.syntaxError(tok)
+	}
+	return nil
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if tok := p.next(); tok.kind != identToken || tok.text != keyword {
+		return p.syntaxError(tok)
+	}
+	return nil
+}
+
+// parseOr parses an expression of the form a || b || ...
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if !p.accept("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if !IsPredicate(left) || !IsPredicate(right) {
+			return nil, p.syntaxError(tok)
+		}
+		left = Operation{Type: OpOr, Left: left, Right: right}
+	}
+}
+
+// parseAnd parses an expression of the form a && b && ...
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if !p.accept("&&") {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if !IsPredicate(left) || !IsPredicate(right) {
+			return nil, p.syntaxError(tok)
+		}
+		left = Operation{Type: OpAnd, Left: left, Right: right}
+	}
+}
+
+// parseNot parses an expression of the form !(predicate) or
+// !exists(expr), falling back to a comparison.
+func (p *parser) parseNot() (Expr, error) {
+	if !p.accept("!") {
+		return p.parseComparison()
+	}
+	var operand Expr
+	tok := p.peek()
+	if tok.kind == identToken && tok.text == "exists" {
+		var err error
+		if operand, err = p.parsePrimary(); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var err error
+		if operand, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if !IsPredicate(operand) {
+			return nil, p.syntaxError(tok)
+		}
+	}
+	return Operation{Type: OpNot, Left: operand}, nil
+}
+
+var comparisonOps = map[string]OperationType{
+	"==": OpEqual,
+	"!=": OpNotEqual,
+	"<>": OpNotEqual,
+	"<":  OpLess,
+	"<=": OpLessEqual,
+	">":  OpGreater,
+	">=": OpGreaterEqual,
+}
+
+// parseComparison parses a comparison, a starts with predicate or a
+// like_regex predicate, falling back to an arithmetic expression.
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	cmpOp, isCmp := comparisonOps[tok.text]
+	switch {
+	case tok.kind == punctToken && isCmp:
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if IsPredicate(left) || IsPredicate(right) {
+			return nil, p.syntaxError(tok)
+		}
+		return Operation{Type: cmpOp, Left: left, Right: right}, nil
+
+	case tok.kind == identToken && tok.text == "starts":
+		p.next()
+		if err := p.expectKeyword("with"); err != nil {
+			return nil, err
+		}
+		var right Expr
+		switch tok := p.next(); tok.kind {
+		case stringToken:
+			right = Scalar{Val: json.FromString(tok.text)}
+		case variableToken:
+			right = Variable(tok.text)
+		default:
+			return nil, p.syntaxError(tok)
+		}
+		if IsPredicate(left) {
+			return nil, p.syntaxError(tok)
+		}
+		return Operation{Type: OpStartsWith, Left: left, Right: right}, nil
+
+	case tok.kind == identToken && tok.text == "like_regex":
+		p.next()
+		pattern := p.next()
+		if pattern.kind != stringToken {
+			return nil, p.syntaxError(pattern)
+		}
+		var flags string
+		if p.acceptKeyword("flag") {
+			flagsTok := p.next()
+			if flagsTok.kind != stringToken {
+				return nil, p.syntaxError(flagsTok)
+			}
+			flags = flagsTok.text
+		}
+		if IsPredicate(left) {
+			return nil, p.syntaxError(tok)
+		}
+		re, err := compileRegex(pattern.text, flags)
+		if err != nil {
+			return nil, err
+		}
+		return Regex{Left: left, Pattern: pattern.text, Flags: flags, re: re}, nil
+	}
+	return left, nil
+}
+
+// compileRegex compiles the pattern of a like_regex predicate with the given
+// XQuery flags.
+func compileRegex(pattern, flags string) (*regexp.Regexp, error) {
+	var prefix strings.Builder
+	for _, flag := range flags {
+		switch flag {
+		case 'i', 'm', 's':
+			prefix.WriteRune(flag)
+		case 'q':
+			pattern = regexp.QuoteMeta(pattern)
+		case 'x':
+			return nil, unimplemented.NewWithIssueDetail(22513, "like_regex x flag",
+				`XQuery "x" flag (expanded regular expressions) is not implemented`)
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"invalid input syntax for type jsonpath: unrecognized flag character %q in LIKE_REGEX predicate",
+				flag)
+		}
+	}
+	if prefix.Len() > 0 {
+		pattern = "(?" + prefix.String() + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidRegularExpression, "invalid regular expression")
+	}
+	return re, nil
+}
+
+// parseAdditive parses an expression of the form a + b - ...
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		var op OperationType
+		switch {
+		case p.accept("+"):
+			op = OpAdd
+		case p.accept("-"):
+			op = OpSub
+		default:
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if IsPredicate(left) || IsPredicate(right) {
+			return nil, p.syntaxError(tok)
+		}
+		left = Operation{Type: op, Left: left, Right: right}
+	}
+}
+
+// parseMultiplicative parses an expression of the form a * b / c % ...
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		var op OperationType
+		switch {
+		case p.accept("*"):
+			op = OpMul
+		case p.accept("/"):
+			op = OpDiv
+		case p.accept("%"):
+			op = OpMod
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if IsPredicate(left) || IsPredicate(right) {
+			return nil, p.syntaxError(tok)
+		}
+		left = Operation{Type: op, Left: left, Right: right}
+	}
+}
+
+// parseUnary parses an expression with an optional unary + or -. Signs
+// applied to numeric literals are folded into the literal.
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.peek()
+	var op OperationType
+	switch {
+	case p.accept("+"):
+		op = OpPlus
+	case p.accept("-"):
+		op = OpMinus
+	default:
+		return p.parseAccessorExpr()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if IsPredicate(operand) {
+		return nil, p.syntaxError(tok)
+	}
+	if s, ok := operand.(Scalar); ok {
+		if d, ok := s.Val.AsDecimal(); ok {
+			if op == OpMinus {
+				var neg apd.Decimal
+				neg.Neg(d)
+				return Scalar{Val: json.FromDecimal(neg)}, nil
+			}
+			return operand, nil
+		}
+	}
+	return Operation{Type: op, Left: operand}, nil
+}
+
+// parseAccessorExpr parses a primary followed by any number of accessors.
+func (p *parser) parseAccessorExpr() (Expr, error) {
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	var path Path
+	if inner, ok := primary.(Path); ok {
+		// Flatten parenthesized paths, as in ($.a).b.
+		path = append(path, inner...)
+	} else {
+		path = append(path, primary)
+	}
+	for {
+		var accessor Expr
+		var err error
+		switch tok := p.peek(); {
+		case tok.kind == punctToken && tok.text == ".":
+			p.next()
+			accessor, err = p.parseDotAccessor()
+		case tok.kind == punctToken && tok.text == "[":
+			p.next()
+			accessor, err = p.parseArrayAccessor()
+		case tok.kind == punctToken && tok.text == "?":
+			p.next()
+			accessor, err = p.parseFilter()
+		default:
+			if len(path) == 1 {
+				return path[0], nil
+			}
+			return path, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, accessor)
+	}
+}
+
+var methodTypes = map[string]MethodType{
+	"type":     TypeMethod,
+	"size":     SizeMethod,
+	"double":   DoubleMethod,
+	"ceiling":  CeilingMethod,
+	"floor":    FloorMethod,
+	"abs":      AbsMethod,
+	"keyvalue": KeyValueMethod,
+}
+
+// parseDotAccessor parses the accessor following a ".", which is either a
+// key, .*, .** or an item method.
+func (p *parser) parseDotAccessor() (Expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case stringToken:
+		return Key(tok.text), nil
+	case identToken:
+		if !p.accept("(") {
+			return Key(tok.text), nil
+		}
+		if tok.text == "datetime" {
+			return nil, unimplemented.NewWithIssue(22513, "jsonpath .datetime() method")
+		}
+		method, ok := methodTypes[tok.text]
+		if !ok {
+			return nil, p.syntaxError(tok)
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return Method{Type: method}, nil
+	case punctToken:
+		if tok.text != "*" {
+			break
+		}
+		if next := p.peek(); next.kind != punctToken || next.text != "*" || next.start != tok.end {
+			return AnyKey{}, nil
+		}
+		p.next()
+		anyPath := AnyPath{First: 0, Last: AnyLevel}
+		if !p.accept("{") {
+			return anyPath, nil
+		}
+		var err error
+		if anyPath.First, err = p.parseLevel(); err != nil {
+			return nil, err
+		}
+		anyPath.Last = anyPath.First
+		if p.acceptKeyword("to") {
+			if anyPath.Last, err = p.parseLevel(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		return anyPath, nil
+	}
+	return nil, p.syntaxError(tok)
+}
+
+// parseLevel parses a nesting level of a .** accessor, which is either a
+// non-negative integer or last.
+func (p *parser) parseLevel() (int, error) {
+	tok := p.next()
+	if tok.kind == identToken && tok.text == "last" {
+		return AnyLevel, nil
+	}
+	if tok.kind == numberToken {
+		if level, err := strconv.ParseInt(tok.text, 10, 32); err == nil {
+			return int(level), nil
+		}
+	}
+	return 0, p.syntaxError(tok)
+}
+
+// parseArrayAccessor parses the accessor following a "[", which is either
+// [*] or a list of subscripts.
+func (p *parser) parseArrayAccessor() (Expr, error) {
+	if p.accept("*") {
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return AnyArray{}, nil
+	}
+	p.subscriptDepth++
+	defer func() { p.subscriptDepth-- }()
+	var list ArrayList
+	for {
+		var sub ArraySubscript
+		var err error
+		if sub.From, err = p.parseSubscriptExpr(); err != nil {
+			return nil, err
+		}
+		if p.acceptKeyword("to") {
+			if sub.To, err = p.parseSubscriptExpr(); err != nil {
+				return nil, err
+			}
+		}
+		list = append(list, sub)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (p *parser) parseSubscriptExpr() (Expr, error) {
+	tok := p.peek()
+	expr, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if IsPredicate(expr) {
+		return nil, p.syntaxError(tok)
+	}
+	return expr, nil
+}
+
+// parseFilter parses the accessor following a "?", which is a parenthesized
+// predicate.
+func (p *parser) parseFilter() (Expr, error) {
+	tok := p.peek()
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	p.filterDepth++
+	cond, err := p.parseOr()
+	p.filterDepth--
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if !IsPredicate(cond) {
+		return nil, p.syntaxError(tok)
+	}
+	return Filter{Cond: cond}, nil
+}
+
+// parsePrimary parses a literal, $, @, last, a variable, exists(expr) or a
+// parenthesized expression.
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case numberToken:
+		var d apd.Decimal
+		if _, _, err := d.SetString(tok.text); err != nil {
+			return nil, p.syntaxError(tok)
+		}
+		return Scalar{Val: json.FromDecimal(d)}, nil
+	case stringToken:
+		return Scalar{Val: json.FromString(tok.text)}, nil
+	case variableToken:
+		return Variable(tok.text), nil
+	case identToken:
+		switch tok.text {
+		case "true":
+			return Scalar{Val: json.TrueJSONValue}, nil
+		case "false":
+			return Scalar{Val: json.FalseJSONValue}, nil
+		case "null":
+			return Scalar{Val: json.NullJSONValue}, nil
+		case "last":
+			if p.subscriptDepth == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "LAST is allowed only in array subscripts")
+			}
+			return Last{}, nil
+		case "exists":
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			inner := p.peek()
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if IsPredicate(expr) {
+				return nil, p.syntaxError(inner)
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return Operation{Type: OpExists, Left: expr}, nil
+		}
+	case punctToken:
+		switch tok.text {
+		case "$":
+			return Root{}, nil
+		case "@":
+			if p.filterDepth == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "@ is not allowed in root expressions")
+			}
+			return Current{}, nil
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if p.acceptKeyword("is") {
+				if err := p.expectKeyword("unknown"); err != nil {
+					return nil, err
+				}
+				if !IsPredicate(expr) {
+					return nil, p.syntaxError(tok)
+				}
+				return Operation{Type: OpIsUnknown, Left: expr}, nil
+			}
+			return expr, nil
+		}
+	}
+	return nil, p.syntaxError(tok)
+}
+
+// isSpecialChar returns whether the character cannot be part of an unquoted
+// key or variable name.
+func isSpecialChar(ch byte) bool {
+	return strings.IndexByte("?%$.[]{}()|&!=<>@#,*:-+/\\\" \t\n\r\f\v", ch) >= 0
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// scan returns the next token of the input.
+func (p *parser) scan() token {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\n\r\f\v", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		return token{kind: eofToken, start: start, end: start}
+	}
+	ch := p.input[p.pos]
+	switch {
+	case isDigit(ch):
+		p.scanNumber()
+		return token{kind: numberToken, text: p.input[start:p.pos], start: start, end: p.pos}
+	case ch == '"':
+		s, ok := p.scanString()
+		if !ok {
+			return p.errorToken(start)
+		}
+		return token{kind: stringToken, text: s, start: start, end: p.pos}
+	case ch == '$':
+		p.pos++
+		if p.pos < len(p.input) && p.input[p.pos] == '"' {
+			s, ok := p.scanString()
+			if !ok {
+				return p.errorToken(start)
+			}
+			return token{kind: variableToken, text: s, start: start, end: p.pos}
+		}
+		if p.pos < len(p.input) && !isSpecialChar(p.input[p.pos]) {
+			nameStart := p.pos
+			p.scanIdent()
+			return token{kind: variableToken, text: p.input[nameStart:p.pos], start: start, end: p.pos}
+		}
+		return token{kind: punctToken, text: "$", start: start, end: p.pos}
+	case !isSpecialChar(ch):
+		p.scanIdent()
+		return token{kind: identToken, text: p.input[start:p.pos], start: start, end: p.pos}
+	}
+	for _, op := range [...]string{"==", "!=", "<>", "<=", ">=", "&&", "||"} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			p.pos += len(op)
+			return token{kind: punctToken, text: op, start: start, end: p.pos}
+		}
+	}
+	if strings.IndexByte("@.[](){},?*+-/%!<>", ch) >= 0 {
+		p.pos++
+		return token{kind: punctToken, text: p.input[start:p.pos], start: start, end: p.pos}
+	}
+	return p.errorToken(start)
+}
+
+// errorToken returns a token spanning the rest of the input, which is used
+// to report invalid input.
+func (p *parser) errorToken(start int) token {
+	p.pos = len(p.input)
+	return token{kind: punctToken, start: start, end: p.pos}
+}
+
+func (p *parser) scanIdent() {
+	for p.pos < len(p.input) && !isSpecialChar(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// scanNumber scans an integer or decimal number, with an optional exponent.
+// A "." that is not followed by a digit is not part of the number, so that
+// 1.type() is scanned as 1, ".", "type", "(" and ")".
+func (p *parser) scanNumber() {
+	scanDigits := func() {
+		for p.pos < len(p.input) && isDigit(p.input[p.pos]) {
+			p.pos++
+		}
+	}
+	scanDigits()
+	if p.pos+1 < len(p.input) && p.input[p.pos] == '.' && isDigit(p.input[p.pos+1]) {
+		p.pos++
+		scanDigits()
+	}
+	if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		i := p.pos + 1
+		if i < len(p.input) && (p.input[i] == '+' || p.input[i] == '-') {
+			i++
+		}
+		if i < len(p.input) && isDigit(p.input[i]) {
+			p.pos = i
+			scanDigits()
+		}
+	}
+}
+
+// scanString scans a double-quoted string, returning its unescaped value and
+// false if the string is not terminated or contains an invalid escape.
+func (p *parser) scanString() (string, bool) {
+	var buf strings.Builder
+	p.pos++
+	for p.pos < len(p.input) {
+		ch := p.input[p.pos]
+		p.pos++
+		switch ch {
+		case '"':
+			return buf.String(), true
+		case '\\':
+			if p.pos >= len(p.input) {
+				return "", false
+			}
+			esc := p.input[p.pos]
+			p.pos++
+			switch esc {
+			case 'b':
+				buf.WriteByte('\b')
+			case 'f':
+				buf.WriteByte('\f')
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case 'v':
+				buf.WriteByte('\v')
+			case 'x':
+				r, ok := p.scanHex(2)
+				if !ok {
+					return "", false
+				}
+				buf.WriteRune(r)
+			case 'u':
+				r, ok := p.scanHex(4)
+				if !ok {
+					return "", false
+				}
+				buf.WriteRune(r)
+			default:
+				buf.WriteByte(esc)
+			}
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	return "", false
+}
+
+// scanHex scans an escaped code point of n hex digits.
+func (p *parser) scanHex(n int) (rune, bool) {
+	if p.pos+n > len(p.input) {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(p.input[p.pos:p.pos+n], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(v)) || v == 0 {
+		return 0, false
+	}
+	p.pos += n
+	return rune(v), true
+}
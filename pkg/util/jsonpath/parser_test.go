@@ -0,0 +1,108 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`$`, `$`},
+		{`(($))`, `$`},
+		{`strict $`, `strict $`},
+		{`lax $`, `$`},
+		{`$.a`, `$."a"`},
+		{`$."a b".c`, `$."a b"."c"`},
+		{`$.a[*]`, `$."a"[*]`},
+		{`$.a[1,2, 3 to 16]`, `$."a"[1,2,3 to 16]`},
+		{`$.a[last - 1 to last]`, `$."a"[last - 1 to last]`},
+		{`$.*`, `$.*`},
+		{`$.**`, `$.**`},
+		{`$.**{2}`, `$.**{2}`},
+		{`$.**{1 to last}`, `$.**{1 to last}`},
+		{`$a`, `$"a"`},
+		{`$.a.type()`, `$."a".type()`},
+		{`1.type()`, `1.type()`},
+		{`1.2.type()`, `1.2.type()`},
+		{`"aaa".type()`, `"aaa".type()`},
+		{`$.size`, `$."size"`},
+		{`$.last`, `$."last"`},
+		{`$.g ? (@.a == 1)`, `$."g"?(@."a" == 1)`},
+		{`$.g ? (@.a == 1 || @.a == 4 && @.b == 7)`, `$."g"?(@."a" == 1 || @."a" == 4 && @."b" == 7)`},
+		{`$.g ? ((@.a == 1 || @.a == 4) && @.b == 7)`, `$."g"?((@."a" == 1 || @."a" == 4) && @."b" == 7)`},
+		{`$.g ? (@.a == 1 || !(@.x >= 123 || @.a == 4) && @.b == 7)`,
+			`$."g"?(@."a" == 1 || !(@."x" >= 123 || @."a" == 4) && @."b" == 7)`},
+		{`$.g ? ((@.x >= 123 || @.a == 4) is unknown)`, `$."g"?((@."x" >= 123 || @."a" == 4) is unknown)`},
+		{`$.g ? (exists (@.x))`, `$."g"?(exists (@."x"))`},
+		{`$ ? (@ like_regex "pattern" flag "i")`, `$?(@ like_regex "pattern" flag "i")`},
+		{`$ ? (@ starts with "abc")`, `$?(@ starts with "abc")`},
+		{`$ ? (@ starts with $var)`, `$?(@ starts with $"var")`},
+		{`$ ? (@ <> 1)`, `$?(@ != 1)`},
+		{`$.a == 1`, `($."a" == 1)`},
+		{`$.a + 1`, `($."a" + 1)`},
+		{`$.a/+-1`, `($."a" / -1)`},
+		{`1 * 2 + 4 % -3 != false`, `(1 * 2 + 4 % -3 != false)`},
+		{`(1 + 2) * 3`, `((1 + 2) * 3)`},
+		{`-$.a`, `(-$."a")`},
+		{`-($.a + 1)`, `(-($."a" + 1))`},
+		{`($ + 1).a`, `($ + 1)."a"`},
+		{`$.a[$.a.size() - 3]`, `$."a"[$."a".size() - 3]`},
+		{`"A\n\"b"`, `"A\n\"b"`},
+		{`1e3`, `1000`},
+		{`null`, `null`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			p, err := Parse(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p.String())
+
+			// The output must parse to the same path.
+			p2, err := Parse(p.String())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p2.String())
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{``, `syntax error at end of jsonpath input`},
+		{`strict`, `syntax error at end of jsonpath input`},
+		{`$.`, `syntax error at end of jsonpath input`},
+		{`$.a[`, `syntax error at end of jsonpath input`},
+		{`$ ? (@.a)`, `syntax error at or near "(" of jsonpath input`},
+		{`$ && $`, `syntax error at or near "&&" of jsonpath input`},
+		{`($ == 1) + 1`, `syntax error at or near "+" of jsonpath input`},
+		{`$ = 1`, `syntax error at or near "= 1" of jsonpath input`},
+		{`$.a.foo()`, `syntax error at or near "foo" of jsonpath input`},
+		{`"abc`, `syntax error at or near "\"abc" of jsonpath input`},
+		{`@`, `@ is not allowed in root expressions`},
+		{`last`, `LAST is allowed only in array subscripts`},
+		{`$ ? (@ like_regex "a" flag "z")`, `unrecognized flag character 'z' in LIKE_REGEX predicate`},
+		{`$ ? (@ like_regex "(")`, `invalid regular expression`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package lsn contains logic for handling Postgres log sequence numbers, which
+// identify positions in the stream of changes sent to replication clients.
+package lsn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// LSN is a Postgres log sequence number.
+type LSN uint64
+
+// String formats the LSN in the same way as Postgres: the high and low 32 bits
+// in hexadecimal, separated by a slash.
+func (lsn LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(lsn>>32), uint32(lsn))
+}
+
+// ParseLSN parses an LSN in the format produced by LSN.String.
+func ParseLSN(str string) (LSN, error) {
+	parts := strings.Split(str, "/")
+	if len(parts) != 2 {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation, "invalid LSN: %q", str)
+	}
+	hi, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation, "invalid LSN: %q", str)
+	}
+	lo, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation, "invalid LSN: %q", str)
+	}
+	return LSN(hi<<32 | lo), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package lsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLSN(t *testing.T) {
+	testCases := []struct {
+		lsn LSN
+		str string
+	}{
+		{0, "0/0"},
+		{0x16B3748, "0/16B3748"},
+		{0x1_0000_0000, "1/0"},
+		{0x16F2AD5C_9B1C8E40, "16F2AD5C/9B1C8E40"},
+		{0xFFFFFFFF_FFFFFFFF, "FFFFFFFF/FFFFFFFF"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.str, func(t *testing.T) {
+			require.Equal(t, tc.str, tc.lsn.String())
+			res, err := ParseLSN(tc.str)
+			require.NoError(t, err)
+			require.Equal(t, tc.lsn, res)
+		})
+	}
+
+	for _, str := range []string{"", "0", "0/", "/0", "0/0/0", "G/0", "100000000/0", "-1/0"} {
+		t.Run(str, func(t *testing.T) {
+			_, err := ParseLSN(str)
+			require.Error(t, err)
+		})
+	}
+
+	// Lowercase hexadecimal digits are accepted.
+	res, err := ParseLSN("a/bc")
+	require.NoError(t, err)
+	require.Equal(t, LSN(0xA_0000_00BC), res)
+}
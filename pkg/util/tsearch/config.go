@@ -0,0 +1,191 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultConfig is the default text search configuration, which is used when
+// no configuration is specified and the default_text_search_config session
+// variable has not been changed.
+const DefaultConfig = "english"
+
+// config is a text search configuration, which determines how the words of a
+// document are converted into lexemes.
+type config struct {
+	// stopWords are words that are too common to be useful for searching, and
+	// are discarded.
+	stopWords map[string]struct{}
+	// stem reduces a word to its root form. It may be nil.
+	stem func(string) string
+}
+
+var configs = map[string]*config{
+	"simple": {},
+	"english": {
+		stopWords: englishStopWords,
+		stem:      stemEnglish,
+	},
+}
+
+// ValidConfig returns an error if there is no text search configuration with
+// the given name.
+func ValidConfig(name string) error {
+	_, err := getConfig(name)
+	return err
+}
+
+// getConfig returns the text search configuration with the given name. The
+// name may be qualified with the pg_catalog schema, in which all of the
+// configurations live.
+func getConfig(name string) (*config, error) {
+	if c, ok := configs[strings.TrimPrefix(name, "pg_catalog.")]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// tokenize splits the input into words, which are runs of letters and digits,
+// and lowercases them.
+func tokenize(input string) []string {
+	return strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// lexeme returns the lexeme for the given word, or false if the word is a stop
+// word. Words containing digits are not stemmed.
+func (c *config) lexeme(word string) (string, bool) {
+	if _, ok := c.stopWords[word]; ok {
+		return "", false
+	}
+	if c.stem == nil || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return word, true
+	}
+	return c.stem(word), true
+}
+
+// ToTSVector parses the given document into a TSVector using the given text
+// search configuration. Each word is assigned a position, starting at 1; stop
+// words are not included in the vector, but still occupy positions.
+func ToTSVector(configName string, document string) (TSVector, error) {
+	c, err := getConfig(configName)
+	if err != nil {
+		return nil, err
+	}
+	var vec TSVector
+	for i, word := range tokenize(document) {
+		lexeme, ok := c.lexeme(word)
+		if !ok || len(lexeme) > maxLexemeLen {
+			continue
+		}
+		pos := i + 1
+		if pos > maxPosition {
+			pos = maxPosition
+		}
+		vec = append(vec, tsTerm{
+			lexeme:    lexeme,
+			positions: []tsPosition{{position: uint16(pos)}},
+		})
+	}
+	return normalizeTSVector(vec), nil
+}
+
+// ToTSQuery parses the given query text using the tsquery syntax, and
+// normalizes each operand using the given text search configuration. Stop
+// words are removed from the query. An operand that contains multiple words
+// matches a phrase of those words.
+func ToTSQuery(configName string, input string) (TSQuery, error) {
+	c, err := getConfig(configName)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	return parseTSQuery(input, func(operand *tsNode) (*tsNode, error) {
+		var res *tsNode
+		for i, word := range tokenize(operand.lexeme) {
+			var n *tsNode
+			if lexeme, ok := c.lexeme(word); ok {
+				n = &tsNode{lexeme: lexeme, weightMask: operand.weightMask, prefix: operand.prefix}
+			}
+			if i == 0 {
+				res = n
+			} else {
+				res = &tsNode{op: followedBy, followedN: 1, l: res, r: n}
+			}
+		}
+		return res, nil
+	})
+}
+
+// PlainToTSQuery converts the given text into a query that matches documents
+// containing all of the words in the text, normalized using the given text
+// search configuration.
+func PlainToTSQuery(configName string, input string) (TSQuery, error) {
+	return wordsToTSQuery(configName, input, and)
+}
+
+// PhraseToTSQuery converts the given text into a query that matches documents
+// containing the words in the text in the same order, normalized using the
+// given text search configuration. Stop words are skipped, but are accounted
+// for in the distance between the remaining words.
+func PhraseToTSQuery(configName string, input string) (TSQuery, error) {
+	return wordsToTSQuery(configName, input, followedBy)
+}
+
+func wordsToTSQuery(configName string, input string, op tsOperator) (TSQuery, error) {
+	c, err := getConfig(configName)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	var root *tsNode
+	for i, word := range tokenize(input) {
+		var n *tsNode
+		if lexeme, ok := c.lexeme(word); ok {
+			n = &tsNode{lexeme: lexeme}
+		}
+		if i == 0 {
+			root = n
+			continue
+		}
+		root = &tsNode{op: op, l: root, r: n}
+		if op == followedBy {
+			root.followedN = 1
+		}
+	}
+	return TSQuery{root: cleanStopWords(root)}, nil
+}
+
+// englishStopWords is the list of English stop words used by Postgres.
+var englishStopWords = makeStopWords(`
+i me my myself we our ours ourselves you your yours yourself yourselves he
+him his himself she her hers herself it its itself they them their theirs
+themselves what which who whom this that these those am is are was were be
+been being have has had having do does did doing a an the and but if or
+because as until while of at by for with about against between into through
+during before after above below to from up down in out on off over under
+again further then once here there when where why how all any both each few
+more most other some such no nor not only own same so than too very s t can
+will just don should now
+`)
+
+func makeStopWords(words string) map[string]struct{} {
+	res := make(map[string]struct{})
+	for _, w := range strings.Fields(words) {
+		res[w] = struct{}{}
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTSVector(t *testing.T) {
+	for _, tc := range []struct {
+		config   string
+		input    string
+		expected string
+	}{
+		{"english", ``, ``},
+		{
+			"english",
+			`The quick brown foxes jumped over the lazy dogs`,
+			`'brown':3 'dog':9 'fox':4 'jump':5 'lazi':8 'quick':2`,
+		},
+		{
+			"pg_catalog.english",
+			`a fat  cat sat on a mat - it ate a fat rats`,
+			`'ate':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4`,
+		},
+		{
+			"simple",
+			`The quick brown foxes`,
+			`'brown':3 'foxes':4 'quick':2 'the':1`,
+		},
+		{"english", `Version 2 of the 3rd release`, `'2':2 '3rd':5 'releas':6 'version':1`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			vec, err := ToTSVector(tc.config, tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vec.String())
+		})
+	}
+
+	_, err := ToTSVector("french", "le chat")
+	assert.EqualError(t, err, `text search configuration "french" does not exist`)
+}
+
+func TestToTSQuery(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`The & Fat & Rats`, `'fat' & 'rat'`},
+		{`Fat:ab & Cats`, `'fat':AB & 'cat'`},
+		{`supernovae:*`, `'supernova':*`},
+		{`'supernovae stars' & !crab`, `'supernova' <-> 'star' & !'crab'`},
+		{`fat <-> the <-> rat`, `'fat' <2> 'rat'`},
+		{`fat <-> (the | a) <-> rat`, `'fat' <2> 'rat'`},
+		{`the <-> fat <-> rat`, `'fat' <-> 'rat'`},
+		{`!the & cat`, `'cat'`},
+		{`the | a`, ``},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ToTSQuery("english", tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q.String())
+		})
+	}
+}
+
+func TestPlainAndPhraseToTSQuery(t *testing.T) {
+	for _, tc := range []struct {
+		input          string
+		expectedPlain  string
+		expectedPhrase string
+	}{
+		{``, ``, ``},
+		{`The Fat Rats`, `'fat' & 'rat'`, `'fat' <-> 'rat'`},
+		{`The Cat and Rats`, `'cat' & 'rat'`, `'cat' <2> 'rat'`},
+		{`The Fat & Rats:C`, `'fat' & 'rat' & 'c'`, `'fat' <-> 'rat' <-> 'c'`},
+		{`fat rats are fat`, `'fat' & 'rat' & 'fat'`, `'fat' <-> 'rat' <2> 'fat'`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := PlainToTSQuery("english", tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPlain, q.String())
+
+			q, err = PhraseToTSQuery("english", tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPhrase, q.String())
+		})
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/keysbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// The binary formats of TSVector and TSQuery are the ones used by Postgres
+// when sending the types over the wire, and are used for both pgwire and the
+// value encoding of the types.
+
+// A position is encoded as a uint16 with the weight in the top 2 bits.
+const positionWeightShift = 14
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// EncodeTSVector appends the binary encoding of the vector to the given
+// buffer: the number of lexemes, followed by each null-terminated lexeme, its
+// number of positions and the positions themselves.
+func EncodeTSVector(appendTo []byte, v TSVector) []byte {
+	appendTo = appendUint32(appendTo, uint32(len(v)))
+	for _, term := range v {
+		appendTo = append(appendTo, term.lexeme...)
+		appendTo = append(appendTo, 0)
+		appendTo = appendUint16(appendTo, uint16(len(term.positions)))
+		for _, p := range term.positions {
+			appendTo = appendUint16(
+				appendTo, uint16(p.weight)<<positionWeightShift|p.position,
+			)
+		}
+	}
+	return appendTo
+}
+
+// DecodeTSVector decodes a vector from its binary encoding.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	if len(b) < 4 {
+		return nil, errors.New("insufficient bytes to decode tsvector")
+	}
+	n := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	vec := make(TSVector, 0, n)
+	for i := uint32(0); i < n; i++ {
+		end := bytes.IndexByte(b, 0)
+		if end < 0 || len(b) < end+3 {
+			return nil, errors.New("insufficient bytes to decode tsvector")
+		}
+		term := tsTerm{lexeme: string(b[:end])}
+		numPositions := int(binary.BigEndian.Uint16(b[end+1:]))
+		b = b[end+3:]
+		if len(b) < numPositions*2 {
+			return nil, errors.New("insufficient bytes to decode tsvector")
+		}
+		if numPositions > 0 {
+			term.positions = make([]tsPosition, numPositions)
+			for j := range term.positions {
+				p := binary.BigEndian.Uint16(b[j*2:])
+				term.positions[j] = tsPosition{
+					position: p & maxPosition,
+					weight:   tsWeight(p >> positionWeightShift),
+				}
+			}
+			b = b[numPositions*2:]
+		}
+		vec = append(vec, term)
+	}
+	if len(b) != 0 {
+		return nil, errors.New("unexpected trailing bytes when decoding tsvector")
+	}
+	return normalizeTSVector(vec), nil
+}
+
+// The item types and operator codes of the binary encoding of a TSQuery.
+const (
+	tsQueryItemValue    = 1
+	tsQueryItemOperator = 2
+
+	tsQueryOpNot        = 1
+	tsQueryOpAnd        = 2
+	tsQueryOpOr         = 3
+	tsQueryOpFollowedBy = 4
+)
+
+// EncodeTSQuery appends the binary encoding of the query to the given buffer:
+// the number of nodes, followed by the nodes in prefix order. Like in
+// Postgres, the right operand of a binary operator precedes its left operand.
+func EncodeTSQuery(appendTo []byte, q TSQuery) []byte {
+	appendTo = appendUint32(appendTo, uint32(q.NumNodes()))
+	return q.root.encode(appendTo)
+}
+
+func (n *tsNode) encode(appendTo []byte) []byte {
+	if n == nil {
+		return appendTo
+	}
+	switch n.op {
+	case invalid:
+		var prefix byte
+		if n.prefix {
+			prefix = 1
+		}
+		appendTo = append(appendTo, tsQueryItemValue, n.weightMask, prefix)
+		appendTo = append(appendTo, n.lexeme...)
+		return append(appendTo, 0)
+	case not:
+		appendTo = append(appendTo, tsQueryItemOperator, tsQueryOpNot)
+		return n.l.encode(appendTo)
+	case and:
+		appendTo = append(appendTo, tsQueryItemOperator, tsQueryOpAnd)
+	case or:
+		appendTo = append(appendTo, tsQueryItemOperator, tsQueryOpOr)
+	case followedBy:
+		appendTo = append(appendTo, tsQueryItemOperator, tsQueryOpFollowedBy)
+		appendTo = appendUint16(appendTo, n.followedN)
+	}
+	appendTo = n.r.encode(appendTo)
+	return n.l.encode(appendTo)
+}
+
+// DecodeTSQuery decodes a query from its binary encoding.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	if len(b) < 4 {
+		return TSQuery{}, errors.New("insufficient bytes to decode tsquery")
+	}
+	n := binary.BigEndian.Uint32(b)
+	if n == 0 {
+		return TSQuery{}, nil
+	}
+	d := tsQueryDecoder{b: b[4:]}
+	root, err := d.decode()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	if len(d.b) != 0 || d.n != n {
+		return TSQuery{}, errors.New("malformed tsquery encoding")
+	}
+	return TSQuery{root: root}, nil
+}
+
+type tsQueryDecoder struct {
+	b []byte
+	// n is the number of nodes decoded so far.
+	n uint32
+}
+
+func (d *tsQueryDecoder) decode() (*tsNode, error) {
+	if len(d.b) < 2 {
+		return nil, errors.New("insufficient bytes to decode tsquery")
+	}
+	d.n++
+	typ, code := d.b[0], d.b[1]
+	d.b = d.b[2:]
+	switch typ {
+	case tsQueryItemValue:
+		if len(d.b) < 1 {
+			return nil, errors.New("insufficient bytes to decode tsquery")
+		}
+		n := &tsNode{weightMask: code, prefix: d.b[0] != 0}
+		d.b = d.b[1:]
+		end := bytes.IndexByte(d.b, 0)
+		if end < 0 {
+			return nil, errors.New("malformed tsquery operand")
+		}
+		n.lexeme = string(d.b[:end])
+		d.b = d.b[end+1:]
+		return n, nil
+	case tsQueryItemOperator:
+		n := &tsNode{}
+		switch code {
+		case tsQueryOpNot:
+			n.op = not
+			var err error
+			n.l, err = d.decode()
+			return n, err
+		case tsQueryOpAnd:
+			n.op = and
+		case tsQueryOpOr:
+			n.op = or
+		case tsQueryOpFollowedBy:
+			if len(d.b) < 2 {
+				return nil, errors.New("insufficient bytes to decode tsquery")
+			}
+			n.op = followedBy
+			n.followedN = binary.BigEndian.Uint16(d.b)
+			d.b = d.b[2:]
+		default:
+			return nil, errors.Newf("unknown tsquery operator %d", code)
+		}
+		var err error
+		if n.r, err = d.decode(); err != nil {
+			return nil, err
+		}
+		if n.l, err = d.decode(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, errors.Newf("unknown tsquery item type %d", typ)
+}
+
+// EncodeInvertedIndexKeys returns the inverted index keys for the vector: one
+// key per lexeme, each of them prefixed by inKey.
+func EncodeInvertedIndexKeys(inKey []byte, v TSVector) [][]byte {
+	outKeys := make([][]byte, 0, len(v))
+	for _, term := range v {
+		outKey := make([]byte, len(inKey), len(inKey)+len(term.lexeme)+2)
+		copy(outKey, inKey)
+		outKeys = append(outKeys, encoding.EncodeStringAscending(outKey, term.lexeme))
+	}
+	return outKeys
+}
+
+// GetInvertedExpr returns the inverted expression that can be used to search
+// an inverted index on a TSVector column for rows that match the query. A
+// NonInvertedColExpression is returned if the index can't be used, for
+// example because the query only consists of negated lexemes.
+func (q TSQuery) GetInvertedExpr() (inverted.Expression, error) {
+	if q.root == nil {
+		return inverted.NonInvertedColExpression{}, nil
+	}
+	return q.root.invertedExpr()
+}
+
+func (n *tsNode) invertedExpr() (inverted.Expression, error) {
+	switch n.op {
+	case invalid:
+		key := encoding.EncodeStringAscending(nil, n.lexeme)
+		var expr *inverted.SpanExpression
+		if n.prefix {
+			// Strip the terminator of the encoded string, so that the key is a
+			// prefix of the keys of all lexemes that begin with the operand.
+			key = key[:len(key)-2]
+			expr = inverted.ExprForSpan(inverted.Span{
+				Start: key, End: keysbase.PrefixEnd(key),
+			}, true /* tight */)
+		} else {
+			expr = inverted.ExprForSpan(inverted.MakeSingleValSpan(key), true /* tight */)
+			expr.Unique = true
+		}
+		if n.weightMask != 0 {
+			// The index doesn't store the weights of lexemes, so they must be
+			// checked after the index scan.
+			expr.SetNotTight()
+		}
+		return expr, nil
+	case not:
+		// The index can't find rows that don't contain a lexeme.
+		return inverted.NonInvertedColExpression{}, nil
+	case and, or, followedBy:
+		l, err := n.l.invertedExpr()
+		if err != nil {
+			return nil, err
+		}
+		r, err := n.r.invertedExpr()
+		if err != nil {
+			return nil, err
+		}
+		if n.op == or {
+			return inverted.Or(l, r), nil
+		}
+		expr := inverted.And(l, r)
+		if n.op == followedBy {
+			// The index doesn't store positions, so phrases must be checked
+			// after the index scan.
+			expr.SetNotTight()
+		}
+		return expr, nil
+	}
+	return nil, errors.AssertionFailedf("unknown tsquery operator %d", n.op)
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeTSVector(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`a`,
+		`a:1A fat:2B,4C cat:5D`,
+		`'it''s' 'a b':16383A stripped`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			vec, err := ParseTSVector(input)
+			require.NoError(t, err)
+			decoded, err := DecodeTSVector(EncodeTSVector(nil, vec))
+			require.NoError(t, err)
+			assert.Equal(t, vec.String(), decoded.String())
+		})
+	}
+
+	// A known encoding of 'a':1A 'b'.
+	vec, err := ParseTSVector(`a:1A b`)
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]byte{0, 0, 0, 2, 'a', 0, 0, 1, 0xc0, 1, 'b', 0, 0, 0},
+		EncodeTSVector(nil, vec),
+	)
+
+	_, err = DecodeTSVector([]byte{0, 0, 0, 1, 'a'})
+	assert.Error(t, err)
+}
+
+func TestEncodeDecodeTSQuery(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`a`,
+		`a:*AB`,
+		`fat & (rat | !cat)`,
+		`a <-> b <3> c`,
+		`a <-> (b <-> c)`,
+		`!(a & b)`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			q, err := ParseTSQuery(input)
+			require.NoError(t, err)
+			decoded, err := DecodeTSQuery(EncodeTSQuery(nil, q))
+			require.NoError(t, err)
+			assert.Equal(t, q.String(), decoded.String())
+		})
+	}
+
+	// A known encoding of 'a' & 'b':*, in which the right operand of the & is
+	// encoded first.
+	q, err := ParseTSQuery(`a & b:*`)
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]byte{0, 0, 0, 3, 2, 2, 1, 0, 1, 'b', 0, 1, 0, 0, 'a', 0},
+		EncodeTSQuery(nil, q),
+	)
+}
+
+func TestGetInvertedExpr(t *testing.T) {
+	vec, err := ParseTSVector(`a:1 fat:2 cat:3 sat:4`)
+	require.NoError(t, err)
+	keys := EncodeInvertedIndexKeys(nil, vec)
+	require.Len(t, keys, 4)
+
+	for _, tc := range []struct {
+		query string
+		// nonInverted is true if the index can't be used for the query.
+		nonInverted bool
+		tight       bool
+		unique      bool
+		// contains is whether the spans of the expression contain any of the
+		// keys of the vector.
+		contains bool
+	}{
+		{query: `cat`, tight: true, unique: true, contains: true},
+		{query: `dog`, tight: true, unique: true, contains: false},
+		{query: `ca:*`, tight: true, contains: true},
+		{query: `do:*`, tight: true, contains: false},
+		{query: `cat:A`, tight: false, unique: true, contains: true},
+		{query: `cat & fat`, tight: true, unique: true, contains: true},
+		{query: `cat | dog`, tight: true, contains: true},
+		{query: `cat <-> fat`, tight: false, unique: true, contains: true},
+		{query: `cat & !dog`, tight: false, unique: true, contains: true},
+		{query: `!dog`, nonInverted: true},
+		{query: `cat | !dog`, nonInverted: true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			expr, err := q.GetInvertedExpr()
+			require.NoError(t, err)
+			if tc.nonInverted {
+				assert.IsType(t, inverted.NonInvertedColExpression{}, expr)
+				return
+			}
+			spanExpr, ok := expr.(*inverted.SpanExpression)
+			require.True(t, ok)
+			assert.Equal(t, tc.tight, spanExpr.Tight)
+			assert.Equal(t, tc.unique, spanExpr.Unique)
+			contains, err := spanExpr.ContainsKeys(keys)
+			require.NoError(t, err)
+			assert.Equal(t, tc.contains, contains)
+		})
+	}
+}
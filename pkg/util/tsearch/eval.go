@@ -0,0 +1,250 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+)
+
+// EvalTSQuery returns whether the given vector matches the query, which is the
+// semantics of the @@ operator.
+func EvalTSQuery(q TSQuery, v TSVector) bool {
+	if q.root == nil {
+		return false
+	}
+	return evalNode(q.root, v)
+}
+
+// evalNode evaluates a node of a query against the vector, ignoring positions
+// unless the node is a phrase operator.
+func evalNode(n *tsNode, v TSVector) bool {
+	switch n.op {
+	case invalid:
+		return matchesOperand(n, v)
+	case not:
+		return !evalNode(n.l, v)
+	case and:
+		return evalNode(n.l, v) && evalNode(n.r, v)
+	case or:
+		return evalNode(n.l, v) || evalNode(n.r, v)
+	case followedBy:
+		res := evalPhrase(n, v)
+		if res.noPositions {
+			// The phrase can't be checked without positions, so fall back to
+			// requiring both operands to match, as Postgres does.
+			return evalNode(n.l, v) && evalNode(n.r, v)
+		}
+		return res.negate || len(res.positions) > 0
+	}
+	return false
+}
+
+// operandTerms returns the range [start, end) of terms of the vector that
+// match the lexeme of the given operand, ignoring weights.
+func operandTerms(n *tsNode, v TSVector) (start, end int) {
+	if n.prefix {
+		return v.findPrefix(n.lexeme)
+	}
+	i, ok := v.find(n.lexeme)
+	if !ok {
+		return i, i
+	}
+	return i, i + 1
+}
+
+// matchesOperand returns whether any term of the vector matches the operand.
+// A term matches if its lexeme matches and it has a position with one of the
+// weights of the operand. Terms without positions match any weight.
+func matchesOperand(n *tsNode, v TSVector) bool {
+	start, end := operandTerms(n, v)
+	for i := start; i < end; i++ {
+		if n.weightMask == 0 || len(v[i].positions) == 0 {
+			return true
+		}
+		for _, p := range v[i].positions {
+			if n.weightMask&(1<<p.weight) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// phraseResult is the result of evaluating a node within a phrase: the sorted
+// list of positions at which the node matches. If negate is true, the node
+// instead matches at all positions but the listed ones. width is the distance
+// between the first and the last operand of the match, and positions always
+// refer to the last operand.
+type phraseResult struct {
+	positions []uint16
+	negate    bool
+	width     int
+	// noPositions is true if a term matched by the node has no position
+	// information, so that the phrase can't be checked.
+	noPositions bool
+}
+
+// evalPhrase evaluates the given node as part of a phrase.
+func evalPhrase(n *tsNode, v TSVector) phraseResult {
+	switch n.op {
+	case invalid:
+		var res phraseResult
+		start, end := operandTerms(n, v)
+		for i := start; i < end; i++ {
+			if len(v[i].positions) == 0 {
+				res.noPositions = true
+				continue
+			}
+			for _, p := range v[i].positions {
+				if n.weightMask == 0 || n.weightMask&(1<<p.weight) != 0 {
+					res.positions = append(res.positions, p.position)
+				}
+			}
+		}
+		if end-start > 1 {
+			res.positions = sortPositions(res.positions)
+		}
+		return res
+
+	case not:
+		res := evalPhrase(n.l, v)
+		res.negate = !res.negate
+		return res
+
+	case and, or:
+		l, r := evalPhrase(n.l, v), evalPhrase(n.r, v)
+		res := phraseResult{
+			width:       maxInt(l.width, r.width),
+			noPositions: l.noPositions || r.noPositions,
+		}
+		if n.op == or {
+			// a | b is equivalent to !(!a & !b).
+			l.negate, r.negate = !l.negate, !r.negate
+		}
+		switch {
+		case l.negate && r.negate:
+			res.positions, res.negate = union(l.positions, r.positions), true
+		case l.negate:
+			res.positions = except(r.positions, l.positions)
+		case r.negate:
+			res.positions = except(l.positions, r.positions)
+		default:
+			res.positions = intersect(l.positions, r.positions)
+		}
+		if n.op == or {
+			res.negate = !res.negate
+		}
+		return res
+
+	case followedBy:
+		l, r := evalPhrase(n.l, v), evalPhrase(n.r, v)
+		res := phraseResult{
+			width:       l.width + int(n.followedN) + r.width,
+			noPositions: l.noPositions || r.noPositions,
+		}
+		// Shift the positions of the left operand so that they line up with the
+		// positions of the matching right operand.
+		offset := int(n.followedN) + r.width
+		shifted := make([]uint16, 0, len(l.positions))
+		for _, p := range l.positions {
+			if pos := int(p) + offset; pos <= math.MaxUint16 {
+				shifted = append(shifted, uint16(pos))
+			}
+		}
+		switch {
+		case l.negate && r.negate:
+			res.positions, res.negate = union(shifted, r.positions), true
+		case l.negate:
+			res.positions = except(r.positions, shifted)
+		case r.negate:
+			res.positions = except(shifted, r.positions)
+		default:
+			res.positions = intersect(shifted, r.positions)
+		}
+		return res
+	}
+	return phraseResult{}
+}
+
+func sortPositions(positions []uint16) []uint16 {
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+	res := positions[:0]
+	for i, p := range positions {
+		if i == 0 || p != positions[i-1] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// union returns the union of two sorted lists of positions.
+func union(a, b []uint16) []uint16 {
+	res := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			res = append(res, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// intersect returns the intersection of two sorted lists of positions.
+func intersect(a, b []uint16) []uint16 {
+	var res []uint16
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// except returns the positions of the sorted list a that are not in the
+// sorted list b.
+func except(a, b []uint16) []uint16 {
+	var res []uint16
+	j := 0
+	for _, p := range a {
+		for j < len(b) && b[j] < p {
+			j++
+		}
+		if j == len(b) || b[j] != p {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalTSQuery(t *testing.T) {
+	for _, tc := range []struct {
+		vector   string
+		query    string
+		expected bool
+	}{
+		{`a fat cat sat on a mat`, ``, false},
+		{`a fat cat sat on a mat`, `cat & mat`, true},
+		{`a fat cat sat on a mat`, `cat & rat`, false},
+		{`a fat cat sat on a mat`, `cat & !rat`, true},
+		{`a fat cat sat on a mat`, `cat & !mat`, false},
+		{`a fat cat sat on a mat`, `rat | mat`, true},
+		{`a fat cat sat on a mat`, `!rat`, true},
+		{`a fat cat sat on a mat`, `ca:*`, true},
+		{`a fat cat sat on a mat`, `ra:*`, false},
+
+		// Weights.
+		{`a:1A b:2`, `a:A`, true},
+		{`a:1A b:2`, `a:BC`, false},
+		{`a:1A b:2`, `b:D`, true},
+		{`a b`, `a:A`, true},
+
+		// Phrases.
+		{`a:1 b:2 c:3`, `a <-> b`, true},
+		{`a:1 b:2 c:3`, `b <-> a`, false},
+		{`a:1 b:2 c:3`, `a <-> c`, false},
+		{`a:1 b:2 c:3`, `a <2> c`, true},
+		{`a:1 b:2 c:3`, `a <0> a`, true},
+		{`a:1 b:2 c:3`, `(a <-> b) <-> c`, true},
+		{`a:1 b:2 c:3`, `a <-> (b <-> c)`, true},
+		{`a:1 b:2 c:3`, `a <-> (c <-> b)`, false},
+		{`a:1 b:2 c:3`, `a <-> (b | d)`, true},
+		{`a:1 b:2 c:3`, `a <-> (b & c)`, false},
+		{`a:1 b:2 c:3`, `!a <-> b`, false},
+		{`a:1 b:2 c:3`, `!c <-> b`, true},
+		{`a:1 b:2 c:3`, `b <-> !a`, true},
+		{`a:1 b:2 c:3`, `a <-> !b`, false},
+		{`a:1 b:2,5 c:3`, `a <-> !b`, false},
+		{`a:1,4 b:2 c:3`, `a <-> !b`, true},
+		{`a:1A b:2 c:3`, `a:B <-> b`, false},
+		{`a:1 b:2 c:3`, `a:* <-> b`, true},
+
+		// Phrases can't be checked without positions.
+		{`a b`, `a <-> b`, true},
+		{`a b`, `a <-> c`, false},
+	} {
+		t.Run(tc.vector+" @@ "+tc.query, func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			require.NoError(t, err)
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, EvalTSQuery(q, v))
+		})
+	}
+}
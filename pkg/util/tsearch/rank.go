@@ -0,0 +1,208 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultWeights are the weights used by ts_rank for the D, C, B and A
+// weights, in that order, when none are specified.
+var DefaultWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
+
+// The normalization flags of ts_rank, which control how the rank is adjusted
+// for the length of the document. The flags may be ORed together.
+const (
+	// rankNormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	rankNormLogLength = 1
+	// rankNormLength divides the rank by the document length.
+	rankNormLength = 2
+	// rankNormUniq divides the rank by the number of unique words in the
+	// document.
+	rankNormUniq = 8
+	// rankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	rankNormLogUniq = 16
+	// rankNormRDivRPlus1 divides the rank by itself + 1.
+	rankNormRDivRPlus1 = 32
+)
+
+// Rank returns the rank of the vector against the query, which is a measure
+// of how relevant the document is to the query, as computed by the Postgres
+// ts_rank function. weights holds the weight of each of the D, C, B and A
+// position weights, and method is a bitmask of normalization flags.
+func Rank(weights [4]float32, v TSVector, q TSQuery, method int) float32 {
+	if len(v) == 0 || q.root == nil {
+		return 0
+	}
+	var res float32
+	if q.root.op == and || q.root.op == followedBy {
+		res = calcRankAnd(weights, v, q)
+	} else {
+		res = calcRankOr(weights, v, q)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if method&rankNormLogLength != 0 {
+		res = float32(float64(res) / (math.Log(float64(v.length()+1)) / math.Log(2.0)))
+	}
+	if method&rankNormLength != 0 {
+		if length := v.length(); length > 0 {
+			res /= float32(length)
+		}
+	}
+	if method&rankNormUniq != 0 {
+		res /= float32(len(v))
+	}
+	if method&rankNormLogUniq != 0 {
+		res = float32(float64(res) / (math.Log(float64(len(v)+1)) / math.Log(2.0)))
+	}
+	if method&rankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// length returns the number of positions in the vector, counting terms
+// without positions once.
+func (v TSVector) length() int {
+	var n int
+	for _, term := range v {
+		if len(term.positions) == 0 {
+			n++
+		} else {
+			n += len(term.positions)
+		}
+	}
+	return n
+}
+
+// uniqueOperands returns the operands of the query, sorted and deduplicated by
+// lexeme.
+func (q TSQuery) uniqueOperands() []*tsNode {
+	var operands []*tsNode
+	q.root.walk(func(n *tsNode) {
+		if n.op == invalid {
+			operands = append(operands, n)
+		}
+	})
+	sort.SliceStable(operands, func(i, j int) bool {
+		return operands[i].lexeme < operands[j].lexeme
+	})
+	res := operands[:0]
+	for i, n := range operands {
+		if i == 0 || n.lexeme != operands[i-1].lexeme {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func wordDistance(dist int) float32 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(float32(dist)/1.5-2))))
+}
+
+// calcRankOr computes the rank of a query that matches if any of its operands
+// match. Each operand contributes according to the weights of its positions.
+func calcRankOr(weights [4]float32, v TSVector, q TSQuery) float32 {
+	operands := q.uniqueOperands()
+	var res float32
+	// The positions of terms without positions are treated as a single
+	// position with weight D.
+	noPositions := []tsPosition{{}}
+	for _, operand := range operands {
+		start, end := operandTerms(operand, v)
+		for i := start; i < end; i++ {
+			positions := v[i].positions
+			if len(positions) == 0 {
+				positions = noPositions
+			}
+			var resj float32
+			wjm := float32(-1)
+			jm := 0
+			for j, p := range positions {
+				w := weights[p.weight]
+				resj += w / float32((j+1)*(j+1))
+				if w > wjm {
+					wjm = w
+					jm = j
+				}
+			}
+			// The sum of 1/i^2 for i = 1..inf is pi^2/6.
+			res = float32(float64(res) +
+				float64(wjm+resj-wjm/float32((jm+1)*(jm+1)))/1.64493406685)
+		}
+	}
+	if len(operands) > 0 {
+		res /= float32(len(operands))
+	}
+	return res
+}
+
+// calcRankAnd computes the rank of a query that matches if all of its
+// operands match. Pairs of matched operands contribute according to their
+// weights and the distance between them.
+func calcRankAnd(weights [4]float32, v TSVector, q TSQuery) float32 {
+	operands := q.uniqueOperands()
+	if len(operands) < 2 {
+		return calcRankOr(weights, v, q)
+	}
+	// The positions of terms without positions are treated as a single
+	// position far away from all others.
+	noPositions := []tsPosition{{position: maxPosition}}
+	pos := make([][]tsPosition, len(operands))
+	res := float32(-1)
+	for i, operand := range operands {
+		start, end := operandTerms(operand, v)
+		for t := start; t < end; t++ {
+			isNull := len(v[t].positions) == 0
+			if isNull {
+				pos[i] = noPositions
+			} else {
+				pos[i] = v[t].positions
+			}
+			for k := 0; k < i; k++ {
+				if pos[k] == nil {
+					continue
+				}
+				kIsNull := len(pos[k]) == 1 && &pos[k][0] == &noPositions[0]
+				for _, pl := range pos[i] {
+					for _, pk := range pos[k] {
+						dist := int(pl.position) - int(pk.position)
+						if dist < 0 {
+							dist = -dist
+						}
+						if dist == 0 && !isNull && !kIsNull {
+							continue
+						}
+						if dist == 0 {
+							dist = maxPosition + 1
+						}
+						curw := float32(math.Sqrt(float64(
+							weights[pl.weight] * weights[pk.weight] * wordDistance(dist))))
+						if res < 0 {
+							res = curw
+						} else {
+							res = float32(1.0 - (1.0-float64(res))*(1.0-float64(curw)))
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
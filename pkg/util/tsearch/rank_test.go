@@ -0,0 +1,51 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	for _, tc := range []struct {
+		weights  [4]float32
+		vector   string
+		query    string
+		method   int
+		expected float32
+	}{
+		{DefaultWeights, `a:1 fat:2 cat:3`, ``, 0, 0},
+		{DefaultWeights, ``, `cat`, 0, 0},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `cat`, 0, 0.0607927},
+		{DefaultWeights, `a:1 fat:2 cat:3A`, `cat`, 0, 0.6079271},
+		{[4]float32{0.1, 0.2, 0.4, 0.5}, `a:1 fat:2 cat:3A`, `cat`, 0, 0.30396355},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `dog`, 0, 0},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `cat | dog`, 0, 0.03039636},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `fat & cat`, 0, 0.09910322},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `fat <-> cat`, 0, 0.09910322},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `a & cat`, 0, 0.09850086},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `fat & cat`, rankNormLength, 0.03303441},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `fat & cat`, rankNormUniq, 0.03303441},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `fat & cat`, rankNormLogLength, 0.04955161},
+		{DefaultWeights, `a:1 fat:2 cat:3`, `fat & cat`, rankNormRDivRPlus1, 0.09016759},
+	} {
+		t.Run(tc.vector+" @@ "+tc.query, func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			require.NoError(t, err)
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, Rank(tc.weights, v, q, tc.method), 1e-6)
+		})
+	}
+}
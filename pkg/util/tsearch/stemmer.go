@@ -0,0 +1,383 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "strings"
+
+// This file implements the Snowball English (Porter2) stemming algorithm,
+// which is the stemmer used by the Postgres english text search
+// configuration. See https://snowballstem.org/algorithms/english/stemmer.html
+// for a description of the algorithm and the terminology used below.
+
+// stemExceptions are words whose stem is not computed by the algorithm.
+var stemExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// stemStep1aExceptions are words that are left as is after step 1a.
+var stemStep1aExceptions = map[string]struct{}{
+	"inning":  {},
+	"outing":  {},
+	"canning": {},
+	"herring": {},
+	"earring": {},
+	"proceed": {},
+	"exceed":  {},
+	"succeed": {},
+}
+
+// stemmer holds the state of a word being stemmed.
+type stemmer struct {
+	b []byte
+	// r1 and r2 are the start offsets of the R1 and R2 regions of the word.
+	r1, r2 int
+}
+
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := stemExceptions[word]; ok {
+		return stem
+	}
+	s := stemmer{b: []byte(strings.TrimPrefix(word, "'"))}
+	s.markYs()
+	s.computeRegions()
+	s.step0()
+	s.step1a()
+	if _, ok := stemStep1aExceptions[string(s.b)]; ok {
+		return string(s.b)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	for i, c := range s.b {
+		if c == 'Y' {
+			s.b[i] = 'y'
+		}
+	}
+	return string(s.b)
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// markYs marks an initial y, and a y after a vowel, as a consonant by
+// replacing it with Y.
+func (s *stemmer) markYs() {
+	for i, c := range s.b {
+		if c == 'y' && (i == 0 || isVowel(s.b[i-1])) {
+			s.b[i] = 'Y'
+		}
+	}
+}
+
+// regionAfter returns the offset of the region after the first non-vowel
+// following a vowel, starting at offset start.
+func (s *stemmer) regionAfter(start int) int {
+	for i := start + 1; i < len(s.b); i++ {
+		if !isVowel(s.b[i]) && isVowel(s.b[i-1]) {
+			return i + 1
+		}
+	}
+	return len(s.b)
+}
+
+func (s *stemmer) computeRegions() {
+	s.r1 = len(s.b)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if s.hasPrefix(prefix) {
+			s.r1 = len(prefix)
+			break
+		}
+	}
+	if s.r1 == len(s.b) {
+		s.r1 = s.regionAfter(0)
+	}
+	s.r2 = s.regionAfter(s.r1)
+}
+
+func (s *stemmer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(s.b), prefix)
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// longestSuffix returns the longest of the given suffixes that the word ends
+// with, or the empty string if there is none.
+func (s *stemmer) longestSuffix(suffixes ...string) string {
+	var res string
+	for _, suffix := range suffixes {
+		if len(suffix) > len(res) && s.hasSuffix(suffix) {
+			res = suffix
+		}
+	}
+	return res
+}
+
+// inR1 and inR2 return whether the given suffix is contained in the R1 or R2
+// regions of the word.
+func (s *stemmer) inR1(suffix string) bool {
+	return len(s.b)-len(suffix) >= s.r1
+}
+
+func (s *stemmer) inR2(suffix string) bool {
+	return len(s.b)-len(suffix) >= s.r2
+}
+
+// replace replaces the given suffix of the word with repl.
+func (s *stemmer) replace(suffix, repl string) {
+	s.b = append(s.b[:len(s.b)-len(suffix)], repl...)
+}
+
+// endsWithShortSyllable returns whether the word ends with a short syllable,
+// which is either a non-vowel followed by a vowel and a non-vowel other than
+// w, x or Y, or a vowel at the start of the word followed by a non-vowel.
+func (s *stemmer) endsWithShortSyllable() bool {
+	n := len(s.b)
+	if n == 2 {
+		return isVowel(s.b[0]) && !isVowel(s.b[1])
+	}
+	if n >= 3 {
+		c := s.b[n-1]
+		return !isVowel(s.b[n-3]) && isVowel(s.b[n-2]) &&
+			!isVowel(c) && c != 'w' && c != 'x' && c != 'Y'
+	}
+	return false
+}
+
+// isShort returns whether the word is short, which is when it ends with a
+// short syllable and its R1 region is empty.
+func (s *stemmer) isShort() bool {
+	return s.r1 >= len(s.b) && s.endsWithShortSyllable()
+}
+
+// step0 removes possessive suffixes.
+func (s *stemmer) step0() {
+	if suffix := s.longestSuffix("'", "'s", "'s'"); suffix != "" {
+		s.replace(suffix, "")
+	}
+}
+
+// step1a handles plurals.
+func (s *stemmer) step1a() {
+	switch suffix := s.longestSuffix("sses", "ied", "ies", "s", "us", "ss"); suffix {
+	case "sses":
+		s.replace(suffix, "ss")
+	case "ied", "ies":
+		if len(s.b) > 4 {
+			s.replace(suffix, "i")
+		} else {
+			s.replace(suffix, "ie")
+		}
+	case "s":
+		// Delete the s if the preceding part of the word contains a vowel not
+		// immediately before the s.
+		for i := 0; i < len(s.b)-2; i++ {
+			if isVowel(s.b[i]) {
+				s.replace(suffix, "")
+				break
+			}
+		}
+	}
+}
+
+// step1b handles past tenses and gerunds.
+func (s *stemmer) step1b() {
+	switch suffix := s.longestSuffix("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if s.inR1(suffix) {
+			s.replace(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		stem := s.b[:len(s.b)-len(suffix)]
+		hasVowel := false
+		for _, c := range stem {
+			if isVowel(c) {
+				hasVowel = true
+				break
+			}
+		}
+		if !hasVowel {
+			return
+		}
+		s.replace(suffix, "")
+		if s.hasSuffix("at") || s.hasSuffix("bl") || s.hasSuffix("iz") {
+			s.b = append(s.b, 'e')
+		} else if s.endsWithDouble() {
+			s.b = s.b[:len(s.b)-1]
+		} else if s.isShort() {
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// endsWithDouble returns whether the word ends with one of the doubled
+// consonants bb, dd, ff, gg, mm, nn, pp, rr or tt.
+func (s *stemmer) endsWithDouble() bool {
+	n := len(s.b)
+	if n < 2 || s.b[n-1] != s.b[n-2] {
+		return false
+	}
+	switch s.b[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+// step1c replaces a final y with i if it follows a non-vowel which is not the
+// first letter of the word.
+func (s *stemmer) step1c() {
+	n := len(s.b)
+	if n > 2 && (s.b[n-1] == 'y' || s.b[n-1] == 'Y') && !isVowel(s.b[n-2]) {
+		s.b[n-1] = 'i'
+	}
+}
+
+func (s *stemmer) step2() {
+	suffix := s.longestSuffix(
+		"tional", "enci", "anci", "abli", "entli", "izer", "ization", "ational",
+		"ation", "ator", "alism", "aliti", "alli", "fulness", "ousli", "ousness",
+		"iveness", "iviti", "biliti", "bli", "ogi", "fulli", "lessli", "li",
+	)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	switch suffix {
+	case "tional":
+		s.replace(suffix, "tion")
+	case "enci":
+		s.replace(suffix, "ence")
+	case "anci":
+		s.replace(suffix, "ance")
+	case "abli":
+		s.replace(suffix, "able")
+	case "entli":
+		s.replace(suffix, "ent")
+	case "izer", "ization":
+		s.replace(suffix, "ize")
+	case "ational", "ation", "ator":
+		s.replace(suffix, "ate")
+	case "alism", "aliti", "alli":
+		s.replace(suffix, "al")
+	case "fulness":
+		s.replace(suffix, "ful")
+	case "ousli", "ousness":
+		s.replace(suffix, "ous")
+	case "iveness", "iviti":
+		s.replace(suffix, "ive")
+	case "biliti", "bli":
+		s.replace(suffix, "ble")
+	case "ogi":
+		if n := len(s.b); n > 3 && s.b[n-4] == 'l' {
+			s.replace(suffix, "og")
+		}
+	case "fulli":
+		s.replace(suffix, "ful")
+	case "lessli":
+		s.replace(suffix, "less")
+	case "li":
+		if n := len(s.b); n > 2 {
+			switch s.b[n-3] {
+			case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+				s.replace(suffix, "")
+			}
+		}
+	}
+}
+
+func (s *stemmer) step3() {
+	suffix := s.longestSuffix(
+		"tional", "ational", "alize", "icate", "iciti", "ative", "ical", "ful", "ness",
+	)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	switch suffix {
+	case "tional":
+		s.replace(suffix, "tion")
+	case "ational":
+		s.replace(suffix, "ate")
+	case "alize":
+		s.replace(suffix, "al")
+	case "icate", "iciti", "ical":
+		s.replace(suffix, "ic")
+	case "ful", "ness":
+		s.replace(suffix, "")
+	case "ative":
+		if s.inR2(suffix) {
+			s.replace(suffix, "")
+		}
+	}
+}
+
+func (s *stemmer) step4() {
+	suffix := s.longestSuffix(
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+		"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+	)
+	if suffix == "" || !s.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" {
+		if n := len(s.b); n > 3 && (s.b[n-4] == 's' || s.b[n-4] == 't') {
+			s.replace(suffix, "")
+		}
+		return
+	}
+	s.replace(suffix, "")
+}
+
+func (s *stemmer) step5() {
+	switch {
+	case s.hasSuffix("e"):
+		if s.inR2("e") {
+			s.replace("e", "")
+		} else if s.inR1("e") {
+			s.b = s.b[:len(s.b)-1]
+			if s.endsWithShortSyllable() {
+				s.b = append(s.b, 'e')
+			}
+		}
+	case s.hasSuffix("l"):
+		if s.inR2("l") && s.hasSuffix("ll") {
+			s.replace("l", "")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStemEnglish(t *testing.T) {
+	// These test cases are taken from the sample vocabulary of the Snowball
+	// English stemmer.
+	for _, tc := range []struct {
+		word     string
+		expected string
+	}{
+		{"a", "a"},
+		{"running", "run"},
+		{"rats", "rat"},
+		{"foxes", "fox"},
+		{"jumped", "jump"},
+		{"lazy", "lazi"},
+		{"supernovae", "supernova"},
+		{"happiness", "happi"},
+		{"generalization", "general"},
+		{"agreed", "agre"},
+		{"consign", "consign"},
+		{"consigned", "consign"},
+		{"consigning", "consign"},
+		{"consignment", "consign"},
+		{"consist", "consist"},
+		{"consisted", "consist"},
+		{"consistency", "consist"},
+		{"consistent", "consist"},
+		{"consistently", "consist"},
+		{"consisting", "consist"},
+		{"consists", "consist"},
+		{"consolation", "consol"},
+		{"consolations", "consol"},
+		{"consolatory", "consolatori"},
+		{"console", "consol"},
+		{"consoled", "consol"},
+		{"consoles", "consol"},
+		{"consolidate", "consolid"},
+		{"consolidated", "consolid"},
+		{"consolidating", "consolid"},
+		{"consoling", "consol"},
+		{"consolingly", "consol"},
+		{"consols", "consol"},
+		{"consonant", "conson"},
+		{"consort", "consort"},
+		{"consorted", "consort"},
+		{"consorting", "consort"},
+		{"conspicuous", "conspicu"},
+		{"conspicuously", "conspicu"},
+		{"conspiracy", "conspiraci"},
+		{"conspirator", "conspir"},
+		{"conspirators", "conspir"},
+		{"conspire", "conspir"},
+		{"conspired", "conspir"},
+		{"conspiring", "conspir"},
+		{"constable", "constabl"},
+		{"constables", "constabl"},
+		{"constance", "constanc"},
+		{"constancy", "constanc"},
+		{"constant", "constant"},
+		{"knack", "knack"},
+		{"knackeries", "knackeri"},
+		{"knacks", "knack"},
+		{"knag", "knag"},
+		{"knave", "knave"},
+		{"knaves", "knave"},
+		{"knavish", "knavish"},
+		{"kneaded", "knead"},
+		{"kneading", "knead"},
+		{"knee", "knee"},
+		{"kneel", "kneel"},
+		{"kneeled", "kneel"},
+		{"kneeling", "kneel"},
+		{"kneels", "kneel"},
+		{"knees", "knee"},
+		{"knell", "knell"},
+		{"knelt", "knelt"},
+		{"knew", "knew"},
+		{"knick", "knick"},
+		{"knif", "knif"},
+		{"knife", "knife"},
+		{"knight", "knight"},
+		{"knightly", "knight"},
+		{"knights", "knight"},
+		{"knit", "knit"},
+		{"knits", "knit"},
+		{"knitted", "knit"},
+		{"knitting", "knit"},
+		{"knives", "knive"},
+		{"knob", "knob"},
+		{"knobs", "knob"},
+		{"knock", "knock"},
+		{"knocked", "knock"},
+		{"knocker", "knocker"},
+		{"knockers", "knocker"},
+		{"knocking", "knock"},
+		{"knocks", "knock"},
+		{"knopp", "knopp"},
+		{"knot", "knot"},
+		{"knots", "knot"},
+		// Exceptions.
+		{"skies", "sky"},
+		{"dying", "die"},
+		{"news", "news"},
+		{"inning", "inning"},
+		{"succeeding", "succeed"},
+	} {
+		assert.Equal(t, tc.expected, stemEnglish(tc.word), tc.word)
+	}
+}
@@ -0,0 +1,473 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// tsOperator is an operator of a tsquery.
+type tsOperator byte
+
+const (
+	// invalid marks an operand node.
+	invalid tsOperator = iota
+	// not is the unary ! operator.
+	not
+	// and is the binary & operator.
+	and
+	// or is the binary | operator.
+	or
+	// followedBy is the binary <-> (or <N>) phrase operator.
+	followedBy
+)
+
+// priority returns the precedence of the operator. Operators with a higher
+// priority bind more tightly.
+func (o tsOperator) priority() int {
+	switch o {
+	case not:
+		return 4
+	case followedBy:
+		return 3
+	case and:
+		return 2
+	case or:
+		return 1
+	}
+	return 0
+}
+
+// maxFollowedByDistance is the maximum distance of a <N> operator.
+const maxFollowedByDistance = 16384
+
+// tsNode is a node of a tsquery tree. It is either an operand (a lexeme that
+// must be matched) or an operator applied to one (for !) or two subtrees.
+type tsNode struct {
+	// lexeme, weightMask and prefix are set for operands.
+	lexeme string
+	// weightMask restricts the operand to match only positions with the given
+	// weights: bit 1<<w is set for each allowed weight w. Zero means any weight
+	// matches.
+	weightMask byte
+	// prefix is true if the operand matches all lexemes beginning with lexeme.
+	prefix bool
+
+	op tsOperator
+	// followedN is the distance of a followedBy operator.
+	followedN uint16
+	l, r      *tsNode
+}
+
+// TSQuery is a tree of lexemes combined by the !, &, | and <-> operators. An
+// empty query has no root.
+type TSQuery struct {
+	root *tsNode
+}
+
+// ParseTSQuery parses the input string using the Postgres tsquery input
+// syntax, e.g. 'fat' & ('rat:*' | !cat).
+func ParseTSQuery(input string) (TSQuery, error) {
+	return parseTSQuery(input, nil /* normalize */)
+}
+
+// operandNormalizer converts the text of an operand into zero or more nodes.
+// It is used by to_tsquery to run the operands of a query through a text
+// search configuration. A nil node is returned for stop words.
+type operandNormalizer func(operand *tsNode) (*tsNode, error)
+
+func parseTSQuery(input string, normalize operandNormalizer) (TSQuery, error) {
+	p := tsQueryParser{tsParser: tsParser{input: input, isQuery: true}, normalize: normalize}
+	p.skipSpace()
+	if p.eof() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return TSQuery{}, p.syntaxError()
+	}
+	if normalize != nil {
+		root = cleanStopWords(root)
+	}
+	return TSQuery{root: root}, nil
+}
+
+type tsQueryParser struct {
+	tsParser
+	normalize operandNormalizer
+}
+
+// parseOr parses an expression of the form a | b | ...
+func (p *tsQueryParser) parseOr() (*tsNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() != '|' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &tsNode{op: or, l: left, r: right}
+	}
+}
+
+// parseAnd parses an expression of the form a & b & ...
+func (p *tsQueryParser) parseAnd() (*tsNode, error) {
+	left, err := p.parseFollowedBy()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() != '&' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFollowedBy()
+		if err != nil {
+			return nil, err
+		}
+		left = &tsNode{op: and, l: left, r: right}
+	}
+}
+
+// parseFollowedBy parses an expression of the form a <-> b <N> ...
+func (p *tsQueryParser) parseFollowedBy() (*tsNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() != '<' {
+			return left, nil
+		}
+		dist, err := p.followedByDistance()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &tsNode{op: followedBy, followedN: dist, l: left, r: right}
+	}
+}
+
+// followedByDistance parses a <-> or <N> operator and returns its distance.
+func (p *tsQueryParser) followedByDistance() (uint16, error) {
+	rest := p.input[p.pos:]
+	if strings.HasPrefix(rest, "<->") {
+		p.pos += 3
+		return 1, nil
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 {
+		return 0, p.syntaxError()
+	}
+	n, err := strconv.Atoi(rest[1:end])
+	if err != nil || n < 0 {
+		return 0, p.syntaxError()
+	}
+	if n > maxFollowedByDistance {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator must be an integer value between zero and %d inclusive",
+			maxFollowedByDistance)
+	}
+	p.pos += end + 1
+	return uint16(n), nil
+}
+
+// parseNot parses an expression of the form !a, or a single operand or
+// parenthesized expression.
+func (p *tsQueryParser) parseNot() (*tsNode, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.syntaxError()
+	}
+	switch p.peek() {
+	case '!':
+		p.pos++
+		child, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &tsNode{op: not, l: child}, nil
+	case '(':
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != ')' {
+			return nil, p.syntaxError()
+		}
+		p.pos++
+		return node, nil
+	case ')', '&', '|', '<':
+		return nil, p.syntaxError()
+	}
+	return p.parseOperand()
+}
+
+// parseOperand parses a lexeme, optionally followed by a colon and a list of
+// weights and a * marking a prefix match, e.g. 'supern':*AB.
+func (p *tsQueryParser) parseOperand() (*tsNode, error) {
+	lexeme, err := p.lexeme(true /* isQuery */)
+	if err != nil {
+		return nil, err
+	}
+	node := &tsNode{lexeme: lexeme}
+	if !p.eof() && p.peek() == ':' {
+		p.pos++
+	Loop:
+		for !p.eof() {
+			c := p.peek()
+			if c == '*' {
+				node.prefix = true
+			} else if w, ok := parseWeight(c); ok {
+				node.weightMask |= 1 << w
+			} else {
+				break Loop
+			}
+			p.pos++
+		}
+	}
+	if p.normalize != nil {
+		return p.normalize(node)
+	}
+	if lexeme == "" {
+		return nil, p.syntaxError()
+	}
+	return node, nil
+}
+
+// String returns the Postgres text representation of the query.
+func (q TSQuery) String() string {
+	if q.root == nil {
+		return ""
+	}
+	var sb strings.Builder
+	q.root.format(&sb, -1 /* parentPriority */, false /* rightPhrase */)
+	return sb.String()
+}
+
+// format writes the node to sb. Parentheses are added if the priority of the
+// node is lower than the priority of its parent, and around a phrase that is
+// the right operand of another phrase, since the phrase operator is not
+// associative.
+func (n *tsNode) format(sb *strings.Builder, parentPriority int, rightPhrase bool) {
+	switch n.op {
+	case invalid:
+		writeLexeme(sb, n.lexeme)
+		if n.prefix || n.weightMask != 0 {
+			sb.WriteByte(':')
+			if n.prefix {
+				sb.WriteByte('*')
+			}
+			for w := weightA; ; w-- {
+				if n.weightMask&(1<<w) != 0 {
+					sb.WriteString(w.String())
+				}
+				if w == weightD {
+					break
+				}
+			}
+		}
+	case not:
+		sb.WriteByte('!')
+		n.l.format(sb, n.op.priority(), false /* rightPhrase */)
+	default:
+		priority := n.op.priority()
+		needParens := priority < parentPriority || (n.op == followedBy && rightPhrase)
+		if needParens {
+			sb.WriteString("( ")
+		}
+		n.l.format(sb, priority, false /* rightPhrase */)
+		switch n.op {
+		case and:
+			sb.WriteString(" & ")
+		case or:
+			sb.WriteString(" | ")
+		case followedBy:
+			if n.followedN == 1 {
+				sb.WriteString(" <-> ")
+			} else {
+				sb.WriteString(" <")
+				sb.WriteString(strconv.Itoa(int(n.followedN)))
+				sb.WriteString("> ")
+			}
+		}
+		n.r.format(sb, priority, n.op == followedBy)
+		if needParens {
+			sb.WriteString(" )")
+		}
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether q sorts before, equal to or
+// after other.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Size returns the approximate in-memory size of the query, in bytes.
+func (q TSQuery) Size() uintptr {
+	var size uintptr
+	q.root.walk(func(n *tsNode) {
+		size += uintptr(len(n.lexeme)) + 48
+	})
+	return size
+}
+
+// NumNodes returns the number of lexemes and operators in the query.
+func (q TSQuery) NumNodes() int {
+	var n int
+	q.root.walk(func(*tsNode) { n++ })
+	return n
+}
+
+// walk calls fn on each node of the tree rooted at n, in prefix order.
+func (n *tsNode) walk(fn func(*tsNode)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+	n.l.walk(fn)
+	n.r.walk(fn)
+}
+
+// And returns the query q & other.
+func (q TSQuery) And(other TSQuery) TSQuery {
+	return q.combine(other, &tsNode{op: and})
+}
+
+// Or returns the query q | other.
+func (q TSQuery) Or(other TSQuery) TSQuery {
+	return q.combine(other, &tsNode{op: or})
+}
+
+// FollowedBy returns the query q <N> other.
+func (q TSQuery) FollowedBy(other TSQuery, distance int) (TSQuery, error) {
+	if distance < 0 || distance > maxFollowedByDistance {
+		return TSQuery{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator must be an integer value between zero and %d inclusive",
+			maxFollowedByDistance)
+	}
+	return q.combine(other, &tsNode{op: followedBy, followedN: uint16(distance)}), nil
+}
+
+// Not returns the query !q.
+func (q TSQuery) Not() TSQuery {
+	if q.root == nil {
+		return q
+	}
+	return TSQuery{root: &tsNode{op: not, l: q.root}}
+}
+
+// combine returns a query with the given operator node applied to q and
+// other. If either query is empty, the other is returned as is.
+func (q TSQuery) combine(other TSQuery, op *tsNode) TSQuery {
+	if q.root == nil {
+		return other
+	}
+	if other.root == nil {
+		return q
+	}
+	op.l, op.r = q.root, other.root
+	return TSQuery{root: op}
+}
+
+// cleanStopWords removes the nil operands left in place of stop words from the
+// tree. A binary operator with one removed operand is replaced by the other
+// operand, and the distance of phrase operators is adjusted so that the
+// positions of the stop words are still accounted for, e.g. a <-> the <-> b
+// becomes a <2> b.
+func cleanStopWords(n *tsNode) *tsNode {
+	res, _, _ := cleanStopWordsRec(n)
+	return res
+}
+
+// cleanStopWordsRec implements cleanStopWords. The returned ladd and radd are
+// the distances that must be added to a phrase operator that has the returned
+// node as its right or left operand, respectively, to account for removed
+// stop words.
+func cleanStopWordsRec(n *tsNode) (res *tsNode, ladd, radd int) {
+	if n == nil {
+		return nil, 0, 0
+	}
+	switch n.op {
+	case invalid:
+		return n, 0, 0
+	case not:
+		n.l, ladd, radd = cleanStopWordsRec(n.l)
+		if n.l == nil {
+			return nil, ladd, radd
+		}
+		return n, ladd, radd
+	}
+
+	var lladd, lradd, rladd, rradd int
+	n.l, lladd, lradd = cleanStopWordsRec(n.l)
+	n.r, rladd, rradd = cleanStopWordsRec(n.r)
+	isPhrase := n.op == followedBy
+	var dist int
+	if isPhrase {
+		dist = int(n.followedN)
+	}
+	switch {
+	case n.l == nil && n.r == nil:
+		// When the whole subtree is removed, propagate its distance to both
+		// sides; the parent will only count it once.
+		if isPhrase {
+			ladd = lladd + dist + rradd
+		} else {
+			ladd = lladd
+			if rradd > ladd {
+				ladd = rradd
+			}
+		}
+		return nil, ladd, ladd
+	case n.l == nil:
+		if isPhrase {
+			return n.r, lladd + dist + rladd, rradd
+		}
+		return n.r, rladd, rradd
+	case n.r == nil:
+		if isPhrase {
+			return n.l, lladd, lradd + dist + rradd
+		}
+		return n.l, lladd, lradd
+	case isPhrase:
+		dist += lradd + rladd
+		if dist > maxFollowedByDistance {
+			dist = maxFollowedByDistance
+		}
+		n.followedN = uint16(dist)
+		return n, lladd, rradd
+	}
+	return n, 0, 0
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`fat & rat`, `'fat' & 'rat'`},
+		{`fat & (rat | cat)`, `'fat' & ( 'rat' | 'cat' )`},
+		{`fat & rat & ! cat`, `'fat' & 'rat' & !'cat'`},
+		{`fat:ab & cat`, `'fat':AB & 'cat'`},
+		{`super:*`, `'super':*`},
+		{`'supern':*DA`, `'supern':*AD`},
+		{`'a b'|'it''s'`, `'a b' | 'it''s'`},
+		{`a | b & c`, `'a' | 'b' & 'c'`},
+		{`(a | b) & c`, `( 'a' | 'b' ) & 'c'`},
+		{`!(a & b)`, `!( 'a' & 'b' )`},
+		{`!!a`, `!!'a'`},
+		{`a <-> b <2> c`, `'a' <-> 'b' <2> 'c'`},
+		{`a <-> (b <-> c)`, `'a' <-> ( 'b' <-> 'c' )`},
+		{`(a | b) <-> c`, `( 'a' | 'b' ) <-> 'c'`},
+		{`a <-> b & c`, `'a' <-> 'b' & 'c'`},
+		{`a <0> b`, `'a' <0> 'b'`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q.String())
+
+			// The output must parse to the same query.
+			q2, err := ParseTSQuery(q.String())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q2.String())
+		})
+	}
+}
+
+func TestParseTSQueryError(t *testing.T) {
+	for _, input := range []string{
+		`a &`,
+		`& a`,
+		`(a`,
+		`a)`,
+		`a b`,
+		`a <- b`,
+		`a <x> b`,
+		`a <20000> b`,
+		`!`,
+		`'a`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTSQuery(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTSQueryFunctions(t *testing.T) {
+	a, err := ParseTSQuery(`a | b`)
+	require.NoError(t, err)
+	b, err := ParseTSQuery(`c`)
+	require.NoError(t, err)
+
+	assert.Equal(t, `( 'a' | 'b' ) & 'c'`, a.And(b).String())
+	assert.Equal(t, `'a' | 'b' | 'c'`, a.Or(b).String())
+	assert.Equal(t, `!( 'a' | 'b' )`, a.Not().String())
+	phrase, err := a.FollowedBy(b, 3)
+	require.NoError(t, err)
+	assert.Equal(t, `( 'a' | 'b' ) <3> 'c'`, phrase.String())
+	assert.Equal(t, `'c'`, TSQuery{}.And(b).String())
+	assert.Equal(t, 3, a.NumNodes())
+}
@@ -0,0 +1,484 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the Postgres full text search data types,
+// tsvector and tsquery, along with the functions that produce, match and rank
+// them.
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+const (
+	// maxLexemeLen is the maximum length of a lexeme, in bytes.
+	maxLexemeLen = 2046
+	// maxPosition is the largest position that can be stored in a tsvector.
+	// Larger positions are silently clamped to this value.
+	maxPosition = 1<<14 - 1
+	// maxPositionsPerLexeme is the maximum number of positions that are stored
+	// for a single lexeme. Any others are silently discarded.
+	maxPositionsPerLexeme = 256
+)
+
+// tsWeight is the weight of a lexeme position. Weights are used to mark
+// lexemes coming from different parts of a document, like the title or body,
+// and affect the ranking of matches.
+type tsWeight byte
+
+const (
+	// weightD is the default weight, and is not printed.
+	weightD tsWeight = iota
+	weightC
+	weightB
+	weightA
+)
+
+// String implements the fmt.Stringer interface.
+func (w tsWeight) String() string {
+	return string("DCBA"[w])
+}
+
+// parseWeight returns the weight corresponding to the given letter, which may
+// be upper or lower case.
+func parseWeight(c byte) (tsWeight, bool) {
+	switch c {
+	case 'A', 'a':
+		return weightA, true
+	case 'B', 'b':
+		return weightB, true
+	case 'C', 'c':
+		return weightC, true
+	case 'D', 'd':
+		return weightD, true
+	}
+	return 0, false
+}
+
+// tsPosition is a single position of a lexeme within a document.
+type tsPosition struct {
+	position uint16
+	weight   tsWeight
+}
+
+// tsTerm is a lexeme of a TSVector along with the positions at which it
+// appears. A term that was stripped of its positions has none.
+type tsTerm struct {
+	lexeme    string
+	positions []tsPosition
+}
+
+// TSVector is a sorted list of distinct lexemes, each with an optional list of
+// the positions at which the lexeme occurs in the document.
+type TSVector []tsTerm
+
+// ParseTSVector parses the input string using the Postgres tsvector input
+// syntax, e.g. 'a fat cat':1A,2 'rat':3.
+func ParseTSVector(input string) (TSVector, error) {
+	p := tsParser{input: input}
+	var vec TSVector
+	for {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+		lexeme, err := p.lexeme(false /* isQuery */)
+		if err != nil {
+			return nil, err
+		}
+		term := tsTerm{lexeme: lexeme}
+		if !p.eof() && p.peek() == ':' {
+			p.pos++
+			if term.positions, err = p.positions(); err != nil {
+				return nil, err
+			}
+		}
+		vec = append(vec, term)
+	}
+	return normalizeTSVector(vec), nil
+}
+
+// normalizeTSVector sorts the terms of the vector and merges duplicates. The
+// positions of each term are sorted and deduplicated as well.
+func normalizeTSVector(vec TSVector) TSVector {
+	if len(vec) == 0 {
+		return nil
+	}
+	sort.SliceStable(vec, func(i, j int) bool {
+		return vec[i].lexeme < vec[j].lexeme
+	})
+	res := vec[:1]
+	for _, term := range vec[1:] {
+		last := &res[len(res)-1]
+		if last.lexeme == term.lexeme {
+			last.positions = append(last.positions, term.positions...)
+			continue
+		}
+		res = append(res, term)
+	}
+	for i := range res {
+		res[i].positions = normalizePositions(res[i].positions)
+	}
+	return res
+}
+
+// normalizePositions sorts and deduplicates the given positions. If the same
+// position appears more than once, the highest weight is kept.
+func normalizePositions(positions []tsPosition) []tsPosition {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].position < positions[j].position
+	})
+	res := positions[:1]
+	for _, p := range positions[1:] {
+		last := &res[len(res)-1]
+		if last.position == p.position {
+			if p.weight > last.weight {
+				last.weight = p.weight
+			}
+			continue
+		}
+		res = append(res, p)
+	}
+	if len(res) > maxPositionsPerLexeme {
+		res = res[:maxPositionsPerLexeme]
+	}
+	return res
+}
+
+// String returns the Postgres text representation of the vector.
+func (v TSVector) String() string {
+	var sb strings.Builder
+	for i, term := range v {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		writeLexeme(&sb, term.lexeme)
+		for j, p := range term.positions {
+			if j == 0 {
+				sb.WriteByte(':')
+			} else {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(strconv.Itoa(int(p.position)))
+			if p.weight != weightD {
+				sb.WriteString(p.weight.String())
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Compare returns -1, 0 or 1 depending on whether v sorts before, equal to or
+// after other. Vectors are ordered by their number of lexemes first, and then
+// lexeme by lexeme.
+func (v TSVector) Compare(other TSVector) int {
+	if len(v) != len(other) {
+		return cmpInt(len(v), len(other))
+	}
+	for i := range v {
+		if c := strings.Compare(v[i].lexeme, other[i].lexeme); c != 0 {
+			return c
+		}
+		a, b := v[i].positions, other[i].positions
+		if len(a) != len(b) {
+			return cmpInt(len(a), len(b))
+		}
+		for j := range a {
+			if a[j].position != b[j].position {
+				return cmpInt(int(a[j].position), int(b[j].position))
+			}
+			if a[j].weight != b[j].weight {
+				return cmpInt(int(a[j].weight), int(b[j].weight))
+			}
+		}
+	}
+	return 0
+}
+
+// Size returns the approximate in-memory size of the vector, in bytes.
+func (v TSVector) Size() uintptr {
+	var size uintptr
+	for _, term := range v {
+		size += uintptr(len(term.lexeme)) + uintptr(len(term.positions))*3 + 40
+	}
+	return size
+}
+
+// Strip returns a copy of the vector with all position information removed.
+func (v TSVector) Strip() TSVector {
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i] = tsTerm{lexeme: v[i].lexeme}
+	}
+	return res
+}
+
+// SetWeight returns a copy of the vector with the weight of every position set
+// to the given weight letter.
+func (v TSVector) SetWeight(weight string) (TSVector, error) {
+	if len(weight) != 1 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", weight)
+	}
+	w, ok := parseWeight(weight[0])
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", weight)
+	}
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i].lexeme = v[i].lexeme
+		if len(v[i].positions) > 0 {
+			res[i].positions = make([]tsPosition, len(v[i].positions))
+			for j, p := range v[i].positions {
+				res[i].positions[j] = tsPosition{position: p.position, weight: w}
+			}
+		}
+	}
+	return res, nil
+}
+
+// Lexemes returns the lexemes of the vector, in order.
+func (v TSVector) Lexemes() []string {
+	res := make([]string, len(v))
+	for i := range v {
+		res[i] = v[i].lexeme
+	}
+	return res
+}
+
+// Concat returns the concatenation of v and other. The positions of other are
+// shifted by the largest position of v, so that the lexemes of other follow
+// the lexemes of v, as in Postgres.
+func (v TSVector) Concat(other TSVector) TSVector {
+	var maxPos uint16
+	for _, term := range v {
+		for _, p := range term.positions {
+			if p.position > maxPos {
+				maxPos = p.position
+			}
+		}
+	}
+	res := make(TSVector, 0, len(v)+len(other))
+	for _, term := range v {
+		res = append(res, tsTerm{
+			lexeme:    term.lexeme,
+			positions: append([]tsPosition(nil), term.positions...),
+		})
+	}
+	for _, term := range other {
+		positions := make([]tsPosition, len(term.positions))
+		for i, p := range term.positions {
+			pos := int(p.position) + int(maxPos)
+			if pos > maxPosition {
+				pos = maxPosition
+			}
+			positions[i] = tsPosition{position: uint16(pos), weight: p.weight}
+		}
+		res = append(res, tsTerm{lexeme: term.lexeme, positions: positions})
+	}
+	return normalizeTSVector(res)
+}
+
+// find returns the index of the term with the given lexeme, if any.
+func (v TSVector) find(lexeme string) (int, bool) {
+	i := sort.Search(len(v), func(i int) bool {
+		return v[i].lexeme >= lexeme
+	})
+	return i, i < len(v) && v[i].lexeme == lexeme
+}
+
+// findPrefix returns the range [start, end) of terms whose lexemes begin with
+// the given prefix.
+func (v TSVector) findPrefix(prefix string) (start, end int) {
+	start, _ = v.find(prefix)
+	end = start
+	for end < len(v) && strings.HasPrefix(v[end].lexeme, prefix) {
+		end++
+	}
+	return start, end
+}
+
+// writeLexeme writes the given lexeme to sb, surrounded by single quotes.
+// Quotes and backslashes within the lexeme are doubled.
+func writeLexeme(sb *strings.Builder, lexeme string) {
+	sb.WriteByte('\'')
+	for i := 0; i < len(lexeme); i++ {
+		c := lexeme[i]
+		if c == '\'' || c == '\\' {
+			sb.WriteByte(c)
+		}
+		sb.WriteByte(c)
+	}
+	sb.WriteByte('\'')
+}
+
+func cmpInt(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// tsParser is a simple scanner shared by the tsvector and tsquery parsers.
+type tsParser struct {
+	input string
+	pos   int
+	// isQuery is true when parsing a tsquery, and is only used to generate
+	// error messages.
+	isQuery bool
+}
+
+func (p *tsParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *tsParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *tsParser) skipSpace() {
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+func (p *tsParser) syntaxError() error {
+	typ := "tsvector"
+	if p.isQuery {
+		typ = "tsquery"
+	}
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", typ, p.input)
+}
+
+// isOperatorChar returns whether c ends an unquoted lexeme in a tsquery.
+func isOperatorChar(c byte) bool {
+	switch c {
+	case '!', '&', '|', '(', ')', '<':
+		return true
+	}
+	return false
+}
+
+// lexeme parses a possibly quoted lexeme. An unquoted lexeme ends at a space
+// or a colon, and within a tsquery at an operator as well. A backslash escapes
+// the following character, and within quotes a doubled quote stands for a
+// single quote.
+func (p *tsParser) lexeme(isQuery bool) (string, error) {
+	var sb strings.Builder
+	if p.peek() == '\'' {
+		p.pos++
+		for {
+			if p.eof() {
+				return "", p.syntaxError()
+			}
+			c := p.peek()
+			p.pos++
+			switch c {
+			case '\\':
+				if p.eof() {
+					return "", p.syntaxError()
+				}
+				sb.WriteByte(p.peek())
+				p.pos++
+				continue
+			case '\'':
+				if !p.eof() && p.peek() == '\'' {
+					sb.WriteByte('\'')
+					p.pos++
+					continue
+				}
+			default:
+				sb.WriteByte(c)
+				continue
+			}
+			break
+		}
+	} else {
+		for !p.eof() {
+			r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+			if unicode.IsSpace(r) || r == ':' || (isQuery && isOperatorChar(p.peek())) {
+				break
+			}
+			if r == '\\' {
+				p.pos++
+				if p.eof() {
+					return "", p.syntaxError()
+				}
+				r, size = utf8.DecodeRuneInString(p.input[p.pos:])
+			}
+			sb.WriteRune(r)
+			p.pos += size
+		}
+	}
+	if sb.Len() == 0 && !isQuery {
+		return "", p.syntaxError()
+	}
+	if sb.Len() > maxLexemeLen {
+		return "", pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"word is too long (%d bytes, max %d bytes)", sb.Len(), maxLexemeLen)
+	}
+	return sb.String(), nil
+}
+
+// positions parses a comma-separated list of positions, each optionally
+// followed by a weight, e.g. 1A,2,3C.
+func (p *tsParser) positions() ([]tsPosition, error) {
+	var res []tsPosition
+	for {
+		start := p.pos
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, p.syntaxError()
+		}
+		n, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil || n > maxPosition {
+			n = maxPosition
+		}
+		if n == 0 {
+			return nil, pgerror.Newf(pgcode.Syntax, "wrong position info in tsvector: %q", p.input)
+		}
+		pos := tsPosition{position: uint16(n)}
+		if !p.eof() {
+			if w, ok := parseWeight(p.peek()); ok {
+				pos.weight = w
+				p.pos++
+			}
+		}
+		res = append(res, pos)
+		if p.eof() || p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if !p.eof() {
+		r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !unicode.IsSpace(r) {
+			return nil, p.syntaxError()
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSVector(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`a fat cat sat on a mat and ate a fat rat`, `'a' 'and' 'ate' 'cat' 'fat' 'mat' 'on' 'rat' 'sat'`},
+		{`the lexeme '    ' contains spaces`, `'    ' 'contains' 'lexeme' 'spaces' 'the'`},
+		{`the lexeme 'Joe''s' contains a quote`, `'Joe''s' 'a' 'contains' 'lexeme' 'quote' 'the'`},
+		{`back\\slash 'a\'b'`, `'a''b' 'back\\slash'`},
+		{
+			`a:1 fat:2 cat:3 sat:4 on:5 a:6 mat:7 and:8 ate:9 a:10 fat:11 rat:12`,
+			`'a':1,6,10 'and':8 'ate':9 'cat':3 'fat':2,11 'mat':7 'on':5 'rat':12 'sat':4`,
+		},
+		{`a:1A fat:2B,4C cat:5D`, `'a':1A 'cat':5 'fat':2B,4C`},
+		// Duplicate lexemes are merged, and duplicate positions keep the
+		// highest weight.
+		{`a:3 b a:1,3A`, `'a':1,3A 'b'`},
+		// Positions that are too large are clamped.
+		{`a:20000`, `'a':16383`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			vec, err := ParseTSVector(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vec.String())
+
+			// The output must parse to the same vector.
+			vec2, err := ParseTSVector(vec.String())
+			require.NoError(t, err)
+			assert.Equal(t, 0, vec.Compare(vec2))
+		})
+	}
+}
+
+func TestParseTSVectorError(t *testing.T) {
+	for _, input := range []string{
+		`'a`,
+		`a:`,
+		`a:0`,
+		`a:1,`,
+		`a:b`,
+		`a:1x`,
+		`''`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTSVector(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTSVectorFunctions(t *testing.T) {
+	vec, err := ParseTSVector(`a:1A b:2 c`)
+	require.NoError(t, err)
+	other, err := ParseTSVector(`a:1 d:2B`)
+	require.NoError(t, err)
+
+	assert.Equal(t, `'a' 'b' 'c'`, vec.Strip().String())
+	assert.Equal(t, []string{"a", "b", "c"}, vec.Lexemes())
+	assert.Equal(t, `'a':1A,3 'b':2 'c' 'd':4B`, vec.Concat(other).String())
+
+	weighted, err := vec.SetWeight("b")
+	require.NoError(t, err)
+	assert.Equal(t, `'a':1B 'b':2B 'c'`, weighted.String())
+	_, err = vec.SetWeight("x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,316 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vecindex
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/cockroachdb/errors"
+)
+
+// DefaultLists is the number of lists of a vector index if it is not
+// specified with the lists storage parameter.
+const DefaultLists = 100
+
+// MaxLists is the maximum number of lists of a vector index.
+const MaxLists = 32768
+
+// MaxDims is the maximum number of dimensions of the vectors of an index.
+const MaxDims = 2000
+
+// MaxCentroidElements is the maximum number of elements of the centroids of an
+// index, which is its number of lists times its number of dimensions. The
+// centroids are stored in the descriptor of the table, which is read whenever
+// the table is used, so they must remain small.
+const MaxCentroidElements = 1 << 18
+
+// maxTrainIterations is the maximum number of iterations of k-means performed
+// when training the centroids of an index.
+const maxTrainIterations = 25
+
+// SamplesPerList is the number of sample vectors per list that are used to
+// train the centroids of an index.
+const SamplesPerList = 50
+
+// OpClassMetrics maps the names of the operator classes of vector indexes to
+// their distance metric. A column without an operator class uses the L2
+// metric.
+var OpClassMetrics = map[string]DistanceMetric{
+	"vector_l2_ops":     DistanceMetric_L2,
+	"vector_ip_ops":     DistanceMetric_INNER_PRODUCT,
+	"vector_cosine_ops": DistanceMetric_COSINE,
+}
+
+// OpClass returns the name of the operator class that uses the metric.
+func (m DistanceMetric) OpClass() string {
+	switch m {
+	case DistanceMetric_INNER_PRODUCT:
+		return "vector_ip_ops"
+	case DistanceMetric_COSINE:
+		return "vector_cosine_ops"
+	default:
+		return "vector_l2_ops"
+	}
+}
+
+// Distance returns the distance between the given vectors according to the
+// metric.
+func (m DistanceMetric) Distance(a, b vector.T) (float64, error) {
+	switch m {
+	case DistanceMetric_INNER_PRODUCT:
+		return vector.NegInnerProduct(a, b)
+	case DistanceMetric_COSINE:
+		return vector.CosDistance(a, b)
+	default:
+		return vector.L2Distance(a, b)
+	}
+}
+
+// IsEmpty returns whether the config contains a vector index configuration.
+func (cfg Config) IsEmpty() bool {
+	return cfg.Dims == 0
+}
+
+// Validate returns an error if the index has too many dimensions, or too many
+// lists for its dimensions.
+func (cfg Config) Validate() error {
+	if cfg.Dims > MaxDims {
+		return pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"column cannot have more than %d dimensions for ivfflat index", MaxDims)
+	}
+	if n := int64(cfg.Lists) * int64(cfg.Dims); n > MaxCentroidElements {
+		return errors.WithHintf(
+			pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"ivfflat index with %d lists of %d dimensions exceeds the maximum of %d centroid elements",
+				cfg.Lists, cfg.Dims, MaxCentroidElements),
+			"Use at most %d lists.", MaxCentroidElements/cfg.Dims,
+		)
+	}
+	return nil
+}
+
+// Centroid returns the centroid of the given list.
+func (cfg Config) Centroid(list int) vector.T {
+	return cfg.Centroids[list*int(cfg.Dims) : (list+1)*int(cfg.Dims)]
+}
+
+// NearestList returns the list whose centroid is nearest to the given vector,
+// which is the list the vector is stored in. Vectors whose distance to the
+// centroids is undefined, like zero vectors with the cosine metric, are
+// stored in the first list.
+func (cfg Config) NearestList(v vector.T) (int, error) {
+	lists, err := cfg.NearestLists(v, 1)
+	if err != nil {
+		return 0, err
+	}
+	return lists[0], nil
+}
+
+// NearestLists returns the n lists whose centroids are nearest to the given
+// vector, in increasing order of list number.
+func (cfg Config) NearestLists(v vector.T, n int) ([]int, error) {
+	if len(v) != int(cfg.Dims) {
+		return nil, errors.AssertionFailedf(
+			"expected %d dimensions for vector index, got %d", cfg.Dims, len(v))
+	}
+	if n > int(cfg.Lists) {
+		n = int(cfg.Lists)
+	}
+	lists := make([]int, cfg.Lists)
+	dists := make([]float64, cfg.Lists)
+	for i := range lists {
+		d, err := cfg.Metric.Distance(v, cfg.Centroid(i))
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(d) {
+			d = math.Inf(1)
+		}
+		lists[i], dists[i] = i, d
+	}
+	sort.SliceStable(lists, func(i, j int) bool {
+		return dists[lists[i]] < dists[lists[j]]
+	})
+	lists = lists[:n]
+	sort.Ints(lists)
+	return lists, nil
+}
+
+// EncodeKey appends the inverted index key of the given list to inKey. All
+// the vectors in a list share the same key.
+func EncodeKey(inKey []byte, list int) []byte {
+	return encoding.EncodeUvarintAscending(inKey, uint64(list))
+}
+
+// EncodeInvertedIndexKey appends the inverted index key of the given vector,
+// which is the key of its nearest list, to inKey.
+func (cfg Config) EncodeInvertedIndexKey(inKey []byte, v vector.T) ([]byte, error) {
+	list, err := cfg.NearestList(v)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeKey(inKey, list), nil
+}
+
+// Train computes the centroids of the lists with k-means clustering of the
+// given sample of the indexed vectors, which must have the dimensions of the
+// index. If there are fewer samples than lists, the remaining centroids are
+// random. The result only depends on the samples and the random number
+// generator.
+func (cfg *Config) Train(rng *rand.Rand, samples []vector.T) {
+	dims, lists := int(cfg.Dims), int(cfg.Lists)
+	if cfg.Metric == DistanceMetric_COSINE {
+		// Spherical k-means: cluster the normalized vectors, ignoring those that
+		// have no direction.
+		normalized := make([]vector.T, 0, len(samples))
+		for _, s := range samples {
+			if n := normalize(s); n != nil {
+				normalized = append(normalized, n)
+			}
+		}
+		samples = normalized
+	}
+	centroids := make([]vector.T, lists)
+	initCentroids(rng, centroids, samples, dims)
+	assignments := make([]int, len(samples))
+	for i := range assignments {
+		assignments[i] = -1
+	}
+	counts := make([]int, lists)
+	for iter := 0; iter < maxTrainIterations; iter++ {
+		changed := false
+		for i, s := range samples {
+			if c := nearestCentroid(centroids, s); c != assignments[i] {
+				assignments[i] = c
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		sums := make([][]float64, lists)
+		for i := range counts {
+			counts[i] = 0
+		}
+		for i, s := range samples {
+			c := assignments[i]
+			if sums[c] == nil {
+				sums[c] = make([]float64, dims)
+			}
+			for j, f := range s {
+				sums[c][j] += float64(f)
+			}
+			counts[c]++
+		}
+		for c, sum := range sums {
+			// Lists without any samples keep their previous centroid.
+			if counts[c] == 0 {
+				continue
+			}
+			for j := range sum {
+				centroids[c][j] = float32(sum[j] / float64(counts[c]))
+			}
+			if cfg.Metric == DistanceMetric_COSINE {
+				if n := normalize(centroids[c]); n != nil {
+					centroids[c] = n
+				}
+			}
+		}
+	}
+	cfg.Centroids = make([]float32, 0, lists*dims)
+	for _, c := range centroids {
+		cfg.Centroids = append(cfg.Centroids, c...)
+	}
+}
+
+// initCentroids chooses the initial centroids for k-means using the k-means++
+// algorithm, which picks samples that are far from the centroids chosen so
+// far. If there are not enough distinct samples, the remaining centroids are
+// random vectors with elements in [-1, 1).
+func initCentroids(rng *rand.Rand, centroids []vector.T, samples []vector.T, dims int) {
+	n := 0
+	if len(samples) > 0 {
+		minDists := make([]float64, len(samples))
+		for i := range minDists {
+			minDists[i] = math.Inf(1)
+		}
+		next := samples[rng.Intn(len(samples))]
+		for n < len(centroids) {
+			centroids[n] = append(vector.T(nil), next...)
+			n++
+			var total float64
+			for i, s := range samples {
+				if d := squaredDistance(s, centroids[n-1]); d < minDists[i] {
+					minDists[i] = d
+				}
+				total += minDists[i]
+			}
+			if total == 0 {
+				// All the samples are already centroids.
+				break
+			}
+			target := rng.Float64() * total
+			for i, d := range minDists {
+				next = samples[i]
+				if target -= d; target < 0 && d > 0 {
+					break
+				}
+			}
+		}
+	}
+	for ; n < len(centroids); n++ {
+		centroids[n] = make(vector.T, dims)
+		for j := range centroids[n] {
+			centroids[n][j] = float32(rng.Float64()*2 - 1)
+		}
+	}
+}
+
+// nearestCentroid returns the index of the centroid with the smallest
+// Euclidean distance to the given vector.
+func nearestCentroid(centroids []vector.T, v vector.T) int {
+	nearest, minDist := 0, math.Inf(1)
+	for i, c := range centroids {
+		if d := squaredDistance(v, c); d < minDist {
+			nearest, minDist = i, d
+		}
+	}
+	return nearest
+}
+
+func squaredDistance(a, b vector.T) float64 {
+	var d float64
+	for i := range a {
+		diff := float64(a[i]) - float64(b[i])
+		d += diff * diff
+	}
+	return d
+}
+
+// normalize returns a copy of the vector scaled to have a norm of one, or nil
+// if it has a norm of zero.
+func normalize(v vector.T) vector.T {
+	norm := vector.Norm(v)
+	if norm == 0 {
+		return nil
+	}
+	res := make(vector.T, len(v))
+	for i, f := range v {
+		res[i] = float32(float64(f) / norm)
+	}
+	return res
+}
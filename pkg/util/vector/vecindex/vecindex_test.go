@@ -0,0 +1,93 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vecindex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrain(t *testing.T) {
+	// Generate samples in four well separated clusters, around (0, 0),
+	// (10, 10), (20, 20) and (30, 30).
+	rng := rand.New(rand.NewSource(1))
+	var samples []vector.T
+	for i := 0; i < 200; i++ {
+		c := float32(i%4) * 10
+		samples = append(samples, vector.T{c + rng.Float32(), c + rng.Float32()})
+	}
+	cfg := Config{Dims: 2, Lists: 4}
+	cfg.Train(rand.New(rand.NewSource(0)), samples)
+	require.Len(t, cfg.Centroids, 8)
+
+	// Each cluster is assigned its own list.
+	seen := make(map[int]bool)
+	for c := float32(0); c < 40; c += 10 {
+		list, err := cfg.NearestList(vector.T{c + 0.5, c + 0.5})
+		require.NoError(t, err)
+		require.False(t, seen[list])
+		seen[list] = true
+		for i := 0; i < 2; i++ {
+			require.InDelta(t, c+0.5, cfg.Centroid(list)[i], 0.2)
+		}
+	}
+
+	// The lists of the two nearest clusters are returned in order.
+	lists, err := cfg.NearestLists(vector.T{14, 14}, 2)
+	require.NoError(t, err)
+	l10, err := cfg.NearestList(vector.T{10, 10})
+	require.NoError(t, err)
+	l20, err := cfg.NearestList(vector.T{20, 20})
+	require.NoError(t, err)
+	if l10 > l20 {
+		l10, l20 = l20, l10
+	}
+	require.Equal(t, []int{l10, l20}, lists)
+
+	// Asking for more lists than there are returns all of them.
+	lists, err = cfg.NearestLists(vector.T{0, 0}, 10)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3}, lists)
+
+	_, err = cfg.NearestList(vector.T{1, 2, 3})
+	require.Error(t, err)
+}
+
+func TestTrainFewSamples(t *testing.T) {
+	cfg := Config{Dims: 3, Lists: 10, Metric: DistanceMetric_COSINE}
+	cfg.Train(rand.New(rand.NewSource(0)), []vector.T{{1, 0, 0}, {0, 0, 0}})
+	require.Len(t, cfg.Centroids, 30)
+	list, err := cfg.NearestList(vector.T{2, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, vector.T{1, 0, 0}, cfg.Centroid(list))
+
+	// Vectors without a direction are stored in the first list.
+	list, err = cfg.NearestList(vector.T{0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, 0, list)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Config{Dims: MaxDims, Lists: DefaultLists}.Validate())
+	require.NoError(t, Config{Dims: 8, Lists: MaxLists}.Validate())
+	require.Error(t, Config{Dims: MaxDims + 1, Lists: 1}.Validate())
+	require.Error(t, Config{Dims: MaxDims, Lists: MaxCentroidElements/MaxDims + 1}.Validate())
+}
+
+func TestEncodeKey(t *testing.T) {
+	// Keys sort in the order of the lists.
+	for i := 0; i < 1000; i++ {
+		require.Less(t, string(EncodeKey(nil, i)), string(EncodeKey(nil, i+1)))
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vector
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// MaxDim is the maximum number of dimensions a vector can have.
+const MaxDim = 16000
+
+// T is a vector of 32-bit floats, as stored by the pgvector-compatible vector
+// type.
+type T []float32
+
+// ParseVector parses the text representation of a vector, which is a list of
+// numbers enclosed in square brackets, like [1,2.5,3].
+func ParseVector(input string) (T, error) {
+	s := strings.TrimSpace(input)
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return nil, malformedVectorError(input)
+	}
+	s = strings.TrimSpace(s[1 : len(s)-1])
+	if s == "" {
+		return nil, pgerror.New(pgcode.DataException, "vector must have at least 1 dimension")
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) > MaxDim {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"vector cannot have more than %d dimensions", MaxDim)
+	}
+	v := make(T, len(parts))
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+					"%q is out of range for type vector", strings.TrimSpace(part))
+			}
+			return nil, malformedVectorError(input)
+		}
+		if err := checkElement(float32(f)); err != nil {
+			return nil, err
+		}
+		v[i] = float32(f)
+	}
+	return v, nil
+}
+
+func malformedVectorError(input string) error {
+	return pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed vector literal: %q", input)
+}
+
+// checkElement returns an error if the given element cannot be stored in a
+// vector.
+func checkElement(f float32) error {
+	if math.IsNaN(float64(f)) {
+		return pgerror.New(pgcode.DataException, "NaN not allowed in vector")
+	}
+	if math.IsInf(float64(f), 0) {
+		return pgerror.New(pgcode.DataException, "infinite value not allowed in vector")
+	}
+	return nil
+}
+
+// FromFloats returns a vector with the given elements, or an error if there
+// are too few or too many of them or one of them cannot be stored in a vector.
+func FromFloats(fs []float64) (T, error) {
+	if len(fs) == 0 {
+		return nil, pgerror.New(pgcode.DataException, "vector must have at least 1 dimension")
+	}
+	if len(fs) > MaxDim {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"vector cannot have more than %d dimensions", MaxDim)
+	}
+	v := make(T, len(fs))
+	for i, f := range fs {
+		if f > math.MaxFloat32 || f < -math.MaxFloat32 {
+			return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+				"%g is out of range for type vector", f)
+		}
+		v[i] = float32(f)
+		if err := checkElement(v[i]); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (v T) String() string {
+	var sb strings.Builder
+	v.Format(&sb)
+	return sb.String()
+}
+
+// Format writes the text representation of the vector to the given builder.
+func (v T) Format(sb *strings.Builder) {
+	var buf [32]byte
+	sb.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.Write(strconv.AppendFloat(buf[:0], float64(f), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+}
+
+// Size returns the size of the vector in bytes.
+func (v T) Size() uintptr {
+	return uintptr(len(v)) * 4
+}
+
+// Compare returns -1, 0 or 1 depending on whether v sorts before, the same as
+// or after other. Like in pgvector, vectors are compared element by element,
+// and a vector sorts before any longer vector that it is a prefix of.
+func (v T) Compare(other T) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if v[i] < other[i] {
+			return -1
+		} else if v[i] > other[i] {
+			return 1
+		}
+	}
+	if len(v) < len(other) {
+		return -1
+	} else if len(v) > len(other) {
+		return 1
+	}
+	return 0
+}
+
+// Encode appends the binary encoding of the vector to the given buffer: the
+// number of dimensions as a 2-byte integer, two unused bytes, and each
+// element as a 4-byte float. This is the format pgvector uses to send the
+// type over the wire, and it is also used for the value encoding.
+func Encode(appendTo []byte, v T) []byte {
+	appendTo = append(appendTo, byte(len(v)>>8), byte(len(v)), 0, 0)
+	for _, f := range v {
+		bits := math.Float32bits(f)
+		appendTo = append(appendTo, byte(bits>>24), byte(bits>>16), byte(bits>>8), byte(bits))
+	}
+	return appendTo
+}
+
+// Decode decodes a vector from its binary encoding.
+func Decode(b []byte) (T, error) {
+	if len(b) < 4 {
+		return nil, errors.New("insufficient bytes to decode vector")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	b = b[4:]
+	if len(b) != n*4 {
+		return nil, errors.Newf("expected %d bytes to decode vector, got %d", n*4, len(b))
+	}
+	v := make(T, n)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.BigEndian.Uint32(b[i*4:]))
+	}
+	return v, nil
+}
+
+// checkDims returns an error if the given vectors have different dimensions.
+func checkDims(a, b T) error {
+	if len(a) != len(b) {
+		return pgerror.Newf(pgcode.DataException,
+			"different vector dimensions %d and %d", len(a), len(b))
+	}
+	return nil
+}
+
+// L2Distance returns the Euclidean distance between the given vectors.
+func L2Distance(a, b T) (float64, error) {
+	if err := checkDims(a, b); err != nil {
+		return 0, err
+	}
+	return math.Sqrt(float64(l2SquaredDistance(a, b))), nil
+}
+
+// l2SquaredDistance returns the squared Euclidean distance between vectors of
+// the same dimensions.
+func l2SquaredDistance(a, b T) float32 {
+	var d float32
+	for i := range a {
+		diff := a[i] - b[i]
+		d += diff * diff
+	}
+	return d
+}
+
+// InnerProduct returns the inner product of the given vectors.
+func InnerProduct(a, b T) (float64, error) {
+	if err := checkDims(a, b); err != nil {
+		return 0, err
+	}
+	return float64(innerProduct(a, b)), nil
+}
+
+// NegInnerProduct returns the negative inner product of the given vectors,
+// which is smaller for vectors that are more similar.
+func NegInnerProduct(a, b T) (float64, error) {
+	p, err := InnerProduct(a, b)
+	return -p, err
+}
+
+func innerProduct(a, b T) float32 {
+	var p float32
+	for i := range a {
+		p += a[i] * b[i]
+	}
+	return p
+}
+
+// CosDistance returns the cosine distance between the given vectors, which is
+// 1 minus their cosine similarity. It is NaN if either vector has zero norm.
+func CosDistance(a, b T) (float64, error) {
+	if err := checkDims(a, b); err != nil {
+		return 0, err
+	}
+	return cosDistance(a, b), nil
+}
+
+func cosDistance(a, b T) float64 {
+	var p, normA, normB float64
+	for i := range a {
+		p += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	similarity := p / math.Sqrt(normA*normB)
+	if math.IsNaN(similarity) {
+		return math.NaN()
+	}
+	// Keep the distance in range despite rounding errors.
+	if similarity > 1 {
+		similarity = 1
+	} else if similarity < -1 {
+		similarity = -1
+	}
+	return 1 - similarity
+}
+
+// Norm returns the Euclidean norm of the vector.
+func Norm(v T) float64 {
+	var n float64
+	for _, f := range v {
+		n += float64(f) * float64(f)
+	}
+	return math.Sqrt(n)
+}
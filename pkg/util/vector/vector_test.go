@@ -0,0 +1,97 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVector(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: `[1,2,3]`, expected: `[1,2,3]`},
+		{input: ` [ 1.5 , -2e3 ] `, expected: `[1.5,-2000]`},
+		{input: `[0.1]`, expected: `[0.1]`},
+		{input: `[]`, err: `vector must have at least 1 dimension`},
+		{input: `1,2`, err: `malformed vector literal: "1,2"`},
+		{input: `[1,a]`, err: `malformed vector literal: "[1,a]"`},
+		{input: `[1,,2]`, err: `malformed vector literal: "[1,,2]"`},
+		{input: `[NaN]`, err: `NaN not allowed in vector`},
+		{input: `[Infinity]`, err: `infinite value not allowed in vector`},
+		{input: `[1e39]`, err: `"1e39" is out of range for type vector`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseVector(tc.input)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v.String())
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, v := range []T{{1}, {1, -2.5, 3e20}, {0, 0, 0, 0}} {
+		decoded, err := Decode(Encode(nil, v))
+		require.NoError(t, err)
+		require.Equal(t, v, decoded)
+	}
+	_, err := Decode([]byte{0, 2, 0, 0, 1})
+	require.Error(t, err)
+}
+
+func TestDistances(t *testing.T) {
+	a, b := T{1, 2, 3}, T{3, 2, 1}
+
+	d, err := L2Distance(a, b)
+	require.NoError(t, err)
+	require.InDelta(t, math.Sqrt(8), d, 1e-9)
+
+	d, err = InnerProduct(a, b)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, d)
+
+	d, err = NegInnerProduct(a, b)
+	require.NoError(t, err)
+	require.Equal(t, -10.0, d)
+
+	d, err = CosDistance(a, b)
+	require.NoError(t, err)
+	require.InDelta(t, 1-10.0/14, d, 1e-9)
+
+	d, err = CosDistance(a, a)
+	require.NoError(t, err)
+	require.Equal(t, 0.0, d)
+
+	d, err = CosDistance(a, T{0, 0, 0})
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(d))
+
+	require.InDelta(t, math.Sqrt(14), Norm(a), 1e-9)
+
+	_, err = L2Distance(a, T{1, 2})
+	require.EqualError(t, err, "different vector dimensions 3 and 2")
+}
+
+func TestCompare(t *testing.T) {
+	require.Equal(t, 0, T{1, 2}.Compare(T{1, 2}))
+	require.Equal(t, -1, T{1, 2}.Compare(T{1, 3}))
+	require.Equal(t, 1, T{2}.Compare(T{1, 3}))
+	require.Equal(t, -1, T{1}.Compare(T{1, 0}))
+}